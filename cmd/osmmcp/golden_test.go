@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+	"github.com/NERVsystems/osmmcp/pkg/tools"
+	"github.com/NERVsystems/osmmcp/pkg/transit"
+	"github.com/NERVsystems/osmmcp/pkg/transit/gtfs"
+)
+
+// updateGolden rewrites the golden output shapes instead of checking them:
+//
+//	go test ./cmd/osmmcp/ -run TestToolOutputGolden -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden tool output shapes in testdata/golden")
+
+const (
+	goldenDir      = "testdata/golden"
+	goldenArgsFile = goldenDir + "/arguments.json"
+
+	// gtfsFeedDir is a GTFS feed of buses through Monaco, loaded for the
+	// transit tools
+	gtfsFeedDir = "testdata/integration/gtfs"
+)
+
+// goldenClock is the simulated time tools run at, so outputs that depend on
+// opening hours or daylight keep their shape
+var goldenClock = time.Date(2026, time.June, 21, 12, 0, 0, 0, time.UTC)
+
+// goldenOutput is the recorded output shape of a tool version
+type goldenOutput struct {
+	Version int               `json:"version"`
+	IsError bool              `json:"is_error"`
+	Shape   map[string]string `json:"shape"`
+}
+
+// outputShape flattens the content of a tool result into the paths of its
+// fields and their JSON types. Elements of an array share the path name[],
+// tags maps and get_version's build settings count as one field since their
+// keys are data, and null values are left out like omitted ones.
+func outputShape(result *mcp.CallToolResult) map[string]string {
+	shape := make(map[string]string)
+	for i, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		var value any
+		if !ok || json.Unmarshal([]byte(text.Text), &value) != nil {
+			shape[fmt.Sprintf("content[%d]", i)] = contentType(content)
+			continue
+		}
+		flattenShape(value, "", shape)
+	}
+	return shape
+}
+
+// contentType names the MCP type of a content item
+func contentType(content mcp.Content) string {
+	switch content.(type) {
+	case mcp.TextContent:
+		return "text"
+	case mcp.ImageContent:
+		return "image"
+	case mcp.EmbeddedResource:
+		return "resource"
+	}
+	return fmt.Sprintf("%T", content)
+}
+
+func flattenShape(value any, path string, shape map[string]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if path != "" {
+			shape[path] = "object"
+		}
+		if strings.HasSuffix(path, "tags") || path == "settings" {
+			return
+		}
+		for key, field := range v {
+			flattenShape(field, join(key), shape)
+		}
+	case []any:
+		shape[path] = "array"
+		for _, element := range v {
+			flattenShape(element, path+"[]", shape)
+		}
+	case string:
+		shape[path] = "string"
+	case float64:
+		shape[path] = "number"
+	case bool:
+		shape[path] = "boolean"
+	}
+}
+
+// diffShape lists the fields of the golden shape that are missing or have
+// another type, which break clients, and the fields that were added
+func diffShape(golden, current map[string]string) (broken, added []string) {
+	for path, typ := range golden {
+		switch got, ok := current[path]; {
+		case !ok:
+			broken = append(broken, path+" removed")
+		case got != typ:
+			broken = append(broken, fmt.Sprintf("%s changed from %s to %s", path, typ, got))
+		}
+	}
+	for path := range current {
+		if _, ok := golden[path]; !ok {
+			added = append(added, path)
+		}
+	}
+	sort.Strings(broken)
+	sort.Strings(added)
+	return broken, added
+}
+
+// useReplayedBackends points the upstream clients at the cassettes, loads
+// the GTFS feed, and fixes the clock and logs, for the duration of a test
+func useReplayedBackends(t *testing.T) {
+	t.Helper()
+	replay := httptest.NewServer(newReplayServer(t, nil))
+	t.Cleanup(replay.Close)
+
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := osm.SetServiceURLs(osm.ServiceURLs{
+		Nominatim: replay.URL + "/nominatim",
+		Overpass:  replay.URL + "/overpass",
+		OSRM:      replay.URL + "/osrm",
+		Elevation: replay.URL + "/elevation",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := osm.SetOverpassMirrors([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.ConfigureTilePolicy(core.TilePolicyConfig{URL: replay.URL + "/tiles", RPS: 1000, Burst: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	osm.UpdateNominatimRateLimits(1000, 1000)
+	osm.UpdateOverpassRateLimits(1000, 1000)
+	osm.UpdateOSRMRateLimits(1000, 1000)
+	core.SetSimulatedClock(goldenClock, true)
+	tools.SetStaleDataThreshold(0)
+	core.InitTileResourceManager(slog.Default())
+
+	feed, err := gtfs.Load(gtfsFeedDir, "cam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gtfs.Register(feed)
+	transit.RegisterProvider(feed)
+
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		osm.SetServiceURLs(osm.ServiceURLs{
+			Nominatim: osm.DefaultNominatimBaseURL,
+			Overpass:  osm.DefaultOverpassBaseURL,
+			OSRM:      osm.DefaultOSRMBaseURL,
+			Elevation: osm.DefaultElevationBaseURL,
+		})
+		osm.SetOverpassMirrors(nil)
+		core.ConfigureTilePolicy(core.TilePolicyConfig{
+			URL:         core.DefaultTileProvider,
+			RPS:         core.DefaultTileRPS,
+			Burst:       core.DefaultTileBurst,
+			HourlyLimit: core.DefaultTileHourlyLimit,
+		})
+		osm.UpdateNominatimRateLimits(1, 1)
+		osm.UpdateOverpassRateLimits(1, 1)
+		osm.UpdateOSRMRateLimits(1, 1)
+		core.ResetClock()
+		tools.SetStaleDataThreshold(tools.DefaultStaleDataThreshold)
+		gtfs.Reset()
+		transit.ResetProviders()
+	})
+}
+
+// TestToolOutputGolden runs every tool with the arguments in
+// testdata/golden/arguments.json against the replayed backends and checks
+// the shape of its output against testdata/golden/<tool>.json. Agent
+// prompts depend on field names, so a field may only be removed or change
+// type in a new tool version (see Changing Tool Behavior in the README).
+func TestToolOutputGolden(t *testing.T) {
+	useReplayedBackends(t)
+
+	data, err := os.ReadFile(goldenArgsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var arguments map[string]map[string]any
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		t.Fatalf("%s: %v", goldenArgsFile, err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	defs := tools.NewRegistry(logger).GetToolDefinitions()
+
+	// Closures reported would change later route_fetch results
+	t.Cleanup(func() {
+		for _, def := range defs {
+			if def.Name == "report_closure" {
+				req := mcp.CallToolRequest{}
+				req.Params.Arguments = map[string]any{"action": "clear"}
+				def.Handler(context.Background(), req)
+			}
+		}
+	})
+
+	// unwatch_area stops the watch started by watch_area, so the next run
+	// starts a new one
+	var watchURI string
+
+	for _, def := range defs {
+		t.Run(def.Name, func(t *testing.T) {
+			base, _, _ := strings.Cut(def.Name, "@")
+			args, ok := arguments[base]
+			if !ok {
+				t.Fatalf("no arguments for %s in %s", base, goldenArgsFile)
+			}
+			if base == "unwatch_area" && watchURI != "" {
+				args = map[string]any{"uri": watchURI}
+			}
+
+			// Cached results carry no upstream sources in their attribution
+			for _, c := range cache.RegisteredCaches() {
+				c.Clear()
+			}
+
+			req := mcp.CallToolRequest{}
+			req.Params.Name = def.Name
+			req.Params.Arguments = args
+			result, err := def.Handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+			if base == "watch_area" && !result.IsError {
+				var watch struct {
+					URI string `json:"uri"`
+				}
+				json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &watch)
+				watchURI = watch.URI
+			}
+			current := goldenOutput{Version: def.Version, IsError: result.IsError, Shape: outputShape(result)}
+
+			path := filepath.Join(goldenDir, def.Name+".json")
+			if *updateGolden {
+				if result.IsError {
+					t.Logf("recording an error result: %v", result.Content)
+				}
+				data, err := json.MarshalIndent(current, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("no golden output for %s; run with -update to record it: %v", def.Name, err)
+			}
+			var golden goldenOutput
+			if err := json.Unmarshal(data, &golden); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+
+			if golden.Version != current.Version {
+				t.Fatalf("%s is now version %d but its golden output is for version %d; run with -update", def.Name, current.Version, golden.Version)
+			}
+			if golden.IsError != current.IsError {
+				t.Fatalf("is_error changed from %v to %v: %v", golden.IsError, current.IsError, result.Content)
+			}
+			broken, added := diffShape(golden.Shape, current.Shape)
+			if len(broken) > 0 {
+				t.Errorf("output shape changed without a version bump:\n  %s\nShip the change as %s@v%d, or restore the fields",
+					strings.Join(broken, "\n  "), base, def.Version+1)
+			}
+			if len(added) > 0 {
+				t.Errorf("output has new fields; run with -update to record them:\n  %s", strings.Join(added, "\n  "))
+			}
+		})
+	}
+}
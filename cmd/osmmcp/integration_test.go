@@ -0,0 +1,494 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The integration tests run the server binary over its HTTP transport and
+// call every registered tool. Upstream services are selected with
+// OSMMCP_IT_BACKEND:
+//
+//	replay (default)  serve the cassettes in testdata/integration/cassettes
+//	record            proxy to the real services and rewrite the cassettes
+//	docker            start the instances in testdata/integration/docker-compose.yml
+//
+// OSMMCP_IT_NOMINATIM_URL, OSMMCP_IT_OVERPASS_URL and OSMMCP_IT_OSRM_URL
+// override the services used by record and docker. Tiles and elevations
+// are always replayed.
+//
+//	go test -tags integration -run Integration ./cmd/osmmcp/
+
+const (
+	composeFile     = "testdata/integration/docker-compose.yml"
+	toolCallTimeout = 60 * time.Second
+)
+
+// integrationServices are the replayed services, each served under its
+// name on the replay server
+var integrationServices = []string{"nominatim", "overpass", "osrm", "tiles", "elevation"}
+
+// integrationBackends returns the upstream URLs the server under test is
+// pointed at for the backend selected with OSMMCP_IT_BACKEND
+func integrationBackends(t *testing.T) map[string]string {
+	t.Helper()
+	mode := os.Getenv("OSMMCP_IT_BACKEND")
+	if mode == "" {
+		mode = "replay"
+	}
+
+	live := map[string]string{
+		"nominatim": envOr("OSMMCP_IT_NOMINATIM_URL", "https://nominatim.openstreetmap.org"),
+		"overpass":  envOr("OSMMCP_IT_OVERPASS_URL", "https://overpass-api.de/api/interpreter"),
+		"osrm":      envOr("OSMMCP_IT_OSRM_URL", "https://router.project-osrm.org"),
+	}
+
+	var upstreams map[string]string
+	switch mode {
+	case "replay":
+	case "record":
+		upstreams = live
+	case "docker":
+		live = map[string]string{
+			"nominatim": envOr("OSMMCP_IT_NOMINATIM_URL", "http://127.0.0.1:18080"),
+			"overpass":  envOr("OSMMCP_IT_OVERPASS_URL", "http://127.0.0.1:18081/api/interpreter"),
+			"osrm":      envOr("OSMMCP_IT_OSRM_URL", "http://127.0.0.1:18082"),
+		}
+		startCompose(t, live)
+	default:
+		t.Fatalf("unknown OSMMCP_IT_BACKEND %q: use replay, record or docker", mode)
+	}
+
+	replay := httptest.NewServer(newReplayServer(t, upstreams))
+	t.Cleanup(replay.Close)
+
+	backends := make(map[string]string, len(integrationServices))
+	for _, service := range integrationServices {
+		backends[service] = replay.URL + "/" + service
+	}
+	if mode == "docker" {
+		for service, url := range live {
+			backends[service] = url
+		}
+	}
+	return backends
+}
+
+func envOr(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
+
+// startCompose starts the dockerized services unless their URLs were
+// given, and waits until each answers a query. The first start imports the
+// extract, so OSMMCP_IT_DOCKER_TIMEOUT (default 30m) bounds the wait.
+func startCompose(t *testing.T, live map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker is not installed")
+	}
+
+	timeout := 30 * time.Minute
+	if value := os.Getenv("OSMMCP_IT_DOCKER_TIMEOUT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			t.Fatalf("invalid OSMMCP_IT_DOCKER_TIMEOUT: %v", err)
+		}
+		timeout = d
+	}
+
+	up := exec.Command("docker", "compose", "-f", composeFile, "up", "-d")
+	if out, err := up.CombinedOutput(); err != nil {
+		t.Fatalf("docker compose up failed: %v\n%s", err, out)
+	}
+	if os.Getenv("OSMMCP_IT_KEEP_DOCKER") == "" {
+		t.Cleanup(func() {
+			exec.Command("docker", "compose", "-f", composeFile, "stop").Run()
+		})
+	}
+
+	probes := map[string]string{
+		"nominatim": live["nominatim"] + "/status",
+		"overpass":  live["overpass"] + "?data=" + "%5Bout%3Ajson%5D%3Bnode%28around%3A50%2C43.7384%2C7.4246%29%3Bout%201%3B",
+		"osrm":      live["osrm"] + "/nearest/v1/driving/7.4246,43.7384",
+	}
+	for service, probe := range probes {
+		if err := waitForEndpoint(probe, timeout); err != nil {
+			t.Fatalf("%s did not become ready: %v", service, err)
+		}
+	}
+}
+
+// startIntegrationServer builds and starts the server binary using the
+// given backends and returns its MCP endpoint
+func startIntegrationServer(t *testing.T, backends map[string]string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to get free port: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	binName := "osmmcp-it"
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	binPath := filepath.Join(t.TempDir(), binName)
+	if out, err := exec.Command("go", "build", "-o", binPath, ".").CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, binPath,
+		"--enable-http",
+		"--http-only",
+		"--http-addr", fmt.Sprintf("127.0.0.1:%d", port),
+		"--enable-monitoring=false",
+		"--preflight=false",
+		"--nominatim-url", backends["nominatim"],
+		"--overpass-url", backends["overpass"],
+		"--overpass-mirrors", "none",
+		"--osrm-url", backends["osrm"],
+		"--tile-url", backends["tiles"],
+		"--elevation-url", backends["elevation"],
+		"--gtfs-feed", gtfsFeedDir,
+		"--tile-hourly-limit", "0",
+		"--nominatim-rps", "50", "--nominatim-burst", "10",
+		"--overpass-rps", "50", "--overpass-burst", "10",
+		"--osrm-rps", "50", "--osrm-burst", "10",
+		"--tile-rps", "50", "--tile-burst", "10",
+	)
+	var logs bytes.Buffer
+	cmd.Stderr = &logs
+	if err := cmd.Start(); err != nil {
+		cancel()
+		t.Fatalf("failed to start server: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		cmd.Wait()
+		if t.Failed() {
+			t.Logf("server log:\n%s", logs.String())
+		}
+	})
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	if err := waitForEndpoint(base+"/health", 15*time.Second); err != nil {
+		t.Fatalf("server did not start: %v", err)
+	}
+	return base + "/mcp"
+}
+
+// rpcError is a JSON-RPC error
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpClient is a minimal MCP client for the streamable HTTP transport
+type mcpClient struct {
+	endpoint string
+	session  string
+	nextID   int
+}
+
+// send posts a JSON-RPC message and returns the result of a request, read
+// from a JSON or an SSE response
+func (c *mcpClient) send(ctx context.Context, method string, params any, notification bool) (json.RawMessage, error) {
+	message := map[string]any{"jsonrpc": "2.0", "method": method}
+	if params != nil {
+		message["params"] = params
+	}
+	if !notification {
+		c.nextID++
+		message["id"] = c.nextID
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if c.session != "" {
+		req.Header.Set("Mcp-Session-Id", c.session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if session := resp.Header.Get("Mcp-Session-Id"); session != "" {
+		c.session = session
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if notification {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: HTTP %d: %s", method, resp.StatusCode, data)
+	}
+
+	// An SSE response carries the reply in its last data line
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var last string
+		for _, line := range strings.Split(string(data), "\n") {
+			if payload, ok := strings.CutPrefix(line, "data:"); ok {
+				last = strings.TrimSpace(payload)
+			}
+		}
+		data = []byte(last)
+	}
+
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("%s: invalid reply %q: %w", method, data, err)
+	}
+	if reply.Error != nil {
+		return nil, fmt.Errorf("%s: JSON-RPC error %d: %s", method, reply.Error.Code, reply.Error.Message)
+	}
+	return reply.Result, nil
+}
+
+func (c *mcpClient) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	return c.send(ctx, method, params, false)
+}
+
+// connectMCP initializes a session with the server
+func connectMCP(t *testing.T, endpoint string) *mcpClient {
+	t.Helper()
+	c := &mcpClient{endpoint: endpoint}
+	ctx := context.Background()
+	_, err := c.call(ctx, "initialize", map[string]any{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "osmmcp-integration", "version": "1.0"},
+	})
+	if err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if _, err := c.send(ctx, "notifications/initialized", nil, true); err != nil {
+		t.Fatalf("initialized notification failed: %v", err)
+	}
+	return c
+}
+
+// toolResult is the result of tools/call
+type toolResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+// callTool calls a tool and checks the result is well formed: it has
+// content, and text that looks like JSON parses as JSON
+func callTool(t *testing.T, c *mcpClient, name string, args map[string]any) toolResult {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), toolCallTimeout)
+	defer cancel()
+
+	raw, err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": args})
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	var result toolResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("%s: invalid result %s: %v", name, raw, err)
+	}
+	if len(result.Content) == 0 {
+		t.Fatalf("%s: result has no content: %s", name, raw)
+	}
+	for _, content := range result.Content {
+		text := strings.TrimSpace(content.Text)
+		if content.Type == "text" && strings.HasPrefix(text, "{") && !json.Valid([]byte(text)) {
+			t.Errorf("%s: result text is not valid JSON: %s", name, text)
+		}
+	}
+	return result
+}
+
+// docExampleArgs reads the example arguments from a tool's documentation
+// resource
+func docExampleArgs(t *testing.T, c *mcpClient, name string) map[string]any {
+	t.Helper()
+	raw, err := c.call(context.Background(), "resources/read", map[string]any{"uri": "doc://tools/" + name})
+	if err != nil {
+		t.Fatalf("reading %s documentation: %v", name, err)
+	}
+	var doc struct {
+		Contents []struct {
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil || len(doc.Contents) == 0 {
+		t.Fatalf("invalid %s documentation: %s", name, raw)
+	}
+
+	_, example, ok := strings.Cut(doc.Contents[0].Text, "## Example\n\n```json\n")
+	if !ok {
+		t.Fatalf("%s documentation has no example", name)
+	}
+	example, _, _ = strings.Cut(example, "\n```")
+	var args map[string]any
+	if err := json.Unmarshal([]byte(example), &args); err != nil {
+		t.Fatalf("%s example is not a JSON object: %v", name, err)
+	}
+	return args
+}
+
+// goldenExpectsError reports whether the golden output of a tool is an
+// error result
+func goldenExpectsError(t *testing.T, name string) bool {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(goldenDir, name+".json"))
+	if err != nil {
+		t.Fatalf("no golden output for %s: %v", name, err)
+	}
+	var golden goldenOutput
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatalf("golden output for %s: %v", name, err)
+	}
+	return golden.IsError
+}
+
+// TestIntegrationAllTools calls every registered tool through the HTTP
+// transport, first with its documented example and then with its arguments
+// in testdata/golden/arguments.json. Examples may fail validation, but every
+// call must return a well formed result rather than a protocol error, a
+// hang or a crash. The golden arguments are in Monaco, so they must succeed
+// unless the golden output records an error.
+func TestIntegrationAllTools(t *testing.T) {
+	c := connectMCP(t, startIntegrationServer(t, integrationBackends(t)))
+
+	data, err := os.ReadFile(goldenArgsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var arguments map[string]map[string]any
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		t.Fatalf("%s: %v", goldenArgsFile, err)
+	}
+
+	raw, err := c.call(context.Background(), "tools/list", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Tools) == 0 {
+		t.Fatal("no tools registered")
+	}
+
+	// Closures reported would change later route results
+	defer callTool(t, c, "report_closure", map[string]any{"action": "clear"})
+
+	for _, tool := range list.Tools {
+		t.Run(tool.Name, func(t *testing.T) {
+			callTool(t, c, tool.Name, docExampleArgs(t, c, tool.Name))
+
+			base, _, _ := strings.Cut(tool.Name, "@")
+			args, ok := arguments[base]
+			if !ok {
+				t.Fatalf("no arguments for %s in %s", base, goldenArgsFile)
+			}
+			// unwatch_area needs a watch to stop
+			if base == "unwatch_area" {
+				args = map[string]any{"uri": startWatch(t, c, arguments["watch_area"])}
+			}
+
+			result := callTool(t, c, tool.Name, args)
+			if result.IsError && !goldenExpectsError(t, tool.Name) {
+				t.Errorf("unexpected error result: %s", result.Content[0].Text)
+			}
+		})
+	}
+}
+
+// startWatch calls watch_area and returns the URI of the watch
+func startWatch(t *testing.T, c *mcpClient, args map[string]any) string {
+	t.Helper()
+	result := callTool(t, c, "watch_area", args)
+	var watch struct {
+		URI string `json:"uri"`
+	}
+	if result.IsError || json.Unmarshal([]byte(result.Content[0].Text), &watch) != nil {
+		t.Fatalf("watch_area failed: %s", result.Content[0].Text)
+	}
+	return watch.URI
+}
+
+// TestIntegrationCoreTools checks the core tools succeed and return data
+// from the backends. The arguments are in Monaco, the area the cassettes
+// were recorded in and the docker instances load.
+func TestIntegrationCoreTools(t *testing.T) {
+	c := connectMCP(t, startIntegrationServer(t, integrationBackends(t)))
+
+	casino := map[string]any{"latitude": 43.7394, "longitude": 7.4271}
+	tests := []struct {
+		tool   string
+		args   map[string]any
+		expect string
+	}{
+		{"geocode_address", map[string]any{"address": "Monaco"}, "Monaco"},
+		{"reverse_geocode", casino, "Monaco"},
+		{"find_nearby_places", map[string]any{"latitude": 43.7394, "longitude": 7.4271, "radius": 500, "category": "restaurant"}, `"places"`},
+		{"osm_query_bbox", map[string]any{
+			"bbox": map[string]any{"minLat": 43.727, "minLon": 7.409, "maxLat": 43.752, "maxLon": 7.440},
+			"tags": map[string]any{"amenity": "restaurant"},
+		}, `"elements"`},
+		{"get_route_directions", map[string]any{"start_lat": 43.7316, "start_lon": 7.4191, "end_lat": 43.7394, "end_lon": 7.4262}, `"distance"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			result := callTool(t, c, tt.tool, tt.args)
+			text := result.Content[0].Text
+			if result.IsError {
+				t.Fatalf("unexpected error result: %s", text)
+			}
+			if !strings.Contains(text, tt.expect) {
+				t.Errorf("result does not contain %s: %s", tt.expect, text)
+			}
+			if !strings.Contains(text, `"attribution"`) {
+				t.Errorf("result carries no attribution: %s", text)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -17,12 +18,17 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/config"
+	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/monitoring"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
+	"github.com/NERVsystems/osmmcp/pkg/preflight"
 	"github.com/NERVsystems/osmmcp/pkg/registration"
 	"github.com/NERVsystems/osmmcp/pkg/server"
 	"github.com/NERVsystems/osmmcp/pkg/tools"
 	"github.com/NERVsystems/osmmcp/pkg/tracing"
+	"github.com/NERVsystems/osmmcp/pkg/transit"
+	"github.com/NERVsystems/osmmcp/pkg/transit/gtfs"
 	ver "github.com/NERVsystems/osmmcp/pkg/version"
 )
 
@@ -35,30 +41,125 @@ var (
 	mergeOnly       bool
 
 	// HTTP transport flags
-	enableHTTP    bool
-	httpOnly      bool
-	httpAddr      string
-	httpBaseURL   string
-	httpAuthType  string
-	httpAuthToken string
+	enableHTTP          bool
+	httpOnly            bool
+	httpAddr            string
+	httpBaseURL         string
+	httpAuthType        string
+	httpAuthToken       string
+	httpAuthTokens      string
+	httpRoles           string
+	authLockoutFailures int
+	authLockoutWindow   time.Duration
+	authLockoutDuration time.Duration
+	httpTrustedProxies  string
+	httpSessionTTL      time.Duration
+	httpCompression     bool
 
 	// Monitoring flags
-	enableMonitoring bool
-	monitoringAddr   string
+	enableMonitoring      bool
+	monitoringAddr        string
+	healthCheckInterval   time.Duration
+	healthCheckTimeout    time.Duration
+	healthCheckJitter     float64
+	healthCheckMaxBackoff time.Duration
+	readyRequires         string
 
 	// Registration flags
 	enableRegistration bool
 	registryURL        string
+	instanceID         string
+	region             string
+	zone               string
+	capacityWeight     int
+	maxSessions        int
 	serviceURL         string
 	internalURL        string
+	registrySecret     string
+
+	// Upstream connection pool flags
+	upstreamMaxConnsPerHost     int
+	upstreamMaxIdleConnsPerHost int
+	upstreamHTTP2               bool
+	upstreamDialTimeout         time.Duration
+	upstreamTLSTimeout          time.Duration
+
+	// Upstream service endpoints
+	nominatimURL string
+	overpassURL  string
+	osrmURL      string
+	elevationURL string
+
+	// Overpass mirrors failed over to
+	overpassMirrors string
+
+	// Data age past which results warn they may be stale
+	staleDataThreshold time.Duration
+
+	// Decimals output coordinates are rounded to
+	coordPrecision int
 
 	// Rate limits for each service
-	nominatimRPS   float64
-	nominatimBurst int
-	overpassRPS    float64
-	overpassBurst  int
-	osrmRPS        float64
-	osrmBurst      int
+	nominatimRPS        float64
+	nominatimBurst      int
+	overpassRPS         float64
+	overpassBurst       int
+	overpassMaxElements int
+	osrmRPS             float64
+	osrmBurst           int
+
+	// Search limit flags
+	searchDefaultRadius float64
+	searchMaxRadius     float64
+	searchDefaultLimit  int
+	searchMaxLimit      int
+	searchToolLimits    string
+
+	// Tile provider and usage policy flags
+	tileURL         string
+	tileAPIKey      string
+	tileRPS         float64
+	tileBurst       int
+	tileHourlyLimit int
+
+	// Closure feed flags
+	closuresFile string
+
+	// Persistent cache flags
+	cacheDir        string
+	cacheDirMaxMB   int
+	cacheMaxEntries string
+
+	// Cache warming flags
+	cacheWarming bool
+	cacheWarmTop int
+
+	// Config file flags
+	configFile           string
+	defaultRegion        string
+	geocodeMinImportance string
+
+	// Response spool flags
+	spoolDir         string
+	spoolThresholdMB int
+	spoolMaxMB       int
+
+	// Simulated clock flags
+	simulatedTime string
+	freezeClock   bool
+
+	// Transit departure flags
+	departuresURL string
+	gtfsFeeds     string
+
+	// Memory watchdog flags
+	memoryWatchdog bool
+	memoryLimitMB  int
+
+	// Preflight flags
+	runPreflight     bool
+	preflightOnly    bool
+	preflightTimeout time.Duration
 )
 
 func init() {
@@ -78,17 +179,53 @@ func init() {
 	flag.StringVar(&httpBaseURL, "http-base-url", "", "Base URL for HTTP transport (auto-detected if empty)")
 	flag.StringVar(&httpAuthType, "http-auth-type", "none", "HTTP authentication type: none, bearer, basic")
 	flag.StringVar(&httpAuthToken, "http-auth-token", "", "HTTP authentication token")
+	flag.StringVar(&httpAuthTokens, "http-auth-tokens", "", "Further bearer tokens for rotation, comma-separated as token or token@from..until with RFC 3339 times, each optionally followed by #role")
+	flag.StringVar(&httpRoles, "http-roles", "", "Tool groups granted to bearer token roles, e.g. reader=read;analyst=read,expensive;operator=* (groups: read, expensive, admin)")
+	flag.IntVar(&authLockoutFailures, "auth-lockout-failures", 10, "Lock out a client IP after this many authentication failures within --auth-lockout-window (0 disables)")
+	flag.DurationVar(&authLockoutWindow, "auth-lockout-window", 5*time.Minute, "Window in which authentication failures are counted")
+	flag.DurationVar(&authLockoutDuration, "auth-lockout-duration", 15*time.Minute, "How long a locked out client IP is refused")
+	flag.StringVar(&httpTrustedProxies, "http-trusted-proxies", "", "Comma-separated IPs or CIDR ranges of reverse proxies whose X-Forwarded-For header identifies clients for the auth lockout (default: none, the peer address is used)")
+	flag.DurationVar(&httpSessionTTL, "http-session-ttl", 30*time.Minute, "End HTTP sessions idle for this long (0 = never)")
+	flag.BoolVar(&httpCompression, "http-compression", true, "Gzip HTTP JSON and SSE responses for clients accepting it")
 
 	// Monitoring flags
 	flag.BoolVar(&enableMonitoring, "enable-monitoring", true, "Enable Prometheus metrics and health endpoints")
 	flag.StringVar(&monitoringAddr, "monitoring-addr", ":9090", "Monitoring server address")
+	flag.DurationVar(&healthCheckInterval, "health-check-interval", monitoring.DefaultCheckInterval, "Interval between upstream service health checks")
+	flag.DurationVar(&healthCheckTimeout, "health-check-timeout", monitoring.DefaultCheckTimeout, "Timeout for each upstream service health check")
+	flag.Float64Var(&healthCheckJitter, "health-check-jitter", monitoring.DefaultCheckJitter, "Randomize health check intervals by up to this fraction (0-1)")
+	flag.DurationVar(&healthCheckMaxBackoff, "health-check-max-backoff", monitoring.DefaultCheckMaxBackoff, "Maximum interval between health checks of a failing service")
+	flag.StringVar(&readyRequires, "ready-requires", "", "Comma-separated backends (nominatim, overpass, osrm) that must be reachable before /ready reports ready")
 
 	// Registration flags
 	flag.BoolVar(&enableRegistration, "enable-registration", false, "Enable service registration with nerva-monitor")
-	flag.StringVar(&registryURL, "registry-url", "", "nerva-monitor registry URL (e.g., http://nerva-monitor:7083)")
+	flag.StringVar(&registryURL, "registry-url", "", "Comma-separated nerva-monitor registry URLs (e.g., http://nerva-monitor:7083); the service registers with each")
+	flag.StringVar(&instanceID, "instance-id", "", "Instance ID distinguishing replicas in the registry (default: hostname)")
+	flag.StringVar(&region, "region", "", "Region label reported to the registry, e.g. eu-west-1")
+	flag.StringVar(&zone, "zone", "", "Zone label reported to the registry, e.g. eu-west-1b")
+	flag.IntVar(&capacityWeight, "capacity-weight", 0, "Relative share of traffic the registry should route to this instance (0 leaves it to the registry)")
+	flag.IntVar(&maxSessions, "max-sessions", 0, "Concurrent sessions this instance accepts, reported to the registry (0 for no hint)")
+	flag.StringVar(&registrySecret, "registry-secret", "", "Secret shared with the registries for HMAC request signing (default: $NERVA_REGISTRY_SECRET)")
 	flag.StringVar(&serviceURL, "service-url", "", "External URL where this service is accessible")
 	flag.StringVar(&internalURL, "internal-url", "", "Internal URL for container environments")
 
+	// Upstream connection pool flags
+	defaults := osm.DefaultTransportConfig()
+	flag.IntVar(&upstreamMaxConnsPerHost, "upstream-max-conns-per-host", defaults.MaxConnsPerHost, "Maximum connections per upstream host (0 for no limit)")
+	flag.IntVar(&upstreamMaxIdleConnsPerHost, "upstream-max-idle-conns-per-host", defaults.MaxIdleConnsPerHost, "Idle connections kept open per upstream host")
+	flag.BoolVar(&upstreamHTTP2, "upstream-http2", defaults.EnableHTTP2, "Use HTTP/2 with upstream servers that support it")
+	flag.DurationVar(&upstreamDialTimeout, "upstream-dial-timeout", defaults.DialTimeout, "Timeout for connecting to upstream servers")
+	flag.DurationVar(&upstreamTLSTimeout, "upstream-tls-timeout", defaults.TLSHandshakeTimeout, "Timeout for TLS handshakes with upstream servers")
+
+	// Upstream service endpoints, for self-hosted instances
+	flag.StringVar(&nominatimURL, "nominatim-url", "", "Nominatim base URL (default: $NOMINATIM_URL or "+osm.DefaultNominatimBaseURL+")")
+	flag.StringVar(&overpassURL, "overpass-url", "", "Overpass interpreter URL (default: $OVERPASS_URL or "+osm.DefaultOverpassBaseURL+")")
+	flag.StringVar(&osrmURL, "osrm-url", "", "OSRM base URL (default: $OSRM_URL or "+osm.DefaultOSRMBaseURL+")")
+	flag.StringVar(&elevationURL, "elevation-url", "", "Open-Meteo elevation endpoint (default: $ELEVATION_URL or "+osm.DefaultElevationBaseURL+")")
+	flag.DurationVar(&staleDataThreshold, "stale-data-threshold", tools.DefaultStaleDataThreshold, "Warn in results when the Overpass data they came from is older than this (0 disables)")
+	flag.IntVar(&coordPrecision, "coordinate-precision", tools.DefaultCoordinatePrecision, "Decimals output coordinates are rounded to unless a call passes coordinate_precision (-1 keeps full precision)")
+	flag.StringVar(&overpassMirrors, "overpass-mirrors", "", "Comma-separated Overpass interpreter URLs tried when Overpass rate limits or fails, or none (default: public mirrors when --overpass-url is the default)")
+
 	// Nominatim rate limits
 	flag.Float64Var(&nominatimRPS, "nominatim-rps", 1.0, "Nominatim rate limit in requests per second")
 	flag.IntVar(&nominatimBurst, "nominatim-burst", 1, "Nominatim rate limit burst size")
@@ -96,15 +233,84 @@ func init() {
 	// Overpass rate limits
 	flag.Float64Var(&overpassRPS, "overpass-rps", 1.0, "Overpass rate limit in requests per second")
 	flag.IntVar(&overpassBurst, "overpass-burst", 1, "Overpass rate limit burst size")
+	flag.IntVar(&overpassMaxElements, "overpass-max-elements", osm.DefaultOverpassElementLimit, "Refuse osm_query_bbox queries matching more elements than this, checked with a cheap count query first (0 disables)")
 
 	// OSRM rate limits
 	flag.Float64Var(&osrmRPS, "osrm-rps", 1.0, "OSRM rate limit in requests per second")
 	flag.IntVar(&osrmBurst, "osrm-burst", 1, "OSRM rate limit burst size")
+
+	// Search limits
+	flag.Float64Var(&searchDefaultRadius, "search-default-radius", 0, "Default radius in meters of the search tools (0 keeps each tool's own, e.g. 1000 for find_nearby_places)")
+	flag.Float64Var(&searchMaxRadius, "search-max-radius", 0, "Largest radius in meters the search tools accept (0 keeps each tool's own, e.g. 50000 for find_nearby_places)")
+	flag.IntVar(&searchDefaultLimit, "search-default-limit", 0, "Default number of results of the search tools (0 keeps each tool's own)")
+	flag.IntVar(&searchMaxLimit, "search-max-limit", 0, "Most results the search tools return (0 keeps each tool's own, e.g. 50 for find_nearby_places)")
+	flag.StringVar(&searchToolLimits, "search-tool-limits", "", "Search limits of single tools, overriding the --search-* settings, e.g. find_nearby_places:max_radius=20000,max_limit=200;search_category:max_limit=500 (settings: default_radius, max_radius, default_limit, max_limit)")
+
+	// Tile provider and usage policy
+	flag.StringVar(&tileURL, "tile-url", core.DefaultTileProvider, "Tile provider base URL or {z}/{x}/{y} template (may include {apikey})")
+	flag.StringVar(&tileAPIKey, "tile-api-key", "", "API key for the tile provider")
+	flag.Float64Var(&tileRPS, "tile-rps", core.DefaultTileRPS, "Tile server rate limit in requests per second")
+	flag.IntVar(&tileBurst, "tile-burst", core.DefaultTileBurst, "Tile server rate limit burst size")
+	flag.IntVar(&tileHourlyLimit, "tile-hourly-limit", core.DefaultTileHourlyLimit, "Maximum upstream tile fetches per hour from the OSMF tile server (0 disables)")
+
+	// Road closures
+	flag.StringVar(&closuresFile, "closures-file", "", "GeoJSON FeatureCollection of temporary road closures to load at startup")
+
+	// Persistent cache
+	flag.StringVar(&cacheDir, "cache-dir", "", "Directory persisting geocoding, route and tile caches across restarts (default: memory only)")
+	flag.IntVar(&cacheDirMaxMB, "cache-dir-max-mb", cache.DefaultDiskCacheMaxBytes>>20, "Disk space in MB for --cache-dir; the entries closest to expiry are removed to make room (0 = unbounded)")
+	flag.StringVar(&cacheMaxEntries, "cache-max-entries", "", "Entry limits of named caches, e.g. geocode=2048,route=512 (caches: global, geocode, reverse_geocode, route, tiles)")
+
+	// Cache warming
+	flag.BoolVar(&cacheWarming, "cache-warming", false, "Refresh the most requested geocoding and POI queries shortly before their cache entries expire, while the server is idle")
+	flag.IntVar(&cacheWarmTop, "cache-warm-top", cache.DefaultWarmTopN, "Number of most requested queries cache warming keeps fresh")
+
+	// Config file
+	flag.StringVar(&configFile, "config", "", "YAML or JSON config file; flags and OSMMCP_* environment variables override its settings")
+	flag.StringVar(&defaultRegion, "default-region", "", "Region appended to single token geocoding queries (default: Singapore)")
+	flag.StringVar(&geocodeMinImportance, "geocode-min-importance", "", "Importance geocode_address needs to prefer a result fitting the query type over a more important one, e.g. address=0,landmark=0.4,area=0.4 (the defaults)")
+
+	// Response spool
+	flag.StringVar(&spoolDir, "spool-dir", "", "Directory for large responses served as spool:// resources (default: a temporary directory)")
+	flag.IntVar(&spoolThresholdMB, "spool-threshold-mb", cache.DefaultSpoolThreshold>>20, "Responses larger than this many MB are spooled to disk and returned as resource links")
+	flag.IntVar(&spoolMaxMB, "spool-max-mb", cache.DefaultSpoolMaxBytes>>20, "Disk space in MB for spooled responses; the oldest are removed to make room")
+
+	// Simulated clock
+	flag.StringVar(&simulatedTime, "simulated-time", "", "Run the server clock from this RFC 3339 time instead of wall-clock time, for testing and training scenarios")
+	flag.BoolVar(&freezeClock, "freeze-clock", false, "Keep the simulated clock at --simulated-time instead of letting it advance")
+
+	// Transit departures
+	flag.StringVar(&departuresURL, "departures-url", "", "JSON departures endpoint used by next_departures, e.g. an adapter for an operator API or GTFS-RT feed")
+	flag.StringVar(&gtfsFeeds, "gtfs-feed", "", "Comma-separated GTFS feeds (zip files or directories) to load for timetables and routes at stops")
+
+	// Memory watchdog flags
+	flag.BoolVar(&memoryWatchdog, "memory-watchdog", true, "Shrink caches when memory use nears the limit")
+	flag.IntVar(&memoryLimitMB, "memory-limit-mb", 0, "Memory limit in MB for the memory watchdog (0 detects the container limit)")
+
+	// Preflight flags
+	flag.BoolVar(&runPreflight, "preflight", true, "Validate configuration and upstream reachability before starting")
+	flag.BoolVar(&preflightOnly, "preflight-only", false, "Run the preflight checks and exit")
+	flag.DurationVar(&preflightTimeout, "preflight-timeout", preflight.DefaultTimeout, "Timeout for each preflight network check")
 }
 
 func main() {
 	flag.Parse()
 
+	// Fill flags not given on the command line from the environment and the
+	// config file, before anything reads them
+	var cfg *config.Config
+	if path := flagOrEnv(configFile, "OSMMCP_CONFIG"); path != "" {
+		var err error
+		if cfg, err = config.Load(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	if err := config.Apply(flag.CommandLine, cfg, os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	// Configure logging
 	var logLevel slog.Level
 	if debug {
@@ -153,10 +359,70 @@ func main() {
 		return
 	}
 
+	// Point upstream requests at self-hosted services, before preflight
+	// checks that they are reachable
+	if err := osm.SetServiceURLs(osm.ServiceURLs{
+		Nominatim: flagOrEnv(nominatimURL, "NOMINATIM_URL"),
+		Overpass:  flagOrEnv(overpassURL, "OVERPASS_URL"),
+		OSRM:      flagOrEnv(osrmURL, "OSRM_URL"),
+		Elevation: flagOrEnv(elevationURL, "ELEVATION_URL"),
+	}); err != nil {
+		logger.Error("invalid upstream service URL", "error", err)
+		os.Exit(1)
+	}
+	if err := osm.SetOverpassMirrors(parseOverpassMirrors(overpassMirrors)); err != nil {
+		logger.Error("invalid --overpass-mirrors", "error", err)
+		os.Exit(1)
+	}
+
+	// Validate the configuration before binding any transport
+	if runPreflight || preflightOnly {
+		problems := preflight.Run(ctx, preflightTimeout, preflightChecks())
+		for _, p := range problems {
+			if p.Warn {
+				logger.Warn("preflight warning", "check", p.Check, "error", p.Err, "fix", p.Fix)
+			} else {
+				logger.Error("preflight failed", "check", p.Check, "error", p.Err, "fix", p.Fix)
+			}
+		}
+		if preflight.HasErrors(problems) {
+			os.Exit(1)
+		}
+		if preflightOnly {
+			logger.Info("preflight passed", "warnings", len(problems))
+			return
+		}
+	}
+
 	// Update global user agent if specified
 	if userAgent != osm.UserAgent {
 		osm.SetUserAgent(userAgent)
 	}
+	if defaultRegion != "" {
+		tools.SetDefaultRegion(defaultRegion)
+	}
+	if geocodeMinImportance != "" {
+		thresholds, err := tools.ParseMinImportance(geocodeMinImportance)
+		if err == nil {
+			err = tools.SetMinImportance(thresholds)
+		}
+		if err != nil {
+			logger.Error("invalid --geocode-min-importance", "error", err)
+			os.Exit(1)
+		}
+	}
+	tools.SetStaleDataThreshold(staleDataThreshold)
+	tools.SetCoordinatePrecision(coordPrecision)
+
+	// Tune the upstream connection pools
+	transportConfig := osm.DefaultTransportConfig()
+	transportConfig.MaxConnsPerHost = upstreamMaxConnsPerHost
+	transportConfig.MaxIdleConnsPerHost = upstreamMaxIdleConnsPerHost
+	transportConfig.MaxIdleConns = max(transportConfig.MaxIdleConns, upstreamMaxIdleConnsPerHost)
+	transportConfig.EnableHTTP2 = upstreamHTTP2
+	transportConfig.DialTimeout = upstreamDialTimeout
+	transportConfig.TLSHandshakeTimeout = upstreamTLSTimeout
+	core.ConfigureTransport(transportConfig)
 
 	// Update rate limits if specified
 	if nominatimRPS != 1.0 || nominatimBurst != 1 {
@@ -168,6 +434,115 @@ func main() {
 	if osrmRPS != 1.0 || osrmBurst != 1 {
 		osm.UpdateOSRMRateLimits(osrmRPS, osrmBurst)
 	}
+	osm.SetOverpassElementLimit(overpassMaxElements)
+
+	// Size the search tools for the backends; self-hosted ones can afford
+	// larger queries than the public ones
+	toolLimits, err := tools.ParseToolSearchLimits(searchToolLimits)
+	if err == nil {
+		err = tools.SetSearchLimits(tools.SearchLimits{
+			DefaultRadius: searchDefaultRadius,
+			MaxRadius:     searchMaxRadius,
+			DefaultLimit:  searchDefaultLimit,
+			MaxLimit:      searchMaxLimit,
+		}, toolLimits)
+	}
+	if err != nil {
+		logger.Error("invalid search limits", "error", err)
+		os.Exit(1)
+	}
+
+	// Configure the tile provider and usage policy
+	if err := core.ConfigureTilePolicy(core.TilePolicyConfig{
+		URL:         tileURL,
+		APIKey:      tileAPIKey,
+		RPS:         tileRPS,
+		Burst:       tileBurst,
+		HourlyLimit: tileHourlyLimit,
+	}); err != nil {
+		logger.Error("invalid tile configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Persist caches on disk
+	if cacheDir != "" {
+		if err := cache.ConfigureDiskCache(cacheDir, int64(cacheDirMaxMB)<<20); err != nil {
+			logger.Error("invalid --cache-dir", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("persistent cache enabled", "dir", cacheDir)
+	}
+	if cacheMaxEntries != "" {
+		limits, err := cache.ParseMaxEntries(cacheMaxEntries)
+		if err != nil {
+			logger.Error("invalid --cache-max-entries", "error", err)
+			os.Exit(1)
+		}
+		for name, n := range limits {
+			cache.SetMaxEntries(name, n)
+		}
+	}
+
+	// Spool large responses to disk
+	if spoolDir != "" || spoolThresholdMB != cache.DefaultSpoolThreshold>>20 || spoolMaxMB != cache.DefaultSpoolMaxBytes>>20 {
+		if err := cache.ConfigureSpool(spoolDir, int64(spoolThresholdMB)<<20, int64(spoolMaxMB)<<20); err != nil {
+			logger.Error("invalid spool configuration", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Simulate the server clock used by time-dependent tools
+	if simulatedTime != "" {
+		start, err := time.Parse(time.RFC3339, simulatedTime)
+		if err != nil {
+			logger.Error("invalid --simulated-time", "value", simulatedTime, "error", err)
+			os.Exit(1)
+		}
+		core.SetSimulatedClock(start, freezeClock)
+		logger.Warn("using simulated clock", "start", start, "frozen", freezeClock)
+	}
+
+	// Load temporary road closures
+	if closuresFile != "" {
+		data, err := os.ReadFile(closuresFile)
+		if err != nil {
+			logger.Error("failed to read closures file", "path", closuresFile, "error", err)
+			os.Exit(1)
+		}
+		closures, err := core.DefaultClosureStore().LoadGeoJSON(data, filepath.Base(closuresFile))
+		if err != nil {
+			logger.Error("failed to load closures", "path", closuresFile, "error", err)
+			os.Exit(1)
+		}
+		logger.Info("loaded road closures", "path", closuresFile, "count", len(closures))
+	}
+
+	// Load GTFS timetables; each feed also provides scheduled departures
+	for _, path := range strings.Split(gtfsFeeds, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		feed, err := gtfs.Load(path, "")
+		if err != nil {
+			logger.Error("failed to load GTFS feed", "path", path, "error", err)
+			os.Exit(1)
+		}
+		gtfs.Register(feed)
+		transit.RegisterProvider(feed)
+		logger.Info("loaded GTFS feed", "path", path, "stops", len(feed.Stops), "routes", len(feed.Routes), "trips", len(feed.Trips))
+	}
+
+	// Register the departures provider
+	if departuresURL != "" {
+		provider, err := transit.NewHTTPProvider("", departuresURL, nil)
+		if err != nil {
+			logger.Error("invalid departures provider", "error", err)
+			os.Exit(1)
+		}
+		transit.RegisterProvider(provider)
+		logger.Info("registered departures provider", "provider", provider.Name())
+	}
 
 	logger.Info("starting OpenStreetMap MCP server",
 		"version", ver.BuildVersion,
@@ -179,16 +554,31 @@ func main() {
 		"overpass_burst", overpassBurst,
 		"osrm_rps", osrmRPS,
 		"osrm_burst", osrmBurst,
+		"tile_rps", tileRPS,
+		"tile_hourly_limit", tileHourlyLimit,
 		"http_enabled", enableHTTP,
 		"monitoring_enabled", enableMonitoring,
 		"monitoring_addr", monitoringAddr)
 
 	// Initialize health checker
 	var healthChecker *monitoring.HealthChecker
+	if !enableMonitoring && readyRequires != "" {
+		logger.Warn("--ready-requires has no effect with monitoring disabled")
+	}
 	if enableMonitoring {
 		healthChecker = monitoring.NewHealthChecker(monitoring.ServiceName, ver.BuildVersion)
 		defer healthChecker.Shutdown()
 
+		required, err := parseRequiredBackends(readyRequires)
+		if err != nil {
+			logger.Error("invalid --ready-requires", "error", err)
+			os.Exit(1)
+		}
+		if len(required) > 0 {
+			healthChecker.SetRequired(required...)
+			logger.Info("readiness requires backends", "backends", required)
+		}
+
 		// Set up monitoring hooks for OSM client
 		osm.SetMonitoringHooks(&osm.MonitoringHooks{
 			OnRequest: func(service, operation string) {
@@ -204,6 +594,12 @@ func main() {
 			OnError: func(service, errorType string) {
 				monitoring.RecordError(service, errorType)
 			},
+			OnRateLimitState: func(service string, tokens float64, queueDepth int) {
+				monitoring.UpdateRateLimitState(service, tokens, queueDepth)
+			},
+			OnConnection: func(service string, reused bool) {
+				monitoring.RecordUpstreamConnection(service, reused)
+			},
 		})
 	}
 
@@ -228,11 +624,72 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	// Start monitoring server if enabled (Prometheus metrics only)
+	// Shrink caches before the process nears its memory limit
+	if memoryWatchdog {
+		config := cache.MemoryWatchdogConfig{
+			LimitBytes: uint64(max(memoryLimitMB, 0)) * 1024 * 1024,
+		}
+		if enableMonitoring {
+			config.Hooks = cache.WatchdogHooks{
+				OnCheck: func(rssBytes, limitBytes uint64, pressure cache.MemoryPressure) {
+					monitoring.UpdateMemoryPressure(rssBytes, limitBytes, int(pressure))
+				},
+				OnEvict: func(cacheType string, evicted, remaining int) {
+					monitoring.RecordCacheEvictions(cacheType, evicted)
+					monitoring.UpdateCacheSize(cacheType, remaining)
+				},
+			}
+		}
+		watchdog, err := cache.NewMemoryWatchdog(config)
+		if err != nil {
+			logger.Info("memory watchdog disabled", "reason", err)
+		} else {
+			watchdog.Start(ctx)
+			logger.Info("started memory watchdog", "limit_bytes", watchdog.LimitBytes())
+		}
+	}
+
+	// Keep popular queries cached, refreshing them while idle
+	if cacheWarming {
+		config := cache.WarmerConfig{TopN: cacheWarmTop}
+		if enableMonitoring {
+			config.Hooks = cache.WarmerHooks{
+				OnRefresh: func(cacheType string, err error) {
+					monitoring.RecordCacheWarmRefresh(cacheType, err == nil)
+				},
+			}
+		}
+		warmer := cache.NewWarmer(config)
+		cache.SetDefaultWarmer(warmer)
+		warmer.Start(ctx)
+		logger.Info("started cache warming", "top", cacheWarmTop)
+	}
+
+	// Start monitoring server if enabled (Prometheus metrics and dashboard)
 	var monitoringServer *http.Server
 	if enableMonitoring {
+		cache.SetStatsHook(func(cacheType string, hit bool) {
+			if hit {
+				monitoring.RecordCacheHit(cacheType)
+			} else {
+				monitoring.RecordCacheMiss(cacheType)
+			}
+		})
+
+		dashboard := monitoring.NewDashboard(monitoring.DashboardConfig{
+			Health: healthChecker,
+			BeforeSample: func() {
+				for cacheType, c := range cache.RegisteredCaches() {
+					monitoring.UpdateCacheSize(cacheType, c.Count())
+				}
+			},
+		})
+		dashboard.Start(ctx)
+
 		mux := http.NewServeMux()
 		mux.Handle("/metrics", promhttp.Handler())
+		mux.Handle("/dashboard", dashboard.PageHandler())
+		mux.Handle("/dashboard/data", dashboard.DataHandler())
 
 		monitoringServer = &http.Server{
 			Addr:              monitoringAddr,
@@ -276,7 +733,11 @@ func main() {
 
 		regCfg := registration.Config{
 			Enabled:           enableRegistration,
-			RegistryURL:       registryURL,
+			RegistryURLs:      splitList(registryURL),
+			InstanceID:        instanceID,
+			Region:            region,
+			Zone:              zone,
+			Capacity:          registration.Capacity{Weight: capacityWeight, MaxSessions: maxSessions},
 			ServiceName:       "osmmcp",
 			ServiceType:       "mcp",
 			ServiceURL:        svcURL,
@@ -290,25 +751,55 @@ func main() {
 				"transport": map[string]bool{"stdio": true, "http": enableHTTP},
 			},
 		}
+		regCfg.SigningSecret = flagOrEnv(registrySecret, "NERVA_REGISTRY_SECRET")
 		regClient = registration.NewClient(regCfg, logger)
 		regClient.Start(ctx)
 		defer regClient.Stop()
 
 		logger.Info("registration client initialized",
-			"registry_url", registryURL,
+			"registry_urls", splitList(registryURL),
+			"region", region,
+			"zone", zone,
 			"service_url", svcURL,
+			"signed", regCfg.SigningSecret != "",
 			"tool_count", len(toolNames))
 	}
 
 	// Start HTTP transport in background if enabled (non-blocking)
 	var httpTransport *server.HTTPTransport
 	if enableHTTP {
+		authTokens, err := core.ParseBearerTokens(httpAuthTokens)
+		if err != nil {
+			logger.Error("invalid --http-auth-tokens", "error", err)
+			os.Exit(1)
+		}
+		roles, err := parseHTTPRoles(httpRoles, authTokens)
+		if err != nil {
+			logger.Error("invalid --http-roles", "error", err)
+			os.Exit(1)
+		}
+		var trustedProxies []string
+		if httpTrustedProxies != "" {
+			trustedProxies = strings.Split(httpTrustedProxies, ",")
+			if _, err := server.ParseTrustedProxies(trustedProxies); err != nil {
+				logger.Error("invalid --http-trusted-proxies", "error", err)
+				os.Exit(1)
+			}
+		}
 		config := server.HTTPTransportConfig{
-			Addr:        httpAddr,
-			BaseURL:     httpBaseURL,
-			AuthType:    httpAuthType,
-			AuthToken:   httpAuthToken,
-			MCPEndpoint: "/mcp",
+			Addr:                httpAddr,
+			BaseURL:             httpBaseURL,
+			AuthType:            httpAuthType,
+			AuthToken:           httpAuthToken,
+			AuthTokens:          authTokens,
+			Roles:               roles,
+			MCPEndpoint:         "/mcp",
+			AuthLockoutFailures: authLockoutFailures,
+			AuthLockoutWindow:   authLockoutWindow,
+			AuthLockoutDuration: authLockoutDuration,
+			TrustedProxies:      trustedProxies,
+			SessionIdleTTL:      httpSessionTTL,
+			Compression:         httpCompression,
 		}
 
 		httpTransport = server.NewHTTPTransport(s.GetMCPServer(), config, logger)
@@ -483,42 +974,223 @@ func showVersion() {
 	fmt.Println(ver.String())
 }
 
-// startExternalServiceMonitoring starts monitoring external services
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseHTTPRoles parses --http-roles and checks that every bearer token
+// role is defined
+func parseHTTPRoles(spec string, tokens []core.BearerToken) (map[string][]string, error) {
+	roles, err := tools.ParseRoles(spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if _, ok := roles[token.Role]; token.Role != "" && !ok {
+			return nil, fmt.Errorf("bearer token role %q is not defined", token.Role)
+		}
+	}
+	return roles, nil
+}
+
+// parseOverpassMirrors parses --overpass-mirrors: empty for the defaults,
+// none for no failover, or a comma-separated list
+func parseOverpassMirrors(value string) []string {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "":
+		return nil
+	case "none":
+		return []string{}
+	}
+	var mirrors []string
+	for _, mirror := range strings.Split(value, ",") {
+		if mirror = strings.TrimSpace(mirror); mirror != "" {
+			mirrors = append(mirrors, mirror)
+		}
+	}
+	return mirrors
+}
+
+// flagOrEnv returns a flag's value, or the environment variable when the
+// flag is unset
+func flagOrEnv(value, env string) string {
+	if value == "" {
+		return os.Getenv(env)
+	}
+	return value
+}
+
+// preflightChecks builds the startup checks for the configured flags
+func preflightChecks() []preflight.Check {
+	var checks []preflight.Check
+	addURL := func(name, raw, fix string, warn bool) {
+		checks = append(checks, preflight.Check{
+			Name: name,
+			Fix:  fix,
+			Warn: warn,
+			Run: func(ctx context.Context) error {
+				return preflight.Reachable(ctx, raw)
+			},
+		})
+	}
+	addStatic := func(name, fix string, err error) {
+		checks = append(checks, preflight.Check{
+			Name: name,
+			Fix:  fix,
+			Run:  func(context.Context) error { return err },
+		})
+	}
+
+	// Public upstreams only warn, so the server still starts offline;
+	// self-hosted ones are expected to be up
+	addURL("nominatim", osm.NominatimBaseURL, "Geocoding tools will fail until the host is reachable; check --nominatim-url", osm.NominatimBaseURL == osm.DefaultNominatimBaseURL)
+	addURL("overpass", osm.OverpassBaseURL, "POI and OSM query tools will fail until the host is reachable; check --overpass-url", osm.OverpassBaseURL == osm.DefaultOverpassBaseURL)
+	addURL("osrm", osm.OSRMBaseURL, "Routing tools will fail until the host is reachable; check --osrm-url", osm.OSRMBaseURL == osm.DefaultOSRMBaseURL)
+	addURL("elevation", osm.ElevationBaseURL, "terrain_risk_screen reports no elevation until the host is reachable; check --elevation-url", osm.ElevationBaseURL == osm.DefaultElevationBaseURL)
+
+	tileFix := "Check --tile-url and --tile-api-key"
+	if strings.Contains(tileURL, "{apikey}") && tileAPIKey == "" {
+		addStatic("--tile-url", "Pass the provider key with --tile-api-key", errors.New("the tile URL needs an API key"))
+	} else {
+		addURL("--tile-url", tileURL, tileFix, tileURL == core.DefaultTileProvider)
+	}
+
+	if departuresURL != "" {
+		addURL("--departures-url", departuresURL, "Check the departures adapter address and that it is running", false)
+	}
+	if enableRegistration {
+		registries := splitList(registryURL)
+		if len(registries) == 0 {
+			addStatic("--registry-url", "Set --registry-url or drop --enable-registration", errors.New("registration is enabled without a registry URL"))
+		}
+		for _, registry := range registries {
+			// Registration retries in the background, so this only warns
+			addURL("--registry-url", registry, "Check the nerva-monitor address", true)
+		}
+		if capacityWeight < 0 || maxSessions < 0 {
+			addStatic("--capacity-weight", "Use a positive value, or 0 for no hint", errors.New("capacity hints cannot be negative"))
+		}
+		for _, u := range []struct{ name, raw string }{{"--service-url", serviceURL}, {"--internal-url", internalURL}} {
+			if u.raw != "" {
+				_, err := preflight.ParseURL(u.raw)
+				addStatic(u.name, "Use an absolute http(s) URL", err)
+			}
+		}
+	}
+
+	for _, path := range splitList(gtfsFeeds) {
+		addStatic("--gtfs-feed", "Point --gtfs-feed at a GTFS zip file or unpacked directory", preflight.ReadablePath(path))
+	}
+	if closuresFile != "" {
+		addStatic("--closures-file", "Point --closures-file at a GeoJSON file", preflight.ReadablePath(closuresFile))
+	}
+
+	if enableHTTP {
+		addStatic("--http-addr", "Use host:port or :port", preflight.ListenAddress(httpAddr))
+		authToken := httpAuthToken
+		if authToken == "" && httpAuthType == "bearer" {
+			authToken = httpAuthTokens
+		}
+		addStatic("--http-auth-type", "Set --http-auth-type and --http-auth-token together", preflight.HTTPAuth(httpAuthType, authToken))
+		if httpAuthTokens != "" {
+			tokens, err := core.ParseBearerTokens(httpAuthTokens)
+			addStatic("--http-auth-tokens", "Use token or token@from..until with RFC 3339 times, comma-separated", err)
+			if err == nil {
+				_, err = parseHTTPRoles(httpRoles, tokens)
+				addStatic("--http-roles", "Define every token role as role=group,group with groups read, expensive, admin or *", err)
+			}
+		}
+		if httpAuthType != "none" && httpAuthToken != "" {
+			if err := core.ValidateAuthToken(httpAuthToken); err != nil {
+				checks = append(checks, preflight.Check{
+					Name: "--http-auth-token",
+					Fix:  "Use a long, randomly generated token",
+					Warn: true,
+					Run:  func(context.Context) error { return err },
+				})
+			}
+		}
+		if httpBaseURL != "" {
+			_, err := preflight.ParseURL(httpBaseURL)
+			addStatic("--http-base-url", "Use an absolute http(s) URL", err)
+		}
+	}
+	if enableMonitoring {
+		addStatic("--monitoring-addr", "Use host:port or :port", preflight.ListenAddress(monitoringAddr))
+	}
+
+	return checks
+}
+
+// externalServiceChecks are the upstream services monitored for health
+var externalServiceChecks = []struct {
+	name  string
+	check func(ctx context.Context) error
+}{
+	{"nominatim", osm.CheckNominatimHealth},
+	{"overpass", osm.CheckOverpassHealth},
+	{"osrm", osm.CheckOSRMHealth},
+}
+
+// parseRequiredBackends parses the --ready-requires list, checking each name
+// against the monitored services
+func parseRequiredBackends(value string) ([]string, error) {
+	var required []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, c := range externalServiceChecks {
+			known = known || c.name == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown backend %q, use nominatim, overpass or osrm", name)
+		}
+		required = append(required, name)
+	}
+	return required, nil
+}
+
+// startExternalServiceMonitoring starts monitoring external services. Each
+// service is checked on its own jittered schedule, backing off while it fails.
 func startExternalServiceMonitoring(healthChecker *monitoring.HealthChecker, logger *slog.Logger) {
-	// Monitor Nominatim service
-	nominatimMonitor := monitoring.NewConnectionMonitor(
-		"nominatim",
-		healthChecker,
-		func() error {
-			return osm.CheckNominatimHealth()
-		},
-		30*time.Second,
-	)
-	nominatimMonitor.Start()
-
-	// Monitor Overpass service
-	overpassMonitor := monitoring.NewConnectionMonitor(
-		"overpass",
-		healthChecker,
-		func() error {
-			return osm.CheckOverpassHealth()
-		},
-		30*time.Second,
-	)
-	overpassMonitor.Start()
-
-	// Monitor OSRM service
-	osrmMonitor := monitoring.NewConnectionMonitor(
-		"osrm",
-		healthChecker,
-		func() error {
-			return osm.CheckOSRMHealth()
-		},
-		30*time.Second,
-	)
-	osrmMonitor.Start()
+	config := monitoring.MonitorConfig{
+		Interval:   healthCheckInterval,
+		Timeout:    healthCheckTimeout,
+		Jitter:     healthCheckJitter,
+		MaxBackoff: healthCheckMaxBackoff,
+	}
+
+	services := make([]string, 0, len(externalServiceChecks))
+	for _, c := range externalServiceChecks {
+		monitoring.NewConnectionMonitorWithConfig(c.name, healthChecker, c.check, config).Start()
+		services = append(services, c.name)
+	}
+
+	// Refresh rate limiter gauges, since tokens refill between requests
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			for _, status := range osm.RateLimitStatus() {
+				monitoring.UpdateRateLimitState(status.Service, status.TokensAvailable, status.QueueDepth)
+			}
+		}
+	}()
 
 	logger.Info("started external service monitoring",
-		"services", []string{"nominatim", "overpass", "osrm"},
-		"check_interval", "30s")
+		"services", services,
+		"check_interval", config.Interval,
+		"jitter", config.Jitter,
+		"max_backoff", config.MaxBackoff)
 }
@@ -2,10 +2,13 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/config"
 )
 
 func TestGenerateClientConfig(t *testing.T) {
@@ -127,3 +130,29 @@ func TestGenerateClientConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRequiredBackends(t *testing.T) {
+	required, err := parseRequiredBackends(" OSRM, overpass ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(required) != 2 || required[0] != "osrm" || required[1] != "overpass" {
+		t.Errorf("got %v, want [osrm overpass]", required)
+	}
+
+	if required, err := parseRequiredBackends(""); err != nil || len(required) != 0 {
+		t.Errorf("expected no backends, got %v %v", required, err)
+	}
+
+	if _, err := parseRequiredBackends("osrm,valhalla"); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
+
+func TestConfigFlagsDefined(t *testing.T) {
+	for _, name := range config.Flags() {
+		if flag.Lookup(name) == nil {
+			t.Errorf("config file setting for undefined flag --%s", name)
+		}
+	}
+}
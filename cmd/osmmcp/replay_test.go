@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cassetteDir holds the upstream responses replayed by the integration
+// and golden output tests
+const cassetteDir = "testdata/integration/cassettes"
+
+// cassette is a recorded upstream response, replayed for requests to its
+// service whose path starts with its route and whose query contains its
+// match. Overpass queries all share one route, so each of its cassettes
+// matches a query shape, such as node.na.nb for street intersections, and a
+// query no cassette matches fails. JSON bodies are kept as JSON so
+// cassettes can be read and edited; others are base64.
+type cassette struct {
+	Service     string          `json:"service"`
+	Route       string          `json:"route"`
+	Match       string          `json:"match,omitempty"`
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type"`
+	JSON        json.RawMessage `json:"json,omitempty"`
+	Body        []byte          `json:"body,omitempty"`
+
+	file string // the file the cassette was read from
+}
+
+// replayServer serves cassettes in place of the upstream services, or in
+// record mode proxies to them and saves what they return
+type replayServer struct {
+	t         *testing.T
+	mu        sync.Mutex
+	cassettes map[string][]cassette // by service and route, longest match first
+	upstreams map[string]string     // services proxied in record mode
+}
+
+func newReplayServer(t *testing.T, upstreams map[string]string) *replayServer {
+	t.Helper()
+	r := &replayServer{t: t, cassettes: make(map[string][]cassette), upstreams: upstreams}
+
+	files, err := filepath.Glob(filepath.Join(cassetteDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.Fatalf("cassette %s: %v", file, err)
+		}
+		c.file = filepath.Base(file)
+		r.add(c)
+	}
+	return r
+}
+
+// add adds a cassette, replacing the one of its route and match
+func (r *replayServer) add(c cassette) {
+	key := c.Service + c.Route
+	cassettes := r.cassettes[key]
+	for i, existing := range cassettes {
+		if existing.Match == c.Match {
+			cassettes[i] = c
+			return
+		}
+	}
+	cassettes = append(cassettes, c)
+	sort.SliceStable(cassettes, func(i, j int) bool {
+		return len(cassettes[i].Match) > len(cassettes[j].Match)
+	})
+	r.cassettes[key] = cassettes
+}
+
+// find returns the cassette replayed for a query to a route
+func (r *replayServer) find(service, route, query string) (cassette, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.cassettes[service+route] {
+		if strings.Contains(query, c.Match) {
+			return c, true
+		}
+	}
+	return cassette{}, false
+}
+
+// requestQuery returns the decoded query of a request, including a form
+// body such as the data of an Overpass POST, and leaves the body readable
+func requestQuery(req *http.Request) string {
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		defer func() { req.Body = io.NopCloser(bytes.NewReader(body)) }()
+	}
+	if err := req.ParseForm(); err != nil {
+		return req.URL.RawQuery
+	}
+
+	keys := make([]string, 0, len(req.Form))
+	for key := range req.Form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		for _, value := range req.Form[key] {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// splitRoute splits a replay server path into its service and the route
+// cassettes are matched on: the first path segment after the service, so
+// /osrm/route/v1/driving/... is replayed from the osrm /route cassette
+func splitRoute(path string) (service, route, rest string) {
+	path = strings.TrimPrefix(path, "/")
+	service, rest, _ = strings.Cut(path, "/")
+	if service == "tiles" || service == "overpass" {
+		return service, "", rest
+	}
+	segment, _, _ := strings.Cut(rest, "/")
+	if segment != "" {
+		route = "/" + segment
+	}
+	return service, route, rest
+}
+
+func (r *replayServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	service, route, rest := splitRoute(req.URL.Path)
+	query := requestQuery(req)
+
+	if upstream, ok := r.upstreams[service]; ok {
+		r.record(w, req, service, route, query, upstream, rest)
+		return
+	}
+
+	c, ok := r.find(service, route, query)
+	if !ok {
+		r.t.Logf("no cassette for %s %s %s", req.Method, req.URL.Path, query)
+		http.Error(w, "no cassette for "+service+route, http.StatusNotFound)
+		return
+	}
+
+	body := c.Body
+	if len(c.JSON) > 0 {
+		body = c.JSON
+	}
+	w.Header().Set("Content-Type", c.ContentType)
+	w.WriteHeader(c.Status)
+	w.Write(body)
+}
+
+// record proxies a request to the real service and saves a successful
+// response as the cassette the request is replayed from. A request no
+// cassette matches gets a new one matching its whole query, to be trimmed
+// to the part that identifies its shape.
+func (r *replayServer) record(w http.ResponseWriter, req *http.Request, service, route, query, upstream, rest string) {
+	target := strings.TrimSuffix(upstream, "/")
+	if rest != "" {
+		target += "/" + rest
+	}
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+
+	proxied, err := http.NewRequestWithContext(req.Context(), req.Method, target, req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	proxied.Header = req.Header.Clone()
+	// Let the client negotiate compression so cassettes store plain bodies
+	proxied.Header.Del("Accept-Encoding")
+	resp, err := http.DefaultClient.Do(proxied)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	c, ok := r.find(service, route, query)
+	if !ok {
+		c = cassette{Service: service, Route: route, Match: query}
+	}
+	c.Status, c.ContentType, c.JSON, c.Body = resp.StatusCode, contentType, nil, nil
+	if strings.Contains(contentType, "json") && json.Valid(body) {
+		c.JSON = body
+	} else {
+		c.Body = body
+	}
+	if err := r.save(c); err != nil {
+		r.t.Errorf("saving cassette: %v", err)
+	}
+}
+
+// save writes a cassette, replacing the previous one of its route and
+// match
+func (r *replayServer) save(c cassette) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c.file == "" {
+		name := c.Service
+		if c.Route != "" {
+			name += "_" + strings.TrimPrefix(c.Route, "/")
+		}
+		if c.Match != "" {
+			name += fmt.Sprintf("_%08x", crc32.ChecksumIEEE([]byte(c.Match)))
+		}
+		c.file = name + ".json"
+	}
+	r.add(c)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(cassetteDir, c.file), append(data, '\n'), 0o644)
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -35,9 +36,12 @@ type TTLCache struct {
 	defaultTTL      time.Duration
 	cleanupInterval time.Duration
 	maxItems        int
+	baseMaxItems    int // capacity before any Shrink
 	stopCleanup     chan bool
 	cleanupStarted  sync.Once
 	cleanupStopped  sync.Once
+	persist         *persistence // set by Persist
+	name            string       // set by Register, labels lookup statistics
 }
 
 // NewTTLCache creates a new cache with the specified TTL and cleanup interval
@@ -48,6 +52,7 @@ func NewTTLCache(defaultTTL, cleanupInterval time.Duration, maxItems int) *TTLCa
 		defaultTTL:      defaultTTL,
 		cleanupInterval: cleanupInterval,
 		maxItems:        maxItems,
+		baseMaxItems:    maxItems,
 		stopCleanup:     make(chan bool),
 	}
 
@@ -87,12 +92,11 @@ func (c *TTLCache) SetWithTTL(key string, value interface{}, ttl time.Duration)
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	c.items[key] = Item{
 		Value:      value,
 		Expiration: expiration,
 	}
+	persist := c.persist
 
 	// Set tracing attributes
 	span.SetAttributes(
@@ -107,6 +111,10 @@ func (c *TTLCache) SetWithTTL(key string, value interface{}, ttl time.Duration)
 		c.evictOldest()
 		span.SetAttributes(attribute.Bool("cache.eviction_triggered", true))
 	}
+	c.mu.Unlock()
+
+	// Write through outside the lock, so reads do not wait on the backend
+	persist.store(key, value, expiration)
 }
 
 // Get retrieves an item from the cache
@@ -119,11 +127,28 @@ func (c *TTLCache) Get(key string) (interface{}, bool) {
 
 	c.mu.RLock()
 	item, found := c.items[key]
+	persist := c.persist
+	name := c.name
 	c.mu.RUnlock()
 
+	// Fall back to the persistent backend, e.g. after a restart
+	if (!found || item.Expired()) && persist != nil {
+		if loaded, ok := persist.load(key); ok {
+			c.mu.Lock()
+			c.items[key] = loaded
+			if c.maxItems > 0 && len(c.items) > c.maxItems {
+				c.evictOldest()
+			}
+			c.mu.Unlock()
+			item, found = loaded, true
+			span.SetAttributes(attribute.Bool("cache.persistent_hit", true))
+		}
+	}
+
 	if !found {
 		// Record cache miss
 		span.SetAttributes(tracing.CacheAttributes(tracing.CacheTypeOSM, false, key)...)
+		recordLookup(name, false)
 		return nil, false
 	}
 
@@ -135,11 +160,13 @@ func (c *TTLCache) Get(key string) (interface{}, bool) {
 		// Record cache miss due to expiration
 		span.SetAttributes(tracing.CacheAttributes(tracing.CacheTypeOSM, false, key)...)
 		span.SetAttributes(attribute.Bool("cache.expired", true))
+		recordLookup(name, false)
 		return nil, false
 	}
 
 	// Record cache hit
 	span.SetAttributes(tracing.CacheAttributes(tracing.CacheTypeOSM, true, key)...)
+	recordLookup(name, true)
 	return item.Value, true
 }
 
@@ -157,7 +184,25 @@ func (c *TTLCache) Delete(key string) {
 
 	c.mu.Lock()
 	delete(c.items, key)
+	persist := c.persist
 	c.mu.Unlock()
+	persist.delete(key)
+}
+
+// Expiration returns when an item held in memory expires, which is the
+// zero time for items that never do. It reports false for missing and
+// expired items.
+func (c *TTLCache) Expiration(key string) (time.Time, bool) {
+	c.mu.RLock()
+	item, found := c.items[key]
+	c.mu.RUnlock()
+	if !found || item.Expired() {
+		return time.Time{}, false
+	}
+	if item.Expiration == 0 {
+		return time.Time{}, true
+	}
+	return time.Unix(0, item.Expiration), true
 }
 
 // Count returns the number of items in the cache
@@ -168,7 +213,8 @@ func (c *TTLCache) Count() int {
 	return count
 }
 
-// Clear removes all items from the cache
+// Clear removes all items from the cache. Persisted entries are kept, so
+// memory pressure does not discard them.
 func (c *TTLCache) Clear() {
 	// Create context and start span for tracing
 	ctx := context.Background()
@@ -186,6 +232,54 @@ func (c *TTLCache) Clear() {
 	)
 }
 
+// Shrink reduces the cache to fraction of its current capacity, evicting
+// expired items first and then those closest to expiry. The reduced capacity
+// also applies to new items until Restore is called. Returns the number of
+// items evicted.
+func (c *TTLCache) Shrink(fraction float64) int {
+	now := time.Now().UnixNano()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	before := len(c.items)
+	for k, v := range c.items {
+		if v.Expiration > 0 && v.Expiration < now {
+			delete(c.items, k)
+		}
+	}
+
+	capacity := c.maxItems
+	if capacity <= 0 {
+		capacity = len(c.items)
+	}
+	c.maxItems = int(float64(capacity) * math.Max(fraction, 0))
+	if c.maxItems <= 0 {
+		// A zero maxItems means unbounded, so clear instead
+		c.items = make(map[string]Item)
+		c.maxItems = 1
+	} else {
+		c.evictOldest()
+	}
+
+	return before - len(c.items)
+}
+
+// Restore returns the cache to the capacity it was created with
+func (c *TTLCache) Restore() {
+	c.mu.Lock()
+	c.maxItems = c.baseMaxItems
+	c.mu.Unlock()
+}
+
+// Capacity returns the maximum number of items the cache currently holds,
+// or 0 when unbounded
+func (c *TTLCache) Capacity() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maxItems
+}
+
 // evictOldest removes the oldest items when cache exceeds maxItems
 // This function assumes the lock is already held
 func (c *TTLCache) evictOldest() {
@@ -271,17 +365,27 @@ func (c *TTLCache) startCleanupTimer() {
 	})
 }
 
-// deleteExpired deletes all expired items
+// deleteExpired deletes all expired items, along with their persisted
+// copies
 func (c *TTLCache) deleteExpired() {
 	now := time.Now().UnixNano()
 
+	var expired []string
 	c.mu.Lock()
 	for k, v := range c.items {
 		if v.Expiration > 0 && v.Expiration < now {
 			delete(c.items, k)
+			expired = append(expired, k)
 		}
 	}
+	persist := c.persist
 	c.mu.Unlock()
+
+	if persist != nil {
+		for _, k := range expired {
+			persist.delete(k)
+		}
+	}
 }
 
 // Stop stops the cleanup timer
@@ -292,6 +396,32 @@ func (c *TTLCache) Stop() {
 }
 
 // Global cache instance
+// defaultGlobalTTL is the TTL of global cache entries stored without a data class
+const defaultGlobalTTL = 5 * time.Minute
+
+// statsHook receives the hits and misses of registered caches
+var statsHook atomic.Pointer[func(cache string, hit bool)]
+
+// SetStatsHook reports every lookup in a registered cache to hook,
+// typically to count hits and misses in metrics. nil removes the hook.
+func SetStatsHook(hook func(cache string, hit bool)) {
+	if hook == nil {
+		statsHook.Store(nil)
+		return
+	}
+	statsHook.Store(&hook)
+}
+
+// recordLookup reports a lookup in the named cache to the stats hook
+func recordLookup(name string, hit bool) {
+	if name == "" {
+		return
+	}
+	if hook := statsHook.Load(); hook != nil {
+		(*hook)(name, hit)
+	}
+}
+
 var (
 	globalCache     *TTLCache
 	globalCacheOnce sync.Once
@@ -301,8 +431,9 @@ var (
 // GetGlobalCache returns the global cache instance
 func GetGlobalCache() *TTLCache {
 	globalCacheOnce.Do(func() {
-		// 5 minute TTL, cleanup every minute, max 1000 items
-		globalCache = NewTTLCache(5*time.Minute, time.Minute, 1000)
+		// 5 minute default TTL, cleanup every minute, max 1000 items
+		globalCache = NewTTLCache(defaultGlobalTTL, time.Minute, MaxEntriesFor("global", 1000))
+		Register("global", globalCache)
 	})
 	return globalCache
 }
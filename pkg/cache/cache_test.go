@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -62,3 +63,35 @@ func TestTTLCacheEviction(t *testing.T) {
 		t.Errorf("expected to get 3 for 'c', got %v", v)
 	}
 }
+
+func TestTTLCacheShrinkAndRestore(t *testing.T) {
+	c := NewTTLCache(time.Hour, 0, 10)
+	defer c.Stop()
+
+	for i := 0; i < 10; i++ {
+		c.SetWithTTL(fmt.Sprintf("key%d", i), i, time.Duration(i+1)*time.Minute)
+	}
+
+	if evicted := c.Shrink(0.5); evicted != 5 {
+		t.Errorf("expected 5 evictions, got %d", evicted)
+	}
+	if c.Capacity() != 5 {
+		t.Errorf("expected capacity 5 while shrunk, got %d", c.Capacity())
+	}
+	// Items closest to expiry go first
+	if _, ok := c.Get("key0"); ok {
+		t.Errorf("expected key0 to be evicted")
+	}
+	if _, ok := c.Get("key9"); !ok {
+		t.Errorf("expected key9 to survive")
+	}
+
+	c.Restore()
+	if c.Capacity() != 10 {
+		t.Errorf("expected capacity 10 after restore, got %d", c.Capacity())
+	}
+
+	if c.Shrink(0); c.Count() != 0 {
+		t.Errorf("expected shrinking to zero to clear the cache, got %d items", c.Count())
+	}
+}
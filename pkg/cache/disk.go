@@ -0,0 +1,404 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend persists cache entries so they survive restarts. Keys are
+// namespaced by the cache that stores them.
+type Backend interface {
+	// Load returns an entry's data and expiration, if present and unexpired
+	Load(key string) (data []byte, expiration time.Time, ok bool)
+	// Store saves an entry until its expiration
+	Store(key string, data []byte, expiration time.Time) error
+	// Delete removes an entry
+	Delete(key string) error
+}
+
+// Codec converts a cache's values to and from bytes for a Backend
+type Codec struct {
+	Encode func(value interface{}) ([]byte, error)
+	Decode func(key string, data []byte) (interface{}, error)
+}
+
+// BytesCodec persists values that are already []byte, such as marshaled
+// JSON responses
+var BytesCodec = Codec{
+	Encode: func(value interface{}) ([]byte, error) {
+		data, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte, got %T", value)
+		}
+		return data, nil
+	},
+	Decode: func(_ string, data []byte) (interface{}, error) {
+		return data, nil
+	},
+}
+
+// JSONCodec persists values of type T as JSON, decoding them back to T
+func JSONCodec[T any]() Codec {
+	return Codec{
+		Encode: func(value interface{}) ([]byte, error) {
+			return json.Marshal(value)
+		},
+		Decode: func(_ string, data []byte) (interface{}, error) {
+			var value T
+			if err := json.Unmarshal(data, &value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		},
+	}
+}
+
+// persistence connects a TTLCache to a Backend
+type persistence struct {
+	backend   Backend
+	namespace string
+	codec     Codec
+}
+
+// Persist writes the cache's entries through to backend under namespace, and
+// reads entries missing from memory, such as after a restart, back from it.
+// A nil backend leaves the cache in memory only.
+func (c *TTLCache) Persist(backend Backend, namespace string, codec Codec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if backend == nil {
+		c.persist = nil
+		return
+	}
+	c.persist = &persistence{backend: backend, namespace: namespace, codec: codec}
+}
+
+// store writes an entry to the backend; errors only cost a cache miss after
+// a restart, so they are not reported
+func (p *persistence) store(key string, value interface{}, expiration int64) {
+	if p == nil || expiration == 0 {
+		return
+	}
+	data, err := p.codec.Encode(value)
+	if err != nil {
+		return
+	}
+	_ = p.backend.Store(p.namespace+":"+key, data, time.Unix(0, expiration))
+}
+
+// load reads an entry from the backend
+func (p *persistence) load(key string) (Item, bool) {
+	if p == nil {
+		return Item{}, false
+	}
+	data, expiration, ok := p.backend.Load(p.namespace + ":" + key)
+	if !ok {
+		return Item{}, false
+	}
+	value, err := p.codec.Decode(key, data)
+	if err != nil {
+		return Item{}, false
+	}
+	return Item{Value: value, Expiration: expiration.UnixNano()}, true
+}
+
+// delete removes an entry from the backend
+func (p *persistence) delete(key string) {
+	if p != nil {
+		_ = p.backend.Delete(p.namespace + ":" + key)
+	}
+}
+
+const (
+	// DefaultDiskCacheMaxBytes caps the disk space used by persisted cache
+	// entries; those closest to expiry are removed to make room
+	DefaultDiskCacheMaxBytes = 1 << 30
+
+	// DefaultDiskPruneInterval is how often expired entries are removed from
+	// disk
+	DefaultDiskPruneInterval = 10 * time.Minute
+)
+
+// diskEntry records the size and expiration of a file in a DiskBackend
+type diskEntry struct {
+	size       int64
+	expiration time.Time
+}
+
+// DiskBackend stores cache entries as files in a directory, one per key,
+// named by a hash of the key. Each file starts with its expiration. The
+// files are indexed in memory, so the directory is kept within maxBytes
+// without walking it.
+type DiskBackend struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	total    int64
+	entries  map[string]diskEntry // by file path
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDiskBackend opens or creates a disk cache in dir holding at most
+// maxBytes, or unbounded if maxBytes is 0. It removes entries that expired
+// while the server was stopped, and then every pruneInterval if positive.
+func NewDiskBackend(dir string, maxBytes int64, pruneInterval time.Duration) (*DiskBackend, error) {
+	if maxBytes < 0 {
+		return nil, fmt.Errorf("cache size must not be negative")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+	d := &DiskBackend{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]diskEntry),
+		stop:     make(chan struct{}),
+	}
+	d.scan(time.Now())
+	if pruneInterval > 0 {
+		go d.pruneEvery(pruneInterval)
+	}
+	return d, nil
+}
+
+// scan indexes the files already in the directory, removing expired
+// entries and leftover temporary files, and evicts entries over the limit
+func (d *DiskBackend) scan(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	filepath.WalkDir(d.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".tmp-") {
+			os.Remove(path)
+			return nil
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if expiration, _, ok := parseDiskEntry(contents); ok && now.Before(expiration) {
+			d.addLocked(path, diskEntry{size: int64(len(contents)), expiration: expiration})
+		} else {
+			os.Remove(path)
+		}
+		return nil
+	})
+	d.evictLocked("")
+}
+
+// pruneEvery removes expired entries every interval until Close
+func (d *DiskBackend) pruneEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			d.Prune(now)
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// Close stops the periodic pruning; the files are kept
+func (d *DiskBackend) Close() {
+	d.stopOnce.Do(func() { close(d.stop) })
+}
+
+// path returns the file of a key, spread over subdirectories by hash prefix
+func (d *DiskBackend) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(d.dir, name[:2], name)
+}
+
+// Load returns an entry's data and expiration, removing it if expired
+func (d *DiskBackend) Load(key string) ([]byte, time.Time, bool) {
+	path := d.path(key)
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	expiration, data, ok := parseDiskEntry(contents)
+	if !ok || !time.Now().Before(expiration) {
+		d.mu.Lock()
+		d.removeLocked(path)
+		d.mu.Unlock()
+		return nil, time.Time{}, false
+	}
+	return data, expiration, true
+}
+
+// Store writes an entry atomically, so a crash never leaves a partial file,
+// and evicts the entries closest to expiry if the cache is over its limit
+func (d *DiskBackend) Store(key string, data []byte, expiration time.Time) error {
+	header := strconv.FormatInt(expiration.UnixNano(), 10) + "\n"
+	size := int64(len(header) + len(data))
+	if d.maxBytes > 0 && size > d.maxBytes {
+		return fmt.Errorf("entry of %d bytes exceeds the cache size", size)
+	}
+
+	path := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(header); err == nil {
+		_, err = tmp.Write(data)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	d.addLocked(path, diskEntry{size: size, expiration: expiration})
+	d.evictLocked(path)
+	return nil
+}
+
+// Delete removes an entry
+func (d *DiskBackend) Delete(key string) error {
+	path := d.path(key)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	d.forgetLocked(path)
+	return nil
+}
+
+// Prune removes expired entries, returning the number removed
+func (d *DiskBackend) Prune(now time.Time) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	removed := 0
+	for path, entry := range d.entries {
+		if !now.Before(entry.expiration) {
+			d.removeLocked(path)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Size returns the bytes and number of entries held on disk
+func (d *DiskBackend) Size() (bytes int64, entries int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.total, len(d.entries)
+}
+
+// addLocked indexes a file, replacing any previous entry at its path
+func (d *DiskBackend) addLocked(path string, entry diskEntry) {
+	d.forgetLocked(path)
+	d.entries[path] = entry
+	d.total += entry.size
+}
+
+// forgetLocked drops a file from the index
+func (d *DiskBackend) forgetLocked(path string) {
+	if entry, ok := d.entries[path]; ok {
+		d.total -= entry.size
+		delete(d.entries, path)
+	}
+}
+
+// removeLocked deletes a file and drops it from the index
+func (d *DiskBackend) removeLocked(path string) {
+	os.Remove(path)
+	d.forgetLocked(path)
+}
+
+// evictLocked deletes the entries closest to expiry, other than keep, until
+// the cache fits its limit
+func (d *DiskBackend) evictLocked(keep string) {
+	if d.maxBytes <= 0 || d.total <= d.maxBytes {
+		return
+	}
+	paths := make([]string, 0, len(d.entries))
+	for path := range d.entries {
+		if path != keep {
+			paths = append(paths, path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return d.entries[paths[i]].expiration.Before(d.entries[paths[j]].expiration)
+	})
+	for _, path := range paths {
+		if d.total <= d.maxBytes {
+			return
+		}
+		d.removeLocked(path)
+	}
+}
+
+// parseDiskEntry splits a file into its expiration and data
+func parseDiskEntry(contents []byte) (time.Time, []byte, bool) {
+	header, data, ok := bytes.Cut(contents, []byte("\n"))
+	if !ok {
+		return time.Time{}, nil, false
+	}
+	nanos, err := strconv.ParseInt(string(header), 10, 64)
+	if err != nil {
+		return time.Time{}, nil, false
+	}
+	return time.Unix(0, nanos), data, true
+}
+
+var (
+	defaultBackend   Backend
+	defaultBackendMu sync.RWMutex
+)
+
+// ConfigureDiskCache persists the caches of geocoding, routes and tiles in
+// dir, using at most maxBytes of disk, or unbounded if maxBytes is 0. It
+// must be called before the caches are first used.
+func ConfigureDiskCache(dir string, maxBytes int64) error {
+	backend, err := NewDiskBackend(dir, maxBytes, DefaultDiskPruneInterval)
+	if err != nil {
+		return err
+	}
+	defaultBackendMu.Lock()
+	if previous, ok := defaultBackend.(*DiskBackend); ok {
+		previous.Close()
+	}
+	defaultBackend = backend
+	defaultBackendMu.Unlock()
+	return nil
+}
+
+// DefaultBackend returns the backend configured with ConfigureDiskCache, or
+// nil if caches are in memory only
+func DefaultBackend() Backend {
+	defaultBackendMu.RLock()
+	defer defaultBackendMu.RUnlock()
+	return defaultBackend
+}
@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiskBackend(t *testing.T) {
+	backend, err := NewDiskBackend(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiration := time.Now().Add(time.Hour).Truncate(0)
+	if err := backend.Store("geocode:london", []byte(`[{"name":"London"}]`), expiration); err != nil {
+		t.Fatal(err)
+	}
+	data, exp, ok := backend.Load("geocode:london")
+	if !ok || string(data) != `[{"name":"London"}]` || !exp.Equal(expiration) {
+		t.Fatalf("unexpected entry %q %s %v", data, exp, ok)
+	}
+
+	// Expired entries are not loaded and are pruned
+	if err := backend.Store("geocode:paris", []byte("[]"), time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := backend.Load("geocode:paris"); ok {
+		t.Error("expected an expired entry to be skipped")
+	}
+	backend.Store("geocode:rome", []byte("[]"), time.Now().Add(-time.Second))
+	if removed := backend.Prune(time.Now()); removed != 1 {
+		t.Errorf("expected one expired entry pruned, got %d", removed)
+	}
+
+	if err := backend.Delete("geocode:london"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := backend.Load("geocode:london"); ok {
+		t.Error("expected the entry to be deleted")
+	}
+	if err := backend.Delete("geocode:london"); err != nil {
+		t.Errorf("deleting a missing entry should succeed, got %v", err)
+	}
+}
+
+func TestDiskBackendMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	// Each entry below takes 100 bytes with its expiration header
+	data := make([]byte, 100-len(strconv.FormatInt(time.Now().UnixNano(), 10))-1)
+	backend, err := NewDiskBackend(dir, 250, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	backend.Store("a", data, now.Add(3*time.Hour))
+	backend.Store("b", data, now.Add(time.Hour))
+	backend.Store("c", data, now.Add(2*time.Hour))
+
+	// The entry closest to expiry was evicted, file and all
+	if _, _, ok := backend.Load("b"); ok {
+		t.Error("expected the entry closest to expiry to be evicted")
+	}
+	if _, err := os.Stat(backend.path("b")); !os.IsNotExist(err) {
+		t.Errorf("expected the evicted file to be removed, got %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok := backend.Load(key); !ok {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+	if size, entries := backend.Size(); size != 200 || entries != 2 {
+		t.Errorf("expected 200 bytes in 2 entries, got %d in %d", size, entries)
+	}
+
+	if err := backend.Store("huge", make([]byte, 300), now.Add(time.Hour)); err == nil {
+		t.Error("expected an entry larger than the cache to be rejected")
+	}
+
+	// Reopening with a smaller limit evicts on startup
+	reopened, err := NewDiskBackend(dir, 150, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, entries := reopened.Size(); entries != 1 {
+		t.Errorf("expected 1 entry after reopening, got %d", entries)
+	}
+	if _, _, ok := reopened.Load("a"); !ok {
+		t.Error("expected the entry furthest from expiry to be kept")
+	}
+}
+
+func TestDiskBackendPrunesPeriodically(t *testing.T) {
+	backend, err := NewDiskBackend(t.TempDir(), 0, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	backend.Store("soon", []byte("[]"), time.Now().Add(20*time.Millisecond))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, entries := backend.Size(); entries == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the expired entry to be pruned")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := os.Stat(backend.path("soon")); !os.IsNotExist(err) {
+		t.Errorf("expected the pruned file to be removed, got %v", err)
+	}
+}
+
+func TestTTLCachePersist(t *testing.T) {
+	type route struct {
+		Distance float64 `json:"distance"`
+	}
+	backend, err := NewDiskBackend(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewTTLCache(time.Hour, 0, 10)
+	defer c.Stop()
+	c.Persist(backend, "route", JSONCodec[*route]())
+	c.Set("a", &route{Distance: 1200})
+	c.SetWithTTL("forever", &route{}, 0)
+
+	// A new cache, as after a restart, reads entries back from disk
+	restarted := NewTTLCache(time.Hour, 0, 10)
+	defer restarted.Stop()
+	restarted.Persist(backend, "route", JSONCodec[*route]())
+	value, ok := restarted.Get("a")
+	if r, isRoute := value.(*route); !ok || !isRoute || r.Distance != 1200 {
+		t.Fatalf("expected the persisted route, got %#v", value)
+	}
+	if restarted.Count() != 1 {
+		t.Errorf("expected the loaded entry to be kept in memory")
+	}
+	if _, ok := restarted.Get("forever"); ok {
+		t.Error("entries without expiration should not be persisted")
+	}
+
+	// Clearing memory keeps persisted entries; Delete removes them
+	restarted.Clear()
+	if _, ok := restarted.Get("a"); !ok {
+		t.Error("expected the entry to survive Clear")
+	}
+	restarted.Delete("a")
+	if _, ok := c.Get("missing"); ok {
+		t.Error("unexpected entry")
+	}
+	c.Clear()
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected Delete to remove the persisted entry")
+	}
+}
+
+// blockingBackend holds every Store until release is closed
+type blockingBackend struct {
+	storing chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingBackend) Load(string) ([]byte, time.Time, bool) { return nil, time.Time{}, false }
+
+func (b *blockingBackend) Store(string, []byte, time.Time) error {
+	b.storing <- struct{}{}
+	<-b.release
+	return nil
+}
+
+func (b *blockingBackend) Delete(string) error { return nil }
+
+func TestTTLCacheSlowBackendDoesNotBlockGet(t *testing.T) {
+	backend := &blockingBackend{storing: make(chan struct{}), release: make(chan struct{})}
+	c := NewTTLCache(time.Hour, 0, 10)
+	defer c.Stop()
+	c.Set("a", []byte("1"))
+	c.Persist(backend, "slow", BytesCodec)
+
+	done := make(chan struct{})
+	go func() {
+		c.Set("b", []byte("2"))
+		close(done)
+	}()
+	<-backend.storing
+
+	got := make(chan bool, 1)
+	go func() {
+		_, ok := c.Get("a")
+		got <- ok
+	}()
+	select {
+	case ok := <-got:
+		if !ok {
+			t.Error("expected the cached entry")
+		}
+	case <-time.After(time.Second):
+		t.Error("Get waited for the backend to store another entry")
+	}
+
+	close(backend.release)
+	<-done
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DataClass identifies the kind of data being cached. Each class has its own
+// TTL, matched to how quickly that data goes stale.
+type DataClass string
+
+// Data classes, from most to least stable
+const (
+	// ClassTile is map tile imagery. The OSMF tile usage policy requires
+	// tiles to be cached for at least seven days.
+	ClassTile DataClass = "tile"
+
+	// ClassAddress is a geocode of a street address, which rarely moves
+	ClassAddress DataClass = "address"
+
+	// ClassGeocode is a geocode of a place name or landmark
+	ClassGeocode DataClass = "geocode"
+
+	// ClassReverseGeocode is the address found at a coordinate
+	ClassReverseGeocode DataClass = "reverse_geocode"
+
+	// ClassRoute is a computed route between fixed points
+	ClassRoute DataClass = "route"
+
+	// ClassPOI is a points-of-interest query. Tags such as opening_hours
+	// are edited often, so POI results are kept briefly.
+	ClassPOI DataClass = "poi"
+
+	// ClassAvailability is data enriched with real-time availability, such
+	// as free parking spaces or open charging points
+	ClassAvailability DataClass = "availability"
+)
+
+var (
+	// ttlPolicy holds the TTL for each data class
+	ttlPolicy = map[DataClass]time.Duration{
+		ClassTile:           7 * 24 * time.Hour,
+		ClassAddress:        7 * 24 * time.Hour,
+		ClassGeocode:        24 * time.Hour,
+		ClassReverseGeocode: 24 * time.Hour,
+		ClassRoute:          time.Hour,
+		ClassPOI:            15 * time.Minute,
+		ClassAvailability:   time.Minute,
+	}
+	ttlPolicyMu sync.RWMutex
+)
+
+// TTLFor returns the TTL for a data class. Unknown classes get the global
+// cache's default of five minutes.
+func TTLFor(class DataClass) time.Duration {
+	ttlPolicyMu.RLock()
+	defer ttlPolicyMu.RUnlock()
+
+	if ttl, ok := ttlPolicy[class]; ok {
+		return ttl
+	}
+	return defaultGlobalTTL
+}
+
+// SetTTLPolicy overrides the TTL for a data class
+func SetTTLPolicy(class DataClass, ttl time.Duration) {
+	ttlPolicyMu.Lock()
+	defer ttlPolicyMu.Unlock()
+	ttlPolicy[class] = ttl
+}
+
+// TTLPolicy returns a copy of the TTL for every data class
+func TTLPolicy() map[DataClass]time.Duration {
+	ttlPolicyMu.RLock()
+	defer ttlPolicyMu.RUnlock()
+
+	policy := make(map[DataClass]time.Duration, len(ttlPolicy))
+	for class, ttl := range ttlPolicy {
+		policy[class] = ttl
+	}
+	return policy
+}
+
+// SetFor adds an item to the cache with the TTL of its data class
+func (c *TTLCache) SetFor(class DataClass, key string, value interface{}) {
+	c.SetWithTTL(key, value, TTLFor(class))
+}
+
+var (
+	// maxEntriesPolicy overrides the entry limit of named caches
+	maxEntriesPolicy   = map[string]int{}
+	maxEntriesPolicyMu sync.RWMutex
+)
+
+// MaxEntriesFor returns the entry limit of a named cache, or def if it was
+// not overridden
+func MaxEntriesFor(name string, def int) int {
+	maxEntriesPolicyMu.RLock()
+	defer maxEntriesPolicyMu.RUnlock()
+
+	if n, ok := maxEntriesPolicy[name]; ok {
+		return n
+	}
+	return def
+}
+
+// SetMaxEntries overrides the entry limit of a named cache. It must be
+// called before the cache is first used.
+func SetMaxEntries(name string, n int) {
+	maxEntriesPolicyMu.Lock()
+	defer maxEntriesPolicyMu.Unlock()
+	maxEntriesPolicy[name] = n
+}
+
+// ParseMaxEntries parses entry limits of named caches, separated by commas:
+//
+//	geocode=2048,route=512
+func ParseMaxEntries(spec string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("cache limit %q must be name=entries", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("cache limit for %s must be a positive number of entries", name)
+		}
+		limits[name] = n
+	}
+	return limits, nil
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLForDefaults(t *testing.T) {
+	if TTLFor(ClassAddress) <= TTLFor(ClassPOI) {
+		t.Errorf("expected address TTL to exceed POI TTL")
+	}
+	if TTLFor(ClassPOI) <= TTLFor(ClassAvailability) {
+		t.Errorf("expected POI TTL to exceed availability TTL")
+	}
+	if got := TTLFor(ClassTile); got < 7*24*time.Hour {
+		t.Errorf("tile TTL %v is below the 7 day minimum", got)
+	}
+	if got := TTLFor(DataClass("unknown")); got != defaultGlobalTTL {
+		t.Errorf("expected unknown class to get %v, got %v", defaultGlobalTTL, got)
+	}
+}
+
+func TestSetTTLPolicy(t *testing.T) {
+	original := TTLFor(ClassPOI)
+	defer SetTTLPolicy(ClassPOI, original)
+
+	SetTTLPolicy(ClassPOI, 2*time.Minute)
+	if got := TTLFor(ClassPOI); got != 2*time.Minute {
+		t.Errorf("expected overridden TTL 2m, got %v", got)
+	}
+
+	policy := TTLPolicy()
+	policy[ClassPOI] = time.Second
+	if got := TTLFor(ClassPOI); got != 2*time.Minute {
+		t.Errorf("modifying the returned policy changed the TTL to %v", got)
+	}
+}
+
+func TestTTLCacheSetFor(t *testing.T) {
+	original := TTLFor(ClassAvailability)
+	defer SetTTLPolicy(ClassAvailability, original)
+	SetTTLPolicy(ClassAvailability, 50*time.Millisecond)
+
+	c := NewTTLCache(time.Hour, 0, 10)
+	defer c.Stop()
+
+	c.SetFor(ClassAvailability, "short", "data")
+	c.SetFor(ClassAddress, "long", "data")
+	time.Sleep(100 * time.Millisecond)
+
+	if _, ok := c.Get("short"); ok {
+		t.Errorf("expected availability item to expire")
+	}
+	if _, ok := c.Get("long"); !ok {
+		t.Errorf("expected address item to still be cached")
+	}
+}
+
+func TestParseMaxEntries(t *testing.T) {
+	limits, err := ParseMaxEntries("geocode=2048, route=512,")
+	if err != nil {
+		t.Fatalf("ParseMaxEntries: %v", err)
+	}
+	if limits["geocode"] != 2048 || limits["route"] != 512 || len(limits) != 2 {
+		t.Errorf("limits = %v", limits)
+	}
+
+	for _, spec := range []string{"geocode", "=5", "route=0", "route=many"} {
+		if _, err := ParseMaxEntries(spec); err == nil {
+			t.Errorf("ParseMaxEntries(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestSetMaxEntries(t *testing.T) {
+	if got := MaxEntriesFor("test_limits", 10); got != 10 {
+		t.Errorf("default = %d, want 10", got)
+	}
+	SetMaxEntries("test_limits", 20)
+	if got := MaxEntriesFor("test_limits", 10); got != 20 {
+		t.Errorf("override = %d, want 20", got)
+	}
+}
@@ -0,0 +1,260 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// SpoolResourceScheme is the URI scheme of spooled responses
+	SpoolResourceScheme = "spool"
+
+	// DefaultSpoolThreshold is the size above which responses are spooled to
+	// disk instead of being returned inline
+	DefaultSpoolThreshold = 4 << 20
+
+	// DefaultSpoolMaxBytes caps the disk space used by spooled responses;
+	// the oldest are removed to make room
+	DefaultSpoolMaxBytes = 512 << 20
+
+	// DefaultSpoolTTL is how long spooled responses stay readable
+	DefaultSpoolTTL = time.Hour
+)
+
+// ErrSpoolNotFound is returned for unknown or expired spooled responses
+var ErrSpoolNotFound = errors.New("spooled response not found or expired")
+
+// SpooledResource is a large response stored on disk and served as an MCP
+// resource
+type SpooledResource struct {
+	URI       string    `json:"uri"`
+	Name      string    `json:"name"`
+	MIMEType  string    `json:"mimeType"`
+	Size      int64     `json:"size"` // bytes
+	ExpiresAt time.Time `json:"expiresAt"`
+	path      string
+}
+
+// Spool stores large responses in files, bounding the memory each request
+// uses, and keeps them readable until they expire
+type Spool struct {
+	mu        sync.Mutex
+	dir       string
+	threshold int64
+	maxBytes  int64
+	ttl       time.Duration
+	total     int64
+	entries   map[string]*SpooledResource
+}
+
+// NewSpool creates a spool in dir, or in a new temporary directory if dir is
+// empty
+func NewSpool(dir string, threshold, maxBytes int64, ttl time.Duration) (*Spool, error) {
+	if dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "osmmcp-spool-"); err != nil {
+			return nil, fmt.Errorf("create spool directory: %w", err)
+		}
+	} else if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create spool directory: %w", err)
+	}
+	return &Spool{
+		dir:       dir,
+		threshold: threshold,
+		maxBytes:  maxBytes,
+		ttl:       ttl,
+		entries:   make(map[string]*SpooledResource),
+	}, nil
+}
+
+var (
+	defaultSpool   *Spool
+	defaultSpoolMu sync.Mutex
+)
+
+// DefaultSpool returns the server-wide spool, created in a temporary
+// directory on first use unless ConfigureSpool set one up
+func DefaultSpool() (*Spool, error) {
+	defaultSpoolMu.Lock()
+	defer defaultSpoolMu.Unlock()
+	if defaultSpool == nil {
+		spool, err := NewSpool("", DefaultSpoolThreshold, DefaultSpoolMaxBytes, DefaultSpoolTTL)
+		if err != nil {
+			return nil, err
+		}
+		defaultSpool = spool
+	}
+	return defaultSpool, nil
+}
+
+// ConfigureSpool replaces the server-wide spool
+func ConfigureSpool(dir string, threshold, maxBytes int64) error {
+	spool, err := NewSpool(dir, threshold, maxBytes, DefaultSpoolTTL)
+	if err != nil {
+		return err
+	}
+	defaultSpoolMu.Lock()
+	defer defaultSpoolMu.Unlock()
+	defaultSpool = spool
+	return nil
+}
+
+// Threshold returns the size above which responses are spooled
+func (s *Spool) Threshold() int64 {
+	return s.threshold
+}
+
+// ReadOrSpool reads r into memory if it is no larger than the threshold.
+// A larger body is streamed to a file instead, holding at most the
+// threshold in memory, and returned as a spooled resource.
+func (s *Spool) ReadOrSpool(r io.Reader, name, mimeType string) ([]byte, *SpooledResource, error) {
+	head, err := io.ReadAll(io.LimitReader(r, s.threshold+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(head)) <= s.threshold {
+		return head, nil, nil
+	}
+	resource, err := s.Store(io.MultiReader(bytes.NewReader(head), r), name, mimeType)
+	return nil, resource, err
+}
+
+// Store streams r to a new spool file
+func (s *Spool) Store(r io.Reader, name, mimeType string) (*SpooledResource, error) {
+	id, err := newSpoolID()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(s.dir, id)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("create spool file: %w", err)
+	}
+
+	// Stop copying once the spool limit is exceeded
+	size, err := io.Copy(file, io.LimitReader(r, s.maxBytes+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > s.maxBytes {
+		err = fmt.Errorf("response exceeds the spool limit of %d bytes", s.maxBytes)
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	resource := &SpooledResource{
+		URI:       SpoolResourceScheme + "://" + id,
+		Name:      name,
+		MIMEType:  mimeType,
+		Size:      size,
+		ExpiresAt: time.Now().Add(s.ttl),
+		path:      path,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpiredLocked()
+	s.entries[resource.URI] = resource
+	s.total += size
+	s.evictLocked(resource.URI)
+	return resource, nil
+}
+
+// Open returns a spooled resource and a reader for its contents
+func (s *Spool) Open(uri string) (*SpooledResource, io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpiredLocked()
+
+	resource, ok := s.entries[uri]
+	if !ok {
+		return nil, nil, ErrSpoolNotFound
+	}
+	file, err := os.Open(resource.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	copied := *resource
+	return &copied, file, nil
+}
+
+// List returns the spooled resources that have not expired, newest first
+func (s *Spool) List() []SpooledResource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpiredLocked()
+
+	resources := make([]SpooledResource, 0, len(s.entries))
+	for _, resource := range s.entries {
+		resources = append(resources, *resource)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].ExpiresAt.After(resources[j].ExpiresAt)
+	})
+	return resources
+}
+
+// Remove deletes a spooled resource
+func (s *Spool) Remove(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(uri)
+}
+
+// removeExpiredLocked deletes expired resources
+func (s *Spool) removeExpiredLocked() {
+	now := time.Now()
+	for uri, resource := range s.entries {
+		if now.After(resource.ExpiresAt) {
+			s.removeLocked(uri)
+		}
+	}
+}
+
+// evictLocked deletes the oldest resources, other than keep, until the
+// spool fits its limit
+func (s *Spool) evictLocked(keep string) {
+	for s.total > s.maxBytes {
+		oldest := ""
+		for uri, resource := range s.entries {
+			if uri != keep && (oldest == "" || resource.ExpiresAt.Before(s.entries[oldest].ExpiresAt)) {
+				oldest = uri
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		s.removeLocked(oldest)
+	}
+}
+
+// removeLocked deletes a resource and its file
+func (s *Spool) removeLocked(uri string) {
+	resource, ok := s.entries[uri]
+	if !ok {
+		return
+	}
+	os.Remove(resource.path)
+	s.total -= resource.Size
+	delete(s.entries, uri)
+}
+
+// newSpoolID returns a random, unguessable resource ID
+func newSpoolID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
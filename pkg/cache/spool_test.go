@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpoolReadOrSpool(t *testing.T) {
+	s, err := NewSpool(t.TempDir(), 10, 100, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, resource, err := s.ReadOrSpool(strings.NewReader("small"), "small", "text/plain")
+	if err != nil || resource != nil || string(data) != "small" {
+		t.Fatalf("expected a small body inline, got %q %v %v", data, resource, err)
+	}
+
+	body := strings.Repeat("x", 50)
+	data, resource, err = s.ReadOrSpool(strings.NewReader(body), "large", "text/plain")
+	if err != nil || data != nil || resource == nil {
+		t.Fatalf("expected a large body to be spooled, got %q %v %v", data, resource, err)
+	}
+	if resource.Size != 50 || !strings.HasPrefix(resource.URI, "spool://") {
+		t.Errorf("unexpected resource %+v", resource)
+	}
+
+	_, r, err := s.Open(resource.URI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	got, _ := io.ReadAll(r)
+	if string(got) != body {
+		t.Errorf("expected the spooled body back, got %q", got)
+	}
+}
+
+func TestSpoolLimits(t *testing.T) {
+	s, err := NewSpool(t.TempDir(), 0, 100, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Store(strings.NewReader(strings.Repeat("x", 101)), "too large", "text/plain"); err == nil {
+		t.Error("expected a body over the spool limit to fail")
+	}
+
+	// The oldest resource is evicted to make room
+	first, _ := s.Store(strings.NewReader(strings.Repeat("a", 60)), "first", "text/plain")
+	second, _ := s.Store(strings.NewReader(strings.Repeat("b", 60)), "second", "text/plain")
+	if _, _, err := s.Open(first.URI); !errors.Is(err, ErrSpoolNotFound) {
+		t.Errorf("expected the first resource to be evicted, got %v", err)
+	}
+	if len(s.List()) != 1 || s.List()[0].URI != second.URI {
+		t.Errorf("expected only the second resource, got %+v", s.List())
+	}
+}
+
+func TestSpoolExpiry(t *testing.T) {
+	s, err := NewSpool(t.TempDir(), 0, 100, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource, err := s.Store(strings.NewReader("data"), "expiring", "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, _, err := s.Open(resource.URI); !errors.Is(err, ErrSpoolNotFound) {
+		t.Errorf("expected the resource to expire, got %v", err)
+	}
+}
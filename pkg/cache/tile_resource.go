@@ -27,6 +27,7 @@ const (
 	TileResourceType = "tile"
 
 	// DefaultTileCacheTTL is the default TTL for cached tiles
+	// Deprecated: tile resources use TTLFor(ClassTile)
 	DefaultTileCacheTTL = 24 * time.Hour
 
 	// MaxCachedTiles is the maximum number of tiles to cache
@@ -67,8 +68,10 @@ type TileResourceManager struct {
 
 // NewTileResourceManager creates a new tile resource manager
 func NewTileResourceManager(logger *slog.Logger) *TileResourceManager {
+	c := NewTTLCache(TTLFor(ClassTile), time.Minute, MaxCachedTiles)
+	Register("tile_resource", c)
 	return &TileResourceManager{
-		cache:  NewTTLCache(DefaultTileCacheTTL, time.Minute, MaxCachedTiles),
+		cache:  c,
 		logger: logger.With("component", "tile_resource_manager"),
 	}
 }
@@ -382,6 +385,6 @@ func (trm *TileResourceManager) GetCacheStats() map[string]interface{} {
 	return map[string]interface{}{
 		"cached_tiles": trm.cache.Count(),
 		"max_tiles":    MaxCachedTiles,
-		"ttl_hours":    DefaultTileCacheTTL.Hours(),
+		"ttl_hours":    TTLFor(ClassTile).Hours(),
 	}
 }
@@ -0,0 +1,338 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cache warming defaults
+const (
+	DefaultWarmInterval  = 30 * time.Second
+	DefaultWarmLead      = 2 * time.Minute  // refresh entries expiring within this
+	DefaultWarmIdleAfter = 5 * time.Second  // quiet time before the server counts as idle
+	DefaultWarmHalfLife  = time.Hour        // popularity halves over this time
+	DefaultWarmTopN      = 50               // most popular queries kept warm
+	DefaultWarmMinHits   = 3                // requests before a query counts as popular
+	DefaultWarmPerCycle  = 5                // refreshes per cycle, to spread upstream load
+	warmRefreshTimeout   = 30 * time.Second // per refresh
+	maxWarmTracked       = 5000             // queries tracked at once
+)
+
+// Refresher fetches a query from upstream again and stores the result in
+// its cache. It is called with a context marked by WithRefresh, so the
+// lookup skips the cached entry it is replacing.
+type Refresher func(ctx context.Context) error
+
+// WarmerHooks reports cache warming activity, typically to metrics
+type WarmerHooks struct {
+	// OnRefresh is called after every refresh attempt
+	OnRefresh func(cache string, err error)
+}
+
+// WarmerConfig configures a Warmer. Zero fields take the defaults.
+type WarmerConfig struct {
+	Interval  time.Duration
+	Lead      time.Duration
+	IdleAfter time.Duration
+	HalfLife  time.Duration
+	TopN      int
+	MinHits   int
+	PerCycle  int
+
+	Hooks WarmerHooks
+}
+
+// PopularQuery is a tracked query in warming statistics. Queries are
+// identified by a hash of their cache key, so statistics never reveal the
+// addresses or places users looked up.
+type PopularQuery struct {
+	Hash  string  `json:"hash"`
+	Cache string  `json:"cache"`
+	Hits  float64 `json:"hits"` // decayed request count
+}
+
+// warmEntry is a tracked query. The key and refresher are only held in
+// memory, alongside the cache entry they refresh.
+type warmEntry struct {
+	name    string
+	cache   *TTLCache
+	key     string
+	refresh Refresher
+	hits    float64
+}
+
+// Warmer counts how often cached queries are requested and, while the
+// server is idle, refreshes the most popular ones shortly before their
+// entries expire. Hot queries then stay cached without adding upstream
+// requests at busy times.
+type Warmer struct {
+	config WarmerConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu           sync.Mutex
+	entries      map[string]*warmEntry // by key hash
+	lastActivity time.Time
+	lastDecay    time.Time
+}
+
+// NewWarmer creates a cache warmer
+func NewWarmer(config WarmerConfig) *Warmer {
+	if config.Interval <= 0 {
+		config.Interval = DefaultWarmInterval
+	}
+	if config.Lead <= 0 {
+		config.Lead = DefaultWarmLead
+	}
+	if config.IdleAfter <= 0 {
+		config.IdleAfter = DefaultWarmIdleAfter
+	}
+	if config.HalfLife <= 0 {
+		config.HalfLife = DefaultWarmHalfLife
+	}
+	if config.TopN <= 0 {
+		config.TopN = DefaultWarmTopN
+	}
+	if config.MinHits <= 0 {
+		config.MinHits = DefaultWarmMinHits
+	}
+	if config.PerCycle <= 0 {
+		config.PerCycle = DefaultWarmPerCycle
+	}
+
+	return &Warmer{
+		config:  config,
+		logger:  slog.Default().With("component", "cache_warmer"),
+		now:     time.Now,
+		entries: make(map[string]*warmEntry),
+	}
+}
+
+// Record counts a request for a cached query and marks the server busy.
+// name labels the cache in statistics.
+func (w *Warmer) Record(name string, c *TTLCache, key string, refresh Refresher) {
+	hash := hashWarmKey(key)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastActivity = w.now()
+	entry, ok := w.entries[hash]
+	if !ok {
+		if len(w.entries) >= maxWarmTracked {
+			w.evictLeastPopularLocked()
+		}
+		entry = &warmEntry{name: name, cache: c, key: key}
+		w.entries[hash] = entry
+	}
+	entry.refresh = refresh
+	entry.hits++
+}
+
+// Start runs warming cycles until the context is cancelled
+func (w *Warmer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.Warm(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Warm runs one warming cycle and returns the number of entries
+// refreshed. It does nothing unless the server is idle, and stops early
+// when a request arrives.
+func (w *Warmer) Warm(ctx context.Context) int {
+	w.mu.Lock()
+	w.decayLocked()
+	if !w.idleLocked() {
+		w.mu.Unlock()
+		return 0
+	}
+	due := w.dueLocked()
+	w.mu.Unlock()
+
+	refreshed := 0
+	for _, entry := range due {
+		w.mu.Lock()
+		idle := w.idleLocked()
+		w.mu.Unlock()
+		if !idle || ctx.Err() != nil {
+			break
+		}
+
+		refreshCtx, cancel := context.WithTimeout(WithRefresh(ctx), warmRefreshTimeout)
+		err := entry.refresh(refreshCtx)
+		cancel()
+
+		if w.config.Hooks.OnRefresh != nil {
+			w.config.Hooks.OnRefresh(entry.name, err)
+		}
+		if err != nil {
+			w.logger.Warn("failed to refresh popular query", "cache", entry.name, "hash", hashWarmKey(entry.key), "error", err)
+			continue
+		}
+		refreshed++
+	}
+	if refreshed > 0 {
+		w.logger.Debug("refreshed popular queries", "count", refreshed)
+	}
+	return refreshed
+}
+
+// Popular returns up to n of the most requested queries, most popular first
+func (w *Warmer) Popular(n int) []PopularQuery {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	popular := make([]PopularQuery, 0, len(w.entries))
+	for hash, entry := range w.entries {
+		popular = append(popular, PopularQuery{Hash: hash, Cache: entry.name, Hits: math.Round(entry.hits*100) / 100})
+	}
+	sort.Slice(popular, func(i, j int) bool {
+		if popular[i].Hits != popular[j].Hits {
+			return popular[i].Hits > popular[j].Hits
+		}
+		return popular[i].Hash < popular[j].Hash
+	})
+	if len(popular) > n {
+		popular = popular[:n]
+	}
+	return popular
+}
+
+// idleLocked reports whether no request has been recorded for the idle
+// period
+func (w *Warmer) idleLocked() bool {
+	return w.now().Sub(w.lastActivity) >= w.config.IdleAfter
+}
+
+// dueLocked returns the popular entries whose cached values expire within
+// the lead time, most popular first. Entries no longer cached, e.g.
+// evicted ones, are left to be fetched on demand.
+func (w *Warmer) dueLocked() []*warmEntry {
+	popular := make([]*warmEntry, 0, len(w.entries))
+	for _, entry := range w.entries {
+		if entry.hits >= float64(w.config.MinHits) {
+			popular = append(popular, entry)
+		}
+	}
+	sort.Slice(popular, func(i, j int) bool {
+		if popular[i].hits != popular[j].hits {
+			return popular[i].hits > popular[j].hits
+		}
+		return popular[i].key < popular[j].key
+	})
+	if len(popular) > w.config.TopN {
+		popular = popular[:w.config.TopN]
+	}
+
+	deadline := w.now().Add(w.config.Lead)
+	due := make([]*warmEntry, 0, w.config.PerCycle)
+	for _, entry := range popular {
+		expiration, ok := entry.cache.Expiration(entry.key)
+		if !ok || expiration.IsZero() || expiration.After(deadline) {
+			continue
+		}
+		due = append(due, entry)
+		if len(due) == w.config.PerCycle {
+			break
+		}
+	}
+	return due
+}
+
+// decayLocked ages request counts so popularity follows recent demand,
+// dropping queries that are no longer requested
+func (w *Warmer) decayLocked() {
+	now := w.now()
+	if w.lastDecay.IsZero() {
+		w.lastDecay = now
+		return
+	}
+	factor := math.Pow(0.5, float64(now.Sub(w.lastDecay))/float64(w.config.HalfLife))
+	w.lastDecay = now
+	for hash, entry := range w.entries {
+		entry.hits *= factor
+		if entry.hits < 0.1 {
+			delete(w.entries, hash)
+		}
+	}
+}
+
+// evictLeastPopularLocked stops tracking the least requested query
+func (w *Warmer) evictLeastPopularLocked() {
+	var victim string
+	least := math.Inf(1)
+	for hash, entry := range w.entries {
+		if entry.hits < least {
+			victim, least = hash, entry.hits
+		}
+	}
+	delete(w.entries, victim)
+}
+
+// hashWarmKey identifies a cache key in statistics and logs
+func hashWarmKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// refreshKey marks contexts of warming refreshes
+type refreshKey struct{}
+
+// WithRefresh marks a context as belonging to a warming refresh
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+// IsRefresh reports whether a lookup is a warming refresh, which must skip
+// the cached entry and fetch from upstream
+func IsRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
+var (
+	defaultWarmer   *Warmer
+	defaultWarmerMu sync.RWMutex
+)
+
+// SetDefaultWarmer sets the warmer that RecordQuery reports to. Warming is
+// off until one is set.
+func SetDefaultWarmer(w *Warmer) {
+	defaultWarmerMu.Lock()
+	defer defaultWarmerMu.Unlock()
+	defaultWarmer = w
+}
+
+// DefaultWarmer returns the warmer set by SetDefaultWarmer, or nil
+func DefaultWarmer() *Warmer {
+	defaultWarmerMu.RLock()
+	defer defaultWarmerMu.RUnlock()
+	return defaultWarmer
+}
+
+// RecordQuery counts a request for a cached query with the default
+// warmer. It does nothing when warming is off or for the warmer's own
+// refreshes.
+func RecordQuery(ctx context.Context, name string, c *TTLCache, key string, refresh Refresher) {
+	if IsRefresh(ctx) {
+		return
+	}
+	if w := DefaultWarmer(); w != nil {
+		w.Record(name, c, key, refresh)
+	}
+}
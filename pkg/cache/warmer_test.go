@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarmerRefreshesPopularEntriesWhenIdle(t *testing.T) {
+	c := NewTTLCache(time.Hour, time.Hour, 0)
+	defer c.Stop()
+	c.SetWithTTL("hot", "old", time.Minute)  // expires within the lead time
+	c.SetWithTTL("fresh", "old", time.Hour)  // expires later
+	c.SetWithTTL("cold", "old", time.Minute) // too few requests
+	c.SetWithTTL("failing", "old", time.Minute)
+
+	var refreshed []string
+	refresher := func(key string, err error) Refresher {
+		return func(ctx context.Context) error {
+			if !IsRefresh(ctx) {
+				t.Errorf("refresh of %s should run with a refresh context", key)
+			}
+			refreshed = append(refreshed, key)
+			if err == nil {
+				c.SetWithTTL(key, "new", time.Hour)
+			}
+			return err
+		}
+	}
+
+	var hookCalls int
+	w := NewWarmer(WarmerConfig{MinHits: 2, Hooks: WarmerHooks{OnRefresh: func(string, error) { hookCalls++ }}})
+	now := time.Now() // cache expirations use the real clock
+	w.now = func() time.Time { return now }
+
+	for range 3 {
+		w.Record("test", c, "hot", refresher("hot", nil))
+		w.Record("test", c, "fresh", refresher("fresh", nil))
+		w.Record("test", c, "failing", refresher("failing", errors.New("upstream down")))
+	}
+	w.Record("test", c, "cold", refresher("cold", nil))
+
+	// A request just arrived, so the server is busy
+	if n := w.Warm(context.Background()); n != 0 || len(refreshed) != 0 {
+		t.Fatalf("busy server: refreshed %d entries %v", n, refreshed)
+	}
+
+	now = now.Add(DefaultWarmIdleAfter)
+	if n := w.Warm(context.Background()); n != 1 {
+		t.Errorf("got %d successful refreshes, want 1", n)
+	}
+	if len(refreshed) != 2 || hookCalls != 2 {
+		t.Errorf("refreshed %v with %d hook calls, want hot and failing", refreshed, hookCalls)
+	}
+	if value, _ := c.Get("hot"); value != "new" {
+		t.Errorf("hot entry = %v, want the refreshed value", value)
+	}
+
+	// The refreshed entry is no longer due
+	refreshed = nil
+	w.Warm(context.Background())
+	if len(refreshed) != 1 || refreshed[0] != "failing" {
+		t.Errorf("second cycle refreshed %v, want only failing", refreshed)
+	}
+}
+
+func TestWarmerPopularHidesKeys(t *testing.T) {
+	c := NewTTLCache(time.Hour, time.Hour, 0)
+	defer c.Stop()
+
+	w := NewWarmer(WarmerConfig{})
+	refresh := func(context.Context) error { return nil }
+	for range 3 {
+		w.Record("geocode", c, "10 downing street", refresh)
+	}
+	w.Record("geocode", c, "baker street", refresh)
+
+	popular := w.Popular(10)
+	if len(popular) != 2 || popular[0].Hits != 3 || popular[1].Hits != 1 || popular[0].Cache != "geocode" {
+		t.Fatalf("got %+v", popular)
+	}
+	for _, query := range popular {
+		if strings.Contains(query.Hash, "street") || len(query.Hash) != 16 {
+			t.Errorf("statistics should identify queries by hash, got %q", query.Hash)
+		}
+	}
+	if got := w.Popular(1); len(got) != 1 || got[0].Hash != popular[0].Hash {
+		t.Errorf("Popular(1) = %+v", got)
+	}
+}
+
+func TestWarmerDecay(t *testing.T) {
+	c := NewTTLCache(time.Hour, time.Hour, 0)
+	defer c.Stop()
+
+	w := NewWarmer(WarmerConfig{HalfLife: time.Hour})
+	now := time.Unix(1_700_000_000, 0)
+	w.now = func() time.Time { return now }
+
+	for range 4 {
+		w.Record("test", c, "query", func(context.Context) error { return nil })
+	}
+	w.Warm(context.Background()) // starts the decay clock
+
+	now = now.Add(time.Hour)
+	w.Warm(context.Background())
+	if popular := w.Popular(1); len(popular) != 1 || popular[0].Hits != 2 {
+		t.Fatalf("after one half-life got %+v, want 2 hits", popular)
+	}
+
+	now = now.Add(10 * time.Hour)
+	w.Warm(context.Background())
+	if popular := w.Popular(1); len(popular) != 0 {
+		t.Errorf("queries no longer requested should be dropped, got %+v", popular)
+	}
+}
+
+func TestRecordQuery(t *testing.T) {
+	c := NewTTLCache(time.Hour, time.Hour, 0)
+	defer c.Stop()
+	refresh := func(context.Context) error { return nil }
+
+	// Warming is off without a default warmer
+	RecordQuery(context.Background(), "test", c, "key", refresh)
+
+	w := NewWarmer(WarmerConfig{})
+	SetDefaultWarmer(w)
+	defer SetDefaultWarmer(nil)
+
+	RecordQuery(context.Background(), "test", c, "key", refresh)
+	RecordQuery(WithRefresh(context.Background()), "test", c, "key", refresh)
+	if popular := w.Popular(1); len(popular) != 1 || popular[0].Hits != 1 {
+		t.Errorf("refreshes should not count as requests, got %+v", popular)
+	}
+}
+
+func TestTTLCacheExpirationTime(t *testing.T) {
+	c := NewTTLCache(time.Hour, time.Hour, 0)
+	defer c.Stop()
+
+	c.SetWithTTL("timed", 1, time.Minute)
+	c.SetWithTTL("forever", 2, 0)
+
+	if expiration, ok := c.Expiration("timed"); !ok || time.Until(expiration) > time.Minute || time.Until(expiration) < 50*time.Second {
+		t.Errorf("timed: got %v, %v", expiration, ok)
+	}
+	if expiration, ok := c.Expiration("forever"); !ok || !expiration.IsZero() {
+		t.Errorf("forever: got %v, %v", expiration, ok)
+	}
+	if _, ok := c.Expiration("missing"); ok {
+		t.Error("missing keys should not have an expiration")
+	}
+}
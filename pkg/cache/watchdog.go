@@ -0,0 +1,306 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryPressure describes how close the process is to its memory limit
+type MemoryPressure int
+
+// Memory pressure levels
+const (
+	PressureNormal MemoryPressure = iota
+	PressureHigh
+	PressureCritical
+)
+
+// String returns the name of a pressure level
+func (p MemoryPressure) String() string {
+	switch p {
+	case PressureHigh:
+		return "high"
+	case PressureCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// Memory watchdog defaults
+const (
+	DefaultWatchdogInterval = 10 * time.Second
+	DefaultHighWater        = 0.80 // shrink caches above 80% of the limit
+	DefaultCriticalWater    = 0.90 // clear caches above 90% of the limit
+	DefaultLowWater         = 0.70 // restore cache capacity below 70% of the limit
+
+	// shrinkFraction is the share of capacity each cache keeps per shrink
+	shrinkFraction = 0.5
+)
+
+var (
+	// registeredCaches holds the caches the memory watchdog may shrink
+	registeredCaches   = make(map[string]*TTLCache)
+	registeredCachesMu sync.RWMutex
+)
+
+// Register makes a cache visible to the memory watchdog under a name, which
+// is also used as its metrics label and reports its lookups to the stats
+// hook
+func Register(name string, c *TTLCache) {
+	registeredCachesMu.Lock()
+	defer registeredCachesMu.Unlock()
+	registeredCaches[name] = c
+
+	c.mu.Lock()
+	c.name = name
+	c.mu.Unlock()
+}
+
+// RegisteredCaches returns the registered caches by name
+func RegisteredCaches() map[string]*TTLCache {
+	registeredCachesMu.RLock()
+	defer registeredCachesMu.RUnlock()
+
+	caches := make(map[string]*TTLCache, len(registeredCaches))
+	for name, c := range registeredCaches {
+		caches[name] = c
+	}
+	return caches
+}
+
+// WatchdogHooks reports memory watchdog activity, typically to metrics
+type WatchdogHooks struct {
+	// OnCheck is called after every check with the RSS and pressure level
+	OnCheck func(rssBytes, limitBytes uint64, pressure MemoryPressure)
+
+	// OnEvict is called when a cache is shrunk under pressure
+	OnEvict func(cache string, evicted, remaining int)
+}
+
+// MemoryWatchdogConfig configures a MemoryWatchdog
+type MemoryWatchdogConfig struct {
+	// LimitBytes is the memory limit to stay under. Zero uses the cgroup
+	// (container) limit.
+	LimitBytes uint64
+
+	Interval      time.Duration
+	HighWater     float64
+	CriticalWater float64
+	LowWater      float64
+
+	Hooks WatchdogHooks
+}
+
+// MemoryWatchdog monitors process RSS and shrinks registered caches before
+// the process reaches its memory limit. Capacity is restored once usage
+// falls below the low water mark.
+type MemoryWatchdog struct {
+	config   MemoryWatchdogConfig
+	readRSS  func() (uint64, error)
+	logger   *slog.Logger
+	mu       sync.Mutex
+	pressure MemoryPressure
+	shrunk   bool
+}
+
+// NewMemoryWatchdog creates a memory watchdog. It returns an error if no
+// limit is configured and none can be detected.
+func NewMemoryWatchdog(config MemoryWatchdogConfig) (*MemoryWatchdog, error) {
+	if config.LimitBytes == 0 {
+		limit, ok := detectMemoryLimit()
+		if !ok {
+			return nil, errors.New("no memory limit configured or detected")
+		}
+		config.LimitBytes = limit
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultWatchdogInterval
+	}
+	if config.HighWater <= 0 {
+		config.HighWater = DefaultHighWater
+	}
+	if config.CriticalWater <= 0 {
+		config.CriticalWater = DefaultCriticalWater
+	}
+	if config.LowWater <= 0 {
+		config.LowWater = DefaultLowWater
+	}
+
+	return &MemoryWatchdog{
+		config:  config,
+		readRSS: processRSS,
+		logger:  slog.Default().With("component", "memory_watchdog"),
+	}, nil
+}
+
+// LimitBytes returns the memory limit the watchdog enforces
+func (w *MemoryWatchdog) LimitBytes() uint64 {
+	return w.config.LimitBytes
+}
+
+// Start runs the watchdog until the context is cancelled
+func (w *MemoryWatchdog) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.Check()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Check samples RSS once, shrinking or restoring caches as needed, and
+// returns the pressure level
+func (w *MemoryWatchdog) Check() MemoryPressure {
+	rss, err := w.readRSS()
+	if err != nil {
+		w.logger.Warn("failed to read process memory", "error", err)
+		return PressureNormal
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	usage := float64(rss) / float64(w.config.LimitBytes)
+	pressure := PressureNormal
+	switch {
+	case usage >= w.config.CriticalWater:
+		pressure = PressureCritical
+	case usage >= w.config.HighWater:
+		pressure = PressureHigh
+	}
+
+	if pressure != w.pressure {
+		w.logger.Info("memory pressure changed",
+			"from", w.pressure.String(),
+			"to", pressure.String(),
+			"rss_bytes", rss,
+			"limit_bytes", w.config.LimitBytes)
+		w.pressure = pressure
+	}
+
+	switch {
+	case pressure == PressureCritical:
+		w.shrinkAll(0)
+	case pressure == PressureHigh:
+		w.shrinkAll(shrinkFraction)
+	case w.shrunk && usage < w.config.LowWater:
+		for _, c := range RegisteredCaches() {
+			c.Restore()
+		}
+		w.shrunk = false
+		w.logger.Info("memory pressure relieved, cache capacity restored", "rss_bytes", rss)
+	}
+
+	if w.config.Hooks.OnCheck != nil {
+		w.config.Hooks.OnCheck(rss, w.config.LimitBytes, pressure)
+	}
+
+	return pressure
+}
+
+// shrinkAll shrinks every registered cache and returns freed memory to the OS.
+// Caches are shrunk in name order so behaviour is reproducible.
+func (w *MemoryWatchdog) shrinkAll(fraction float64) {
+	caches := RegisteredCaches()
+	names := make([]string, 0, len(caches))
+	for name := range caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	total := 0
+	for _, name := range names {
+		c := caches[name]
+		evicted := c.Shrink(fraction)
+		total += evicted
+		if w.config.Hooks.OnEvict != nil {
+			w.config.Hooks.OnEvict(name, evicted, c.Count())
+		}
+	}
+	w.shrunk = true
+
+	w.logger.Warn("shrinking caches under memory pressure",
+		"pressure", w.pressure.String(),
+		"keep_fraction", fraction,
+		"evicted", total)
+
+	// Evicted items are only garbage; release them so RSS actually drops
+	debug.FreeOSMemory()
+}
+
+// processRSS returns the resident set size of the process. On Linux it is
+// read from /proc; elsewhere the Go runtime's view of memory obtained from
+// the OS is used as an approximation.
+func processRSS() (uint64, error) {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) >= 2 {
+			pages, err := strconv.ParseUint(fields[1], 10, 64)
+			if err == nil {
+				return pages * uint64(os.Getpagesize()), nil
+			}
+		}
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.Sys, nil
+}
+
+// maxCgroupLimit is the threshold above which a cgroup v1 limit means
+// "unlimited" (the kernel reports a page-aligned maximum int64)
+const maxCgroupLimit = 1 << 62
+
+// detectMemoryLimit reads the container memory limit from cgroup v2 or v1
+func detectMemoryLimit() (uint64, bool) {
+	for _, path := range []string{
+		"/sys/fs/cgroup/memory.max",                   // cgroup v2
+		"/sys/fs/cgroup/memory/memory.limit_in_bytes", // cgroup v1
+	} {
+		if limit, ok := readCgroupLimit(path); ok {
+			return limit, true
+		}
+	}
+	return 0, false
+}
+
+// readCgroupLimit parses a cgroup memory limit file
+func readCgroupLimit(path string) (uint64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return 0, false
+	}
+	value := strings.TrimSpace(scanner.Text())
+	if value == "max" {
+		return 0, false
+	}
+	limit, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || limit == 0 || limit >= maxCgroupLimit {
+		return 0, false
+	}
+	return limit, true
+}
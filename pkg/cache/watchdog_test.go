@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryWatchdogCheck(t *testing.T) {
+	c := NewTTLCache(time.Hour, 0, 100)
+	defer c.Stop()
+	Register("watchdog_test", c)
+	defer func() {
+		registeredCachesMu.Lock()
+		delete(registeredCaches, "watchdog_test")
+		registeredCachesMu.Unlock()
+	}()
+
+	for i := 0; i < 100; i++ {
+		c.Set(fmt.Sprintf("key%d", i), i)
+	}
+
+	evictions := 0
+	w, err := NewMemoryWatchdog(MemoryWatchdogConfig{
+		LimitBytes: 1000,
+		Hooks: WatchdogHooks{
+			OnEvict: func(name string, evicted, remaining int) {
+				if name == "watchdog_test" {
+					evictions += evicted
+				}
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rss uint64
+	w.readRSS = func() (uint64, error) { return rss, nil }
+
+	rss = 500
+	if p := w.Check(); p != PressureNormal || c.Count() != 100 {
+		t.Fatalf("expected normal pressure and untouched cache, got %s with %d items", p, c.Count())
+	}
+
+	rss = 850
+	if p := w.Check(); p != PressureHigh {
+		t.Fatalf("expected high pressure, got %s", p)
+	}
+	if c.Count() != 50 || evictions != 50 {
+		t.Errorf("expected cache halved, got %d items and %d evictions", c.Count(), evictions)
+	}
+
+	// Between low and high water the reduced capacity is kept
+	rss = 750
+	w.Check()
+	if c.Capacity() != 50 {
+		t.Errorf("expected capacity to stay reduced, got %d", c.Capacity())
+	}
+
+	rss = 950
+	if p := w.Check(); p != PressureCritical || c.Count() != 0 {
+		t.Errorf("expected critical pressure to clear the cache, got %s with %d items", p, c.Count())
+	}
+
+	rss = 600
+	w.Check()
+	if c.Capacity() != 100 {
+		t.Errorf("expected capacity restored to 100, got %d", c.Capacity())
+	}
+}
+
+func TestReadCgroupLimit(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		content string
+		want    uint64
+		ok      bool
+	}{
+		{"536870912\n", 536870912, true},
+		{"max\n", 0, false},
+		{"9223372036854771712\n", 0, false},
+		{"garbage\n", 0, false},
+	}
+
+	for i, tt := range tests {
+		path := filepath.Join(dir, fmt.Sprintf("limit%d", i))
+		if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, ok := readCgroupLimit(path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("readCgroupLimit(%q) = %d, %v; want %d, %v", tt.content, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := readCgroupLimit(filepath.Join(dir, "missing")); ok {
+		t.Errorf("expected missing file to report no limit")
+	}
+}
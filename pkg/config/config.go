@@ -0,0 +1,334 @@
+// Package config loads osmmcp settings from a YAML or JSON file.
+//
+// Every setting in the file mirrors a command line flag. Settings are
+// resolved in order of precedence: flags given on the command line, then
+// environment variables named OSMMCP_ followed by the flag name in upper
+// case with dashes as underscores (OSMMCP_HTTP_ADDR for --http-addr), then
+// the config file, then the flag defaults.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variable of each flag
+const EnvPrefix = "OSMMCP_"
+
+// Config is the structure of a config file. Leaves are Values tagged with
+// the flag they set; a tag ending in a dash on a struct prefixes the flags
+// of its fields.
+type Config struct {
+	UserAgent            Value `json:"user_agent" yaml:"user_agent" flag:"user-agent"`
+	Debug                Value `json:"debug" yaml:"debug" flag:"debug"`
+	DefaultRegion        Value `json:"default_region" yaml:"default_region" flag:"default-region"`
+	GeocodeMinImportance Value `json:"geocode_min_importance" yaml:"geocode_min_importance" flag:"geocode-min-importance"`
+
+	Endpoints    Endpoints    `json:"endpoints" yaml:"endpoints"`
+	RateLimits   RateLimits   `json:"rate_limits" yaml:"rate_limits"`
+	Search       Search       `json:"search" yaml:"search" flag:"search-"`
+	Upstream     Upstream     `json:"upstream" yaml:"upstream"`
+	HTTP         HTTP         `json:"http" yaml:"http"`
+	Cache        Cache        `json:"cache" yaml:"cache"`
+	Monitoring   Monitoring   `json:"monitoring" yaml:"monitoring"`
+	Registration Registration `json:"registration" yaml:"registration"`
+	Transit      Transit      `json:"transit" yaml:"transit"`
+	Preflight    Preflight    `json:"preflight" yaml:"preflight"`
+}
+
+// Endpoints are the upstream services
+type Endpoints struct {
+	Nominatim          Value `json:"nominatim" yaml:"nominatim" flag:"nominatim-url"`
+	Overpass           Value `json:"overpass" yaml:"overpass" flag:"overpass-url"`
+	OverpassMirrors    Value `json:"overpass_mirrors" yaml:"overpass_mirrors" flag:"overpass-mirrors"`
+	StaleDataThreshold Value `json:"stale_data_threshold" yaml:"stale_data_threshold" flag:"stale-data-threshold"`
+	OSRM               Value `json:"osrm" yaml:"osrm" flag:"osrm-url"`
+	Tiles              Value `json:"tiles" yaml:"tiles" flag:"tile-url"`
+	TileAPIKey         Value `json:"tile_api_key" yaml:"tile_api_key" flag:"tile-api-key"`
+}
+
+// RateLimits are the request rates allowed to each upstream service
+type RateLimits struct {
+	Nominatim RateLimit `json:"nominatim" yaml:"nominatim" flag:"nominatim-"`
+	Overpass  RateLimit `json:"overpass" yaml:"overpass" flag:"overpass-"`
+	OSRM      RateLimit `json:"osrm" yaml:"osrm" flag:"osrm-"`
+	Tiles     RateLimit `json:"tiles" yaml:"tiles" flag:"tile-"`
+
+	OverpassMaxElements Value `json:"overpass_max_elements" yaml:"overpass_max_elements" flag:"overpass-max-elements"`
+	TileHourlyLimit     Value `json:"tile_hourly_limit" yaml:"tile_hourly_limit" flag:"tile-hourly-limit"`
+}
+
+// RateLimit is a token bucket rate and burst
+type RateLimit struct {
+	RPS   Value `json:"rps" yaml:"rps" flag:"rps"`
+	Burst Value `json:"burst" yaml:"burst" flag:"burst"`
+}
+
+// Search sizes the queries of the search tools
+type Search struct {
+	DefaultRadius Value `json:"default_radius" yaml:"default_radius" flag:"default-radius"`
+	MaxRadius     Value `json:"max_radius" yaml:"max_radius" flag:"max-radius"`
+	DefaultLimit  Value `json:"default_limit" yaml:"default_limit" flag:"default-limit"`
+	MaxLimit      Value `json:"max_limit" yaml:"max_limit" flag:"max-limit"`
+	ToolLimits    Value `json:"tool_limits" yaml:"tool_limits" flag:"tool-limits"`
+}
+
+// Upstream tunes the connection pools to upstream services
+type Upstream struct {
+	MaxConnsPerHost     Value `json:"max_conns_per_host" yaml:"max_conns_per_host" flag:"upstream-max-conns-per-host"`
+	MaxIdleConnsPerHost Value `json:"max_idle_conns_per_host" yaml:"max_idle_conns_per_host" flag:"upstream-max-idle-conns-per-host"`
+	HTTP2               Value `json:"http2" yaml:"http2" flag:"upstream-http2"`
+	DialTimeout         Value `json:"dial_timeout" yaml:"dial_timeout" flag:"upstream-dial-timeout"`
+	TLSTimeout          Value `json:"tls_timeout" yaml:"tls_timeout" flag:"upstream-tls-timeout"`
+}
+
+// HTTP configures the HTTP transport
+type HTTP struct {
+	Enabled             Value `json:"enabled" yaml:"enabled" flag:"enable-http"`
+	Only                Value `json:"only" yaml:"only" flag:"http-only"`
+	Addr                Value `json:"addr" yaml:"addr" flag:"http-addr"`
+	BaseURL             Value `json:"base_url" yaml:"base_url" flag:"http-base-url"`
+	AuthType            Value `json:"auth_type" yaml:"auth_type" flag:"http-auth-type"`
+	AuthToken           Value `json:"auth_token" yaml:"auth_token" flag:"http-auth-token"`
+	AuthTokens          Value `json:"auth_tokens" yaml:"auth_tokens" flag:"http-auth-tokens"`
+	Roles               Value `json:"roles" yaml:"roles" flag:"http-roles"`
+	AuthLockoutFailures Value `json:"auth_lockout_failures" yaml:"auth_lockout_failures" flag:"auth-lockout-failures"`
+	AuthLockoutWindow   Value `json:"auth_lockout_window" yaml:"auth_lockout_window" flag:"auth-lockout-window"`
+	AuthLockoutDuration Value `json:"auth_lockout_duration" yaml:"auth_lockout_duration" flag:"auth-lockout-duration"`
+	TrustedProxies      Value `json:"trusted_proxies" yaml:"trusted_proxies" flag:"http-trusted-proxies"`
+	SessionTTL          Value `json:"session_ttl" yaml:"session_ttl" flag:"http-session-ttl"`
+	Compression         Value `json:"compression" yaml:"compression" flag:"http-compression"`
+}
+
+// Cache configures the caches and the response spool
+type Cache struct {
+	Dir              Value `json:"dir" yaml:"dir" flag:"cache-dir"`
+	DirMaxMB         Value `json:"dir_max_mb" yaml:"dir_max_mb" flag:"cache-dir-max-mb"`
+	MaxEntries       Value `json:"max_entries" yaml:"max_entries" flag:"cache-max-entries"`
+	SpoolDir         Value `json:"spool_dir" yaml:"spool_dir" flag:"spool-dir"`
+	SpoolThresholdMB Value `json:"spool_threshold_mb" yaml:"spool_threshold_mb" flag:"spool-threshold-mb"`
+	SpoolMaxMB       Value `json:"spool_max_mb" yaml:"spool_max_mb" flag:"spool-max-mb"`
+	MemoryWatchdog   Value `json:"memory_watchdog" yaml:"memory_watchdog" flag:"memory-watchdog"`
+	MemoryLimitMB    Value `json:"memory_limit_mb" yaml:"memory_limit_mb" flag:"memory-limit-mb"`
+	Warming          Value `json:"warming" yaml:"warming" flag:"cache-warming"`
+	WarmTop          Value `json:"warm_top" yaml:"warm_top" flag:"cache-warm-top"`
+}
+
+// Monitoring configures metrics and health checks
+type Monitoring struct {
+	Enabled               Value `json:"enabled" yaml:"enabled" flag:"enable-monitoring"`
+	Addr                  Value `json:"addr" yaml:"addr" flag:"monitoring-addr"`
+	HealthCheckInterval   Value `json:"health_check_interval" yaml:"health_check_interval" flag:"health-check-interval"`
+	HealthCheckTimeout    Value `json:"health_check_timeout" yaml:"health_check_timeout" flag:"health-check-timeout"`
+	HealthCheckJitter     Value `json:"health_check_jitter" yaml:"health_check_jitter" flag:"health-check-jitter"`
+	HealthCheckMaxBackoff Value `json:"health_check_max_backoff" yaml:"health_check_max_backoff" flag:"health-check-max-backoff"`
+	ReadyRequires         Value `json:"ready_requires" yaml:"ready_requires" flag:"ready-requires"`
+}
+
+// Registration configures registration with nerva-monitor
+type Registration struct {
+	Enabled        Value `json:"enabled" yaml:"enabled" flag:"enable-registration"`
+	RegistryURL    Value `json:"registry_url" yaml:"registry_url" flag:"registry-url"`
+	Secret         Value `json:"secret" yaml:"secret" flag:"registry-secret"`
+	InstanceID     Value `json:"instance_id" yaml:"instance_id" flag:"instance-id"`
+	Region         Value `json:"region" yaml:"region" flag:"region"`
+	Zone           Value `json:"zone" yaml:"zone" flag:"zone"`
+	CapacityWeight Value `json:"capacity_weight" yaml:"capacity_weight" flag:"capacity-weight"`
+	MaxSessions    Value `json:"max_sessions" yaml:"max_sessions" flag:"max-sessions"`
+	ServiceURL     Value `json:"service_url" yaml:"service_url" flag:"service-url"`
+	InternalURL    Value `json:"internal_url" yaml:"internal_url" flag:"internal-url"`
+}
+
+// Transit configures departures and timetables
+type Transit struct {
+	DeparturesURL Value `json:"departures_url" yaml:"departures_url" flag:"departures-url"`
+	GTFSFeeds     Value `json:"gtfs_feeds" yaml:"gtfs_feeds" flag:"gtfs-feed"`
+	ClosuresFile  Value `json:"closures_file" yaml:"closures_file" flag:"closures-file"`
+}
+
+// Preflight configures the startup checks
+type Preflight struct {
+	Enabled Value `json:"enabled" yaml:"enabled" flag:"preflight"`
+	Timeout Value `json:"timeout" yaml:"timeout" flag:"preflight-timeout"`
+}
+
+// Value is a setting in the text its flag takes, so a file may write 5m,
+// 1.5, true or a list as it would on the command line. Lists are joined
+// with commas.
+type Value struct {
+	Text string
+	Set  bool
+}
+
+// UnmarshalJSON accepts strings, numbers, booleans and arrays of them
+func (v *Value) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		if err := json.Unmarshal(data, &v.Text); err != nil {
+			return err
+		}
+	case len(data) > 0 && data[0] == '[':
+		var items []Value
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		v.Text = joinValues(items)
+	case len(data) > 0 && data[0] == '{':
+		return errors.New("expected a value, not an object")
+	default:
+		v.Text = string(data)
+	}
+	v.Set = true
+	return nil
+}
+
+// UnmarshalYAML accepts scalars and sequences of them
+func (v *Value) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil
+		}
+		v.Text = node.Value
+	case yaml.SequenceNode:
+		var items []Value
+		if err := node.Decode(&items); err != nil {
+			return err
+		}
+		v.Text = joinValues(items)
+	default:
+		return fmt.Errorf("line %d: expected a value or a list", node.Line)
+	}
+	v.Set = true
+	return nil
+}
+
+// joinValues joins list items with commas
+func joinValues(items []Value) string {
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.Text
+	}
+	return strings.Join(texts, ",")
+}
+
+// Load reads a config file, as JSON if its name ends in .json and as YAML
+// otherwise. Unknown keys are errors, so typos are not silently ignored.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&cfg)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&cfg); errors.Is(err, io.EOF) {
+			err = nil // an empty file sets nothing
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Settings returns the flag values a config sets, by flag name
+func (c *Config) Settings() map[string]string {
+	settings := make(map[string]string)
+	if c != nil {
+		collect(reflect.ValueOf(c).Elem(), "", settings)
+	}
+	return settings
+}
+
+// collect adds the set Values under v to settings
+func collect(v reflect.Value, prefix string, settings map[string]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := prefix + field.Tag.Get("flag")
+		switch value := v.Field(i).Interface().(type) {
+		case Value:
+			if value.Set {
+				settings[name] = value.Text
+			}
+		default:
+			collect(v.Field(i), name, settings)
+		}
+	}
+}
+
+// Flags returns the flag name of every setting a config file can hold
+func Flags() []string {
+	var cfg Config
+	var names []string
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := prefix + field.Tag.Get("flag")
+			if field.Type == reflect.TypeOf(Value{}) {
+				names = append(names, name)
+			} else {
+				walk(field.Type, name)
+			}
+		}
+	}
+	walk(reflect.TypeOf(cfg), "")
+	sort.Strings(names)
+	return names
+}
+
+// EnvName returns the environment variable of a flag
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Apply sets the flags of fs that a config file can hold and that were not
+// given on the command line, from their environment variables, read with
+// getenv, or else from cfg, which may be nil.
+func Apply(fs *flag.FlagSet, cfg *Config, getenv func(string) string) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	settings := cfg.Settings()
+	var errs []error
+	for _, name := range Flags() {
+		switch {
+		case fs.Lookup(name) == nil:
+			errs = append(errs, fmt.Errorf("config setting for unknown flag --%s", name))
+		case explicit[name]:
+		case getenv(EnvName(name)) != "":
+			if err := fs.Set(name, getenv(EnvName(name))); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", EnvName(name), err))
+			}
+		default:
+			if value, ok := settings[name]; ok {
+				if err := fs.Set(name, value); err != nil {
+					errs = append(errs, fmt.Errorf("config setting for --%s: %w", name, err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
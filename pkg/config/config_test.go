@@ -0,0 +1,162 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadYAML(t *testing.T) {
+	path := writeConfig(t, "osmmcp.yaml", `
+default_region: Berlin
+endpoints:
+  nominatim: http://nominatim.internal
+rate_limits:
+  nominatim:
+    rps: 5
+    burst: 10
+search:
+  max_radius: 20000
+  tool_limits: [find_nearby_places:max_limit=200]
+http:
+  enabled: true
+  session_ttl: 10m
+cache:
+  max_entries: geocode=2048
+transit:
+  gtfs_feeds:
+    - a.zip
+    - b.zip
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := map[string]string{
+		"default-region":     "Berlin",
+		"nominatim-url":      "http://nominatim.internal",
+		"nominatim-rps":      "5",
+		"nominatim-burst":    "10",
+		"search-max-radius":  "20000",
+		"search-tool-limits": "find_nearby_places:max_limit=200",
+		"enable-http":        "true",
+		"http-session-ttl":   "10m",
+		"cache-max-entries":  "geocode=2048",
+		"gtfs-feed":          "a.zip,b.zip",
+	}
+	got := cfg.Settings()
+	if len(got) != len(want) {
+		t.Errorf("settings = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := writeConfig(t, "osmmcp.json", `{"rate_limits": {"osrm": {"rps": 2.5}}, "preflight": {"enabled": false}}`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got := cfg.Settings()
+	if got["osrm-rps"] != "2.5" || got["preflight"] != "false" || len(got) != 2 {
+		t.Errorf("settings = %v", got)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	for name, contents := range map[string]string{
+		"typo.yaml":   "endpoints:\n  nominatum: http://x\n",
+		"typo.json":   `{"htpp": {"addr": ":80"}}`,
+		"object.yaml": "debug:\n  level: 1\n",
+	} {
+		if _, err := Load(writeConfig(t, name, contents)); err == nil {
+			t.Errorf("Load(%s) succeeded", name)
+		}
+	}
+}
+
+func TestLoadEmptyFile(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "empty.yaml", ""))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Settings()) != 0 {
+		t.Errorf("settings = %v, want none", cfg.Settings())
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("http-session-ttl"); got != "OSMMCP_HTTP_SESSION_TTL" {
+		t.Errorf("EnvName = %s", got)
+	}
+}
+
+// testFlags defines every flag a config file can hold
+func testFlags() (*flag.FlagSet, map[string]*string) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	values := make(map[string]*string)
+	for _, name := range Flags() {
+		values[name] = fs.String(name, "default", "")
+	}
+	return fs, values
+}
+
+func TestApplyPrecedence(t *testing.T) {
+	fs, values := testFlags()
+	if err := fs.Parse([]string{"--http-addr=:9000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(writeConfig(t, "osmmcp.yaml", "http:\n  addr: ':8000'\n  base_url: http://file\ncache:\n  dir: /file\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"OSMMCP_HTTP_ADDR":     ":7000",
+		"OSMMCP_HTTP_BASE_URL": "http://env",
+	}
+	if err := Apply(fs, cfg, func(key string) string { return env[key] }); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"http-addr":     ":9000",      // flag beats env and file
+		"http-base-url": "http://env", // env beats file
+		"cache-dir":     "/file",      // file beats default
+		"debug":         "default",
+	} {
+		if got := *values[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("http-addr", 0, "")
+	cfg := &Config{HTTP: HTTP{Addr: Value{Text: "not a number", Set: true}}}
+
+	err := Apply(fs, cfg, func(string) string { return "" })
+	if err == nil {
+		t.Fatal("Apply succeeded")
+	}
+	if !strings.Contains(err.Error(), "--http-addr") || !strings.Contains(err.Error(), "unknown flag --debug") {
+		t.Errorf("error = %v", err)
+	}
+}
@@ -9,6 +9,7 @@
 //   - UTM: Universal Transverse Mercator (e.g., "47N 485986 2197460")
 //   - DMS: Degrees Minutes Seconds (e.g., "19°51'22"N 99°48'59"E")
 //   - Decimal Degrees: Standard lat/lon (e.g., "19.856, 99.816")
+//   - Plus Codes: Open Location Code (e.g., "849VCWC8+R9")
 package coords
 
 import (
@@ -27,11 +28,12 @@ import (
 type Format int
 
 const (
-	FormatUnknown Format = iota
-	FormatDecimal        // Decimal degrees (lat, lon)
-	FormatDMS            // Degrees Minutes Seconds
-	FormatMGRS           // Military Grid Reference System
-	FormatUTM            // Universal Transverse Mercator
+	FormatUnknown  Format = iota
+	FormatDecimal         // Decimal degrees (lat, lon)
+	FormatDMS             // Degrees Minutes Seconds
+	FormatMGRS            // Military Grid Reference System
+	FormatUTM             // Universal Transverse Mercator
+	FormatPlusCode        // Open Location Code (full codes only)
 )
 
 // String returns the format name
@@ -47,6 +49,8 @@ func (f Format) String() string {
 		return "mgrs"
 	case FormatUTM:
 		return "utm"
+	case FormatPlusCode:
+		return "pluscode"
 	}
 	return "unknown"
 }
@@ -87,7 +91,12 @@ func Parse(input string) (*ParseResult, error) {
 	}
 
 	// Try each format in order of specificity
-	// MGRS first (most specific pattern)
+	// Plus codes first (the separator is unambiguous)
+	if result, err := ParsePlusCode(input); err == nil {
+		return result, nil
+	}
+
+	// MGRS next
 	if result, err := ParseMGRS(input); err == nil {
 		return result, nil
 	}
@@ -119,6 +128,9 @@ func IsCoordinate(input string) bool {
 	}
 
 	// Check each pattern
+	if IsFullPlusCode(input) {
+		return true
+	}
 	if mgrsRegex.MatchString(input) {
 		return true
 	}
@@ -142,6 +154,9 @@ func DetectFormat(input string) Format {
 		return FormatUnknown
 	}
 
+	if IsFullPlusCode(input) {
+		return FormatPlusCode
+	}
 	if mgrsRegex.MatchString(input) {
 		return FormatMGRS
 	}
@@ -327,11 +342,8 @@ func ParseDecimal(input string) (*ParseResult, error) {
 	}
 
 	// Validate ranges
-	if lat < -90 || lat > 90 {
-		return nil, fmt.Errorf("latitude out of range: %f", lat)
-	}
-	if lon < -180 || lon > 180 {
-		return nil, fmt.Errorf("longitude out of range: %f", lon)
+	if err := geo.ValidateCoords(lat, lon); err != nil {
+		return nil, err
 	}
 
 	return &ParseResult{
@@ -350,8 +362,8 @@ func ToMGRS(lat, lon float64, precision int) (string, error) {
 	if precision < 1 || precision > 5 {
 		precision = 5 // Default to 1m precision
 	}
-	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
-		return "", fmt.Errorf("coordinates out of range: lat=%f, lon=%f", lat, lon)
+	if err := geo.ValidateCoords(lat, lon); err != nil {
+		return "", err
 	}
 
 	result, err := mgrs.LatLngToMGRS(lat, lon, precision)
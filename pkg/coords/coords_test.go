@@ -414,6 +414,8 @@ func TestParse(t *testing.T) {
 		{name: "Auto-detect DMS", input: `19°51'22"N 99°49'0"E`, wantFormat: FormatDMS, wantErr: false},
 		// Decimal
 		{name: "Auto-detect Decimal", input: "19.856, 99.817", wantFormat: FormatDecimal, wantErr: false},
+		// Plus code
+		{name: "Auto-detect Plus Code", input: "7QWJV2VF+WF", wantFormat: FormatPlusCode, wantErr: false},
 		// Unknown format
 		{name: "Unknown format - address", input: "123 Main Street, New York", wantErr: true},
 		{name: "Empty string", input: "", wantErr: true},
@@ -464,6 +466,10 @@ func TestIsCoordinate(t *testing.T) {
 		{`19°51'22"N 99°49'0"E`, true},
 		{"19.856, 99.817", true},
 		{"-33.857, 151.215", true},
+		{"849VCWC8+R9", true},
+
+		// Short plus codes need a locality to resolve
+		{"CWC8+R9", false},
 
 		// Should not be coordinates
 		{"Chiang Rai, Thailand", false},
@@ -494,6 +500,7 @@ func TestDetectFormat(t *testing.T) {
 		{`19°51'22"N 99°49'0"E`, FormatDMS},
 		{"19.856, 99.817", FormatDecimal},
 		{"-33.857 151.215", FormatDecimal},
+		{"849vcwc8+r9", FormatPlusCode},
 		{"Chiang Rai", FormatUnknown},
 		{"", FormatUnknown},
 	}
@@ -616,6 +623,7 @@ func TestFormat_String(t *testing.T) {
 		{FormatDMS, "dms"},
 		{FormatMGRS, "mgrs"},
 		{FormatUTM, "utm"},
+		{FormatPlusCode, "pluscode"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,286 @@
+package coords
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// Open Location Code (plus code) constants, following the reference
+// specification at https://github.com/google/open-location-code
+const (
+	plusCodeAlphabet  = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator = '+'
+	plusCodePadding   = '0'
+
+	// plusCodeSeparatorPos is the number of digits before the separator in a full code
+	plusCodeSeparatorPos = 8
+
+	// plusCodePairLen is the number of digits encoded as lat/lon pairs
+	plusCodePairLen = 10
+
+	// plusCodeGridLen is the maximum number of grid refinement digits
+	plusCodeGridLen = 5
+
+	plusCodeGridRows = 5
+	plusCodeGridCols = 4
+
+	// MaxPlusCodeLength is the longest supported code (digits, excluding the separator)
+	MaxPlusCodeLength = plusCodePairLen + plusCodeGridLen
+
+	// DefaultPlusCodeLength gives ~14m x 14m cells, the length shown by most map apps
+	DefaultPlusCodeLength = 10
+
+	// Integer units per degree at the finest precision
+	plusCodeLatUnits = 8000 * 3125 // 20^3 * 5^5
+	plusCodeLonUnits = 8000 * 1024 // 20^3 * 4^5
+)
+
+// plusCodeRegex matches anything shaped like a full or short plus code.
+// Structural rules are checked separately by validatePlusCode.
+var plusCodeRegex = regexp.MustCompile(`(?i)^[23456789CFGHJMPQRVWX0]{2,8}\+[23456789CFGHJMPQRVWX]*$`)
+
+// PlusCodeArea is the area covered by a plus code
+type PlusCodeArea struct {
+	Code   string          `json:"code"`
+	Center geo.Location    `json:"center"`
+	BBox   geo.BoundingBox `json:"bbox"`
+	Length int             `json:"length"` // number of digits, excluding padding
+}
+
+// EncodePlusCode encodes a coordinate as a full plus code with the given
+// number of digits (2, 4, 6, 8, or 10-15).
+func EncodePlusCode(lat, lon float64, length int) (string, error) {
+	if length < 2 || length > MaxPlusCodeLength || (length < plusCodePairLen && length%2 == 1) {
+		return "", fmt.Errorf("plus code length must be 2, 4, 6, 8 or 10-%d", MaxPlusCodeLength)
+	}
+	if err := geo.ValidateCoords(lat, lon); err != nil {
+		return "", err
+	}
+
+	latVal := int64(math.Floor(math.Round((lat+90)*plusCodeLatUnits*1e6) / 1e6))
+	lonVal := int64(math.Floor(math.Round((lon+180)*plusCodeLonUnits*1e6) / 1e6))
+
+	// The north pole belongs to the cell below it; 180 wraps to -180
+	if latVal >= 180*plusCodeLatUnits {
+		latVal = 180*plusCodeLatUnits - 1
+	}
+	lonVal %= 360 * plusCodeLonUnits
+
+	digits := make([]byte, MaxPlusCodeLength)
+
+	// Grid digits, least significant first
+	for i := MaxPlusCodeLength - 1; i >= plusCodePairLen; i-- {
+		row := latVal % plusCodeGridRows
+		col := lonVal % plusCodeGridCols
+		digits[i] = plusCodeAlphabet[row*plusCodeGridCols+col]
+		latVal /= plusCodeGridRows
+		lonVal /= plusCodeGridCols
+	}
+
+	// Pair digits, alternating latitude and longitude
+	for i := plusCodePairLen - 2; i >= 0; i -= 2 {
+		digits[i] = plusCodeAlphabet[latVal%20]
+		digits[i+1] = plusCodeAlphabet[lonVal%20]
+		latVal /= 20
+		lonVal /= 20
+	}
+
+	var code strings.Builder
+	for i := 0; i < length; i++ {
+		if i == plusCodeSeparatorPos {
+			code.WriteByte(plusCodeSeparator)
+		}
+		code.WriteByte(digits[i])
+	}
+	for i := length; i < plusCodeSeparatorPos; i++ {
+		code.WriteByte(plusCodePadding)
+	}
+	if length <= plusCodeSeparatorPos {
+		code.WriteByte(plusCodeSeparator)
+	}
+	return code.String(), nil
+}
+
+// DecodePlusCode returns the area covered by a full plus code
+func DecodePlusCode(code string) (*PlusCodeArea, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !IsFullPlusCode(code) {
+		return nil, fmt.Errorf("invalid full plus code: %q", code)
+	}
+
+	digits := strings.TrimRight(strings.Replace(code, string(plusCodeSeparator), "", 1), string(plusCodePadding))
+	if len(digits) > MaxPlusCodeLength {
+		digits = digits[:MaxPlusCodeLength]
+	}
+
+	// Accumulate in integer units of the finest precision to avoid rounding drift
+	var latVal, lonVal int64
+	latPlace := int64(20 * 20 * 20 * 20 * 3125)
+	lonPlace := int64(20 * 20 * 20 * 20 * 1024)
+	latRes, lonRes := latPlace, lonPlace
+
+	for i := 0; i < len(digits); i++ {
+		d := int64(strings.IndexByte(plusCodeAlphabet, digits[i]))
+		switch {
+		case i < plusCodePairLen && i%2 == 0:
+			if i > 0 {
+				latPlace /= 20
+				lonPlace /= 20
+			}
+			latVal += d * latPlace
+			latRes = latPlace
+		case i < plusCodePairLen:
+			lonVal += d * lonPlace
+			lonRes = lonPlace
+		default:
+			latPlace /= plusCodeGridRows
+			lonPlace /= plusCodeGridCols
+			latVal += (d / plusCodeGridCols) * latPlace
+			lonVal += (d % plusCodeGridCols) * lonPlace
+			latRes, lonRes = latPlace, lonPlace
+		}
+	}
+
+	bbox := geo.BoundingBox{
+		MinLat: float64(latVal)/plusCodeLatUnits - 90,
+		MinLon: float64(lonVal)/plusCodeLonUnits - 180,
+		MaxLat: float64(latVal+latRes)/plusCodeLatUnits - 90,
+		MaxLon: float64(lonVal+lonRes)/plusCodeLonUnits - 180,
+	}
+	return &PlusCodeArea{
+		Code:   code,
+		Center: bbox.Center(),
+		BBox:   bbox,
+		Length: len(digits),
+	}, nil
+}
+
+// IsFullPlusCode reports whether code is a valid full plus code
+func IsFullPlusCode(code string) bool {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !validatePlusCode(code) || strings.IndexByte(code, plusCodeSeparator) != plusCodeSeparatorPos {
+		return false
+	}
+	// The first digits must encode a latitude below 90 and longitude below 180
+	if strings.IndexByte(plusCodeAlphabet, code[0])*20 >= 180 {
+		return false
+	}
+	if len(code) > 1 && code[1] != plusCodePadding && strings.IndexByte(plusCodeAlphabet, code[1])*20 >= 360 {
+		return false
+	}
+	return true
+}
+
+// IsShortPlusCode reports whether code is a valid short plus code, such as
+// "CWC8+R9", which needs a nearby reference location to be resolved
+func IsShortPlusCode(code string) bool {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	sep := strings.IndexByte(code, plusCodeSeparator)
+	return validatePlusCode(code) && sep < plusCodeSeparatorPos && !strings.ContainsRune(code, plusCodePadding)
+}
+
+// validatePlusCode checks the structural rules shared by full and short codes
+func validatePlusCode(code string) bool {
+	if !plusCodeRegex.MatchString(code) || strings.Count(code, string(plusCodeSeparator)) != 1 {
+		return false
+	}
+	sep := strings.IndexByte(code, plusCodeSeparator)
+	if sep%2 == 1 || len(code)-sep-1 == 1 {
+		return false
+	}
+
+	if pad := strings.IndexByte(code, plusCodePadding); pad >= 0 {
+		// Padding must be an even run right before the separator, with no
+		// digits after it
+		if pad == 0 || pad%2 == 1 || sep != plusCodeSeparatorPos || sep != len(code)-1 {
+			return false
+		}
+		if strings.Trim(code[pad:sep], string(plusCodePadding)) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// RecoverPlusCode expands a short plus code to the nearest full code
+// around a reference location, as done for codes written with a locality
+// (e.g. "CWC8+R9 Mountain View"). Full codes are returned unchanged.
+func RecoverPlusCode(short string, refLat, refLon float64) (string, error) {
+	short = strings.ToUpper(strings.TrimSpace(short))
+	if IsFullPlusCode(short) {
+		return short, nil
+	}
+	if !IsShortPlusCode(short) {
+		return "", fmt.Errorf("invalid short plus code: %q", short)
+	}
+	if err := geo.ValidateCoords(refLat, refLon); err != nil {
+		return "", fmt.Errorf("invalid reference coordinates: %w", err)
+	}
+
+	padLen := plusCodeSeparatorPos - strings.IndexByte(short, plusCodeSeparator)
+	resolution := math.Pow(20, float64(2-padLen/2))
+	half := resolution / 2
+
+	prefix, err := EncodePlusCode(refLat, refLon, plusCodePairLen)
+	if err != nil {
+		return "", err
+	}
+
+	area, err := DecodePlusCode(prefix[:padLen] + short)
+	if err != nil {
+		return "", err
+	}
+
+	// The prefix came from the reference cell; the intended cell may be the
+	// neighboring one if the reference lies near a cell edge
+	lat, lon := area.Center.Latitude, area.Center.Longitude
+	if refLat+half < lat && lat-resolution >= -90 {
+		lat -= resolution
+	} else if refLat-half > lat && lat+resolution <= 90 {
+		lat += resolution
+	}
+	if refLon+half < lon {
+		lon -= resolution
+	} else if refLon-half > lon {
+		lon += resolution
+	}
+	for lon >= 180 {
+		lon -= 360
+	}
+	for lon < -180 {
+		lon += 360
+	}
+
+	return EncodePlusCode(lat, lon, area.Length)
+}
+
+// SplitShortPlusCode splits input such as "CWC8+R9 Mountain View, CA" into
+// the short code and the locality used to resolve it
+func SplitShortPlusCode(input string) (code, locality string, ok bool) {
+	fields := strings.Fields(strings.TrimSpace(input))
+	if len(fields) < 2 || !IsShortPlusCode(fields[0]) {
+		return "", "", false
+	}
+	locality = strings.TrimLeft(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), fields[0])), ", ")
+	if locality == "" {
+		return "", "", false
+	}
+	return strings.ToUpper(fields[0]), locality, true
+}
+
+// ParsePlusCode parses a full plus code, returning the center of its area
+func ParsePlusCode(input string) (*ParseResult, error) {
+	area, err := DecodePlusCode(input)
+	if err != nil {
+		return nil, err
+	}
+	return &ParseResult{
+		Location: area.Center,
+		Format:   FormatPlusCode,
+		Original: area.Code,
+	}, nil
+}
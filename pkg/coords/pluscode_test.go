@@ -0,0 +1,136 @@
+package coords
+
+import "testing"
+
+func TestEncodePlusCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		lat    float64
+		lon    float64
+		length int
+		want   string
+	}{
+		{name: "Padded", lat: 20.375, lon: 2.775, length: 6, want: "7FG49Q00+"},
+		{name: "Standard", lat: 20.3700625, lon: 2.78221875, length: 10, want: "7FG49QCJ+2V"},
+		{name: "Grid digits", lat: 47.365590, lon: 8.524997, length: 11, want: "8FVC9G8F+6XQ"},
+		{name: "North pole", lat: 90, lon: 1, length: 4, want: "CFX30000+"},
+		{name: "Antimeridian wraps", lat: 1, lon: 180, length: 4, want: "62H20000+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodePlusCode(tt.lat, tt.lon, tt.length)
+			if err != nil {
+				t.Fatalf("EncodePlusCode() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EncodePlusCode(%f, %f, %d) = %s, want %s", tt.lat, tt.lon, tt.length, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := EncodePlusCode(0, 0, 7); err == nil {
+		t.Error("expected error for odd length below 10")
+	}
+}
+
+func TestDecodePlusCode(t *testing.T) {
+	area, err := DecodePlusCode("7FG49Q00+")
+	if err != nil {
+		t.Fatalf("DecodePlusCode() error: %v", err)
+	}
+	if !almostEqual(area.BBox.MinLat, 20.35, 1e-9) || !almostEqual(area.BBox.MaxLat, 20.4, 1e-9) ||
+		!almostEqual(area.BBox.MinLon, 2.75, 1e-9) || !almostEqual(area.BBox.MaxLon, 2.8, 1e-9) {
+		t.Errorf("unexpected bbox %+v", area.BBox)
+	}
+
+	// Round trip at every supported length
+	for _, length := range []int{2, 4, 6, 8, 10, 11, 12, 13, 14, 15} {
+		code, err := EncodePlusCode(-33.857, 151.215, length)
+		if err != nil {
+			t.Fatalf("EncodePlusCode() error: %v", err)
+		}
+		area, err := DecodePlusCode(code)
+		if err != nil {
+			t.Fatalf("DecodePlusCode(%s) error: %v", code, err)
+		}
+		if area.Length != length {
+			t.Errorf("DecodePlusCode(%s) length = %d, want %d", code, area.Length, length)
+		}
+		if area.BBox.MinLat > -33.857 || area.BBox.MaxLat <= -33.857 || area.BBox.MinLon > 151.215 || area.BBox.MaxLon <= 151.215 {
+			t.Errorf("DecodePlusCode(%s) bbox %+v does not contain the point", code, area.BBox)
+		}
+	}
+}
+
+func TestPlusCodeValidity(t *testing.T) {
+	tests := []struct {
+		code  string
+		full  bool
+		short bool
+	}{
+		{"849VCWC8+R9", true, false},
+		{"849vcwc8+r9", true, false},
+		{"8FVC0000+", true, false},
+		{"CWC8+R9", false, true},
+		{"WC8+R9", false, false},     // odd separator position
+		{"849VCWC8+R", false, false}, // single digit after separator
+		{"8FVC00+", false, false},    // padding needs the separator at 8
+		{"8F0C0000+", false, false},  // digits after padding
+		{"8FVC0000+9Q", false, false},
+		{"X49VCWC8+R9", false, false}, // latitude out of range
+		{"849VCWC8R9", false, false},
+		{"Main Street", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := IsFullPlusCode(tt.code); got != tt.full {
+				t.Errorf("IsFullPlusCode(%q) = %v, want %v", tt.code, got, tt.full)
+			}
+			if got := IsShortPlusCode(tt.code); got != tt.short {
+				t.Errorf("IsShortPlusCode(%q) = %v, want %v", tt.code, got, tt.short)
+			}
+		})
+	}
+}
+
+func TestRecoverPlusCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		short  string
+		refLat float64
+		refLon float64
+		want   string
+	}{
+		{name: "Same cell", short: "9G8F+6X", refLat: 47.4, refLon: 8.6, want: "8FVC9G8F+6X"},
+		{name: "Short two digits", short: "CJ+2VX", refLat: 51.3708675, refLon: -1.217765625, want: "9C3W9QCJ+2VX"},
+		{name: "Neighboring cell", short: "9G8F+6X", refLat: 48.9, refLon: 8.5, want: "8FXC9G8F+6X"},
+		{name: "Full code unchanged", short: "849VCWC8+R9", refLat: 0, refLon: 0, want: "849VCWC8+R9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RecoverPlusCode(tt.short, tt.refLat, tt.refLon)
+			if err != nil {
+				t.Fatalf("RecoverPlusCode() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RecoverPlusCode(%s) = %s, want %s", tt.short, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitShortPlusCode(t *testing.T) {
+	code, locality, ok := SplitShortPlusCode("cwc8+r9 Mountain View, CA")
+	if !ok || code != "CWC8+R9" || locality != "Mountain View, CA" {
+		t.Errorf("SplitShortPlusCode() = %q, %q, %v", code, locality, ok)
+	}
+	if _, _, ok := SplitShortPlusCode("CWC8+R9"); ok {
+		t.Error("expected no split without a locality")
+	}
+	if _, _, ok := SplitShortPlusCode("10 Downing Street"); ok {
+		t.Error("expected no split for an address")
+	}
+}
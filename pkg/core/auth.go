@@ -1,24 +1,22 @@
 package core
 
 import (
+	"crypto/sha256"
 	"crypto/subtle"
+	"fmt"
 	"strings"
 	"time"
 )
 
 // SecureCompareString performs constant-time string comparison to prevent timing attacks
 func SecureCompareString(a, b string) bool {
-	// Convert strings to byte slices for constant-time comparison
-	aBytes := []byte(a)
-	bBytes := []byte(b)
-
-	// If lengths are different, the strings are not equal
-	if len(aBytes) != len(bBytes) {
-		return false
-	}
+	// Compare fixed-length digests so the time taken does not reveal the
+	// length of the expected value either
+	aSum := sha256.Sum256([]byte(a))
+	bSum := sha256.Sum256([]byte(b))
 
 	// Use constant-time comparison
-	return subtle.ConstantTimeCompare(aBytes, bBytes) == 1
+	return subtle.ConstantTimeCompare(aSum[:], bSum[:]) == 1
 }
 
 // ValidateAuthToken validates an authentication token with security best practices
@@ -51,15 +49,105 @@ func ValidateAuthToken(token string) error {
 	return nil
 }
 
+// BearerToken is an accepted bearer token and the window in which it is
+// valid. A zero time leaves the window open at that end, so a new token can
+// be added before clients switch to it and the old one retired afterwards.
+type BearerToken struct {
+	Token     string    `json:"token"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	Role      string    `json:"role,omitempty"` // access role; empty for unrestricted access
+}
+
+// ActiveAt reports whether the token is valid at the given time
+func (b BearerToken) ActiveAt(now time.Time) bool {
+	if !b.NotBefore.IsZero() && now.Before(b.NotBefore) {
+		return false
+	}
+	return b.NotAfter.IsZero() || now.Before(b.NotAfter)
+}
+
+// ParseBearerTokens parses a comma-separated list of bearer tokens, each
+// optionally followed by a validity window of RFC 3339 times, either of
+// which may be left out, and by a #role:
+//
+//	new-token@2025-01-01T00:00:00Z..,old-token@..2025-01-08T00:00:00Z,ro-token#reader
+func ParseBearerTokens(spec string) ([]BearerToken, error) {
+	var tokens []BearerToken
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var role string
+		if i := strings.LastIndex(entry, "#"); i >= 0 {
+			entry, role = entry[:i], entry[i+1:]
+			if role == "" {
+				return nil, NewError(ErrInvalidParameter, "Bearer token role cannot be empty").
+					WithGuidance("Use token#role or leave out the # for unrestricted access")
+			}
+		}
+		token, window, hasWindow := strings.Cut(entry, "@")
+		if token == "" {
+			return nil, NewError(ErrInvalidParameter, "Bearer token cannot be empty").
+				WithGuidance("Use token or token@from..until")
+		}
+		bearer := BearerToken{Token: token, Role: role}
+		if hasWindow {
+			from, until, ok := strings.Cut(window, "..")
+			if !ok {
+				return nil, NewError(ErrInvalidParameter, fmt.Sprintf("Invalid validity window %q", window)).
+					WithGuidance("Use token@from..until with RFC 3339 times; either end may be omitted")
+			}
+			var err error
+			if bearer.NotBefore, err = parseTokenTime(from); err != nil {
+				return nil, err
+			}
+			if bearer.NotAfter, err = parseTokenTime(until); err != nil {
+				return nil, err
+			}
+			if !bearer.NotBefore.IsZero() && !bearer.NotAfter.IsZero() && !bearer.NotAfter.After(bearer.NotBefore) {
+				return nil, NewError(ErrInvalidParameter, fmt.Sprintf("Validity window %q ends before it starts", window))
+			}
+		}
+		tokens = append(tokens, bearer)
+	}
+	return tokens, nil
+}
+
+// parseTokenTime parses one end of a validity window
+func parseTokenTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, NewError(ErrInvalidParameter, fmt.Sprintf("Invalid time %q in validity window", value)).
+			WithGuidance("Use RFC 3339 times such as 2025-01-01T00:00:00Z")
+	}
+	return t, nil
+}
+
 // AuthResult represents the result of authentication
 type AuthResult struct {
 	Authorized bool
 	Error      string
+	Reason     string // short failure category for metrics
+	Role       string // role of the matched bearer token
 	Duration   time.Duration
 }
 
 // AuthenticateBearer performs secure bearer token authentication
 func AuthenticateBearer(authHeader, expectedToken string) AuthResult {
+	return AuthenticateBearerTokens(authHeader, []BearerToken{{Token: expectedToken}}, time.Now())
+}
+
+// AuthenticateBearerTokens performs secure bearer token authentication
+// against several tokens, as during rotation. Every token is compared,
+// whether or not it is active, so the time taken does not reveal which
+// token matched.
+func AuthenticateBearerTokens(authHeader string, tokens []BearerToken, now time.Time) AuthResult {
 	start := time.Now()
 	defer func() {
 		// Add small delay to prevent timing attacks
@@ -70,6 +158,7 @@ func AuthenticateBearer(authHeader, expectedToken string) AuthResult {
 		return AuthResult{
 			Authorized: false,
 			Error:      "Missing Authorization header",
+			Reason:     "missing",
 			Duration:   time.Since(start),
 		}
 	}
@@ -80,23 +169,43 @@ func AuthenticateBearer(authHeader, expectedToken string) AuthResult {
 		return AuthResult{
 			Authorized: false,
 			Error:      "Invalid Authorization header format",
+			Reason:     "malformed",
 			Duration:   time.Since(start),
 		}
 	}
 
 	token := parts[1]
 
-	// Secure comparison
-	if !SecureCompareString(token, expectedToken) {
+	// Secure comparison against every token
+	matched, active, role := false, false, ""
+	for _, expected := range tokens {
+		if SecureCompareString(token, expected.Token) {
+			matched = true
+			if !active && expected.ActiveAt(now) {
+				active, role = true, expected.Role
+			}
+		}
+	}
+	if !matched {
 		return AuthResult{
 			Authorized: false,
 			Error:      "Invalid bearer token",
+			Reason:     "invalid",
+			Duration:   time.Since(start),
+		}
+	}
+	if !active {
+		return AuthResult{
+			Authorized: false,
+			Error:      "Bearer token is not valid at this time",
+			Reason:     "inactive",
 			Duration:   time.Since(start),
 		}
 	}
 
 	return AuthResult{
 		Authorized: true,
+		Role:       role,
 		Duration:   time.Since(start),
 	}
 }
@@ -113,6 +222,7 @@ func AuthenticateBasic(username, password, expectedCredentials string) AuthResul
 		return AuthResult{
 			Authorized: false,
 			Error:      "Missing basic auth credentials",
+			Reason:     "missing",
 			Duration:   time.Since(start),
 		}
 	}
@@ -125,6 +235,7 @@ func AuthenticateBasic(username, password, expectedCredentials string) AuthResul
 		return AuthResult{
 			Authorized: false,
 			Error:      "Invalid basic auth credentials",
+			Reason:     "invalid",
 			Duration:   time.Since(start),
 		}
 	}
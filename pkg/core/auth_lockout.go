@@ -0,0 +1,136 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// maxLockoutClients bounds the number of clients whose failures are tracked
+const maxLockoutClients = 10000
+
+// AuthLockout counts authentication failures per client and locks out
+// clients that fail too often within a window, slowing brute-force guessing
+// of tokens. A nil AuthLockout never locks anyone out.
+type AuthLockout struct {
+	maxFailures int
+	window      time.Duration
+	duration    time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*authFailures
+}
+
+// authFailures tracks the recent failures of one client
+type authFailures struct {
+	count       int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// NewAuthLockout locks a client out for duration after maxFailures failures
+// within window. It returns nil, disabling lockout, if maxFailures is not
+// positive.
+func NewAuthLockout(maxFailures int, window, duration time.Duration) *AuthLockout {
+	if maxFailures <= 0 || window <= 0 || duration <= 0 {
+		return nil
+	}
+	return &AuthLockout{
+		maxFailures: maxFailures,
+		window:      window,
+		duration:    duration,
+		clients:     make(map[string]*authFailures),
+	}
+}
+
+// LockedUntil returns when a client's lockout ends, or the zero time if it
+// is not locked out
+func (l *AuthLockout) LockedUntil(client string, now time.Time) time.Time {
+	if l == nil {
+		return time.Time{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if f, ok := l.clients[client]; ok && now.Before(f.lockedUntil) {
+		return f.lockedUntil
+	}
+	return time.Time{}
+}
+
+// Failure records a failed attempt and reports whether it locked the
+// client out
+func (l *AuthLockout) Failure(client string, now time.Time) bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, ok := l.clients[client]
+	if !ok {
+		if len(l.clients) >= maxLockoutClients {
+			l.prune(now)
+		}
+		f = &authFailures{}
+		l.clients[client] = f
+	}
+	if now.Before(f.lockedUntil) {
+		return false
+	}
+	if f.count == 0 || now.Sub(f.first) > l.window {
+		f.count, f.first = 0, now
+	}
+
+	f.count++
+	if f.count < l.maxFailures {
+		return false
+	}
+	f.count = 0
+	f.lockedUntil = now.Add(l.duration)
+	return true
+}
+
+// Success clears a client's failures after it authenticates
+func (l *AuthLockout) Success(client string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.clients, client)
+}
+
+// Locked returns the number of clients currently locked out
+func (l *AuthLockout) Locked(now time.Time) int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	locked := 0
+	for _, f := range l.clients {
+		if now.Before(f.lockedUntil) {
+			locked++
+		}
+	}
+	return locked
+}
+
+// prune forgets clients that are neither locked out nor within a failure
+// window, and if that is not enough, clients that are not locked out
+func (l *AuthLockout) prune(now time.Time) {
+	for client, f := range l.clients {
+		if !now.Before(f.lockedUntil) && now.Sub(f.first) > l.window {
+			delete(l.clients, client)
+		}
+	}
+	for client, f := range l.clients {
+		if len(l.clients) < maxLockoutClients {
+			return
+		}
+		if !now.Before(f.lockedUntil) {
+			delete(l.clients, client)
+		}
+	}
+}
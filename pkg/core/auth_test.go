@@ -1,6 +1,9 @@
 package core
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 // Test ValidateAuthToken with a strong token
 func TestValidateAuthTokenStrong(t *testing.T) {
@@ -71,3 +74,105 @@ func TestAuthenticateBasicMismatch(t *testing.T) {
 		t.Fatalf("expected invalid credentials, got %+v", result)
 	}
 }
+
+// Test ParseBearerTokens with validity windows and roles
+func TestParseBearerTokens(t *testing.T) {
+	tokens, err := ParseBearerTokens("current, next@2025-01-01T00:00:00Z.., old@..2025-01-08T00:00:00Z, viewer#reader")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 4 || tokens[3].Token != "viewer" || tokens[3].Role != "reader" || tokens[0].Role != "" {
+		t.Fatalf("unexpected roles %+v", tokens)
+	}
+	if tokens[0].Token != "current" || tokens[1].Token != "next" || tokens[2].Token != "old" {
+		t.Fatalf("unexpected tokens %+v", tokens)
+	}
+	if !tokens[0].NotBefore.IsZero() || !tokens[0].NotAfter.IsZero() {
+		t.Errorf("expected an open window, got %+v", tokens[0])
+	}
+	if tokens[1].NotBefore.Year() != 2025 || !tokens[1].NotAfter.IsZero() {
+		t.Errorf("expected a start only, got %+v", tokens[1])
+	}
+	if !tokens[2].NotBefore.IsZero() || tokens[2].NotAfter.Day() != 8 {
+		t.Errorf("expected an end only, got %+v", tokens[2])
+	}
+
+	for _, spec := range []string{
+		"@..",
+		"token#",
+		"token@2025-01-01",
+		"token@yesterday..",
+		"token@2025-01-08T00:00:00Z..2025-01-01T00:00:00Z",
+	} {
+		if _, err := ParseBearerTokens(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+// Test AuthenticateBearerTokens across a rotation
+func TestAuthenticateBearerTokensRotation(t *testing.T) {
+	switchover := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tokens := []BearerToken{
+		{Token: "oldtokensecret", NotAfter: switchover.Add(time.Hour)},
+		{Token: "newtokensecret", NotBefore: switchover},
+	}
+
+	tests := []struct {
+		token  string
+		at     time.Time
+		reason string
+	}{
+		{"oldtokensecret", switchover.Add(-time.Hour), ""},
+		{"newtokensecret", switchover.Add(-time.Hour), "inactive"},
+		{"oldtokensecret", switchover.Add(30 * time.Minute), ""},
+		{"newtokensecret", switchover.Add(30 * time.Minute), ""},
+		{"oldtokensecret", switchover.Add(2 * time.Hour), "inactive"},
+		{"newtokensecret", switchover.Add(2 * time.Hour), ""},
+		{"othertokensecret", switchover, "invalid"},
+	}
+	for _, tt := range tests {
+		result := AuthenticateBearerTokens("Bearer "+tt.token, tokens, tt.at)
+		if result.Authorized != (tt.reason == "") || result.Reason != tt.reason {
+			t.Errorf("%s at %s: got %+v, want reason %q", tt.token, tt.at, result, tt.reason)
+		}
+	}
+}
+
+// Test AuthLockout locks out after repeated failures and expires
+func TestAuthLockout(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	lockout := NewAuthLockout(3, time.Minute, 10*time.Minute)
+
+	// Failures spread over more than the window do not lock out
+	lockout.Failure("10.0.0.1", now)
+	lockout.Failure("10.0.0.1", now.Add(30*time.Second))
+	if lockout.Failure("10.0.0.1", now.Add(2*time.Minute)) {
+		t.Fatal("failures outside the window should not lock out")
+	}
+
+	// A success clears the count
+	lockout.Success("10.0.0.1")
+	lockout.Failure("10.0.0.1", now)
+	lockout.Failure("10.0.0.1", now)
+	if !lockout.Failure("10.0.0.1", now) {
+		t.Fatal("expected the third failure to lock out")
+	}
+	if until := lockout.LockedUntil("10.0.0.1", now.Add(time.Minute)); !until.Equal(now.Add(10 * time.Minute)) {
+		t.Errorf("expected lockout until %s, got %s", now.Add(10*time.Minute), until)
+	}
+	if !lockout.LockedUntil("10.0.0.2", now).IsZero() {
+		t.Error("other clients should not be locked out")
+	}
+	if got := lockout.Locked(now); got != 1 {
+		t.Errorf("expected 1 locked client, got %d", got)
+	}
+	if !lockout.LockedUntil("10.0.0.1", now.Add(10*time.Minute)).IsZero() {
+		t.Error("lockout should expire")
+	}
+
+	disabled := NewAuthLockout(0, time.Minute, time.Minute)
+	if disabled != nil || disabled.Failure("10.0.0.1", now) || !disabled.LockedUntil("10.0.0.1", now).IsZero() {
+		t.Error("a disabled lockout should never lock out")
+	}
+}
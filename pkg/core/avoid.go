@@ -0,0 +1,254 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// MaxAvoidAreas caps how many polygons a route may avoid
+	MaxAvoidAreas = 10
+
+	// MaxAvoidAreaPoints caps the size of each avoid polygon
+	MaxAvoidAreaPoints = 500
+
+	// maxAvoidAttempts caps the extra OSRM requests made to honor avoid areas
+	maxAvoidAttempts = 6
+
+	// minDetourBuffer is the minimum distance in meters between an avoid
+	// area's bounding box and an inserted detour waypoint
+	minDetourBuffer = 200.0
+
+	// AvoidMethodAlternatives means an OSRM alternative already avoided the areas
+	AvoidMethodAlternatives = "osrm_alternatives"
+
+	// AvoidMethodWaypoints means detour waypoints were inserted
+	AvoidMethodWaypoints = "osrm_waypoints"
+)
+
+// AvoidAreasResult is a route chosen to avoid a set of polygons.
+// OSRM cannot exclude arbitrary areas, so avoidance is a heuristic: first
+// the alternatives are checked, then detour waypoints are inserted around
+// each area the route still crosses. Honored is false when no attempt
+// avoided every area, in which case Route is the attempt crossing the
+// fewest areas.
+type AvoidAreasResult struct {
+	Route    OSRMRoute      `json:"-"`
+	Honored  bool           `json:"honored"`
+	Method   string         `json:"method"`
+	Via      []geo.Location `json:"via,omitempty"`            // inserted detour waypoints
+	Violated []int          `json:"violated_areas,omitempty"` // indices of areas still crossed
+	Attempts int            `json:"attempts"`                 // OSRM requests made
+	Message  string         `json:"message,omitempty"`
+
+	// Closures are the reported closures on Route, whose penalties count
+	// against it when choosing between routes crossing as many areas
+	Closures       []Closure `json:"-"`
+	ClosurePenalty float64   `json:"-"` // in seconds
+}
+
+// ValidateAvoidAreas checks avoid polygons for size and coordinate validity
+func ValidateAvoidAreas(areas [][]geo.Location) error {
+	if len(areas) > MaxAvoidAreas {
+		return fmt.Errorf("at most %d avoid areas are supported", MaxAvoidAreas)
+	}
+	for i, area := range areas {
+		if len(area) < 3 || len(area) > MaxAvoidAreaPoints {
+			return fmt.Errorf("avoid area %d must have between 3 and %d points", i, MaxAvoidAreaPoints)
+		}
+		for _, p := range area {
+			if err := ValidateCoords(p.Latitude, p.Longitude); err != nil {
+				return fmt.Errorf("avoid area %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RouteAvoidingAreas finds a route from start to end ([lon, lat]) that does
+// not pass through any of the given polygons, using OSRM with the given options.
+// The route geometry is a polyline in options.Geometries, polyline or
+// polyline6. Routes are penalised for the closures in closures they pass,
+// unless it is nil.
+func RouteAvoidingAreas(ctx context.Context, start, end []float64, options OSRMOptions, areas [][]geo.Location, closures *ClosureStore) (*AvoidAreasResult, error) {
+	options.Overview = "full"
+	options.Alternatives = 2
+	precision := PolylinePrecision(options.Geometries)
+	closuresOn := func(route OSRMRoute) []Closure {
+		if closures == nil {
+			return nil
+		}
+		return closures.AlongRoute(osm.DecodePolylinePrecision(route.Geometry, precision))
+	}
+
+	result, err := GetRoute(ctx, [][]float64{start, end}, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Routes) == 0 {
+		return nil, NewError(ErrNoResults, "no routes found").
+			WithGuidance("No route could be calculated between these points. The locations may be inaccessible by the selected mode of transport")
+	}
+
+	best := &AvoidAreasResult{Method: AvoidMethodAlternatives, Attempts: 1}
+	bestSet := false
+	for _, route := range result.Routes {
+		violated := areasCrossed(route, areas, precision)
+		hits := closuresOn(route)
+		penalty := ClosurePenalty(hits)
+		if !bestSet || betterAvoidance(route, violated, penalty, best) {
+			best.Route, best.Violated, bestSet = route, violated, true
+			best.Closures, best.ClosurePenalty = hits, penalty
+		}
+	}
+	if len(best.Violated) == 0 {
+		best.Honored = true
+		return best, nil
+	}
+
+	// Insert detour waypoints around the areas the best route still crosses
+	options.Alternatives = 0
+	startLoc := geo.Location{Latitude: start[1], Longitude: start[0]}
+	endLoc := geo.Location{Latitude: end[1], Longitude: end[0]}
+	vias := make(map[int]geo.Location)
+	tried := make(map[int]int)
+	current := best.Violated
+
+	for attempt := 0; attempt < maxAvoidAttempts && len(current) > 0; attempt++ {
+		area := current[0]
+		candidates := detourCandidates(areas[area], startLoc, endLoc)
+		if tried[area] >= len(candidates) {
+			break
+		}
+		vias[area] = candidates[tried[area]]
+		tried[area]++
+
+		via := orderedVias(vias, startLoc)
+		coords := make([][]float64, 0, len(via)+2)
+		coords = append(coords, start)
+		for _, v := range via {
+			coords = append(coords, []float64{v.Longitude, v.Latitude})
+		}
+		coords = append(coords, end)
+
+		best.Attempts++
+		detour, err := GetRoute(ctx, coords, options)
+		if err != nil || len(detour.Routes) == 0 {
+			continue
+		}
+
+		route := detour.Routes[0]
+		current = areasCrossed(route, areas, precision)
+		hits := closuresOn(route)
+		penalty := ClosurePenalty(hits)
+		if betterAvoidance(route, current, penalty, best) {
+			best.Route, best.Violated, best.Via, best.Method = route, current, via, AvoidMethodWaypoints
+			best.Closures, best.ClosurePenalty = hits, penalty
+		}
+	}
+
+	best.Honored = len(best.Violated) == 0
+	if !best.Honored {
+		best.Message = fmt.Sprintf("Could not find a route avoiding %d of %d areas; the returned route crosses them", len(best.Violated), len(areas))
+	}
+	return best, nil
+}
+
+// betterAvoidance reports whether a route crossing violated areas, with a
+// closure penalty in seconds, beats the current best
+func betterAvoidance(route OSRMRoute, violated []int, penalty float64, best *AvoidAreasResult) bool {
+	if len(violated) != len(best.Violated) {
+		return len(violated) < len(best.Violated)
+	}
+	return route.Duration+penalty < best.Route.Duration+best.ClosurePenalty
+}
+
+// areasCrossed returns the indices of areas a route passes through, in the
+// order the route first enters them. Its geometry is a polyline encoded with
+// precision decimals.
+func areasCrossed(route OSRMRoute, areas [][]geo.Location, precision int) []int {
+	points := osm.DecodePolylinePrecision(route.Geometry, precision)
+
+	type hit struct{ area, index int }
+	hits := make([]hit, 0)
+	for a, area := range areas {
+		ring := append(append([]geo.Location{}, area...), area[0])
+		for i, p := range points {
+			crossesEdge := i > 0 && pathsCross(points[i-1:i+1], ring)
+			if crossesEdge || geo.PointInPolygon(p.Latitude, p.Longitude, area) {
+				hits = append(hits, hit{area: a, index: i})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].index < hits[j].index
+	})
+	crossed := make([]int, len(hits))
+	for i, h := range hits {
+		crossed[i] = h.area
+	}
+	return crossed
+}
+
+// detourCandidates returns points just outside an area's bounding box.
+// Points whose straight-line detour clears the area come first, each group
+// ordered by how little it lengthens the straight line from start to end.
+func detourCandidates(area []geo.Location, start, end geo.Location) []geo.Location {
+	bbox := geo.NewBoundingBox()
+	for _, p := range area {
+		bbox.ExtendWithPoint(p.Latitude, p.Longitude)
+	}
+
+	diagonal := geo.HaversineDistance(bbox.MinLat, bbox.MinLon, bbox.MaxLat, bbox.MaxLon)
+	bbox.Buffer(math.Max(minDetourBuffer, diagonal*0.1))
+
+	midLat := (bbox.MinLat + bbox.MaxLat) / 2
+	midLon := (bbox.MinLon + bbox.MaxLon) / 2
+	candidates := []geo.Location{
+		{Latitude: bbox.MaxLat, Longitude: midLon},
+		{Latitude: bbox.MinLat, Longitude: midLon},
+		{Latitude: midLat, Longitude: bbox.MaxLon},
+		{Latitude: midLat, Longitude: bbox.MinLon},
+		{Latitude: bbox.MaxLat, Longitude: bbox.MaxLon},
+		{Latitude: bbox.MaxLat, Longitude: bbox.MinLon},
+		{Latitude: bbox.MinLat, Longitude: bbox.MaxLon},
+		{Latitude: bbox.MinLat, Longitude: bbox.MinLon},
+	}
+
+	ring := append(append([]geo.Location{}, area...), area[0])
+	clears := func(p geo.Location) bool {
+		return !pathsCross([]geo.Location{start, p, end}, ring)
+	}
+	detour := func(p geo.Location) float64 {
+		return geo.HaversineDistance(start.Latitude, start.Longitude, p.Latitude, p.Longitude) +
+			geo.HaversineDistance(p.Latitude, p.Longitude, end.Latitude, end.Longitude)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ci, cj := clears(candidates[i]), clears(candidates[j])
+		if ci != cj {
+			return ci
+		}
+		return detour(candidates[i]) < detour(candidates[j])
+	})
+	return candidates
+}
+
+// orderedVias orders detour waypoints by distance from the start
+func orderedVias(vias map[int]geo.Location, start geo.Location) []geo.Location {
+	ordered := make([]geo.Location, 0, len(vias))
+	for _, v := range vias {
+		ordered = append(ordered, v)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return geo.HaversineDistance(start.Latitude, start.Longitude, ordered[i].Latitude, ordered[i].Longitude) <
+			geo.HaversineDistance(start.Latitude, start.Longitude, ordered[j].Latitude, ordered[j].Longitude)
+	})
+	return ordered
+}
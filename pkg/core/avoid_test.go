@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// avoidArea is a small square straddling the direct route along the equator
+var avoidArea = []geo.Location{
+	{Latitude: -0.002, Longitude: 0.009},
+	{Latitude: -0.002, Longitude: 0.011},
+	{Latitude: 0.002, Longitude: 0.011},
+	{Latitude: 0.002, Longitude: 0.009},
+}
+
+// newAvoidMockServer returns the direct route for two coordinates and a
+// route through the requested waypoints otherwise, encoded in the requested
+// geometries format
+func newAvoidMockServer(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		path := strings.TrimPrefix(r.URL.Path, "/route/v1/car/")
+		points := make([]geo.Location, 0)
+		for _, pair := range strings.Split(path, ";") {
+			var lon, lat float64
+			if _, err := fmt.Sscanf(pair, "%f,%f", &lon, &lat); err != nil {
+				t.Errorf("bad coordinate %q: %v", pair, err)
+			}
+			points = append(points, geo.Location{Latitude: lat, Longitude: lon})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"code":"Ok","routes":[{"distance":%d,"duration":%d,"geometry":%q,"legs":[]}],"waypoints":[]}`,
+			1000*len(points), 100*len(points), osm.EncodePolylinePrecision(points, PolylinePrecision(r.URL.Query().Get("geometries"))))
+	}))
+	return server, &count
+}
+
+func TestRouteAvoidingAreas(t *testing.T) {
+	resetRouteCache()
+	server, count := newAvoidMockServer(t)
+	defer server.Close()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+
+	result, err := RouteAvoidingAreas(context.Background(), []float64{0, 0}, []float64{0.02, 0}, options, [][]geo.Location{avoidArea}, nil)
+	if err != nil {
+		t.Fatalf("RouteAvoidingAreas() error: %v", err)
+	}
+	if !result.Honored || result.Method != AvoidMethodWaypoints {
+		t.Fatalf("expected avoidance via waypoints, got %+v", result)
+	}
+	if len(result.Via) != 1 || geo.PointInPolygon(result.Via[0].Latitude, result.Via[0].Longitude, avoidArea) {
+		t.Errorf("expected one waypoint outside the area, got %+v", result.Via)
+	}
+	if *count != 2 || result.Attempts != 2 {
+		t.Errorf("expected 2 OSRM requests, got %d (attempts %d)", *count, result.Attempts)
+	}
+}
+
+func TestRouteAvoidingAreasPolyline6(t *testing.T) {
+	resetRouteCache()
+	server, _ := newAvoidMockServer(t)
+	defer server.Close()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+	options.Geometries = "polyline6"
+
+	result, err := RouteAvoidingAreas(context.Background(), []float64{0, 0}, []float64{0.02, 0}, options, [][]geo.Location{avoidArea}, nil)
+	if err != nil {
+		t.Fatalf("RouteAvoidingAreas() error: %v", err)
+	}
+	if !result.Honored || result.Method != AvoidMethodWaypoints {
+		t.Errorf("expected the polyline6 route to be detected crossing the area, got %+v", result)
+	}
+}
+
+func TestRouteAvoidingAreasNotCrossed(t *testing.T) {
+	resetRouteCache()
+	server, count := newAvoidMockServer(t)
+	defer server.Close()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+
+	// The area lies north of the direct route
+	north := make([]geo.Location, len(avoidArea))
+	for i, p := range avoidArea {
+		north[i] = geo.Location{Latitude: p.Latitude + 0.01, Longitude: p.Longitude}
+	}
+
+	result, err := RouteAvoidingAreas(context.Background(), []float64{0, 0}, []float64{0.02, 0}, options, [][]geo.Location{north}, nil)
+	if err != nil {
+		t.Fatalf("RouteAvoidingAreas() error: %v", err)
+	}
+	if !result.Honored || result.Method != AvoidMethodAlternatives || *count != 1 {
+		t.Errorf("expected the direct route to be accepted, got %+v after %d requests", result, *count)
+	}
+}
+
+func TestValidateAvoidAreas(t *testing.T) {
+	if err := ValidateAvoidAreas([][]geo.Location{avoidArea}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateAvoidAreas([][]geo.Location{avoidArea[:2]}); err == nil {
+		t.Error("expected error for degenerate area")
+	}
+	if err := ValidateAvoidAreas([][]geo.Location{{{Latitude: 91}, {}, {}}}); err == nil {
+		t.Error("expected error for invalid coordinates")
+	}
+}
+
+func TestRouteAvoidingAreasReportsClosures(t *testing.T) {
+	resetRouteCache()
+	server, _ := newAvoidMockServer(t)
+	defer server.Close()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+
+	north := make([]geo.Location, len(avoidArea))
+	for i, p := range avoidArea {
+		north[i] = geo.Location{Latitude: p.Latitude + 0.01, Longitude: p.Longitude}
+	}
+	closures := NewClosureStore()
+	if _, err := closures.Add(Closure{Geometry: []geo.Location{{Latitude: 0, Longitude: 0.015}}, Radius: 50}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RouteAvoidingAreas(context.Background(), []float64{0, 0}, []float64{0.02, 0}, options, [][]geo.Location{north}, closures)
+	if err != nil {
+		t.Fatalf("RouteAvoidingAreas() error: %v", err)
+	}
+	if len(result.Closures) != 1 || result.ClosurePenalty != ClosedPenalty {
+		t.Errorf("expected the closure on the route to be reported, got %+v (penalty %.0f)", result.Closures, result.ClosurePenalty)
+	}
+}
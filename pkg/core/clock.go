@@ -0,0 +1,56 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// clock is the server clock. By default it reads wall-clock time; in
+// simulated mode it starts at a chosen time, for testing and training
+// scenarios that depend on opening hours, daylight or timetables.
+var clock struct {
+	mu        sync.RWMutex
+	simulated bool
+	start     time.Time // simulated time when the clock was set
+	setAt     time.Time // wall-clock time when the clock was set
+	frozen    bool
+}
+
+// Now returns the current time of the server clock
+func Now() time.Time {
+	clock.mu.RLock()
+	defer clock.mu.RUnlock()
+	if !clock.simulated {
+		return time.Now()
+	}
+	if clock.frozen {
+		return clock.start
+	}
+	return clock.start.Add(time.Since(clock.setAt))
+}
+
+// SetSimulatedClock sets the server clock to start. The clock then advances
+// in real time, or stays at start if frozen.
+func SetSimulatedClock(start time.Time, frozen bool) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.simulated = true
+	clock.start = start
+	clock.setAt = time.Now()
+	clock.frozen = frozen
+}
+
+// ResetClock returns the server clock to wall-clock time
+func ResetClock() {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.simulated = false
+	clock.frozen = false
+}
+
+// ClockSimulated reports whether the server clock is simulated
+func ClockSimulated() bool {
+	clock.mu.RLock()
+	defer clock.mu.RUnlock()
+	return clock.simulated
+}
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimulatedClock(t *testing.T) {
+	defer ResetClock()
+
+	start := time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+	SetSimulatedClock(start, true)
+	if !ClockSimulated() || !Now().Equal(start) {
+		t.Errorf("expected the frozen clock at %v, got %v", start, Now())
+	}
+
+	SetSimulatedClock(start, false)
+	if now := Now(); now.Before(start) || now.Sub(start) > time.Minute {
+		t.Errorf("expected the clock to run from %v, got %v", start, now)
+	}
+
+	ResetClock()
+	if ClockSimulated() || time.Since(Now()) > time.Minute {
+		t.Errorf("expected wall-clock time, got %v", Now())
+	}
+}
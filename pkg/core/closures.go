@@ -0,0 +1,416 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+const (
+	// DefaultClosureRadius is the buffer around closure geometry, in meters,
+	// within which a route is considered affected
+	DefaultClosureRadius = 25.0
+
+	// MaxClosureRadius caps the closure buffer
+	MaxClosureRadius = 5000.0
+
+	// ClosedPenalty is the routing penalty, in seconds, for passing through a
+	// full closure. It is large enough that any open alternative wins.
+	ClosedPenalty = 6 * 3600.0
+
+	// ClosureSeverityClosed marks a road that cannot be used
+	ClosureSeverityClosed = "closed"
+
+	// ClosureSeverityDelay marks a road that is usable with a delay
+	ClosureSeverityDelay = "delay"
+)
+
+// Closure is a temporary road closure or incident. OSM has no real-time
+// closure data, so closures are reported by clients or loaded from feeds.
+type Closure struct {
+	ID          string         `json:"id"`
+	Description string         `json:"description,omitempty"`
+	Severity    string         `json:"severity"`                  // closed or delay
+	Delay       float64        `json:"delay,omitempty"`           // seconds, for delay severity
+	Geometry    []geo.Location `json:"geometry"`                  // point, line or area outline
+	Area        bool           `json:"area,omitempty"`            // geometry is a polygon ring
+	Radius      float64        `json:"radius"`                    // buffer in meters
+	Source      string         `json:"source,omitempty"`          // manual or feed name
+	Start       *time.Time     `json:"start,omitempty"`           // not active before this time
+	End         *time.Time     `json:"end,omitempty"`             // expires at this time
+	ReportedAt  time.Time      `json:"reported_at"`               // when the closure was stored
+	Penalty     float64        `json:"penalty_seconds,omitempty"` // set when reported against a route
+}
+
+// ClosureStore holds closures in server state
+type ClosureStore struct {
+	mu       sync.Mutex
+	closures map[string]Closure
+	nextID   int
+	now      func() time.Time
+}
+
+// NewClosureStore creates an empty closure store
+func NewClosureStore() *ClosureStore {
+	return &ClosureStore{
+		closures: make(map[string]Closure),
+		now:      Now,
+	}
+}
+
+var defaultClosureStore = NewClosureStore()
+
+// DefaultClosureStore returns the server-wide closure store
+func DefaultClosureStore() *ClosureStore {
+	return defaultClosureStore
+}
+
+// Add validates and stores a closure, assigning an ID if it has none.
+// A closure with an existing ID replaces the stored one.
+func (s *ClosureStore) Add(c Closure) (Closure, error) {
+	if len(c.Geometry) == 0 {
+		return Closure{}, fmt.Errorf("closure geometry must contain at least one point")
+	}
+	for _, p := range c.Geometry {
+		if err := ValidateCoords(p.Latitude, p.Longitude); err != nil {
+			return Closure{}, err
+		}
+	}
+	if c.Area && len(c.Geometry) < 3 {
+		return Closure{}, fmt.Errorf("closure area must have at least 3 points")
+	}
+
+	switch c.Severity {
+	case "":
+		c.Severity = ClosureSeverityClosed
+	case ClosureSeverityClosed, ClosureSeverityDelay:
+	default:
+		return Closure{}, fmt.Errorf("unknown closure severity %q: use %s or %s", c.Severity, ClosureSeverityClosed, ClosureSeverityDelay)
+	}
+	if c.Severity == ClosureSeverityDelay && c.Delay <= 0 {
+		return Closure{}, fmt.Errorf("delay closures require a positive delay")
+	}
+
+	if c.Radius == 0 {
+		c.Radius = DefaultClosureRadius
+	}
+	if err := ValidateRadius(c.Radius, MaxClosureRadius); err != nil {
+		return Closure{}, err
+	}
+	if c.Start != nil && c.End != nil && !c.End.After(*c.Start) {
+		return Closure{}, fmt.Errorf("closure end must be after its start")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.ID == "" {
+		s.nextID++
+		c.ID = "closure-" + strconv.Itoa(s.nextID)
+	}
+	c.ReportedAt = s.now()
+	c.Penalty = 0
+	s.closures[c.ID] = c
+	return c, nil
+}
+
+// Remove deletes a closure, reporting whether it existed
+func (s *ClosureStore) Remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.closures[id]
+	delete(s.closures, id)
+	return ok
+}
+
+// Clear removes all closures and returns how many were removed
+func (s *ClosureStore) Clear() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.closures)
+	s.closures = make(map[string]Closure)
+	return n
+}
+
+// Active returns closures in effect now, ordered by ID. Expired closures are dropped.
+func (s *ClosureStore) Active() []Closure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	active := make([]Closure, 0, len(s.closures))
+	for id, c := range s.closures {
+		if c.End != nil && !now.Before(*c.End) {
+			delete(s.closures, id)
+			continue
+		}
+		if c.Start != nil && now.Before(*c.Start) {
+			continue
+		}
+		active = append(active, c)
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].ID < active[j].ID
+	})
+	return active
+}
+
+// AlongRoute returns the active closures affecting a route geometry, each
+// with its routing penalty set
+func (s *ClosureStore) AlongRoute(route []geo.Location) []Closure {
+	hits := make([]Closure, 0)
+	for _, c := range s.Active() {
+		if closureTouchesRoute(c, route) {
+			c.Penalty = c.penaltySeconds()
+			hits = append(hits, c)
+		}
+	}
+	return hits
+}
+
+// ClosurePenalty sums the routing penalties of closures
+func ClosurePenalty(closures []Closure) float64 {
+	total := 0.0
+	for _, c := range closures {
+		total += c.penaltySeconds()
+	}
+	return total
+}
+
+// penaltySeconds is the extra travel time a closure adds to a route using it
+func (c Closure) penaltySeconds() float64 {
+	if c.Severity == ClosureSeverityDelay {
+		return c.Delay
+	}
+	return ClosedPenalty
+}
+
+// closureTouchesRoute reports whether a route passes within the closure's buffer
+func closureTouchesRoute(c Closure, route []geo.Location) bool {
+	if len(route) == 0 {
+		return false
+	}
+
+	// Route vertices near or inside the closure geometry
+	for _, p := range route {
+		if c.Area {
+			if geo.PointInPolygon(p.Latitude, p.Longitude, c.Geometry) ||
+				geo.DistanceToBoundary(p.Latitude, p.Longitude, c.Geometry) <= c.Radius {
+				return true
+			}
+		} else if geo.DistanceToPolyline(p.Latitude, p.Longitude, c.Geometry) <= c.Radius {
+			return true
+		}
+	}
+
+	// Closure vertices near route segments, for sparse route geometry
+	for _, p := range c.Geometry {
+		if geo.DistanceToPolyline(p.Latitude, p.Longitude, route) <= c.Radius {
+			return true
+		}
+	}
+
+	// Route segments crossing a closure line between vertices
+	return pathsCross(route, c.Geometry)
+}
+
+// pathsCross reports whether any segment of a crosses any segment of b
+func pathsCross(a, b []geo.Location) bool {
+	for i := 0; i+1 < len(a); i++ {
+		for j := 0; j+1 < len(b); j++ {
+			if segmentsCross(a[i], a[i+1], b[j], b[j+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentsCross reports whether segments p1p2 and q1q2 properly intersect
+func segmentsCross(p1, p2, q1, q2 geo.Location) bool {
+	orient := func(a, b, c geo.Location) float64 {
+		return (b.Longitude-a.Longitude)*(c.Latitude-a.Latitude) - (b.Latitude-a.Latitude)*(c.Longitude-a.Longitude)
+	}
+	d1, d2 := orient(q1, q2, p1), orient(q1, q2, p2)
+	d3, d4 := orient(p1, p2, q1), orient(p1, p2, q2)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+// geoJSONFeatureCollection is the subset of GeoJSON used by closure feeds
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	ID         any             `json:"id"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties struct {
+		ID           string  `json:"id"`
+		Description  string  `json:"description"`
+		Reason       string  `json:"reason"`
+		Severity     string  `json:"severity"`
+		DelayMinutes float64 `json:"delay_minutes"`
+		Radius       float64 `json:"radius"`
+		Start        string  `json:"start"`
+		End          string  `json:"end"`
+	} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// LoadGeoJSON adds the closures in a GeoJSON FeatureCollection. Point,
+// LineString and Polygon features are supported; each MultiPoint or
+// MultiLineString part becomes its own closure. It returns the closures added.
+func (s *ClosureStore) LoadGeoJSON(data []byte, source string) ([]Closure, error) {
+	var fc geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a GeoJSON FeatureCollection, got %q", fc.Type)
+	}
+
+	added := make([]Closure, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		parts, area, err := geoJSONParts(f.Geometry)
+		if err != nil {
+			return added, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		base := Closure{
+			ID:          f.Properties.ID,
+			Description: f.Properties.Description,
+			Severity:    f.Properties.Severity,
+			Delay:       f.Properties.DelayMinutes * 60,
+			Radius:      f.Properties.Radius,
+			Area:        area,
+			Source:      source,
+		}
+		if base.ID == "" && f.ID != nil {
+			base.ID = fmt.Sprint(f.ID)
+		}
+		if base.Description == "" {
+			base.Description = f.Properties.Reason
+		}
+		if base.Start, err = parseClosureTime(f.Properties.Start); err != nil {
+			return added, fmt.Errorf("feature %d: %w", i, err)
+		}
+		if base.End, err = parseClosureTime(f.Properties.End); err != nil {
+			return added, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		for j, part := range parts {
+			c := base
+			c.Geometry = part
+			if len(parts) > 1 && c.ID != "" {
+				c.ID = fmt.Sprintf("%s-%d", c.ID, j+1)
+			}
+			stored, err := s.Add(c)
+			if err != nil {
+				return added, fmt.Errorf("feature %d: %w", i, err)
+			}
+			added = append(added, stored)
+		}
+	}
+	return added, nil
+}
+
+// geoJSONParts converts a GeoJSON geometry into closure geometries
+func geoJSONParts(g geoJSONGeometry) ([][]geo.Location, bool, error) {
+	switch g.Type {
+	case "Point":
+		var c []float64
+		if err := json.Unmarshal(g.Coordinates, &c); err != nil {
+			return nil, false, fmt.Errorf("invalid Point coordinates: %w", err)
+		}
+		p, err := geoJSONPosition(c)
+		if err != nil {
+			return nil, false, err
+		}
+		return [][]geo.Location{{p}}, false, nil
+	case "MultiPoint", "LineString":
+		var cs [][]float64
+		if err := json.Unmarshal(g.Coordinates, &cs); err != nil {
+			return nil, false, fmt.Errorf("invalid %s coordinates: %w", g.Type, err)
+		}
+		line, err := geoJSONLine(cs)
+		if err != nil {
+			return nil, false, err
+		}
+		if g.Type == "LineString" {
+			return [][]geo.Location{line}, false, nil
+		}
+		parts := make([][]geo.Location, len(line))
+		for i, p := range line {
+			parts[i] = []geo.Location{p}
+		}
+		return parts, false, nil
+	case "MultiLineString", "Polygon":
+		var css [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &css); err != nil {
+			return nil, false, fmt.Errorf("invalid %s coordinates: %w", g.Type, err)
+		}
+		if len(css) == 0 {
+			return nil, false, fmt.Errorf("empty %s", g.Type)
+		}
+		if g.Type == "Polygon" {
+			// Only the exterior ring matters for closures
+			ring, err := geoJSONLine(css[0])
+			return [][]geo.Location{ring}, true, err
+		}
+		parts := make([][]geo.Location, 0, len(css))
+		for _, cs := range css {
+			line, err := geoJSONLine(cs)
+			if err != nil {
+				return nil, false, err
+			}
+			parts = append(parts, line)
+		}
+		return parts, false, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+}
+
+// geoJSONLine converts GeoJSON [lon, lat] positions to locations
+func geoJSONLine(cs [][]float64) ([]geo.Location, error) {
+	line := make([]geo.Location, 0, len(cs))
+	for _, c := range cs {
+		p, err := geoJSONPosition(c)
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, p)
+	}
+	return line, nil
+}
+
+// geoJSONPosition converts a GeoJSON [lon, lat] position to a location
+func geoJSONPosition(c []float64) (geo.Location, error) {
+	if len(c) < 2 {
+		return geo.Location{}, fmt.Errorf("GeoJSON positions need longitude and latitude")
+	}
+	return geo.Location{Latitude: c[1], Longitude: c[0]}, nil
+}
+
+// parseClosureTime parses an optional RFC 3339 timestamp
+func parseClosureTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q: use RFC 3339", value)
+	}
+	return &t, nil
+}
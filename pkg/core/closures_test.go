@@ -0,0 +1,114 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestClosureStoreAddAndExpire(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewClosureStore()
+	store.now = func() time.Time { return now }
+
+	end := now.Add(time.Hour)
+	c, err := store.Add(Closure{Geometry: []geo.Location{{Latitude: 1, Longitude: 1}}, End: &end})
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if c.ID == "" || c.Severity != ClosureSeverityClosed || c.Radius != DefaultClosureRadius {
+		t.Errorf("expected defaults to be applied, got %+v", c)
+	}
+
+	if got := len(store.Active()); got != 1 {
+		t.Fatalf("expected 1 active closure, got %d", got)
+	}
+
+	now = end
+	if got := len(store.Active()); got != 0 {
+		t.Errorf("expected closure to expire, got %d active", got)
+	}
+}
+
+func TestClosureStoreValidation(t *testing.T) {
+	store := NewClosureStore()
+	point := []geo.Location{{Latitude: 1, Longitude: 1}}
+
+	invalid := []Closure{
+		{},
+		{Geometry: []geo.Location{{Latitude: 95, Longitude: 1}}},
+		{Geometry: point, Severity: "blocked"},
+		{Geometry: point, Severity: ClosureSeverityDelay},
+		{Geometry: point, Radius: MaxClosureRadius + 1},
+		{Geometry: point, Area: true},
+	}
+	for i, c := range invalid {
+		if _, err := store.Add(c); err == nil {
+			t.Errorf("case %d: expected validation error for %+v", i, c)
+		}
+	}
+}
+
+func TestClosureStoreAlongRoute(t *testing.T) {
+	store := NewClosureStore()
+
+	// Route runs east along the equator
+	route := []geo.Location{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.02}}
+
+	if _, err := store.Add(Closure{ID: "on-route", Geometry: []geo.Location{{Latitude: 0.0001, Longitude: 0.01}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Closure{ID: "off-route", Geometry: []geo.Location{{Latitude: 0.01, Longitude: 0.01}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Closure{ID: "delay", Severity: ClosureSeverityDelay, Delay: 300, Geometry: []geo.Location{
+		{Latitude: -0.001, Longitude: 0.015}, {Latitude: 0.001, Longitude: 0.015},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	hits := store.AlongRoute(route)
+	if len(hits) != 2 || hits[0].ID != "delay" || hits[1].ID != "on-route" {
+		t.Fatalf("unexpected closures along route: %+v", hits)
+	}
+	if got := ClosurePenalty(hits); got != ClosedPenalty+300 {
+		t.Errorf("ClosurePenalty() = %v, want %v", got, ClosedPenalty+300)
+	}
+}
+
+func TestClosureStoreLoadGeoJSON(t *testing.T) {
+	store := NewClosureStore()
+	feed := []byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "id": 7, "geometry": {"type": "Point", "coordinates": [-122.42, 37.77]},
+			 "properties": {"reason": "Water main break"}},
+			{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[-122.41, 37.78], [-122.40, 37.78]]},
+			 "properties": {"id": "market", "severity": "delay", "delay_minutes": 10, "end": "2999-01-01T00:00:00Z"}},
+			{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[-122.5, 37.7], [-122.4, 37.7], [-122.4, 37.8], [-122.5, 37.7]]]},
+			 "properties": {"id": "zone"}}
+		]
+	}`)
+
+	added, err := store.LoadGeoJSON(feed, "test")
+	if err != nil {
+		t.Fatalf("LoadGeoJSON() error: %v", err)
+	}
+	if len(added) != 3 {
+		t.Fatalf("expected 3 closures, got %d", len(added))
+	}
+	if added[0].ID != "7" || added[0].Description != "Water main break" || added[0].Geometry[0].Latitude != 37.77 {
+		t.Errorf("unexpected point closure: %+v", added[0])
+	}
+	if added[1].Delay != 600 || added[1].End == nil || len(added[1].Geometry) != 2 {
+		t.Errorf("unexpected line closure: %+v", added[1])
+	}
+	if !added[2].Area || added[2].Source != "test" {
+		t.Errorf("unexpected area closure: %+v", added[2])
+	}
+
+	if _, err := store.LoadGeoJSON([]byte(`{"type": "Feature"}`), "test"); err == nil {
+		t.Error("expected error for non-FeatureCollection")
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// snippetRadius is the number of bytes shown on each side of a parse error
+const snippetRadius = 24
+
+// DecodeUpstream decodes a JSON response from an upstream service such as
+// Nominatim, Overpass or OSRM into v. Empty, non-JSON and malformed bodies,
+// and bodies missing one of the required top-level fields, are PARSE_ERRORs
+// naming the service, the field path and the offending text. A field of the
+// wrong type is tolerated: it keeps its zero value, the rest of the response
+// is used and the mismatch is logged, so one odd element does not fail a
+// whole query.
+func DecodeUpstream(service string, r io.Reader, v any, required ...string) *MCPError {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return NewError(ErrParseError, fmt.Sprintf("%s response could not be read: %v", service, err)).
+			WithGuidance("The connection was interrupted; try again")
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return NewError(ErrParseError, fmt.Sprintf("%s returned an empty response", service)).
+			WithGuidance("The service may be overloaded; try again later")
+	}
+	if trimmed[0] == '<' {
+		return NewError(ErrParseError, fmt.Sprintf("%s returned HTML or XML instead of JSON: %s", service, snippet(trimmed, 0))).
+			WithGuidance("The service returned an error page; try again later")
+	}
+
+	err = json.Unmarshal(data, v)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return NewError(ErrParseError, fmt.Sprintf("%s returned malformed JSON at %s (offset %d): %v near %q",
+			service, fieldPathAt(data, syntaxErr.Offset), syntaxErr.Offset, syntaxErr, snippet(data, syntaxErr.Offset))).
+			WithGuidance("The response may have been truncated; try again or reduce the query size")
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		return NewError(ErrParseError, fmt.Sprintf("%s returned a JSON %s where %s was expected", service, typeErr.Value, jsonKind(typeErr.Type.String()))).
+			WithGuidance("The response was not in the expected format: " + snippet(data, 0))
+	case errors.As(err, &typeErr):
+		slog.Default().Warn("ignoring mistyped field in upstream response",
+			"service", service, "field", typeErr.Field, "got", typeErr.Value, "want", typeErr.Type.String())
+	case err != nil:
+		return NewError(ErrParseError, fmt.Sprintf("%s response could not be decoded: %v", service, err))
+	}
+
+	if len(required) > 0 {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return NewError(ErrParseError, fmt.Sprintf("%s returned a JSON value where an object was expected", service))
+		}
+		for _, name := range required {
+			if _, ok := fields[name]; !ok {
+				return NewError(ErrParseError, fmt.Sprintf("%s response is missing the required field %q: %s", service, name, snippet(data, 0))).
+					WithGuidance("The service may have returned an error; check the query and try again")
+			}
+		}
+	}
+	return nil
+}
+
+// jsonKind names the JSON kind a Go type decodes from
+func jsonKind(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "[]"):
+		return "an array"
+	case strings.HasPrefix(goType, "map["), strings.HasPrefix(goType, "struct"), strings.Contains(goType, "."):
+		return "an object"
+	}
+	return "a " + goType
+}
+
+// snippet returns the printable text around an offset in data
+func snippet(data []byte, offset int64) string {
+	start := max(0, int(offset)-snippetRadius)
+	end := min(len(data), int(offset)+snippetRadius)
+	if start > end {
+		start = end
+	}
+	text := strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return ' '
+	}, string(data[start:end]))
+	if end < len(data) {
+		text += "..."
+	}
+	return text
+}
+
+// pathFrame is an open object or array while walking JSON tokens
+type pathFrame struct {
+	array   bool
+	index   int    // index of the current array element
+	key     string // key of the current object member
+	wantKey bool   // the next string in an object is a key
+}
+
+// fieldPathAt returns the path, such as elements[3].tags, of the value being
+// read at an offset in data, by walking its tokens up to that point
+func fieldPathAt(data []byte, offset int64) string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []pathFrame
+
+	startValue := func() {
+		if n := len(stack); n > 0 && stack[n-1].array {
+			stack[n-1].index++
+		}
+	}
+	endValue := func() {
+		if n := len(stack); n > 0 && !stack[n-1].array {
+			stack[n-1].wantKey = true
+		}
+	}
+
+	for dec.InputOffset() < offset {
+		// An array element that fails to parse was still started
+		inArray := len(stack) > 0 && stack[len(stack)-1].array && dec.More()
+		tok, err := dec.Token()
+		if err != nil {
+			if inArray {
+				startValue()
+			}
+			break
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				startValue()
+				stack = append(stack, pathFrame{array: t == '[', index: -1, wantKey: t == '{'})
+			case '}', ']':
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+				endValue()
+			}
+		case string:
+			if n := len(stack); n > 0 && !stack[n-1].array && stack[n-1].wantKey {
+				stack[n-1].key = t
+				stack[n-1].wantKey = false
+				continue
+			}
+			startValue()
+			endValue()
+		default:
+			startValue()
+			endValue()
+		}
+	}
+
+	var b strings.Builder
+	for _, frame := range stack {
+		switch {
+		case frame.array && frame.index >= 0:
+			fmt.Fprintf(&b, "[%d]", frame.index)
+		case !frame.array && frame.key != "":
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(frame.key)
+		}
+	}
+	if b.Len() == 0 {
+		return "the top level"
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeUpstream(t *testing.T) {
+	type element struct {
+		ID   int               `json:"id"`
+		Tags map[string]string `json:"tags"`
+	}
+	type response struct {
+		Elements []element `json:"elements"`
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantErr  string // substring of the error message, empty for success
+		elements int
+	}{
+		{"Valid", `{"elements": [{"id": 1}, {"id": 2}]}`, "", 2},
+		{"Mistyped field is tolerated", `{"elements": [{"id": 1}, {"id": "two"}, {"id": 3}]}`, "", 3},
+		{"Empty", "  \n", "empty response", 0},
+		{"HTML error page", "<html><body>Too busy</body></html>", "HTML or XML instead of JSON", 0},
+		{"Truncated", `{"elements": [{"id": 1}, {"id": 2, "tags": {"name": "Caf`, "elements[1].tags.name", 0},
+		{"Malformed", `{"elements": [{"id": 1}, {"id": 2,, }]}`, "elements[1]", 0},
+		{"Array instead of object", `[1, 2]`, "JSON array where an object was expected", 0},
+		{"Missing required field", `{"remark": "runtime error: timeout"}`, `missing the required field "elements"`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp response
+			err := DecodeUpstream("Overpass", strings.NewReader(tt.body), &resp, "elements")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(resp.Elements) != tt.elements {
+					t.Errorf("expected %d elements, got %d", tt.elements, len(resp.Elements))
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if err.Code != string(ErrParseError) || !strings.Contains(err.Message, tt.wantErr) || !strings.HasPrefix(err.Message, "Overpass") {
+				t.Errorf("expected a PARSE_ERROR mentioning %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFieldPathAt(t *testing.T) {
+	data := []byte(`{"routes": [{"legs": []}, {"legs": [{"steps": [1, 2, x]}]}]}`)
+	offset := int64(strings.Index(string(data), "x"))
+	if got := fieldPathAt(data, offset); got != "routes[1].legs[0].steps[2]" {
+		t.Errorf("unexpected path %q", got)
+	}
+	if got := fieldPathAt([]byte(`x`), 0); got != "the top level" {
+		t.Errorf("unexpected path %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// MaxElevationPoints is the largest number of points per elevation request
+	MaxElevationPoints = 100
+)
+
+// ElevationOptions defines options for elevation requests
+type ElevationOptions struct {
+	// Base URL for the elevation service
+	BaseURL string
+
+	// Client is the HTTP client to use for requests
+	Client *http.Client
+
+	// RetryOptions controls retry behavior
+	RetryOptions RetryOptions
+}
+
+// DefaultElevationOptions returns reasonable defaults for elevation requests
+func DefaultElevationOptions() ElevationOptions {
+	return ElevationOptions{
+		BaseURL:      osm.ElevationBaseURL,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+		RetryOptions: DefaultRetryOptions,
+	}
+}
+
+// elevationResponse is the response from the elevation service
+type elevationResponse struct {
+	Elevation []float64 `json:"elevation"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// GetElevations returns the terrain elevation in meters above sea level for
+// each point, in input order
+func GetElevations(ctx context.Context, points []geo.Location, options ElevationOptions) ([]float64, error) {
+	if len(points) == 0 || len(points) > MaxElevationPoints {
+		return nil, NewError(ErrInvalidParameter, fmt.Sprintf("between 1 and %d points are required", MaxElevationPoints))
+	}
+
+	lats := make([]string, len(points))
+	lons := make([]string, len(points))
+	for i, p := range points {
+		lats[i] = strconv.FormatFloat(p.Latitude, 'f', 6, 64)
+		lons[i] = strconv.FormatFloat(p.Longitude, 'f', 6, 64)
+	}
+
+	if options.BaseURL == "" {
+		options.BaseURL = osm.ElevationBaseURL
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	reqURL, err := url.Parse(options.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	query := reqURL.Query()
+	query.Set("latitude", strings.Join(lats, ","))
+	query.Set("longitude", strings.Join(lons, ","))
+	reqURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "OSM-MCP-Client/1.0")
+
+	resp, err := WithRetry(ctx, req, options.Client, options.RetryOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result elevationResponse
+	if err := DecodeUpstream("Elevation", resp.Body, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Elevation) != len(points) {
+		return nil, NewError(ErrServiceUnavailable, fmt.Sprintf("elevation service returned %d values for %d points", len(result.Elevation), len(points)))
+	}
+
+	return result.Elevation, nil
+}
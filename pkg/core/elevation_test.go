@@ -0,0 +1,57 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestGetElevations(t *testing.T) {
+	var gotLat, gotLon string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotLat, gotLon = r.URL.Query().Get("latitude"), r.URL.Query().Get("longitude")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"elevation":[38.0,3.5]}`))
+	}))
+	defer server.Close()
+
+	options := DefaultElevationOptions()
+	options.BaseURL = server.URL
+	options.RetryOptions.MaxAttempts = 1
+
+	points := []geo.Location{{Latitude: 52.52, Longitude: 13.41}, {Latitude: 48.85, Longitude: 2.35}}
+	elevations, err := GetElevations(context.Background(), points, options)
+	if err != nil {
+		t.Fatalf("GetElevations() error: %v", err)
+	}
+	if gotLat != "52.520000,48.850000" || gotLon != "13.410000,2.350000" {
+		t.Errorf("unexpected query latitude=%s longitude=%s", gotLat, gotLon)
+	}
+	if len(elevations) != 2 || elevations[0] != 38 || elevations[1] != 3.5 {
+		t.Errorf("unexpected elevations %v", elevations)
+	}
+
+	// A response with the wrong number of values is an error
+	if _, err := GetElevations(context.Background(), points[:1], options); err == nil {
+		t.Error("expected error for mismatched elevation count")
+	}
+}
+
+func TestGetElevationsErrors(t *testing.T) {
+	server, _ := newErrorServer(http.StatusBadRequest)
+	defer server.Close()
+
+	options := DefaultElevationOptions()
+	options.BaseURL = server.URL
+	options.RetryOptions.MaxAttempts = 1
+
+	if _, err := GetElevations(context.Background(), nil, options); err == nil {
+		t.Error("expected error for no points")
+	}
+	if _, err := GetElevations(context.Background(), []geo.Location{{}}, options); err == nil {
+		t.Error("expected error for an HTTP error status")
+	}
+}
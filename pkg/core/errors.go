@@ -4,7 +4,9 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -31,9 +33,13 @@ const (
 	ErrNetworkError       ErrorCode = "NETWORK_ERROR"
 
 	// Data errors
-	ErrNoResults     ErrorCode = "NO_RESULTS"
-	ErrParseError    ErrorCode = "PARSE_ERROR"
-	ErrInternalError ErrorCode = "INTERNAL_ERROR"
+	ErrNoResults      ErrorCode = "NO_RESULTS"
+	ErrTooManyResults ErrorCode = "TOO_MANY_RESULTS"
+	ErrParseError     ErrorCode = "PARSE_ERROR"
+	ErrInternalError  ErrorCode = "INTERNAL_ERROR"
+
+	// Access errors
+	ErrPermissionDenied ErrorCode = "PERMISSION_DENIED"
 )
 
 // MCPError represents a detailed error structure for MCP tool responses
@@ -43,6 +49,9 @@ type MCPError struct {
 	Query       string   `json:"query,omitempty"`
 	Suggestions []string `json:"suggestions,omitempty"`
 	Guidance    string   `json:"guidance,omitempty"`
+	// RetryAfterSeconds is the wait the service asked for before retrying,
+	// so clients can decide whether to wait or narrow the query
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
 }
 
 // Error implements the error interface
@@ -73,6 +82,13 @@ func (e *MCPError) WithGuidance(guidance string) *MCPError {
 	return e
 }
 
+// WithRetryAfter records the wait a service asked for before retrying,
+// rounded up to whole seconds
+func (e *MCPError) WithRetryAfter(wait time.Duration) *MCPError {
+	e.RetryAfterSeconds = int(math.Ceil(wait.Seconds()))
+	return e
+}
+
 // WithSuggestions adds suggestions to the error
 func (e *MCPError) WithSuggestions(suggestions ...string) *MCPError {
 	e.Suggestions = append(e.Suggestions, suggestions...)
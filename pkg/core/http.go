@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/NERVsystems/osmmcp/pkg/osm"
 	"github.com/NERVsystems/osmmcp/pkg/tracing"
 )
 
@@ -33,13 +35,21 @@ var DefaultRetryOptions = RetryOptions{
 
 // DefaultClient provides a pre-configured HTTP client with secure defaults
 var DefaultClient = &http.Client{
-	Timeout: 30 * time.Second,
-	Transport: &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		TLSHandshakeTimeout: 10 * time.Second,
-	},
+	Timeout:   30 * time.Second,
+	Transport: osm.NewTransport(osm.DefaultTransportConfig()),
+}
+
+// ConfigureTransport applies connection pool tuning to DefaultClient and the
+// OSM client
+func ConfigureTransport(config osm.TransportConfig) {
+	DefaultClient.Transport = osm.NewTransport(config)
+	osm.ConfigureTransport(config)
+}
+
+// isSuccessStatus reports whether a response ends the retry loop. 304 Not
+// Modified is only returned to conditional requests, whose callers handle it.
+func isSuccessStatus(status int) bool {
+	return status == http.StatusOK || status == http.StatusNotModified
 }
 
 // secureHeaders adds security headers to the request
@@ -72,6 +82,7 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 	var lastErr error
 
 	delay := options.InitialDelay
+	wait := delay // delay before the next attempt, or the wait the server asked for
 
 	for attempt := 0; attempt < options.MaxAttempts; attempt++ {
 		// If not the first attempt, log and wait
@@ -80,7 +91,7 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 			tracing.AddEvent(ctx, "retry_attempt",
 				trace.WithAttributes(
 					attribute.Int("attempt", attempt+1),
-					attribute.Int64("delay_ms", delay.Milliseconds()),
+					attribute.Int64("delay_ms", wait.Milliseconds()),
 					attribute.String("error", fmt.Sprintf("%v", lastErr)),
 				),
 			)
@@ -88,13 +99,13 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 			logger.Info("retrying request",
 				"attempt", attempt+1,
 				"max_attempts", options.MaxAttempts,
-				"delay", delay,
+				"delay", wait,
 				"last_error", lastErr,
 			)
 
 			// Wait for backoff delay
 			select {
-			case <-time.After(delay):
+			case <-time.After(wait):
 				// Continue with retry
 			case <-ctx.Done():
 				span.SetStatus(codes.Error, "request canceled")
@@ -106,6 +117,7 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 			if delay > options.MaxDelay {
 				delay = options.MaxDelay
 			}
+			wait = delay
 		}
 
 		// Make a new request for each attempt to avoid body already closed errors
@@ -122,7 +134,7 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 
 		// Execute the request
 		resp, err := client.Do(newReq)
-		if err == nil && resp.StatusCode == http.StatusOK {
+		if err == nil && isSuccessStatus(resp.StatusCode) {
 			// Success - set span attributes
 			span.SetAttributes(
 				attribute.Int(tracing.AttrHTTPStatusCode, resp.StatusCode),
@@ -149,7 +161,7 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 				"url", req.URL.String(),
 			)
 		} else {
-			lastErr = ServiceError("HTTP", resp.StatusCode, fmt.Sprintf("HTTP status %d", resp.StatusCode))
+			lastErr = statusError(resp)
 			logger.Error("request returned error status",
 				"status", resp.StatusCode,
 				"attempt", attempt+1,
@@ -159,6 +171,19 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 			if err := resp.Body.Close(); err != nil {
 				logger.Warn("failed to close response body", "error", err)
 			}
+			if !retryable(resp) {
+				span.SetStatus(codes.Error, "request rejected")
+				return nil, lastErr
+			}
+			// Retry when the server asked to, or give up at once when that
+			// is too far off and leave the decision to the client
+			if serverWait, ok := retryAfter(resp); ok {
+				if !withinRetryBudget(ctx, serverWait, options) {
+					span.SetStatus(codes.Error, "retry after exceeds the retry budget")
+					return nil, lastErr
+				}
+				wait = serverWait
+			}
 		}
 	}
 
@@ -177,6 +202,48 @@ func WithRetry(ctx context.Context, req *http.Request, client *http.Client, opti
 		WithGuidance("The request failed after multiple attempts. Please try again later")
 }
 
+// statusError describes a response with an error status, using the message
+// Overpass embedded in it when there is one and the wait the server asked
+// for before retrying
+func statusError(resp *http.Response) *MCPError {
+	var err *MCPError
+	if message := resp.Header.Get(osm.OverpassErrorHeader); message != "" {
+		err = ServiceError("Overpass", resp.StatusCode, message)
+	} else {
+		err = ServiceError("HTTP", resp.StatusCode, fmt.Sprintf("HTTP status %d", resp.StatusCode))
+	}
+	if wait, ok := retryAfter(resp); ok && wait > 0 {
+		err.WithRetryAfter(wait).WithGuidance(fmt.Sprintf(
+			"The service asked to retry in %d seconds. Wait that long, or narrow the query so it needs fewer resources",
+			int(math.Ceil(wait.Seconds()))))
+	}
+	return err
+}
+
+// retryAfter returns the wait a failed response asked for with Retry-After
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	return osm.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+}
+
+// withinRetryBudget reports whether a wait the server asked for is short
+// enough to retry within the request: no longer than the maximum delay and
+// ending before the context deadline
+func withinRetryBudget(ctx context.Context, wait time.Duration, options RetryOptions) bool {
+	if wait > options.MaxDelay {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+		return false
+	}
+	return true
+}
+
+// retryable reports whether a failed response may succeed on retry. An
+// Overpass query syntax error fails the same way every time.
+func retryable(resp *http.Response) bool {
+	return resp.StatusCode != http.StatusBadRequest || resp.Header.Get(osm.OverpassErrorHeader) == ""
+}
+
 // DoWithRetry performs an HTTP request with default retry options
 func DoWithRetry(ctx context.Context, req *http.Request, client *http.Client) (*http.Response, error) {
 	if client == nil {
@@ -201,6 +268,7 @@ func WithRetryFactory(ctx context.Context, factory RequestFactory, client *http.
 
 	var lastErr error
 	delay := options.InitialDelay
+	wait := delay // delay before the next attempt, or the wait the server asked for
 	logger := slog.Default()
 
 	if client == nil {
@@ -214,7 +282,7 @@ func WithRetryFactory(ctx context.Context, factory RequestFactory, client *http.
 			tracing.AddEvent(ctx, "retry_attempt",
 				trace.WithAttributes(
 					attribute.Int("attempt", attempt+1),
-					attribute.Int64("delay_ms", delay.Milliseconds()),
+					attribute.Int64("delay_ms", wait.Milliseconds()),
 					attribute.String("error", fmt.Sprintf("%v", lastErr)),
 				),
 			)
@@ -222,13 +290,13 @@ func WithRetryFactory(ctx context.Context, factory RequestFactory, client *http.
 			logger.Info("retrying request",
 				"attempt", attempt+1,
 				"max_attempts", options.MaxAttempts,
-				"delay", delay,
+				"delay", wait,
 				"last_error", lastErr,
 			)
 
 			// Wait for backoff delay
 			select {
-			case <-time.After(delay):
+			case <-time.After(wait):
 				// Continue with retry
 			case <-ctx.Done():
 				span.SetStatus(codes.Error, "request canceled")
@@ -240,6 +308,7 @@ func WithRetryFactory(ctx context.Context, factory RequestFactory, client *http.
 			if delay > options.MaxDelay {
 				delay = options.MaxDelay
 			}
+			wait = delay
 		}
 
 		// Create a new request
@@ -262,7 +331,7 @@ func WithRetryFactory(ctx context.Context, factory RequestFactory, client *http.
 
 		// Execute the request
 		resp, err := client.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
+		if err == nil && isSuccessStatus(resp.StatusCode) {
 			// Success - set span attributes
 			span.SetAttributes(
 				attribute.String(tracing.AttrHTTPMethod, req.Method),
@@ -293,7 +362,7 @@ func WithRetryFactory(ctx context.Context, factory RequestFactory, client *http.
 				"url", req.URL.String(),
 			)
 		} else {
-			lastErr = ServiceError("HTTP", resp.StatusCode, fmt.Sprintf("HTTP status %d", resp.StatusCode))
+			lastErr = statusError(resp)
 			logger.Error("request returned error status",
 				"status", resp.StatusCode,
 				"attempt", attempt+1,
@@ -303,6 +372,19 @@ func WithRetryFactory(ctx context.Context, factory RequestFactory, client *http.
 			if err := resp.Body.Close(); err != nil {
 				logger.Warn("failed to close response body", "error", err)
 			}
+			if !retryable(resp) {
+				span.SetStatus(codes.Error, "request rejected")
+				return nil, lastErr
+			}
+			// Retry when the server asked to, or give up at once when that
+			// is too far off and leave the decision to the client
+			if serverWait, ok := retryAfter(resp); ok {
+				if !withinRetryBudget(ctx, serverWait, options) {
+					span.SetStatus(codes.Error, "retry after exceeds the retry budget")
+					return nil, lastErr
+				}
+				wait = serverWait
+			}
 		}
 	}
 
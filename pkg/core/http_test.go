@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithRetryFactoryHonorsRetryAfter(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	factory := func() (*http.Request, error) { return http.NewRequest(http.MethodGet, srv.URL, nil) }
+	// A blind backoff would wait the full initial delay
+	options := RetryOptions{MaxAttempts: 2, InitialDelay: 5 * time.Second, MaxDelay: 10 * time.Second, Multiplier: 2}
+
+	start := time.Now()
+	resp, err := WithRetryFactory(context.Background(), factory, srv.Client(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retry took %v, want it scheduled by Retry-After", elapsed)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("got %d requests, want 2", hits.Load())
+	}
+}
+
+func TestWithRetryGivesUpOnLongRetryAfter(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	_, err := WithRetry(context.Background(), req, srv.Client(), DefaultRetryOptions)
+	mcpErr, ok := err.(*MCPError)
+	if !ok {
+		t.Fatalf("got %v, want an MCPError", err)
+	}
+	if mcpErr.Code != string(ErrRateLimit) || mcpErr.RetryAfterSeconds != 120 {
+		t.Errorf("got %+v, want a rate limit error asking for 120 seconds", mcpErr)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("got %d requests, want no retry before the requested time", hits.Load())
+	}
+}
+
+func TestWithinRetryBudget(t *testing.T) {
+	options := RetryOptions{MaxDelay: 10 * time.Second}
+	if !withinRetryBudget(context.Background(), 5*time.Second, options) {
+		t.Error("a wait under the maximum delay should be retried")
+	}
+	if withinRetryBudget(context.Background(), 11*time.Second, options) {
+		t.Error("a wait over the maximum delay should not be retried")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if withinRetryBudget(ctx, 5*time.Second, options) {
+		t.Error("a wait past the deadline should not be retried")
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+const (
+	// isochroneBearings is the number of directions sampled around the origin
+	isochroneBearings = 16
+	// isochroneRings is the number of distances sampled in each direction;
+	// bearings times rings plus the origin must fit one table request
+	isochroneRings = 5
+)
+
+// isochroneMaxSpeed is a fast but plausible average speed per profile in
+// meters per second, bounding how far samples are placed from the origin
+var isochroneMaxSpeed = map[string]float64{
+	"car":  25,  // 90 km/h
+	"bike": 6,   // 22 km/h
+	"foot": 1.6, // 5.8 km/h
+}
+
+// IsochroneResult is the area reachable from an origin within a travel time
+type IsochroneResult struct {
+	Polygon   []geo.Location // closed ring, one vertex per bearing
+	Reach     []float64      // meters from the origin to each vertex
+	Area      float64        // square meters
+	Samples   int            // points whose travel time was requested
+	Reachable int            // samples reached within the time
+}
+
+// Isochrone estimates the area reachable from an origin within a number of
+// seconds. Points on rings around the origin are timed with one OSRM table
+// request, and in each direction the boundary is placed where the travel
+// time crosses the limit, interpolating between samples.
+func Isochrone(ctx context.Context, origin geo.Location, seconds float64, options OSRMOptions) (*IsochroneResult, error) {
+	if seconds <= 0 {
+		return nil, NewError(ErrInvalidParameter, "Travel time must be positive")
+	}
+	speed, ok := isochroneMaxSpeed[options.Profile]
+	if !ok {
+		return nil, NewError(ErrInvalidParameter, fmt.Sprintf("Unsupported profile %q", options.Profile)).
+			WithGuidance("Use car, bike or foot")
+	}
+
+	maxReach := seconds * speed
+	radii := make([]float64, isochroneRings)
+	for i := range radii {
+		radii[i] = maxReach * float64(i+1) / isochroneRings
+	}
+
+	destinations := make([][]float64, 0, isochroneBearings*isochroneRings)
+	for b := 0; b < isochroneBearings; b++ {
+		bearing := 360 * float64(b) / isochroneBearings
+		for _, r := range radii {
+			p := geo.DestinationPoint(origin, bearing, r)
+			destinations = append(destinations, []float64{p.Longitude, p.Latitude})
+		}
+	}
+
+	table, err := GetTable(ctx, [][]float64{{origin.Longitude, origin.Latitude}}, destinations, options)
+	if err != nil {
+		return nil, err
+	}
+	durations := table.Durations[0]
+	if len(durations) != len(destinations) {
+		return nil, NewError(ErrParseError, "OSRM table has the wrong number of columns").
+			WithGuidance("The routing service returned an unexpected response; try again later")
+	}
+
+	result := &IsochroneResult{Samples: len(destinations)}
+	for _, d := range durations {
+		if d != nil && *d <= seconds {
+			result.Reachable++
+		}
+	}
+	for b := 0; b < isochroneBearings; b++ {
+		bearing := 360 * float64(b) / isochroneBearings
+		reach := reachAlong(radii, durations[b*isochroneRings:(b+1)*isochroneRings], seconds)
+		result.Reach = append(result.Reach, reach)
+		result.Polygon = append(result.Polygon, geo.DestinationPoint(origin, bearing, reach))
+	}
+	result.Area = geo.PolygonArea(result.Polygon)
+	result.Polygon = append(result.Polygon, result.Polygon[0])
+	return result, nil
+}
+
+// reachAlong returns how far from the origin the time limit is reached in
+// one direction, given travel times to samples at increasing distances.
+// Samples without a route are skipped. Beyond the farthest sample reached
+// in time, the distance is interpolated toward the next slower sample.
+func reachAlong(radii []float64, durations []*float64, limit float64) float64 {
+	last := -1
+	for i, d := range durations {
+		if d != nil && *d <= limit {
+			last = i
+		}
+	}
+
+	lastRadius, lastDuration := 0.0, 0.0
+	if last >= 0 {
+		lastRadius, lastDuration = radii[last], *durations[last]
+	}
+	for i := last + 1; i < len(radii); i++ {
+		if d := durations[i]; d != nil && *d > lastDuration {
+			return lastRadius + (radii[i]-lastRadius)*(limit-lastDuration)/(*d-lastDuration)
+		}
+	}
+	return lastRadius
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestReachAlong(t *testing.T) {
+	d := func(v float64) *float64 { return &v }
+	radii := []float64{100, 200, 300, 400}
+
+	tests := []struct {
+		name      string
+		durations []*float64
+		want      float64
+	}{
+		{"Interpolated", []*float64{d(10), d(20), d(40), d(60)}, 250},
+		{"All reachable", []*float64{d(10), d(20), d(25), d(29)}, 400},
+		{"None reachable", []*float64{d(60), d(90), d(120), d(150)}, 50},
+		{"Unroutable samples skipped", []*float64{d(10), nil, d(40), nil}, 100 + 200*20.0/30},
+		{"No routes", []*float64{nil, nil, nil, nil}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reachAlong(radii, tt.durations, 30); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("reachAlong() = %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsochrone(t *testing.T) {
+	origin := geo.Location{Latitude: 51.5, Longitude: -0.12}
+
+	// Travel at 10 m/s in a straight line, but only 5 m/s to the east
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/table/v1/car/") || r.URL.Query().Get("sources") != "0" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		coords := strings.Split(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ";")
+		row := make([]float64, 0, len(coords)-1)
+		for _, c := range coords[1:] {
+			parts := strings.Split(c, ",")
+			lon, _ := strconv.ParseFloat(parts[0], 64)
+			lat, _ := strconv.ParseFloat(parts[1], 64)
+			speed := 10.0
+			if lon > origin.Longitude+0.001 && math.Abs(lat-origin.Latitude) < 0.001 {
+				speed = 5
+			}
+			row = append(row, geo.HaversineDistance(origin.Latitude, origin.Longitude, lat, lon)/speed)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"code": "Ok", "durations": [][]float64{row}})
+	}))
+	defer server.Close()
+	resetRouteCache()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+	options.Client = server.Client()
+	options.RetryOptions.MaxAttempts = 1
+
+	result, err := Isochrone(context.Background(), origin, 600, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Polygon) != isochroneBearings+1 || result.Polygon[0] != result.Polygon[isochroneBearings] {
+		t.Fatalf("expected a closed ring of %d vertices, got %d", isochroneBearings, len(result.Polygon))
+	}
+	if math.Abs(result.Reach[0]-6000) > 1 {
+		t.Errorf("expected 6 km reach to the north, got %.0f", result.Reach[0])
+	}
+	if east := result.Reach[isochroneBearings/4]; math.Abs(east-3000) > 1 {
+		t.Errorf("expected 3 km reach to the east, got %.0f", east)
+	}
+	if result.Samples != isochroneBearings*isochroneRings || result.Area <= 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := Isochrone(context.Background(), origin, 600, OSRMOptions{Profile: "boat"}); err == nil {
+		t.Error("expected an unsupported profile to fail")
+	}
+}
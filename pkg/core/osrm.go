@@ -12,23 +12,19 @@ import (
 	"sync"
 	"time"
 
-	lru "github.com/hashicorp/golang-lru/v2"
-
+	"github.com/NERVsystems/osmmcp/pkg/cache"
 	"github.com/NERVsystems/osmmcp/pkg/geo"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
 const (
-	// Default OSRM service base URL
-	defaultOSRMBaseURL = "https://router.project-osrm.org"
-
 	// Default cache size for route results
 	defaultRouteCacheSize = 256
 )
 
 var (
 	// Global route cache
-	routeCache     *lru.Cache[string, *OSRMResult]
+	routeCache     *cache.TTLCache
 	routeCacheOnce sync.Once
 )
 
@@ -74,10 +70,19 @@ type OSRMOptions struct {
 	RetryOptions RetryOptions
 }
 
+// PolylinePrecision returns the decimals of polylines returned in an OSRM
+// geometries format
+func PolylinePrecision(geometries string) int {
+	if geometries == "polyline6" {
+		return 6
+	}
+	return 5
+}
+
 // DefaultOSRMOptions returns reasonable defaults for OSRM requests
 func DefaultOSRMOptions() OSRMOptions {
 	return OSRMOptions{
-		BaseURL:         defaultOSRMBaseURL,
+		BaseURL:         osm.OSRMBaseURL,
 		Profile:         "car",
 		Overview:        "simplified",
 		Steps:           false,
@@ -150,17 +155,33 @@ type OSRMResult struct {
 	Waypoints []OSRMWaypoint `json:"waypoints"` // Array of waypoints
 }
 
-// initCache initializes the route cache
+// initCache initializes the route cache, with entries expiring after the
+// route TTL of the cache policy
 func initCache() {
 	routeCacheOnce.Do(func() {
-		var err error
-		routeCache, err = lru.New[string, *OSRMResult](defaultRouteCacheSize)
-		if err != nil {
-			routeCache, _ = lru.New[string, *OSRMResult](16) // Fallback to smaller cache
-		}
+		routeCache = cache.NewTTLCache(cache.TTLFor(cache.ClassRoute), time.Minute, cache.MaxEntriesFor("route", defaultRouteCacheSize))
+		cache.Register("route", routeCache)
+		routeCache.Persist(cache.DefaultBackend(), "route", routeCodec)
 	})
 }
 
+// routeCodec persists routes and tables, told apart by their keys
+var routeCodec = cache.Codec{
+	Encode: func(value interface{}) ([]byte, error) {
+		return json.Marshal(value)
+	},
+	Decode: func(key string, data []byte) (interface{}, error) {
+		var value interface{} = &OSRMResult{}
+		if strings.HasPrefix(key, "table:") {
+			value = &OSRMTable{}
+		}
+		if err := json.Unmarshal(data, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	},
+}
+
 // cacheKey generates a cache key for a route request
 func cacheKey(coordinates [][]float64, options OSRMOptions) string {
 	// Build a string representing coordinates
@@ -195,8 +216,10 @@ func GetRoute(ctx context.Context, coordinates [][]float64, options OSRMOptions)
 
 	// Check cache first
 	if cached, found := routeCache.Get(key); found {
-		logger.Debug("route cache hit", "key", key)
-		return cached, nil
+		if result, ok := cached.(*OSRMResult); ok {
+			logger.Debug("route cache hit", "key", key)
+			return result, nil
+		}
 	}
 
 	logger.Debug("route cache miss", "key", key)
@@ -213,7 +236,7 @@ func GetRoute(ctx context.Context, coordinates [][]float64, options OSRMOptions)
 
 	// Default BaseURL if not provided
 	if options.BaseURL == "" {
-		options.BaseURL = defaultOSRMBaseURL
+		options.BaseURL = osm.OSRMBaseURL
 	}
 
 	// Default Client if not provided
@@ -276,7 +299,7 @@ func GetRoute(ctx context.Context, coordinates [][]float64, options OSRMOptions)
 
 	// Parse the response
 	result := &OSRMResult{}
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+	if err := DecodeUpstream("OSRM", resp.Body, result, "code"); err != nil {
 		return nil, err
 	}
 
@@ -296,7 +319,7 @@ func GetRoute(ctx context.Context, coordinates [][]float64, options OSRMOptions)
 	}
 
 	// Cache the result
-	routeCache.Add(key, result)
+	routeCache.SetFor(cache.ClassRoute, key, result)
 
 	return result, nil
 }
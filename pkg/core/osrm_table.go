@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// MaxTableCoordinates is the most coordinates, sources and destinations
+// together, that the public OSRM server accepts in a table request
+const MaxTableCoordinates = 100
+
+// OSRMTable is a duration and distance matrix from the OSRM table service.
+// Entries are nil for pairs with no route.
+type OSRMTable struct {
+	Code         string         `json:"code"`
+	Message      string         `json:"message"`
+	Durations    [][]*float64   `json:"durations"` // seconds, [source][destination]
+	Distances    [][]*float64   `json:"distances"` // meters, [source][destination]
+	Sources      []OSRMWaypoint `json:"sources"`
+	Destinations []OSRMWaypoint `json:"destinations"`
+}
+
+// GetTable fetches travel durations and distances from each source to each
+// destination. Coordinates are [longitude, latitude], as for GetRoute. With
+// no destinations the table is between all the sources, each sent once.
+func GetTable(ctx context.Context, sources, destinations [][]float64, options OSRMOptions) (*OSRMTable, error) {
+	if len(sources) == 0 {
+		return nil, NewError(ErrInvalidInput, "A table needs at least one source")
+	}
+	if len(sources)+len(destinations) > MaxTableCoordinates {
+		return nil, NewError(ErrInvalidInput,
+			fmt.Sprintf("A table supports at most %d sources and destinations together", MaxTableCoordinates))
+	}
+
+	initCache()
+	coordinates := append(append([][]float64{}, sources...), destinations...)
+	key := "table:" + cacheKey(coordinates, options) + "|" + strconv.Itoa(len(sources))
+	if cached, found := routeCache.Get(key); found {
+		if table, ok := cached.(*OSRMTable); ok {
+			return table, nil
+		}
+	}
+
+	if options.BaseURL == "" {
+		options.BaseURL = osm.OSRMBaseURL
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	coordStrs := make([]string, len(coordinates))
+	for i, coord := range coordinates {
+		coordStrs[i] = fmt.Sprintf("%.6f,%.6f", coord[0], coord[1])
+	}
+	indices := func(from, to int) string {
+		parts := make([]string, 0, to-from)
+		for i := from; i < to; i++ {
+			parts = append(parts, strconv.Itoa(i))
+		}
+		return strings.Join(parts, ";")
+	}
+
+	reqURL, err := url.Parse(fmt.Sprintf("%s/table/v1/%s/%s",
+		strings.TrimRight(options.BaseURL, "/"), options.Profile, strings.Join(coordStrs, ";")))
+	if err != nil {
+		return nil, err
+	}
+	query := reqURL.Query()
+	if len(destinations) > 0 {
+		query.Add("sources", indices(0, len(sources)))
+		query.Add("destinations", indices(len(sources), len(coordinates)))
+	}
+	query.Add("annotations", "duration,distance")
+	reqURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "OSM-MCP-Client/1.0")
+
+	resp, err := WithRetry(ctx, req, options.Client, options.RetryOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	table := &OSRMTable{}
+	if err := DecodeUpstream("OSRM", resp.Body, table, "code"); err != nil {
+		return nil, err
+	}
+	if table.Code != "Ok" {
+		return nil, NewError(ErrServiceUnavailable, fmt.Sprintf("OSRM error: %s", table.Message)).
+			WithGuidance("The routing service encountered an error. Please check your coordinates and try again")
+	}
+	if len(table.Durations) != len(sources) {
+		return nil, NewError(ErrParseError, "OSRM table has the wrong number of rows").
+			WithGuidance("The routing service returned an unexpected response; try again later")
+	}
+
+	routeCache.SetFor(cache.ClassRoute, key, table)
+	return table, nil
+}
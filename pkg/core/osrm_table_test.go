@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTable(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		if r.URL.Path != "/table/v1/foot/2.352200,48.856600;2.337600,48.860600;2.349900,48.853000" ||
+			query.Get("sources") != "0;1" || query.Get("destinations") != "2" ||
+			query.Get("annotations") != "duration,distance" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"code":"Ok","durations":[[120.5],[null]],"distances":[[150.2],[null]]}`))
+	}))
+	defer server.Close()
+	resetRouteCache()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+	options.Profile = "foot"
+	options.RetryOptions.MaxAttempts = 1
+
+	sources := [][]float64{{2.3522, 48.8566}, {2.3376, 48.8606}}
+	destinations := [][]float64{{2.3499, 48.8530}}
+	for i := 0; i < 2; i++ {
+		table, err := GetTable(context.Background(), sources, destinations, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *table.Durations[0][0] != 120.5 || *table.Distances[0][0] != 150.2 || table.Durations[1][0] != nil {
+			t.Errorf("unexpected table %+v", table)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the second table to come from the cache, got %d requests", requests)
+	}
+
+	if _, err := GetTable(context.Background(), nil, destinations, options); err == nil {
+		t.Error("expected an error without sources")
+	}
+	if _, err := GetTable(context.Background(), make([][]float64, 60), make([][]float64, 41), options); err == nil {
+		t.Error("expected an error for too many coordinates")
+	}
+}
+
+func TestGetTableAllPairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/table/v1/foot/2.352200,48.856600;2.337600,48.860600" ||
+			query.Has("sources") || query.Has("destinations") {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"code":"Ok","durations":[[0,60],[61,0]],"distances":[[0,80],[82,0]]}`))
+	}))
+	defer server.Close()
+	resetRouteCache()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+	options.Profile = "foot"
+	options.RetryOptions.MaxAttempts = 1
+
+	table, err := GetTable(context.Background(), [][]float64{{2.3522, 48.8566}, {2.3376, 48.8606}}, nil, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Durations) != 2 || *table.Durations[1][0] != 61 {
+		t.Errorf("unexpected table %+v", table)
+	}
+}
+
+func TestRouteCodec(t *testing.T) {
+	for key, value := range map[string]interface{}{
+		"route":     &OSRMResult{Code: "Ok", Routes: []OSRMRoute{{Distance: 1200}}},
+		"table:x|1": &OSRMTable{Code: "Ok"},
+	} {
+		data, err := routeCodec.Encode(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := routeCodec.Decode(key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprintf("%T", decoded) != fmt.Sprintf("%T", value) {
+			t.Errorf("%s: decoded %T, want %T", key, decoded, value)
+		}
+	}
+}
@@ -13,7 +13,7 @@ const mockOSRMResponse = `{"code":"Ok","routes":[{"distance":100,"duration":10,"
 
 func resetRouteCache() {
 	initCache()
-	routeCache.Purge()
+	routeCache.Clear()
 }
 
 func newMockServer() (*httptest.Server, *int) {
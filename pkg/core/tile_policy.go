@@ -0,0 +1,240 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/NERVsystems/osmmcp/pkg/monitoring"
+)
+
+// Defaults chosen to stay well inside the OSMF tile usage policy
+// (https://operations.osmfoundation.org/policies/tiles/).
+const (
+	// DefaultTileRPS is the default sustained rate of upstream tile requests
+	DefaultTileRPS = 2.0
+
+	// DefaultTileBurst is the default burst size for upstream tile requests
+	DefaultTileBurst = 4
+
+	// DefaultTileHourlyLimit is the number of upstream tile fetches per hour
+	// above which the OSMF tile server may no longer be used
+	DefaultTileHourlyLimit = 1000
+
+	// tileWarnRatio is the fraction of the hourly limit at which warnings start
+	tileWarnRatio = 0.8
+)
+
+// TilePolicyConfig configures the tile provider and its usage limits
+type TilePolicyConfig struct {
+	// URL is the tile provider. It is either a base URL to which
+	// "/{z}/{x}/{y}.png" is appended, or a template containing {z}, {x}, {y}
+	// and optionally {apikey} placeholders.
+	URL string
+
+	// APIKey is substituted for {apikey} in templated URLs
+	APIKey string
+
+	// RPS and Burst throttle upstream tile requests
+	RPS   float64
+	Burst int
+
+	// HourlyLimit caps upstream fetches per hour when the OSMF tile server
+	// is used without an API key. Zero disables the cap.
+	HourlyLimit int
+}
+
+// DefaultTilePolicyConfig returns the policy used when nothing is configured
+func DefaultTilePolicyConfig() TilePolicyConfig {
+	return TilePolicyConfig{
+		URL:         DefaultTileProvider,
+		RPS:         DefaultTileRPS,
+		Burst:       DefaultTileBurst,
+		HourlyLimit: DefaultTileHourlyLimit,
+	}
+}
+
+// tilePolicy enforces throttling and volume limits for upstream tile fetches
+type tilePolicy struct {
+	mu      sync.Mutex
+	config  TilePolicyConfig
+	limiter *rate.Limiter
+
+	// fetches holds upstream fetch times within the last hour
+	fetches []time.Time
+	warned  bool
+	now     func() time.Time
+}
+
+var (
+	tilePolicyMu     sync.RWMutex
+	activeTilePolicy = newTilePolicy(DefaultTilePolicyConfig())
+)
+
+// newTilePolicy creates a tile policy from a config, filling in defaults
+func newTilePolicy(cfg TilePolicyConfig) *tilePolicy {
+	defaults := DefaultTilePolicyConfig()
+	if cfg.URL == "" {
+		cfg.URL = defaults.URL
+	}
+	if cfg.RPS <= 0 {
+		cfg.RPS = defaults.RPS
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = defaults.Burst
+	}
+	if cfg.HourlyLimit < 0 {
+		cfg.HourlyLimit = 0
+	}
+
+	return &tilePolicy{
+		config:  cfg,
+		limiter: rate.NewLimiter(rate.Limit(cfg.RPS), cfg.Burst),
+		now:     time.Now,
+	}
+}
+
+// ConfigureTilePolicy replaces the tile provider and usage limits
+func ConfigureTilePolicy(cfg TilePolicyConfig) error {
+	if cfg.URL != "" && !strings.HasPrefix(cfg.URL, "https://") && !strings.HasPrefix(cfg.URL, "http://") {
+		return fmt.Errorf("tile URL must be an http(s) URL: %q", cfg.URL)
+	}
+	if strings.Contains(cfg.URL, "{apikey}") && cfg.APIKey == "" {
+		return fmt.Errorf("tile URL %q requires an API key", cfg.URL)
+	}
+
+	policy := newTilePolicy(cfg)
+
+	tilePolicyMu.Lock()
+	activeTilePolicy = policy
+	tilePolicyMu.Unlock()
+
+	if policy.usesOSMF() {
+		slog.Default().Info("using OpenStreetMap Foundation tile server; heavy use requires an alternate tile provider",
+			"hourly_limit", policy.config.HourlyLimit, "rps", policy.config.RPS)
+	}
+	return nil
+}
+
+// getTilePolicy returns the active tile policy
+func getTilePolicy() *tilePolicy {
+	tilePolicyMu.RLock()
+	defer tilePolicyMu.RUnlock()
+	return activeTilePolicy
+}
+
+// TileUsageStatus is a snapshot of upstream tile throttling
+type TileUsageStatus struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	TokensAvailable   float64 `json:"tokens_available"`
+	FetchesLastHour   int     `json:"fetches_last_hour"`
+	HourlyLimit       int     `json:"hourly_limit,omitempty"` // enforced for the OSMF tile server only
+}
+
+// TileUsage returns the current state of upstream tile throttling
+func TileUsage() TileUsageStatus {
+	p := getTilePolicy()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cutoff := p.now().Add(-time.Hour)
+	count := 0
+	for _, t := range p.fetches {
+		if t.After(cutoff) {
+			count++
+		}
+	}
+
+	status := TileUsageStatus{
+		RequestsPerSecond: p.config.RPS,
+		Burst:             p.config.Burst,
+		TokensAvailable:   math.Max(p.limiter.Tokens(), 0),
+		FetchesLastHour:   count,
+	}
+	if p.usesOSMF() {
+		status.HourlyLimit = p.config.HourlyLimit
+	}
+	return status
+}
+
+// usesOSMF reports whether tiles come from the OSMF servers without an API key
+func (p *tilePolicy) usesOSMF() bool {
+	return strings.Contains(p.config.URL, "tile.openstreetmap.org") && p.config.APIKey == ""
+}
+
+// tileURL builds the URL for a tile. The API key is only included when
+// withKey is set so that URLs shown to clients never leak it.
+func (p *tilePolicy) tileURL(x, y, zoom int, withKey bool) string {
+	base := p.config.URL
+	if !strings.Contains(base, "{z}") {
+		return fmt.Sprintf("%s/%d/%d/%d.png", strings.TrimRight(base, "/"), zoom, x, y)
+	}
+
+	key := ""
+	if withKey {
+		key = p.config.APIKey
+	}
+	return strings.NewReplacer(
+		"{z}", fmt.Sprint(zoom),
+		"{x}", fmt.Sprint(x),
+		"{y}", fmt.Sprint(y),
+		"{apikey}", key,
+	).Replace(base)
+}
+
+// acquire reserves an upstream fetch. It enforces the hourly cap for the OSMF
+// servers, emits warnings as the cap approaches, and waits for the throttle.
+func (p *tilePolicy) acquire(ctx context.Context) error {
+	p.mu.Lock()
+	now := p.now()
+	cutoff := now.Add(-time.Hour)
+	kept := p.fetches[:0]
+	for _, t := range p.fetches {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	p.fetches = kept
+	count := len(p.fetches)
+	limit := p.config.HourlyLimit
+	enforce := p.usesOSMF() && limit > 0
+
+	if enforce && count >= limit {
+		p.mu.Unlock()
+		monitoring.RecordTilePolicyEvent("blocked")
+		return NewError(ErrRateLimit, "Tile request volume exceeds the OpenStreetMap tile usage policy").
+			WithGuidance("Configure an alternate tile provider (--tile-url) or an API key (--tile-api-key) for this volume; cached tiles remain available")
+	}
+
+	if enforce && float64(count) >= tileWarnRatio*float64(limit) {
+		if !p.warned {
+			p.warned = true
+			slog.Default().Warn("approaching OpenStreetMap tile usage policy limit",
+				"fetches_last_hour", count, "hourly_limit", limit)
+		}
+		monitoring.RecordTilePolicyEvent("warning")
+	} else {
+		p.warned = false
+	}
+
+	p.fetches = append(p.fetches, now)
+	monitoring.UpdateTileFetchesLastHour(len(p.fetches))
+	p.mu.Unlock()
+
+	start := time.Now()
+	if err := p.limiter.Wait(ctx); err != nil {
+		return NewError(ErrServiceTimeout, "Timed out waiting for tile request slot")
+	}
+	if waited := time.Since(start); waited > time.Millisecond {
+		monitoring.RecordRateLimitWait("tiles", waited)
+		monitoring.RecordTilePolicyEvent("throttled")
+	}
+	return nil
+}
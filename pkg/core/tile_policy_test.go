@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTilePolicyURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TilePolicyConfig
+		withKey bool
+		want    string
+	}{
+		{
+			name:   "default provider",
+			config: DefaultTilePolicyConfig(),
+			want:   "https://tile.openstreetmap.org/12/1205/1539.png",
+		},
+		{
+			name:   "base URL with trailing slash",
+			config: TilePolicyConfig{URL: "https://tiles.example.com/"},
+			want:   "https://tiles.example.com/12/1205/1539.png",
+		},
+		{
+			name:    "template with key",
+			config:  TilePolicyConfig{URL: "https://tiles.example.com/{z}/{x}/{y}.png?key={apikey}", APIKey: "secret"},
+			withKey: true,
+			want:    "https://tiles.example.com/12/1205/1539.png?key=secret",
+		},
+		{
+			name:   "template hides key",
+			config: TilePolicyConfig{URL: "https://tiles.example.com/{z}/{x}/{y}.png?key={apikey}", APIKey: "secret"},
+			want:   "https://tiles.example.com/12/1205/1539.png?key=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTilePolicy(tt.config)
+			if got := p.tileURL(1205, 1539, 12, tt.withKey); got != tt.want {
+				t.Errorf("tileURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTilePolicyHourlyLimit(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	p := newTilePolicy(TilePolicyConfig{RPS: 1000, Burst: 100, HourlyLimit: 5})
+	p.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := p.acquire(ctx); err != nil {
+			t.Fatalf("acquire %d: unexpected error: %v", i, err)
+		}
+	}
+
+	err := p.acquire(ctx)
+	if err == nil {
+		t.Fatal("expected acquire to fail once the hourly limit is reached")
+	}
+	var mcpErr *MCPError
+	if !errors.As(err, &mcpErr) || mcpErr.Code != string(ErrRateLimit) {
+		t.Errorf("expected %s error, got %v", ErrRateLimit, err)
+	}
+
+	// Fetches older than an hour no longer count against the limit
+	now = now.Add(time.Hour + time.Second)
+	if err := p.acquire(ctx); err != nil {
+		t.Errorf("expected acquire to succeed after the window slid, got %v", err)
+	}
+}
+
+func TestTilePolicyAlternateProviderNotCapped(t *testing.T) {
+	configs := []TilePolicyConfig{
+		{URL: "https://tiles.example.com", RPS: 1000, Burst: 100, HourlyLimit: 2},
+		{URL: DefaultTileProvider, APIKey: "secret", RPS: 1000, Burst: 100, HourlyLimit: 2},
+	}
+
+	for _, cfg := range configs {
+		p := newTilePolicy(cfg)
+		for i := 0; i < 5; i++ {
+			if err := p.acquire(context.Background()); err != nil {
+				t.Fatalf("%s: acquire %d: unexpected error: %v", cfg.URL, i, err)
+			}
+		}
+	}
+}
+
+func TestConfigureTilePolicy(t *testing.T) {
+	defer func() {
+		_ = ConfigureTilePolicy(DefaultTilePolicyConfig())
+	}()
+
+	if err := ConfigureTilePolicy(TilePolicyConfig{URL: "ftp://tiles.example.com"}); err == nil {
+		t.Error("expected error for non-http tile URL")
+	}
+	if err := ConfigureTilePolicy(TilePolicyConfig{URL: "https://tiles.example.com/{z}/{x}/{y}.png?key={apikey}"}); err == nil {
+		t.Error("expected error for templated URL without API key")
+	}
+
+	if err := ConfigureTilePolicy(TilePolicyConfig{URL: "https://tiles.example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetTileInfo(0, 0, 0).TileURL; got != "https://tiles.example.com/0/0/0.png" {
+		t.Errorf("GetTileInfo().TileURL = %q, want configured provider", got)
+	}
+}
@@ -3,6 +3,7 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -13,6 +14,7 @@ import (
 	"log/slog"
 
 	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/monitoring"
 )
 
 const (
@@ -22,8 +24,13 @@ const (
 	// DefaultTileSize is the size of OSM tiles in pixels
 	DefaultTileSize = 256
 
-	// TileCacheTTL is how long to cache tiles
-	TileCacheTTL = 24 * time.Hour
+	// TileCacheTTL is how long to cache tiles. The OSMF tile usage policy
+	// requires tiles to be cached for at least seven days.
+	TileCacheTTL = 7 * 24 * time.Hour
+
+	// tileRetentionFactor is how many freshness periods a tile is kept for
+	// revalidation after it goes stale
+	tileRetentionFactor = 4
 )
 
 // TileCache is the cache for map tiles
@@ -36,7 +43,11 @@ var tileResourceManager *cache.TileResourceManager
 func InitTileCache() {
 	// Use the existing cache implementation
 	if tileCache == nil {
-		tileCache = cache.NewTTLCache(TileCacheTTL, time.Minute, 1000)
+		// Entries outlive their freshness so stale tiles keep the validators
+		// needed for a conditional request
+		tileCache = cache.NewTTLCache(tileFreshness()*tileRetentionFactor, time.Minute, cache.MaxEntriesFor("tiles", 1000))
+		cache.Register("tiles", tileCache)
+		tileCache.Persist(cache.DefaultBackend(), "tiles", cache.JSONCodec[*tileEntry]())
 	}
 }
 
@@ -113,29 +124,36 @@ func FetchMapTile(ctx context.Context, x, y, zoom int) ([]byte, error) {
 	// Create cache key for legacy cache
 	cacheKey := fmt.Sprintf("tile:%d:%d:%d", zoom, x, y)
 
-	// Check legacy cache first
-	if cachedData, found := tileCache.Get(cacheKey); found {
-		logger.Debug("tile cache hit", "key", cacheKey)
-		tileData := cachedData.([]byte)
-
-		// Update resource manager if available
-		if tileResourceManager != nil {
-			uri := fmt.Sprintf("osm://tile/%d/%d/%d", zoom, x, y)
-			err := tileResourceManager.SetTileData(uri, tileData)
-			if err != nil {
-				logger.Warn("failed to update tile resource", "error", err)
-			}
+	// Serve fresh tiles from the legacy cache
+	var stale *tileEntry
+	if cached, found := tileCache.Get(cacheKey); found {
+		entry := cached.(*tileEntry)
+		if entry.fresh() {
+			logger.Debug("tile cache hit", "key", cacheKey)
+			updateTileResource(logger, x, y, zoom, entry.data)
+			return entry.data, nil
 		}
-
-		return tileData, nil
+		stale = entry
 	}
 
-	logger.Debug("tile cache miss", "key", cacheKey)
+	logger.Debug("tile cache miss", "key", cacheKey, "stale", stale != nil)
+
+	// Enforce the tile usage policy before going upstream
+	policy := getTilePolicy()
+	if err := policy.acquire(ctx); err != nil {
+		if stale != nil {
+			logger.Warn("tile fetch refused by usage policy, serving stale tile", "x", x, "y", y, "zoom", zoom, "error", err)
+			return stale.data, nil
+		}
+		logger.Warn("tile fetch refused by usage policy", "x", x, "y", y, "zoom", zoom, "error", err)
+		return nil, err
+	}
 
 	// Build the tile URL
-	tileURL := fmt.Sprintf("%s/%d/%d/%d.png", DefaultTileProvider, zoom, x, y)
+	tileURL := policy.tileURL(x, y, zoom, true)
 
-	// Create HTTP request with retry factory
+	// Create HTTP request with retry factory. Stale tiles are revalidated
+	// with a conditional GET so unchanged tiles are not downloaded again.
 	requestFactory := func() (*http.Request, error) {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, tileURL, nil)
 		if err != nil {
@@ -143,16 +161,37 @@ func FetchMapTile(ctx context.Context, x, y, zoom int) ([]byte, error) {
 		}
 		req.Header.Set("User-Agent", "NERV-MCP-Client/1.0 (contact: ops@nerv.systems)")
 		req.Header.Set("Referer", "https://github.com/NERVsystems/osmmcp")
+		if stale != nil {
+			if stale.etag != "" {
+				req.Header.Set("If-None-Match", stale.etag)
+			}
+			if stale.lastModified != "" {
+				req.Header.Set("If-Modified-Since", stale.lastModified)
+			}
+		}
 		return req, nil
 	}
 
 	// Execute request with retries
 	resp, err := WithRetryFactory(ctx, requestFactory, nil, DefaultRetryOptions)
 	if err != nil {
+		if stale != nil {
+			logger.Warn("tile revalidation failed, serving stale tile", "key", cacheKey, "error", err)
+			return stale.data, nil
+		}
 		return nil, ServiceError("TileServer", http.StatusServiceUnavailable, "Failed to fetch map tile")
 	}
 	defer resp.Body.Close()
 
+	// The cached tile is still current
+	if resp.StatusCode == http.StatusNotModified && stale != nil {
+		logger.Debug("tile not modified", "key", cacheKey)
+		monitoring.RecordTileRevalidation(true)
+		tileCache.Set(cacheKey, stale.revalidated(resp.Header))
+		updateTileResource(logger, x, y, zoom, stale.data)
+		return stale.data, nil
+	}
+
 	// Handle error response
 	if resp.StatusCode != http.StatusOK {
 		return nil, ServiceError("TileServer", resp.StatusCode, fmt.Sprintf("Tile server error: %d", resp.StatusCode))
@@ -163,24 +202,102 @@ func FetchMapTile(ctx context.Context, x, y, zoom int) ([]byte, error) {
 	if err != nil {
 		return nil, NewError(ErrInternalError, "Failed to read tile data")
 	}
+	if stale != nil {
+		monitoring.RecordTileRevalidation(false)
+	}
 
 	// Cache the result in legacy cache
-	tileCache.Set(cacheKey, tileData)
+	tileCache.Set(cacheKey, newTileEntry(tileData, resp.Header))
 
 	// Cache as resource if resource manager is available
-	if tileResourceManager != nil {
-		uri := fmt.Sprintf("osm://tile/%d/%d/%d", zoom, x, y)
-		err := tileResourceManager.SetTileData(uri, tileData)
-		if err != nil {
-			logger.Warn("failed to cache tile as resource", "error", err)
-		} else {
-			logger.Debug("tile cached as resource", "uri", uri)
-		}
-	}
+	updateTileResource(logger, x, y, zoom, tileData)
 
 	return tileData, nil
 }
 
+// tileEntry is a cached tile with the validators needed to revalidate it
+type tileEntry struct {
+	data         []byte
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+	freshFor     time.Duration
+}
+
+// persistedTile is the form of a tileEntry in a persistent cache
+type persistedTile struct {
+	Data         []byte        `json:"data"`
+	ETag         string        `json:"etag,omitempty"`
+	LastModified string        `json:"last_modified,omitempty"`
+	FetchedAt    time.Time     `json:"fetched_at"`
+	FreshFor     time.Duration `json:"fresh_for"`
+}
+
+// MarshalJSON encodes the entry for a persistent cache
+func (e *tileEntry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(persistedTile{e.data, e.etag, e.lastModified, e.fetchedAt, e.freshFor})
+}
+
+// UnmarshalJSON decodes an entry from a persistent cache
+func (e *tileEntry) UnmarshalJSON(data []byte) error {
+	var p persistedTile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*e = tileEntry{data: p.Data, etag: p.ETag, lastModified: p.LastModified, fetchedAt: p.FetchedAt, freshFor: p.FreshFor}
+	return nil
+}
+
+// newTileEntry creates a cache entry for a tile response
+func newTileEntry(data []byte, header http.Header) *tileEntry {
+	return &tileEntry{
+		data:         data,
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+		fetchedAt:    time.Now(),
+		freshFor:     tileFreshness(),
+	}
+}
+
+// revalidated returns a copy of the entry refreshed by a 304 response, which
+// may carry updated validators
+func (e *tileEntry) revalidated(header http.Header) *tileEntry {
+	updated := *e
+	if etag := header.Get("ETag"); etag != "" {
+		updated.etag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		updated.lastModified = lastModified
+	}
+	updated.fetchedAt = time.Now()
+	updated.freshFor = tileFreshness()
+	return &updated
+}
+
+// fresh reports whether the tile can be served without revalidation
+func (e *tileEntry) fresh() bool {
+	return time.Since(e.fetchedAt) < e.freshFor
+}
+
+// tileFreshness is how long a fetched tile is served before revalidation.
+// It never drops below the seven days the OSMF tile usage policy requires.
+func tileFreshness() time.Duration {
+	return max(cache.TTLFor(cache.ClassTile), TileCacheTTL)
+}
+
+// updateTileResource stores tile data with the resource manager, if any
+func updateTileResource(logger *slog.Logger, x, y, zoom int, tileData []byte) {
+	if tileResourceManager == nil {
+		return
+	}
+	uri := fmt.Sprintf("osm://tile/%d/%d/%d", zoom, x, y)
+	if err := tileResourceManager.SetTileData(uri, tileData); err != nil {
+		logger.Warn("failed to cache tile as resource", "error", err)
+	} else {
+		logger.Debug("tile cached as resource", "uri", uri)
+	}
+}
+
 // TileInfo contains information about a map tile
 type TileInfo struct {
 	Zoom      int     `json:"zoom"`
@@ -207,12 +324,7 @@ func GetTileInfo(x, y, zoom int) TileInfo {
 	northLat, westLon := TileToLatLon(x, y, zoom)
 	southLat, eastLon := TileToLatLon(x+1, y+1, zoom)
 
-	// Calculate approximate meters per pixel at this latitude
-	metersPerPixel := 156543.03 * math.Cos(centerLat*math.Pi/180) / math.Pow(2, float64(zoom))
-
-	// Calculate approximate map scale (1:X)
-	// Assuming 96 DPI (1 pixel = 0.26 mm)
-	mapScale := metersPerPixel / 0.00026
+	metersPerPixel, mapScale := GroundResolution(centerLat, zoom)
 
 	return TileInfo{
 		Zoom:      zoom,
@@ -224,8 +336,19 @@ func GetTileInfo(x, y, zoom int) TileInfo {
 		SouthLat:  southLat,
 		EastLon:   eastLon,
 		WestLon:   westLon,
-		TileURL:   fmt.Sprintf("%s/%d/%d/%d.png", DefaultTileProvider, zoom, x, y),
+		TileURL:   getTilePolicy().tileURL(x, y, zoom, false),
 		PixelSize: metersPerPixel,
-		MapScale:  "1:" + strconv.FormatInt(int64(math.Round(mapScale)), 10),
+		MapScale:  mapScale,
 	}
 }
+
+// GroundResolution returns the approximate meters per pixel of 256-pixel
+// tiles at a latitude and zoom level, and the matching map scale (e.g.
+// "1:10000")
+func GroundResolution(lat float64, zoom int) (metersPerPixel float64, mapScale string) {
+	metersPerPixel = 156543.03 * math.Cos(lat*math.Pi/180) / math.Pow(2, float64(zoom))
+
+	// Assuming 96 DPI (1 pixel = 0.26 mm)
+	scale := metersPerPixel / 0.00026
+	return metersPerPixel, "1:" + strconv.FormatInt(int64(math.Round(scale)), 10)
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchMapTileRevalidatesWithETag(t *testing.T) {
+	var fullResponses, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 06 Jan 2025 10:00:00 GMT")
+		w.Write([]byte("tile-data"))
+	}))
+	defer server.Close()
+
+	if err := ConfigureTilePolicy(TilePolicyConfig{URL: server.URL, RPS: 1000, Burst: 100}); err != nil {
+		t.Fatalf("configure tile policy: %v", err)
+	}
+	defer func() {
+		_ = ConfigureTilePolicy(DefaultTilePolicyConfig())
+	}()
+
+	ctx := context.Background()
+	x, y, zoom := 4711, 2342, 13
+	cacheKey := fmt.Sprintf("tile:%d:%d:%d", zoom, x, y)
+
+	data, err := FetchMapTile(ctx, x, y, zoom)
+	if err != nil || string(data) != "tile-data" {
+		t.Fatalf("first fetch: got %q, %v", data, err)
+	}
+
+	// A fresh tile is served without contacting the server
+	if _, err := FetchMapTile(ctx, x, y, zoom); err != nil {
+		t.Fatalf("cached fetch: %v", err)
+	}
+	if fullResponses.Load() != 1 || notModified.Load() != 0 {
+		t.Fatalf("expected one upstream request, got %d full and %d conditional", fullResponses.Load(), notModified.Load())
+	}
+
+	// Once stale, the tile is revalidated rather than downloaded again
+	cached, _ := tileCache.Get(cacheKey)
+	entry := cached.(*tileEntry)
+	entry.fetchedAt = time.Now().Add(-2 * entry.freshFor)
+
+	data, err = FetchMapTile(ctx, x, y, zoom)
+	if err != nil || string(data) != "tile-data" {
+		t.Fatalf("revalidated fetch: got %q, %v", data, err)
+	}
+	if fullResponses.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("expected a 304 revalidation, got %d full and %d conditional", fullResponses.Load(), notModified.Load())
+	}
+
+	cached, _ = tileCache.Get(cacheKey)
+	if !cached.(*tileEntry).fresh() {
+		t.Errorf("expected tile to be fresh after revalidation")
+	}
+}
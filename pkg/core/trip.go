@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// MaxTripStops is the largest number of stops OSRM's public trip service
+// will reliably order in one request
+const MaxTripStops = 25
+
+// TripOptions controls how OSRM orders the stops of a trip
+type TripOptions struct {
+	// Roundtrip returns to the first stop. When false the trip starts at the
+	// first stop and ends at the last one, which is the only open-trip
+	// combination OSRM supports.
+	Roundtrip bool
+}
+
+// OSRMTripWaypoint is an input stop as placed in the optimized trip
+type OSRMTripWaypoint struct {
+	OSRMWaypoint
+	WaypointIndex int `json:"waypoint_index"` // position of the stop in the trip
+	TripsIndex    int `json:"trips_index"`    // which trip the stop belongs to
+}
+
+// OSRMTripResult represents the response from the OSRM trip service
+type OSRMTripResult struct {
+	Code      string             `json:"code"`
+	Message   string             `json:"message"`
+	Trips     []OSRMRoute        `json:"trips"`
+	Waypoints []OSRMTripWaypoint `json:"waypoints"` // in input order
+}
+
+// GetTrip solves the traveling salesman problem over coordinates ([lon, lat])
+// using the OSRM trip service. Only the connection settings, profile,
+// geometry and step options are used from options.
+func GetTrip(ctx context.Context, coordinates [][]float64, options OSRMOptions, trip TripOptions) (*OSRMTripResult, error) {
+	if len(coordinates) < 2 || len(coordinates) > MaxTripStops {
+		return nil, NewError(ErrInvalidParameter, fmt.Sprintf("a trip needs between 2 and %d stops", MaxTripStops))
+	}
+
+	var coordStr strings.Builder
+	for i, coord := range coordinates {
+		if i > 0 {
+			coordStr.WriteString(";")
+		}
+		coordStr.WriteString(fmt.Sprintf("%.6f,%.6f", coord[0], coord[1]))
+	}
+
+	if options.BaseURL == "" {
+		options.BaseURL = osm.OSRMBaseURL
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	reqURL, err := url.Parse(fmt.Sprintf("%s/trip/v1/%s/%s",
+		strings.TrimRight(options.BaseURL, "/"),
+		options.Profile,
+		coordStr.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	query := reqURL.Query()
+	query.Add("overview", options.Overview)
+	query.Add("steps", fmt.Sprintf("%v", options.Steps))
+	query.Add("geometries", options.Geometries)
+	query.Add("roundtrip", fmt.Sprintf("%v", trip.Roundtrip))
+	query.Add("source", "first")
+	if !trip.Roundtrip {
+		query.Add("destination", "last")
+	}
+	reqURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "OSM-MCP-Client/1.0")
+
+	resp, err := WithRetry(ctx, req, options.Client, options.RetryOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &OSRMTripResult{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != "Ok" {
+		return nil, NewError(ErrServiceUnavailable, fmt.Sprintf("OSRM error: %s", result.Message)).
+			WithGuidance("The routing service could not order these stops. Check that every stop is reachable by the selected mode of transport")
+	}
+	if len(result.Trips) == 0 || len(result.Waypoints) != len(coordinates) {
+		return nil, NewError(ErrNoResults, "no trip found").
+			WithGuidance("No trip could be calculated through these stops")
+	}
+
+	return result, nil
+}
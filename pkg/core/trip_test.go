@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const mockTripResponse = `{"code":"Ok","trips":[{"distance":3000,"duration":300,"geometry":"_p~iF~ps|U","legs":[{"distance":1000,"duration":100},{"distance":1000,"duration":100},{"distance":1000,"duration":100}]}],"waypoints":[{"location":[0,0],"waypoint_index":0,"trips_index":0},{"location":[0.02,0],"waypoint_index":2,"trips_index":0},{"location":[0.01,0],"waypoint_index":1,"trips_index":0}]}`
+
+func TestGetTrip(t *testing.T) {
+	var gotPath, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockTripResponse))
+	}))
+	defer server.Close()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+	options.RetryOptions.MaxAttempts = 1
+
+	coords := [][]float64{{0, 0}, {0.02, 0}, {0.01, 0}}
+	result, err := GetTrip(context.Background(), coords, options, TripOptions{Roundtrip: true})
+	if err != nil {
+		t.Fatalf("GetTrip() error: %v", err)
+	}
+
+	if !strings.HasPrefix(gotPath, "/trip/v1/car/") {
+		t.Errorf("unexpected path %s", gotPath)
+	}
+	if !strings.Contains(gotQuery, "roundtrip=true") || !strings.Contains(gotQuery, "source=first") || strings.Contains(gotQuery, "destination") {
+		t.Errorf("unexpected query %s", gotQuery)
+	}
+	if result.Waypoints[1].WaypointIndex != 2 || len(result.Trips[0].Legs) != 3 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	// Open trips pin the last stop as the destination
+	if _, err := GetTrip(context.Background(), coords, options, TripOptions{}); err != nil {
+		t.Fatalf("GetTrip() error: %v", err)
+	}
+	if !strings.Contains(gotQuery, "roundtrip=false") || !strings.Contains(gotQuery, "destination=last") {
+		t.Errorf("unexpected query %s", gotQuery)
+	}
+}
+
+func TestGetTripErrors(t *testing.T) {
+	server, _ := newErrorServer(http.StatusOK)
+	defer server.Close()
+
+	options := DefaultOSRMOptions()
+	options.BaseURL = server.URL
+	options.RetryOptions.MaxAttempts = 1
+
+	if _, err := GetTrip(context.Background(), [][]float64{{0, 0}}, options, TripOptions{}); err == nil {
+		t.Error("expected error for a single stop")
+	}
+	if _, err := GetTrip(context.Background(), [][]float64{{0, 0}, {1, 1}}, options, TripOptions{}); err == nil {
+		t.Error("expected error for an OSRM error code")
+	}
+}
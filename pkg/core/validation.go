@@ -9,42 +9,17 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
 )
 
 // ValidationError represents a validation error with a specific code and message
-type ValidationError struct {
-	Code    string
-	Message string
-}
+type ValidationError = geo.ValidationError
 
-func (e ValidationError) Error() string {
-	return fmt.Sprintf("%s: %s", e.Code, e.Message)
-}
-
-// ValidateCoords validates latitude and longitude coordinates
+// ValidateCoords validates latitude and longitude coordinates, rejecting NaN
+// and infinite values. It defers to geo.ValidateCoords.
 func ValidateCoords(lat, lon float64) error {
-	if math.IsNaN(lat) || math.IsNaN(lon) {
-		return ValidationError{
-			Code:    "INVALID_COORDINATES",
-			Message: "Coordinates must be valid numbers",
-		}
-	}
-
-	if lat < -90 || lat > 90 {
-		return ValidationError{
-			Code:    "INVALID_LATITUDE",
-			Message: "Latitude must be between -90 and 90 degrees",
-		}
-	}
-
-	if lon < -180 || lon > 180 {
-		return ValidationError{
-			Code:    "INVALID_LONGITUDE",
-			Message: "Longitude must be between -180 and 180 degrees",
-		}
-	}
-
-	return nil
+	return geo.ValidateCoords(lat, lon)
 }
 
 // ValidateRadius validates a search radius
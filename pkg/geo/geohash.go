@@ -0,0 +1,157 @@
+package geo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxGeohashPrecision is the longest geohash supported (about 3.7cm cells)
+const MaxGeohashPrecision = 12
+
+// geohashAlphabet is the base32 alphabet used by geohash
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash encodes a coordinate as a geohash of the given precision
+// (number of characters, 1-12).
+func EncodeGeohash(lat, lon float64, precision int) (string, error) {
+	if precision < 1 || precision > MaxGeohashPrecision {
+		return "", fmt.Errorf("geohash precision must be between 1 and %d", MaxGeohashPrecision)
+	}
+	if err := ValidateCoords(lat, lon); err != nil {
+		return "", err
+	}
+
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	var hash strings.Builder
+	hash.Grow(precision)
+
+	bit, ch := 0, 0
+	even := true // bits alternate between longitude (even) and latitude
+	for hash.Len() < precision {
+		if even {
+			mid := (lonRange[0] + lonRange[1]) / 2
+			if lon >= mid {
+				ch |= 1 << (4 - bit)
+				lonRange[0] = mid
+			} else {
+				lonRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				latRange[0] = mid
+			} else {
+				latRange[1] = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+			continue
+		}
+		hash.WriteByte(geohashAlphabet[ch])
+		bit, ch = 0, 0
+	}
+
+	return hash.String(), nil
+}
+
+// DecodeGeohash returns the bounding box of a geohash cell
+func DecodeGeohash(hash string) (BoundingBox, error) {
+	if hash == "" || len(hash) > MaxGeohashPrecision {
+		return BoundingBox{}, fmt.Errorf("geohash must be 1 to %d characters", MaxGeohashPrecision)
+	}
+
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	even := true
+
+	for _, r := range strings.ToLower(hash) {
+		idx := strings.IndexRune(geohashAlphabet, r)
+		if idx < 0 {
+			return BoundingBox{}, fmt.Errorf("invalid geohash character %q", r)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := idx&(1<<bit) != 0
+			if even {
+				mid := (lonRange[0] + lonRange[1]) / 2
+				if set {
+					lonRange[0] = mid
+				} else {
+					lonRange[1] = mid
+				}
+			} else {
+				mid := (latRange[0] + latRange[1]) / 2
+				if set {
+					latRange[0] = mid
+				} else {
+					latRange[1] = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return BoundingBox{
+		MinLat: latRange[0],
+		MinLon: lonRange[0],
+		MaxLat: latRange[1],
+		MaxLon: lonRange[1],
+	}, nil
+}
+
+// Center returns the center point of the bounding box
+func (bb *BoundingBox) Center() Location {
+	return Location{
+		Latitude:  (bb.MinLat + bb.MaxLat) / 2,
+		Longitude: (bb.MinLon + bb.MaxLon) / 2,
+	}
+}
+
+// GeohashNeighbors returns the geohash cells within k cells of hash (a
+// (2k+1) x (2k+1) block, excluding hash itself), in row order from the
+// north-west. Cells wrap across the antimeridian; rows beyond a pole are
+// omitted.
+func GeohashNeighbors(hash string, k int) ([]string, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("ring size must be at least 1")
+	}
+	bbox, err := DecodeGeohash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	center := bbox.Center()
+	height := bbox.MaxLat - bbox.MinLat
+	width := bbox.MaxLon - bbox.MinLon
+
+	cells := make([]string, 0, (2*k+1)*(2*k+1)-1)
+	for dy := k; dy >= -k; dy-- {
+		lat := center.Latitude + float64(dy)*height
+		if lat > 90 || lat < -90 {
+			continue
+		}
+		for dx := -k; dx <= k; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			lon := center.Longitude + float64(dx)*width
+			for lon > 180 {
+				lon -= 360
+			}
+			for lon < -180 {
+				lon += 360
+			}
+			neighbor, err := EncodeGeohash(lat, lon, len(hash))
+			if err != nil {
+				return nil, err
+			}
+			cells = append(cells, neighbor)
+		}
+	}
+	return cells, nil
+}
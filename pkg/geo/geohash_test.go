@@ -0,0 +1,106 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodeGeohash(t *testing.T) {
+	tests := []struct {
+		name      string
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{"Jutland", 57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{"San Francisco", 37.7749, -122.4194, 6, "9q8yyk"},
+		{"Origin", 0, 0, 5, "s0000"},
+		{"South west corner", -90, -180, 4, "0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeGeohash(tt.lat, tt.lon, tt.precision)
+			if err != nil {
+				t.Fatalf("EncodeGeohash() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EncodeGeohash() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := EncodeGeohash(0, 0, 13); err == nil {
+		t.Error("expected error for precision 13")
+	}
+	if _, err := EncodeGeohash(91, 0, 5); err == nil {
+		t.Error("expected error for invalid latitude")
+	}
+}
+
+func TestDecodeGeohash(t *testing.T) {
+	bbox, err := DecodeGeohash("u4pruydqqvj")
+	if err != nil {
+		t.Fatalf("DecodeGeohash() error: %v", err)
+	}
+	center := bbox.Center()
+	if math.Abs(center.Latitude-57.64911) > 1e-5 || math.Abs(center.Longitude-10.40744) > 1e-5 {
+		t.Errorf("unexpected center %+v", center)
+	}
+
+	// Round trip: the encoded point lies inside the decoded cell
+	hash, _ := EncodeGeohash(37.7749, -122.4194, 7)
+	cell, err := DecodeGeohash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if 37.7749 < cell.MinLat || 37.7749 > cell.MaxLat || -122.4194 < cell.MinLon || -122.4194 > cell.MaxLon {
+		t.Errorf("point not inside decoded cell %+v", cell)
+	}
+
+	if _, err := DecodeGeohash("abc"); err == nil {
+		t.Error("expected error for invalid character 'a'")
+	}
+	if _, err := DecodeGeohash(""); err == nil {
+		t.Error("expected error for empty geohash")
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	cells, err := GeohashNeighbors("u4pruyd", 1)
+	if err != nil {
+		t.Fatalf("GeohashNeighbors() error: %v", err)
+	}
+
+	// Known neighbors of u4pruyd, north-west first
+	want := []string{"u4pruyc", "u4pruyf", "u4pruyg", "u4pruy9", "u4pruye", "u4pruy3", "u4pruy6", "u4pruy7"}
+	if len(cells) != len(want) {
+		t.Fatalf("got %d neighbors, want %d: %v", len(cells), len(want), cells)
+	}
+	for i := range want {
+		if cells[i] != want[i] {
+			t.Errorf("neighbor %d = %q, want %q", i, cells[i], want[i])
+		}
+	}
+
+	// Cells on the antimeridian wrap around
+	east, _ := EncodeGeohash(0.1, 179.99, 3)
+	wrapped, err := GeohashNeighbors(east, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	west, _ := EncodeGeohash(0.1, -179.99, 3)
+	found := false
+	for _, c := range wrapped {
+		if c == west {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected %s to neighbor %s across the antimeridian, got %v", west, east, wrapped)
+	}
+
+	if _, err := GeohashNeighbors("u4pruyd", 0); err == nil {
+		t.Error("expected error for k=0")
+	}
+}
@@ -0,0 +1,96 @@
+package geo
+
+import "math"
+
+// IntermediatePoint returns the point a fraction f (0-1) of the way along the
+// great circle from one location to another. The path between antipodal
+// points is undefined; callers should check with IsAntipodal first.
+func IntermediatePoint(from, to Location, f float64) Location {
+	lat1, lon1 := radians(from.Latitude), radians(from.Longitude)
+	lat2, lon2 := radians(to.Latitude), radians(to.Longitude)
+
+	d := HaversineDistance(from.Latitude, from.Longitude, to.Latitude, to.Longitude) / EarthRadius
+	if d == 0 {
+		return from
+	}
+
+	a := math.Sin((1-f)*d) / math.Sin(d)
+	b := math.Sin(f*d) / math.Sin(d)
+	x := a*math.Cos(lat1)*math.Cos(lon1) + b*math.Cos(lat2)*math.Cos(lon2)
+	y := a*math.Cos(lat1)*math.Sin(lon1) + b*math.Cos(lat2)*math.Sin(lon2)
+	z := a*math.Sin(lat1) + b*math.Sin(lat2)
+
+	return Location{
+		Latitude:  degrees(math.Atan2(z, math.Hypot(x, y))),
+		Longitude: degrees(math.Atan2(y, x)),
+	}
+}
+
+// GreatCirclePath returns n evenly spaced points along the great circle
+// between two locations, including both endpoints
+func GreatCirclePath(from, to Location, n int) []Location {
+	if n < 2 {
+		n = 2
+	}
+	path := make([]Location, n)
+	for i := range path {
+		path[i] = IntermediatePoint(from, to, float64(i)/float64(n-1))
+	}
+	// Keep the endpoints exact
+	path[0], path[n-1] = from, to
+	return path
+}
+
+// Midpoint returns the point halfway along the great circle between two locations
+func Midpoint(from, to Location) Location {
+	return IntermediatePoint(from, to, 0.5)
+}
+
+// Antipode returns the point on the opposite side of the Earth
+func Antipode(loc Location) Location {
+	lon := loc.Longitude + 180
+	if lon > 180 {
+		lon -= 360
+	}
+	return Location{Latitude: -loc.Latitude, Longitude: lon}
+}
+
+// IsAntipodal reports whether two locations are within about a meter of
+// being antipodal, in which case no unique great circle joins them
+func IsAntipodal(from, to Location) bool {
+	opposite := Antipode(from)
+	return HaversineDistance(opposite.Latitude, opposite.Longitude, to.Latitude, to.Longitude) < 1
+}
+
+// InitialBearing returns the initial great-circle bearing in degrees
+// clockwise from north for travel from one location to another
+func InitialBearing(from, to Location) float64 {
+	lat1, lat2 := radians(from.Latitude), radians(to.Latitude)
+	dLon := radians(to.Longitude - from.Longitude)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// FinalBearing returns the great-circle bearing in degrees clockwise from
+// north on arrival at the destination
+func FinalBearing(from, to Location) float64 {
+	return math.Mod(InitialBearing(to, from)+180, 360)
+}
+
+// DestinationPoint returns the location reached by travelling a distance in
+// meters along a great circle from a start location at an initial bearing
+// in degrees clockwise from north
+func DestinationPoint(from Location, bearing, distance float64) Location {
+	lat1, lon1 := radians(from.Latitude), radians(from.Longitude)
+	theta := radians(bearing)
+	delta := distance / EarthRadius
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+	return Location{
+		Latitude:  degrees(lat2),
+		Longitude: math.Mod(degrees(lon2)+540, 360) - 180,
+	}
+}
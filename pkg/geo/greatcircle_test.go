@@ -0,0 +1,92 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	london  = Location{Latitude: 51.4700, Longitude: -0.4543}  // LHR
+	newYork = Location{Latitude: 40.6413, Longitude: -73.7781} // JFK
+)
+
+func TestGreatCirclePath(t *testing.T) {
+	path := GreatCirclePath(london, newYork, 11)
+	if len(path) != 11 || path[0] != london || path[10] != newYork {
+		t.Fatalf("unexpected path endpoints %+v", path)
+	}
+
+	// Points are evenly spaced and the path bulges north of both endpoints
+	total := HaversineDistance(london.Latitude, london.Longitude, newYork.Latitude, newYork.Longitude)
+	for i := 1; i < len(path); i++ {
+		step := HaversineDistance(path[i-1].Latitude, path[i-1].Longitude, path[i].Latitude, path[i].Longitude)
+		if math.Abs(step-total/10) > 1 {
+			t.Errorf("step %d is %.0f m, want %.0f", i, step, total/10)
+		}
+	}
+	if path[5].Latitude < 52 {
+		t.Errorf("expected the midpoint north of London, got %+v", path[5])
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	mid := Midpoint(Location{Latitude: 0, Longitude: 0}, Location{Latitude: 0, Longitude: 90})
+	if math.Abs(mid.Latitude) > 1e-9 || math.Abs(mid.Longitude-45) > 1e-9 {
+		t.Errorf("Midpoint() = %+v, want (0, 45)", mid)
+	}
+
+	// Crossing the antimeridian
+	mid = Midpoint(Location{Latitude: 0, Longitude: 170}, Location{Latitude: 0, Longitude: -170})
+	if math.Abs(math.Abs(mid.Longitude)-180) > 1e-9 {
+		t.Errorf("Midpoint() across the antimeridian = %+v, want longitude 180", mid)
+	}
+}
+
+func TestAntipode(t *testing.T) {
+	tests := []struct {
+		in, want Location
+	}{
+		{Location{Latitude: 40, Longitude: -74}, Location{Latitude: -40, Longitude: 106}},
+		{Location{Latitude: -33.9, Longitude: 151.2}, Location{Latitude: 33.9, Longitude: -28.8}},
+		{Location{Latitude: 0, Longitude: 0}, Location{Latitude: 0, Longitude: 180}},
+	}
+	for _, tt := range tests {
+		got := Antipode(tt.in)
+		if math.Abs(got.Latitude-tt.want.Latitude) > 1e-9 || math.Abs(got.Longitude-tt.want.Longitude) > 1e-9 {
+			t.Errorf("Antipode(%+v) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if !IsAntipodal(tt.in, got) {
+			t.Errorf("IsAntipodal(%+v, %+v) = false", tt.in, got)
+		}
+	}
+	if IsAntipodal(london, newYork) {
+		t.Error("London and New York are not antipodal")
+	}
+}
+
+func TestBearings(t *testing.T) {
+	initial := InitialBearing(london, newYork)
+	final := FinalBearing(london, newYork)
+	if math.Abs(initial-288.3) > 0.5 {
+		t.Errorf("InitialBearing() = %.1f, want ~288.3", initial)
+	}
+	if math.Abs(final-231.3) > 0.5 {
+		t.Errorf("FinalBearing() = %.1f, want ~231.3", final)
+	}
+	if b := InitialBearing(Location{}, Location{Latitude: 1}); math.Abs(b) > 1e-9 {
+		t.Errorf("due north bearing = %.3f, want 0", b)
+	}
+}
+
+func TestDestinationPoint(t *testing.T) {
+	distance := HaversineDistance(london.Latitude, london.Longitude, newYork.Latitude, newYork.Longitude)
+	got := DestinationPoint(london, InitialBearing(london, newYork), distance)
+	if d := HaversineDistance(got.Latitude, got.Longitude, newYork.Latitude, newYork.Longitude); d > 100 {
+		t.Errorf("DestinationPoint() = %+v, %.0f m from JFK", got, d)
+	}
+
+	east := DestinationPoint(Location{Longitude: 179.9}, 90, 50000)
+	if east.Longitude > -179 || east.Longitude < -180 {
+		t.Errorf("expected longitude to wrap past the antimeridian, got %+v", east)
+	}
+}
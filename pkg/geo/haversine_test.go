@@ -159,4 +159,20 @@ func TestBoundingBox(t *testing.T) {
 			t.Errorf("String() = %s, expected %s", bbox.String(), expected)
 		}
 	})
+
+	t.Run("Contains", func(t *testing.T) {
+		bbox := &BoundingBox{MinLat: 10, MinLon: 20, MaxLat: 11, MaxLon: 21}
+		if !bbox.Contains(10.5, 20.5) || !bbox.Contains(10, 21) {
+			t.Error("Contains() should include the inside and the edges")
+		}
+		if bbox.Contains(9.9, 20.5) || bbox.Contains(10.5, 21.1) {
+			t.Error("Contains() should exclude points outside")
+		}
+
+		// Fiji, across the antimeridian
+		wrapped := &BoundingBox{MinLat: -21, MinLon: 177, MaxLat: -12, MaxLon: -178}
+		if !wrapped.Contains(-17, 179) || !wrapped.Contains(-17, -179) || wrapped.Contains(-17, 0) {
+			t.Error("Contains() should wrap across the antimeridian")
+		}
+	})
 }
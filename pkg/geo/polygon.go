@@ -0,0 +1,145 @@
+package geo
+
+import "math"
+
+// PointInPolygon reports whether a point lies inside a polygon ring using the
+// even-odd rule. The ring may be open or closed (first point repeated last).
+// Coordinates are treated as planar, which is accurate for polygons that do
+// not span the antimeridian or a pole.
+func PointInPolygon(lat, lon float64, ring []Location) bool {
+	n := len(ring)
+	if n < 3 {
+		return false
+	}
+
+	inside := false
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Latitude > lat) != (b.Latitude > lat) {
+			crossLon := (b.Longitude-a.Longitude)*(lat-a.Latitude)/(b.Latitude-a.Latitude) + a.Longitude
+			if lon < crossLon {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// PointInMultiPolygon reports whether a point lies inside any of several
+// polygons, each an outer ring followed by its holes. A point inside a hole
+// is outside that polygon.
+func PointInMultiPolygon(lat, lon float64, polygons [][][]Location) bool {
+	for _, rings := range polygons {
+		if len(rings) == 0 || !PointInPolygon(lat, lon, rings[0]) {
+			continue
+		}
+		inHole := false
+		for _, hole := range rings[1:] {
+			if PointInPolygon(lat, lon, hole) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// DistanceToBoundary returns the distance in meters from a point to the
+// nearest edge of a polygon ring. Edges are projected onto a local
+// equirectangular plane centered on the point, which is accurate to well
+// under a percent for edges within tens of kilometers.
+func DistanceToBoundary(lat, lon float64, ring []Location) float64 {
+	return distanceToPath(lat, lon, ring, true)
+}
+
+// DistanceToPolyline returns the distance in meters from a point to the
+// nearest segment of an open polyline, using the same local projection as
+// DistanceToBoundary.
+func DistanceToPolyline(lat, lon float64, line []Location) float64 {
+	return distanceToPath(lat, lon, line, false)
+}
+
+// NearestPointOnPolyline returns the point on an open polyline closest to
+// the given point and its distance in meters, using the same local
+// projection as DistanceToBoundary.
+func NearestPointOnPolyline(lat, lon float64, line []Location) (Location, float64) {
+	return nearestOnPath(lat, lon, line, false)
+}
+
+// distanceToPath measures the distance from a point to a path, optionally
+// including the closing segment from the last point back to the first
+func distanceToPath(lat, lon float64, path []Location, closed bool) float64 {
+	_, d := nearestOnPath(lat, lon, path, closed)
+	return d
+}
+
+// nearestOnPath finds the closest point on a path and its distance
+func nearestOnPath(lat, lon float64, path []Location, closed bool) (Location, float64) {
+	if len(path) == 0 {
+		return Location{}, math.Inf(1)
+	}
+	if len(path) == 1 {
+		return path[0], HaversineDistance(lat, lon, path[0].Latitude, path[0].Longitude)
+	}
+
+	metersPerDegLat := EarthRadius * math.Pi / 180
+	metersPerDegLon := metersPerDegLat * math.Cos(lat*math.Pi/180)
+	project := func(p Location) (float64, float64) {
+		return (p.Longitude - lon) * metersPerDegLon, (p.Latitude - lat) * metersPerDegLat
+	}
+
+	segments := len(path) - 1
+	if closed {
+		segments = len(path)
+	}
+
+	best := math.Inf(1)
+	var nearest Location
+	for i := 0; i < segments; i++ {
+		a, b := path[i], path[(i+1)%len(path)]
+		ax, ay := project(a)
+		bx, by := project(b)
+		if t, d := closestOnSegment(ax, ay, bx, by); d < best {
+			best = d
+			nearest = Location{
+				Latitude:  a.Latitude + t*(b.Latitude-a.Latitude),
+				Longitude: a.Longitude + t*(b.Longitude-a.Longitude),
+			}
+		}
+	}
+	return nearest, best
+}
+
+// closestOnSegment returns the fraction along segment AB of its point
+// closest to the origin, and that point's distance from the origin
+func closestOnSegment(ax, ay, bx, by float64) (float64, float64) {
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+	t := 0.0
+	if lengthSq > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSq))
+	}
+	return t, math.Hypot(ax+t*dx, ay+t*dy)
+}
+
+// PolygonArea returns the area of a polygon ring in square meters on a
+// spherical Earth. The ring may be open or closed and in either winding
+// order.
+func PolygonArea(ring []Location) float64 {
+	n := len(ring)
+	if n < 3 {
+		return 0
+	}
+
+	const rad = math.Pi / 180
+	total := 0.0
+	for i := 0; i < n; i++ {
+		a, b := ring[i], ring[(i+1)%n]
+		total += (b.Longitude - a.Longitude) * rad *
+			(2 + math.Sin(a.Latitude*rad) + math.Sin(b.Latitude*rad))
+	}
+	return math.Abs(total * EarthRadius * EarthRadius / 2)
+}
@@ -0,0 +1,140 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// square is a ~1.1km square around (0.005, 0.005)
+var square = []Location{
+	{Latitude: 0, Longitude: 0},
+	{Latitude: 0, Longitude: 0.01},
+	{Latitude: 0.01, Longitude: 0.01},
+	{Latitude: 0.01, Longitude: 0},
+}
+
+func TestPointInPolygon(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		ring     []Location
+		want     bool
+	}{
+		{"center", 0.005, 0.005, square, true},
+		{"outside east", 0.005, 0.02, square, false},
+		{"outside south", -0.001, 0.005, square, false},
+		{"closed ring", 0.005, 0.005, append(append([]Location{}, square...), square[0]), true},
+		{"degenerate ring", 0.005, 0.005, square[:2], false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PointInPolygon(tt.lat, tt.lon, tt.ring); got != tt.want {
+				t.Errorf("PointInPolygon() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointInMultiPolygon(t *testing.T) {
+	hole := []Location{
+		{Latitude: 0.004, Longitude: 0.004},
+		{Latitude: 0.004, Longitude: 0.006},
+		{Latitude: 0.006, Longitude: 0.006},
+		{Latitude: 0.006, Longitude: 0.004},
+	}
+	island := []Location{
+		{Latitude: 0.02, Longitude: 0.02},
+		{Latitude: 0.02, Longitude: 0.03},
+		{Latitude: 0.03, Longitude: 0.03},
+	}
+	polygons := [][][]Location{{square, hole}, {island}}
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     bool
+	}{
+		{"outer ring", 0.002, 0.002, true},
+		{"in the hole", 0.005, 0.005, false},
+		{"second polygon", 0.021, 0.025, true},
+		{"outside both", 0.015, 0.015, false},
+	}
+	for _, tt := range tests {
+		if got := PointInMultiPolygon(tt.lat, tt.lon, polygons); got != tt.want {
+			t.Errorf("%s: PointInMultiPolygon() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDistanceToBoundary(t *testing.T) {
+	// 0.001 degrees of latitude is ~111m
+	got := DistanceToBoundary(0.001, 0.005, square)
+	if math.Abs(got-111.2) > 1 {
+		t.Errorf("DistanceToBoundary() = %.1f, want ~111.2", got)
+	}
+
+	// The center is ~556m from every edge
+	got = DistanceToBoundary(0.005, 0.005, square)
+	if math.Abs(got-556) > 2 {
+		t.Errorf("DistanceToBoundary() = %.1f, want ~556", got)
+	}
+
+	// Points outside measure to the nearest edge too
+	got = DistanceToBoundary(0.005, 0.011, square)
+	if math.Abs(got-111.2) > 1 {
+		t.Errorf("DistanceToBoundary() = %.1f, want ~111.2", got)
+	}
+}
+
+func TestDistanceToPolyline(t *testing.T) {
+	// The open polyline has no closing edge back along longitude 0
+	line := square[:3]
+	got := DistanceToPolyline(0.005, 0.001, line)
+	if math.Abs(got-556) > 2 {
+		t.Errorf("DistanceToPolyline() = %.1f, want ~556", got)
+	}
+	if closed := DistanceToBoundary(0.005, 0.001, line); closed >= got {
+		t.Errorf("expected closing edge to be nearer, got %.1f >= %.1f", closed, got)
+	}
+}
+
+func TestNearestPointOnPolyline(t *testing.T) {
+	line := []Location{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.01}}
+	nearest, d := NearestPointOnPolyline(0.001, 0.004, line)
+	if math.Abs(nearest.Latitude) > 1e-9 || math.Abs(nearest.Longitude-0.004) > 1e-9 {
+		t.Errorf("NearestPointOnPolyline() point = %+v, want (0, 0.004)", nearest)
+	}
+	if math.Abs(d-111) > 1 {
+		t.Errorf("NearestPointOnPolyline() distance = %.1f, want ~111", d)
+	}
+
+	// Beyond the end of the line the endpoint is nearest
+	if nearest, _ := NearestPointOnPolyline(0, 0.02, line); nearest != line[1] {
+		t.Errorf("expected endpoint, got %+v", nearest)
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	// One degree degree at the equator is about 12,364 km²
+	degree := []Location{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 1},
+		{Latitude: 1, Longitude: 1},
+		{Latitude: 1, Longitude: 0},
+	}
+	area := PolygonArea(degree) / 1e6
+	if math.Abs(area-12364) > 20 {
+		t.Errorf("PolygonArea() = %.0f km², want about 12364", area)
+	}
+
+	// Winding order and closing point do not matter
+	reversed := []Location{degree[0], degree[3], degree[2], degree[1], degree[0]}
+	if math.Abs(PolygonArea(reversed)/1e6-area) > 1e-6 {
+		t.Errorf("reversed area %.3f differs from %.3f", PolygonArea(reversed)/1e6, area)
+	}
+
+	if PolygonArea(degree[:2]) != 0 {
+		t.Error("expected zero area for a degenerate ring")
+	}
+}
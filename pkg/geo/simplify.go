@@ -0,0 +1,58 @@
+package geo
+
+// SimplifyPath reduces the number of points in a path with the
+// Douglas-Peucker algorithm, dropping points that lie within tolerance
+// meters of the simplified line. The first and last points are always kept,
+// so a closed ring stays closed.
+func SimplifyPath(path []Location, tolerance float64) []Location {
+	if len(path) < 3 || tolerance <= 0 {
+		return append([]Location(nil), path...)
+	}
+
+	keep := make([]bool, len(path))
+	keep[0], keep[len(path)-1] = true, true
+
+	// Ranges still to simplify, as index pairs
+	stack := [][2]int{{0, len(path) - 1}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		first, last := r[0], r[1]
+		if last-first < 2 {
+			continue
+		}
+
+		segment := []Location{path[first], path[last]}
+		farthest, maxDist := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			if d := DistanceToPolyline(path[i].Latitude, path[i].Longitude, segment); d > maxDist {
+				farthest, maxDist = i, d
+			}
+		}
+		if farthest < 0 {
+			continue
+		}
+		keep[farthest] = true
+		stack = append(stack, [2]int{first, farthest}, [2]int{farthest, last})
+	}
+
+	simplified := make([]Location, 0, len(path))
+	for i, p := range path {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// SimplifyToLimit simplifies a path until it has at most maxPoints points,
+// starting at tolerance meters and doubling it as needed. Paths that
+// already fit are returned unchanged.
+func SimplifyToLimit(path []Location, tolerance float64, maxPoints int) []Location {
+	simplified := SimplifyPath(path, tolerance)
+	for maxPoints > 0 && len(simplified) > maxPoints && tolerance > 0 {
+		tolerance *= 2
+		simplified = SimplifyPath(path, tolerance)
+	}
+	return simplified
+}
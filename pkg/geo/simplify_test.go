@@ -0,0 +1,64 @@
+package geo
+
+import "testing"
+
+func TestSimplifyPath(t *testing.T) {
+	// A nearly straight line with one large detour
+	path := []Location{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0.00001, Longitude: 0.001}, // ~1 m off the line
+		{Latitude: 0, Longitude: 0.002},
+		{Latitude: 0.01, Longitude: 0.003}, // ~1.1 km off the line
+		{Latitude: 0, Longitude: 0.004},
+	}
+
+	got := SimplifyPath(path, 10)
+	want := []Location{path[0], path[2], path[3], path[4]}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d points, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("point %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := SimplifyPath(path, 0); len(got) != len(path) {
+		t.Errorf("expected zero tolerance to keep all points, got %d", len(got))
+	}
+}
+
+func TestSimplifyPathKeepsRingClosed(t *testing.T) {
+	ring := []Location{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 0.0005},
+		{Latitude: 0, Longitude: 0.001},
+		{Latitude: 0.001, Longitude: 0.001},
+		{Latitude: 0.001, Longitude: 0},
+		{Latitude: 0, Longitude: 0},
+	}
+
+	got := SimplifyPath(ring, 5)
+	if got[0] != got[len(got)-1] {
+		t.Errorf("expected ring to stay closed, got %v", got)
+	}
+	if len(got) != 5 {
+		t.Errorf("expected the collinear point to be dropped, got %d points", len(got))
+	}
+}
+
+func TestSimplifyToLimit(t *testing.T) {
+	// A zigzag that only simplifies with a large tolerance
+	var path []Location
+	for i := 0; i < 200; i++ {
+		lat := 0.0
+		if i%2 == 1 {
+			lat = 0.001 * float64(i%7)
+		}
+		path = append(path, Location{Latitude: lat, Longitude: float64(i) * 0.001})
+	}
+
+	if got := SimplifyToLimit(path, 1, 20); len(got) > 20 {
+		t.Errorf("expected at most 20 points, got %d", len(got))
+	}
+}
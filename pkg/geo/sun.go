@@ -0,0 +1,176 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+// Sun zenith angles in degrees for daylight events
+const (
+	// SunriseZenith accounts for atmospheric refraction and the solar disc radius
+	SunriseZenith = 90.833
+	// CivilTwilightZenith is the sun 6 degrees below the horizon
+	CivilTwilightZenith = 96.0
+)
+
+// SunEvents are the times of solar events on one day. Rise and set times are
+// nil when the sun does not cross the given zenith that day, i.e. during
+// polar day or polar night.
+type SunEvents struct {
+	SolarNoon  time.Time
+	Sunrise    *time.Time
+	Sunset     *time.Time
+	CivilDawn  *time.Time
+	CivilDusk  *time.Time
+	PolarDay   bool // sun stays above the horizon all day
+	PolarNight bool // sun stays below the horizon all day
+}
+
+// DayLength returns the time between sunrise and sunset, which is 24 hours
+// during polar day and zero during polar night
+func (e SunEvents) DayLength() time.Duration {
+	switch {
+	case e.PolarDay:
+		return 24 * time.Hour
+	case e.Sunrise == nil || e.Sunset == nil:
+		return 0
+	default:
+		return e.Sunset.Sub(*e.Sunrise)
+	}
+}
+
+// SunTimes computes solar noon, sunrise, sunset and civil twilight for the
+// calendar day of date at a location, using the NOAA solar equations.
+// Results are accurate to about a minute away from the polar circles.
+func SunTimes(lat, lon float64, date time.Time) SunEvents {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Solar noon, refined once with the equation of time at noon itself
+	noon := day.Add(minutes(720 - 4*lon))
+	_, eqTime := solarDeclination(noon)
+	noon = day.Add(minutes(720 - 4*lon - eqTime))
+
+	events := SunEvents{SolarNoon: noon}
+	var polar int
+	events.Sunrise, events.Sunset, polar = sunCrossings(lat, lon, day, noon, SunriseZenith)
+	events.PolarDay = polar > 0
+	events.PolarNight = polar < 0
+	events.CivilDawn, events.CivilDusk, _ = sunCrossings(lat, lon, day, noon, CivilTwilightZenith)
+	return events
+}
+
+// sunCrossings finds when the sun crosses a zenith angle before and after
+// solar noon, with times measured from day at midnight UTC. polar is 1 if
+// the sun stays above the zenith angle all day and -1 if it stays below.
+func sunCrossings(lat, lon float64, day, noon time.Time, zenith float64) (*time.Time, *time.Time, int) {
+	cross := func(estimate time.Time, sign float64) (*time.Time, int) {
+		// Iterate so the declination matches the time of the event
+		for i := 0; i < 2; i++ {
+			decl, eqTime := solarDeclination(estimate)
+			cosH := (math.Cos(radians(zenith)) - math.Sin(radians(lat))*math.Sin(decl)) /
+				(math.Cos(radians(lat)) * math.Cos(decl))
+			if cosH < -1 {
+				return nil, 1
+			}
+			if cosH > 1 {
+				return nil, -1
+			}
+			h := degrees(math.Acos(cosH))
+			estimate = day.Add(minutes(720 - 4*lon - eqTime + sign*4*h))
+		}
+		return &estimate, 0
+	}
+
+	rise, polar := cross(noon, -1)
+	set, _ := cross(noon, 1)
+	if polar != 0 {
+		return nil, nil, polar
+	}
+	return rise, set, 0
+}
+
+// SunPosition returns the sun's azimuth in degrees clockwise from north and
+// its elevation in degrees above the horizon, corrected for atmospheric
+// refraction, at a location and time
+func SunPosition(lat, lon float64, t time.Time) (azimuth, elevation float64) {
+	t = t.UTC()
+	decl, eqTime := solarDeclination(t)
+
+	minutesUTC := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60
+	trueSolarTime := math.Mod(minutesUTC+eqTime+4*lon, 1440)
+	if trueSolarTime < 0 {
+		trueSolarTime += 1440
+	}
+	hourAngle := radians(trueSolarTime/4 - 180)
+
+	latRad := radians(lat)
+	cosZenith := math.Sin(latRad)*math.Sin(decl) + math.Cos(latRad)*math.Cos(decl)*math.Cos(hourAngle)
+	zenith := math.Acos(math.Max(-1, math.Min(1, cosZenith)))
+	elevation = 90 - degrees(zenith)
+
+	azimuth = degrees(math.Atan2(math.Sin(hourAngle),
+		math.Cos(hourAngle)*math.Sin(latRad)-math.Tan(decl)*math.Cos(latRad))) + 180
+	azimuth = math.Mod(azimuth, 360)
+
+	return azimuth, elevation + atmosphericRefraction(elevation)
+}
+
+// solarDeclination returns the sun's declination in radians and the
+// equation of time in minutes at time t
+func solarDeclination(t time.Time) (float64, float64) {
+	julianDay := float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+	jc := (julianDay - 2451545) / 36525
+
+	meanLong := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360)
+	meanAnomaly := radians(357.52911 + jc*(35999.05029-0.0001537*jc))
+	eccentricity := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+
+	center := math.Sin(meanAnomaly)*(1.914602-jc*(0.004817+0.000014*jc)) +
+		math.Sin(2*meanAnomaly)*(0.019993-0.000101*jc) +
+		math.Sin(3*meanAnomaly)*0.000289
+	omega := radians(125.04 - 1934.136*jc)
+	apparentLong := radians(meanLong + center - 0.00569 - 0.00478*math.Sin(omega))
+
+	meanObliquity := 23 + (26+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60)/60
+	obliquity := radians(meanObliquity + 0.00256*math.Cos(omega))
+
+	decl := math.Asin(math.Sin(obliquity) * math.Sin(apparentLong))
+
+	y := math.Pow(math.Tan(obliquity/2), 2)
+	l0 := radians(meanLong)
+	eqTime := 4 * degrees(y*math.Sin(2*l0)-
+		2*eccentricity*math.Sin(meanAnomaly)+
+		4*eccentricity*y*math.Sin(meanAnomaly)*math.Cos(2*l0)-
+		0.5*y*y*math.Sin(4*l0)-
+		1.25*eccentricity*eccentricity*math.Sin(2*meanAnomaly))
+
+	return decl, eqTime
+}
+
+// atmosphericRefraction returns the refraction correction in degrees for a
+// geometric solar elevation
+func atmosphericRefraction(elevation float64) float64 {
+	if elevation > 85 {
+		return 0
+	}
+	tanE := math.Tan(radians(elevation))
+	var arcSeconds float64
+	switch {
+	case elevation > 5:
+		arcSeconds = 58.1/tanE - 0.07/math.Pow(tanE, 3) + 0.000086/math.Pow(tanE, 5)
+	case elevation > -0.575:
+		arcSeconds = 1735 + elevation*(-518.2+elevation*(103.4+elevation*(-12.79+elevation*0.711)))
+	default:
+		arcSeconds = -20.772 / tanE
+	}
+	return arcSeconds / 3600
+}
+
+// minutes converts fractional minutes to a duration
+func minutes(m float64) time.Duration {
+	return time.Duration(m * float64(time.Minute))
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
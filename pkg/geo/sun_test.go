@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSunTimes(t *testing.T) {
+	// London at the June solstice, NOAA reference times in UTC
+	events := SunTimes(51.5074, -0.1278, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC))
+
+	checks := []struct {
+		name string
+		got  *time.Time
+		want time.Time
+	}{
+		{"solar noon", &events.SolarNoon, time.Date(2024, 6, 21, 12, 2, 27, 0, time.UTC)},
+		{"sunrise", events.Sunrise, time.Date(2024, 6, 21, 3, 43, 9, 0, time.UTC)},
+		{"sunset", events.Sunset, time.Date(2024, 6, 21, 20, 21, 41, 0, time.UTC)},
+	}
+	for _, c := range checks {
+		if c.got == nil {
+			t.Errorf("%s: missing", c.name)
+			continue
+		}
+		if diff := c.got.Sub(c.want); diff < -2*time.Minute || diff > 2*time.Minute {
+			t.Errorf("%s = %s, want %s", c.name, c.got.Format(time.RFC3339), c.want.Format(time.RFC3339))
+		}
+	}
+
+	if events.CivilDawn == nil || !events.CivilDawn.Before(*events.Sunrise) || !events.CivilDusk.After(*events.Sunset) {
+		t.Errorf("civil twilight should surround sunrise and sunset: %+v", events)
+	}
+	if length := events.DayLength(); length < 16*time.Hour+30*time.Minute || length > 16*time.Hour+45*time.Minute {
+		t.Errorf("unexpected day length %s", length)
+	}
+}
+
+func TestSunTimesPolar(t *testing.T) {
+	summer := SunTimes(69.65, 18.96, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC))
+	if !summer.PolarDay || summer.Sunrise != nil || summer.DayLength() != 24*time.Hour {
+		t.Errorf("expected polar day in Tromsø in June: %+v", summer)
+	}
+
+	winter := SunTimes(69.65, 18.96, time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC))
+	if !winter.PolarNight || winter.Sunset != nil || winter.DayLength() != 0 {
+		t.Errorf("expected polar night in Tromsø in December: %+v", winter)
+	}
+	// Civil twilight still occurs around midday
+	if winter.CivilDawn == nil || winter.CivilDusk == nil {
+		t.Error("expected civil twilight in Tromsø in December")
+	}
+}
+
+func TestSunPosition(t *testing.T) {
+	// Sun due south at solar noon, at 90 - latitude + declination
+	azimuth, elevation := SunPosition(51.5074, -0.1278, time.Date(2024, 6, 21, 12, 2, 27, 0, time.UTC))
+	if math.Abs(azimuth-180) > 1 {
+		t.Errorf("azimuth = %.2f, want ~180", azimuth)
+	}
+	if math.Abs(elevation-61.95) > 0.2 {
+		t.Errorf("elevation = %.2f, want ~61.95", elevation)
+	}
+
+	// Morning sun is in the east and below the horizon before sunrise
+	azimuth, _ = SunPosition(51.5074, -0.1278, time.Date(2024, 6, 21, 6, 0, 0, 0, time.UTC))
+	if azimuth < 45 || azimuth > 90 {
+		t.Errorf("morning azimuth = %.2f, want north-east", azimuth)
+	}
+	if _, elevation := SunPosition(51.5074, -0.1278, time.Date(2024, 6, 21, 2, 0, 0, 0, time.UTC)); elevation >= 0 {
+		t.Errorf("expected the sun below the horizon at night, got %.2f", elevation)
+	}
+}
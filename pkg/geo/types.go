@@ -71,6 +71,19 @@ func (bb *BoundingBox) ExtendWithPoint(lat, lon float64) {
 	}
 }
 
+// Contains reports whether a point lies inside the bounding box, edges
+// included. A box whose MinLon is greater than its MaxLon crosses the
+// antimeridian.
+func (bb *BoundingBox) Contains(lat, lon float64) bool {
+	if lat < bb.MinLat || lat > bb.MaxLat {
+		return false
+	}
+	if bb.MinLon > bb.MaxLon {
+		return lon >= bb.MinLon || lon <= bb.MaxLon
+	}
+	return lon >= bb.MinLon && lon <= bb.MaxLon
+}
+
 // Buffer adds a buffer around the bounding box in meters
 // This is a rough approximation as it converts meters to degrees using
 // a simple factor that's reasonably accurate near the equator.
@@ -0,0 +1,57 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Coordinate validation error codes
+const (
+	ErrCodeInvalidCoordinates = "INVALID_COORDINATES"
+	ErrCodeInvalidLatitude    = "INVALID_LATITUDE"
+	ErrCodeInvalidLongitude   = "INVALID_LONGITUDE"
+)
+
+// ValidationError represents a validation error with a specific code and message
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// ValidateCoords checks that a latitude and longitude are finite and in
+// range. Both ranges are inclusive: the poles (±90) and the antimeridian
+// (±180) are valid coordinates. This is the one coordinate validator the
+// other packages defer to, so every tool agrees on the edge cases.
+func ValidateCoords(lat, lon float64) error {
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.IsInf(lat, 0) || math.IsInf(lon, 0) {
+		return ValidationError{
+			Code:    ErrCodeInvalidCoordinates,
+			Message: "Coordinates must be finite numbers",
+		}
+	}
+
+	if lat < -90 || lat > 90 {
+		return ValidationError{
+			Code:    ErrCodeInvalidLatitude,
+			Message: fmt.Sprintf("Latitude must be between -90 and 90 degrees, got %g", lat),
+		}
+	}
+
+	if lon < -180 || lon > 180 {
+		return ValidationError{
+			Code:    ErrCodeInvalidLongitude,
+			Message: fmt.Sprintf("Longitude must be between -180 and 180 degrees, got %g", lon),
+		}
+	}
+
+	return nil
+}
+
+// ValidateLocation validates a location's coordinates
+func ValidateLocation(loc Location) error {
+	return ValidateCoords(loc.Latitude, loc.Longitude)
+}
@@ -0,0 +1,47 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateCoords(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		wantCode string
+	}{
+		{"valid", 51.5074, -0.1278, ""},
+		{"north pole", 90, 0, ""},
+		{"south pole", -90, 0, ""},
+		{"antimeridian east", 0, 180, ""},
+		{"antimeridian west", 0, -180, ""},
+		{"latitude too high", 90.0001, 0, ErrCodeInvalidLatitude},
+		{"latitude too low", -91, 0, ErrCodeInvalidLatitude},
+		{"longitude too high", 0, 180.0001, ErrCodeInvalidLongitude},
+		{"longitude too low", 0, -181, ErrCodeInvalidLongitude},
+		{"NaN latitude", math.NaN(), 0, ErrCodeInvalidCoordinates},
+		{"NaN longitude", 0, math.NaN(), ErrCodeInvalidCoordinates},
+		{"infinite latitude", math.Inf(1), 0, ErrCodeInvalidCoordinates},
+		{"infinite longitude", 0, math.Inf(-1), ErrCodeInvalidCoordinates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCoords(tt.lat, tt.lon)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			valErr, ok := err.(ValidationError)
+			if !ok {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if valErr.Code != tt.wantCode {
+				t.Errorf("got code %s, want %s", valErr.Code, tt.wantCode)
+			}
+		})
+	}
+}
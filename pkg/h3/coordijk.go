@@ -0,0 +1,252 @@
+package h3
+
+import "math"
+
+// coordIJK is a hexagon address in an ijk+ coordinate system, with all
+// components non-negative once normalized
+type coordIJK struct {
+	i, j, k int
+}
+
+// vec2d is a point on the plane of an icosahedron face
+type vec2d struct {
+	x, y float64
+}
+
+// direction is an H3 digit, a unit vector in ijk+ coordinates
+type direction int
+
+const (
+	centerDigit direction = iota
+	kAxesDigit
+	jAxesDigit
+	jkAxesDigit
+	iAxesDigit
+	ikAxesDigit
+	ijAxesDigit
+	invalidDigit
+)
+
+// unitVecs are the ijk+ unit vectors of each direction
+var unitVecs = [7]coordIJK{
+	{0, 0, 0}, {0, 0, 1}, {0, 1, 0}, {0, 1, 1}, {1, 0, 0}, {1, 0, 1}, {1, 1, 0},
+}
+
+func (c coordIJK) add(o coordIJK) coordIJK {
+	return coordIJK{c.i + o.i, c.j + o.j, c.k + o.k}
+}
+
+func (c coordIJK) sub(o coordIJK) coordIJK {
+	return coordIJK{c.i - o.i, c.j - o.j, c.k - o.k}
+}
+
+func (c coordIJK) scale(factor int) coordIJK {
+	return coordIJK{c.i * factor, c.j * factor, c.k * factor}
+}
+
+// normalize returns the coordinates with no negative component and at least
+// one zero
+func (c coordIJK) normalize() coordIJK {
+	if c.i < 0 {
+		c.j -= c.i
+		c.k -= c.i
+		c.i = 0
+	}
+	if c.j < 0 {
+		c.i -= c.j
+		c.k -= c.j
+		c.j = 0
+	}
+	if c.k < 0 {
+		c.i -= c.k
+		c.j -= c.k
+		c.k = 0
+	}
+	if m := min(c.i, c.j, c.k); m > 0 {
+		c.i -= m
+		c.j -= m
+		c.k -= m
+	}
+	return c
+}
+
+// combine returns i*iVec + j*jVec + k*kVec, normalized; the aperture and
+// rotation changes of basis are all of this form
+func (c coordIJK) combine(iVec, jVec, kVec coordIJK) coordIJK {
+	return iVec.scale(c.i).add(jVec.scale(c.j)).add(kVec.scale(c.k)).normalize()
+}
+
+// toDigit returns the direction of a unit vector, or invalidDigit
+func (c coordIJK) toDigit() direction {
+	c = c.normalize()
+	for d := centerDigit; d < invalidDigit; d++ {
+		if c == unitVecs[d] {
+			return d
+		}
+	}
+	return invalidDigit
+}
+
+// neighbor returns the adjacent cell in a direction
+func (c coordIJK) neighbor(d direction) coordIJK {
+	if d > centerDigit && d < invalidDigit {
+		return c.add(unitVecs[d]).normalize()
+	}
+	return c
+}
+
+// upAp7 returns the parent cell in the counter-clockwise aperture 7 grid
+func (c coordIJK) upAp7() coordIJK {
+	i, j := c.i-c.k, c.j-c.k
+	return coordIJK{
+		i: int(math.Round(float64(3*i-j) / 7)),
+		j: int(math.Round(float64(i+2*j) / 7)),
+	}.normalize()
+}
+
+// upAp7r returns the parent cell in the clockwise aperture 7 grid
+func (c coordIJK) upAp7r() coordIJK {
+	i, j := c.i-c.k, c.j-c.k
+	return coordIJK{
+		i: int(math.Round(float64(2*i+j) / 7)),
+		j: int(math.Round(float64(3*j-i) / 7)),
+	}.normalize()
+}
+
+// downAp7 returns the center child in the counter-clockwise aperture 7 grid
+func (c coordIJK) downAp7() coordIJK {
+	return c.combine(coordIJK{3, 0, 1}, coordIJK{1, 3, 0}, coordIJK{0, 1, 3})
+}
+
+// downAp7r returns the center child in the clockwise aperture 7 grid
+func (c coordIJK) downAp7r() coordIJK {
+	return c.combine(coordIJK{3, 1, 0}, coordIJK{0, 3, 1}, coordIJK{1, 0, 3})
+}
+
+// downAp3 returns the center child in the counter-clockwise aperture 3 grid
+func (c coordIJK) downAp3() coordIJK {
+	return c.combine(coordIJK{2, 0, 1}, coordIJK{1, 2, 0}, coordIJK{0, 1, 2})
+}
+
+// downAp3r returns the center child in the clockwise aperture 3 grid
+func (c coordIJK) downAp3r() coordIJK {
+	return c.combine(coordIJK{2, 1, 0}, coordIJK{0, 2, 1}, coordIJK{1, 0, 2})
+}
+
+func (c coordIJK) rotate60ccw() coordIJK {
+	return c.combine(coordIJK{1, 1, 0}, coordIJK{0, 1, 1}, coordIJK{1, 0, 1})
+}
+
+func (c coordIJK) rotate60cw() coordIJK {
+	return c.combine(coordIJK{1, 0, 1}, coordIJK{1, 1, 0}, coordIJK{0, 1, 1})
+}
+
+// hex2d returns the center of a cell on the face plane
+func (c coordIJK) hex2d() vec2d {
+	i, j := float64(c.i-c.k), float64(c.j-c.k)
+	return vec2d{x: i - 0.5*j, y: j * sqrt3_2}
+}
+
+// hex2dToCoordIJK returns the cell containing a point on the face plane
+func hex2dToCoordIJK(v vec2d) coordIJK {
+	var h coordIJK
+
+	// quantize into the ij system and then normalize
+	a1, a2 := math.Abs(v.x), math.Abs(v.y)
+	x2 := a2 / sin60
+	x1 := a1 + x2/2
+	m1, m2 := int(x1), int(x2)
+	r1, r2 := x1-float64(m1), x2-float64(m2)
+
+	if r1 < 0.5 {
+		if r1 < 1.0/3.0 {
+			h.i = m1
+			if r2 < (1+r1)/2 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+		} else {
+			if r2 < 1-r1 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+			if 1-r1 <= r2 && r2 < 2*r1 {
+				h.i = m1 + 1
+			} else {
+				h.i = m1
+			}
+		}
+	} else {
+		if r1 < 2.0/3.0 {
+			if r2 < 1-r1 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+			if 2*r1-1 < r2 && r2 < 1-r1 {
+				h.i = m1
+			} else {
+				h.i = m1 + 1
+			}
+		} else {
+			h.i = m1 + 1
+			if r2 < r1/2 {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+		}
+	}
+
+	// fold across the axes if necessary
+	if v.x < 0 {
+		if h.j%2 == 0 {
+			h.i -= 2 * (h.i - h.j/2)
+		} else {
+			h.i -= 2*(h.i-(h.j+1)/2) + 1
+		}
+	}
+	if v.y < 0 {
+		h.i -= (2*h.j + 1) / 2
+		h.j = -h.j
+	}
+	return h.normalize()
+}
+
+func (d direction) rotate60ccw() direction {
+	switch d {
+	case kAxesDigit:
+		return ikAxesDigit
+	case ikAxesDigit:
+		return iAxesDigit
+	case iAxesDigit:
+		return ijAxesDigit
+	case ijAxesDigit:
+		return jAxesDigit
+	case jAxesDigit:
+		return jkAxesDigit
+	case jkAxesDigit:
+		return kAxesDigit
+	}
+	return d
+}
+
+func (d direction) rotate60cw() direction {
+	switch d {
+	case kAxesDigit:
+		return jkAxesDigit
+	case jkAxesDigit:
+		return jAxesDigit
+	case jAxesDigit:
+		return ijAxesDigit
+	case ijAxesDigit:
+		return iAxesDigit
+	case iAxesDigit:
+		return ikAxesDigit
+	case ikAxesDigit:
+		return kAxesDigit
+	}
+	return d
+}
@@ -0,0 +1,434 @@
+package h3
+
+import "math"
+
+const (
+	numIcosaFaces = 20
+	numBaseCells  = 122
+	numHexVerts   = 6
+	numPentVerts  = 5
+
+	epsilon   = 1e-16
+	sqrt3_2   = 0.8660254037844386467637231707529361834714
+	sin60     = sqrt3_2
+	sqrt7     = 2.6457513110645905905016157536392604257102
+	rsqrt7    = 0.37796447300922722721451653623418006081576
+	oneThird  = 1.0 / 3.0
+	twoPi     = 2 * math.Pi
+	ap7RotRad = 0.333473172251832115336090755351601070065900389
+
+	// res0UGnomonic is the scaling factor from resolution 0 unit length
+	// to gnomonic unit length
+	res0UGnomonic    = 0.38196601125010500003
+	invRes0UGnomonic = 2.61803398874989588842
+	// flatEpsilon is the tolerance of vertices meeting an edge intersection,
+	// FLT_EPSILON in the C library
+	flatEpsilon = 1.1920928955078125e-7
+)
+
+// Quadrants of the faceNeighbors table
+const (
+	quadCentral = iota
+	quadIJ
+	quadKI
+	quadJK
+)
+
+// overage is where a substrate cell falls relative to its face
+type overage int
+
+const (
+	noOverage overage = iota // on the original face
+	faceEdge                 // on a face edge, only in substrate grids
+	newFace                  // on the interior of another face
+)
+
+// maxDimByCIIres is the largest ijk+ coordinate sum on a face at each Class
+// II resolution
+var maxDimByCIIres = [...]int{2, -1, 14, -1, 98, -1, 686, -1, 4802, -1, 33614, -1, 235298, -1, 1647086, -1, 11529602}
+
+// unitScaleByCIIres is the unit length at each Class II resolution relative
+// to resolution 0
+var unitScaleByCIIres = [...]int{1, -1, 7, -1, 49, -1, 343, -1, 2401, -1, 16807, -1, 117649, -1, 823543, -1, 5764801}
+
+// latLng is a point on the sphere in radians
+type latLng struct {
+	lat, lng float64
+}
+
+// vec3d is a point in 3D space
+type vec3d struct {
+	x, y, z float64
+}
+
+// faceIJK is a cell address relative to an icosahedron face
+type faceIJK struct {
+	face  int
+	coord coordIJK
+}
+
+// faceOrientIJK is the transform into an adjacent face's coordinates
+type faceOrientIJK struct {
+	face      int
+	translate coordIJK // resolution 0 translation relative to the primary face
+	ccwRot60  int
+}
+
+// baseCellRotation is a base cell and the ccw rotations into its orientation
+type baseCellRotation struct {
+	baseCell int
+	ccwRot60 int
+}
+
+// baseCellInfo is the home face of a base cell and its pentagon data
+type baseCellInfo struct {
+	homeFijk     faceIJK
+	isPentagon   bool
+	cwOffsetPent [2]int
+}
+
+// isClassIII reports whether a resolution is rotated from the icosahedron
+func isClassIII(res int) bool {
+	return res%2 == 1
+}
+
+// posAngle returns an angle normalized to [0, 2pi)
+func posAngle(rads float64) float64 {
+	tmp := rads
+	if rads < 0 {
+		tmp = rads + twoPi
+	}
+	if rads >= twoPi {
+		tmp -= twoPi
+	}
+	return tmp
+}
+
+// constrainLng returns a longitude normalized to [-pi, pi]
+func constrainLng(lng float64) float64 {
+	for lng > math.Pi {
+		lng -= twoPi
+	}
+	for lng < -math.Pi {
+		lng += twoPi
+	}
+	return lng
+}
+
+// azimuth returns the azimuth from p1 to p2 in radians
+func azimuth(p1, p2 latLng) float64 {
+	return math.Atan2(math.Cos(p2.lat)*math.Sin(p2.lng-p1.lng),
+		math.Cos(p1.lat)*math.Sin(p2.lat)-math.Sin(p1.lat)*math.Cos(p2.lat)*math.Cos(p2.lng-p1.lng))
+}
+
+// azDistance returns the point a distance in radians along an azimuth from p1
+func azDistance(p1 latLng, az, distance float64) latLng {
+	if distance < epsilon {
+		return p1
+	}
+
+	var p2 latLng
+	az = posAngle(az)
+
+	// due north or south
+	if az < epsilon || math.Abs(az-math.Pi) < epsilon {
+		if az < epsilon {
+			p2.lat = p1.lat + distance
+		} else {
+			p2.lat = p1.lat - distance
+		}
+		switch {
+		case math.Abs(p2.lat-math.Pi/2) < epsilon:
+			p2 = latLng{math.Pi / 2, 0}
+		case math.Abs(p2.lat+math.Pi/2) < epsilon:
+			p2 = latLng{-math.Pi / 2, 0}
+		default:
+			p2.lng = constrainLng(p1.lng)
+		}
+		return p2
+	}
+
+	sinlat := math.Sin(p1.lat)*math.Cos(distance) + math.Cos(p1.lat)*math.Sin(distance)*math.Cos(az)
+	p2.lat = math.Asin(clamp(sinlat))
+	switch {
+	case math.Abs(p2.lat-math.Pi/2) < epsilon:
+		p2 = latLng{math.Pi / 2, 0}
+	case math.Abs(p2.lat+math.Pi/2) < epsilon:
+		p2 = latLng{-math.Pi / 2, 0}
+	default:
+		invcosp2lat := 1 / math.Cos(p2.lat)
+		sinlng := math.Sin(az) * math.Sin(distance) * invcosp2lat
+		coslng := (math.Cos(distance) - math.Sin(p1.lat)*math.Sin(p2.lat)) / math.Cos(p1.lat) * invcosp2lat
+		p2.lng = constrainLng(p1.lng + math.Atan2(clamp(sinlng), clamp(coslng)))
+	}
+	return p2
+}
+
+// clamp limits a sine or cosine to [-1, 1]
+func clamp(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+// geoToFaceIJK returns the address of the cell containing a point
+func geoToFaceIJK(g latLng, res int) faceIJK {
+	face, v := geoToHex2d(g, res)
+	return faceIJK{face: face, coord: hex2dToCoordIJK(v)}
+}
+
+// geoToHex2d returns the icosahedron face containing a point and the
+// point's position on the face plane
+func geoToHex2d(g latLng, res int) (int, vec2d) {
+	// the closest face center by squared euclidean distance
+	r := math.Cos(g.lat)
+	p := vec3d{x: math.Cos(g.lng) * r, y: math.Sin(g.lng) * r, z: math.Sin(g.lat)}
+	face, sqd := 0, 5.0
+	for f, c := range faceCenterPoint {
+		d := (c.x-p.x)*(c.x-p.x) + (c.y-p.y)*(c.y-p.y) + (c.z-p.z)*(c.z-p.z)
+		if d < sqd {
+			face, sqd = f, d
+		}
+	}
+
+	// cos(r) = 1 - 2 * sin^2(r/2) = 1 - 2 * (sqd / 4) = 1 - sqd/2
+	dist := math.Acos(1 - sqd*0.5)
+	if dist < epsilon {
+		return face, vec2d{}
+	}
+
+	// the ccw angle from the Class II i-axis
+	theta := posAngle(faceAxesAzRadsCII[face][0] - posAngle(azimuth(faceCenterGeo[face], g)))
+	if isClassIII(res) {
+		theta = posAngle(theta - ap7RotRad)
+	}
+
+	// gnomonic scaling to the resolution's unit length
+	dist = math.Tan(dist) * invRes0UGnomonic
+	for i := 0; i < res; i++ {
+		dist *= sqrt7
+	}
+	return face, vec2d{x: dist * math.Cos(theta), y: dist * math.Sin(theta)}
+}
+
+// hex2dToGeo returns the point at a position on a face plane; substrate
+// positions are on the aperture 3 grid of vertices
+func hex2dToGeo(v vec2d, face, res int, substrate bool) latLng {
+	r := math.Sqrt(v.x*v.x + v.y*v.y)
+	if r < epsilon {
+		return faceCenterGeo[face]
+	}
+
+	theta := math.Atan2(v.y, v.x)
+	for i := 0; i < res; i++ {
+		r *= rsqrt7
+	}
+	if substrate {
+		r *= oneThird
+		if isClassIII(res) {
+			r *= rsqrt7
+		}
+	}
+
+	// inverse gnomonic scaling
+	r = math.Atan(r * res0UGnomonic)
+
+	// a substrate grid is already adjusted for Class III
+	if !substrate && isClassIII(res) {
+		theta = posAngle(theta + ap7RotRad)
+	}
+	theta = posAngle(faceAxesAzRadsCII[face][0] - theta)
+	return azDistance(faceCenterGeo[face], theta, r)
+}
+
+// toGeo returns the center of a cell
+func (f faceIJK) toGeo(res int) latLng {
+	return hex2dToGeo(f.coord.hex2d(), f.face, res, false)
+}
+
+// substrateVertices returns the vertices of a cell as addresses in the
+// substrate grid and the substrate resolution
+func (f faceIJK) substrateVertices(res, count int) ([]faceIJK, int) {
+	// vertices of an origin-centered cell ccw from the i-axis, in the
+	// aperture 33r substrate grid of Class II and 33r7r of Class III
+	vertsCII := [numHexVerts]coordIJK{{2, 1, 0}, {1, 2, 0}, {0, 2, 1}, {0, 1, 2}, {1, 0, 2}, {2, 0, 1}}
+	vertsCIII := [numHexVerts]coordIJK{{5, 4, 0}, {1, 5, 0}, {0, 5, 4}, {0, 1, 5}, {4, 0, 5}, {5, 0, 1}}
+	verts := vertsCII
+	if isClassIII(res) {
+		verts = vertsCIII
+	}
+
+	center := f.coord.downAp3().downAp3r()
+	if isClassIII(res) {
+		center = center.downAp7r()
+		res++
+	}
+
+	out := make([]faceIJK, count)
+	for v := range out {
+		out[v] = faceIJK{face: f.face, coord: center.add(verts[v]).normalize()}
+	}
+	return out, res
+}
+
+// adjustOverageClassII moves an address on a Class II grid onto the face it
+// lies on, reporting whether it moved
+func (f *faceIJK) adjustOverageClassII(res int, pentLeading4, substrate bool) overage {
+	maxDim := maxDimByCIIres[res]
+	if substrate {
+		maxDim *= 3
+	}
+
+	ijk := &f.coord
+	sum := ijk.i + ijk.j + ijk.k
+	if substrate && sum == maxDim {
+		return faceEdge
+	}
+	if sum <= maxDim {
+		return noOverage
+	}
+
+	var orient faceOrientIJK
+	switch {
+	case ijk.k > 0 && ijk.j > 0:
+		orient = faceNeighbors[f.face][quadJK]
+	case ijk.k > 0:
+		orient = faceNeighbors[f.face][quadKI]
+		// adjust for the pentagonal missing sequence
+		if pentLeading4 {
+			origin := coordIJK{maxDim, 0, 0}
+			*ijk = ijk.sub(origin).rotate60cw().add(origin)
+		}
+	default:
+		orient = faceNeighbors[f.face][quadIJ]
+	}
+
+	f.face = orient.face
+	for i := 0; i < orient.ccwRot60; i++ {
+		*ijk = ijk.rotate60ccw()
+	}
+	unitScale := unitScaleByCIIres[res]
+	if substrate {
+		unitScale *= 3
+	}
+	*ijk = ijk.add(orient.translate.scale(unitScale)).normalize()
+
+	// overage points on pentagon boundaries can end up on edges
+	if substrate && ijk.i+ijk.j+ijk.k == maxDim {
+		return faceEdge
+	}
+	return newFace
+}
+
+// adjustPentVertOverage moves a pentagon vertex in a substrate grid onto
+// the face it lies on
+func (f *faceIJK) adjustPentVertOverage(res int) {
+	for f.adjustOverageClassII(res, false, true) == newFace {
+	}
+}
+
+// faceEdgeVertices returns the end points of a face edge on the plane of a
+// substrate grid
+func faceEdgeVertices(res, quadrant int) (vec2d, vec2d) {
+	maxDim := float64(maxDimByCIIres[res])
+	v0 := vec2d{3 * maxDim, 0}
+	v1 := vec2d{-1.5 * maxDim, 3 * sqrt3_2 * maxDim}
+	v2 := vec2d{-1.5 * maxDim, -3 * sqrt3_2 * maxDim}
+	switch quadrant {
+	case quadIJ:
+		return v0, v1
+	case quadJK:
+		return v1, v2
+	default:
+		return v2, v0
+	}
+}
+
+// intersect returns the intersection of the lines p0-p1 and p2-p3
+func intersect(p0, p1, p2, p3 vec2d) vec2d {
+	s1 := vec2d{p1.x - p0.x, p1.y - p0.y}
+	s2 := vec2d{p3.x - p2.x, p3.y - p2.y}
+	t := (s2.x*(p0.y-p2.y) - s2.y*(p0.x-p2.x)) / (-s2.x*s1.y + s1.x*s2.y)
+	return vec2d{p0.x + t*s1.x, p0.y + t*s1.y}
+}
+
+// almostEquals reports whether two points on a face plane coincide
+func (v vec2d) almostEquals(o vec2d) bool {
+	return math.Abs(v.x-o.x) < flatEpsilon && math.Abs(v.y-o.y) < flatEpsilon
+}
+
+// hexBoundary returns the vertices of a hexagon, adding a vertex where an
+// edge crosses an icosahedron edge
+func (f faceIJK) hexBoundary(res int) []latLng {
+	verts, adjRes := f.substrateVertices(res, numHexVerts)
+
+	var out []latLng
+	lastFace, lastOverage := -1, noOverage
+	// one more iteration checks the last edge for a crossing
+	for vert := 0; vert <= numHexVerts; vert++ {
+		v := vert % numHexVerts
+		fijk := verts[v]
+		over := fijk.adjustOverageClassII(adjRes, false, true)
+
+		// Class II cell edges have vertices on the face edge, with no edge
+		// line intersections
+		if isClassIII(res) && vert > 0 && fijk.face != lastFace && lastOverage != faceEdge {
+			lastV := (v + 5) % numHexVerts
+			orig0 := verts[lastV].coord.hex2d()
+			orig1 := verts[v].coord.hex2d()
+
+			face2 := lastFace
+			if lastFace == f.face {
+				face2 = fijk.face
+			}
+			edge0, edge1 := faceEdgeVertices(adjRes, adjacentFaceDir[f.face][face2])
+
+			// an intersection at a vertex needs no extra vertex
+			inter := intersect(orig0, orig1, edge0, edge1)
+			if !orig0.almostEquals(inter) && !orig1.almostEquals(inter) {
+				out = append(out, hex2dToGeo(inter, f.face, adjRes, true))
+			}
+		}
+
+		if vert < numHexVerts {
+			out = append(out, hex2dToGeo(fijk.coord.hex2d(), fijk.face, adjRes, true))
+		}
+		lastFace, lastOverage = fijk.face, over
+	}
+	return out
+}
+
+// pentBoundary returns the vertices of a pentagon, adding a vertex where an
+// edge crosses an icosahedron edge
+func (f faceIJK) pentBoundary(res int) []latLng {
+	verts, adjRes := f.substrateVertices(res, numPentVerts)
+
+	var out []latLng
+	var last faceIJK
+	// one more iteration checks the last edge for a crossing
+	for vert := 0; vert <= numPentVerts; vert++ {
+		fijk := verts[vert%numPentVerts]
+		fijk.adjustPentVertOverage(adjRes)
+
+		// all Class III pentagon edges cross icosahedron edges
+		if isClassIII(res) && vert > 0 {
+			orig0 := last.coord.hex2d()
+
+			// the current vertex in the coordinates of the last face
+			orient := faceNeighbors[fijk.face][adjacentFaceDir[fijk.face][last.face]]
+			tmp := faceIJK{face: orient.face, coord: fijk.coord}
+			for i := 0; i < orient.ccwRot60; i++ {
+				tmp.coord = tmp.coord.rotate60ccw()
+			}
+			tmp.coord = tmp.coord.add(orient.translate.scale(unitScaleByCIIres[adjRes] * 3)).normalize()
+			orig1 := tmp.coord.hex2d()
+
+			edge0, edge1 := faceEdgeVertices(adjRes, adjacentFaceDir[tmp.face][fijk.face])
+			out = append(out, hex2dToGeo(intersect(orig0, orig1, edge0, edge1), tmp.face, adjRes, true))
+		}
+
+		if vert < numPentVerts {
+			out = append(out, hex2dToGeo(fijk.coord.hex2d(), fijk.face, adjRes, true))
+		}
+		last = fijk
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+// Package h3 implements the cell indexing of Uber's H3 hierarchical
+// hexagonal grid: encoding points as cells, cell boundaries and grid disks.
+//
+// It is a pure Go port of the H3 C library (https://github.com/uber/h3),
+// Copyright Uber Technologies, Inc., licensed under the Apache License 2.0,
+// so that the server keeps building without cgo.
+package h3
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// MaxResolution is the finest H3 resolution (about 0.9 m² cells)
+const MaxResolution = 15
+
+// Cell is an H3 cell index
+type Cell uint64
+
+// LatLngToCell returns the cell containing a coordinate at a resolution
+// (0-15)
+func LatLngToCell(lat, lon float64, res int) (Cell, error) {
+	if res < 0 || res > MaxResolution {
+		return 0, fmt.Errorf("H3 resolution must be between 0 and %d", MaxResolution)
+	}
+	if err := geo.ValidateCoords(lat, lon); err != nil {
+		return 0, err
+	}
+
+	g := latLng{lat: lat * (math.Pi / 180), lng: lon * (math.Pi / 180)}
+	h := faceIJKToIndex(geoToFaceIJK(g, res), res)
+	if h == 0 {
+		return 0, fmt.Errorf("no H3 cell at %f,%f", lat, lon)
+	}
+	return Cell(h), nil
+}
+
+// ParseCell parses the hexadecimal form of a cell index, as in
+// "8928308280fffff"
+func ParseCell(s string) (Cell, error) {
+	h, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x"), 16, 64)
+	if err != nil || !Cell(h).IsValid() {
+		return 0, fmt.Errorf("invalid H3 cell %q", s)
+	}
+	return Cell(h), nil
+}
+
+// String returns the hexadecimal form of the cell index
+func (c Cell) String() string {
+	return strconv.FormatUint(uint64(c), 16)
+}
+
+// Resolution returns the resolution of the cell
+func (c Cell) Resolution() int {
+	return getResolution(uint64(c))
+}
+
+// IsPentagon reports whether the cell is one of the twelve pentagons at its
+// resolution
+func (c Cell) IsPentagon() bool {
+	return isPentagon(uint64(c))
+}
+
+// IsValid reports whether the index is a valid H3 cell
+func (c Cell) IsValid() bool {
+	h := uint64(c)
+	if h&highBitMask != 0 || (h&modeMask)>>modeOffset != cellMode || h&reservedMask != 0 {
+		return false
+	}
+	bc := getBaseCell(h)
+	if bc >= numBaseCells {
+		return false
+	}
+	res := getResolution(h)
+	for r := 1; r <= MaxResolution; r++ {
+		d := getDigit(h, r)
+		if (r <= res) == (d == invalidDigit) {
+			return false
+		}
+	}
+	// pentagons have no k-axes subsequence
+	return !isBaseCellPentagon(bc) || leadingNonZeroDigit(h) != kAxesDigit
+}
+
+// Center returns the center of the cell
+func (c Cell) Center() geo.Location {
+	return toLocation(indexToFaceIJK(uint64(c)).toGeo(c.Resolution()))
+}
+
+// Boundary returns the vertices of the cell counter-clockwise, without
+// repeating the first. Edges crossing an icosahedron edge add a vertex, so a
+// hexagon may have up to 10.
+func (c Cell) Boundary() []geo.Location {
+	fijk := indexToFaceIJK(uint64(c))
+	var verts []latLng
+	if c.IsPentagon() {
+		verts = fijk.pentBoundary(c.Resolution())
+	} else {
+		verts = fijk.hexBoundary(c.Resolution())
+	}
+
+	boundary := make([]geo.Location, len(verts))
+	for i, v := range verts {
+		boundary[i] = toLocation(v)
+	}
+	return boundary
+}
+
+// GridDisk returns the cells within k steps of a cell, including the cell,
+// ordered by distance and then index
+func GridDisk(origin Cell, k int) ([]Cell, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("k must not be negative")
+	}
+	if !origin.IsValid() {
+		return nil, fmt.Errorf("invalid H3 cell %q", origin)
+	}
+
+	// breadth first, since distortion around pentagons rules out walking
+	// the rings directly
+	distances := map[Cell]int{origin: 0}
+	frontier := []Cell{origin}
+	for step := 1; step <= k; step++ {
+		var next []Cell
+		for _, cell := range frontier {
+			for _, dir := range []direction{jAxesDigit, jkAxesDigit, kAxesDigit, ikAxesDigit, iAxesDigit, ijAxesDigit} {
+				h, _, ok := neighborRotations(uint64(cell), dir, 0)
+				if !ok {
+					continue
+				}
+				if _, seen := distances[Cell(h)]; !seen {
+					distances[Cell(h)] = step
+					next = append(next, Cell(h))
+				}
+			}
+		}
+		frontier = next
+	}
+
+	cells := make([]Cell, 0, len(distances))
+	for cell := range distances {
+		cells = append(cells, cell)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if distances[cells[i]] != distances[cells[j]] {
+			return distances[cells[i]] < distances[cells[j]]
+		}
+		return cells[i] < cells[j]
+	})
+	return cells, nil
+}
+
+// toLocation converts a point in radians to degrees
+func toLocation(g latLng) geo.Location {
+	return geo.Location{Latitude: g.lat * (180 / math.Pi), Longitude: g.lng * (180 / math.Pi)}
+}
@@ -0,0 +1,138 @@
+package h3
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// Expected values are from the H3 C library
+
+func TestLatLngToCell(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		res      int
+		want     string
+		center   geo.Location
+	}{
+		{"Sunnyvale", 37.3615593, -122.0553238, 7, "87283472bffffff", geo.Location{Latitude: 37.35172, Longitude: -122.05033}},
+		{"Westminster", 51.5007, -0.1246, 9, "89194ad14c3ffff", geo.Location{Latitude: 51.49987, Longitude: -0.12607}},
+		{"Origin", 0, 0, 0, "8075fffffffffff", geo.Location{Latitude: 2.30088, Longitude: -5.24539}},
+		{"Sydney finest", -33.8568, 151.2153, 15, "8fbe0e35c0942e5", geo.Location{Latitude: -33.85680, Longitude: 151.21530}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LatLngToCell(tt.lat, tt.lon, tt.res)
+			if err != nil {
+				t.Fatalf("LatLngToCell() error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("LatLngToCell() = %s, want %s", got, tt.want)
+			}
+			if got.Resolution() != tt.res {
+				t.Errorf("Resolution() = %d, want %d", got.Resolution(), tt.res)
+			}
+			center := got.Center()
+			if math.Abs(center.Latitude-tt.center.Latitude) > 1e-5 || math.Abs(center.Longitude-tt.center.Longitude) > 1e-5 {
+				t.Errorf("Center() = %+v, want %+v", center, tt.center)
+			}
+		})
+	}
+
+	if _, err := LatLngToCell(0, 0, 16); err == nil {
+		t.Error("expected error for resolution 16")
+	}
+	if _, err := LatLngToCell(91, 0, 5); err == nil {
+		t.Error("expected error for invalid latitude")
+	}
+}
+
+func TestParseCell(t *testing.T) {
+	cell, err := ParseCell(" 8928308280FFFFF ")
+	if err != nil {
+		t.Fatalf("ParseCell() error: %v", err)
+	}
+	if cell.String() != "8928308280fffff" || cell.Resolution() != 9 {
+		t.Errorf("ParseCell() = %s at resolution %d", cell, cell.Resolution())
+	}
+
+	for _, s := range []string{"", "xyz", "8928308280ffff7", "0", "ffffffffffffffff", "8019fffffffffff1"} {
+		if _, err := ParseCell(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+	// The deleted k-axes subsequence of a pentagon
+	if _, err := ParseCell("81093ffffffffff"); err != nil {
+		t.Errorf("unexpected error for a child of a pentagon: %v", err)
+	}
+	if _, err := ParseCell("81087ffffffffff"); err == nil {
+		t.Error("expected error for the deleted subsequence of a pentagon")
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	tests := []struct {
+		cell  string
+		verts int
+		first geo.Location
+	}{
+		{"87283472bffffff", 6, geo.Location{Latitude: 37.34110, Longitude: -122.04156}},
+		{"8075fffffffffff", 5, geo.Location{Latitude: 11.54530, Longitude: -4.01400}},
+		{"8009fffffffffff", 5, geo.Location{Latitude: 63.09505, Longitude: -10.44498}},
+		// Class III cells crossing icosahedron edges
+		{"81017ffffffffff", 7, geo.Location{Latitude: 73.48746, Longitude: 14.55185}},
+		{"81083ffffffffff", 10, geo.Location{Latitude: 63.32706, Longitude: 4.01262}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cell, func(t *testing.T) {
+			cell, err := ParseCell(tt.cell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			boundary := cell.Boundary()
+			if len(boundary) != tt.verts {
+				t.Fatalf("Boundary() has %d vertices, want %d", len(boundary), tt.verts)
+			}
+			if math.Abs(boundary[0].Latitude-tt.first.Latitude) > 1e-5 || math.Abs(boundary[0].Longitude-tt.first.Longitude) > 1e-5 {
+				t.Errorf("Boundary()[0] = %+v, want %+v", boundary[0], tt.first)
+			}
+		})
+	}
+}
+
+func TestGridDisk(t *testing.T) {
+	cell, _ := ParseCell("8928308280fffff")
+	disk, err := GridDisk(cell, 1)
+	if err != nil {
+		t.Fatalf("GridDisk() error: %v", err)
+	}
+	want := map[string]bool{
+		"8928308280fffff": true, "8928308280bffff": true, "89283082873ffff": true, "89283082877ffff": true,
+		"8928308283bffff": true, "89283082807ffff": true, "89283082803ffff": true,
+	}
+	if len(disk) != len(want) || disk[0] != cell {
+		t.Fatalf("GridDisk() = %v", disk)
+	}
+	for _, c := range disk {
+		if !want[c.String()] {
+			t.Errorf("unexpected cell %s", c)
+		}
+	}
+
+	if disk, _ := GridDisk(cell, 2); len(disk) != 19 {
+		t.Errorf("GridDisk(k=2) has %d cells, want 19", len(disk))
+	}
+
+	// A pentagon has five neighbors
+	pentagon, _ := ParseCell("820807fffffffff")
+	if !pentagon.IsPentagon() {
+		t.Fatal("expected a pentagon")
+	}
+	if disk, _ := GridDisk(pentagon, 1); len(disk) != 6 {
+		t.Errorf("GridDisk() of a pentagon has %d cells, want 6", len(disk))
+	}
+}
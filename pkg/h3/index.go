@@ -0,0 +1,363 @@
+package h3
+
+// Bit layout of a 64-bit H3 index: a reserved high bit, 4 mode bits, 3
+// reserved bits, 4 resolution bits, 7 base cell bits and fifteen 3-bit digits
+const (
+	modeOffset     = 59
+	reservedOffset = 56
+	resOffset      = 52
+	bcOffset       = 45
+	perDigitOffset = 3
+
+	highBitMask  = uint64(1) << 63
+	modeMask     = uint64(15) << modeOffset
+	reservedMask = uint64(7) << reservedOffset
+	resMask      = uint64(15) << resOffset
+	bcMask       = uint64(127) << bcOffset
+	digitMask    = uint64(7)
+
+	// cellMode is the mode of cell indexes
+	cellMode = 1
+	// initIndex has mode 0, resolution 0, base cell 0 and all digits 7
+	initIndex = uint64(35184372088831)
+
+	invalidBaseCell = 127
+)
+
+func getResolution(h uint64) int {
+	return int((h & resMask) >> resOffset)
+}
+
+func setResolution(h uint64, res int) uint64 {
+	return h&^resMask | uint64(res)<<resOffset
+}
+
+func getBaseCell(h uint64) int {
+	return int((h & bcMask) >> bcOffset)
+}
+
+func setBaseCell(h uint64, bc int) uint64 {
+	return h&^bcMask | uint64(bc)<<bcOffset
+}
+
+func getDigit(h uint64, res int) direction {
+	return direction((h >> ((MaxResolution - res) * perDigitOffset)) & digitMask)
+}
+
+func setDigit(h uint64, res int, d direction) uint64 {
+	shift := (MaxResolution - res) * perDigitOffset
+	return h&^(digitMask<<shift) | uint64(d)<<shift
+}
+
+// newIndex returns the cell index of a base cell at a resolution, with all
+// digits up to the resolution set to d
+func newIndex(res, baseCell int, d direction) uint64 {
+	h := initIndex&^modeMask | uint64(cellMode)<<modeOffset
+	h = setResolution(h, res)
+	h = setBaseCell(h, baseCell)
+	for r := 1; r <= res; r++ {
+		h = setDigit(h, r, d)
+	}
+	return h
+}
+
+// leadingNonZeroDigit returns the coarsest non-zero digit of an index
+func leadingNonZeroDigit(h uint64) direction {
+	for r := 1; r <= getResolution(h); r++ {
+		if d := getDigit(h, r); d != centerDigit {
+			return d
+		}
+	}
+	return centerDigit
+}
+
+func isBaseCellPentagon(bc int) bool {
+	return bc >= 0 && bc < numBaseCells && baseCellData[bc].isPentagon
+}
+
+// isBaseCellPolarPentagon reports whether all neighbors of a pentagon base
+// cell are oriented towards it
+func isBaseCellPolarPentagon(bc int) bool {
+	return bc == 4 || bc == 117
+}
+
+func baseCellIsCwOffset(bc, face int) bool {
+	return baseCellData[bc].cwOffsetPent[0] == face || baseCellData[bc].cwOffsetPent[1] == face
+}
+
+func isPentagon(h uint64) bool {
+	return isBaseCellPentagon(getBaseCell(h)) && leadingNonZeroDigit(h) == centerDigit
+}
+
+func rotate60ccw(h uint64) uint64 {
+	for r, res := 1, getResolution(h); r <= res; r++ {
+		h = setDigit(h, r, getDigit(h, r).rotate60ccw())
+	}
+	return h
+}
+
+func rotate60cw(h uint64) uint64 {
+	for r, res := 1, getResolution(h); r <= res; r++ {
+		h = setDigit(h, r, getDigit(h, r).rotate60cw())
+	}
+	return h
+}
+
+// rotatePent60ccw rotates an index about a pentagonal center, skipping the
+// deleted k-axes subsequence
+func rotatePent60ccw(h uint64) uint64 {
+	found := false
+	for r, res := 1, getResolution(h); r <= res; r++ {
+		h = setDigit(h, r, getDigit(h, r).rotate60ccw())
+		if !found && getDigit(h, r) != centerDigit {
+			found = true
+			if leadingNonZeroDigit(h) == kAxesDigit {
+				h = rotate60ccw(h)
+			}
+		}
+	}
+	return h
+}
+
+// faceIJKToIndex returns the index of the cell at a face address, or 0 if
+// the address is out of range
+func faceIJKToIndex(fijk faceIJK, res int) uint64 {
+	h := newIndex(res, 0, invalidDigit)
+
+	// build the digits from the finest resolution up; fijk ends as the
+	// base cell's address on the face
+	ijk := &fijk.coord
+	for r := res - 1; r >= 0; r-- {
+		last := *ijk
+		var lastCenter coordIJK
+		if isClassIII(r + 1) {
+			*ijk = ijk.upAp7()
+			lastCenter = ijk.downAp7()
+		} else {
+			*ijk = ijk.upAp7r()
+			lastCenter = ijk.downAp7r()
+		}
+		h = setDigit(h, r+1, last.sub(lastCenter).normalize().toDigit())
+	}
+
+	if ijk.i > 2 || ijk.j > 2 || ijk.k > 2 {
+		return 0
+	}
+	cell := faceIjkBaseCells[fijk.face][ijk.i][ijk.j][ijk.k]
+	h = setBaseCell(h, cell.baseCell)
+
+	// rotate into the canonical orientation of the base cell
+	if isBaseCellPentagon(cell.baseCell) {
+		// force rotation out of the missing k-axes subsequence
+		if leadingNonZeroDigit(h) == kAxesDigit {
+			if baseCellIsCwOffset(cell.baseCell, fijk.face) {
+				h = rotate60cw(h)
+			} else {
+				h = rotate60ccw(h)
+			}
+		}
+		for i := 0; i < cell.ccwRot60; i++ {
+			h = rotatePent60ccw(h)
+		}
+	} else {
+		for i := 0; i < cell.ccwRot60; i++ {
+			h = rotate60ccw(h)
+		}
+	}
+	return h
+}
+
+// indexToFaceIJK returns the face address of a valid cell index
+func indexToFaceIJK(h uint64) faceIJK {
+	bc := getBaseCell(h)
+	res := getResolution(h)
+
+	// adjust for the pentagonal missing sequence; all of subsequence 5
+	// needs to be adjusted, and some of subsequence 4 below
+	if isBaseCellPentagon(bc) && leadingNonZeroDigit(h) == ikAxesDigit {
+		h = rotate60cw(h)
+	}
+
+	fijk := baseCellData[bc].homeFijk
+	possibleOverage := isBaseCellPentagon(bc) || (res != 0 && fijk.coord != coordIJK{})
+	for r := 1; r <= res; r++ {
+		if isClassIII(r) {
+			fijk.coord = fijk.coord.downAp7()
+		} else {
+			fijk.coord = fijk.coord.downAp7r()
+		}
+		fijk.coord = fijk.coord.neighbor(getDigit(h, r))
+	}
+	if !possibleOverage {
+		return fijk
+	}
+
+	// the cell may lie on an adjacent face; check in the next finer Class
+	// II grid
+	origIJK := fijk.coord
+	adjRes := res
+	if isClassIII(res) {
+		fijk.coord = fijk.coord.downAp7r()
+		adjRes++
+	}
+
+	pentLeading4 := isBaseCellPentagon(bc) && leadingNonZeroDigit(h) == iAxesDigit
+	if fijk.adjustOverageClassII(adjRes, pentLeading4, false) != noOverage {
+		// pentagons may have secondary overages
+		if isBaseCellPentagon(bc) {
+			for fijk.adjustOverageClassII(adjRes, false, false) != noOverage {
+			}
+		}
+		if adjRes != res {
+			fijk.coord = fijk.coord.upAp7r()
+		}
+	} else if adjRes != res {
+		fijk.coord = origIJK
+	}
+	return fijk
+}
+
+// Traversal tables of neighborRotations: the new digit and the move at the
+// next coarser resolution, by current digit and direction
+var (
+	newDigitII = [7][7]direction{
+		{centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit},
+		{kAxesDigit, iAxesDigit, jkAxesDigit, ijAxesDigit, ikAxesDigit, jAxesDigit, centerDigit},
+		{jAxesDigit, jkAxesDigit, kAxesDigit, iAxesDigit, ijAxesDigit, centerDigit, ikAxesDigit},
+		{jkAxesDigit, ijAxesDigit, iAxesDigit, ikAxesDigit, centerDigit, kAxesDigit, jAxesDigit},
+		{iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, jAxesDigit, jkAxesDigit, kAxesDigit},
+		{ikAxesDigit, jAxesDigit, centerDigit, kAxesDigit, jkAxesDigit, ijAxesDigit, iAxesDigit},
+		{ijAxesDigit, centerDigit, ikAxesDigit, jAxesDigit, kAxesDigit, iAxesDigit, jkAxesDigit},
+	}
+	newAdjustmentII = [7][7]direction{
+		{centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, kAxesDigit, centerDigit, kAxesDigit, centerDigit, ikAxesDigit, centerDigit},
+		{centerDigit, centerDigit, jAxesDigit, jkAxesDigit, centerDigit, centerDigit, jAxesDigit},
+		{centerDigit, kAxesDigit, jkAxesDigit, jkAxesDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, centerDigit, centerDigit, centerDigit, iAxesDigit, iAxesDigit, ijAxesDigit},
+		{centerDigit, ikAxesDigit, centerDigit, centerDigit, iAxesDigit, ikAxesDigit, centerDigit},
+		{centerDigit, centerDigit, jAxesDigit, centerDigit, ijAxesDigit, centerDigit, ijAxesDigit},
+	}
+	newDigitIII = [7][7]direction{
+		{centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit},
+		{kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit},
+		{jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit},
+		{jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit},
+		{iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit},
+		{ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit},
+		{ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit},
+	}
+	newAdjustmentIII = [7][7]direction{
+		{centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, kAxesDigit, centerDigit, jkAxesDigit, centerDigit, kAxesDigit, centerDigit},
+		{centerDigit, centerDigit, jAxesDigit, jAxesDigit, centerDigit, centerDigit, ijAxesDigit},
+		{centerDigit, jkAxesDigit, jAxesDigit, jkAxesDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, centerDigit, centerDigit, centerDigit, iAxesDigit, ikAxesDigit, iAxesDigit},
+		{centerDigit, kAxesDigit, centerDigit, centerDigit, ikAxesDigit, ikAxesDigit, centerDigit},
+		{centerDigit, centerDigit, ijAxesDigit, centerDigit, iAxesDigit, centerDigit, ijAxesDigit},
+	}
+)
+
+// neighborRotations returns the neighbor of a cell in a direction rotated
+// ccw by rotations, and the rotations to apply to directions from the
+// neighbor. It returns false moving into the deleted k direction of a
+// pentagon.
+func neighborRotations(origin uint64, dir direction, rotations int) (uint64, int, bool) {
+	current := origin
+
+	rotations %= 6
+	for i := 0; i < rotations; i++ {
+		dir = dir.rotate60ccw()
+	}
+
+	newRotations := 0
+	oldBaseCell := getBaseCell(current)
+	oldLeadingDigit := leadingNonZeroDigit(current)
+
+	// adjust the digits and, if needed, the base cell
+	for r := getResolution(current) - 1; ; r-- {
+		if r == -1 {
+			current = setBaseCell(current, baseCellNeighbors[oldBaseCell][dir])
+			newRotations = baseCellNeighbor60CCWRots[oldBaseCell][dir]
+
+			if getBaseCell(current) == invalidBaseCell {
+				// the deleted k vertex at the base cell level borders a
+				// different neighbor
+				current = setBaseCell(current, baseCellNeighbors[oldBaseCell][ikAxesDigit])
+				newRotations = baseCellNeighbor60CCWRots[oldBaseCell][ikAxesDigit]
+				current = rotate60ccw(current)
+				rotations++
+			}
+			break
+		}
+
+		oldDigit := getDigit(current, r+1)
+		var nextDir direction
+		if isClassIII(r + 1) {
+			current = setDigit(current, r+1, newDigitII[oldDigit][dir])
+			nextDir = newAdjustmentII[oldDigit][dir]
+		} else {
+			current = setDigit(current, r+1, newDigitIII[oldDigit][dir])
+			nextDir = newAdjustmentIII[oldDigit][dir]
+		}
+		if nextDir == centerDigit {
+			break
+		}
+		dir = nextDir
+	}
+
+	newBaseCell := getBaseCell(current)
+	if !isBaseCellPentagon(newBaseCell) {
+		for i := 0; i < newRotations; i++ {
+			current = rotate60ccw(current)
+		}
+		return current, (rotations + newRotations) % 6, true
+	}
+
+	// force rotation out of the missing k-axes subsequence
+	alreadyAdjustedKSubsequence := false
+	if leadingNonZeroDigit(current) == kAxesDigit {
+		if oldBaseCell != newBaseCell {
+			// traversed into the deleted k subsequence of a pentagon base
+			// cell from another base cell
+			if baseCellIsCwOffset(newBaseCell, baseCellData[oldBaseCell].homeFijk.face) {
+				current = rotate60cw(current)
+			} else {
+				current = rotate60ccw(current)
+			}
+			alreadyAdjustedKSubsequence = true
+		} else {
+			// traversed into the deleted k subsequence from within the same
+			// pentagon base cell
+			switch oldLeadingDigit {
+			case jkAxesDigit:
+				current = rotate60ccw(current)
+				rotations++
+			case ikAxesDigit:
+				current = rotate60cw(current)
+				rotations += 5
+			default:
+				return 0, 0, false
+			}
+		}
+	}
+
+	for i := 0; i < newRotations; i++ {
+		current = rotatePent60ccw(current)
+	}
+
+	// account for the differing orientation of the base cells
+	if oldBaseCell != newBaseCell {
+		if isBaseCellPolarPentagon(newBaseCell) {
+			// polar pentagons have all i neighbors
+			if oldBaseCell != 118 && oldBaseCell != 8 && leadingNonZeroDigit(current) != jkAxesDigit {
+				rotations++
+			}
+		} else if leadingNonZeroDigit(current) == ikAxesDigit && !alreadyAdjustedKSubsequence {
+			// distortion introduced to the 5 neighbor by the deleted k
+			// subsequence
+			rotations++
+		}
+	}
+	return current, (rotations + newRotations) % 6, true
+}
@@ -0,0 +1,310 @@
+package monitoring
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Dashboard defaults
+const (
+	DefaultDashboardInterval = 15 * time.Second
+	// dashboardHistory is the number of samples kept per latency sparkline
+	dashboardHistory = 60
+)
+
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// DashboardConfig configures a Dashboard
+type DashboardConfig struct {
+	// Health supplies the health section; nil leaves it out
+	Health *HealthChecker
+
+	// Gatherer supplies the metrics. Nil uses the default registry.
+	Gatherer prometheus.Gatherer
+
+	// Interval is the time between latency samples
+	Interval time.Duration
+
+	// BeforeSample is called before each sample, e.g. to refresh gauges
+	// that are not updated as they change
+	BeforeSample func()
+}
+
+// ToolStats summarizes the calls of one tool
+type ToolStats struct {
+	Tool         string     `json:"tool"`
+	Requests     float64    `json:"requests"`
+	Errors       float64    `json:"errors"`
+	AvgLatencyMs float64    `json:"avg_latency_ms"`
+	LatencyMs    []*float64 `json:"latency_ms"` // average per sample interval, null when idle
+}
+
+// CacheStats summarizes the lookups of one cache
+type CacheStats struct {
+	Cache   string   `json:"cache"`
+	Hits    float64  `json:"hits"`
+	Misses  float64  `json:"misses"`
+	HitRate *float64 `json:"hit_rate"` // null before the first lookup
+	Size    float64  `json:"size"`
+}
+
+// RateLimitStats summarizes the rate limiter of one upstream service
+type RateLimitStats struct {
+	Service         string  `json:"service"`
+	TokensAvailable float64 `json:"tokens_available"`
+	QueueDepth      float64 `json:"queue_depth"`
+	Exceeded        float64 `json:"exceeded"`
+}
+
+// DashboardData is the state shown on the dashboard
+type DashboardData struct {
+	GeneratedAt     time.Time        `json:"generated_at"`
+	IntervalSeconds float64          `json:"interval_seconds"`
+	Health          *ServiceHealth   `json:"health,omitempty"`
+	Tools           []ToolStats      `json:"tools"`
+	Caches          []CacheStats     `json:"caches"`
+	RateLimits      []RateLimitStats `json:"rate_limits"`
+}
+
+// histogramTotals are the cumulative sum and count of a latency histogram
+type histogramTotals struct {
+	sum   float64
+	count uint64
+}
+
+// Dashboard serves a built-in HTML page with health, per-tool latency,
+// cache hit rates and rate limit state read from the Prometheus
+// collectors, for operators without a Grafana stack
+type Dashboard struct {
+	config DashboardConfig
+
+	mu      sync.Mutex
+	history map[string][]*float64 // per tool
+	last    map[string]histogramTotals
+}
+
+// NewDashboard creates a dashboard
+func NewDashboard(config DashboardConfig) *Dashboard {
+	if config.Gatherer == nil {
+		config.Gatherer = prometheus.DefaultGatherer
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultDashboardInterval
+	}
+	return &Dashboard{
+		config:  config,
+		history: make(map[string][]*float64),
+		last:    make(map[string]histogramTotals),
+	}
+}
+
+// Start samples tool latency until the context is cancelled
+func (d *Dashboard) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				_ = d.Sample()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Sample records each tool's average latency since the previous sample
+func (d *Dashboard) Sample() error {
+	if d.config.BeforeSample != nil {
+		d.config.BeforeSample()
+	}
+	families, err := d.gather()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, metric := range families["osmmcp_mcp_request_duration_seconds"].GetMetric() {
+		tool := labelValue(metric, "tool")
+		totals := histogramTotals{sum: metric.GetHistogram().GetSampleSum(), count: metric.GetHistogram().GetSampleCount()}
+		previous, seen := d.last[tool]
+		d.last[tool] = totals
+
+		var point *float64
+		if calls := totals.count - previous.count; seen && calls > 0 {
+			ms := (totals.sum - previous.sum) / float64(calls) * 1000
+			point = &ms
+		}
+		if !seen {
+			continue // the first sample only sets the baseline
+		}
+		history := append(d.history[tool], point)
+		if len(history) > dashboardHistory {
+			history = history[len(history)-dashboardHistory:]
+		}
+		d.history[tool] = history
+	}
+	return nil
+}
+
+// Snapshot returns the current dashboard state
+func (d *Dashboard) Snapshot() (DashboardData, error) {
+	families, err := d.gather()
+	if err != nil {
+		return DashboardData{}, err
+	}
+
+	data := DashboardData{
+		GeneratedAt:     time.Now().UTC(),
+		IntervalSeconds: d.config.Interval.Seconds(),
+		Tools:           []ToolStats{},
+		Caches:          []CacheStats{},
+		RateLimits:      []RateLimitStats{},
+	}
+	if d.config.Health != nil {
+		health := d.config.Health.GetHealth()
+		data.Health = &health
+	}
+
+	// Tools
+	tools := make(map[string]*ToolStats)
+	tool := func(name string) *ToolStats {
+		if tools[name] == nil {
+			tools[name] = &ToolStats{Tool: name}
+		}
+		return tools[name]
+	}
+	for _, metric := range families["osmmcp_mcp_requests_total"].GetMetric() {
+		stats := tool(labelValue(metric, "tool"))
+		stats.Requests += metric.GetCounter().GetValue()
+		if labelValue(metric, "status") == "error" {
+			stats.Errors += metric.GetCounter().GetValue()
+		}
+	}
+	for _, metric := range families["osmmcp_mcp_request_duration_seconds"].GetMetric() {
+		stats := tool(labelValue(metric, "tool"))
+		if count := metric.GetHistogram().GetSampleCount(); count > 0 {
+			stats.AvgLatencyMs = metric.GetHistogram().GetSampleSum() / float64(count) * 1000
+		}
+	}
+	d.mu.Lock()
+	for name, stats := range tools {
+		stats.LatencyMs = append([]*float64{}, d.history[name]...)
+		data.Tools = append(data.Tools, *stats)
+	}
+	d.mu.Unlock()
+	sort.Slice(data.Tools, func(i, j int) bool {
+		if data.Tools[i].Requests != data.Tools[j].Requests {
+			return data.Tools[i].Requests > data.Tools[j].Requests
+		}
+		return data.Tools[i].Tool < data.Tools[j].Tool
+	})
+
+	// Caches
+	caches := make(map[string]*CacheStats)
+	cacheStats := func(name string) *CacheStats {
+		if caches[name] == nil {
+			caches[name] = &CacheStats{Cache: name}
+		}
+		return caches[name]
+	}
+	for _, metric := range families["osmmcp_cache_hits_total"].GetMetric() {
+		cacheStats(labelValue(metric, "cache_type")).Hits = metric.GetCounter().GetValue()
+	}
+	for _, metric := range families["osmmcp_cache_misses_total"].GetMetric() {
+		cacheStats(labelValue(metric, "cache_type")).Misses = metric.GetCounter().GetValue()
+	}
+	for _, metric := range families["osmmcp_cache_size"].GetMetric() {
+		cacheStats(labelValue(metric, "cache_type")).Size = metric.GetGauge().GetValue()
+	}
+	for _, stats := range caches {
+		if lookups := stats.Hits + stats.Misses; lookups > 0 {
+			rate := stats.Hits / lookups
+			stats.HitRate = &rate
+		}
+		data.Caches = append(data.Caches, *stats)
+	}
+	sort.Slice(data.Caches, func(i, j int) bool { return data.Caches[i].Cache < data.Caches[j].Cache })
+
+	// Rate limits
+	limits := make(map[string]*RateLimitStats)
+	limit := func(name string) *RateLimitStats {
+		if limits[name] == nil {
+			limits[name] = &RateLimitStats{Service: name}
+		}
+		return limits[name]
+	}
+	for _, metric := range families["osmmcp_rate_limit_tokens_available"].GetMetric() {
+		limit(labelValue(metric, "service")).TokensAvailable = metric.GetGauge().GetValue()
+	}
+	for _, metric := range families["osmmcp_rate_limit_queue_depth"].GetMetric() {
+		limit(labelValue(metric, "service")).QueueDepth = metric.GetGauge().GetValue()
+	}
+	for _, metric := range families["osmmcp_rate_limit_exceeded_total"].GetMetric() {
+		limit(labelValue(metric, "service")).Exceeded = metric.GetCounter().GetValue()
+	}
+	for _, stats := range limits {
+		data.RateLimits = append(data.RateLimits, *stats)
+	}
+	sort.Slice(data.RateLimits, func(i, j int) bool { return data.RateLimits[i].Service < data.RateLimits[j].Service })
+
+	return data, nil
+}
+
+// PageHandler serves the dashboard page, which polls DataHandler
+func (d *Dashboard) PageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+		_, _ = w.Write(dashboardPage)
+	}
+}
+
+// DataHandler serves the dashboard state as JSON
+func (d *Dashboard) DataHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := d.Snapshot()
+		if err != nil {
+			http.Error(w, "Failed to gather metrics", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(data)
+	}
+}
+
+// gather reads the metric families by name
+func (d *Dashboard) gather() (map[string]*dto.MetricFamily, error) {
+	families, err := d.config.Gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+	return byName, nil
+}
+
+// labelValue returns the value of a metric label, or ""
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboardSnapshot(t *testing.T) {
+	MCPRequestsTotal.Reset()
+	MCPRequestDuration.Reset()
+	CacheHits.Reset()
+	CacheMisses.Reset()
+	CacheSize.Reset()
+	RateLimitTokensAvailable.Reset()
+	RateLimitQueueDepth.Reset()
+	RateLimitExceeded.Reset()
+
+	var sampled int
+	d := NewDashboard(DashboardConfig{BeforeSample: func() { sampled++ }})
+
+	RecordMCPRequest("geocode_address", 100*time.Millisecond, true)
+	if err := d.Sample(); err != nil { // baseline
+		t.Fatal(err)
+	}
+	RecordMCPRequest("geocode_address", 200*time.Millisecond, true)
+	RecordMCPRequest("geocode_address", 400*time.Millisecond, false)
+	RecordMCPRequest("get_route", 50*time.Millisecond, true)
+	if err := d.Sample(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Sample(); err != nil { // idle interval
+		t.Fatal(err)
+	}
+
+	RecordCacheHit("geocode")
+	RecordCacheHit("geocode")
+	RecordCacheHit("geocode")
+	RecordCacheMiss("geocode")
+	UpdateCacheSize("geocode", 42)
+	UpdateRateLimitState("nominatim", 0.5, 2)
+	RecordRateLimitExceeded("nominatim")
+
+	data, err := d.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sampled != 3 {
+		t.Errorf("BeforeSample called %d times, want 3", sampled)
+	}
+
+	if len(data.Tools) != 2 || data.Tools[0].Tool != "geocode_address" {
+		t.Fatalf("tools should be ordered by requests, got %+v", data.Tools)
+	}
+	geocode := data.Tools[0]
+	if geocode.Requests != 3 || geocode.Errors != 1 {
+		t.Errorf("geocode_address: %v requests, %v errors", geocode.Requests, geocode.Errors)
+	}
+	if geocode.AvgLatencyMs < 233 || geocode.AvgLatencyMs > 234 {
+		t.Errorf("average latency = %v ms, want 233.3", geocode.AvgLatencyMs)
+	}
+	if len(geocode.LatencyMs) != 2 || geocode.LatencyMs[0] == nil || math.Abs(*geocode.LatencyMs[0]-300) > 1e-6 || geocode.LatencyMs[1] != nil {
+		t.Errorf("latency samples = %v, want [300 null]", geocode.LatencyMs)
+	}
+	if route := data.Tools[1]; len(route.LatencyMs) != 1 {
+		t.Errorf("a tool first seen in a sample should start its history there, got %v", route.LatencyMs)
+	}
+
+	if len(data.Caches) != 1 || data.Caches[0].HitRate == nil || *data.Caches[0].HitRate != 0.75 || data.Caches[0].Size != 42 {
+		t.Errorf("caches = %+v", data.Caches)
+	}
+	if len(data.RateLimits) != 1 || data.RateLimits[0].TokensAvailable != 0.5 ||
+		data.RateLimits[0].QueueDepth != 2 || data.RateLimits[0].Exceeded != 1 {
+		t.Errorf("rate limits = %+v", data.RateLimits)
+	}
+	if data.Health != nil {
+		t.Error("health should be left out without a health checker")
+	}
+}
+
+func TestDashboardHandlers(t *testing.T) {
+	d := NewDashboard(DashboardConfig{Health: NewHealthChecker("osmmcp", "1.0.0")})
+
+	w := httptest.NewRecorder()
+	d.PageHandler()(w, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") || !strings.Contains(w.Body.String(), "/dashboard/data") {
+		t.Errorf("unexpected page: %s %.100s", w.Header().Get("Content-Type"), w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	d.DataHandler()(w, httptest.NewRequest(http.MethodGet, "/dashboard/data", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	var data DashboardData
+	if err := json.Unmarshal(w.Body.Bytes(), &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Health == nil || data.Health.Service != "osmmcp" || data.Tools == nil {
+		t.Errorf("unexpected data %+v", data)
+	}
+}
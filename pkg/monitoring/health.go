@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"net/http"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -19,6 +21,7 @@ type HealthChecker struct {
 	startTime   time.Time
 	mu          sync.RWMutex
 	connections map[string]*ConnStatus
+	required    map[string]bool // connections that must be up for readiness
 	transport   *TransportInfo
 	ctx         context.Context
 	cancel      context.CancelFunc
@@ -33,6 +36,7 @@ func NewHealthChecker(serviceName, version string) *HealthChecker {
 		version:     version,
 		startTime:   time.Now(),
 		connections: make(map[string]*ConnStatus),
+		required:    make(map[string]bool),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
@@ -67,6 +71,37 @@ func (h *HealthChecker) RemoveConnection(name string) {
 	delete(h.connections, name)
 }
 
+// SetRequired marks connections as required for readiness. The service is
+// not ready until each of them has been checked and is reachable.
+func (h *HealthChecker) SetRequired(names ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.required = make(map[string]bool, len(names))
+	for _, name := range names {
+		h.required[name] = true
+	}
+}
+
+// Ready reports whether the service can serve traffic, and the required
+// connections it is still waiting for
+func (h *HealthChecker) Ready() (bool, []string) {
+	health := h.GetHealth()
+
+	h.mu.RLock()
+	var waiting []string
+	for name := range h.required {
+		conn, ok := health.Connections[name]
+		if !ok || conn.Status == "error" || conn.Status == "disconnected" {
+			waiting = append(waiting, name)
+		}
+	}
+	h.mu.RUnlock()
+	sort.Strings(waiting)
+
+	return health.Status != "unhealthy" && len(waiting) == 0, waiting
+}
+
 // SetTransport updates the transport information
 func (h *HealthChecker) SetTransport(transportType, httpAddr string) {
 	h.mu.Lock()
@@ -180,23 +215,28 @@ func (h *HealthChecker) HealthHandler() http.HandlerFunc {
 	}
 }
 
-// ReadinessHandler returns a simple readiness check
+// ReadinessHandler returns a readiness check that fails while the service
+// is unhealthy or a required connection is unreachable
 func (h *HealthChecker) ReadinessHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ready, waiting := h.Ready()
 		health := h.GetHealth()
 
 		w.Header().Set("Content-Type", "application/json")
 
-		if health.Status == "unhealthy" {
+		if !ready {
 			w.WriteHeader(http.StatusServiceUnavailable)
 		} else {
 			w.WriteHeader(http.StatusOK)
 		}
 
 		response := map[string]interface{}{
-			"ready":  health.Status != "unhealthy",
+			"ready":  ready,
 			"status": health.Status,
 		}
+		if len(waiting) > 0 {
+			response["waiting_for"] = waiting
+		}
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			http.Error(w, fmt.Sprintf("Failed to encode readiness response: %v", err), http.StatusInternalServerError)
@@ -261,25 +301,81 @@ func (h *HealthChecker) Shutdown() {
 	h.cancel()
 }
 
+// Defaults for external service checks
+const (
+	DefaultCheckInterval   = 30 * time.Second
+	DefaultCheckTimeout    = 10 * time.Second
+	DefaultCheckJitter     = 0.2
+	DefaultCheckMaxBackoff = 5 * time.Minute
+)
+
+// MonitorConfig configures a ConnectionMonitor
+type MonitorConfig struct {
+	// Interval between checks while the service is healthy
+	Interval time.Duration
+	// Timeout for a single check
+	Timeout time.Duration
+	// Jitter randomizes each wait by up to this fraction of it (0-1), so
+	// instances started together do not check in lockstep
+	Jitter float64
+	// MaxBackoff caps the wait between checks of a failing service. The
+	// wait doubles with each consecutive failure, starting at Interval.
+	MaxBackoff time.Duration
+}
+
+// DefaultMonitorConfig returns the default check schedule
+func DefaultMonitorConfig() MonitorConfig {
+	return MonitorConfig{
+		Interval:   DefaultCheckInterval,
+		Timeout:    DefaultCheckTimeout,
+		Jitter:     DefaultCheckJitter,
+		MaxBackoff: DefaultCheckMaxBackoff,
+	}
+}
+
 // ConnectionMonitor helps monitor external service connections
 type ConnectionMonitor struct {
 	name          string
 	healthChecker *HealthChecker
-	checkFunc     func() error
+	checkFunc     func(ctx context.Context) error
 	interval      time.Duration
+	config        MonitorConfig
+	random        func() float64 // uniform in [0, 1)
+	failures      int
 	ctx           context.Context
 	cancel        context.CancelFunc
 }
 
-// NewConnectionMonitor creates a new connection monitor
+// NewConnectionMonitor creates a new connection monitor that checks at a
+// fixed interval
 func NewConnectionMonitor(name string, hc *HealthChecker, checkFunc func() error, interval time.Duration) *ConnectionMonitor {
+	return NewConnectionMonitorWithConfig(name, hc, func(context.Context) error {
+		return checkFunc()
+	}, MonitorConfig{Interval: interval})
+}
+
+// NewConnectionMonitorWithConfig creates a connection monitor with jitter,
+// backoff and a per-check timeout
+func NewConnectionMonitorWithConfig(name string, hc *HealthChecker, checkFunc func(ctx context.Context) error, config MonitorConfig) *ConnectionMonitor {
+	if config.Interval <= 0 {
+		config.Interval = DefaultCheckInterval
+	}
+	if config.Jitter < 0 {
+		config.Jitter = 0
+	}
+	if config.Jitter > 1 {
+		config.Jitter = 1
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &ConnectionMonitor{
 		name:          name,
 		healthChecker: hc,
 		checkFunc:     checkFunc,
-		interval:      interval,
+		interval:      config.Interval,
+		config:        config,
+		random:        rand.Float64,
 		ctx:           ctx,
 		cancel:        cancel,
 	}
@@ -297,31 +393,72 @@ func (cm *ConnectionMonitor) Stop() {
 
 // monitor runs the connection monitoring loop
 func (cm *ConnectionMonitor) monitor() {
-	// Initial check
-	cm.performCheck()
-
-	ticker := time.NewTicker(cm.interval)
-	defer ticker.Stop()
+	// Spread the first checks of monitors started together
+	if !cm.sleep(time.Duration(cm.random() * cm.config.Jitter * float64(cm.interval))) {
+		return
+	}
 
 	for {
-		select {
-		case <-cm.ctx.Done():
+		cm.performCheck()
+		if !cm.sleep(cm.nextDelay()) {
 			return
-		case <-ticker.C:
-			cm.performCheck()
 		}
 	}
 }
 
+// sleep waits for d, returning false if the monitor was stopped
+func (cm *ConnectionMonitor) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return cm.ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-cm.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// nextDelay returns the wait before the next check: the interval, doubled
+// for each consecutive failure up to MaxBackoff, with jitter applied
+func (cm *ConnectionMonitor) nextDelay() time.Duration {
+	delay := cm.interval
+	if cm.failures > 0 && cm.config.MaxBackoff > cm.interval {
+		for i := 0; i < cm.failures && delay < cm.config.MaxBackoff; i++ {
+			delay *= 2
+		}
+		if delay > cm.config.MaxBackoff {
+			delay = cm.config.MaxBackoff
+		}
+	}
+
+	// Uniform in [delay*(1-jitter), delay*(1+jitter))
+	factor := 1 + cm.config.Jitter*(2*cm.random()-1)
+	return time.Duration(float64(delay) * factor)
+}
+
 // performCheck executes the health check and updates status
 func (cm *ConnectionMonitor) performCheck() {
+	ctx := cm.ctx
+	if cm.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cm.config.Timeout)
+		defer cancel()
+	}
+
 	start := time.Now()
-	err := cm.checkFunc()
+	err := cm.checkFunc(ctx)
 	latency := time.Since(start).Milliseconds()
 
 	status := "connected"
 	if err != nil {
 		status = "error"
+		cm.failures++
+	} else {
+		cm.failures = 0
 	}
 
 	cm.healthChecker.UpdateConnection(cm.name, status, latency, err)
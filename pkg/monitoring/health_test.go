@@ -1,6 +1,7 @@
 package monitoring
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -270,6 +271,51 @@ func TestReadinessHandler(t *testing.T) {
 	}
 }
 
+func TestReadinessRequiredBackends(t *testing.T) {
+	hc := NewHealthChecker("test-service", "1.0.0")
+	defer hc.Shutdown()
+
+	hc.SetRequired("osrm")
+	hc.UpdateConnection("nominatim", "connected", 10, nil)
+
+	ready := func() (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		hc.ReadinessHandler()(w, httptest.NewRequest("GET", "/ready", nil))
+		var response map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode readiness response: %v", err)
+		}
+		return w.Code, response
+	}
+
+	// Not checked yet
+	code, response := ready()
+	if code != http.StatusServiceUnavailable || response["ready"] != false {
+		t.Errorf("expected not ready before the first check, got %d %v", code, response)
+	}
+	if waiting, _ := response["waiting_for"].([]interface{}); len(waiting) != 1 || waiting[0] != "osrm" {
+		t.Errorf("expected to wait for osrm, got %v", response["waiting_for"])
+	}
+
+	// Unreachable, although the service as a whole is only degraded
+	hc.UpdateConnection("osrm", "error", 0, errors.New("connection refused"))
+	if code, _ := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while osrm is down, got %d", code)
+	}
+
+	hc.UpdateConnection("osrm", "connected", 5, nil)
+	code, response = ready()
+	if code != http.StatusOK || response["ready"] != true || response["waiting_for"] != nil {
+		t.Errorf("expected ready once osrm is up, got %d %v", code, response)
+	}
+
+	// Optional backends do not gate readiness
+	hc.UpdateConnection("nominatim", "error", 0, errors.New("timeout"))
+	if ok, waiting := hc.Ready(); !ok || len(waiting) != 0 {
+		t.Errorf("expected ready with an optional backend down, got %v %v", ok, waiting)
+	}
+}
+
 func TestLivenessHandler(t *testing.T) {
 	hc := NewHealthChecker("test-service", "1.0.0")
 	defer hc.Shutdown()
@@ -401,6 +447,87 @@ func TestConnectionMonitorStop(t *testing.T) {
 	// but we can at least verify it doesn't panic)
 }
 
+func TestConnectionMonitorBackoff(t *testing.T) {
+	hc := NewHealthChecker("test-service", "1.0.0")
+	defer hc.Shutdown()
+
+	failing := true
+	monitor := NewConnectionMonitorWithConfig("test-monitor", hc, func(ctx context.Context) error {
+		if failing {
+			return errors.New("down")
+		}
+		return nil
+	}, MonitorConfig{Interval: 10 * time.Second, MaxBackoff: 60 * time.Second})
+	defer monitor.Stop()
+
+	if d := monitor.nextDelay(); d != 10*time.Second {
+		t.Errorf("expected the interval before any failure, got %v", d)
+	}
+
+	want := []time.Duration{20 * time.Second, 40 * time.Second, 60 * time.Second, 60 * time.Second}
+	for i, w := range want {
+		monitor.performCheck()
+		if d := monitor.nextDelay(); d != w {
+			t.Errorf("after %d failures: delay %v, want %v", i+1, d, w)
+		}
+	}
+
+	failing = false
+	monitor.performCheck()
+	if d := monitor.nextDelay(); d != 10*time.Second {
+		t.Errorf("expected the interval after recovery, got %v", d)
+	}
+}
+
+func TestConnectionMonitorJitter(t *testing.T) {
+	hc := NewHealthChecker("test-service", "1.0.0")
+	defer hc.Shutdown()
+
+	monitor := NewConnectionMonitorWithConfig("test-monitor", hc, func(ctx context.Context) error { return nil },
+		MonitorConfig{Interval: 10 * time.Second, Jitter: 0.2})
+	defer monitor.Stop()
+
+	for _, tt := range []struct {
+		random float64
+		want   time.Duration
+	}{
+		{0, 8 * time.Second},
+		{0.5, 10 * time.Second},
+		{0.75, 11 * time.Second},
+	} {
+		monitor.random = func() float64 { return tt.random }
+		if d := monitor.nextDelay(); d != tt.want {
+			t.Errorf("random %v: delay %v, want %v", tt.random, d, tt.want)
+		}
+	}
+
+	// Out of range jitter is clamped
+	clamped := NewConnectionMonitorWithConfig("clamped", hc, nil, MonitorConfig{Jitter: 3})
+	if clamped.config.Jitter != 1 || clamped.interval != DefaultCheckInterval {
+		t.Errorf("unexpected config %+v", clamped.config)
+	}
+}
+
+func TestConnectionMonitorTimeout(t *testing.T) {
+	hc := NewHealthChecker("test-service", "1.0.0")
+	defer hc.Shutdown()
+
+	monitor := NewConnectionMonitorWithConfig("slow", hc, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, MonitorConfig{Interval: time.Second, Timeout: 20 * time.Millisecond})
+	defer monitor.Stop()
+
+	monitor.performCheck()
+
+	hc.mu.RLock()
+	conn := hc.connections["slow"]
+	hc.mu.RUnlock()
+	if conn == nil || conn.Status != "error" {
+		t.Fatalf("expected the timed out check to be recorded as an error, got %+v", conn)
+	}
+}
+
 func BenchmarkGetHealth(b *testing.B) {
 	hc := NewHealthChecker("test-service", "1.0.0")
 	defer hc.Shutdown()
@@ -1,6 +1,7 @@
 package monitoring
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -67,6 +68,54 @@ var (
 		[]string{"service"},
 	)
 
+	RateLimitTokensAvailable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "osmmcp_rate_limit_tokens_available",
+			Help: "Requests that can be made to an upstream service without waiting",
+		},
+		[]string{"service"},
+	)
+
+	RateLimitQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "osmmcp_rate_limit_queue_depth",
+			Help: "Requests currently waiting for an upstream service rate limit",
+		},
+		[]string{"service"},
+	)
+
+	UpstreamConnections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osmmcp_upstream_connections_total",
+			Help: "Total number of upstream connections obtained, by whether they were reused from the pool",
+		},
+		[]string{"service", "reused"},
+	)
+
+	// Tile usage policy metrics
+	TilePolicyEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osmmcp_tile_policy_events_total",
+			Help: "Total number of tile usage policy events (warning, throttled, blocked)",
+		},
+		[]string{"event"},
+	)
+
+	TileRevalidations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osmmcp_tile_revalidations_total",
+			Help: "Total number of conditional tile requests, by whether the cached tile was still current",
+		},
+		[]string{"not_modified"},
+	)
+
+	TileFetchesLastHour = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "osmmcp_tile_fetches_last_hour",
+			Help: "Number of upstream tile fetches in the last hour",
+		},
+	)
+
 	// Cache metrics
 	CacheHits = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -92,6 +141,44 @@ var (
 		[]string{"cache_type"},
 	)
 
+	CacheEvictions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osmmcp_cache_pressure_evictions_total",
+			Help: "Total number of cache items evicted under memory pressure",
+		},
+		[]string{"cache_type"},
+	)
+
+	CacheWarmRefreshes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osmmcp_cache_warm_refreshes_total",
+			Help: "Total number of popular cache entries refreshed before expiry, by result",
+		},
+		[]string{"cache_type", "result"},
+	)
+
+	// Memory watchdog metrics
+	ProcessRSS = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "osmmcp_process_rss_bytes",
+			Help: "Resident set size of the process in bytes",
+		},
+	)
+
+	MemoryLimit = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "osmmcp_memory_limit_bytes",
+			Help: "Memory limit enforced by the memory watchdog in bytes",
+		},
+	)
+
+	MemoryPressure = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "osmmcp_memory_pressure",
+			Help: "Memory pressure level (0 normal, 1 high, 2 critical)",
+		},
+	)
+
 	// Connection metrics
 	ActiveConnections = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -110,6 +197,37 @@ var (
 		[]string{"component", "error_type"},
 	)
 
+	// Authentication metrics
+	AuthFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osmmcp_auth_failures_total",
+			Help: "Total number of failed HTTP authentication attempts",
+		},
+		[]string{"reason"}, // "missing", "malformed", "invalid", "inactive", "locked"
+	)
+
+	AuthLockouts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "osmmcp_auth_lockouts_total",
+			Help: "Total number of clients locked out after repeated authentication failures",
+		},
+	)
+
+	AuthLockedClients = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "osmmcp_auth_locked_clients",
+			Help: "Number of clients currently locked out",
+		},
+	)
+
+	// Session metrics
+	OrphanedSessions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "osmmcp_http_sessions_orphaned_total",
+			Help: "Total number of HTTP sessions expired after going idle without being terminated",
+		},
+	)
+
 	// System metrics
 	SystemInfo = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -198,6 +316,24 @@ func UpdateCacheSize(cacheType string, size int) {
 	CacheSize.WithLabelValues(cacheType).Set(float64(size))
 }
 
+func RecordCacheEvictions(cacheType string, evicted int) {
+	CacheEvictions.WithLabelValues(cacheType).Add(float64(evicted))
+}
+
+func RecordCacheWarmRefresh(cacheType string, success bool) {
+	result := "success"
+	if !success {
+		result = "error"
+	}
+	CacheWarmRefreshes.WithLabelValues(cacheType, result).Inc()
+}
+
+func UpdateMemoryPressure(rssBytes, limitBytes uint64, level int) {
+	ProcessRSS.Set(float64(rssBytes))
+	MemoryLimit.Set(float64(limitBytes))
+	MemoryPressure.Set(float64(level))
+}
+
 func RecordRateLimitExceeded(service string) {
 	RateLimitExceeded.WithLabelValues(service).Inc()
 }
@@ -206,6 +342,43 @@ func RecordRateLimitWait(service string, duration time.Duration) {
 	RateLimitWaitTime.WithLabelValues(service).Observe(duration.Seconds())
 }
 
+func UpdateRateLimitState(service string, tokens float64, queueDepth int) {
+	RateLimitTokensAvailable.WithLabelValues(service).Set(tokens)
+	RateLimitQueueDepth.WithLabelValues(service).Set(float64(queueDepth))
+}
+
+func RecordUpstreamConnection(service string, reused bool) {
+	UpstreamConnections.WithLabelValues(service, strconv.FormatBool(reused)).Inc()
+}
+
+func RecordTilePolicyEvent(event string) {
+	TilePolicyEvents.WithLabelValues(event).Inc()
+}
+
+func RecordTileRevalidation(notModified bool) {
+	TileRevalidations.WithLabelValues(strconv.FormatBool(notModified)).Inc()
+}
+
+func UpdateTileFetchesLastHour(count int) {
+	TileFetchesLastHour.Set(float64(count))
+}
+
+func RecordAuthFailure(reason string) {
+	AuthFailures.WithLabelValues(reason).Inc()
+}
+
+func RecordAuthLockout() {
+	AuthLockouts.Inc()
+}
+
+func UpdateAuthLockedClients(count int) {
+	AuthLockedClients.Set(float64(count))
+}
+
+func RecordOrphanedSession() {
+	OrphanedSessions.Inc()
+}
+
 func RecordError(component, errorType string) {
 	ErrorsTotal.WithLabelValues(component, errorType).Inc()
 }
@@ -0,0 +1,139 @@
+package osm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Attribution required wherever OpenStreetMap data is displayed
+const (
+	AttributionNotice     = "© OpenStreetMap contributors"
+	AttributionLicense    = "ODbL-1.0"
+	AttributionLicenseURL = "https://www.openstreetmap.org/copyright"
+)
+
+// maxTimestampPeek bounds how much of an Overpass response is read to find
+// its osm3s timestamp, which precedes the elements
+const maxTimestampPeek = 1024
+
+// osm3sTimestampPattern matches the data timestamp of JSON and XML
+// Overpass responses
+var osm3sTimestampPattern = regexp.MustCompile(`"timestamp_osm_base"\s*:\s*"([^"]+)"|osm_base="([^"]+)"`)
+
+// DataSources records the upstream services a tool call queried and the
+// timestamp of the OSM data they returned
+type DataSources struct {
+	mu        sync.Mutex
+	services  map[string]bool
+	timestamp time.Time
+}
+
+// dataSourcesKey is the context key for DataSources
+type dataSourcesKey struct{}
+
+// WithDataSources returns a context whose upstream requests are recorded
+// in the returned DataSources
+func WithDataSources(ctx context.Context) (context.Context, *DataSources) {
+	sources := &DataSources{services: make(map[string]bool)}
+	return context.WithValue(ctx, dataSourcesKey{}, sources), sources
+}
+
+// dataSourcesFrom returns the DataSources of a context, or nil
+func dataSourcesFrom(ctx context.Context) *DataSources {
+	sources, _ := ctx.Value(dataSourcesKey{}).(*DataSources)
+	return sources
+}
+
+// Services returns the services queried, sorted
+func (d *DataSources) Services() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	services := make([]string, 0, len(d.services))
+	for service := range d.services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// Timestamp returns the oldest data timestamp Overpass reported, or the
+// zero time if none was seen
+func (d *DataSources) Timestamp() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.timestamp
+}
+
+// RecordDataTimestamp records the data timestamp of a result served from
+// cache, which was seen when the result was first fetched
+func RecordDataTimestamp(ctx context.Context, timestamp time.Time) {
+	if sources := dataSourcesFrom(ctx); sources != nil && !timestamp.IsZero() {
+		sources.recordTimestamp(timestamp)
+	}
+}
+
+// record adds a queried service
+func (d *DataSources) record(service string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.services[service] = true
+}
+
+// recordTimestamp keeps the oldest data timestamp, which bounds the age of
+// everything returned
+func (d *DataSources) recordTimestamp(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timestamp.IsZero() || t.Before(d.timestamp) {
+		d.timestamp = t
+	}
+}
+
+// recordDataSource sends a request with roundTrip, recording its service
+// in sources, and the data timestamp of a successful Overpass response
+func recordDataSource(sources *DataSources, req *http.Request, roundTrip func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	service := getServiceFromRequest(req)
+	if service == "unknown" {
+		return roundTrip(req)
+	}
+
+	sources.record(service)
+	resp, err := roundTrip(req)
+	if err != nil || service != "overpass" || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	head := make([]byte, maxTimestampPeek)
+	n, readErr := io.ReadFull(resp.Body, head)
+	head = head[:n]
+	if readErr != nil && readErr != io.ErrUnexpectedEOF && readErr != io.EOF {
+		resp.Body.Close()
+		return nil, readErr
+	}
+	resp.Body = prependBody(head, resp.Body)
+
+	if timestamp, ok := parseOsm3sTimestamp(head); ok {
+		sources.recordTimestamp(timestamp)
+	}
+	return resp, nil
+}
+
+// parseOsm3sTimestamp finds the data timestamp at the start of an Overpass
+// response
+func parseOsm3sTimestamp(head []byte) (time.Time, bool) {
+	match := osm3sTimestampPattern.FindSubmatch(head)
+	if match == nil {
+		return time.Time{}, false
+	}
+	timestamp, err := time.Parse(time.RFC3339, string(match[1])+string(match[2]))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return timestamp, true
+}
@@ -0,0 +1,71 @@
+package osm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDataSourcesRecordOverpassTimestamp(t *testing.T) {
+	const body = `{"version":0.6,"generator":"Overpass API","osm3s":{"timestamp_osm_base":"2024-05-01T10:00:00Z","copyright":"ODbL"},"elements":[]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	oldBase := OverpassBaseURL
+	OverpassBaseURL = srv.URL
+	defer func() { OverpassBaseURL = oldBase }()
+
+	ctx, sources := WithDataSources(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := (&http.Client{Transport: NewTransport(DefaultTransportConfig())}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(data) != body {
+		t.Errorf("body changed while reading the timestamp: %s", data)
+	}
+	if got := sources.Services(); len(got) != 1 || got[0] != "overpass" {
+		t.Errorf("services = %v, want [overpass]", got)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !sources.Timestamp().Equal(want) {
+		t.Errorf("timestamp = %v, want %v", sources.Timestamp(), want)
+	}
+}
+
+func TestParseOsm3sTimestamp(t *testing.T) {
+	for _, head := range []string{
+		`{"osm3s": {"timestamp_osm_base": "2024-05-01T10:00:00Z"}}`,
+		`<?xml version="1.0"?><osm><meta osm_base="2024-05-01T10:00:00Z"/>`,
+	} {
+		if _, ok := parseOsm3sTimestamp([]byte(head)); !ok {
+			t.Errorf("no timestamp found in %s", head)
+		}
+	}
+	if _, ok := parseOsm3sTimestamp([]byte(`{"elements": []}`)); ok {
+		t.Error("found a timestamp in a response without one")
+	}
+}
+
+func TestDataSourcesKeepOldestTimestamp(t *testing.T) {
+	_, sources := WithDataSources(context.Background())
+	newer := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	older := newer.Add(-time.Hour)
+	sources.recordTimestamp(newer)
+	sources.recordTimestamp(older)
+	sources.recordTimestamp(newer)
+	if !sources.Timestamp().Equal(older) {
+		t.Errorf("timestamp = %v, want the oldest %v", sources.Timestamp(), older)
+	}
+	if len(sources.Services()) != 0 {
+		t.Errorf("services = %v, want none", sources.Services())
+	}
+}
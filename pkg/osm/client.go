@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
 	"sync"
@@ -41,12 +42,8 @@ var (
 func init() {
 	// Initialize HTTP client with connection pooling
 	httpClient = &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:        100,
-			MaxIdleConnsPerHost: 10,
-			IdleConnTimeout:     90 * time.Second,
-		},
-		Timeout: 30 * time.Second,
+		Transport: newOSMTransport(DefaultTransportConfig()),
+		Timeout:   30 * time.Second,
 	}
 
 	// Initialize rate limiters with default values
@@ -112,21 +109,19 @@ func waitForRateLimit(ctx context.Context, req *http.Request) error {
 	host := hostFromURL(req.URL.String())
 
 	var service string
-	var limiter *rate.Limiter
 
 	switch host {
 	case hostFromURL(NominatimBaseURL):
 		service = tracing.ServiceNominatim
-		limiter = nominatimLimiter
 	case hostFromURL(OverpassBaseURL):
 		service = tracing.ServiceOverpass
-		limiter = overpassLimiter
 	case hostFromURL(OSRMBaseURL):
 		service = tracing.ServiceOSRM
-		limiter = osrmLimiter
 	default:
 		return nil // No rate limiting for unknown hosts
 	}
+	limiter := limiterForService(service)
+	stats := limiterStatsByService[service]
 
 	// Check if we need to wait
 	if !limiter.Allow() {
@@ -140,11 +135,13 @@ func waitForRateLimit(ctx context.Context, req *http.Request) error {
 			),
 		)
 
-		// Wait for rate limit
+		// Wait for rate limit, tracking the queue for rate limit status
+		reportRateLimitState(service, limiter, stats.enter())
 		err := limiter.Wait(ctx)
 
 		// Record wait duration
 		waitDuration := time.Since(startWait)
+		reportRateLimitState(service, limiter, stats.leave(waitDuration))
 		tracing.SetAttributes(ctx,
 			attribute.String(tracing.AttrRateLimitService, service),
 			attribute.Int64(tracing.AttrRateLimitWaitMs, waitDuration.Milliseconds()),
@@ -158,6 +155,14 @@ func waitForRateLimit(ctx context.Context, req *http.Request) error {
 	return nil
 }
 
+// reportRateLimitState passes a limiter's state to the monitoring hooks
+func reportRateLimitState(service string, limiter *rate.Limiter, queueDepth int) {
+	hooks := getMonitoringHooks()
+	if hooks != nil && hooks.OnRateLimitState != nil {
+		hooks.OnRateLimitState(service, math.Max(limiter.Tokens(), 0), queueDepth)
+	}
+}
+
 // DoRequest performs an HTTP request with rate limiting
 func DoRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
 	// Set User-Agent header
@@ -215,12 +220,11 @@ func (c *Client) SetLogger(logger *slog.Logger) {
 	c.logger = logger
 }
 
-// Health check functions for external services
-// CheckNominatimHealth checks if Nominatim service is available
-func CheckNominatimHealth() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// Health check functions for external services. The caller bounds each
+// check with the context deadline.
 
+// CheckNominatimHealth checks if Nominatim service is available
+func CheckNominatimHealth(ctx context.Context) error {
 	// Make a simple status request to Nominatim
 	req, err := http.NewRequestWithContext(ctx, "GET", NominatimBaseURL+"/status", nil)
 	if err != nil {
@@ -241,10 +245,7 @@ func CheckNominatimHealth() error {
 }
 
 // CheckOverpassHealth checks if Overpass API is available
-func CheckOverpassHealth() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func CheckOverpassHealth(ctx context.Context) error {
 	// Make a simple status request to Overpass
 	req, err := http.NewRequestWithContext(ctx, "GET", OverpassBaseURL, nil)
 	if err != nil {
@@ -268,10 +269,7 @@ func CheckOverpassHealth() error {
 }
 
 // CheckOSRMHealth checks if OSRM service is available
-func CheckOSRMHealth() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func CheckOSRMHealth(ctx context.Context) error {
 	// Make a simple status request to OSRM
 	req, err := http.NewRequestWithContext(ctx, "GET", OSRMBaseURL+"/nearest/v1/driving/0,0", nil)
 	if err != nil {
@@ -20,6 +20,14 @@ type MonitoringHooks struct {
 
 	// OnError is called when an error occurs
 	OnError func(service, errorType string)
+
+	// OnRateLimitState is called when a caller starts or finishes waiting
+	// for a rate limiter, with the tokens available and callers queued
+	OnRateLimitState func(service string, tokens float64, queueDepth int)
+
+	// OnConnection is called when a request obtains a connection, reporting
+	// whether it was reused from the pool
+	OnConnection func(service string, reused bool)
 }
 
 var (
@@ -99,6 +107,9 @@ func getServiceFromRequest(req *http.Request) string {
 	case hostFromURL(OSRMBaseURL):
 		return "osrm"
 	default:
+		if isOverpassMirror(host) {
+			return "overpass"
+		}
 		return "unknown"
 	}
 }
@@ -0,0 +1,305 @@
+// Package openinghours parses the OpenStreetMap opening_hours tag syntax.
+//
+// It supports the subset of the specification that covers the vast majority
+// of tagged features: "24/7", weekday selectors (Mo-Fr, Sa,Su), comma
+// separated time spans (including spans that run past midnight), and
+// "off"/"closed" rules. Later rules override earlier rules for the days they
+// name, as defined by the specification. Public and school holiday rules are
+// ignored. Rules using selectors that are not supported (months, week
+// numbers, sunrise/sunset, ...) are reported as errors so callers can treat
+// the schedule as unknown rather than wrong.
+package openinghours
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+// weekdayNames maps OSM weekday abbreviations to time.Weekday values
+var weekdayNames = map[string]time.Weekday{
+	"Mo": time.Monday,
+	"Tu": time.Tuesday,
+	"We": time.Wednesday,
+	"Th": time.Thursday,
+	"Fr": time.Friday,
+	"Sa": time.Saturday,
+	"Su": time.Sunday,
+}
+
+// span is an opening interval in minutes from local midnight. End may exceed
+// minutesPerDay for spans that run past midnight.
+type span struct {
+	start int
+	end   int
+}
+
+// Schedule is a parsed weekly opening_hours schedule.
+type Schedule struct {
+	raw  string
+	days [7][]span // indexed by time.Weekday
+}
+
+// Parse parses an opening_hours tag value.
+func Parse(value string) (*Schedule, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("empty opening_hours value")
+	}
+
+	s := &Schedule{raw: value}
+
+	// "||" fallback rules are treated like normal rules
+	value = strings.ReplaceAll(value, "||", ";")
+
+	for _, rule := range strings.Split(value, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if err := s.applyRule(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// String returns the original opening_hours value.
+func (s *Schedule) String() string {
+	return s.raw
+}
+
+// applyRule applies a single rule such as "Mo-Fr 08:00-18:00" to the schedule
+func (s *Schedule) applyRule(rule string) error {
+	if rule == "24/7" {
+		for d := range s.days {
+			s.days[d] = []span{{start: 0, end: minutesPerDay}}
+		}
+		return nil
+	}
+
+	// Public and school holiday rules do not affect the regular week
+	if isHolidayRule(rule) {
+		return nil
+	}
+
+	days := allDays()
+	selector, times := splitRule(rule)
+	if selector != "" {
+		var err error
+		days, err = parseWeekdays(selector)
+		if err != nil {
+			return err
+		}
+	}
+
+	var spans []span
+	switch strings.ToLower(times) {
+	case "off", "closed":
+		spans = nil
+	case "", "open":
+		if selector == "" {
+			return fmt.Errorf("rule %q has neither days nor times", rule)
+		}
+		spans = []span{{start: 0, end: minutesPerDay}}
+	default:
+		var err error
+		spans, err = parseSpans(times)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, d := range days {
+		s.days[d] = spans
+	}
+	return nil
+}
+
+// splitRule separates the weekday selector from the time selector
+func splitRule(rule string) (string, string) {
+	first, rest, _ := strings.Cut(rule, " ")
+	if len(first) >= 2 {
+		if _, ok := weekdayNames[first[:2]]; ok {
+			return first, strings.TrimSpace(rest)
+		}
+	}
+	return "", rule
+}
+
+// isHolidayRule reports whether a rule only selects public or school holidays
+func isHolidayRule(rule string) bool {
+	selector, _, _ := strings.Cut(rule, " ")
+	for _, part := range strings.Split(selector, ",") {
+		if part != "PH" && part != "SH" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseWeekdays parses a selector like "Mo-Fr,Su"
+func parseWeekdays(selector string) ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0, 7)
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "PH" || part == "SH" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		start, ok := weekdayNames[from]
+		if !ok {
+			return nil, fmt.Errorf("unsupported day selector %q", part)
+		}
+		if !isRange {
+			days = append(days, start)
+			continue
+		}
+		end, ok := weekdayNames[to]
+		if !ok {
+			return nil, fmt.Errorf("unsupported day selector %q", part)
+		}
+		// Ranges may wrap around the week, e.g. Fr-Mo
+		for d := start; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseSpans parses "08:00-12:00,13:00-18:00"
+func parseSpans(times string) ([]span, error) {
+	spans := make([]span, 0)
+	for _, part := range strings.Split(times, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(part), "-")
+		if !ok {
+			return nil, fmt.Errorf("unsupported time span %q", part)
+		}
+		to = strings.TrimSuffix(to, "+")
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		if end <= start {
+			end += minutesPerDay
+		}
+		spans = append(spans, span{start: start, end: end})
+	}
+	return spans, nil
+}
+
+// parseClock parses "HH:MM" into minutes from midnight, allowing 24:00
+func parseClock(value string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	hours, err := strconv.Atoi(h)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	minutes, err := strconv.Atoi(m)
+	if err != nil || len(m) != 2 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	if hours < 0 || hours > 48 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// allDays returns every weekday
+func allDays() []time.Weekday {
+	return []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+}
+
+// interval is an absolute opening interval
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// intervals returns the merged opening intervals from the day before t to a
+// week after it, in t's location.
+func (s *Schedule) intervals(t time.Time) []interval {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	result := make([]interval, 0)
+	for offset := -1; offset <= 8; offset++ {
+		day := midnight.AddDate(0, 0, offset)
+		for _, sp := range s.days[day.Weekday()] {
+			result = append(result, interval{
+				start: day.Add(time.Duration(sp.start) * time.Minute),
+				end:   day.Add(time.Duration(sp.end) * time.Minute),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].start.Before(result[j].start)
+	})
+
+	merged := make([]interval, 0, len(result))
+	for _, iv := range result {
+		if n := len(merged); n > 0 && !iv.start.After(merged[n-1].end) {
+			if iv.end.After(merged[n-1].end) {
+				merged[n-1].end = iv.end
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// IsOpen reports whether the schedule is open at t. The weekday and clock
+// time are taken from t's location, which should be the feature's local time.
+func (s *Schedule) IsOpen(t time.Time) bool {
+	for _, iv := range s.intervals(t) {
+		if !t.Before(iv.start) && t.Before(iv.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextClose returns when the schedule next closes after t, if it is open at
+// t. It returns false if it is closed at t or does not close within a week.
+func (s *Schedule) NextClose(t time.Time) (time.Time, bool) {
+	horizon := t.AddDate(0, 0, 7)
+	for _, iv := range s.intervals(t) {
+		if !t.Before(iv.start) && t.Before(iv.end) {
+			if iv.end.After(horizon) {
+				return time.Time{}, false
+			}
+			return iv.end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// NextOpen returns when the schedule next opens after t, if it is closed at
+// t. It returns false if it is open at t or does not open within a week.
+func (s *Schedule) NextOpen(t time.Time) (time.Time, bool) {
+	if s.IsOpen(t) {
+		return time.Time{}, false
+	}
+	for _, iv := range s.intervals(t) {
+		if iv.start.After(t) {
+			return iv.start, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,79 @@
+package openinghours
+
+import (
+	"testing"
+	"time"
+)
+
+// 2024-06-03 is a Monday
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, time.June, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseAndIsOpen(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		at    time.Time
+		open  bool
+	}{
+		{"24/7", "24/7", at(3, 3, 0), true},
+		{"Weekday open", "Mo-Fr 08:00-18:00", at(3, 9, 0), true},
+		{"Weekday closed evening", "Mo-Fr 08:00-18:00", at(3, 18, 0), false},
+		{"Weekend closed", "Mo-Fr 08:00-18:00", at(8, 12, 0), false},
+		{"Split shift lunch", "Mo-Fr 08:00-12:00,13:00-18:00", at(4, 12, 30), false},
+		{"Override", "Mo-Su 09:00-17:00; Su off", at(9, 10, 0), false},
+		{"Past midnight", "Fr 20:00-02:00", at(8, 1, 0), true},
+		{"Wrapping day range", "Fr-Mo 10:00-16:00", at(9, 11, 0), true},
+		{"Day list", "Sa,Su 10:00-14:00", at(8, 13, 59), true},
+		{"Holiday rule ignored", "Mo-Fr 08:00-18:00; PH off", at(3, 9, 0), true},
+		{"Times only", "10:00-20:00", at(5, 19, 0), true},
+		{"24:00 end", "Mo-Su 18:00-24:00", at(5, 23, 59), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.value)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.value, err)
+			}
+			if got := s.IsOpen(tt.at); got != tt.open {
+				t.Errorf("IsOpen(%s) = %v, want %v", tt.at, got, tt.open)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, value := range []string{"", "Jan-Mar 10:00-12:00", "Mo-Fr 8-18", "sunrise-sunset"} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestNextCloseAndOpen(t *testing.T) {
+	s, err := Parse("Mo-Fr 08:00-18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closes, ok := s.NextClose(at(3, 17, 15))
+	if !ok || !closes.Equal(at(3, 18, 0)) {
+		t.Errorf("NextClose = %v, %v; want %v", closes, ok, at(3, 18, 0))
+	}
+
+	if _, ok := s.NextClose(at(3, 19, 0)); ok {
+		t.Error("expected no close time while closed")
+	}
+
+	opens, ok := s.NextOpen(at(7, 19, 0))
+	if !ok || !opens.Equal(at(10, 8, 0)) {
+		t.Errorf("NextOpen = %v, %v; want %v", opens, ok, at(10, 8, 0))
+	}
+
+	always, _ := Parse("24/7")
+	if _, ok := always.NextClose(at(3, 12, 0)); ok {
+		t.Error("expected 24/7 schedule to never close")
+	}
+}
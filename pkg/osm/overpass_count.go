@@ -0,0 +1,107 @@
+package osm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm/queries"
+)
+
+// DefaultOverpassElementLimit is the default maximum number of elements a
+// heavy Overpass query may return before it is refused
+const DefaultOverpassElementLimit = 5000
+
+// overpassElementLimit holds the configured element limit, 0 disables it
+var overpassElementLimit atomic.Int64
+
+func init() {
+	overpassElementLimit.Store(DefaultOverpassElementLimit)
+}
+
+// SetOverpassElementLimit sets the maximum number of elements a heavy
+// Overpass query may return. Zero disables the pre-check.
+func SetOverpassElementLimit(limit int) {
+	overpassElementLimit.Store(int64(max(limit, 0)))
+}
+
+// OverpassElementLimit returns the configured element limit
+func OverpassElementLimit() int {
+	return int(overpassElementLimit.Load())
+}
+
+// ElementCount is the number of elements an Overpass query matches
+type ElementCount struct {
+	Nodes     int `json:"nodes"`
+	Ways      int `json:"ways"`
+	Relations int `json:"relations"`
+	Total     int `json:"total"`
+}
+
+// CountOverpassElements runs the "out count;" variant of a query, which
+// returns only element totals, so the size of a query can be checked before
+// downloading its results
+func CountOverpassElements(ctx context.Context, query string) (ElementCount, error) {
+	countQuery := queries.CountQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, OverpassBaseURL,
+		strings.NewReader("data="+url.QueryEscape(countQuery)))
+	if err != nil {
+		return ElementCount{}, fmt.Errorf("failed to create count request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := DoRequest(ctx, req)
+	if err != nil {
+		return ElementCount{}, fmt.Errorf("count request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ElementCount{}, fmt.Errorf("count request returned status %d", resp.StatusCode)
+	}
+
+	return decodeElementCount(resp.Body)
+}
+
+// decodeElementCount parses the response to an "out count;" query
+func decodeElementCount(body io.Reader) (ElementCount, error) {
+	var countResp struct {
+		Elements []struct {
+			Type string            `json:"type"`
+			Tags map[string]string `json:"tags"`
+		} `json:"elements"`
+	}
+	if err := json.NewDecoder(body).Decode(&countResp); err != nil {
+		return ElementCount{}, fmt.Errorf("failed to decode count response: %w", err)
+	}
+
+	// A query with several output statements returns one count per statement
+	var count ElementCount
+	for _, element := range countResp.Elements {
+		if element.Type != "count" {
+			continue
+		}
+		count.Nodes += atoiTag(element.Tags, "nodes")
+		count.Ways += atoiTag(element.Tags, "ways")
+		count.Relations += atoiTag(element.Tags, "relations")
+		count.Total += atoiTag(element.Tags, "total")
+	}
+
+	return count, nil
+}
+
+// atoiTag parses a numeric tag, treating missing or invalid values as zero
+func atoiTag(tags map[string]string, key string) int {
+	n, err := strconv.Atoi(tags[key])
+	if err != nil {
+		return 0
+	}
+	return n
+}
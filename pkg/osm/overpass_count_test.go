@@ -0,0 +1,39 @@
+package osm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeElementCount(t *testing.T) {
+	body := `{"elements":[
+		{"type":"count","id":0,"tags":{"nodes":"120","ways":"30","relations":"2","total":"152"}},
+		{"type":"count","id":0,"tags":{"nodes":"8","ways":"0","relations":"0","total":"8"}}
+	]}`
+
+	count, err := decodeElementCount(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ElementCount{Nodes: 128, Ways: 30, Relations: 2, Total: 160}
+	if count != want {
+		t.Errorf("got %+v, want %+v", count, want)
+	}
+
+	if _, err := decodeElementCount(strings.NewReader("<html>")); err == nil {
+		t.Errorf("expected error for non-JSON response")
+	}
+}
+
+func TestSetOverpassElementLimit(t *testing.T) {
+	defer SetOverpassElementLimit(DefaultOverpassElementLimit)
+
+	SetOverpassElementLimit(100)
+	if got := OverpassElementLimit(); got != 100 {
+		t.Errorf("expected limit 100, got %d", got)
+	}
+	SetOverpassElementLimit(-1)
+	if got := OverpassElementLimit(); got != 0 {
+		t.Errorf("expected negative limit to disable the check, got %d", got)
+	}
+}
@@ -0,0 +1,138 @@
+package osm
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// OverpassErrorHeader carries the error message that Overpass embedded in a
+// non-JSON response, set when the response status is rewritten
+const OverpassErrorHeader = "X-Overpass-Error"
+
+// maxOverpassErrorBody bounds how much of a non-JSON body is inspected
+const maxOverpassErrorBody = 64 << 10
+
+var (
+	// overpassErrorPattern matches the messages of the osm3s HTML and XML
+	// error documents, e.g. <strong ...>Error</strong>: line 1: parse error: ...
+	overpassErrorPattern = regexp.MustCompile(`(?s)<strong[^>]*>Error</strong>:\s*(.*?)</p>|<remark>\s*(.*?)\s*</remark>`)
+	// htmlTagPattern matches markup left in an extracted message
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ClassifyOverpassError reads the error in a non-JSON Overpass body and
+// returns the HTTP status it corresponds to: 429 for rate limiting, 504 for
+// query timeouts and memory exhaustion, 400 for query syntax errors and 502
+// otherwise, with the message Overpass gave.
+func ClassifyOverpassError(body []byte) (int, string) {
+	var messages []string
+	for _, match := range overpassErrorPattern.FindAllSubmatch(body, -1) {
+		text := string(match[1]) + string(match[2])
+		text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, ""))
+		if text = strings.Join(strings.Fields(text), " "); text != "" {
+			messages = append(messages, text)
+		}
+	}
+	message := strings.Join(messages, "; ")
+	if message == "" {
+		message = "unexpected non-JSON response"
+	}
+
+	lower := strings.ToLower(message)
+	switch {
+	case bytes.Contains(body, []byte("rate_limited")), strings.Contains(lower, "too many requests"):
+		return http.StatusTooManyRequests, message
+	case strings.Contains(lower, "timed out"), strings.Contains(lower, "timeout"), strings.Contains(lower, "out of memory"):
+		return http.StatusGatewayTimeout, message
+	case strings.Contains(lower, "parse error"), strings.Contains(lower, "static error"):
+		return http.StatusBadRequest, message
+	}
+	return http.StatusBadGateway, message
+}
+
+// overpassErrorTransport turns Overpass error documents served with status
+// 200 into error statuses, so callers retry rate limits and timeouts and
+// report syntax errors instead of failing to decode HTML as JSON. Rate
+// limited responses get a Retry-After from the instance's status page.
+type overpassErrorTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *overpassErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || getServiceFromRequest(req) != "overpass" {
+		return resp, err
+	}
+	resp, err = rewriteOverpassError(resp)
+	if err == nil {
+		addOverpassRetryAfter(t.base, req, resp)
+	}
+	return resp, err
+}
+
+// CloseIdleConnections closes idle connections of the underlying transport
+func (t *overpassErrorTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// rewriteOverpassError sets the status of a successful response whose body
+// is an HTML or XML error document. Other responses, including OSM XML
+// requested with [out:xml], pass through with their body intact.
+func rewriteOverpassError(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if strings.Contains(contentType, "json") {
+		return resp, nil
+	}
+
+	// Peek at the start of the body; JSON without a content type passes
+	head := make([]byte, 512)
+	n, err := io.ReadFull(resp.Body, head)
+	head = head[:n]
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(head)
+	if len(trimmed) == 0 || trimmed[0] != '<' {
+		resp.Body = prependBody(head, resp.Body)
+		return resp, nil
+	}
+
+	rest, err := io.ReadAll(io.LimitReader(resp.Body, maxOverpassErrorBody))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	body := append(head, rest...)
+	if !overpassErrorPattern.Match(body) && !bytes.Contains(bytes.ToLower(head), []byte("<html")) {
+		resp.Body = prependBody(body, resp.Body)
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	status, message := ClassifyOverpassError(body)
+	resp.StatusCode = status
+	resp.Status = fmt.Sprintf("%d %s", status, http.StatusText(status))
+	resp.Header.Set(OverpassErrorHeader, message)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// prependBody returns a body reading data and then the rest of body
+func prependBody(data []byte, body io.ReadCloser) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), body), body}
+}
@@ -0,0 +1,80 @@
+package osm
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// osm3sErrorPage builds an Overpass HTML error document
+func osm3sErrorPage(message string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en" lang="en">
+<head><title>OSM3S Response</title></head>
+<body>
+<p>The data included in this document is from www.openstreetmap.org. The data is made available under ODbL.</p>
+<p><strong style="color:#FF0000">Error</strong>: ` + message + ` </p>
+</body>
+</html>`
+}
+
+func TestClassifyOverpassError(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"Rate limited", osm3sErrorPage("runtime error: open64: 0 Success /osm3s_v0.7.62_osm_base Dispatcher_Client::request_read_and_idx::rate_limited. Please check /api/status for the quota of your IP address."), http.StatusTooManyRequests, "rate_limited"},
+		{"Timeout", osm3sErrorPage(`runtime error: Query timed out in "query" at line 1 after 26 seconds.`), http.StatusGatewayTimeout, `Query timed out in "query"`},
+		{"Out of memory", osm3sErrorPage("runtime error: Query run out of memory using about 2048 MB of RAM."), http.StatusGatewayTimeout, "out of memory"},
+		{"Syntax error", osm3sErrorPage(`line 1: parse error: Unknown type &quot;nod&quot;`), http.StatusBadRequest, `Unknown type "nod"`},
+		{"XML remark", `<?xml version="1.0"?><osm><remark> runtime error: Query timed out </remark></osm>`, http.StatusGatewayTimeout, "Query timed out"},
+		{"Unknown page", "<html><body>Bad gateway</body></html>", http.StatusBadGateway, "unexpected non-JSON response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, message := ClassifyOverpassError([]byte(tt.body))
+			if status != tt.status || !strings.Contains(message, tt.want) {
+				t.Errorf("got %d %q, want %d containing %q", status, message, tt.status, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteOverpassError(t *testing.T) {
+	response := func(contentType, body string) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	resp, err := rewriteOverpassError(response("text/html", osm3sErrorPage("line 1: parse error: ';' expected")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(resp.Header.Get(OverpassErrorHeader), "';' expected") {
+		t.Errorf("expected a 400 with the parse error, got %d %q", resp.StatusCode, resp.Header.Get(OverpassErrorHeader))
+	}
+
+	// JSON and OSM XML bodies pass through unchanged
+	for _, tt := range []struct{ contentType, body string }{
+		{"application/json", `{"elements": []}`},
+		{"", `{"elements": []}`},
+		{"application/osm3s+xml", `<?xml version="1.0"?><osm version="0.6"><node id="1" lat="0" lon="0"/></osm>`},
+	} {
+		resp, err := rewriteOverpassError(response(tt.contentType, tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || string(data) != tt.body {
+			t.Errorf("expected %q to pass through, got %d %q", tt.body, resp.StatusCode, data)
+		}
+	}
+}
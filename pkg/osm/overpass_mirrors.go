@@ -0,0 +1,260 @@
+package osm
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOverpassMirrors are public Overpass instances tried when the
+// default endpoint fails. They are only used with DefaultOverpassBaseURL, so
+// queries meant for a self-hosted instance never reach a public one unless
+// configured with SetOverpassMirrors.
+var DefaultOverpassMirrors = []string{
+	"https://overpass.kumi.systems/api/interpreter",
+	"https://overpass.private.coffee/api/interpreter",
+}
+
+const (
+	// mirrorCooldown is how long an endpoint is skipped after failing,
+	// doubling with each consecutive failure up to maxMirrorCooldown
+	mirrorCooldown    = 30 * time.Second
+	maxMirrorCooldown = 10 * time.Minute
+	// maxMirrorBackoffShift caps the doubling of mirrorCooldown
+	maxMirrorBackoffShift = 5
+)
+
+// mirrorHealth tracks the failures of an Overpass endpoint
+type mirrorHealth struct {
+	failures  int
+	downUntil time.Time
+}
+
+// mirrorPool holds the Overpass mirrors that requests to OverpassBaseURL
+// fail over to, and the health of every endpoint
+type mirrorPool struct {
+	mu      sync.Mutex
+	mirrors []string
+	health  map[string]*mirrorHealth
+	now     func() time.Time
+}
+
+var overpassMirrors = &mirrorPool{
+	health: make(map[string]*mirrorHealth),
+	now:    time.Now,
+}
+
+// SetOverpassMirrors sets the Overpass endpoints tried, in order, when the
+// one at OverpassBaseURL rate limits or fails. A nil list uses
+// DefaultOverpassMirrors if OverpassBaseURL is the default, and an empty
+// list disables failover. It must be called at startup, after
+// SetServiceURLs.
+func SetOverpassMirrors(mirrors []string) error {
+	if mirrors == nil {
+		if OverpassBaseURL == DefaultOverpassBaseURL {
+			mirrors = DefaultOverpassMirrors
+		} else {
+			mirrors = []string{}
+		}
+	}
+
+	cleaned := make([]string, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		u, err := url.Parse(mirror)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid Overpass mirror URL %q: must be an absolute http or https URL", mirror)
+		}
+		mirror = strings.TrimSuffix(mirror, "/")
+		if mirror != OverpassBaseURL {
+			cleaned = append(cleaned, mirror)
+		}
+	}
+
+	overpassMirrors.mu.Lock()
+	defer overpassMirrors.mu.Unlock()
+	overpassMirrors.mirrors = cleaned
+	overpassMirrors.health = make(map[string]*mirrorHealth)
+	return nil
+}
+
+// OverpassMirrors returns the configured Overpass mirrors
+func OverpassMirrors() []string {
+	overpassMirrors.mu.Lock()
+	defer overpassMirrors.mu.Unlock()
+	return append([]string(nil), overpassMirrors.mirrors...)
+}
+
+// isOverpassMirror reports whether host serves one of the Overpass mirrors
+func isOverpassMirror(host string) bool {
+	overpassMirrors.mu.Lock()
+	defer overpassMirrors.mu.Unlock()
+	for _, mirror := range overpassMirrors.mirrors {
+		if hostFromURL(mirror) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// order returns the endpoints to try: healthy ones in configured order,
+// primary first, then those cooling down, soonest available first
+func (p *mirrorPool) order(primary string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	endpoints := append([]string{primary}, p.mirrors...)
+	now := p.now()
+	var healthy, down []string
+	for _, endpoint := range endpoints {
+		if h := p.health[endpoint]; h != nil && now.Before(h.downUntil) {
+			down = append(down, endpoint)
+		} else {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	sort.SliceStable(down, func(i, j int) bool {
+		return p.health[down[i]].downUntil.Before(p.health[down[j]].downUntil)
+	})
+	return append(healthy, down...)
+}
+
+// failed marks an endpoint down for a cooldown that grows with
+// consecutive failures, or until the time the endpoint asked to be retried
+// at when retryAfter is positive
+func (p *mirrorPool) failed(endpoint string, retryAfter time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h := p.health[endpoint]
+	if h == nil {
+		h = &mirrorHealth{}
+		p.health[endpoint] = h
+	}
+	cooldown := min(mirrorCooldown<<min(h.failures, maxMirrorBackoffShift), maxMirrorCooldown)
+	if retryAfter > 0 {
+		cooldown = min(retryAfter, maxMirrorCooldown)
+	}
+	h.failures++
+	h.downUntil = p.now().Add(cooldown)
+}
+
+// succeeded marks an endpoint healthy
+func (p *mirrorPool) succeeded(endpoint string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.health, endpoint)
+}
+
+// overpassFailoverTransport retries Overpass requests that are rate limited
+// or fail on the next mirror, so Overpass tools keep working while a public
+// instance is overloaded or down
+type overpassFailoverTransport struct {
+	base http.RoundTripper
+	pool *mirrorPool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *overpassFailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	primary := OverpassBaseURL
+	if req.URL.Host != hostFromURL(primary) || len(OverpassMirrors()) == 0 {
+		return t.base.RoundTrip(req)
+	}
+	// A body that cannot be replayed allows a single attempt
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	// Each attempt reads its own copy of the body
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	endpoints := t.pool.order(primary)
+	var resp *http.Response
+	var err error
+	for i, endpoint := range endpoints {
+		attempt, buildErr := retarget(req, endpoint)
+		if buildErr != nil {
+			return nil, buildErr
+		}
+
+		resp, err = t.base.RoundTrip(attempt)
+		if !shouldFailOver(req, resp, err) {
+			t.pool.succeeded(endpoint)
+			return resp, err
+		}
+		var retryAfter time.Duration
+		if resp != nil {
+			retryAfter, _ = ParseRetryAfter(resp.Header.Get("Retry-After"), t.pool.now())
+		}
+		t.pool.failed(endpoint, retryAfter)
+		if i == len(endpoints)-1 {
+			break
+		}
+
+		reason := "request error"
+		if resp != nil {
+			reason = resp.Status
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxOverpassErrorBody))
+			resp.Body.Close()
+		}
+		slog.Default().Warn("Overpass endpoint failed, trying next mirror",
+			"endpoint", endpoint, "next", endpoints[i+1], "reason", reason)
+		if hooks := getMonitoringHooks(); hooks != nil && hooks.OnError != nil {
+			hooks.OnError("overpass", "mirror_failover")
+		}
+	}
+	return resp, err
+}
+
+// CloseIdleConnections closes idle connections of the underlying transport
+func (t *overpassFailoverTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// shouldFailOver reports whether a response means another mirror may do
+// better: connection errors, rate limiting and unavailable or overloaded
+// servers. Query timeouts reported by Overpass are the query's fault and
+// would time out on a mirror too.
+func shouldFailOver(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	case http.StatusGatewayTimeout:
+		return resp.Header.Get(OverpassErrorHeader) == ""
+	}
+	return false
+}
+
+// retarget returns a copy of req sent to endpoint, keeping its query string
+// and a fresh copy of its body
+func retarget(req *http.Request, endpoint string) (*http.Request, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := req.Clone(req.Context())
+	attempt.URL.Scheme = u.Scheme
+	attempt.URL.Host = u.Host
+	attempt.URL.Path = u.Path
+	attempt.URL.RawPath = u.RawPath
+	attempt.Host = ""
+	if req.GetBody != nil {
+		if attempt.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return attempt, nil
+}
@@ -0,0 +1,172 @@
+package osm
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// overpassStub serves Overpass responses with a handler, counting requests
+func overpassStub(t *testing.T, handler func(w http.ResponseWriter, body string)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		handler(w, string(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+// useOverpassMirrors points OverpassBaseURL and the mirrors at test servers
+func useOverpassMirrors(t *testing.T, primary string, mirrors ...string) {
+	t.Helper()
+	oldBase := OverpassBaseURL
+	OverpassBaseURL = primary
+	if err := SetOverpassMirrors(mirrors); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		OverpassBaseURL = oldBase
+		SetOverpassMirrors([]string{})
+	})
+}
+
+func postOverpass(t *testing.T, client *http.Client) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, OverpassBaseURL, strings.NewReader("data=node(1);out;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestOverpassFailover(t *testing.T) {
+	primary, primaryHits := overpassStub(t, func(w http.ResponseWriter, _ string) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, osm3sErrorPage("runtime error: Dispatcher_Client::request_read_and_idx::rate_limited."))
+	})
+	mirror, mirrorHits := overpassStub(t, func(w http.ResponseWriter, body string) {
+		if body != "data=node(1);out;" {
+			t.Errorf("mirror got body %q", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"elements":[]}`)
+	})
+	useOverpassMirrors(t, primary.URL, mirror.URL)
+
+	client := &http.Client{Transport: newOSMTransport(DefaultTransportConfig())}
+	if resp := postOverpass(t, client); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200 from the mirror", resp.StatusCode)
+	}
+	if primaryHits.Load() != 1 || mirrorHits.Load() != 1 {
+		t.Errorf("hits primary=%d mirror=%d, want 1 and 1", primaryHits.Load(), mirrorHits.Load())
+	}
+
+	// The rate limited primary is skipped while it cools down
+	postOverpass(t, client)
+	if primaryHits.Load() != 1 || mirrorHits.Load() != 2 {
+		t.Errorf("hits primary=%d mirror=%d, want 1 and 2", primaryHits.Load(), mirrorHits.Load())
+	}
+}
+
+func TestOverpassNoFailoverOnQueryErrors(t *testing.T) {
+	for name, message := range map[string]string{
+		"syntax":  "line 1: parse error: Unknown type &quot;nod&quot;",
+		"timeout": `runtime error: Query timed out in "query" at line 1 after 26 seconds.`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			primary, _ := overpassStub(t, func(w http.ResponseWriter, _ string) {
+				w.Header().Set("Content-Type", "text/html")
+				io.WriteString(w, osm3sErrorPage(message))
+			})
+			mirror, mirrorHits := overpassStub(t, func(w http.ResponseWriter, _ string) {})
+			useOverpassMirrors(t, primary.URL, mirror.URL)
+
+			client := &http.Client{Transport: newOSMTransport(DefaultTransportConfig())}
+			if resp := postOverpass(t, client); resp.StatusCode == http.StatusOK {
+				t.Error("expected the primary's error status")
+			}
+			if mirrorHits.Load() != 0 {
+				t.Errorf("a query error was retried on a mirror")
+			}
+		})
+	}
+}
+
+func TestOverpassFailoverAllDown(t *testing.T) {
+	unavailable := func(w http.ResponseWriter, _ string) { w.WriteHeader(http.StatusServiceUnavailable) }
+	primary, primaryHits := overpassStub(t, unavailable)
+	mirror, mirrorHits := overpassStub(t, unavailable)
+	useOverpassMirrors(t, primary.URL, mirror.URL)
+
+	client := &http.Client{Transport: newOSMTransport(DefaultTransportConfig())}
+	if resp := postOverpass(t, client); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want the last endpoint's 503", resp.StatusCode)
+	}
+	if primaryHits.Load() != 1 || mirrorHits.Load() != 1 {
+		t.Errorf("hits primary=%d mirror=%d, want each tried once", primaryHits.Load(), mirrorHits.Load())
+	}
+}
+
+func TestMirrorPoolOrder(t *testing.T) {
+	now := time.Unix(0, 0)
+	pool := &mirrorPool{
+		mirrors: []string{"https://b", "https://c"},
+		health:  make(map[string]*mirrorHealth),
+		now:     func() time.Time { return now },
+	}
+
+	pool.failed("https://a", 0)
+	pool.failed("https://a", 0) // longer cooldown than b
+	pool.failed("https://b", 0)
+	got := strings.Join(pool.order("https://a"), " ")
+	if want := "https://c https://b https://a"; got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+
+	now = now.Add(maxMirrorCooldown)
+	pool.succeeded("https://b")
+	got = strings.Join(pool.order("https://a"), " ")
+	if want := "https://a https://b https://c"; got != want {
+		t.Errorf("order after recovery = %s, want %s", got, want)
+	}
+}
+
+func TestSetOverpassMirrorsDefaults(t *testing.T) {
+	oldBase := OverpassBaseURL
+	t.Cleanup(func() {
+		OverpassBaseURL = oldBase
+		SetOverpassMirrors([]string{})
+	})
+
+	OverpassBaseURL = DefaultOverpassBaseURL
+	if err := SetOverpassMirrors(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(OverpassMirrors()) != len(DefaultOverpassMirrors) {
+		t.Errorf("mirrors = %v, want the public defaults", OverpassMirrors())
+	}
+
+	OverpassBaseURL = "http://overpass.internal/api/interpreter"
+	if err := SetOverpassMirrors(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(OverpassMirrors()) != 0 {
+		t.Errorf("a self-hosted Overpass got public mirrors %v", OverpassMirrors())
+	}
+
+	if err := SetOverpassMirrors([]string{"ftp://mirror"}); err == nil {
+		t.Error("expected an error for a non-HTTP mirror")
+	}
+}
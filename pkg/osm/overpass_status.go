@@ -0,0 +1,122 @@
+package osm
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// overpassStatusTimeout bounds the status lookup made for a rate
+	// limited response
+	overpassStatusTimeout = 5 * time.Second
+	// maxOverpassStatusBody bounds how much of a status page is read
+	maxOverpassStatusBody = 16 << 10
+)
+
+var (
+	// overpassSlotsAvailablePattern matches "2 slots available now."
+	overpassSlotsAvailablePattern = regexp.MustCompile(`(?m)^(\d+) slots? available now`)
+	// overpassSlotAfterPattern matches "Slot available after:
+	// 2024-05-01T10:00:12Z, in 12 seconds."
+	overpassSlotAfterPattern = regexp.MustCompile(`(?m)^Slot available after: \S+, in (-?\d+) seconds?\.`)
+)
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date, returning the wait from now
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// ParseOverpassStatus reads the time until a query slot frees up from the
+// text of an Overpass /api/status page. It returns 0 when a slot is free
+// now, and false when the page says neither.
+func ParseOverpassStatus(body string) (time.Duration, bool) {
+	if match := overpassSlotsAvailablePattern.FindStringSubmatch(body); match != nil && match[1] != "0" {
+		return 0, true
+	}
+
+	wait := math.MaxInt
+	for _, match := range overpassSlotAfterPattern.FindAllStringSubmatch(body, -1) {
+		if seconds, err := strconv.Atoi(match[1]); err == nil {
+			wait = min(wait, max(seconds, 0))
+		}
+	}
+	if wait == math.MaxInt {
+		return 0, false
+	}
+	return time.Duration(wait) * time.Second, true
+}
+
+// overpassStatusURL returns the status page of the Overpass instance
+// serving an interpreter URL, or "" when the URL is not an interpreter
+func overpassStatusURL(interpreter *http.Request) string {
+	path := interpreter.URL.Path
+	if !strings.HasSuffix(path, "/interpreter") {
+		return ""
+	}
+	u := *interpreter.URL
+	u.Path = strings.TrimSuffix(path, "interpreter") + "status"
+	u.RawPath = ""
+	u.RawQuery = ""
+	return u.String()
+}
+
+// addOverpassRetryAfter sets Retry-After on a rate limited Overpass
+// response that lacks it, from the status page of the instance, so callers
+// retry when a query slot frees up instead of backing off blindly. The
+// response is left unchanged when the status cannot be read.
+func addOverpassRetryAfter(base http.RoundTripper, req *http.Request, resp *http.Response) {
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "" {
+		return
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+	statusURL := overpassStatusURL(req)
+	if statusURL == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), overpassStatusTimeout)
+	defer cancel()
+	statusReq, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		return
+	}
+	statusReq.Header.Set("User-Agent", req.Header.Get("User-Agent"))
+
+	statusResp, err := base.RoundTrip(statusReq)
+	if err != nil {
+		return
+	}
+	defer statusResp.Body.Close()
+	if statusResp.StatusCode != http.StatusOK {
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(statusResp.Body, maxOverpassStatusBody))
+	if err != nil {
+		return
+	}
+
+	wait, ok := ParseOverpassStatus(string(body))
+	if !ok {
+		return
+	}
+	resp.Header.Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
@@ -0,0 +1,112 @@
+package osm
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"12", 12 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-5", 0, true},
+		{"Wed, 01 May 2024 10:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 May 2024 09:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseOverpassStatus(t *testing.T) {
+	busy := `Connected as: 3232235777
+Current time: 2024-05-01T10:00:00Z
+Announced endpoint: none
+Rate limit: 2
+Slot available after: 2024-05-01T10:00:40Z, in 40 seconds.
+Slot available after: 2024-05-01T10:00:12Z, in 12 seconds.
+Currently running queries (pid, space limit, time limit, start time):
+`
+	if wait, ok := ParseOverpassStatus(busy); !ok || wait != 12*time.Second {
+		t.Errorf("busy: got %v, %v, want the earliest slot in 12s", wait, ok)
+	}
+
+	free := "Connected as: 3232235777\nRate limit: 2\n1 slots available now.\n"
+	if wait, ok := ParseOverpassStatus(free); !ok || wait != 0 {
+		t.Errorf("free: got %v, %v", wait, ok)
+	}
+
+	if _, ok := ParseOverpassStatus("<html>maintenance</html>"); ok {
+		t.Error("a page without slot information should not give a wait")
+	}
+}
+
+func TestOverpassRetryAfterFromStatus(t *testing.T) {
+	var statusHits int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/interpreter", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, osm3sErrorPage("runtime error: Dispatcher_Client::request_read_and_idx::rate_limited."))
+	})
+	mux.HandleFunc("/api/status", func(w http.ResponseWriter, r *http.Request) {
+		statusHits++
+		io.WriteString(w, "Rate limit: 2\nSlot available after: 2024-05-01T10:00:07Z, in 7 seconds.\n")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	useOverpassMirrors(t, srv.URL+"/api/interpreter")
+
+	client := &http.Client{Transport: newOSMTransport(DefaultTransportConfig())}
+	resp := postOverpass(t, client)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "7" || statusHits != 1 {
+		t.Errorf("Retry-After = %q after %d status lookups, want 7 from the status page", got, statusHits)
+	}
+}
+
+func TestOverpassRetryAfterKept(t *testing.T) {
+	var statusHits int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/interpreter", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/api/status", func(w http.ResponseWriter, r *http.Request) { statusHits++ })
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	useOverpassMirrors(t, srv.URL+"/api/interpreter")
+
+	client := &http.Client{Transport: newOSMTransport(DefaultTransportConfig())}
+	if resp := postOverpass(t, client); resp.Header.Get("Retry-After") != "3" || statusHits != 0 {
+		t.Errorf("Retry-After = %q with %d status lookups, want the server's own header", resp.Header.Get("Retry-After"), statusHits)
+	}
+}
+
+func TestMirrorPoolRetryAfter(t *testing.T) {
+	now := time.Unix(0, 0)
+	pool := &mirrorPool{health: make(map[string]*mirrorHealth), now: func() time.Time { return now }}
+
+	pool.failed("https://a", 5*time.Second)
+	if got := pool.health["https://a"].downUntil.Sub(now); got != 5*time.Second {
+		t.Errorf("cooldown = %v, want the 5s the endpoint asked for", got)
+	}
+	pool.failed("https://a", time.Hour)
+	if got := pool.health["https://a"].downUntil.Sub(now); got != maxMirrorCooldown {
+		t.Errorf("cooldown = %v, want it capped at %v", got, maxMirrorCooldown)
+	}
+}
@@ -12,6 +12,12 @@ import (
 // The algorithm uses 5 decimal places of precision (1e-5) for coordinates.
 // See https://developers.google.com/maps/documentation/utilities/polylinealgorithm
 func DecodePolyline(encoded string) []geo.Location {
+	return DecodePolylinePrecision(encoded, 5)
+}
+
+// DecodePolylinePrecision decodes a polyline encoded with the given number
+// of decimal places, such as 6 for OSRM's polyline6 geometries.
+func DecodePolylinePrecision(encoded string, precision int) []geo.Location {
 	if len(encoded) == 0 {
 		return []geo.Location{}
 	}
@@ -26,6 +32,7 @@ func DecodePolyline(encoded string) []geo.Location {
 	points := make([]geo.Location, 0, count)
 
 	// Initialize variables
+	scale := math.Pow10(-precision)
 	index := 0
 	lat := 0
 	lng := 0
@@ -73,8 +80,8 @@ func DecodePolyline(encoded string) []geo.Location {
 
 		// Convert to floating point and add to result
 		points = append(points, geo.Location{
-			Latitude:  float64(lat) * 1e-5,
-			Longitude: float64(lng) * 1e-5,
+			Latitude:  float64(lat) * scale,
+			Longitude: float64(lng) * scale,
 		})
 	}
 
@@ -86,6 +93,12 @@ func DecodePolyline(encoded string) []geo.Location {
 // The algorithm uses 5 decimal places of precision (1e-5) for coordinates.
 // See https://developers.google.com/maps/documentation/utilities/polylinealgorithm
 func EncodePolyline(points []geo.Location) string {
+	return EncodePolylinePrecision(points, 5)
+}
+
+// EncodePolylinePrecision encodes locations as a polyline with the given
+// number of decimal places.
+func EncodePolylinePrecision(points []geo.Location, precision int) string {
 	if len(points) == 0 {
 		return ""
 	}
@@ -100,14 +113,15 @@ func EncodePolyline(points []geo.Location) string {
 	result := make([]byte, 0, len(points)*6)
 
 	// Initialize previous values
+	scale := math.Pow10(precision)
 	prevLat := 0
 	prevLng := 0
 
 	// Encode each point
 	for _, point := range points {
-		// Convert to integers with 5 decimal precision
-		lat := int(math.Round(point.Latitude * 1e5))
-		lng := int(math.Round(point.Longitude * 1e5))
+		// Convert to integers with the requested decimal precision
+		lat := int(math.Round(point.Latitude * scale))
+		lng := int(math.Round(point.Longitude * scale))
 
 		// Encode differences from previous values
 		deltaLat := lat - prevLat
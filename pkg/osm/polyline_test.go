@@ -2,6 +2,7 @@
 package osm
 
 import (
+	"math"
 	"testing"
 
 	"github.com/NERVsystems/osmmcp/pkg/geo"
@@ -182,3 +183,29 @@ func almostEqual(a, b, tolerance float64) bool {
 	}
 	return diff <= tolerance
 }
+
+// TestPolylinePrecision6 checks OSRM's polyline6 encoding keeps the sixth
+// decimal place
+func TestPolylinePrecision6(t *testing.T) {
+	points := []geo.Location{
+		{Latitude: 37.774929, Longitude: -122.419416},
+		{Latitude: 37.804363, Longitude: -122.271114},
+	}
+
+	// Reference encoding from the polyline algorithm with precision 6
+	encoded := EncodePolylinePrecision([]geo.Location{{Latitude: 38.5, Longitude: -120.2}}, 6)
+	if encoded != "_izlhA~rlgdF" {
+		t.Errorf("expected _izlhA~rlgdF, got %s", encoded)
+	}
+
+	decoded := DecodePolylinePrecision(EncodePolylinePrecision(points, 6), 6)
+	if len(decoded) != len(points) {
+		t.Fatalf("expected %d points, got %d", len(points), len(decoded))
+	}
+	for i := range points {
+		if math.Abs(decoded[i].Latitude-points[i].Latitude) > 1e-7 ||
+			math.Abs(decoded[i].Longitude-points[i].Longitude) > 1e-7 {
+			t.Errorf("point %d: expected %v, got %v", i, points[i], decoded[i])
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package queries
+
+import "regexp"
+
+// outStatement matches an Overpass output statement such as "out;",
+// "out body;" or "out center 500;"
+var outStatement = regexp.MustCompile(`\bout(\s+[^;]*)?;`)
+
+// CountQuery rewrites an Overpass query so that each output statement
+// returns only element counts ("out count;"). The rewritten query is cheap
+// to run and can be used to size a query before downloading its results.
+func CountQuery(query string) string {
+	return outStatement.ReplaceAllString(query, "out count;")
+}
@@ -0,0 +1,182 @@
+package queries
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+const (
+	// searchAreaSet names the set holding an area looked up with
+	// WithAreaByName or WithAreaByID
+	searchAreaSet = "searchArea"
+
+	// Overpass area IDs are derived from way and relation IDs by adding
+	// these offsets
+	wayAreaOffset      = 2400000000
+	relationAreaOffset = 3600000000
+)
+
+// Recursion operators applied to a query's results
+const (
+	RecurseDown     = ">"  // nodes of ways and members of relations
+	RecurseDownDeep = ">>" // as RecurseDown, through nested relations
+	RecurseUp       = "<"  // ways and relations containing the results
+	RecurseUpDeep   = "<<" // as RecurseUp, through parent relations
+)
+
+// WithAreaByName looks up an area by name, narrowed by further tags such as
+// {"boundary": "administrative", "admin_level": "8"}, for the *InArea
+// methods. It must be called before any element is added.
+func (b *OverpassBuilder) WithAreaByName(name string, tags map[string]string) *OverpassBuilder {
+	filters := map[string]string{"name": name}
+	for key, value := range tags {
+		filters[key] = value
+	}
+	clauses, err := tagClauses(filters)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.withArea("area" + clauses)
+}
+
+// WithAreaByID selects the area of a closed way or a relation, such as a
+// boundary relation from geocoding, for the *InArea methods. It must be
+// called before any element is added.
+func (b *OverpassBuilder) WithAreaByID(osmType string, id int64) *OverpassBuilder {
+	var areaID int64
+	switch osmType {
+	case "way":
+		areaID = wayAreaOffset + id
+	case "relation":
+		areaID = relationAreaOffset + id
+	default:
+		b.fail(fmt.Errorf("areas are built from ways and relations, not %q", osmType))
+		return b
+	}
+	if id <= 0 {
+		b.fail(fmt.Errorf("invalid %s ID %d", osmType, id))
+		return b
+	}
+	return b.withArea(fmt.Sprintf("area(%d)", areaID))
+}
+
+// WithNodeInArea adds a node query within the area and with specified tags.
+func (b *OverpassBuilder) WithNodeInArea(tags map[string]string) *OverpassBuilder {
+	return b.addAreaElement("node", tags)
+}
+
+// WithWayInArea adds a way query within the area and with specified tags.
+func (b *OverpassBuilder) WithWayInArea(tags map[string]string) *OverpassBuilder {
+	return b.addAreaElement("way", tags)
+}
+
+// WithRelationInArea adds a relation query within the area and with
+// specified tags.
+func (b *OverpassBuilder) WithRelationInArea(tags map[string]string) *OverpassBuilder {
+	return b.addAreaElement("relation", tags)
+}
+
+// WithNodeAround adds a node query within radius meters of a point, or of
+// a line through several points, and with specified tags.
+func (b *OverpassBuilder) WithNodeAround(radius float64, points []geo.Location, tags map[string]string) *OverpassBuilder {
+	return b.addAroundElement("node", radius, points, tags)
+}
+
+// WithWayAround adds a way query within radius meters of a point, or of a
+// line through several points such as a route corridor, and with specified
+// tags.
+func (b *OverpassBuilder) WithWayAround(radius float64, points []geo.Location, tags map[string]string) *OverpassBuilder {
+	return b.addAroundElement("way", radius, points, tags)
+}
+
+// WithRelationAround adds a relation query within radius meters of a point
+// or line, and with specified tags.
+func (b *OverpassBuilder) WithRelationAround(radius float64, points []geo.Location, tags map[string]string) *OverpassBuilder {
+	return b.addAroundElement("relation", radius, points, tags)
+}
+
+// WithRecursion adds the elements reached by recursion operators, such as
+// RecurseDown for the nodes of matched ways, to the results. It must be
+// called before End.
+func (b *OverpassBuilder) WithRecursion(ops ...string) *OverpassBuilder {
+	for _, op := range ops {
+		switch op {
+		case RecurseDown, RecurseDownDeep, RecurseUp, RecurseUpDeep:
+			b.recurse = append(b.recurse, op)
+		default:
+			b.fail(fmt.Errorf("unknown recursion operator %q", op))
+		}
+	}
+	return b
+}
+
+// withArea writes the area lookup ahead of the element group
+func (b *OverpassBuilder) withArea(statement string) *OverpassBuilder {
+	switch {
+	case b.hasElement:
+		b.fail(fmt.Errorf("the area must be set before elements are added"))
+	case b.hasArea:
+		b.fail(fmt.Errorf("only one area can be set"))
+	default:
+		b.buf.WriteString(statement + "->." + searchAreaSet + ";")
+		b.hasArea = true
+	}
+	return b
+}
+
+// addAreaElement adds an element query within the area
+func (b *OverpassBuilder) addAreaElement(elementType string, tags map[string]string) *OverpassBuilder {
+	if !b.hasArea {
+		b.fail(fmt.Errorf("%s query in an area needs WithAreaByName or WithAreaByID first", elementType))
+		return b
+	}
+	b.addElement(fmt.Sprintf("%s(area.%s)", elementType, searchAreaSet), tags)
+	return b
+}
+
+// addAroundElement adds an element query near a point or line
+func (b *OverpassBuilder) addAroundElement(elementType string, radius float64, points []geo.Location, tags map[string]string) *OverpassBuilder {
+	if radius <= 0 {
+		b.fail(fmt.Errorf("around radius must be positive, got %g", radius))
+		return b
+	}
+	if len(points) == 0 {
+		b.fail(fmt.Errorf("around filter needs at least one point"))
+		return b
+	}
+
+	coords := make([]string, 0, 2*len(points))
+	for _, p := range points {
+		if err := geo.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+			b.fail(err)
+			return b
+		}
+		coords = append(coords, strconv.FormatFloat(p.Latitude, 'f', 6, 64), strconv.FormatFloat(p.Longitude, 'f', 6, 64))
+	}
+	b.addElement(fmt.Sprintf("%s(around:%.1f,%s)", elementType, radius, strings.Join(coords, ",")), tags)
+	return b
+}
+
+// tagClauses formats tag filters in key order
+func tagClauses(tags map[string]string) (string, error) {
+	var clauses strings.Builder
+	for _, key := range sortedKeys(tags) {
+		clause, err := TagFilterClause(key, tags[key])
+		if err != nil {
+			return "", err
+		}
+		clauses.WriteString(clause)
+	}
+	return clauses.String(), nil
+}
+
+// fail records the first error in building the query
+func (b *OverpassBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
@@ -0,0 +1,118 @@
+package queries
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// plainTagToken matches keys and values that need no quoting in a filter
+var plainTagToken = regexp.MustCompile(`^[A-Za-z0-9_:.-]+$`)
+
+// TagFilterClause converts a key and value of a tag map into an Overpass
+// filter clause:
+//
+//	"amenity": "cafe"          [amenity=cafe]
+//	"amenity": ""              [amenity]          (key present)
+//	"!amenity": ""             [!"amenity"]       (key absent)
+//	"name": "~pizz(a|eria)"    ["name"~"pizz(a|eria)"]
+//	"name": "~\"pizza\",i"     ["name"~"pizza",i] (case-insensitive)
+//	"~^name(:.*)?$": "~pizza"  [~"^name(:.*)?$"~"pizza"] (any key matching,
+//	                           e.g. names in every language)
+//
+// Keys and values are quoted and escaped unless they are plain tokens, and
+// regular expressions are checked before they are sent.
+func TagFilterClause(key, value string) (string, error) {
+	if negated, ok := strings.CutPrefix(key, "!"); ok {
+		if negated == "" {
+			return "", fmt.Errorf("negated tag key is empty")
+		}
+		if value != "" && value != "*" {
+			return "", fmt.Errorf("negated key %q cannot have a value", negated)
+		}
+		return fmt.Sprintf("[!%s]", quoteTag(negated)), nil
+	}
+
+	if keyPattern, ok := strings.CutPrefix(key, "~"); ok {
+		if _, err := regexp.Compile(keyPattern); err != nil || keyPattern == "" {
+			return "", fmt.Errorf("invalid key pattern %q", keyPattern)
+		}
+		pattern, caseInsensitive := ".", false
+		if value != "" && value != "*" {
+			rest, ok := strings.CutPrefix(value, "~")
+			if !ok {
+				return "", fmt.Errorf("key pattern %q needs a ~pattern value", keyPattern)
+			}
+			var err error
+			if pattern, caseInsensitive, err = parseTagPattern(rest); err != nil {
+				return "", fmt.Errorf("tag %q: %w", key, err)
+			}
+		}
+		clause := fmt.Sprintf("[~%s~%s", quoteTag(keyPattern), quoteTag(pattern))
+		if caseInsensitive {
+			clause += ",i"
+		}
+		return clause + "]", nil
+	}
+
+	if pattern, ok := strings.CutPrefix(value, "~"); ok {
+		pattern, caseInsensitive, err := parseTagPattern(pattern)
+		if err != nil {
+			return "", fmt.Errorf("tag %q: %w", key, err)
+		}
+		clause := fmt.Sprintf("[%s~%s", quoteTag(key), quoteTag(pattern))
+		if caseInsensitive {
+			clause += ",i"
+		}
+		return clause + "]", nil
+	}
+
+	if value == "" {
+		return fmt.Sprintf("[%s]", formatTagToken(key)), nil
+	}
+	return fmt.Sprintf("[%s=%s]", formatTagToken(key), formatTagToken(value)), nil
+}
+
+// parseTagPattern reads a regex value after its ~: either a bare pattern
+// or a quoted one, optionally followed by ,i for case-insensitive matching
+func parseTagPattern(s string) (string, bool, error) {
+	caseInsensitive := false
+	if strings.HasPrefix(s, `"`) {
+		end := strings.LastIndex(s, `"`)
+		if end == 0 {
+			return "", false, fmt.Errorf("unterminated quoted pattern")
+		}
+		switch rest := s[end+1:]; rest {
+		case "":
+		case ",i":
+			caseInsensitive = true
+		default:
+			return "", false, fmt.Errorf("unexpected %q after pattern; only ,i is allowed", rest)
+		}
+		s = s[1:end]
+	} else if trimmed, ok := strings.CutSuffix(s, ",i"); ok {
+		s, caseInsensitive = trimmed, true
+	}
+
+	if s == "" {
+		return "", false, fmt.Errorf("empty pattern")
+	}
+	if _, err := regexp.Compile(s); err != nil {
+		return "", false, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return s, caseInsensitive, nil
+}
+
+// formatTagToken leaves plain tokens unquoted, matching the queries built
+// before quoting was added, and quotes anything else
+func formatTagToken(s string) string {
+	if plainTagToken.MatchString(s) {
+		return s
+	}
+	return quoteTag(s)
+}
+
+// quoteTag quotes a key, value or pattern for an Overpass filter
+func quoteTag(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,58 @@
+package queries
+
+import "testing"
+
+func TestTagFilterClause(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       string
+	}{
+		{"amenity", "cafe", "[amenity=cafe]"},
+		{"amenity", "", "[amenity]"},
+		{"name", "Joe's Diner", `[name="Joe's Diner"]`},
+		{"name", `a"]b`, `[name="a\"]b"]`},
+		{"!takeaway", "", `[!"takeaway"]`},
+		{"!takeaway", "*", `[!"takeaway"]`},
+		{"name", "~pizz(a|eria)", `["name"~"pizz(a|eria)"]`},
+		{"name", "~pizza,i", `["name"~"pizza",i]`},
+		{"name", `~"pizza",i`, `["name"~"pizza",i]`},
+		{"name", `~^\d+$`, `["name"~"^\\d+$"]`},
+		{"~^name(:.*)?$", "~bahnhof,i", `[~"^name(:.*)?$"~"bahnhof",i]`},
+		{"~^name:", "", `[~"^name:"~"."]`},
+	}
+
+	for _, tt := range tests {
+		got, err := TagFilterClause(tt.key, tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("TagFilterClause(%q, %q) = %q, %v, want %q", tt.key, tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestTagFilterClauseErrors(t *testing.T) {
+	tests := []struct{ key, value string }{
+		{"!", ""},
+		{"!amenity", "cafe"},
+		{"name", "~"},
+		{"name", "~(unclosed"},
+		{"name", `~"pizza`},
+		{"name", `~"pizza",x`},
+		{"~^name", "pizza"},
+		{"~(", "~pizza"},
+	}
+
+	for _, tt := range tests {
+		if got, err := TagFilterClause(tt.key, tt.value); err == nil {
+			t.Errorf("TagFilterClause(%q, %q) = %q, want an error", tt.key, tt.value, got)
+		}
+	}
+}
+
+func TestOverpassBuilderInvalidTag(t *testing.T) {
+	b := NewOverpassBuilder().
+		WithNodeInBbox(1, 2, 3, 4, map[string]string{"name": "~(", "amenity": "cafe"}).
+		End()
+	if b.Err() == nil {
+		t.Errorf("expected an error for an invalid pattern, got query %s", b.Build())
+	}
+}
@@ -3,6 +3,7 @@ package queries
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -12,7 +13,10 @@ type OverpassBuilder struct {
 	buf        strings.Builder
 	elements   []string
 	hasElement bool
+	hasArea    bool
+	recurse    []string
 	output     string
+	err        error
 }
 
 // NewOverpassBuilder creates a new Overpass query builder with initial settings.
@@ -64,7 +68,12 @@ func (b *OverpassBuilder) End() *OverpassBuilder {
 		if b.output != "" {
 			out = b.output
 		}
-		b.buf.WriteString(fmt.Sprintf(");out %s;", out))
+		b.buf.WriteString(")")
+		if len(b.recurse) > 0 {
+			// Union the results with the elements reached by recursion
+			b.buf.WriteString(";(._;" + strings.Join(b.recurse, ";") + ";)")
+		}
+		b.buf.WriteString(fmt.Sprintf(";out %s;", out))
 	}
 	return b
 }
@@ -101,6 +110,13 @@ func (b *OverpassBuilder) Build() string {
 	return b.buf.String()
 }
 
+// Err returns the first invalid tag filter, area, around filter or
+// recursion passed to the builder. Invalid parts are left out of the query,
+// so check Err before running it.
+func (b *OverpassBuilder) Err() error {
+	return b.err
+}
+
 // addElement adds a query element with tags to the builder.
 // This is an internal helper method used by the public With* methods.
 func (b *OverpassBuilder) addElement(baseQuery string, tags map[string]string) {
@@ -113,16 +129,13 @@ func (b *OverpassBuilder) addElement(baseQuery string, tags map[string]string) {
 	var query strings.Builder
 	query.WriteString(baseQuery)
 
-	// Add tags as filters
-	for key, value := range tags {
-		if value == "" {
-			// Just check for the presence of the key
-			query.WriteString(fmt.Sprintf("[%s]", key))
-		} else {
-			// Check for specific key=value
-			query.WriteString(fmt.Sprintf("[%s=%s]", key, value))
-		}
+	// Add tags as filters, in key order so equal maps build equal queries
+	clauses, err := tagClauses(tags)
+	if err != nil {
+		b.fail(err)
+		return
 	}
+	query.WriteString(clauses)
 
 	// Add semicolon
 	query.WriteString(";")
@@ -130,3 +143,13 @@ func (b *OverpassBuilder) addElement(baseQuery string, tags map[string]string) {
 	// Add to the main query
 	b.buf.WriteString(query.String())
 }
+
+// sortedKeys returns the keys of a tag map in order
+func sortedKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
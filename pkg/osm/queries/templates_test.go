@@ -1,6 +1,10 @@
 package queries
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
 
 func TestOverpassBuilder_Simple(t *testing.T) {
 	q := NewOverpassBuilder().
@@ -24,3 +28,91 @@ func TestOverpassBuilder_CustomOutput(t *testing.T) {
 		t.Errorf("unexpected query: %s", q)
 	}
 }
+
+func TestCountQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: "[out:json];(node(1,2,3,4)[amenity];way(1,2,3,4)[amenity];);out center;",
+			want:  "[out:json];(node(1,2,3,4)[amenity];way(1,2,3,4)[amenity];);out count;",
+		},
+		{
+			query: "[out:json][timeout:25];node[shop](around:500,1,2);out;",
+			want:  "[out:json][timeout:25];node[shop](around:500,1,2);out count;",
+		},
+		{
+			query: "[out:json];way[highway](1,2,3,4);out body;>;out skel qt;",
+			want:  "[out:json];way[highway](1,2,3,4);out count;>;out count;",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := CountQuery(tt.query); got != tt.want {
+			t.Errorf("CountQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestOverpassBuilder_Area(t *testing.T) {
+	q := NewOverpassBuilder().
+		WithAreaByName("Berlin", map[string]string{"boundary": "administrative"}).
+		WithNodeInArea(map[string]string{"amenity": "library"}).
+		WithWayInArea(map[string]string{"amenity": "library"}).
+		End().
+		WithOutput("center").
+		Build()
+	expected := "[out:json];area[boundary=administrative][name=Berlin]->.searchArea;(node(area.searchArea)[amenity=library];way(area.searchArea)[amenity=library];);out center;"
+	if q != expected {
+		t.Errorf("unexpected query: %s", q)
+	}
+
+	q = NewOverpassBuilder().
+		WithAreaByID("relation", 62422).
+		WithRelationInArea(map[string]string{"boundary": "administrative", "admin_level": "9"}).
+		End().
+		Build()
+	expected = "[out:json];area(3600062422)->.searchArea;(relation(area.searchArea)[admin_level=9][boundary=administrative];);out body;"
+	if q != expected {
+		t.Errorf("unexpected query: %s", q)
+	}
+}
+
+func TestOverpassBuilder_AroundAndRecursion(t *testing.T) {
+	corridor := []geo.Location{{Latitude: 51.5, Longitude: -0.12}, {Latitude: 51.51, Longitude: -0.1}}
+	q := NewOverpassBuilder().
+		WithWayAround(50, corridor, map[string]string{"waterway": "river"}).
+		WithRecursion(RecurseDown).
+		End().
+		Build()
+	expected := "[out:json];(way(around:50.0,51.500000,-0.120000,51.510000,-0.100000)[waterway=river];);(._;>;);out body;"
+	if q != expected {
+		t.Errorf("unexpected query: %s", q)
+	}
+
+	q = NewOverpassBuilder().
+		WithNodeAround(100, corridor[:1], nil).
+		End().
+		Build()
+	expected = "[out:json];(node(around:100.0,51.500000,-0.120000););out body;"
+	if q != expected {
+		t.Errorf("unexpected query: %s", q)
+	}
+}
+
+func TestOverpassBuilder_Errors(t *testing.T) {
+	tests := map[string]*OverpassBuilder{
+		"Area after elements": NewOverpassBuilder().WithNodeInBbox(1, 2, 3, 4, nil).WithAreaByID("relation", 1),
+		"Area from node":      NewOverpassBuilder().WithAreaByID("node", 1),
+		"In area without one": NewOverpassBuilder().WithNodeInArea(nil),
+		"Around no points":    NewOverpassBuilder().WithWayAround(50, nil, nil),
+		"Around bad radius":   NewOverpassBuilder().WithWayAround(0, []geo.Location{{Latitude: 1, Longitude: 2}}, nil),
+		"Unknown recursion":   NewOverpassBuilder().WithRecursion("<>"),
+	}
+	for name, b := range tests {
+		if b.Err() == nil {
+			t.Errorf("%s: expected an error, got query %s", name, b.Build())
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package osm
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxRecentWaits is the number of recent rate limit waits kept per service
+const maxRecentWaits = 20
+
+// limiterStats tracks callers queued on a service rate limiter and how long
+// recent callers had to wait
+type limiterStats struct {
+	mu         sync.Mutex
+	queued     int
+	waits      []time.Duration // ring buffer of recent waits
+	next       int
+	lastWaitAt time.Time
+}
+
+var (
+	// Rate limiter statistics for each service
+	limiterStatsByService = map[string]*limiterStats{
+		ServiceNominatim: {},
+		ServiceOverpass:  {},
+		ServiceOSRM:      {},
+	}
+)
+
+// enter records a caller starting to wait and returns the new queue depth
+func (s *limiterStats) enter() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queued++
+	return s.queued
+}
+
+// leave records a caller that finished waiting after d and returns the new
+// queue depth
+func (s *limiterStats) leave(d time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queued--
+	if len(s.waits) < maxRecentWaits {
+		s.waits = append(s.waits, d)
+	} else {
+		s.waits[s.next] = d
+	}
+	s.next = (s.next + 1) % maxRecentWaits
+	s.lastWaitAt = time.Now()
+	return s.queued
+}
+
+// RateLimiterStatus is a snapshot of the rate limiter for one upstream service
+type RateLimiterStatus struct {
+	Service           string     `json:"service"`
+	RequestsPerSecond float64    `json:"requests_per_second"`
+	Burst             int        `json:"burst"`
+	TokensAvailable   float64    `json:"tokens_available"`
+	QueueDepth        int        `json:"queue_depth"`       // callers currently waiting
+	EstimatedWaitMs   int64      `json:"estimated_wait_ms"` // for a request made now
+	RecentWaits       int        `json:"recent_waits"`      // waits in the sample below
+	AverageWaitMs     int64      `json:"average_wait_ms"`   // over recent waits
+	MaxWaitMs         int64      `json:"max_wait_ms"`       // over recent waits
+	LastWaitAt        *time.Time `json:"last_wait_at,omitempty"`
+}
+
+// limiterForService returns the rate limiter configured for a service
+func limiterForService(service string) *rate.Limiter {
+	switch service {
+	case ServiceNominatim:
+		return nominatimLimiter
+	case ServiceOverpass:
+		return overpassLimiter
+	case ServiceOSRM:
+		return osrmLimiter
+	default:
+		return nil
+	}
+}
+
+// RateLimitStatus returns the current state of the Nominatim, Overpass and
+// OSRM rate limiters
+func RateLimitStatus() []RateLimiterStatus {
+	services := []string{ServiceNominatim, ServiceOverpass, ServiceOSRM}
+	statuses := make([]RateLimiterStatus, 0, len(services))
+	for _, service := range services {
+		statuses = append(statuses, rateLimiterStatus(service, limiterForService(service), limiterStatsByService[service]))
+	}
+	return statuses
+}
+
+// rateLimiterStatus builds the status of one limiter
+func rateLimiterStatus(service string, limiter *rate.Limiter, stats *limiterStats) RateLimiterStatus {
+	// Tokens go negative while queued callers hold reservations
+	tokens := limiter.Tokens()
+	status := RateLimiterStatus{
+		Service:           service,
+		RequestsPerSecond: float64(limiter.Limit()),
+		Burst:             limiter.Burst(),
+		TokensAvailable:   math.Max(tokens, 0),
+	}
+
+	stats.mu.Lock()
+	status.QueueDepth = stats.queued
+	status.RecentWaits = len(stats.waits)
+	var total time.Duration
+	for _, d := range stats.waits {
+		total += d
+		if ms := d.Milliseconds(); ms > status.MaxWaitMs {
+			status.MaxWaitMs = ms
+		}
+	}
+	if len(stats.waits) > 0 {
+		status.AverageWaitMs = (total / time.Duration(len(stats.waits))).Milliseconds()
+		lastWaitAt := stats.lastWaitAt
+		status.LastWaitAt = &lastWaitAt
+	}
+	stats.mu.Unlock()
+
+	// A new request waits until the limiter has refilled a whole token
+	if deficit := 1 - tokens; deficit > 0 && status.RequestsPerSecond > 0 {
+		status.EstimatedWaitMs = int64(deficit / status.RequestsPerSecond * 1000)
+	}
+
+	return status
+}
@@ -0,0 +1,66 @@
+package osm
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestLimiterStatsRecentWaits(t *testing.T) {
+	stats := &limiterStats{}
+	for i := 1; i <= maxRecentWaits+5; i++ {
+		if depth := stats.enter(); depth != 1 {
+			t.Fatalf("queue depth after enter = %d, want 1", depth)
+		}
+		if depth := stats.leave(time.Duration(i) * time.Millisecond); depth != 0 {
+			t.Fatalf("queue depth after leave = %d, want 0", depth)
+		}
+	}
+
+	status := rateLimiterStatus(ServiceOverpass, rate.NewLimiter(1, 1), stats)
+	if status.RecentWaits != maxRecentWaits {
+		t.Errorf("RecentWaits = %d, want %d", status.RecentWaits, maxRecentWaits)
+	}
+	if status.MaxWaitMs != int64(maxRecentWaits+5) {
+		t.Errorf("MaxWaitMs = %d, want %d", status.MaxWaitMs, maxRecentWaits+5)
+	}
+	// The oldest five waits were overwritten, leaving 6..25 ms
+	if status.AverageWaitMs != 15 {
+		t.Errorf("AverageWaitMs = %d, want 15", status.AverageWaitMs)
+	}
+	if status.LastWaitAt == nil {
+		t.Error("expected LastWaitAt to be set")
+	}
+}
+
+func TestRateLimiterStatusEstimatedWait(t *testing.T) {
+	limiter := rate.NewLimiter(0.5, 1)
+
+	status := rateLimiterStatus(ServiceNominatim, limiter, &limiterStats{})
+	if status.TokensAvailable != 1 || status.EstimatedWaitMs != 0 {
+		t.Errorf("fresh limiter: tokens %.2f, estimated wait %dms", status.TokensAvailable, status.EstimatedWaitMs)
+	}
+
+	// Spend the only token; the next one arrives in about two seconds
+	limiter.Allow()
+	status = rateLimiterStatus(ServiceNominatim, limiter, &limiterStats{})
+	if status.EstimatedWaitMs < 1900 || status.EstimatedWaitMs > 2000 {
+		t.Errorf("estimated wait = %dms, want about 2000ms", status.EstimatedWaitMs)
+	}
+	if status.RequestsPerSecond != 0.5 || status.Burst != 1 {
+		t.Errorf("got %.1f rps burst %d", status.RequestsPerSecond, status.Burst)
+	}
+}
+
+func TestRateLimitStatusServices(t *testing.T) {
+	statuses := RateLimitStatus()
+	if len(statuses) != 3 {
+		t.Fatalf("got %d services, want 3", len(statuses))
+	}
+	for i, want := range []string{ServiceNominatim, ServiceOverpass, ServiceOSRM} {
+		if statuses[i].Service != want {
+			t.Errorf("service %d = %s, want %s", i, statuses[i].Service, want)
+		}
+	}
+}
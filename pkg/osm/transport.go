@@ -0,0 +1,118 @@
+package osm
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// TransportConfig tunes the connection pool used for upstream requests
+type TransportConfig struct {
+	MaxIdleConns        int           // idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // idle connections kept per host
+	MaxConnsPerHost     int           // total connections per host, 0 for no limit
+	IdleConnTimeout     time.Duration // how long an idle connection is kept
+	DialTimeout         time.Duration // TCP connect timeout
+	TLSHandshakeTimeout time.Duration
+	EnableHTTP2         bool // negotiate HTTP/2 with servers that support it
+}
+
+// DefaultTransportConfig returns the transport settings used when none are
+// configured
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		DialTimeout:         10 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		EnableHTTP2:         true,
+	}
+}
+
+// NewTransport builds an HTTP transport from a config. The transport reports
+// whether each request reused a pooled connection via the OnConnection
+// monitoring hook, and records the services queried in the DataSources of
+// the request context.
+func NewTransport(config TransportConfig) http.RoundTripper {
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		MaxIdleConns:        config.MaxIdleConns,
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     config.MaxConnsPerHost,
+		IdleConnTimeout:     config.IdleConnTimeout,
+		TLSHandshakeTimeout: config.TLSHandshakeTimeout,
+		ForceAttemptHTTP2:   config.EnableHTTP2,
+	}
+	if !config.EnableHTTP2 {
+		// A non-nil empty map disables the transport's built-in HTTP/2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return &connTrackingTransport{base: transport}
+}
+
+// ConfigureTransport replaces the transport of the global HTTP client
+func ConfigureTransport(config TransportConfig) {
+	httpClient.Transport = newOSMTransport(config)
+}
+
+// newOSMTransport builds the transport of the global HTTP client. Overpass
+// error documents served with status 200 are given an error status, and
+// Overpass requests that fail are retried on a mirror.
+func newOSMTransport(config TransportConfig) http.RoundTripper {
+	return &overpassFailoverTransport{
+		base: &overpassErrorTransport{base: NewTransport(config)},
+		pool: overpassMirrors,
+	}
+}
+
+// connTrackingTransport records connection reuse for each upstream service,
+// and the services queried for a DataSources
+type connTrackingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *connTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if sources := dataSourcesFrom(req.Context()); sources != nil {
+		return recordDataSource(sources, req, t.roundTrip)
+	}
+	return t.roundTrip(req)
+}
+
+// roundTrip sends a request, reporting whether it reused a connection
+func (t *connTrackingTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	hooks := getMonitoringHooks()
+	if hooks == nil || hooks.OnConnection == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	service := getServiceFromRequest(req)
+	if service == "unknown" {
+		service = req.URL.Host
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			hooks.OnConnection(service, info.Reused)
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	return t.base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes idle connections of the underlying transport
+func (t *connTrackingTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
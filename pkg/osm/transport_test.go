@@ -0,0 +1,65 @@
+package osm
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTransportReportsConnectionReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var (
+		mu     sync.Mutex
+		reused []bool
+	)
+	previous := getMonitoringHooks()
+	SetMonitoringHooks(&MonitoringHooks{
+		OnConnection: func(service string, r bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			reused = append(reused, r)
+		},
+	})
+	defer SetMonitoringHooks(previous)
+
+	client := &http.Client{Transport: NewTransport(DefaultTransportConfig())}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reused) != 2 || reused[0] || !reused[1] {
+		t.Errorf("expected a new connection then a reused one, got %v", reused)
+	}
+}
+
+func TestNewTransportHTTP2(t *testing.T) {
+	config := DefaultTransportConfig()
+	config.MaxConnsPerHost = 32
+
+	transport := NewTransport(config).(*connTrackingTransport).base.(*http.Transport)
+	if !transport.ForceAttemptHTTP2 {
+		t.Errorf("expected HTTP/2 to be attempted by default")
+	}
+	if transport.MaxConnsPerHost != 32 {
+		t.Errorf("expected MaxConnsPerHost 32, got %d", transport.MaxConnsPerHost)
+	}
+
+	config.EnableHTTP2 = false
+	transport = NewTransport(config).(*connTrackingTransport).base.(*http.Transport)
+	if transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Errorf("expected HTTP/2 to be disabled")
+	}
+}
@@ -11,11 +11,21 @@ type OverpassElement struct {
 		Lat float64 `json:"lat"`
 		Lon float64 `json:"lon"`
 	} `json:"center,omitempty"`
-	Tags    map[string]string `json:"tags,omitempty"`
-	Nodes   []int64           `json:"nodes,omitempty"` // For ways, list of node IDs
+	Tags     map[string]string `json:"tags,omitempty"`
+	Nodes    []int64           `json:"nodes,omitempty"` // For ways, list of node IDs
+	Geometry []struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"geometry,omitempty"` // For ways, coordinates when queried with "out geom"
 	Members []struct {
-		Type string `json:"type"`
-		Ref  int64  `json:"ref"`
-		Role string `json:"role"`
+		Type     string  `json:"type"`
+		Ref      int64   `json:"ref"`
+		Role     string  `json:"role"`
+		Lat      float64 `json:"lat,omitempty"` // node members, with "out geom"
+		Lon      float64 `json:"lon,omitempty"`
+		Geometry []struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		} `json:"geometry,omitempty"` // way members, with "out geom"
 	} `json:"members,omitempty"` // For relations
 }
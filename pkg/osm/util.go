@@ -4,16 +4,20 @@ package osm
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/NERVsystems/osmmcp/pkg/geo"
 )
 
 const (
-	// API endpoints
-	NominatimBaseURL = "https://nominatim.openstreetmap.org"
-	OverpassBaseURL  = "https://overpass-api.de/api/interpreter"
-	OSRMBaseURL      = "https://router.project-osrm.org"
+	// Public API endpoints, used unless SetServiceURLs overrides them
+	DefaultNominatimBaseURL = "https://nominatim.openstreetmap.org"
+	DefaultOverpassBaseURL  = "https://overpass-api.de/api/interpreter"
+	DefaultOSRMBaseURL      = "https://router.project-osrm.org"
+	// Elevation service, backed by the Copernicus GLO-90 DEM
+	DefaultElevationBaseURL = "https://api.open-meteo.com/v1/elevation"
 
 	// User agent for API requests (required by Nominatim's usage policy)
 	UserAgent = "osm-mcp-server/0.1.0"
@@ -22,6 +26,48 @@ const (
 	EarthRadius = geo.EarthRadius
 )
 
+// API endpoints in use
+var (
+	NominatimBaseURL = DefaultNominatimBaseURL
+	OverpassBaseURL  = DefaultOverpassBaseURL
+	OSRMBaseURL      = DefaultOSRMBaseURL
+	ElevationBaseURL = DefaultElevationBaseURL
+)
+
+// ServiceURLs holds upstream service endpoints, such as those of
+// self-hosted instances. Empty fields keep the current endpoint.
+type ServiceURLs struct {
+	Nominatim string // base URL, e.g. http://nominatim:8080
+	Overpass  string // interpreter URL, e.g. http://overpass/api/interpreter
+	OSRM      string // base URL, e.g. http://osrm:5000
+	Elevation string // Open-Meteo elevation endpoint, e.g. http://open-meteo:8080/v1/elevation
+}
+
+// SetServiceURLs points requests at other upstream services. It must be
+// called at startup, before any requests are made.
+func SetServiceURLs(urls ServiceURLs) error {
+	for _, endpoint := range []struct {
+		name  string
+		value string
+		dest  *string
+	}{
+		{"Nominatim", urls.Nominatim, &NominatimBaseURL},
+		{"Overpass", urls.Overpass, &OverpassBaseURL},
+		{"OSRM", urls.OSRM, &OSRMBaseURL},
+		{"elevation", urls.Elevation, &ElevationBaseURL},
+	} {
+		if endpoint.value == "" {
+			continue
+		}
+		u, err := url.Parse(endpoint.value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s URL %q: must be an absolute http or https URL", endpoint.name, endpoint.value)
+		}
+		*endpoint.dest = strings.TrimSuffix(endpoint.value, "/")
+	}
+	return nil
+}
+
 // NewClient returns an HTTP client configured for OSM API requests
 // Deprecated: Use GetClient(ctx) instead for connection pooling
 func NewClient() *http.Client {
@@ -50,12 +96,7 @@ func NewBoundingBox() *geo.BoundingBox {
 
 // ValidateCoords validates latitude and longitude values
 // Returns an error if the coordinates are invalid
+// Deprecated: Use geo.ValidateCoords instead
 func ValidateCoords(lat, lon float64) error {
-	if lat < -90 || lat > 90 {
-		return fmt.Errorf("invalid latitude: %f (must be between -90 and 90)", lat)
-	}
-	if lon < -180 || lon > 180 {
-		return fmt.Errorf("invalid longitude: %f (must be between -180 and 180)", lon)
-	}
-	return nil
+	return geo.ValidateCoords(lat, lon)
 }
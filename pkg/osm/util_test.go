@@ -64,3 +64,34 @@ func TestValidateCoords(t *testing.T) {
 		})
 	}
 }
+
+func TestSetServiceURLs(t *testing.T) {
+	defer func() {
+		NominatimBaseURL = DefaultNominatimBaseURL
+		OverpassBaseURL = DefaultOverpassBaseURL
+		OSRMBaseURL = DefaultOSRMBaseURL
+	}()
+
+	err := SetServiceURLs(ServiceURLs{
+		Nominatim: "http://nominatim.internal:8080/",
+		OSRM:      "https://osrm.internal",
+	})
+	if err != nil {
+		t.Fatalf("SetServiceURLs failed: %v", err)
+	}
+	if NominatimBaseURL != "http://nominatim.internal:8080" {
+		t.Errorf("expected the trailing slash trimmed, got %s", NominatimBaseURL)
+	}
+	if OverpassBaseURL != DefaultOverpassBaseURL {
+		t.Errorf("expected an empty URL to keep the default, got %s", OverpassBaseURL)
+	}
+	if OSRMBaseURL != "https://osrm.internal" {
+		t.Errorf("expected the OSRM URL to be set, got %s", OSRMBaseURL)
+	}
+
+	for _, invalid := range []string{"osrm.internal:5000", "ftp://osrm.internal", "http://"} {
+		if err := SetServiceURLs(ServiceURLs{OSRM: invalid}); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
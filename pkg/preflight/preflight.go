@@ -0,0 +1,217 @@
+// Package preflight validates the server configuration at startup, before
+// any transport is bound, so misconfiguration fails fast with an actionable
+// message instead of surfacing on the first tool call.
+package preflight
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds each network check
+const DefaultTimeout = 5 * time.Second
+
+// Check is one preflight validation
+type Check struct {
+	// Name identifies what is checked, usually the flag, e.g. --tile-url
+	Name string
+	// Fix tells the operator how to resolve a failure
+	Fix string
+	// Warn reports a failure as a warning instead of stopping startup, for
+	// optional services and built-in defaults
+	Warn bool
+	// Run performs the check
+	Run func(ctx context.Context) error
+}
+
+// Problem is a failed check
+type Problem struct {
+	Check string
+	Err   error
+	Fix   string
+	Warn  bool
+}
+
+// String formats the problem for logs
+func (p Problem) String() string {
+	msg := fmt.Sprintf("%s: %v", p.Check, p.Err)
+	if p.Fix != "" {
+		msg += ". " + p.Fix
+	}
+	return msg
+}
+
+// Run executes the checks concurrently, each bounded by timeout, and
+// returns the failures in the order the checks were given
+func Run(ctx context.Context, timeout time.Duration, checks []Check) []Problem {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			errs[i] = check.Run(checkCtx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	var problems []Problem
+	for i, err := range errs {
+		if err != nil {
+			problems = append(problems, Problem{
+				Check: checks[i].Name,
+				Err:   err,
+				Fix:   checks[i].Fix,
+				Warn:  checks[i].Warn,
+			})
+		}
+	}
+	return problems
+}
+
+// HasErrors reports whether any problem should stop startup
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if !p.Warn {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholderPattern matches {z}-style template placeholders
+var placeholderPattern = regexp.MustCompile(`\{[a-z]+\}`)
+
+// ParseURL checks that a URL, or a {z}/{x}/{y} URL template, is an absolute
+// http or https URL with a host
+func ParseURL(raw string) (*url.URL, error) {
+	filled := placeholderPattern.ReplaceAllStringFunc(raw, func(p string) string {
+		if p == "{s}" {
+			return "a" // tile server subdomain
+		}
+		return "0"
+	})
+	u, err := url.Parse(filled)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("URL %q must start with http:// or https://", raw)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+// Reachable resolves a URL's host and opens a connection to it, completing
+// the TLS handshake for https so certificate problems show up at startup
+func Reachable(ctx context.Context, raw string) error {
+	u, err := ParseURL(raw)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if net.ParseIP(host) == nil {
+		if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+			return fmt.Errorf("DNS lookup of %s failed: %w", host, err)
+		}
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	addr := net.JoinHostPort(host, port)
+
+	if u.Scheme == "https" {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			var certErr *tls.CertificateVerificationError
+			if errors.As(err, &certErr) {
+				return fmt.Errorf("TLS certificate of %s is not trusted: %w", host, err)
+			}
+			return fmt.Errorf("TLS connection to %s failed: %w", addr, err)
+		}
+		return conn.Close()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connection to %s failed: %w", addr, err)
+	}
+	return conn.Close()
+}
+
+// ListenAddress checks that a listen address such as :7082 is well formed.
+// It does not bind the address.
+func ListenAddress(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q in listen address %q", port, addr)
+	}
+	return nil
+}
+
+// ReadablePath checks that a file or directory exists and can be read
+func ReadablePath(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s does not exist", path)
+		}
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("%s is not readable", path)
+		}
+		return err
+	}
+	return f.Close()
+}
+
+// HTTPAuth checks the HTTP transport authentication settings
+func HTTPAuth(authType, token string) error {
+	switch authType {
+	case "none":
+		return nil
+	case "bearer":
+		if token == "" {
+			return errors.New("bearer authentication needs a token")
+		}
+	case "basic":
+		if token == "" {
+			return errors.New("basic authentication needs credentials")
+		}
+		if user, pass, ok := strings.Cut(token, ":"); !ok || user == "" || pass == "" {
+			return errors.New("basic authentication credentials must be username:password")
+		}
+	default:
+		return fmt.Errorf("unknown authentication type %q, use none, bearer or basic", authType)
+	}
+	return nil
+}
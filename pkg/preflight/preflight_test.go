@@ -0,0 +1,135 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "ok", Run: func(context.Context) error { return nil }},
+		{Name: "optional", Warn: true, Fix: "ignore it", Run: func(context.Context) error { return errors.New("down") }},
+		{Name: "slow", Fix: "raise the timeout", Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}
+
+	start := time.Now()
+	problems := Run(context.Background(), 50*time.Millisecond, checks)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("checks took %v, expected the timeout to apply", elapsed)
+	}
+
+	if len(problems) != 2 || problems[0].Check != "optional" || problems[1].Check != "slow" {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+	if !problems[0].Warn || problems[1].Warn {
+		t.Error("unexpected severities")
+	}
+	if !HasErrors(problems) || HasErrors(problems[:1]) {
+		t.Error("HasErrors should only count non-warnings")
+	}
+	if got := problems[0].String(); got != "optional: down. ignore it" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	valid := []string{
+		"https://nominatim.openstreetmap.org",
+		"http://localhost:5000/route",
+		"https://{s}.tile.example.com/{z}/{x}/{y}.png?key={apikey}",
+	}
+	for _, raw := range valid {
+		if _, err := ParseURL(raw); err != nil {
+			t.Errorf("ParseURL(%q) failed: %v", raw, err)
+		}
+	}
+
+	invalid := []string{"", "nominatim.openstreetmap.org", "ftp://example.com", "https://", "http://[::1"}
+	for _, raw := range invalid {
+		if _, err := ParseURL(raw); err == nil {
+			t.Errorf("ParseURL(%q) should fail", raw)
+		}
+	}
+}
+
+func TestReachable(t *testing.T) {
+	plain := httptest.NewServer(http.NotFoundHandler())
+	defer plain.Close()
+	if err := Reachable(context.Background(), plain.URL+"/api"); err != nil {
+		t.Errorf("expected %s to be reachable: %v", plain.URL, err)
+	}
+
+	// The test server's certificate is not trusted by the system pool
+	secure := httptest.NewTLSServer(http.NotFoundHandler())
+	defer secure.Close()
+	err := Reachable(context.Background(), secure.URL)
+	if err == nil || !strings.Contains(err.Error(), "not trusted") {
+		t.Errorf("expected an untrusted certificate error, got %v", err)
+	}
+
+	// Find a closed port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	if err := Reachable(context.Background(), "http://"+addr); err == nil {
+		t.Errorf("expected %s to be unreachable", addr)
+	}
+}
+
+func TestListenAddress(t *testing.T) {
+	for _, addr := range []string{":7082", "0.0.0.0:9090", "[::1]:80"} {
+		if err := ListenAddress(addr); err != nil {
+			t.Errorf("ListenAddress(%q) failed: %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"7082", ":http-alt", ":70000"} {
+		if err := ListenAddress(addr); err == nil {
+			t.Errorf("ListenAddress(%q) should fail", addr)
+		}
+	}
+}
+
+func TestReadablePath(t *testing.T) {
+	dir := t.TempDir()
+	if err := ReadablePath(dir); err != nil {
+		t.Errorf("expected %s to be readable: %v", dir, err)
+	}
+	err := ReadablePath(filepath.Join(dir, "missing.zip"))
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected a missing file error, got %v", err)
+	}
+}
+
+func TestHTTPAuth(t *testing.T) {
+	tests := []struct {
+		authType string
+		token    string
+		ok       bool
+	}{
+		{"none", "", true},
+		{"bearer", "a-long-random-token", true},
+		{"bearer", "", false},
+		{"basic", "admin:s3cret", true},
+		{"basic", "admin", false},
+		{"basic", ":s3cret", false},
+		{"oauth", "token", false},
+	}
+	for _, tt := range tests {
+		if err := HTTPAuth(tt.authType, tt.token); (err == nil) != tt.ok {
+			t.Errorf("HTTPAuth(%q, %q) = %v, want ok %v", tt.authType, tt.token, err, tt.ok)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 // Package registration provides service registration with nerva-monitor.
 // Registration is optional and fails gracefully - the server will always function
-// even if the registry is unavailable.
+// even if the registry is unavailable. A service can register with several
+// registries, e.g. one per region, so each keeps routing to it if another
+// region's registry fails.
 package registration
 
 import (
@@ -11,6 +13,9 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -30,9 +35,26 @@ type Config struct {
 	// e.g., "http://nerva-monitor:7083"
 	RegistryURL string
 
+	// RegistryURLs are additional registries to register with. Each is
+	// registered and heartbeated independently.
+	RegistryURLs []string
+
 	// ServiceName is the unique name of this service
 	ServiceName string
 
+	// InstanceID distinguishes replicas of the service (default: hostname)
+	InstanceID string
+
+	// Region and Zone locate the instance for registry-side load balancing
+	Region string
+	Zone   string
+
+	// Labels are free-form placement labels, e.g. {"tier": "batch"}
+	Labels map[string]string
+
+	// Capacity hints how much traffic the instance should receive
+	Capacity Capacity
+
 	// ServiceType is the type of service (usually "mcp")
 	ServiceType string
 
@@ -65,11 +87,31 @@ type Config struct {
 
 	// Timeout is the HTTP request timeout (default: 5s)
 	Timeout time.Duration
+
+	// SigningSecret, if set, is a secret shared with the registries used to
+	// sign every request with HMAC-SHA256 (see SignRequest), so other hosts
+	// on the network cannot register or deregister in this service's name
+	SigningSecret string
+}
+
+// Capacity hints how a registry should balance traffic across replicas
+type Capacity struct {
+	// Weight is the relative share of traffic, e.g. 2 for a replica with
+	// twice the resources of a weight 1 replica
+	Weight int `json:"weight,omitempty"`
+
+	// MaxSessions is the number of concurrent sessions the instance accepts
+	MaxSessions int `json:"max_sessions,omitempty"`
 }
 
 // RegistrationRequest is the request format for the registry API.
 type RegistrationRequest struct {
 	Name           string                 `json:"name"`
+	InstanceID     string                 `json:"instance_id,omitempty"`
+	Region         string                 `json:"region,omitempty"`
+	Zone           string                 `json:"zone,omitempty"`
+	Labels         map[string]string      `json:"labels,omitempty"`
+	Capacity       *Capacity              `json:"capacity,omitempty"`
 	Type           string                 `json:"type"`
 	URL            string                 `json:"url"`
 	HealthURL      string                 `json:"health_url"`
@@ -96,7 +138,8 @@ type Client struct {
 	httpClient *http.Client
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
-	registered bool
+	registries []string
+	registered map[string]bool // by registry URL
 	mu         sync.RWMutex
 }
 
@@ -112,16 +155,35 @@ func NewClient(cfg Config, logger *slog.Logger) *Client {
 	if cfg.ServiceType == "" {
 		cfg.ServiceType = "mcp"
 	}
+	if cfg.InstanceID == "" {
+		cfg.InstanceID, _ = os.Hostname()
+	}
 
 	return &Client{
-		cfg:    cfg,
-		logger: logger,
+		cfg:        cfg,
+		logger:     logger,
+		registries: registryURLs(cfg),
+		registered: make(map[string]bool),
 		httpClient: &http.Client{
 			Timeout: cfg.Timeout,
 		},
 	}
 }
 
+// registryURLs returns the distinct configured registries, in order
+func registryURLs(cfg Config) []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, u := range append([]string{cfg.RegistryURL}, cfg.RegistryURLs...) {
+		u = strings.TrimRight(strings.TrimSpace(u), "/")
+		if u != "" && !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
 // Start begins the registration and heartbeat loop.
 // This method is non-blocking and returns immediately.
 // If registration is disabled, this is a no-op.
@@ -131,7 +193,7 @@ func (c *Client) Start(ctx context.Context) {
 		return
 	}
 
-	if c.cfg.RegistryURL == "" {
+	if len(c.registries) == 0 {
 		c.logger.Warn("service registration enabled but no registry URL configured")
 		return
 	}
@@ -156,11 +218,31 @@ func (c *Client) Stop() {
 	c.wg.Wait()
 }
 
-// IsRegistered returns whether the service is currently registered.
+// IsRegistered returns whether the service is currently registered with
+// at least one registry.
 func (c *Client) IsRegistered() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.registered
+	for _, ok := range c.registered {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisteredWith returns the registries the service is currently
+// registered with.
+func (c *Client) RegisteredWith() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var urls []string
+	for _, u := range c.registries {
+		if c.registered[u] {
+			urls = append(urls, u)
+		}
+	}
+	return urls
 }
 
 // heartbeatLoop sends periodic heartbeats to the registry.
@@ -168,7 +250,7 @@ func (c *Client) heartbeatLoop(ctx context.Context) {
 	defer c.wg.Done()
 
 	// Initial registration
-	c.register()
+	c.registerAll()
 
 	ticker := time.NewTicker(c.cfg.HeartbeatInterval)
 	defer ticker.Stop()
@@ -176,17 +258,35 @@ func (c *Client) heartbeatLoop(ctx context.Context) {
 	for {
 		select {
 		case <-ticker.C:
-			c.register()
+			c.registerAll()
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-// register sends a registration/heartbeat request to the registry.
-func (c *Client) register() {
+// registerAll registers with every registry concurrently, so a slow or
+// unreachable registry does not delay the others.
+func (c *Client) registerAll() {
+	var wg sync.WaitGroup
+	for _, registry := range c.registries {
+		wg.Add(1)
+		go func(registry string) {
+			defer wg.Done()
+			c.register(registry)
+		}(registry)
+	}
+	wg.Wait()
+}
+
+// request builds the registration request.
+func (c *Client) request() RegistrationRequest {
 	req := RegistrationRequest{
 		Name:           c.cfg.ServiceName,
+		InstanceID:     c.cfg.InstanceID,
+		Region:         c.cfg.Region,
+		Zone:           c.cfg.Zone,
+		Labels:         c.cfg.Labels,
 		Type:           c.cfg.ServiceType,
 		URL:            c.cfg.ServiceURL,
 		HealthURL:      c.cfg.HealthURL,
@@ -197,86 +297,121 @@ func (c *Client) register() {
 		Tools:          c.cfg.Tools,
 		Metadata:       c.cfg.Metadata,
 	}
+	if c.cfg.Capacity != (Capacity{}) {
+		capacity := c.cfg.Capacity
+		req.Capacity = &capacity
+	}
+	return req
+}
 
-	body, err := json.Marshal(req)
+// register sends a registration/heartbeat request to a registry.
+func (c *Client) register(registry string) {
+	logger := c.logger.With("registry", registry)
+
+	body, err := json.Marshal(c.request())
 	if err != nil {
-		c.logger.Error("failed to marshal registration request", "error", err)
-		c.setRegistered(false)
+		logger.Error("failed to marshal registration request", "error", err)
+		c.setRegistered(registry, false)
 		return
 	}
 
-	url := fmt.Sprintf("%s/api/register", c.cfg.RegistryURL)
+	url := fmt.Sprintf("%s/api/register", registry)
 	httpReq, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		c.logger.Error("failed to create registration request", "error", err)
-		c.setRegistered(false)
+		logger.Error("failed to create registration request", "error", err)
+		c.setRegistered(registry, false)
 		return
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	c.sign(httpReq, body)
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		c.logger.Debug("registration failed (registry may be unavailable)", "error", err)
-		c.setRegistered(false)
+		logger.Debug("registration failed (registry may be unavailable)", "error", err)
+		c.setRegistered(registry, false)
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		c.logger.Warn("registration failed", "status", resp.StatusCode, "body", string(bodyBytes))
-		c.setRegistered(false)
+		logger.Warn("registration failed", "status", resp.StatusCode, "body", string(bodyBytes))
+		c.setRegistered(registry, false)
 		return
 	}
 
 	var regResp RegistrationResponse
 	if err := json.NewDecoder(resp.Body).Decode(&regResp); err != nil {
-		c.logger.Warn("failed to decode registration response", "error", err)
-		c.setRegistered(false)
+		logger.Warn("failed to decode registration response", "error", err)
+		c.setRegistered(registry, false)
 		return
 	}
 
-	wasRegistered := c.IsRegistered()
-	c.setRegistered(true)
-
-	if !wasRegistered {
-		c.logger.Info("registered with nerva-monitor",
+	if wasRegistered := c.setRegistered(registry, true); !wasRegistered {
+		logger.Info("registered with nerva-monitor",
 			"name", c.cfg.ServiceName,
+			"instance_id", c.cfg.InstanceID,
 			"ttl_seconds", regResp.TTLSeconds,
 		)
 	}
 }
 
-// deregister sends a deregistration request to the registry.
+// deregister sends a deregistration request to every registry the service
+// is registered with.
 func (c *Client) deregister() {
-	if !c.IsRegistered() {
-		return
+	var wg sync.WaitGroup
+	for _, registry := range c.RegisteredWith() {
+		wg.Add(1)
+		go func(registry string) {
+			defer wg.Done()
+			c.deregisterFrom(registry)
+		}(registry)
 	}
+	wg.Wait()
+}
+
+// deregisterFrom sends a deregistration request to one registry.
+func (c *Client) deregisterFrom(registry string) {
+	logger := c.logger.With("registry", registry)
 
-	url := fmt.Sprintf("%s/api/register/%s", c.cfg.RegistryURL, c.cfg.ServiceName)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, url, nil)
+	endpoint := fmt.Sprintf("%s/api/register/%s", registry, url.PathEscape(c.cfg.ServiceName))
+	if c.cfg.InstanceID != "" {
+		endpoint += "?instance_id=" + url.QueryEscape(c.cfg.InstanceID)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, endpoint, nil)
 	if err != nil {
-		c.logger.Debug("failed to create deregistration request", "error", err)
+		logger.Debug("failed to create deregistration request", "error", err)
 		return
 	}
+	c.sign(req, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		c.logger.Debug("deregistration failed (registry may be unavailable)", "error", err)
+		logger.Debug("deregistration failed (registry may be unavailable)", "error", err)
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusOK {
-		c.logger.Info("deregistered from nerva-monitor", "name", c.cfg.ServiceName)
+		logger.Info("deregistered from nerva-monitor", "name", c.cfg.ServiceName)
 	}
 
-	c.setRegistered(false)
+	c.setRegistered(registry, false)
+}
+
+// sign adds a request signature if a signing secret is configured
+func (c *Client) sign(req *http.Request, body []byte) {
+	if c.cfg.SigningSecret != "" {
+		SignRequest(req, body, c.cfg.SigningSecret, time.Now())
+	}
 }
 
-// setRegistered updates the registration status.
-func (c *Client) setRegistered(registered bool) {
+// setRegistered updates the registration status for a registry and returns
+// the previous status.
+func (c *Client) setRegistered(registry string, registered bool) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.registered = registered
+	previous := c.registered[registry]
+	c.registered[registry] = registered
+	return previous
 }
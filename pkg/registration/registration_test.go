@@ -0,0 +1,128 @@
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRegistry records the registrations it receives
+type fakeRegistry struct {
+	mu           sync.Mutex
+	requests     []RegistrationRequest
+	deregistered []string
+	fail         bool
+}
+
+func (f *fakeRegistry) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			var req RegistrationRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f.requests = append(f.requests, req)
+			json.NewEncoder(w).Encode(RegistrationResponse{Status: "registered", Name: req.Name, TTLSeconds: 90})
+		case http.MethodDelete:
+			f.deregistered = append(f.deregistered, r.URL.Path+"?"+r.URL.RawQuery)
+		}
+	})
+}
+
+func TestRegisterWithMultipleRegistries(t *testing.T) {
+	east, west, down := &fakeRegistry{}, &fakeRegistry{}, &fakeRegistry{fail: true}
+	eastServer := httptest.NewServer(east.handler())
+	defer eastServer.Close()
+	westServer := httptest.NewServer(west.handler())
+	defer westServer.Close()
+	downServer := httptest.NewServer(down.handler())
+	defer downServer.Close()
+
+	client := NewClient(Config{
+		Enabled:      true,
+		RegistryURL:  eastServer.URL,
+		RegistryURLs: []string{westServer.URL + "/", downServer.URL, eastServer.URL},
+		ServiceName:  "osmmcp",
+		InstanceID:   "osmmcp-1",
+		Region:       "eu-west-1",
+		Zone:         "eu-west-1b",
+		Labels:       map[string]string{"tier": "batch"},
+		Capacity:     Capacity{Weight: 2, MaxSessions: 50},
+	}, slog.Default())
+
+	if len(client.registries) != 3 {
+		t.Fatalf("expected duplicate registries to be dropped, got %v", client.registries)
+	}
+
+	client.registerAll()
+
+	if !client.IsRegistered() {
+		t.Fatal("expected to be registered")
+	}
+	if got := client.RegisteredWith(); len(got) != 2 || got[0] != eastServer.URL || got[1] != westServer.URL {
+		t.Errorf("registered with %v, want the two healthy registries", got)
+	}
+
+	if len(west.requests) != 1 {
+		t.Fatalf("expected one registration with west, got %d", len(west.requests))
+	}
+	req := west.requests[0]
+	if req.InstanceID != "osmmcp-1" || req.Region != "eu-west-1" || req.Zone != "eu-west-1b" || req.Labels["tier"] != "batch" {
+		t.Errorf("unexpected placement in %+v", req)
+	}
+	if req.Capacity == nil || req.Capacity.Weight != 2 || req.Capacity.MaxSessions != 50 {
+		t.Errorf("unexpected capacity %+v", req.Capacity)
+	}
+
+	// A registry that recovers is picked up on the next heartbeat
+	down.mu.Lock()
+	down.fail = false
+	down.mu.Unlock()
+	client.registerAll()
+	if got := client.RegisteredWith(); len(got) != 3 {
+		t.Errorf("expected all registries after recovery, got %v", got)
+	}
+
+	client.deregister()
+	if client.IsRegistered() {
+		t.Error("expected to be deregistered")
+	}
+	if len(east.deregistered) != 1 || east.deregistered[0] != "/api/register/osmmcp?instance_id=osmmcp-1" {
+		t.Errorf("unexpected deregistration %v", east.deregistered)
+	}
+}
+
+func TestClientDefaults(t *testing.T) {
+	client := NewClient(Config{ServiceName: "osmmcp"}, slog.Default())
+
+	if client.cfg.HeartbeatInterval != DefaultHeartbeatInterval || client.cfg.Timeout != DefaultTimeout {
+		t.Errorf("unexpected defaults %+v", client.cfg)
+	}
+	if req := client.request(); req.Capacity != nil {
+		t.Error("expected no capacity hint by default")
+	}
+
+	// Start without registries is a no-op
+	client.cfg.Enabled = true
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	client.Start(ctx)
+	client.Stop()
+	if client.IsRegistered() {
+		t.Error("expected no registration without registries")
+	}
+}
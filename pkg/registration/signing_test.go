@@ -0,0 +1,87 @@
+package registration
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerifyRequest(t *testing.T) {
+	now := time.Unix(1735689600, 0)
+	body := []byte(`{"name":"osmmcp"}`)
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "http://registry:7083/api/register?x=1", bytes.NewReader(body))
+		SignRequest(req, body, "shared-secret", now)
+		return req
+	}
+
+	req := newRequest()
+	if err := VerifyRequest(req, "shared-secret", DefaultMaxClockSkew, now.Add(4*time.Minute)); err != nil {
+		t.Fatalf("expected a valid signature within the skew, got %v", err)
+	}
+	if got, _ := io.ReadAll(req.Body); !bytes.Equal(got, body) {
+		t.Errorf("expected the body to be readable after verification, got %q", got)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*http.Request)
+		secret string
+		at     time.Time
+	}{
+		{"Wrong secret", func(*http.Request) {}, "other-secret", now},
+		{"Tampered body", func(r *http.Request) { r.Body = io.NopCloser(strings.NewReader(`{"name":"evil"}`)) }, "shared-secret", now},
+		{"Tampered path", func(r *http.Request) { r.URL.Path = "/api/register/other" }, "shared-secret", now},
+		{"Tampered timestamp", func(r *http.Request) { r.Header.Set(TimestampHeader, "1735689601") }, "shared-secret", now},
+		{"Too old", func(*http.Request) {}, "shared-secret", now.Add(6 * time.Minute)},
+		{"Too far ahead", func(*http.Request) {}, "shared-secret", now.Add(-6 * time.Minute)},
+		{"Unsigned", func(r *http.Request) { r.Header.Del(SignatureHeader) }, "shared-secret", now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest()
+			tt.modify(req)
+			if err := VerifyRequest(req, tt.secret, DefaultMaxClockSkew, tt.at); err == nil {
+				t.Error("expected verification to fail")
+			}
+		})
+	}
+}
+
+func TestClientSignsRequests(t *testing.T) {
+	var mu sync.Mutex
+	var verified []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := VerifyRequest(r, "shared-secret", DefaultMaxClockSkew, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		verified = append(verified, r.Method)
+		mu.Unlock()
+		w.Write([]byte(`{"status":"registered","ttl_seconds":90}`))
+	}))
+	defer server.Close()
+
+	unsigned := NewClient(Config{Enabled: true, RegistryURL: server.URL, ServiceName: "osmmcp"}, slog.Default())
+	unsigned.registerAll()
+	if unsigned.IsRegistered() {
+		t.Error("expected the registry to refuse unsigned requests")
+	}
+
+	client := NewClient(Config{Enabled: true, RegistryURL: server.URL, ServiceName: "osmmcp", SigningSecret: "shared-secret"}, slog.Default())
+	client.registerAll()
+	if !client.IsRegistered() {
+		t.Fatal("expected signed registration to succeed")
+	}
+	client.deregister()
+	if len(verified) != 2 || verified[0] != http.MethodPost || verified[1] != http.MethodDelete {
+		t.Errorf("expected signed registration and deregistration, got %v", verified)
+	}
+}
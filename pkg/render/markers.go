@@ -0,0 +1,74 @@
+package render
+
+import (
+	"image/color"
+	"strconv"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// MarkerColor is the fill of numbered place markers
+var MarkerColor = color.RGBA{R: 0xc6, G: 0x28, B: 0x28, A: 0xff}
+
+// digitGlyphs are 3x5 bitmaps of the digits 0-9, one row per entry with the
+// high bit on the left
+var digitGlyphs = [10][5]uint8{
+	{0b111, 0b101, 0b101, 0b101, 0b111}, // 0
+	{0b010, 0b110, 0b010, 0b010, 0b111}, // 1
+	{0b111, 0b001, 0b111, 0b100, 0b111}, // 2
+	{0b111, 0b001, 0b111, 0b001, 0b111}, // 3
+	{0b101, 0b101, 0b111, 0b001, 0b001}, // 4
+	{0b111, 0b100, 0b111, 0b001, 0b111}, // 5
+	{0b111, 0b100, 0b111, 0b101, 0b111}, // 6
+	{0b111, 0b001, 0b010, 0b010, 0b010}, // 7
+	{0b111, 0b101, 0b111, 0b101, 0b111}, // 8
+	{0b111, 0b101, 0b111, 0b001, 0b111}, // 9
+}
+
+// glyphScale is the size in pixels of one glyph cell
+const glyphScale = 2
+
+// MaxMarkerNumber is the largest number that fits in a marker
+const MaxMarkerNumber = 99
+
+// DrawNumberedMarker draws a marker labelled with a number from 1 to
+// MaxMarkerNumber in white
+func (m *Map) DrawNumberedMarker(loc geo.Location, number int, c color.Color) {
+	const radius = 10
+	m.DrawMarker(loc, c, radius)
+	if number < 0 || number > MaxMarkerNumber {
+		return
+	}
+
+	label := strconv.Itoa(number)
+	textWidth := len(label)*3*glyphScale + (len(label)-1)*glyphScale
+	x, y := m.Point(loc)
+	left := int(x) - textWidth/2
+	top := int(y) - 5*glyphScale/2
+
+	for i, r := range label {
+		glyph := digitGlyphs[r-'0']
+		for row := 0; row < 5; row++ {
+			for col := 0; col < 3; col++ {
+				if glyph[row]&(0b100>>col) == 0 {
+					continue
+				}
+				px := left + (i*4+col)*glyphScale
+				py := top + row*glyphScale
+				m.fillRect(px, py, glyphScale, glyphScale, CasingColor)
+			}
+		}
+	}
+}
+
+// fillRect fills a rectangle, skipping pixels outside the image
+func (m *Map) fillRect(x, y, w, h int, c color.Color) {
+	bounds := m.img.Bounds()
+	for py := y; py < y+h; py++ {
+		for px := x; px < x+w; px++ {
+			if px >= bounds.Min.X && px < bounds.Max.X && py >= bounds.Min.Y && py < bounds.Max.Y {
+				m.img.Set(px, py, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package render draws static map images from OpenStreetMap tiles with
+// route and marker overlays.
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // tile servers may serve JPEG
+	"image/png"
+	"math"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// TileSize is the edge length of a map tile in pixels
+const TileSize = 256
+
+// Limits for rendered images
+const (
+	MinSize = 64
+	MaxSize = 1280
+	MaxZoom = 19
+)
+
+// Overlay colors
+var (
+	RouteColor  = color.RGBA{R: 0x1e, G: 0x64, B: 0xff, A: 0xff}
+	CasingColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	StartColor  = color.RGBA{R: 0x2e, G: 0xa0, B: 0x43, A: 0xff}
+	EndColor    = color.RGBA{R: 0xd7, G: 0x30, B: 0x27, A: 0xff}
+	background  = color.RGBA{R: 0xe5, G: 0xe3, B: 0xdf, A: 0xff}
+)
+
+// TileFetcher returns the image data of a tile
+type TileFetcher func(ctx context.Context, x, y, zoom int) ([]byte, error)
+
+// View is the area shown by a map image
+type View struct {
+	Zoom   int             `json:"zoom"`
+	Center geo.Location    `json:"center"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Bounds geo.BoundingBox `json:"bounds"`
+}
+
+// worldSize returns the size of the world in pixels at a zoom level
+func worldSize(zoom int) float64 {
+	return TileSize * math.Exp2(float64(zoom))
+}
+
+// project converts a location to world pixel coordinates at a zoom level
+func project(loc geo.Location, zoom int) (x, y float64) {
+	lat := math.Max(-85.05112878, math.Min(85.05112878, loc.Latitude))
+	sinLat := math.Sin(lat * math.Pi / 180)
+	size := worldSize(zoom)
+	x = (loc.Longitude + 180) / 360 * size
+	y = (0.5 - math.Log((1+sinLat)/(1-sinLat))/(4*math.Pi)) * size
+	return x, y
+}
+
+// unproject converts world pixel coordinates back to a location
+func unproject(x, y float64, zoom int) geo.Location {
+	size := worldSize(zoom)
+	lon := x/size*360 - 180
+	lat := math.Atan(math.Sinh(math.Pi*(1-2*y/size))) * 180 / math.Pi
+	return geo.Location{Latitude: lat, Longitude: lon}
+}
+
+// NewView returns the view of the given size centered on a location
+func NewView(center geo.Location, zoom, width, height int) View {
+	cx, cy := project(center, zoom)
+	nw := unproject(cx-float64(width)/2, cy-float64(height)/2, zoom)
+	se := unproject(cx+float64(width)/2, cy+float64(height)/2, zoom)
+	return View{
+		Zoom:   zoom,
+		Center: center,
+		Width:  width,
+		Height: height,
+		Bounds: geo.BoundingBox{MinLat: se.Latitude, MinLon: nw.Longitude, MaxLat: nw.Latitude, MaxLon: se.Longitude},
+	}
+}
+
+// FitBounds returns the view of the given size at the highest zoom, up to
+// maxZoom, showing the whole bounding box with padding pixels to spare on
+// each side
+func FitBounds(bbox geo.BoundingBox, width, height, padding, maxZoom int) View {
+	zoom := maxZoom
+	for ; zoom > 0; zoom-- {
+		minX, maxY := project(geo.Location{Latitude: bbox.MinLat, Longitude: bbox.MinLon}, zoom)
+		maxX, minY := project(geo.Location{Latitude: bbox.MaxLat, Longitude: bbox.MaxLon}, zoom)
+		if maxX-minX <= float64(width-2*padding) && maxY-minY <= float64(height-2*padding) {
+			break
+		}
+	}
+
+	minX, maxY := project(geo.Location{Latitude: bbox.MinLat, Longitude: bbox.MinLon}, zoom)
+	maxX, minY := project(geo.Location{Latitude: bbox.MaxLat, Longitude: bbox.MaxLon}, zoom)
+	center := unproject((minX+maxX)/2, (minY+maxY)/2, zoom)
+	return NewView(center, zoom, width, height)
+}
+
+// Map is a map image being drawn
+type Map struct {
+	View  View
+	Tiles int // tiles fetched
+
+	img              *image.RGBA
+	originX, originY float64 // world pixel of the top-left corner
+}
+
+// NewMap fetches and stitches the tiles covering a view
+func NewMap(ctx context.Context, view View, fetch TileFetcher) (*Map, error) {
+	if view.Width < MinSize || view.Width > MaxSize || view.Height < MinSize || view.Height > MaxSize {
+		return nil, fmt.Errorf("image size must be between %d and %d pixels", MinSize, MaxSize)
+	}
+	if view.Zoom < 0 || view.Zoom > MaxZoom {
+		return nil, fmt.Errorf("zoom must be between 0 and %d", MaxZoom)
+	}
+
+	cx, cy := project(view.Center, view.Zoom)
+	m := &Map{
+		View:    view,
+		img:     image.NewRGBA(image.Rect(0, 0, view.Width, view.Height)),
+		originX: math.Floor(cx - float64(view.Width)/2),
+		originY: math.Floor(cy - float64(view.Height)/2),
+	}
+	draw.Draw(m.img, m.img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	n := 1 << view.Zoom
+	firstX, firstY := int(math.Floor(m.originX/TileSize)), int(math.Floor(m.originY/TileSize))
+	lastX := int(math.Floor((m.originX + float64(view.Width) - 1) / TileSize))
+	lastY := int(math.Floor((m.originY + float64(view.Height) - 1) / TileSize))
+
+	for ty := firstY; ty <= lastY; ty++ {
+		if ty < 0 || ty >= n {
+			continue // beyond the poles
+		}
+		for tx := firstX; tx <= lastX; tx++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			// Wrap around the antimeridian
+			data, err := fetch(ctx, ((tx%n)+n)%n, ty, view.Zoom)
+			if err != nil {
+				return nil, fmt.Errorf("fetch tile %d/%d/%d: %w", view.Zoom, tx, ty, err)
+			}
+			tile, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("decode tile %d/%d/%d: %w", view.Zoom, tx, ty, err)
+			}
+			m.Tiles++
+
+			at := image.Pt(tx*TileSize-int(m.originX), ty*TileSize-int(m.originY))
+			draw.Draw(m.img, tile.Bounds().Sub(tile.Bounds().Min).Add(at), tile, tile.Bounds().Min, draw.Src)
+		}
+	}
+	return m, nil
+}
+
+// Point returns the pixel position of a location on the image
+func (m *Map) Point(loc geo.Location) (x, y float64) {
+	wx, wy := project(loc, m.View.Zoom)
+	return wx - m.originX, wy - m.originY
+}
+
+// DrawPath draws a line through the locations with a casing of
+// width+2 pixels, so it stands out on any background
+func (m *Map) DrawPath(path []geo.Location, c color.Color, width float64) {
+	m.strokePath(path, CasingColor, width+2)
+	m.strokePath(path, c, width)
+}
+
+func (m *Map) strokePath(path []geo.Location, c color.Color, width float64) {
+	if len(path) == 1 {
+		x, y := m.Point(path[0])
+		m.fillCircle(x, y, width/2, c)
+		return
+	}
+	for i := 1; i < len(path); i++ {
+		x0, y0 := m.Point(path[i-1])
+		x1, y1 := m.Point(path[i])
+		steps := math.Ceil(math.Hypot(x1-x0, y1-y0) * 2)
+		for s := 0.0; s <= steps; s++ {
+			f := 0.0
+			if steps > 0 {
+				f = s / steps
+			}
+			m.fillCircle(x0+(x1-x0)*f, y0+(y1-y0)*f, width/2, c)
+		}
+	}
+}
+
+// DrawMarker draws a filled circle with a white outline
+func (m *Map) DrawMarker(loc geo.Location, c color.Color, radius float64) {
+	x, y := m.Point(loc)
+	m.fillCircle(x, y, radius+2, CasingColor)
+	m.fillCircle(x, y, radius, c)
+}
+
+// fillCircle fills a circle, skipping pixels outside the image
+func (m *Map) fillCircle(cx, cy, r float64, c color.Color) {
+	bounds := m.img.Bounds()
+	if cx+r < 0 || cy+r < 0 || cx-r > float64(bounds.Max.X) || cy-r > float64(bounds.Max.Y) {
+		return
+	}
+	for y := int(math.Floor(cy - r)); y <= int(math.Ceil(cy+r)); y++ {
+		for x := int(math.Floor(cx - r)); x <= int(math.Ceil(cx+r)); x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= r*r && image.Pt(x, y).In(bounds) {
+				m.img.Set(x, y, c)
+			}
+		}
+	}
+}
+
+// Image returns the drawn image
+func (m *Map) Image() image.Image {
+	return m.img
+}
+
+// PNG encodes the image as PNG
+func (m *Map) PNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, m.img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// solidTile returns a fetcher serving plain gray PNG tiles
+func solidTile(fetched *int) TileFetcher {
+	return func(ctx context.Context, x, y, zoom int) ([]byte, error) {
+		*fetched++
+		img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+		for i := range img.Pix {
+			img.Pix[i] = 0xc0
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+func TestProjectRoundTrip(t *testing.T) {
+	loc := geo.Location{Latitude: 51.5074, Longitude: -0.1278}
+	x, y := project(loc, 12)
+	back := unproject(x, y, 12)
+	if math.Abs(back.Latitude-loc.Latitude) > 1e-9 || math.Abs(back.Longitude-loc.Longitude) > 1e-9 {
+		t.Errorf("round trip gave %v, want %v", back, loc)
+	}
+}
+
+func TestFitBounds(t *testing.T) {
+	// Central London, about 4 km across
+	bbox := geo.BoundingBox{MinLat: 51.49, MinLon: -0.16, MaxLat: 51.52, MaxLon: -0.10}
+	view := FitBounds(bbox, 640, 480, 32, 18)
+
+	if view.Zoom != 13 {
+		t.Errorf("expected zoom 13, got %d", view.Zoom)
+	}
+	if view.Bounds.MinLat > bbox.MinLat || view.Bounds.MaxLat < bbox.MaxLat ||
+		view.Bounds.MinLon > bbox.MinLon || view.Bounds.MaxLon < bbox.MaxLon {
+		t.Errorf("view %+v does not cover %+v", view.Bounds, bbox)
+	}
+
+	// A single point uses the maximum zoom
+	point := geo.BoundingBox{MinLat: 51.5, MinLon: -0.1, MaxLat: 51.5, MaxLon: -0.1}
+	if view := FitBounds(point, 640, 480, 32, 16); view.Zoom != 16 {
+		t.Errorf("expected zoom 16 for a point, got %d", view.Zoom)
+	}
+}
+
+func TestNewMapDrawsOverlays(t *testing.T) {
+	fetched := 0
+	path := []geo.Location{{Latitude: 51.49, Longitude: -0.16}, {Latitude: 51.52, Longitude: -0.10}}
+	view := FitBounds(geo.BoundingBox{MinLat: 51.49, MinLon: -0.16, MaxLat: 51.52, MaxLon: -0.10}, 320, 240, 16, 18)
+
+	m, err := NewMap(context.Background(), view, solidTile(&fetched))
+	if err != nil {
+		t.Fatalf("NewMap failed: %v", err)
+	}
+	if fetched == 0 || m.Tiles != fetched {
+		t.Errorf("fetched %d tiles, counted %d", fetched, m.Tiles)
+	}
+
+	m.DrawPath(path, RouteColor, 4)
+	m.DrawMarker(path[0], StartColor, 6)
+
+	x, y := m.Point(path[0])
+	if got := m.Image().At(int(x), int(y)); got != color.Color(StartColor) {
+		t.Errorf("expected the start marker at (%.0f, %.0f), got %v", x, y, got)
+	}
+	mx, my := m.Point(geo.Location{Latitude: 51.505, Longitude: -0.13})
+	if got := m.Image().At(int(mx), int(my)); got != color.Color(RouteColor) {
+		t.Errorf("expected the route at (%.0f, %.0f), got %v", mx, my, got)
+	}
+
+	data, err := m.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil || img.Bounds().Dx() != 320 || img.Bounds().Dy() != 240 {
+		t.Errorf("unexpected PNG %v %v", img.Bounds(), err)
+	}
+}
+
+func TestNewMapErrors(t *testing.T) {
+	fetched := 0
+	view := NewView(geo.Location{Latitude: 0, Longitude: 0}, 3, 10, 10)
+	if _, err := NewMap(context.Background(), view, solidTile(&fetched)); err == nil {
+		t.Error("expected an error for a tiny image")
+	}
+
+	failing := func(ctx context.Context, x, y, zoom int) ([]byte, error) {
+		return nil, errors.New("tile server down")
+	}
+	view = NewView(geo.Location{Latitude: 0, Longitude: 0}, 3, 256, 256)
+	if _, err := NewMap(context.Background(), view, failing); err == nil {
+		t.Error("expected tile errors to be returned")
+	}
+}
+
+func TestDrawNumberedMarker(t *testing.T) {
+	fetched := 0
+	loc := geo.Location{Latitude: 48.8566, Longitude: 2.3522}
+	m, err := NewMap(context.Background(), NewView(loc, 14, 128, 128), solidTile(&fetched))
+	if err != nil {
+		t.Fatalf("NewMap failed: %v", err)
+	}
+	m.DrawNumberedMarker(loc, 8, MarkerColor)
+
+	x, y := m.Point(loc)
+	if got := m.Image().At(int(x), int(y)); got != color.Color(CasingColor) {
+		t.Errorf("expected the digit at the marker center, got %v", got)
+	}
+	if got := m.Image().At(int(x)+7, int(y)); got != color.Color(MarkerColor) {
+		t.Errorf("expected the marker fill beside the digit, got %v", got)
+	}
+}
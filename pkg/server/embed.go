@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+	"github.com/NERVsystems/osmmcp/pkg/tools"
+)
+
+// Option configures a Server created with New.
+//
+// Options that change upstream behavior, such as WithUserAgent, apply to the
+// whole process, since the OSM clients are shared.
+type Option func(*options)
+
+// options collects the settings applied by Option values.
+type options struct {
+	logger        *slog.Logger
+	name          string
+	version       string
+	include       map[string]bool
+	exclude       map[string]bool
+	userAgent     string
+	prompts       bool
+	serverOptions []mcpserver.ServerOption
+}
+
+// WithLogger sets the logger for the server and tool registry. Tools log
+// through slog.Default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithName sets the server name and version reported to MCP clients.
+func WithName(name, version string) Option {
+	return func(o *options) {
+		o.name = name
+		o.version = version
+	}
+}
+
+// WithTools limits the server to the named tools.
+func WithTools(names ...string) Option {
+	return func(o *options) {
+		if o.include == nil {
+			o.include = make(map[string]bool)
+		}
+		for _, name := range names {
+			o.include[name] = true
+		}
+	}
+}
+
+// WithoutTools removes the named tools from the server.
+func WithoutTools(names ...string) Option {
+	return func(o *options) {
+		if o.exclude == nil {
+			o.exclude = make(map[string]bool)
+		}
+		for _, name := range names {
+			o.exclude[name] = true
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent sent to OSM services, which their usage
+// policies require to identify the application.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithoutPrompts skips registering the geocoding prompts.
+func WithoutPrompts() Option {
+	return func(o *options) {
+		o.prompts = false
+	}
+}
+
+// WithServerOptions passes options through to the underlying mcp-go server,
+// e.g. mcpserver.WithHooks.
+func WithServerOptions(opts ...mcpserver.ServerOption) Option {
+	return func(o *options) {
+		o.serverOptions = append(o.serverOptions, opts...)
+	}
+}
+
+// New creates an OpenStreetMap MCP server for embedding in another Go
+// program. The caller serves it over its own transport, e.g. with
+// HTTPHandler or mcpserver.ServeStdio(s.GetMCPServer()), or calls tools
+// directly with CallTool.
+func New(opts ...Option) (*Server, error) {
+	o := options{
+		logger:  slog.Default(),
+		name:    ServerName,
+		version: ServerVersion,
+		prompts: true,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.userAgent != "" {
+		osm.SetUserAgent(o.userAgent)
+	}
+
+	registry := tools.NewRegistry(o.logger)
+	if err := checkToolNames(registry.GetToolNames(), o.include, o.exclude); err != nil {
+		return nil, err
+	}
+	if o.include != nil || o.exclude != nil {
+		registry.WithFilter(func(name string) bool {
+			return (o.include == nil || o.include[name]) && !o.exclude[name]
+		})
+	}
+
+	return newServer(o, registry), nil
+}
+
+// checkToolNames reports tool names in the options that do not exist
+func checkToolNames(available []string, include, exclude map[string]bool) error {
+	known := make(map[string]bool, len(available))
+	for _, name := range available {
+		known[name] = true
+	}
+
+	var unknown []string
+	for _, names := range []map[string]bool{include, exclude} {
+		for name := range names {
+			if !known[name] {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown tools: %v", unknown)
+	}
+	return nil
+}
+
+// Tools returns the names of the tools the server provides, sorted.
+func (s *Server) Tools() []string {
+	names := make([]string, 0, len(s.srv.ListTools()))
+	for name := range s.srv.ListTools() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CallTool runs a tool in-process, without an MCP transport. Tool failures
+// are reported in the result, with IsError set, as MCP clients see them.
+func (s *Server) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	tool := s.srv.GetTool(name)
+	if tool == nil {
+		return nil, fmt.Errorf("unknown tool %q", name)
+	}
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      name,
+			Arguments: args,
+		},
+	}
+	return tool.Handler(ctx, req)
+}
+
+// HTTPHandler returns an http.Handler serving the server over the MCP
+// Streamable HTTP transport at endpoint, for mounting on the caller's mux.
+func (s *Server) HTTPHandler(endpoint string) http.Handler {
+	return mcpserver.NewStreamableHTTPServer(s.srv, mcpserver.WithEndpointPath(endpoint))
+}
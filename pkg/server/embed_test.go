@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewWithTools(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s, err := New(WithLogger(logger), WithName("embedded-osm", "1.2.3"), WithTools("geo_distance", "geo_midpoint"), WithoutPrompts())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if tools := s.Tools(); len(tools) != 2 || tools[0] != "geo_distance" || tools[1] != "geo_midpoint" {
+		t.Errorf("unexpected tools %v", tools)
+	}
+
+	result, err := s.CallTool(context.Background(), "geo_distance", map[string]any{
+		"from": map[string]any{"latitude": 51.5074, "longitude": -0.1278},
+		"to":   map[string]any{"latitude": 48.8566, "longitude": 2.3522},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("CallTool failed: %v %v", err, result)
+	}
+	var output struct {
+		Distance float64 `json:"distance"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Distance < 340000 || output.Distance > 345000 {
+		t.Errorf("unexpected London-Paris distance %.0f m", output.Distance)
+	}
+
+	if _, err := s.CallTool(context.Background(), "geocode_address", nil); err == nil {
+		t.Error("expected an error for a tool that was not included")
+	}
+}
+
+func TestNewWithoutTools(t *testing.T) {
+	all, err := New()
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s, err := New(WithoutTools("tile_cache", "get_map_image"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if len(s.Tools()) != len(all.Tools())-2 {
+		t.Errorf("expected two tools fewer than %d, got %d", len(all.Tools()), len(s.Tools()))
+	}
+	for _, name := range s.Tools() {
+		if name == "tile_cache" || name == "get_map_image" {
+			t.Errorf("excluded tool %s was registered", name)
+		}
+	}
+}
+
+func TestNewUnknownTool(t *testing.T) {
+	_, err := New(WithTools("geo_distance", "teleport"), WithoutTools("warp"))
+	if err == nil || !strings.Contains(err.Error(), "teleport") || !strings.Contains(err.Error(), "warp") {
+		t.Errorf("expected the unknown tools to be reported, got %v", err)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	s, err := New(WithTools("geo_midpoint"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/osm/mcp", s.HTTPHandler("/osm/mcp"))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+	resp, err := http.Post(ts.URL+"/osm/mcp", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(data), ServerName) {
+		t.Errorf("unexpected initialize response %d: %s", resp.StatusCode, data)
+	}
+}
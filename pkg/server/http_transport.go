@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,6 +17,7 @@ import (
 
 	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/monitoring"
+	"github.com/NERVsystems/osmmcp/pkg/tools"
 )
 
 // HTTPTransportConfig holds configuration for the HTTP transport
@@ -29,6 +34,34 @@ type HTTPTransportConfig struct {
 	TLSCertFile    string  `json:"tls_cert_file"`    // Path to TLS certificate file
 	TLSKeyFile     string  `json:"tls_key_file"`     // Path to TLS private key file
 	ForceHTTPS     bool    `json:"force_https"`      // Force HTTPS redirect for HTTP requests
+
+	// AuthTokens are further bearer tokens with validity windows, accepted
+	// alongside AuthToken so tokens can be rotated without downtime
+	AuthTokens []core.BearerToken `json:"auth_tokens"`
+
+	// Roles grants tool groups (see tools.ToolGroups) to the roles of bearer
+	// tokens. Tokens without a role may call every tool; a role missing here
+	// may call none.
+	Roles map[string][]string `json:"roles"`
+
+	// Clients failing authentication AuthLockoutFailures times within
+	// AuthLockoutWindow are refused for AuthLockoutDuration (0 = disabled)
+	AuthLockoutFailures int           `json:"auth_lockout_failures"`
+	AuthLockoutWindow   time.Duration `json:"auth_lockout_window"`
+	AuthLockoutDuration time.Duration `json:"auth_lockout_duration"`
+
+	// TrustedProxies are the IPs or CIDR ranges of reverse proxies whose
+	// X-Forwarded-For header names the client for the lockout. Headers
+	// from other peers are ignored, as clients could forge them.
+	TrustedProxies []string `json:"trusted_proxies"`
+
+	// SessionIdleTTL ends sessions without requests or an open stream for
+	// this long, as clients often leave without a DELETE (0 = never)
+	SessionIdleTTL time.Duration `json:"session_idle_ttl"`
+
+	// Compression gzips JSON and SSE responses for clients sending
+	// Accept-Encoding: gzip
+	Compression bool `json:"compression"`
 }
 
 // DefaultHTTPTransportConfig returns sensible defaults
@@ -46,9 +79,19 @@ func DefaultHTTPTransportConfig() HTTPTransportConfig {
 		TLSCertFile:    "",       // No TLS by default
 		TLSKeyFile:     "",       // No TLS by default
 		ForceHTTPS:     false,    // No HTTPS enforcement by default
+
+		AuthLockoutFailures: 10,               // Lock out after 10 failures
+		AuthLockoutWindow:   5 * time.Minute,  // within 5 minutes
+		AuthLockoutDuration: 15 * time.Minute, // for 15 minutes
+
+		SessionIdleTTL: 30 * time.Minute,
+		Compression:    true,
 	}
 }
 
+// minGzipSize is the smallest JSON response worth compressing
+const minGzipSize = 1024
+
 // HTTPTransport implements Streamable HTTP transport for MCP (2025-03-26 spec)
 type HTTPTransport struct {
 	config           HTTPTransportConfig
@@ -57,6 +100,11 @@ type HTTPTransport struct {
 	mux              *http.ServeMux
 	httpSrv          *http.Server
 	healthChecker    *monitoring.HealthChecker
+	bearerTokens     []core.BearerToken
+	lockout          *core.AuthLockout
+	trustedProxies   []*net.IPNet
+	sessions         *sessionManager
+	stopSessions     context.CancelFunc
 	mu               sync.RWMutex
 }
 
@@ -72,11 +120,29 @@ func NewHTTPTransport(mcpServer *mcpserver.MCPServer, config HTTPTransportConfig
 			logger.Warn("weak authentication token detected", "error", err.Error())
 		}
 	}
+	bearerTokens := append([]core.BearerToken{}, config.AuthTokens...)
+	if config.AuthToken != "" {
+		bearerTokens = append(bearerTokens, core.BearerToken{Token: config.AuthToken})
+	}
+	if config.AuthType == "bearer" {
+		for i, token := range config.AuthTokens {
+			if err := core.ValidateAuthToken(token.Token); err != nil {
+				logger.Warn("weak authentication token detected", "index", i, "error", err.Error())
+			}
+		}
+	}
+
+	trustedProxies, err := ParseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		logger.Warn("ignoring invalid trusted proxies", "error", err.Error())
+	}
 
 	// Create Streamable HTTP server (MCP 2025-03-26 spec)
+	sessions := newSessionManager(config.SessionIdleTTL)
 	streamableServer := mcpserver.NewStreamableHTTPServer(
 		mcpServer,
 		mcpserver.WithEndpointPath(config.MCPEndpoint),
+		mcpserver.WithSessionIdManager(sessions),
 	)
 
 	// Create HTTP mux
@@ -87,7 +153,12 @@ func NewHTTPTransport(mcpServer *mcpserver.MCPServer, config HTTPTransportConfig
 		logger:           logger,
 		streamableServer: streamableServer,
 		mux:              mux,
+		bearerTokens:     bearerTokens,
+		lockout:          core.NewAuthLockout(config.AuthLockoutFailures, config.AuthLockoutWindow, config.AuthLockoutDuration),
+		trustedProxies:   trustedProxies,
+		sessions:         sessions,
 	}
+	sessions.onChange = transport.updateSessionCount
 
 	// Mount handlers with proper routing for streamable HTTP
 	transport.setupRoutes()
@@ -119,7 +190,7 @@ func (t *HTTPTransport) setupRoutes() {
 	// GET:    SSE stream for server→client messages
 	// POST:   JSON-RPC messages (client→server)
 	// DELETE: Session termination
-	t.mux.Handle(t.config.MCPEndpoint, t.httpsEnforcement(t.authMiddleware(t.streamableServer).ServeHTTP))
+	t.mux.Handle(t.config.MCPEndpoint, t.httpsEnforcement(t.authMiddleware(t.sessionMiddleware(t.streamableServer)).ServeHTTP))
 }
 
 // httpsEnforcement redirects HTTP requests to HTTPS if ForceHTTPS is enabled
@@ -159,12 +230,23 @@ func (t *HTTPTransport) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Refuse clients locked out after repeated failures without
+		// checking their credentials
+		client := clientIP(r, t.trustedProxies)
+		now := time.Now()
+		if until := t.lockout.LockedUntil(client, now); !until.IsZero() {
+			monitoring.RecordAuthFailure("locked")
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(until.Sub(now).Seconds()))))
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+
 		var authResult core.AuthResult
 
 		switch t.config.AuthType {
 		case "bearer":
 			authHeader := r.Header.Get("Authorization")
-			authResult = core.AuthenticateBearer(authHeader, t.config.AuthToken)
+			authResult = core.AuthenticateBearerTokens(authHeader, t.bearerTokens, now)
 
 		case "basic":
 			username, password, ok := r.BasicAuth()
@@ -172,6 +254,7 @@ func (t *HTTPTransport) authMiddleware(next http.Handler) http.Handler {
 				authResult = core.AuthResult{
 					Authorized: false,
 					Error:      "Missing basic auth credentials",
+					Reason:     "missing",
 				}
 			} else {
 				authResult = core.AuthenticateBasic(username, password, t.config.AuthToken)
@@ -181,6 +264,7 @@ func (t *HTTPTransport) authMiddleware(next http.Handler) http.Handler {
 			authResult = core.AuthResult{
 				Authorized: false,
 				Error:      "Unknown auth type",
+				Reason:     "invalid",
 			}
 		}
 
@@ -192,10 +276,34 @@ func (t *HTTPTransport) authMiddleware(next http.Handler) http.Handler {
 				"error", authResult.Error,
 				"auth_duration", authResult.Duration)
 
+			monitoring.RecordAuthFailure(authResult.Reason)
+			if t.lockout.Failure(client, now) {
+				t.logger.Warn("client locked out after repeated authentication failures",
+					"client_ip", client,
+					"duration", t.config.AuthLockoutDuration)
+				monitoring.RecordAuthLockout()
+			}
+			monitoring.UpdateAuthLockedClients(t.lockout.Locked(now))
+
 			w.Header().Set("WWW-Authenticate", "Bearer")
 			t.writeJSONRPCError(w, nil, -32602, "Authentication required")
 			return
 		}
+		t.lockout.Success(client)
+
+		// Limit the tools a token with a role may list and call
+		if authResult.Role != "" {
+			r = r.WithContext(tools.WithAllowedGroups(r.Context(), t.config.Roles[authResult.Role]))
+		}
+
+		// Sessions are bound to the credential that created them
+		var credential string
+		if t.config.AuthType == "basic" {
+			credential, _, _ = r.BasicAuth()
+		} else {
+			credential = strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		}
+		r = r.WithContext(withIdentity(r.Context(), credentialIdentity(t.config.AuthType, credential)))
 
 		next.ServeHTTP(w, r)
 	})
@@ -393,11 +501,21 @@ func (t *HTTPTransport) Start() error {
 
 	// Apply middleware in the correct order
 	handler := http.Handler(t.mux)
+	if t.config.Compression {
+		handler = GzipCompression(minGzipSize)(handler)
+	}
 	handler = TracingMiddleware()(handler) // Add tracing first to capture all requests
 	handler = LoggingMiddleware(t.logger)(handler)
 	handler = SecurityHeaders(handler)
 	handler = RequestSizeLimiter(10 * 1024 * 1024)(handler) // 10MB limit
 
+	// Expire idle sessions, checking several times per TTL
+	if t.config.SessionIdleTTL > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.stopSessions = cancel
+		go t.expireSessions(ctx, max(t.config.SessionIdleTTL/4, time.Second))
+	}
+
 	t.httpSrv = &http.Server{
 		Addr:         t.config.Addr,
 		Handler:      handler,
@@ -457,6 +575,11 @@ func (t *HTTPTransport) Shutdown(ctx context.Context) error {
 
 	t.logger.Info("shutting down Streamable HTTP transport")
 
+	if t.stopSessions != nil {
+		t.stopSessions()
+		t.stopSessions = nil
+	}
+
 	// Shutdown streamable server first
 	if err := t.streamableServer.Shutdown(ctx); err != nil {
 		t.logger.Error("failed to shutdown streamable server", "error", err)
@@ -12,6 +12,9 @@ import (
 	"time"
 
 	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/tools"
 )
 
 func TestHTTPTransport_ServiceDiscovery(t *testing.T) {
@@ -247,3 +250,138 @@ func TestHTTPTransport_ForceHTTPSWithoutTLS(t *testing.T) {
 		transport.Shutdown(context.Background())
 	}
 }
+
+func TestHTTPTransport_AuthRotationAndLockout(t *testing.T) {
+	mcpSrv := mcpserver.NewMCPServer("test-server", "1.0.0")
+	config := DefaultHTTPTransportConfig()
+	config.AuthType = "bearer"
+	config.AuthToken = "q7Rv2LkP9xWm4ZtB"
+	config.AuthTokens = []core.BearerToken{
+		{Token: "h3Nc8YfJ5sGd1UeA", NotBefore: time.Now().Add(-time.Hour)},
+		{Token: "z6Kp0MwQ2vTb8RxE", NotAfter: time.Now().Add(-time.Hour)},
+	}
+	config.AuthLockoutFailures = 3
+	transport := NewHTTPTransport(mcpSrv, config, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := transport.authMiddleware(ok)
+	request := func(ip, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = ip + ":40000"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, token := range []string{config.AuthToken, "h3Nc8YfJ5sGd1UeA"} {
+		if w := request("192.0.2.1", token); w.Code != http.StatusOK {
+			t.Errorf("expected token %s to be accepted, got %d", token, w.Code)
+		}
+	}
+	if w := request("192.0.2.1", "z6Kp0MwQ2vTb8RxE"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected the expired token to be refused, got %d", w.Code)
+	}
+
+	// Two more failures lock the client out, even with a valid token
+	request("192.0.2.1", "")
+	request("192.0.2.1", "wrong")
+	w := request("192.0.2.1", config.AuthToken)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected a locked out client to get 429 with Retry-After, got %d", w.Code)
+	}
+
+	if w := request("192.0.2.2", config.AuthToken); w.Code != http.StatusOK {
+		t.Errorf("expected other clients to be unaffected, got %d", w.Code)
+	}
+}
+
+func TestHTTPTransport_LockoutIgnoresForgedForwarding(t *testing.T) {
+	mcpSrv := mcpserver.NewMCPServer("test-server", "1.0.0")
+	config := DefaultHTTPTransportConfig()
+	config.AuthType = "bearer"
+	config.AuthToken = "q7Rv2LkP9xWm4ZtB"
+	config.AuthLockoutFailures = 2
+	config.TrustedProxies = []string{"10.0.0.0/8"}
+	transport := NewHTTPTransport(mcpSrv, config, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := transport.authMiddleware(ok)
+	request := func(peer, forwardedFor, token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = peer + ":40000"
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+			req.Header.Set("X-Real-IP", forwardedFor)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Rotating a forged header does not dodge the lockout of the peer
+	request("192.0.2.1", "198.51.100.1", "wrong")
+	request("192.0.2.1", "198.51.100.2", "wrong")
+	if code := request("192.0.2.1", "198.51.100.3", config.AuthToken); code != http.StatusTooManyRequests {
+		t.Errorf("expected the peer to be locked out, got %d", code)
+	}
+
+	// Nor does naming a victim lock the victim out
+	request("192.0.2.5", "192.0.2.9", "wrong")
+	request("192.0.2.5", "192.0.2.9", "wrong")
+	if code := request("192.0.2.9", "", config.AuthToken); code != http.StatusOK {
+		t.Errorf("expected the named victim to be unaffected, got %d", code)
+	}
+
+	// Behind a trusted proxy the forwarded client is locked out, not the
+	// proxy, and a client-supplied hop before it is ignored
+	request("10.0.0.1", "203.0.113.50, 198.51.100.7", "wrong")
+	request("10.0.0.1", "203.0.113.51, 198.51.100.7", "wrong")
+	if code := request("10.0.0.1", "198.51.100.7", config.AuthToken); code != http.StatusTooManyRequests {
+		t.Errorf("expected the forwarded client to be locked out, got %d", code)
+	}
+	if code := request("10.0.0.1", "198.51.100.8", config.AuthToken); code != http.StatusOK {
+		t.Errorf("expected other clients of the proxy to be unaffected, got %d", code)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	nets, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.0.2.1 ", "::1", ""})
+	if err != nil || len(nets) != 3 {
+		t.Fatalf("got %v, %v", nets, err)
+	}
+	if !ipTrusted("192.0.2.1", nets) || ipTrusted("192.0.2.2", nets) || !ipTrusted("10.1.2.3", nets) {
+		t.Error("single IPs should match only themselves")
+	}
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("expected an error for a host name")
+	}
+}
+
+func TestHTTPTransport_TokenRoles(t *testing.T) {
+	mcpSrv := mcpserver.NewMCPServer("test-server", "1.0.0")
+	config := DefaultHTTPTransportConfig()
+	config.AuthType = "bearer"
+	config.AuthTokens = []core.BearerToken{
+		{Token: "q7Rv2LkP9xWm4ZtB"},
+		{Token: "h3Nc8YfJ5sGd1UeA", Role: "reader"},
+	}
+	config.Roles = map[string][]string{"reader": {tools.GroupRead}}
+	transport := NewHTTPTransport(mcpSrv, config, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	var allowed bool
+	handler := transport.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed = tools.ToolAllowed(r.Context(), "osm_query_bbox")
+	}))
+	for token, want := range map[string]bool{"q7Rv2LkP9xWm4ZtB": true, "h3Nc8YfJ5sGd1UeA": false} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if allowed != want {
+			t.Errorf("token %s: osm_query_bbox allowed = %v, want %v", token, allowed, want)
+		}
+	}
+}
@@ -2,10 +2,14 @@ package server
 
 import (
 	"bufio"
+	"compress/gzip"
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -166,6 +170,79 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// ParseTrustedProxies parses proxy IPs and CIDR ranges, returning the
+// valid ones and an error naming any others
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	var (
+		nets    []*net.IPNet
+		invalid []string
+	)
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		} else if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			nets = append(nets, ipNet)
+			continue
+		}
+		invalid = append(invalid, proxy)
+	}
+	if len(invalid) > 0 {
+		return nets, fmt.Errorf("invalid proxy addresses: %s", strings.Join(invalid, ", "))
+	}
+	return nets, nil
+}
+
+// clientIP returns the IP of the client sending a request for decisions a
+// forged header must not sway. X-Forwarded-For is only honored when the
+// peer is a trusted proxy, and then the last address not itself a trusted
+// proxy is the client, as proxies append to the header.
+func clientIP(r *http.Request, trusted []*net.IPNet) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if !ipTrusted(peer, trusted) {
+		return peer
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		if !ipTrusted(hop, trusted) {
+			return hop
+		}
+	}
+	return peer
+}
+
+// ipTrusted reports whether an IP lies in one of the trusted ranges
+func ipTrusted(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range trusted {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // getIP extracts the client IP from the request
 func getIP(r *http.Request) string {
 	// Check X-Forwarded-For header
@@ -367,3 +444,158 @@ func TracingMiddleware() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// gzipWriterPool reuses gzip writers, whose buffers are large
+var gzipWriterPool = sync.Pool{
+	New: func() any { return gzip.NewWriter(io.Discard) },
+}
+
+// GzipCompression returns middleware that compresses JSON and SSE responses
+// with gzip for clients accepting it. JSON responses smaller than minSize
+// are sent as is; SSE streams are compressed event by event, flushing with
+// each event so clients never wait on the compressor.
+func GzipCompression(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+			defer gw.Close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter compresses a response once its type and size are known
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+
+	status      int
+	buf         []byte // JSON held back until it reaches minSize
+	gz          *gzip.Writer
+	decided     bool // whether to compress has been decided
+	passthrough bool // the response is sent uncompressed
+}
+
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	if code < http.StatusOK {
+		g.ResponseWriter.WriteHeader(code) // informational, the real status follows
+		return
+	}
+	if g.status != 0 {
+		return
+	}
+	g.status = code
+
+	header := g.Header()
+	mediaType, _, _ := strings.Cut(header.Get("Content-Type"), ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	switch {
+	case header.Get("Content-Encoding") != "" || code == http.StatusNoContent || code == http.StatusNotModified:
+		g.startPassthrough()
+	case mediaType == "text/event-stream":
+		g.startGzip()
+	case mediaType != "application/json":
+		g.startPassthrough()
+	}
+	// JSON waits in buf until its size is known
+}
+
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	if g.status == 0 {
+		if g.Header().Get("Content-Type") == "" {
+			g.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		g.WriteHeader(http.StatusOK)
+	}
+	switch {
+	case g.passthrough:
+		return g.ResponseWriter.Write(p)
+	case g.gz != nil:
+		return g.gz.Write(p)
+	}
+
+	g.buf = append(g.buf, p...)
+	if len(g.buf) >= g.minSize {
+		g.startGzip()
+	}
+	return len(p), nil
+}
+
+// startGzip sends the headers of a compressed response and any held data
+func (g *gzipResponseWriter) startGzip() {
+	g.decided = true
+	header := g.Header()
+	header.Del("Content-Length")
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	g.ResponseWriter.WriteHeader(g.status)
+
+	g.gz = gzipWriterPool.Get().(*gzip.Writer)
+	g.gz.Reset(g.ResponseWriter)
+	if len(g.buf) > 0 {
+		g.gz.Write(g.buf)
+		g.buf = nil
+	}
+}
+
+// startPassthrough sends the headers of an uncompressed response and any
+// held data
+func (g *gzipResponseWriter) startPassthrough() {
+	g.decided = true
+	g.passthrough = true
+	g.ResponseWriter.WriteHeader(g.status)
+	if len(g.buf) > 0 {
+		g.ResponseWriter.Write(g.buf)
+		g.buf = nil
+	}
+}
+
+// Flush implements the http.Flusher interface, sending held JSON as is
+// when it is below minSize
+func (g *gzipResponseWriter) Flush() {
+	if g.status != 0 && !g.decided {
+		g.startPassthrough()
+	}
+	if g.gz != nil {
+		g.gz.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the response after the handler returns
+func (g *gzipResponseWriter) Close() {
+	if g.status != 0 && !g.decided {
+		g.startPassthrough()
+	}
+	if g.gz != nil {
+		g.gz.Close()
+		g.gz.Reset(io.Discard)
+		gzipWriterPool.Put(g.gz)
+		g.gz = nil
+	}
+}
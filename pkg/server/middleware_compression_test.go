@@ -0,0 +1,93 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipCompression(t *testing.T) {
+	large := `{"geometry":"` + strings.Repeat("abcdefgh", 512) + `"}`
+	jsonHandler := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, body)
+		})
+	}
+	serve := func(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		GzipCompression(1024)(handler).ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("LargeJSON", func(t *testing.T) {
+		w := serve(jsonHandler(large), "br, gzip")
+		if w.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected a gzipped response, got headers %v", w.Header())
+		}
+		if w.Body.Len() >= len(large) {
+			t.Errorf("expected compression, got %d bytes for %d", w.Body.Len(), len(large))
+		}
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(gz)
+		if err != nil || string(body) != large {
+			t.Errorf("decompressed body does not match: %v", err)
+		}
+	})
+
+	t.Run("SmallJSON", func(t *testing.T) {
+		w := serve(jsonHandler(`{"ok":true}`), "gzip")
+		if w.Header().Get("Content-Encoding") != "" || w.Body.String() != `{"ok":true}` {
+			t.Errorf("expected a small response uncompressed, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("NotAccepted", func(t *testing.T) {
+		for _, accept := range []string{"", "br", "gzip;q=0"} {
+			w := serve(jsonHandler(large), accept)
+			if w.Header().Get("Content-Encoding") != "" || w.Body.String() != large {
+				t.Errorf("Accept-Encoding %q: expected an uncompressed response", accept)
+			}
+		}
+	})
+
+	t.Run("SSE", func(t *testing.T) {
+		event := "event: message\ndata: {\"jsonrpc\":\"2.0\"}\n\n"
+		w := httptest.NewRecorder()
+		var flushed string
+		handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "text/event-stream")
+			rw.WriteHeader(http.StatusOK)
+			io.WriteString(rw, event)
+			rw.(http.Flusher).Flush()
+			flushed = w.Body.String()
+		})
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		GzipCompression(1024)(handler).ServeHTTP(w, req)
+		if w.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected a gzipped stream, got headers %v", w.Header())
+		}
+
+		// The event can be decoded from what was sent at the flush
+		gz, err := gzip.NewReader(strings.NewReader(flushed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, len(event))
+		if _, err := io.ReadFull(gz, got); err != nil || string(got) != event {
+			t.Errorf("expected the flushed event to decode, got %q (%v)", got, err)
+		}
+	})
+}
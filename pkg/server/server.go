@@ -43,50 +43,62 @@ type Server struct {
 
 // NewServer creates a new OpenStreetMap MCP server with all tools registered.
 func NewServer() (*Server, error) {
-	logger := slog.Default()
+	return New()
+}
+
+// newServer builds the MCP server and registers the registry's tools, their
+// documentation resources and, if enabled, the prompts.
+func newServer(o options, registry *tools.Registry) *Server {
+	logger := o.logger
 	logger.Info("initializing OpenStreetMap MCP server",
-		"name", ServerName,
-		"version", ServerVersion)
+		"name", o.name,
+		"version", o.version)
 
 	// Initialize tile resource manager
 	core.InitTileResourceManager(logger)
 
 	// Create MCP server with options
-	srv := mcpserver.NewMCPServer(
-		ServerName,
-		ServerVersion,
+	serverOptions := append([]mcpserver.ServerOption{
 		mcpserver.WithToolCapabilities(false),
+		mcpserver.WithResourceCapabilities(false, true),
 		mcpserver.WithRecovery(),
-	)
-
-	// Create tool registry and register all tools and prompts
-	registry := tools.NewRegistry(logger)
-	registry.RegisterAll(srv)
-
-	// Register the geocoding system prompt using the v0.28.0+ API
-	geocodingPrompt := mcp.NewPrompt("geocoding_system",
-		mcp.WithPromptDescription("System prompt with geocoding instructions"),
-	)
-
-	// Add the prompt with its handler function
-	srv.AddPrompt(geocodingPrompt, func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
-		return mcp.NewGetPromptResult(
-			"Geocoding System Instructions",
-			[]mcp.PromptMessage{
-				mcp.NewPromptMessage(
-					mcp.RoleAssistant,
-					mcp.NewTextContent(prompts.GeocodingSystemPrompt()),
-				),
-			},
-		), nil
-	})
+	}, o.serverOptions...)
+	serverOptions = append(serverOptions, tools.AccessControl()...)
+	srv := mcpserver.NewMCPServer(o.name, o.version, serverOptions...)
+
+	if !o.prompts {
+		registry.RegisterTools(srv)
+		registry.RegisterDocs(srv)
+		registry.RegisterSpool(srv)
+	} else {
+		// Register all tools and prompts
+		registry.RegisterAll(srv)
+
+		// Register the geocoding system prompt using the v0.28.0+ API
+		geocodingPrompt := mcp.NewPrompt("geocoding_system",
+			mcp.WithPromptDescription("System prompt with geocoding instructions"),
+		)
+
+		// Add the prompt with its handler function
+		srv.AddPrompt(geocodingPrompt, func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult(
+				"Geocoding System Instructions",
+				[]mcp.PromptMessage{
+					mcp.NewPromptMessage(
+						mcp.RoleAssistant,
+						mcp.NewTextContent(prompts.GeocodingSystemPrompt()),
+					),
+				},
+			), nil
+		})
+	}
 
 	return &Server{
 		srv:    srv,
 		logger: logger,
 		stopCh: make(chan struct{}),
 		doneCh: make(chan struct{}),
-	}, nil
+	}
 }
 
 // Run starts the MCP server using stdin/stdout for communication.
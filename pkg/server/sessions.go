@@ -0,0 +1,332 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/monitoring"
+)
+
+// sessionIDPrefix matches the session IDs of the mcp-go default manager
+const sessionIDPrefix = "mcp-session-"
+
+// sessionState is the outcome of checking a request's session
+type sessionState int
+
+const (
+	sessionValid   sessionState = iota
+	sessionUnknown              // never issued, expired or terminated
+	sessionForeign              // issued to another identity
+)
+
+// httpSession is a streamable HTTP session issued by the transport
+type httpSession struct {
+	identity string
+	bound    bool
+	lastSeen time.Time
+	streams  int // open GET streams, which keep the session alive
+}
+
+// sessionManager issues streamable HTTP session IDs, binds each session to
+// the identity that created it, and expires sessions left idle, since
+// clients often disappear without a DELETE. It implements
+// mcpserver.SessionIdManager.
+type sessionManager struct {
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+	idleTTL  time.Duration
+	now      func() time.Time
+
+	// onChange is called with the number of sessions after it changes
+	onChange func(active int)
+}
+
+// newSessionManager creates a manager expiring sessions idle for idleTTL,
+// or never if idleTTL is not positive
+func newSessionManager(idleTTL time.Duration) *sessionManager {
+	return &sessionManager{
+		sessions: make(map[string]*httpSession),
+		idleTTL:  idleTTL,
+		now:      time.Now,
+	}
+}
+
+// Generate issues a new session ID with 128 random bits
+func (m *sessionManager) Generate() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	id := sessionIDPrefix + hex.EncodeToString(b)
+
+	m.mu.Lock()
+	m.sessions[id] = &httpSession{lastSeen: m.now()}
+	count := len(m.sessions)
+	m.mu.Unlock()
+	m.changed(count)
+	return id
+}
+
+// Validate reports unknown and expired sessions as terminated, so clients
+// get 404 and start a new session
+func (m *sessionManager) Validate(sessionID string) (isTerminated bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return true, nil
+	}
+	session.lastSeen = m.now()
+	return false, nil
+}
+
+// Terminate ends a session
+func (m *sessionManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	m.mu.Lock()
+	_, ok := m.sessions[sessionID]
+	delete(m.sessions, sessionID)
+	count := len(m.sessions)
+	m.mu.Unlock()
+
+	if ok {
+		m.changed(count)
+	}
+	return false, nil
+}
+
+// bind records the identity that created a session
+func (m *sessionManager) bind(sessionID, identity string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if session, ok := m.sessions[sessionID]; ok && !session.bound {
+		session.identity = identity
+		session.bound = true
+	}
+}
+
+// check reports whether identity may use a session
+func (m *sessionManager) check(sessionID, identity string) sessionState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[sessionID]
+	switch {
+	case !ok:
+		return sessionUnknown
+	case !session.bound || session.identity != identity:
+		return sessionForeign
+	}
+	return sessionValid
+}
+
+// stream marks a GET stream open on a session until the returned function
+// is called
+func (m *sessionManager) stream(sessionID string) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return func() {}
+	}
+	session.streams++
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		session.streams--
+		session.lastSeen = m.now()
+	}
+}
+
+// expire removes sessions idle for longer than the TTL without an open
+// stream and returns their IDs
+func (m *sessionManager) expire() []string {
+	if m.idleTTL <= 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	var expired []string
+	cutoff := m.now().Add(-m.idleTTL)
+	for id, session := range m.sessions {
+		if session.streams == 0 && session.lastSeen.Before(cutoff) {
+			expired = append(expired, id)
+			delete(m.sessions, id)
+		}
+	}
+	count := len(m.sessions)
+	m.mu.Unlock()
+
+	if len(expired) > 0 {
+		m.changed(count)
+	}
+	return expired
+}
+
+// count returns the number of sessions
+func (m *sessionManager) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// changed reports the number of sessions
+func (m *sessionManager) changed(count int) {
+	if m.onChange != nil {
+		m.onChange(count)
+	}
+}
+
+// identityKey is the context key for the authenticated identity
+type identityKey struct{}
+
+// withIdentity records the authenticated identity of a request
+func withIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// requestIdentity returns the authenticated identity of a request, or ""
+// when authentication is disabled
+func requestIdentity(r *http.Request) string {
+	identity, _ := r.Context().Value(identityKey{}).(string)
+	return identity
+}
+
+// credentialIdentity derives a stable identity from a credential without
+// keeping the credential itself
+func credentialIdentity(authType, credential string) string {
+	sum := sha256.Sum256([]byte(authType + ":" + credential))
+	return hex.EncodeToString(sum[:16])
+}
+
+// sessionMiddleware refuses requests for sessions that are unknown or
+// belong to another identity, and binds new sessions to the identity that
+// initialized them. Sessions of another identity are reported as not found
+// rather than forbidden, so a client whose key was rotated starts a new
+// session instead of failing until it restarts.
+func (t *HTTPTransport) sessionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := requestIdentity(r)
+		sessionID := r.Header.Get(mcpserver.HeaderKeySessionID)
+
+		if sessionID == "" {
+			if r.Method == http.MethodPost {
+				w = &sessionBinder{ResponseWriter: w, bind: func(id string) { t.sessions.bind(id, identity) }}
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch t.sessions.check(sessionID, identity) {
+		case sessionUnknown:
+			// Also answers replayed DELETEs for ended sessions
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		case sessionForeign:
+			t.logger.Warn("request for a session of another credential",
+				"remote_addr", r.RemoteAddr,
+				"method", r.Method)
+			monitoring.RecordError("http_transport", "session_identity_mismatch")
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			defer t.sessions.stream(sessionID)()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// expireSessions ends idle sessions every interval until ctx is done
+func (t *HTTPTransport) expireSessions(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.expireIdleSessions()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// expireIdleSessions ends sessions left idle, releasing the state mcp-go
+// keeps for them as a DELETE would
+func (t *HTTPTransport) expireIdleSessions() {
+	for _, id := range t.sessions.expire() {
+		t.logger.Debug("expired idle session", "session_id", id)
+		monitoring.RecordOrphanedSession()
+
+		req, err := http.NewRequest(http.MethodDelete, t.config.MCPEndpoint, nil)
+		if err != nil {
+			continue
+		}
+		req.Header.Set(mcpserver.HeaderKeySessionID, id)
+		t.streamableServer.ServeHTTP(discardResponseWriter{header: http.Header{}}, req)
+	}
+}
+
+// updateSessionCount publishes the number of sessions
+func (t *HTTPTransport) updateSessionCount(count int) {
+	monitoring.UpdateActiveConnections("http", "session", count)
+
+	t.mu.RLock()
+	hc := t.healthChecker
+	t.mu.RUnlock()
+	if hc != nil {
+		hc.UpdateTransportSessions(count)
+	}
+}
+
+// sessionBinder captures the session ID the server issues in response to
+// an initialize request
+type sessionBinder struct {
+	http.ResponseWriter
+	bind  func(sessionID string)
+	bound bool
+}
+
+func (b *sessionBinder) WriteHeader(code int) {
+	if !b.bound {
+		b.bound = true
+		if id := b.Header().Get(mcpserver.HeaderKeySessionID); id != "" {
+			b.bind(id)
+		}
+	}
+	b.ResponseWriter.WriteHeader(code)
+}
+
+func (b *sessionBinder) Write(p []byte) (int, error) {
+	if !b.bound {
+		b.WriteHeader(http.StatusOK)
+	}
+	return b.ResponseWriter.Write(p)
+}
+
+// Flush implements the http.Flusher interface
+func (b *sessionBinder) Flush() {
+	if f, ok := b.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// discardResponseWriter drops the response to an internal request
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d discardResponseWriter) Header() http.Header         { return d.header }
+func (d discardResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (d discardResponseWriter) WriteHeader(int)             {}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+
+func newSessionTestTransport(t *testing.T) *HTTPTransport {
+	t.Helper()
+	mcpSrv := mcpserver.NewMCPServer("test-server", "1.0.0")
+	config := DefaultHTTPTransportConfig()
+	config.AuthType = "bearer"
+	config.AuthTokens = []core.BearerToken{{Token: "q7Rv2LkP9xWm4ZtB"}, {Token: "h3Nc8YfJ5sGd1UeA"}}
+	config.SessionIdleTTL = time.Minute
+	return NewHTTPTransport(mcpSrv, config, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+}
+
+// sessionRequest sends a request to the MCP endpoint of transport
+func sessionRequest(transport *HTTPTransport, method, token, sessionID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(mcpserver.HeaderKeySessionID, sessionID)
+	}
+	w := httptest.NewRecorder()
+	transport.mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestSessions_BoundToCredential(t *testing.T) {
+	transport := newSessionTestTransport(t)
+
+	w := sessionRequest(transport, http.MethodPost, "q7Rv2LkP9xWm4ZtB", "", initializeRequest)
+	sessionID := w.Header().Get(mcpserver.HeaderKeySessionID)
+	if w.Code != http.StatusOK || sessionID == "" {
+		t.Fatalf("initialize failed: %d %s", w.Code, w.Body.String())
+	}
+
+	// Another credential, such as a rotated key, is told the session does
+	// not exist, so the client initializes a new one
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+	if w := sessionRequest(transport, http.MethodPost, "h3Nc8YfJ5sGd1UeA", sessionID, ping); w.Code != http.StatusNotFound {
+		t.Errorf("expected another credential to get 404, got %d", w.Code)
+	}
+	if w := sessionRequest(transport, http.MethodDelete, "h3Nc8YfJ5sGd1UeA", sessionID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected another credential to be refused DELETE, got %d", w.Code)
+	}
+	if w := sessionRequest(transport, http.MethodPost, "q7Rv2LkP9xWm4ZtB", sessionID, ping); w.Code != http.StatusOK {
+		t.Errorf("expected the owner to use the session, got %d", w.Code)
+	}
+
+	if w := sessionRequest(transport, http.MethodDelete, "q7Rv2LkP9xWm4ZtB", sessionID, ""); w.Code != http.StatusOK {
+		t.Errorf("expected DELETE to end the session, got %d", w.Code)
+	}
+	if w := sessionRequest(transport, http.MethodDelete, "q7Rv2LkP9xWm4ZtB", sessionID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected a replayed DELETE to get 404, got %d", w.Code)
+	}
+	if transport.sessions.count() != 0 {
+		t.Errorf("expected no sessions, got %d", transport.sessions.count())
+	}
+}
+
+func TestSessions_IdleExpiry(t *testing.T) {
+	transport := newSessionTestTransport(t)
+	now := time.Now()
+	transport.sessions.now = func() time.Time { return now }
+
+	idle := sessionRequest(transport, http.MethodPost, "q7Rv2LkP9xWm4ZtB", "", initializeRequest).Header().Get(mcpserver.HeaderKeySessionID)
+	streaming := sessionRequest(transport, http.MethodPost, "q7Rv2LkP9xWm4ZtB", "", initializeRequest).Header().Get(mcpserver.HeaderKeySessionID)
+	if idle == "" || streaming == "" {
+		t.Fatal("initialize did not return session IDs")
+	}
+	done := transport.sessions.stream(streaming)
+
+	now = now.Add(2 * time.Minute)
+	transport.expireIdleSessions()
+	if transport.sessions.count() != 1 {
+		t.Fatalf("expected only the streaming session to remain, got %d sessions", transport.sessions.count())
+	}
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+	if w := sessionRequest(transport, http.MethodPost, "q7Rv2LkP9xWm4ZtB", idle, ping); w.Code != http.StatusNotFound {
+		t.Errorf("expected an expired session to get 404, got %d", w.Code)
+	}
+
+	done()
+	now = now.Add(2 * time.Minute)
+	transport.expireIdleSessions()
+	if transport.sessions.count() != 0 {
+		t.Errorf("expected the session to expire once its stream closed, got %d sessions", transport.sessions.count())
+	}
+}
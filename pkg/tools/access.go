@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// Tool groups for access control. Credentials are granted groups rather
+// than individual tools.
+const (
+	// GroupRead holds lookups, geometry and single routes: every tool not
+	// in another group
+	GroupRead = "read"
+	// GroupExpensive holds tools that extract data in bulk or make many
+	// upstream requests per call
+	GroupExpensive = "expensive"
+	// GroupAdmin holds tools that change state shared by all clients
+	GroupAdmin = "admin"
+)
+
+// ToolGroups lists the tool groups
+var ToolGroups = []string{GroupRead, GroupExpensive, GroupAdmin}
+
+// toolGroups assigns tools outside GroupRead to their group
+var toolGroups = map[string]string{
+	"osm_query_bbox":            GroupExpensive,
+	"explore_area":              GroupExpensive,
+	"analyze_neighborhood":      GroupExpensive,
+	"partition_territory":       GroupExpensive,
+	"search_isochrone_boundary": GroupExpensive,
+	"get_isochrone":             GroupExpensive,
+	"route_matrix":              GroupExpensive,
+	"optimize_stops":            GroupExpensive,
+	"reverse_geocode_track":     GroupExpensive,
+	"reverse_geocode_batch":     GroupExpensive,
+	"terrain_risk_screen":       GroupExpensive,
+	"watch_area":                GroupExpensive,
+	"unwatch_area":              GroupExpensive,
+	"get_osm_geometry":          GroupExpensive,
+	"search_places_by_name":     GroupExpensive,
+	"hydrate_elements":          GroupExpensive,
+
+	"report_closure": GroupAdmin,
+	"tile_cache":     GroupAdmin,
+}
+
+// ToolGroup returns the access control group of a tool. Pinned versions
+// such as name@v1 share the group of the tool.
+func ToolGroup(name string) string {
+	base, _ := splitToolVersion(name)
+	if group, ok := toolGroups[base]; ok {
+		return group
+	}
+	return GroupRead
+}
+
+// ParseRoles parses role definitions granting tool groups, separated by
+// semicolons, where * grants every group:
+//
+//	reader=read;analyst=read,expensive;operator=*
+func ParseRoles(spec string) (map[string][]string, error) {
+	roles := make(map[string][]string)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		role, list, ok := strings.Cut(entry, "=")
+		role = strings.TrimSpace(role)
+		if !ok || role == "" {
+			return nil, fmt.Errorf("role definition %q must be role=group,group", entry)
+		}
+
+		var groups []string
+		for _, group := range strings.Split(list, ",") {
+			group = strings.TrimSpace(group)
+			switch {
+			case group == "*":
+				groups = append(groups, ToolGroups...)
+			case group == GroupRead || group == GroupExpensive || group == GroupAdmin:
+				groups = append(groups, group)
+			case group != "":
+				return nil, fmt.Errorf("unknown tool group %q for role %s, use %s or *", group, role, strings.Join(ToolGroups, ", "))
+			}
+		}
+		roles[role] = groups
+	}
+	return roles, nil
+}
+
+// accessKey is the context key for granted tool groups
+type accessKey struct{}
+
+// WithAllowedGroups limits the tools callable with ctx to the given
+// groups. Without it, as over stdio, every tool is allowed.
+func WithAllowedGroups(ctx context.Context, groups []string) context.Context {
+	allowed := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		allowed[group] = true
+	}
+	return context.WithValue(ctx, accessKey{}, allowed)
+}
+
+// ToolAllowed reports whether the tool may be called with ctx
+func ToolAllowed(ctx context.Context, name string) bool {
+	allowed, ok := ctx.Value(accessKey{}).(map[string]bool)
+	return !ok || allowed[ToolGroup(name)]
+}
+
+// AccessControl returns the server options enforcing the groups granted
+// with WithAllowedGroups: tools outside them are left out of tools/list
+// and their calls are refused.
+func AccessControl() []server.ServerOption {
+	return []server.ServerOption{
+		server.WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+			filtered := make([]mcp.Tool, 0, len(tools))
+			for _, tool := range tools {
+				if ToolAllowed(ctx, tool.Name) {
+					filtered = append(filtered, tool)
+				}
+			}
+			return filtered
+		}),
+		server.WithToolHandlerMiddleware(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				if !ToolAllowed(ctx, req.Params.Name) {
+					return core.NewError(core.ErrPermissionDenied,
+						fmt.Sprintf("Tool %s is in the %s group, which this credential is not granted", req.Params.Name, ToolGroup(req.Params.Name))).
+						WithGuidance("Use tools/list to see the tools this credential may call, or ask the operator for a credential with more access").
+						ToMCPResult(), nil
+				}
+				return next(ctx, req)
+			}
+		}),
+	}
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestToolGroupsNameRegisteredTools(t *testing.T) {
+	names := make(map[string]bool)
+	for _, name := range NewRegistry(slog.New(slog.NewTextHandler(io.Discard, nil))).GetToolNames() {
+		names[name] = true
+	}
+	for name := range toolGroups {
+		if !names[name] {
+			t.Errorf("tool group assigned to unknown tool %s", name)
+		}
+	}
+
+	if ToolGroup("osm_query_bbox") != GroupExpensive || ToolGroup("osm_query_bbox@v1") != GroupExpensive {
+		t.Error("expected osm_query_bbox and its pinned versions to be expensive")
+	}
+	if ToolGroup("geo_distance") != GroupRead {
+		t.Error("expected tools without a group to be read")
+	}
+}
+
+func TestParseRoles(t *testing.T) {
+	roles, err := ParseRoles(" reader=read ; analyst=read,expensive;operator=*;none=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(roles["reader"], ",") != "read" || strings.Join(roles["analyst"], ",") != "read,expensive" ||
+		strings.Join(roles["operator"], ",") != "read,expensive,admin" || len(roles["none"]) != 0 {
+		t.Errorf("unexpected roles %v", roles)
+	}
+
+	for _, spec := range []string{"reader", "=read", "reader=write"} {
+		if _, err := ParseRoles(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestAccessControl(t *testing.T) {
+	registry := NewRegistry(slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithFilter(func(name string) bool { return name == "geo_distance" || name == "osm_query_bbox" })
+	srv := server.NewMCPServer("test", "1.0.0", AccessControl()...)
+	registry.RegisterTools(srv)
+
+	call := func(ctx context.Context, message string) string {
+		data, err := json.Marshal(srv.HandleMessage(ctx, json.RawMessage(message)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	list := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+	query := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"osm_query_bbox","arguments":{}}}`
+
+	// Without granted groups, as over stdio, every tool is available
+	if got := call(context.Background(), list); !strings.Contains(got, `"osm_query_bbox"`) {
+		t.Errorf("expected osm_query_bbox to be listed, got %s", got)
+	}
+
+	reader := WithAllowedGroups(context.Background(), []string{GroupRead})
+	got := call(reader, list)
+	if strings.Contains(got, `"osm_query_bbox"`) || !strings.Contains(got, `"geo_distance"`) {
+		t.Errorf("expected only read tools to be listed, got %s", got)
+	}
+	if got := call(reader, query); !strings.Contains(got, "PERMISSION_DENIED") {
+		t.Errorf("expected the call to be refused, got %s", got)
+	}
+
+	analyst := WithAllowedGroups(context.Background(), []string{GroupRead, GroupExpensive})
+	if got := call(analyst, query); strings.Contains(got, "PERMISSION_DENIED") {
+		t.Errorf("expected the call to be allowed, got %s", got)
+	}
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+const (
+	// defaultAggregatePrecision is the default geohash length (~1.2km x 0.6km cells)
+	defaultAggregatePrecision = 6
+
+	// maxAggregatePoints caps how many points can be aggregated per call
+	maxAggregatePoints = 10000
+)
+
+// AggregatePointsInput defines the input parameters for aggregate_points
+type AggregatePointsInput struct {
+	Points    []geo.Location `json:"points"`
+	Scheme    string         `json:"scheme,omitempty"`
+	Precision int            `json:"precision,omitempty"`
+	MinCount  int            `json:"min_count,omitempty"`
+}
+
+// AggregateCell is a grid cell with the number of points inside it
+type AggregateCell struct {
+	Cell   string          `json:"cell"`
+	Count  int             `json:"count"`
+	Center geo.Location    `json:"center"`
+	BBox   geo.BoundingBox `json:"bbox"`
+}
+
+// AggregatePointsOutput defines the output for aggregate_points
+type AggregatePointsOutput struct {
+	Scheme           string          `json:"scheme"`
+	Precision        int             `json:"precision"`
+	Cells            []AggregateCell `json:"cells"`
+	TotalPoints      int             `json:"total_points"`
+	SuppressedPoints int             `json:"suppressed_points"` // in cells below min_count
+	SuppressedCells  int             `json:"suppressed_cells"`
+}
+
+// AggregatePointsTool returns a tool definition for binning points into grid cells
+func AggregatePointsTool() mcp.Tool {
+	return mcp.NewTool("aggregate_points",
+		mcp.WithDescription("Bin points into geohash grid cells with counts, for sharing aggregate location data without exposing individual coordinates. Cells with fewer than min_count points are suppressed"),
+		mcp.WithArray("points",
+			mcp.Required(),
+			mcp.Description("Array of {latitude, longitude} points to aggregate (max 10000)"),
+		),
+		mcp.WithString("scheme",
+			mcp.Description("Grid scheme; only 'geohash' is supported"),
+			mcp.DefaultString("geohash"),
+		),
+		mcp.WithNumber("precision",
+			mcp.Description("Geohash length 1-12; 5 is ~4.9km cells, 6 is ~1.2km, 7 is ~150m"),
+			mcp.DefaultNumber(defaultAggregatePrecision),
+		),
+		mcp.WithNumber("min_count",
+			mcp.Description("Suppress cells containing fewer points than this (k-anonymity threshold)"),
+			mcp.DefaultNumber(1),
+		),
+	)
+}
+
+// HandleAggregatePoints bins points into grid cells
+func HandleAggregatePoints(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "aggregate_points")
+
+	// Parse input
+	var input AggregatePointsInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if len(input.Points) == 0 {
+		return core.NewError(core.ErrEmptyParameter, "At least one point is required").ToMCPResult(), nil
+	}
+	if len(input.Points) > maxAggregatePoints {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("At most %d points can be aggregated per call", maxAggregatePoints)).ToMCPResult(), nil
+	}
+
+	input.Scheme = strings.ToLower(strings.TrimSpace(input.Scheme))
+	if input.Scheme == "" {
+		input.Scheme = "geohash"
+	}
+	if input.Scheme != "geohash" {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Unsupported scheme: %s", input.Scheme)).
+			WithGuidance("Use scheme 'geohash'; H3 indexing is not available in this server").
+			ToMCPResult(), nil
+	}
+
+	if input.Precision == 0 {
+		input.Precision = defaultAggregatePrecision
+	}
+	if input.Precision < 1 || input.Precision > geo.MaxGeohashPrecision {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Precision must be between 1 and %d", geo.MaxGeohashPrecision)).ToMCPResult(), nil
+	}
+	if input.MinCount < 1 {
+		input.MinCount = 1
+	}
+
+	for i, p := range input.Points {
+		if err := core.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid coordinates at index %d: %s", i, err)).ToMCPResult(), nil
+		}
+	}
+
+	output, err := aggregateGeohash(input.Points, input.Precision, input.MinCount)
+	if err != nil {
+		logger.Error("failed to aggregate points", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to aggregate points").ToMCPResult(), nil
+	}
+
+	logger.Info("aggregated points", "points", output.TotalPoints, "cells", len(output.Cells), "suppressed_cells", output.SuppressedCells)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// aggregateGeohash counts points per geohash cell, dropping cells below minCount.
+// Cells are ordered by count, busiest first, then by cell ID.
+func aggregateGeohash(points []geo.Location, precision, minCount int) (*AggregatePointsOutput, error) {
+	counts := make(map[string]int)
+	for _, p := range points {
+		hash, err := geo.EncodeGeohash(p.Latitude, p.Longitude, precision)
+		if err != nil {
+			return nil, err
+		}
+		counts[hash]++
+	}
+
+	output := &AggregatePointsOutput{
+		Scheme:      "geohash",
+		Precision:   precision,
+		Cells:       make([]AggregateCell, 0, len(counts)),
+		TotalPoints: len(points),
+	}
+
+	for hash, count := range counts {
+		if count < minCount {
+			output.SuppressedCells++
+			output.SuppressedPoints += count
+			continue
+		}
+		bbox, err := geo.DecodeGeohash(hash)
+		if err != nil {
+			return nil, err
+		}
+		output.Cells = append(output.Cells, AggregateCell{
+			Cell:   hash,
+			Count:  count,
+			Center: bbox.Center(),
+			BBox:   bbox,
+		})
+	}
+
+	sort.Slice(output.Cells, func(i, j int) bool {
+		if output.Cells[i].Count != output.Cells[j].Count {
+			return output.Cells[i].Count > output.Cells[j].Count
+		}
+		return output.Cells[i].Cell < output.Cells[j].Cell
+	})
+
+	return output, nil
+}
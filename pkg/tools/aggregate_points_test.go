@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestAggregateGeohash(t *testing.T) {
+	points := []geo.Location{
+		{Latitude: 37.7749, Longitude: -122.4194},
+		{Latitude: 37.7750, Longitude: -122.4195},
+		{Latitude: 37.7751, Longitude: -122.4193},
+		{Latitude: 40.7128, Longitude: -74.0060},
+	}
+
+	out, err := aggregateGeohash(points, 6, 2)
+	if err != nil {
+		t.Fatalf("aggregateGeohash() error: %v", err)
+	}
+
+	if out.TotalPoints != 4 || out.SuppressedPoints != 1 || out.SuppressedCells != 1 {
+		t.Errorf("unexpected totals: %+v", out)
+	}
+	if len(out.Cells) != 1 || out.Cells[0].Cell != "9q8yyk" || out.Cells[0].Count != 3 {
+		t.Fatalf("unexpected cells: %+v", out.Cells)
+	}
+
+	cell := out.Cells[0]
+	if cell.Center.Latitude < cell.BBox.MinLat || cell.Center.Latitude > cell.BBox.MaxLat {
+		t.Errorf("cell center outside cell bbox: %+v", cell)
+	}
+}
+
+func TestHandleAggregatePointsValidation(t *testing.T) {
+	point := map[string]any{"latitude": 37.7749, "longitude": -122.4194}
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+	}{
+		{"No points", map[string]any{"points": []any{}}},
+		{"Unsupported scheme", map[string]any{"points": []any{point}, "scheme": "s2"}},
+		{"Precision too high", map[string]any{"points": []any{point}, "precision": 13.0}},
+		{"Invalid point", map[string]any{"points": []any{map[string]any{"latitude": 91.0, "longitude": 0.0}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "aggregate_points",
+					Arguments: tt.arguments,
+				},
+			}
+
+			result, err := HandleAggregatePoints(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
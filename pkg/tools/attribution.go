@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// Attribution is the source and license notice attached to JSON results,
+// which products displaying them must show
+type Attribution struct {
+	Notice     string `json:"notice"`
+	License    string `json:"license"`
+	LicenseURL string `json:"license_url"`
+	// Sources lists the upstream services queried for this call; results
+	// served from cache list none
+	Sources []string `json:"sources,omitempty"`
+	// DataTimestamp is when the OSM data Overpass returned was last
+	// updated, the oldest if several queries were made
+	DataTimestamp  string `json:"data_timestamp,omitempty"`
+	DataAgeSeconds int64  `json:"data_age_seconds,omitempty"`
+	// Warning is set when the data is older than the staleness threshold
+	Warning string `json:"warning,omitempty"`
+}
+
+// DefaultStaleDataThreshold is the data age past which results warn that
+// recent map edits may be missing. Public Overpass instances normally lag
+// the OSM database by minutes.
+const DefaultStaleDataThreshold = time.Hour
+
+var (
+	staleDataThreshold = DefaultStaleDataThreshold
+
+	// wallClock measures data age; the simulated clock does not apply, as
+	// data timestamps are real
+	wallClock = time.Now
+)
+
+// SetStaleDataThreshold sets the data age past which results carry a
+// warning; zero disables the warning
+func SetStaleDataThreshold(threshold time.Duration) {
+	staleDataThreshold = threshold
+}
+
+// withAttribution wraps every handler to add an attribution field to
+// results that are JSON objects. It runs after transforms, which may
+// reshape results into values that cannot carry it, and before tabular
+// output, which drops it.
+func withAttribution(defs []ToolDefinition) []ToolDefinition {
+	for i, def := range defs {
+		defs[i].Handler = attributionHandler(def.Handler)
+	}
+	return defs
+}
+
+// attributionHandler wraps a handler to record the services it queries and
+// attach the attribution to its result
+func attributionHandler(handler func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, sources := osm.WithDataSources(ctx)
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+		return attachAttribution(result, newAttribution(sources.Services(), sources.Timestamp())), nil
+	}
+}
+
+// newAttribution builds the attribution for the services a call queried
+// and the timestamp of their data, if known
+func newAttribution(services []string, timestamp time.Time) Attribution {
+	attribution := Attribution{
+		Notice:     osm.AttributionNotice,
+		License:    osm.AttributionLicense,
+		LicenseURL: osm.AttributionLicenseURL,
+		Sources:    services,
+	}
+	if timestamp.IsZero() {
+		return attribution
+	}
+
+	age := max(wallClock().Sub(timestamp), 0)
+	attribution.DataTimestamp = timestamp.UTC().Format(time.RFC3339)
+	attribution.DataAgeSeconds = int64(age.Seconds())
+	if staleDataThreshold > 0 && age > staleDataThreshold {
+		attribution.Warning = fmt.Sprintf("OSM data is %s old (last updated %s), so recent map edits may be missing",
+			age.Truncate(time.Minute), attribution.DataTimestamp)
+	}
+	return attribution
+}
+
+// attachAttribution appends an attribution field to a JSON object result,
+// keeping the order of its other fields. Results that are not single JSON
+// objects, or that already carry an attribution, are returned unchanged.
+func attachAttribution(result *mcp.CallToolResult, attribution Attribution) *mcp.CallToolResult {
+	if len(result.Content) != 1 {
+		return result
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return result
+	}
+
+	body := bytes.TrimSpace([]byte(text.Text))
+	var fields map[string]json.RawMessage
+	if len(body) == 0 || body[0] != '{' || json.Unmarshal(body, &fields) != nil {
+		return result
+	}
+	if _, exists := fields["attribution"]; exists {
+		return result
+	}
+
+	data, err := json.Marshal(attribution)
+	if err != nil {
+		return result
+	}
+	var out bytes.Buffer
+	out.Write(body[:len(body)-1])
+	if len(fields) > 0 {
+		out.WriteByte(',')
+	}
+	out.WriteString(`"attribution":`)
+	out.Write(data)
+	out.WriteByte('}')
+
+	attributed := *result
+	attributed.Content = []mcp.Content{mcp.NewTextContent(out.String())}
+	return &attributed
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestAttachAttribution(t *testing.T) {
+	attribution := newAttribution(nil, time.Time{})
+
+	tests := []struct {
+		name string
+		in   string
+		want string // prefix of the result, or the whole result if unchanged
+		same bool
+	}{
+		{"object keeps field order", `{"z":1,"a":2}`, `{"z":1,"a":2,"attribution":{`, false},
+		{"empty object", `{}`, `{"attribution":{`, false},
+		{"array", `[1,2]`, `[1,2]`, true},
+		{"existing attribution", `{"attribution":"© OpenStreetMap"}`, `{"attribution":"© OpenStreetMap"}`, true},
+		{"not JSON", `Route: 5 km`, `Route: 5 km`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := attachAttribution(mcp.NewToolResultText(tt.in), attribution).Content[0].(mcp.TextContent).Text
+			if tt.same && got != tt.want || !strings.HasPrefix(got, tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributionHandler(t *testing.T) {
+	handler := attributionHandler(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"distance":12.5}`), nil
+	})
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Distance    float64     `json:"distance"`
+		Attribution Attribution `json:"attribution"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Distance != 12.5 || out.Attribution.License != osm.AttributionLicense || out.Attribution.Notice != osm.AttributionNotice {
+		t.Errorf("result = %+v", out)
+	}
+	if len(out.Attribution.Sources) != 0 || out.Attribution.DataTimestamp != "" {
+		t.Errorf("a call without upstream requests has sources %v, timestamp %q", out.Attribution.Sources, out.Attribution.DataTimestamp)
+	}
+}
+
+func TestAttributionDataAge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	oldClock := wallClock
+	wallClock = func() time.Time { return now }
+	defer func() { wallClock = oldClock }()
+
+	fresh := newAttribution([]string{"overpass"}, now.Add(-5*time.Minute))
+	if fresh.DataTimestamp != "2024-05-01T11:55:00Z" || fresh.DataAgeSeconds != 300 || fresh.Warning != "" {
+		t.Errorf("fresh data = %+v", fresh)
+	}
+
+	stale := newAttribution([]string{"overpass"}, now.Add(-3*time.Hour))
+	if stale.DataAgeSeconds != 3*3600 || !strings.Contains(stale.Warning, "3h0m0s old") {
+		t.Errorf("stale data = %+v", stale)
+	}
+
+	SetStaleDataThreshold(0)
+	defer SetStaleDataThreshold(DefaultStaleDataThreshold)
+	if disabled := newAttribution([]string{"overpass"}, now.Add(-3*time.Hour)); disabled.Warning != "" {
+		t.Errorf("warning with the threshold disabled: %s", disabled.Warning)
+	}
+}
+
+func TestAttributionTimestampOnCacheHit(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"osm3s":{"timestamp_osm_base":"2024-05-01T10:00:00Z"},"elements":[{"type":"node","id":1,"lat":51.5,"lon":-0.1}]}`)
+	}))
+	defer srv.Close()
+	if err := osm.SetServiceURLs(osm.ServiceURLs{Overpass: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { osm.SetServiceURLs(osm.ServiceURLs{Overpass: osm.DefaultOverpassBaseURL}) })
+
+	handler := attributionHandler(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := executeOverpassQuery(ctx, "[out:json];node(1);out;// attribution cache test"); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(`{"count":1}`), nil
+	})
+
+	// The second call is served from cache and keeps the data timestamp
+	for call := 1; call <= 2; call++ {
+		result, err := handler(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Attribution Attribution `json:"attribution"`
+		}
+		if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.Attribution.DataTimestamp != "2024-05-01T10:00:00Z" {
+			t.Errorf("call %d: data_timestamp = %q", call, out.Attribution.DataTimestamp)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the second call to be served from cache, got %d requests", requests)
+	}
+}
@@ -0,0 +1,231 @@
+package tools
+
+import (
+	"strings"
+)
+
+// categoryMapping maps the names a user may give a category to the OSM
+// tags of its places
+type categoryMapping struct {
+	names []string            // canonical name first, then plurals and synonyms
+	tags  map[string][]string // tag key to values, "*" matching any value
+}
+
+// categoryTable lists the categories with curated tags. Names are
+// normalized: lower case with words separated by single spaces.
+var categoryTable = []categoryMapping{
+	{
+		names: []string{"restaurant", "restaurants", "dining", "food", "eatery"},
+		tags:  map[string][]string{"amenity": {"restaurant", "cafe", "fast_food", "bar", "pub", "food_court"}},
+	},
+	{
+		names: []string{"park", "parks", "recreation"},
+		tags: map[string][]string{
+			"leisure": {"park", "garden", "playground", "nature_reserve"},
+			"landuse": {"recreation_ground", "park", "greenfield"},
+			"natural": {"wood", "grassland", "meadow"},
+		},
+	},
+	{
+		names: []string{"hotel", "hotels", "lodging", "accommodation", "motel", "hostel"},
+		tags:  map[string][]string{"tourism": {"hotel", "hostel", "guest_house", "motel", "apartment", "resort"}},
+	},
+	{
+		names: []string{"school", "schools", "education"},
+		tags:  map[string][]string{"amenity": {"school", "kindergarten", "university", "college", "language_school", "music_school", "driving_school"}},
+	},
+	{
+		names: []string{"bank", "banks", "atm", "atms", "finance", "cash machine"},
+		tags:  map[string][]string{"amenity": {"bank", "atm", "bureau_de_change", "money_transfer"}},
+	},
+	{
+		names: []string{"shop", "shops", "store", "stores", "shopping"},
+		tags:  map[string][]string{"shop": {"*"}},
+	},
+	{
+		names: []string{"cafe", "cafes", "coffee", "tea", "coffee shop", "café"},
+		tags: map[string][]string{
+			"amenity": {"cafe", "ice_cream"},
+			"shop":    {"coffee", "tea"},
+		},
+	},
+	{
+		names: []string{"hospital", "hospitals", "medical", "healthcare", "clinic"},
+		tags:  map[string][]string{"amenity": {"hospital", "clinic", "doctors", "dentist", "pharmacy", "healthcare"}},
+	},
+	{
+		names: []string{"pharmacy", "pharmacies", "drugstore", "chemist", "apothecary"},
+		tags: map[string][]string{
+			"amenity": {"pharmacy"},
+			"shop":    {"chemist", "drugstore", "medical_supply"},
+		},
+	},
+	{
+		names: []string{"supermarket", "grocery", "market", "food shop", "groceries", "grocery store"},
+		tags:  map[string][]string{"shop": {"supermarket", "convenience", "grocery", "greengrocer", "butcher", "bakery", "deli"}},
+	},
+	{
+		names: []string{"museum", "museums", "gallery", "galleries", "art"},
+		tags: map[string][]string{
+			"tourism": {"museum", "gallery", "artwork"},
+			"amenity": {"arts_centre"},
+		},
+	},
+	{
+		names: []string{"attraction", "attractions", "tourist", "tourism", "sightseeing"},
+		tags:  map[string][]string{"tourism": {"attraction", "viewpoint", "information", "museum", "gallery", "theme_park", "zoo"}},
+	},
+	{
+		names: []string{"transport", "transportation", "transit", "bus", "train", "station", "bus station", "public transport"},
+		tags: map[string][]string{
+			"public_transport": {"station", "stop_position", "platform"},
+			"railway":          {"station", "halt", "tram_stop", "subway_entrance"},
+			"amenity":          {"bus_station", "ferry_terminal", "taxi"},
+			"highway":          {"bus_stop"},
+		},
+	},
+	{
+		names: []string{"fuel", "gas station", "petrol station", "filling station", "service station", "gas", "petrol"},
+		tags:  map[string][]string{"amenity": {"fuel"}},
+	},
+	{
+		names: []string{"charging station", "ev charging", "ev charger", "electric vehicle charging"},
+		tags:  map[string][]string{"amenity": {"charging_station"}},
+	},
+	{
+		names: []string{"parking", "car park", "parking lot", "parking garage"},
+		tags:  map[string][]string{"amenity": {"parking", "parking_space"}},
+	},
+	{
+		names: []string{"post office", "post", "postal"},
+		tags:  map[string][]string{"amenity": {"post_office"}},
+	},
+	{
+		names: []string{"toilets", "toilet", "restroom", "restrooms", "bathroom", "wc", "lavatory"},
+		tags:  map[string][]string{"amenity": {"toilets"}},
+	},
+	{
+		names: []string{"gym", "gyms", "fitness", "fitness centre", "fitness center"},
+		tags:  map[string][]string{"leisure": {"fitness_centre", "sports_centre"}},
+	},
+	{
+		names: []string{"bar", "bars", "pub", "pubs", "nightlife"},
+		tags:  map[string][]string{"amenity": {"bar", "pub", "biergarten", "nightclub"}},
+	},
+	{
+		names: []string{"police", "police station"},
+		tags:  map[string][]string{"amenity": {"police"}},
+	},
+}
+
+// categoryIndex maps each normalized name, and the singular of each, to
+// its category
+var categoryIndex = func() map[string]*categoryMapping {
+	index := make(map[string]*categoryMapping)
+	for i := range categoryTable {
+		for _, name := range categoryTable[i].names {
+			index[name] = &categoryTable[i]
+			if singular := singularCategory(name); index[singular] == nil {
+				index[singular] = &categoryTable[i]
+			}
+		}
+	}
+	return index
+}()
+
+// normalizeCategory lower-cases a category and separates its words with
+// single spaces, so "Gas_Station" and "gas  station" match
+func normalizeCategory(category string) string {
+	category = strings.ToLower(category)
+	category = strings.NewReplacer("_", " ", "-", " ").Replace(category)
+	return strings.Join(strings.Fields(category), " ")
+}
+
+// singularCategory folds the plural of each word of a category to its
+// singular: pharmacies, churches and stations become pharmacy, church and
+// station
+func singularCategory(category string) string {
+	words := strings.Fields(category)
+	for i, word := range words {
+		switch {
+		case len(word) > 4 && strings.HasSuffix(word, "ies"):
+			words[i] = strings.TrimSuffix(word, "ies") + "y"
+		case len(word) > 4 && (strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes") ||
+			strings.HasSuffix(word, "sses") || strings.HasSuffix(word, "xes")):
+			words[i] = strings.TrimSuffix(word, "es")
+		case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// maxCategoryTypos is the number of typos tolerated in a word of a category
+// name of the given length: none in short words, where one letter makes
+// another word ("bar", "car", "marker"), and up to two in long ones
+func maxCategoryTypos(length int) int {
+	switch {
+	case length < 7:
+		return 0
+	case length < 10:
+		return 1
+	}
+	return 2
+}
+
+// categoryTypos returns the typos between a name and a known category name
+// with the same words but for one, misspelled within what that word
+// tolerates, or -1 when they are further apart. Tolerance is per word, so
+// "bus station" is not a misspelled "gas station".
+func categoryTypos(name, known string) int {
+	words, knownWords := strings.Fields(name), strings.Fields(known)
+	if len(words) != len(knownWords) {
+		return -1
+	}
+	typos := -1
+	for i, word := range words {
+		if word == knownWords[i] {
+			continue
+		}
+		distance := levenshtein(word, knownWords[i])
+		if typos >= 0 || distance > maxCategoryTypos(len([]rune(knownWords[i]))) {
+			return -1
+		}
+		typos = distance
+	}
+	return typos
+}
+
+// lookupCategory finds the curated category a name refers to, by exact
+// name or synonym, by its singular, or by the closest name with a few
+// typos in one word. A typo equally close to two categories matches
+// neither.
+func lookupCategory(category string) (*categoryMapping, bool) {
+	name := normalizeCategory(category)
+	if mapping, ok := categoryIndex[name]; ok {
+		return mapping, true
+	}
+	singular := singularCategory(name)
+	if mapping, ok := categoryIndex[singular]; ok {
+		return mapping, true
+	}
+
+	var best *categoryMapping
+	bestDistance, ambiguous := -1, false
+	for known, mapping := range categoryIndex {
+		distance := categoryTypos(singular, known)
+		if distance < 0 {
+			continue
+		}
+		switch {
+		case bestDistance < 0 || distance < bestDistance:
+			best, bestDistance, ambiguous = mapping, distance, false
+		case distance == bestDistance && mapping != best:
+			ambiguous = true
+		}
+	}
+	if best == nil || ambiguous {
+		return nil, false
+	}
+	return best, true
+}
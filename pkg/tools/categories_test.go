@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLookupCategory(t *testing.T) {
+	tests := []struct {
+		category string
+		want     string // canonical name, empty for no match
+	}{
+		{"restaurant", "restaurant"},
+		{"Gas Station", "fuel"},
+		{"gas_station", "fuel"},
+		{"chemist", "pharmacy"},
+		{"Pharmacies", "pharmacy"},
+		{"police stations", "police"},
+		{"resturant", "restaurant"},
+		{"pharmcy", "pharmacy"},
+		{"supermarkett", "supermarket"},
+		{"accomodation", "hotel"},
+		// Short names must match exactly
+		{"car", ""},
+		{"bat", ""},
+		{"bar", "bar"},
+		{"volcano", ""},
+		// Typos are tolerated per word, with the other words exact
+		{"bus station", "transport"},
+		{"bus stations", "transport"},
+		{"gas staton", "fuel"},
+		{"bus staton", "transport"},
+		{"bud station", ""},
+		{"marker", ""},
+		{"markets", "supermarket"},
+	}
+
+	for _, tt := range tests {
+		mapping, ok := lookupCategory(tt.category)
+		got := ""
+		if ok {
+			got = mapping.names[0]
+		}
+		if got != tt.want {
+			t.Errorf("lookupCategory(%q) = %q, want %q", tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestSingularCategory(t *testing.T) {
+	for plural, want := range map[string]string{
+		"pharmacies":     "pharmacy",
+		"churches":       "church",
+		"gas stations":   "gas station",
+		"boxes":          "box",
+		"glass":          "glass",
+		"bus":            "bus",
+		"charging hubs":  "charging hub",
+		"fitness centre": "fitness centre",
+	} {
+		if got := singularCategory(plural); got != want {
+			t.Errorf("singularCategory(%q) = %q, want %q", plural, got, want)
+		}
+	}
+}
+
+func TestMapCategoryToOSMTags(t *testing.T) {
+	if tags := mapCategoryToOSMTags("petrol station"); !slices.Equal(tags["amenity"], []string{"fuel"}) {
+		t.Errorf("unexpected tags for petrol station: %v", tags)
+	}
+
+	// Unknown categories are tried as values of common keys
+	tags := mapCategoryToOSMTags("Volcano")
+	if !slices.Equal(tags["natural"], []string{"volcano"}) || !slices.Equal(tags["amenity"], []string{"volcano"}) {
+		t.Errorf("unexpected tags for an unknown category: %v", tags)
+	}
+	tags = mapCategoryToOSMTags("ice rink")
+	if !slices.Equal(tags["leisure"], []string{"ice_rink", "ice rink"}) {
+		t.Errorf("unexpected tags for an unknown multi-word category: %v", tags)
+	}
+}
@@ -0,0 +1,27 @@
+package tools
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// asOfDescription documents the as_of parameter of time-dependent tools
+const asOfDescription = "Evaluate as of this RFC 3339 time, e.g. 2024-12-24T18:00:00Z, instead of now"
+
+// parseAsOf reads the optional as_of parameter, defaulting to the server
+// clock, which may be simulated
+func parseAsOf(req mcp.CallToolRequest) (time.Time, error) {
+	value := mcp.ParseString(req, "as_of", "")
+	if value == "" {
+		return core.Now(), nil
+	}
+	asOf, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("as_of must be an RFC 3339 time such as 2024-12-24T18:00:00Z")
+	}
+	return asOf, nil
+}
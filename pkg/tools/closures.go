@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// ReportClosureInput defines the input parameters for report_closure
+type ReportClosureInput struct {
+	Action           string         `json:"action"`
+	ID               string         `json:"id,omitempty"`
+	Location         *geo.Location  `json:"location,omitempty"`
+	Geometry         []geo.Location `json:"geometry,omitempty"`
+	Area             bool           `json:"area,omitempty"`
+	Radius           float64        `json:"radius,omitempty"`
+	Description      string         `json:"description,omitempty"`
+	Severity         string         `json:"severity,omitempty"`
+	DelayMinutes     float64        `json:"delay_minutes,omitempty"`
+	ExpiresInMinutes float64        `json:"expires_in_minutes,omitempty"`
+	GeoJSON          any            `json:"geojson,omitempty"`
+}
+
+// ReportClosureOutput defines the output for report_closure
+type ReportClosureOutput struct {
+	Action   string         `json:"action"`
+	Closures []core.Closure `json:"closures"`
+	Removed  int            `json:"removed,omitempty"`
+}
+
+// ReportClosureTool returns a tool definition for managing temporary road closures
+func ReportClosureTool() mcp.Tool {
+	return mcp.NewTool("report_closure",
+		mcp.WithDescription("Report, list or remove temporary road closures and incidents. OSM has no real-time closure data; closures stored here are checked by route_fetch and get_route_directions, which report affected routes and prefers alternatives that avoid them"),
+		mcp.WithString("action",
+			mcp.Description("Action to perform: 'add', 'list', 'remove', 'clear' or 'import' (GeoJSON FeatureCollection)"),
+			mcp.DefaultString("add"),
+		),
+		mcp.WithString("id",
+			mcp.Description("Closure ID (for 'remove', or to replace an existing closure on 'add')"),
+		),
+		mcp.WithObject("location",
+			mcp.Description("Closure point as {latitude, longitude} (for 'add')"),
+		),
+		mcp.WithArray("geometry",
+			mcp.Description("Closed road section as an array of {latitude, longitude} points (for 'add', instead of location)"),
+		),
+		mcp.WithBoolean("area",
+			mcp.Description("Treat geometry as a polygon ring enclosing the closed area"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("Buffer around the geometry in meters within which routes are affected (max 5000)"),
+			mcp.DefaultNumber(core.DefaultClosureRadius),
+		),
+		mcp.WithString("description",
+			mcp.Description("What is closed and why"),
+		),
+		mcp.WithString("severity",
+			mcp.Description("'closed' (road unusable) or 'delay' (usable with delay_minutes extra time)"),
+			mcp.DefaultString(core.ClosureSeverityClosed),
+		),
+		mcp.WithNumber("delay_minutes",
+			mcp.Description("Extra travel time in minutes for 'delay' closures"),
+		),
+		mcp.WithNumber("expires_in_minutes",
+			mcp.Description("Minutes until the closure expires; omit for closures that last until removed"),
+		),
+		mcp.WithObject("geojson",
+			mcp.Description("GeoJSON FeatureCollection of closures (for 'import'). Supported properties: id, description, severity, delay_minutes, radius, start, end (RFC 3339)"),
+		),
+	)
+}
+
+// HandleReportClosure manages the server's temporary closure store
+func HandleReportClosure(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "report_closure")
+
+	// Parse input
+	var input ReportClosureInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if input.Action == "" {
+		input.Action = "add"
+	}
+
+	store := core.DefaultClosureStore()
+	output := ReportClosureOutput{Action: input.Action}
+
+	switch input.Action {
+	case "add":
+		closure, err := closureFromInput(input)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance("Provide a location or geometry; delay closures also need delay_minutes").
+				ToMCPResult(), nil
+		}
+		stored, err := store.Add(closure)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid closure: %s", err)).ToMCPResult(), nil
+		}
+		logger.Info("closure reported", "id", stored.ID, "severity", stored.Severity)
+		output.Closures = []core.Closure{stored}
+	case "list":
+		output.Closures = store.Active()
+	case "remove":
+		if input.ID == "" {
+			return core.NewError(core.ErrMissingParameter, "id is required for 'remove'").ToMCPResult(), nil
+		}
+		if !store.Remove(input.ID) {
+			return core.NewError(core.ErrNoResults, fmt.Sprintf("No closure with id %q", input.ID)).ToMCPResult(), nil
+		}
+		output.Removed = 1
+	case "clear":
+		output.Removed = store.Clear()
+	case "import":
+		if input.GeoJSON == nil {
+			return core.NewError(core.ErrMissingParameter, "geojson is required for 'import'").ToMCPResult(), nil
+		}
+		data, err := json.Marshal(input.GeoJSON)
+		if err != nil {
+			return core.NewError(core.ErrInvalidInput, "Invalid geojson").ToMCPResult(), nil
+		}
+		added, err := store.LoadGeoJSON(data, "import")
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Failed to import closures: %s", err)).ToMCPResult(), nil
+		}
+		logger.Info("closures imported", "count", len(added))
+		output.Closures = added
+	default:
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Unknown action: %s", input.Action)).
+			WithGuidance("Use 'add', 'list', 'remove', 'clear' or 'import'").
+			ToMCPResult(), nil
+	}
+
+	if output.Closures == nil {
+		output.Closures = []core.Closure{}
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// closureFromInput builds a closure from report_closure parameters
+func closureFromInput(input ReportClosureInput) (core.Closure, error) {
+	geometry := input.Geometry
+	if len(geometry) == 0 && input.Location != nil {
+		geometry = []geo.Location{*input.Location}
+	}
+	if len(geometry) == 0 {
+		return core.Closure{}, fmt.Errorf("location or geometry is required for 'add'")
+	}
+
+	closure := core.Closure{
+		ID:          input.ID,
+		Description: input.Description,
+		Severity:    input.Severity,
+		Delay:       input.DelayMinutes * 60,
+		Geometry:    geometry,
+		Area:        input.Area,
+		Radius:      input.Radius,
+		Source:      "manual",
+	}
+	if input.ExpiresInMinutes < 0 {
+		return core.Closure{}, fmt.Errorf("expires_in_minutes must not be negative")
+	}
+	if input.ExpiresInMinutes > 0 {
+		end := core.Now().Add(time.Duration(input.ExpiresInMinutes * float64(time.Minute)))
+		closure.End = &end
+	}
+	return closure, nil
+}
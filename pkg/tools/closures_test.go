@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func callReportClosure(t *testing.T, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "report_closure",
+			Arguments: arguments,
+		},
+	}
+	result, err := HandleReportClosure(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result
+}
+
+func TestHandleReportClosure(t *testing.T) {
+	core.DefaultClosureStore().Clear()
+	defer core.DefaultClosureStore().Clear()
+
+	result := callReportClosure(t, map[string]any{
+		"location":           map[string]any{"latitude": 37.77, "longitude": -122.42},
+		"description":        "Street fair",
+		"expires_in_minutes": 60.0,
+	})
+	if result.IsError {
+		t.Fatalf("add failed: %v", result.Content)
+	}
+
+	var added ReportClosureOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &added); err != nil {
+		t.Fatal(err)
+	}
+	if len(added.Closures) != 1 || added.Closures[0].End == nil {
+		t.Fatalf("unexpected add output: %+v", added)
+	}
+
+	result = callReportClosure(t, map[string]any{"action": "list"})
+	var listed ReportClosureOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Closures) != 1 || listed.Closures[0].Description != "Street fair" {
+		t.Errorf("unexpected list output: %+v", listed)
+	}
+
+	if result := callReportClosure(t, map[string]any{"action": "remove", "id": added.Closures[0].ID}); result.IsError {
+		t.Errorf("remove failed: %v", result.Content)
+	}
+	if result := callReportClosure(t, map[string]any{"action": "remove", "id": "missing"}); !result.IsError {
+		t.Error("expected error removing unknown closure")
+	}
+}
+
+func TestHandleReportClosureValidation(t *testing.T) {
+	defer core.DefaultClosureStore().Clear()
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+	}{
+		{"Missing geometry", map[string]any{"action": "add"}},
+		{"Delay without minutes", map[string]any{
+			"location": map[string]any{"latitude": 37.77, "longitude": -122.42},
+			"severity": "delay",
+		}},
+		{"Unknown action", map[string]any{"action": "reroute"}},
+		{"Import without geojson", map[string]any{"action": "import"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := callReportClosure(t, tt.arguments); !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
+
+func TestHandleGetRouteDirectionsAvoidsClosures(t *testing.T) {
+	// The fastest route runs straight through a closure; the alternative
+	// detours north of it
+	direct := osm.EncodePolyline([]geo.Location{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.02}})
+	detour := osm.EncodePolyline([]geo.Location{{Latitude: 0, Longitude: 0}, {Latitude: 0.01, Longitude: 0.01}, {Latitude: 0, Longitude: 0.02}})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("alternatives") == "" || r.URL.Query().Get("alternatives") == "false" {
+			fmt.Fprintf(w, `{"code":"Ok","routes":[{"distance":2000,"duration":100,"geometry":%q,"legs":[]}],"waypoints":[]}`, direct)
+			return
+		}
+		fmt.Fprintf(w, `{"code":"Ok","routes":[{"distance":2000,"duration":100,"geometry":%q,"legs":[]},`+
+			`{"distance":3000,"duration":200,"geometry":%q,"legs":[]}],"waypoints":[]}`, direct, detour)
+	}))
+	t.Cleanup(srv.Close)
+	if err := osm.SetServiceURLs(osm.ServiceURLs{OSRM: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	osm.UpdateOSRMRateLimits(1000, 1000)
+	t.Cleanup(func() {
+		osm.SetServiceURLs(osm.ServiceURLs{OSRM: osm.DefaultOSRMBaseURL})
+		osm.UpdateOSRMRateLimits(1, 1)
+		core.DefaultClosureStore().Clear()
+	})
+
+	if result := callReportClosure(t, map[string]any{
+		"location": map[string]any{"latitude": 0.0, "longitude": 0.01},
+		"radius":   50.0,
+	}); result.IsError {
+		t.Fatalf("add failed: %v", result.Content)
+	}
+
+	call := func(arguments map[string]any) (output struct {
+		Duration       float64        `json:"duration"`
+		Closures       []core.Closure `json:"closures"`
+		ClosurePenalty float64        `json:"closure_penalty"`
+	}) {
+		t.Helper()
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_route_directions", Arguments: arguments}}
+		result, err := HandleGetRouteDirections(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		AssertSuccessResult(t, result, "directions should succeed")
+		if err := ParseResultJSON(result, &output); err != nil {
+			t.Fatal(err)
+		}
+		return output
+	}
+	arguments := map[string]any{"start_lat": 0.0, "start_lon": 0.0, "end_lat": 0.0, "end_lon": 0.02, "mode": "car"}
+
+	if output := call(arguments); output.Duration != 200 || len(output.Closures) != 0 {
+		t.Errorf("expected the detour around the closure, got %+v", output)
+	}
+
+	arguments["ignore_closures"] = true
+	if output := call(arguments); output.Duration != 100 || len(output.Closures) != 0 {
+		t.Errorf("expected the direct route when ignoring closures, got %+v", output)
+	}
+
+	// The closures on a route chosen around avoid areas are reported
+	delete(arguments, "ignore_closures")
+	arguments["avoid_areas"] = []any{[]any{
+		map[string]any{"latitude": 0.05, "longitude": 0.05},
+		map[string]any{"latitude": 0.05, "longitude": 0.06},
+		map[string]any{"latitude": 0.06, "longitude": 0.06},
+	}}
+	if output := call(arguments); output.Duration != 200 || output.ClosurePenalty != 0 {
+		t.Errorf("expected the avoid-area route to skip the closure, got %+v", output)
+	}
+}
@@ -129,11 +129,11 @@ func HandleAnalyzeCommute(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 	}
 
 	// Basic validation
-	if homeLat < -90 || homeLat > 90 || workLat < -90 || workLat > 90 {
-		return ErrorResponse("Latitude must be between -90 and 90"), nil
+	if err := core.ValidateCoords(homeLat, homeLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
-	if homeLon < -180 || homeLon > 180 || workLon < -180 || workLon > 180 {
-		return ErrorResponse("Longitude must be between -180 and 180"), nil
+	if err := core.ValidateCoords(workLat, workLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
 
 	// Initialize analysis result
@@ -216,7 +216,7 @@ func HandleAnalyzeCommute(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 			} `json:"routes"`
 		}
 
-		if err := json.NewDecoder(resp.Body).Decode(&osrmResp); err != nil {
+		if err := core.DecodeUpstream("OSRM", resp.Body, &osrmResp, "code"); err != nil {
 			logger.Error("failed to decode response", "error", err)
 			if err := resp.Body.Close(); err != nil {
 				logger.Warn("failed to close response body", "error", err)
@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// Contact holds normalized contact details parsed from OSM tags
+type Contact struct {
+	Phone   []string          `json:"phone,omitempty"`   // E.164 where the number carries a country code
+	Website string            `json:"website,omitempty"` // absolute URL
+	Email   string            `json:"email,omitempty"`
+	Social  map[string]string `json:"social,omitempty"` // platform name to profile URL
+}
+
+// socialProfileBases maps social platforms to the base URL used for bare handles
+var socialProfileBases = map[string]string{
+	"facebook":  "https://www.facebook.com/",
+	"instagram": "https://www.instagram.com/",
+	"twitter":   "https://x.com/",
+	"youtube":   "https://www.youtube.com/",
+	"linkedin":  "https://www.linkedin.com/in/",
+	"tiktok":    "https://www.tiktok.com/@",
+	"telegram":  "https://t.me/",
+	"vk":        "https://vk.com/",
+}
+
+// parseContact extracts phone, website, email and social-media details from
+// both the plain (phone=*) and namespaced (contact:phone=*) OSM tag forms.
+// It returns nil when the element carries no usable contact information.
+func parseContact(tags map[string]string) *Contact {
+	contact := &Contact{}
+
+	seenPhones := make(map[string]bool)
+	for _, key := range []string{"phone", "contact:phone", "contact:mobile", "mobile"} {
+		for _, raw := range splitTagValues(tags[key]) {
+			if phone := normalizePhone(raw); phone != "" && !seenPhones[phone] {
+				seenPhones[phone] = true
+				contact.Phone = append(contact.Phone, phone)
+			}
+		}
+	}
+
+	for _, key := range []string{"website", "contact:website", "url"} {
+		if website := normalizeWebsite(tags[key]); website != "" {
+			contact.Website = website
+			break
+		}
+	}
+
+	for _, key := range []string{"email", "contact:email"} {
+		if email := normalizeEmail(tags[key]); email != "" {
+			contact.Email = email
+			break
+		}
+	}
+
+	for platform := range socialProfileBases {
+		value := tags["contact:"+platform]
+		if value == "" {
+			value = tags[platform]
+		}
+		if value == "" && platform == "twitter" {
+			value = tags["contact:x"]
+		}
+		if profile := normalizeSocial(platform, value); profile != "" {
+			if contact.Social == nil {
+				contact.Social = make(map[string]string)
+			}
+			contact.Social[platform] = profile
+		}
+	}
+
+	if len(contact.Phone) == 0 && contact.Website == "" && contact.Email == "" && len(contact.Social) == 0 {
+		return nil
+	}
+	return contact
+}
+
+// normalizePhone converts a phone number to E.164 when it carries an
+// international prefix ("+" or "00"). Numbers without a country code are
+// returned with formatting characters stripped, as the country is unknown.
+func normalizePhone(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	// Drop extensions such as "ext. 12" or "x12"
+	lower := strings.ToLower(raw)
+	for _, marker := range []string{"ext", " x"} {
+		if idx := strings.Index(lower, marker); idx > 0 {
+			raw = raw[:idx]
+			lower = lower[:idx]
+		}
+	}
+
+	international := strings.HasPrefix(strings.TrimSpace(raw), "+") || strings.HasPrefix(strings.TrimSpace(raw), "00")
+
+	// The "(0)" trunk prefix is dropped when dialling internationally
+	if international {
+		raw = strings.Replace(raw, "(0)", "", 1)
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	number := digits.String()
+
+	if international && !strings.HasPrefix(strings.TrimSpace(raw), "+") {
+		number = strings.TrimPrefix(number, "00")
+	}
+
+	if len(number) < 4 || len(number) > 15 {
+		return ""
+	}
+	if international {
+		return "+" + number
+	}
+	return number
+}
+
+// normalizeWebsite returns an absolute http(s) URL, assuming https when the scheme is missing
+func normalizeWebsite(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || !strings.Contains(u.Host, ".") {
+		return ""
+	}
+	return u.String()
+}
+
+// normalizeEmail returns the lower-cased address, or "" if it is not a valid email
+func normalizeEmail(raw string) string {
+	raw = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(raw), "mailto:"))
+	if raw == "" {
+		return ""
+	}
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(addr.Address)
+}
+
+// normalizeSocial turns a social-media tag value (URL or handle) into a profile URL
+func normalizeSocial(platform, raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if strings.HasPrefix(raw, "http://") || strings.HasPrefix(raw, "https://") {
+		return normalizeWebsite(raw)
+	}
+	if strings.Contains(raw, ".") && strings.Contains(raw, "/") {
+		return normalizeWebsite(raw)
+	}
+
+	handle := strings.TrimPrefix(raw, "@")
+	if handle == "" || strings.ContainsAny(handle, " /") {
+		return ""
+	}
+	return socialProfileBases[platform] + url.PathEscape(handle)
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"+1 (415) 555-0132", "+14155550132"},
+		{"+44 (0)20 7946 0000", "+442079460000"},
+		{"0044 20 7946 0000", "+442079460000"},
+		{"+49 30 1234567 ext. 12", "+49301234567"},
+		{"(415) 555-0132", "4155550132"},
+		{"12", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := normalizePhone(tt.input); got != tt.want {
+				t.Errorf("normalizePhone(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseContact(t *testing.T) {
+	tags := map[string]string{
+		"phone":             "+1 415 555 0132;+1 415 555 0133",
+		"contact:phone":     "+1-415-555-0132",
+		"contact:website":   "example.com/cafe",
+		"email":             "Hello@Example.com",
+		"contact:instagram": "@examplecafe",
+		"facebook":          "https://www.facebook.com/examplecafe",
+	}
+
+	contact := parseContact(tags)
+	if contact == nil {
+		t.Fatal("expected contact to be parsed")
+	}
+	if len(contact.Phone) != 2 || contact.Phone[0] != "+14155550132" || contact.Phone[1] != "+14155550133" {
+		t.Errorf("unexpected phones: %v", contact.Phone)
+	}
+	if contact.Website != "https://example.com/cafe" {
+		t.Errorf("unexpected website: %s", contact.Website)
+	}
+	if contact.Email != "hello@example.com" {
+		t.Errorf("unexpected email: %s", contact.Email)
+	}
+	if contact.Social["instagram"] != "https://www.instagram.com/examplecafe" {
+		t.Errorf("unexpected instagram: %s", contact.Social["instagram"])
+	}
+	if contact.Social["facebook"] != "https://www.facebook.com/examplecafe" {
+		t.Errorf("unexpected facebook: %s", contact.Social["facebook"])
+	}
+
+	if parseContact(map[string]string{"name": "No Contact", "email": "not-an-email"}) != nil {
+		t.Error("expected nil contact for elements without usable contact tags")
+	}
+}
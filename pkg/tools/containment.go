@@ -0,0 +1,207 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// maxContainmentLocations caps the locations tested in one call
+const maxContainmentLocations = 1000
+
+// Containment is whether one location lies inside an area
+type Containment struct {
+	Location geo.Location `json:"location"`
+	Inside   bool         `json:"inside"`
+	// DistanceToBoundary is how far the location is from the nearest edge
+	// of the polygon, in meters, to judge points close to the line
+	DistanceToBoundary *float64 `json:"distance_to_boundary,omitempty"`
+}
+
+// ContainmentOutput defines the output of point_in_polygon and point_in_bbox
+type ContainmentOutput struct {
+	Results []Containment `json:"results"`
+	Inside  int           `json:"inside"`  // count of locations inside
+	Outside int           `json:"outside"` // count of locations outside
+}
+
+// PointInPolygonInput defines the input parameters for point_in_polygon
+type PointInPolygonInput struct {
+	Locations []geo.Location `json:"locations"`
+	Polygon   []geo.Location `json:"polygon,omitempty"`
+	Polyline  string         `json:"polyline,omitempty"`
+	Geometry  *AreaGeometry  `json:"geometry,omitempty"`
+}
+
+// PointInPolygonTool returns a tool definition for testing locations against a polygon
+func PointInPolygonTool() mcp.Tool {
+	return mcp.NewTool("point_in_polygon",
+		mcp.WithDescription("Test whether locations fall inside a polygon, such as a boundary from geocode_address or get_osm_geometry, a delivery area or a flood zone. The polygon is given as points, an encoded polyline or a GeoJSON Polygon or MultiPolygon; locations in holes are outside. Returns each location's distance to the boundary"),
+		mcp.WithArray("locations",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("The locations to test as an array of {latitude, longitude}, at most %d", maxContainmentLocations)),
+		),
+		mcp.WithArray("polygon",
+			mcp.Description("The polygon outline as an array of {latitude, longitude} points, at least 3"),
+		),
+		mcp.WithString("polyline",
+			mcp.Description("The polygon outline as an encoded polyline, instead of polygon"),
+		),
+		mcp.WithObject("geometry",
+			mcp.Description("A GeoJSON Polygon or MultiPolygon geometry with [longitude, latitude] positions, instead of polygon"),
+		),
+	)
+}
+
+// HandlePointInPolygon tests locations against a polygon
+func HandlePointInPolygon(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "point_in_polygon")
+
+	var input PointInPolygonInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if mcpErr := validateContainmentLocations(input.Locations); mcpErr != nil {
+		return mcpErr.ToMCPResult(), nil
+	}
+	polygons, err := areaInputPolygons("polygon", input.Polygon, input.Polyline, input.Geometry)
+	if err != nil {
+		logger.Error("invalid polygon", "error", err)
+		return core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance("Pass the area as polygon points, or the geometry returned by geocode_address or get_osm_geometry").
+			ToMCPResult(), nil
+	}
+
+	output := ContainmentOutput{Results: make([]Containment, len(input.Locations))}
+	for i, loc := range input.Locations {
+		distance := math.Inf(1)
+		for _, rings := range polygons {
+			for _, ring := range rings {
+				distance = math.Min(distance, geo.DistanceToBoundary(loc.Latitude, loc.Longitude, ring))
+			}
+		}
+		distance = math.Round(distance*10) / 10
+		output.Results[i] = Containment{
+			Location:           loc,
+			Inside:             geo.PointInMultiPolygon(loc.Latitude, loc.Longitude, polygons),
+			DistanceToBoundary: &distance,
+		}
+		output.count(output.Results[i].Inside)
+	}
+
+	return containmentResult(logger, output)
+}
+
+// PointInBBoxInput defines the input parameters for point_in_bbox
+type PointInBBoxInput struct {
+	Locations []geo.Location   `json:"locations"`
+	BBox      *geo.BoundingBox `json:"bbox"`
+}
+
+// PointInBBoxTool returns a tool definition for testing locations against a bounding box
+func PointInBBoxTool() mcp.Tool {
+	return mcp.NewTool("point_in_bbox",
+		mcp.WithDescription("Test whether locations fall inside a bounding box, edges included. A box with minLon greater than maxLon crosses the antimeridian"),
+		mcp.WithArray("locations",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("The locations to test as an array of {latitude, longitude}, at most %d", maxContainmentLocations)),
+		),
+		mcp.WithObject("bbox",
+			mcp.Required(),
+			mcp.Description("The bounding box as {minLat, minLon, maxLat, maxLon}"),
+		),
+	)
+}
+
+// HandlePointInBBox tests locations against a bounding box
+func HandlePointInBBox(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "point_in_bbox")
+
+	var input PointInBBoxInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if mcpErr := validateContainmentLocations(input.Locations); mcpErr != nil {
+		return mcpErr.ToMCPResult(), nil
+	}
+	if input.BBox == nil {
+		return core.NewError(core.ErrMissingParameter, "bbox is required").ToMCPResult(), nil
+	}
+	bbox := input.BBox
+	if err := core.ValidateCoords(bbox.MinLat, bbox.MinLon); err != nil {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid bbox: %s", err)).ToMCPResult(), nil
+	}
+	if err := core.ValidateCoords(bbox.MaxLat, bbox.MaxLon); err != nil {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid bbox: %s", err)).ToMCPResult(), nil
+	}
+	if bbox.MinLat > bbox.MaxLat {
+		return core.NewError(core.ErrInvalidParameter, "Invalid bbox: minLat is greater than maxLat").ToMCPResult(), nil
+	}
+
+	output := ContainmentOutput{Results: make([]Containment, len(input.Locations))}
+	for i, loc := range input.Locations {
+		output.Results[i] = Containment{Location: loc, Inside: bbox.Contains(loc.Latitude, loc.Longitude)}
+		output.count(output.Results[i].Inside)
+	}
+
+	return containmentResult(logger, output)
+}
+
+// count adds a location to the inside or outside tally
+func (o *ContainmentOutput) count(inside bool) {
+	if inside {
+		o.Inside++
+	} else {
+		o.Outside++
+	}
+}
+
+// validateContainmentLocations checks the locations given to the
+// containment tools
+func validateContainmentLocations(locations []geo.Location) *core.MCPError {
+	if len(locations) == 0 {
+		return core.NewError(core.ErrMissingParameter, "locations is required").
+			WithGuidance("Pass at least one {latitude, longitude} location")
+	}
+	if len(locations) > maxContainmentLocations {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Too many locations: %d (maximum is %d)", len(locations), maxContainmentLocations)).
+			WithGuidance("Split the locations into several calls")
+	}
+	for i, loc := range locations {
+		if err := core.ValidateCoords(loc.Latitude, loc.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid coordinates at index %d: %s", i, err))
+		}
+	}
+	return nil
+}
+
+// containmentResult marshals a containment output as a tool result
+func containmentResult(logger *slog.Logger, output ContainmentOutput) (*mcp.CallToolResult, error) {
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
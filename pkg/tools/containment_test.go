@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestHandlePointInPolygon(t *testing.T) {
+	call := func(args map[string]any) *mcp.CallToolResult {
+		result, err := HandlePointInPolygon(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+	locations := []geo.Location{
+		{Latitude: 0.002, Longitude: 0.002}, // inside, ~222 m from the edge
+		{Latitude: 0.005, Longitude: 0.005}, // in the hole
+		{Latitude: 0.005, Longitude: 0.02},  // east of the square
+	}
+
+	// A 0.01 degree square with a hole, as returned by get_osm_geometry
+	geometry := json.RawMessage(`{"type": "Polygon", "coordinates": [
+		[[0, 0], [0.01, 0], [0.01, 0.01], [0, 0.01], [0, 0]],
+		[[0.004, 0.004], [0.004, 0.006], [0.006, 0.006], [0.006, 0.004], [0.004, 0.004]]]}`)
+	result := call(map[string]any{"locations": locations, "geometry": geometry})
+	AssertSuccessResult(t, result, "geometry polygon should succeed")
+	var output ContainmentOutput
+	if err := ParseResultJSON(result, &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Inside != 1 || output.Outside != 2 {
+		t.Fatalf("got %d inside and %d outside, want 1 and 2", output.Inside, output.Outside)
+	}
+	for i, want := range []bool{true, false, false} {
+		if output.Results[i].Inside != want {
+			t.Errorf("location %d inside = %v, want %v", i, output.Results[i].Inside, want)
+		}
+	}
+	if d := output.Results[0].DistanceToBoundary; d == nil || *d < 220 || *d > 225 {
+		t.Errorf("distance to boundary = %v, want about 222 m", d)
+	}
+	if d := output.Results[1].DistanceToBoundary; d == nil || *d < 110 || *d > 112 {
+		t.Errorf("distance to the hole = %v, want about 111 m", d)
+	}
+
+	// An outline of points works the same, without the hole
+	square := []geo.Location{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.01}, {Latitude: 0.01, Longitude: 0.01}, {Latitude: 0.01, Longitude: 0}}
+	if err := ParseResultJSON(call(map[string]any{"locations": locations, "polygon": square}), &output); err != nil || output.Inside != 2 {
+		t.Errorf("points polygon: got %d inside, %v", output.Inside, err)
+	}
+
+	for name, args := range map[string]map[string]any{
+		"no locations":   {"polygon": square},
+		"no polygon":     {"locations": locations},
+		"two polygons":   {"locations": locations, "polygon": square, "geometry": geometry},
+		"bad location":   {"locations": []geo.Location{{Latitude: 95, Longitude: 0}}, "polygon": square},
+		"line geometry":  {"locations": locations, "geometry": map[string]any{"type": "LineString", "coordinates": [][]float64{{0, 0}, {1, 1}}}},
+		"two point ring": {"locations": locations, "polygon": square[:2]},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("%s: expected an error result", name)
+		}
+	}
+}
+
+func TestHandlePointInBBox(t *testing.T) {
+	call := func(args map[string]any) *mcp.CallToolResult {
+		result, err := HandlePointInBBox(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+	locations := []geo.Location{{Latitude: -17, Longitude: 179}, {Latitude: -17, Longitude: -179}, {Latitude: -17, Longitude: 170}}
+
+	// Fiji crosses the antimeridian
+	result := call(map[string]any{"locations": locations, "bbox": geo.BoundingBox{MinLat: -21, MinLon: 177, MaxLat: -12, MaxLon: -178}})
+	AssertSuccessResult(t, result, "bbox should succeed")
+	var output ContainmentOutput
+	if err := ParseResultJSON(result, &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Inside != 2 || output.Outside != 1 || output.Results[2].Inside {
+		t.Errorf("got %+v, want the first two locations inside", output)
+	}
+	if output.Results[0].DistanceToBoundary != nil {
+		t.Error("bbox results should not carry a boundary distance")
+	}
+
+	if result := call(map[string]any{"locations": locations}); !result.IsError {
+		t.Error("expected an error result without a bbox")
+	}
+	if result := call(map[string]any{"locations": locations, "bbox": geo.BoundingBox{MinLat: 10, MaxLat: 5}}); !result.IsError {
+		t.Error("expected an error result for an inverted bbox")
+	}
+}
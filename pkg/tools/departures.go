@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/transit"
+)
+
+const (
+	// defaultDepartureLimit is the default number of departures returned
+	defaultDepartureLimit = 10
+	// maxDepartureLimit caps the number of departures returned
+	maxDepartureLimit = 50
+)
+
+// NextDeparturesOutput defines the output for next_departures
+type NextDeparturesOutput struct {
+	Stop       transit.Stop        `json:"stop"`
+	Departures []transit.Departure `json:"departures"`
+	Sources    []string            `json:"sources"`
+}
+
+// NextDeparturesTool returns a tool definition for upcoming departures at a stop
+func NextDeparturesTool() mcp.Tool {
+	return mcp.NewTool("next_departures",
+		mcp.WithDescription("List upcoming departures from a bus, tram, metro, rail or ferry stop found in OpenStreetMap, using the configured departures providers (GTFS feeds or operator APIs). Departures include real-time predictions when the provider has them"),
+		mcp.WithString("stop_id",
+			mcp.Required(),
+			mcp.Description("OSM ID of the stop, e.g. node/123456. A bare number is taken as a node ID"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of departures to return (max 50)"),
+			mcp.DefaultNumber(defaultDepartureLimit),
+		),
+		mcp.WithString("after",
+			mcp.Description("Only return departures at or after this RFC 3339 time; defaults to as_of"),
+		),
+		mcp.WithString("as_of",
+			mcp.Description(asOfDescription),
+		),
+	)
+}
+
+// HandleNextDepartures returns upcoming departures for an OSM transit stop
+func HandleNextDepartures(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "next_departures")
+
+	elementType, id, err := parseElementID(mcp.ParseString(req, "stop_id", ""))
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance("Use the id of a stop returned by find_nearby_places with category transport, e.g. node/123456").
+			ToMCPResult(), nil
+	}
+
+	limit := int(mcp.ParseFloat64(req, "limit", defaultDepartureLimit))
+	if limit <= 0 || limit > maxDepartureLimit {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Limit must be between 1 and %d", maxDepartureLimit)).ToMCPResult(), nil
+	}
+
+	from, err := parseAsOf(req)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+	if after := mcp.ParseString(req, "after", ""); after != "" {
+		from, err = time.Parse(time.RFC3339, after)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, "after must be an RFC 3339 time such as 2025-06-01T08:30:00Z").ToMCPResult(), nil
+		}
+	}
+
+	if len(transit.Providers()) == 0 {
+		return core.NewError(core.ErrServiceUnavailable, "No departures provider is configured").
+			WithGuidance("Start the server with --departures-url or --gtfs-feed to enable departures").
+			ToMCPResult(), nil
+	}
+
+	stop, errResult := lookupTransitStop(ctx, logger, elementType, id)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	departures, sources, err := transit.NextDepartures(ctx, stop, from, limit)
+	if errors.Is(err, transit.ErrStopNotCovered) {
+		return core.NewError(core.ErrNoResults, "No departures provider covers this stop").
+			WithGuidance("The configured feeds do not include this stop or operator").
+			ToMCPResult(), nil
+	}
+	if err != nil {
+		logger.Error("failed to fetch departures", "stop", stop.OSMID, "error", err)
+		return core.ServiceError("Departures", http.StatusServiceUnavailable,
+			"Failed to fetch departures").ToMCPResult(), nil
+	}
+
+	output := NextDeparturesOutput{
+		Stop:       stop,
+		Departures: departures,
+		Sources:    sources,
+	}
+	if output.Departures == nil {
+		output.Departures = []transit.Departure{}
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// lookupTransitStop fetches an OSM element and checks that it is a transit
+// stop. On failure it returns the tool result to report.
+func lookupTransitStop(ctx context.Context, logger *slog.Logger, elementType string, id int64) (transit.Stop, *mcp.CallToolResult) {
+	elements, err := executeOverpassQuery(ctx, fmt.Sprintf("[out:json][timeout:25];%s(%d);out center;", elementType, id))
+	if err != nil {
+		logger.Error("failed to look up stop", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return transit.Stop{}, mcpErr.ToMCPResult()
+		}
+		return transit.Stop{}, core.ServiceError("Overpass", http.StatusServiceUnavailable,
+			"Failed to look up the stop").ToMCPResult()
+	}
+	if len(elements) == 0 {
+		return transit.Stop{}, core.NewError(core.ErrNoResults, fmt.Sprintf("%s/%d was not found", elementType, id)).ToMCPResult()
+	}
+
+	element := elements[0]
+	if !isTransitStop(element.Tags) {
+		return transit.Stop{}, core.NewError(core.ErrInvalidParameter, fmt.Sprintf("%s/%d is not a transit stop", elementType, id)).
+			WithGuidance("Find stops with find_nearby_places and category transport").
+			ToMCPResult()
+	}
+
+	location := geo.Location{Latitude: element.Lat, Longitude: element.Lon}
+	if element.Center != nil {
+		location = geo.Location{Latitude: element.Center.Lat, Longitude: element.Center.Lon}
+	}
+	return transit.StopFromTags(fmt.Sprintf("%s/%d", elementType, id), location, element.Tags), nil
+}
+
+// parseElementID parses an OSM element reference such as node/123. A bare
+// number is taken as a node ID.
+func parseElementID(value string) (string, int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", 0, errors.New("stop_id is required")
+	}
+
+	elementType, idPart := "node", value
+	if i := strings.IndexByte(value, '/'); i >= 0 {
+		elementType, idPart = strings.ToLower(value[:i]), value[i+1:]
+	}
+	switch elementType {
+	case "node", "way", "relation":
+	default:
+		return "", 0, fmt.Errorf("invalid element type %q, use node, way or relation", elementType)
+	}
+
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, fmt.Errorf("invalid element ID %q", value)
+	}
+	return elementType, id, nil
+}
+
+// isTransitStop reports whether tags describe a stop, platform or station
+func isTransitStop(tags map[string]string) bool {
+	switch {
+	case tags["highway"] == "bus_stop":
+		return true
+	case tags["public_transport"] == "platform", tags["public_transport"] == "stop_position", tags["public_transport"] == "station":
+		return true
+	case tags["railway"] == "station", tags["railway"] == "halt", tags["railway"] == "tram_stop", tags["railway"] == "platform":
+		return true
+	case tags["amenity"] == "bus_station", tags["amenity"] == "ferry_terminal":
+		return true
+	}
+	return false
+}
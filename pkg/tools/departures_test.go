@@ -0,0 +1,48 @@
+package tools
+
+import "testing"
+
+func TestParseElementID(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantType string
+		wantID   int64
+		wantErr  bool
+	}{
+		{"node/123", "node", 123, false},
+		{"Way/45", "way", 45, false},
+		{"678", "node", 678, false},
+		{"area/1", "", 0, true},
+		{"node/abc", "", 0, true},
+		{"node/-1", "", 0, true},
+		{"", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		elementType, id, err := parseElementID(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseElementID(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if elementType != tt.wantType || id != tt.wantID {
+			t.Errorf("parseElementID(%q) = %s, %d; want %s, %d", tt.value, elementType, id, tt.wantType, tt.wantID)
+		}
+	}
+}
+
+func TestIsTransitStop(t *testing.T) {
+	stops := []map[string]string{
+		{"highway": "bus_stop"},
+		{"public_transport": "platform"},
+		{"railway": "tram_stop"},
+		{"amenity": "ferry_terminal"},
+	}
+	for _, tags := range stops {
+		if !isTransitStop(tags) {
+			t.Errorf("expected %v to be a transit stop", tags)
+		}
+	}
+	if isTransitStop(map[string]string{"amenity": "cafe"}) {
+		t.Error("a cafe is not a transit stop")
+	}
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxDistanceBands bounds the number of bands a search radius is split into
+const maxDistanceBands = 20
+
+const bandsDescription = "Group results into distance bands this many meters wide (e.g. 250 for 0-250m, 250-500m, ...), each with its count and closest results; limit then applies per band"
+
+// DistanceBand is the places found between two distances from the center
+type DistanceBand struct {
+	MinDistance float64 `json:"min_distance"`
+	MaxDistance float64 `json:"max_distance"`
+	Count       int     `json:"count"`
+	Places      []Place `json:"places"`
+}
+
+// validateBandWidth checks that a band width splits radius into at most
+// maxDistanceBands bands
+func validateBandWidth(width, radius float64) error {
+	if width <= 0 || math.IsNaN(width) || math.IsInf(width, 0) {
+		return fmt.Errorf("bands must be a positive width in meters")
+	}
+	if math.Ceil(radius/width) > maxDistanceBands {
+		return fmt.Errorf("bands of %.0f m split a %.0f m radius into more than %d bands; use a width of at least %.0f m",
+			width, radius, maxDistanceBands, math.Ceil(radius/maxDistanceBands))
+	}
+	return nil
+}
+
+// groupByDistance splits places sorted by distance into bands of width
+// meters covering radius, keeping the closest perBand places of each. Every
+// band is returned, so empty bands show where nothing was found.
+func groupByDistance(places []Place, width, radius float64, perBand int) []DistanceBand {
+	n := max(int(math.Ceil(radius/width)), 1)
+	bands := make([]DistanceBand, n)
+	for i := range bands {
+		bands[i] = DistanceBand{
+			MinDistance: float64(i) * width,
+			MaxDistance: math.Min(float64(i+1)*width, radius),
+			Places:      []Place{},
+		}
+	}
+
+	for _, place := range places {
+		// Places just past the radius, as Overpass measures it, join the
+		// last band
+		i := min(int(place.Distance/width), n-1)
+		bands[i].Count++
+		if len(bands[i].Places) < perBand {
+			bands[i].Places = append(bands[i].Places, place)
+		}
+	}
+	return bands
+}
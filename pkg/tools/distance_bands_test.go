@@ -0,0 +1,65 @@
+package tools
+
+import "testing"
+
+func TestGroupByDistance(t *testing.T) {
+	places := []Place{
+		{ID: "1", Distance: 10},
+		{ID: "2", Distance: 100},
+		{ID: "3", Distance: 200},
+		{ID: "4", Distance: 600},
+		{ID: "5", Distance: 1003}, // just past the radius
+	}
+
+	bands := groupByDistance(places, 250, 1000, 2)
+	if len(bands) != 4 {
+		t.Fatalf("got %d bands, want 4", len(bands))
+	}
+
+	want := []struct {
+		min, max float64
+		count    int
+		ids      []string
+	}{
+		{0, 250, 3, []string{"1", "2"}},
+		{250, 500, 0, nil},
+		{500, 750, 1, []string{"4"}},
+		{750, 1000, 1, []string{"5"}},
+	}
+	for i, w := range want {
+		b := bands[i]
+		if b.MinDistance != w.min || b.MaxDistance != w.max || b.Count != w.count {
+			t.Errorf("band %d = %v-%v count %d, want %v-%v count %d", i, b.MinDistance, b.MaxDistance, b.Count, w.min, w.max, w.count)
+		}
+		if len(b.Places) != len(w.ids) {
+			t.Errorf("band %d has %d places, want %d", i, len(b.Places), len(w.ids))
+			continue
+		}
+		for j, id := range w.ids {
+			if b.Places[j].ID != id {
+				t.Errorf("band %d place %d = %s, want %s", i, j, b.Places[j].ID, id)
+			}
+		}
+	}
+}
+
+func TestGroupByDistancePartialBand(t *testing.T) {
+	bands := groupByDistance(nil, 400, 1000, 5)
+	if len(bands) != 3 || bands[2].MaxDistance != 1000 {
+		t.Errorf("bands = %+v, want 3 ending at the radius", bands)
+	}
+	if bands[0].Places == nil {
+		t.Error("empty band places should encode as [], not null")
+	}
+}
+
+func TestValidateBandWidth(t *testing.T) {
+	if err := validateBandWidth(250, 5000); err != nil {
+		t.Errorf("250 m over 5000 m: %v", err)
+	}
+	for _, width := range []float64{-1, 100} {
+		if err := validateBandWidth(width, 5000); err == nil {
+			t.Errorf("validateBandWidth(%v, 5000) succeeded", width)
+		}
+	}
+}
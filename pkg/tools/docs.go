@@ -0,0 +1,241 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+const (
+	// docIndexURI is the resource listing the documented tools
+	docIndexURI = "doc://tools"
+	// docURIPrefix starts the URI of each tool's documentation
+	docURIPrefix = "doc://tools/"
+	// docMIMEType is the type of the documentation resources
+	docMIMEType = "text/markdown"
+)
+
+// exampleValues are sample arguments used in generated examples, by
+// parameter name
+var exampleValues = map[string]any{
+	"latitude":    51.5074,
+	"longitude":   -0.1278,
+	"lat":         51.5074,
+	"lon":         -0.1278,
+	"address":     "10 Downing Street, London, UK",
+	"query":       "British Museum, London",
+	"name":        "British Museum",
+	"radius":      500,
+	"category":    "restaurant",
+	"polyline":    "a~l~FfynpOnlB_pDhgEhjD",
+	"mode":        "car",
+	"zoom":        14,
+	"x":           8186,
+	"y":           5448,
+	"interval":    100,
+	"location":    map[string]any{"latitude": 51.5074, "longitude": -0.1278},
+	"center":      map[string]any{"latitude": 51.5074, "longitude": -0.1278},
+	"from":        map[string]any{"latitude": 51.5074, "longitude": -0.1278},
+	"to":          map[string]any{"latitude": 51.5194, "longitude": -0.1270},
+	"start":       map[string]any{"latitude": 51.5074, "longitude": -0.1278},
+	"end":         map[string]any{"latitude": 51.5194, "longitude": -0.1270},
+	"origin":      map[string]any{"latitude": 51.5074, "longitude": -0.1278},
+	"destination": map[string]any{"latitude": 51.5194, "longitude": -0.1270},
+	"bbox":        map[string]any{"minLat": 51.50, "minLon": -0.14, "maxLat": 51.52, "maxLon": -0.11},
+	"tags":        map[string]any{"amenity": "cafe"},
+}
+
+// RegisterDocs registers a documentation resource for each tool, so clients
+// can fetch detailed usage help when they need it rather than with every
+// tool listing. doc://tools lists them.
+func (r *Registry) RegisterDocs(mcpServer *server.MCPServer) {
+	defs := r.GetToolDefinitions()
+
+	mcpServer.AddResource(mcp.NewResource(docIndexURI, "Tool documentation index",
+		mcp.WithResourceDescription("Lists the tools with a one-line summary and the URI of each tool's documentation"),
+		mcp.WithMIMEType(docMIMEType),
+	), func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      docIndexURI,
+			MIMEType: docMIMEType,
+			Text:     docIndex(defs),
+		}}, nil
+	})
+
+	for _, def := range defs {
+		uri := docURIPrefix + def.Name
+		text := toolDoc(def)
+		mcpServer.AddResource(mcp.NewResource(uri, def.Name+" documentation",
+			mcp.WithResourceDescription("Parameters, examples and common errors for "+def.Name),
+			mcp.WithMIMEType(docMIMEType),
+		), func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: docMIMEType,
+				Text:     text,
+			}}, nil
+		})
+	}
+}
+
+// docIndex renders the list of documented tools
+func docIndex(defs []ToolDefinition) string {
+	var b strings.Builder
+	b.WriteString("# OpenStreetMap MCP tools\n\n")
+	for _, def := range defs {
+		fmt.Fprintf(&b, "- [%s](%s%s): %s\n", def.Name, docURIPrefix, def.Name, def.Description)
+	}
+	return b.String()
+}
+
+// toolDoc renders the documentation of a tool from its definition and
+// input schema
+func toolDoc(def ToolDefinition) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n", def.Name, def.Tool.Description)
+	fmt.Fprintf(&b, "Version: %d\n", def.Version)
+	if def.Deprecation != nil {
+		fmt.Fprintf(&b, "\nDeprecated since %s", def.Deprecation.Since)
+		if def.Deprecation.Replacement != "" {
+			fmt.Fprintf(&b, "; use %s instead", def.Deprecation.Replacement)
+		}
+		b.WriteString(".\n")
+	}
+
+	schema := def.Tool.InputSchema
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	b.WriteString("\n## Parameters\n\n")
+	names := docParamNames(schema.Properties, required)
+	if len(names) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, name := range names {
+		prop, _ := schema.Properties[name].(map[string]any)
+		fmt.Fprintf(&b, "- `%s` (%v", name, prop["type"])
+		if required[name] {
+			b.WriteString(", required")
+		}
+		b.WriteString(")")
+		if desc, _ := prop["description"].(string); desc != "" {
+			b.WriteString(": " + desc)
+		}
+		if enum, ok := prop["enum"]; ok {
+			fmt.Fprintf(&b, " One of: %v.", docJSON(enum))
+		}
+		if value, ok := prop["default"]; ok {
+			fmt.Fprintf(&b, " Default: %s.", docJSON(value))
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\n## Example\n\n```json\n%s\n```\n", docJSON(docExample(schema.Properties, required)))
+
+	b.WriteString("\n## Common errors\n\n")
+	for _, line := range docErrors(schema.Properties, schema.Required) {
+		b.WriteString("- " + line + "\n")
+	}
+	return b.String()
+}
+
+// docParamNames orders parameters with the required ones first
+func docParamNames(properties map[string]any, required map[string]bool) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if required[names[i]] != required[names[j]] {
+			return required[names[i]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// docExample builds example arguments from the required parameters, or from
+// the first known parameter when none are required
+func docExample(properties map[string]any, required map[string]bool) map[string]any {
+	example := make(map[string]any)
+	for _, name := range docParamNames(properties, required) {
+		if !required[name] {
+			continue
+		}
+		prop, _ := properties[name].(map[string]any)
+		example[name] = docExampleValue(name, prop)
+	}
+	if len(example) == 0 {
+		for _, name := range docParamNames(properties, required) {
+			if value, ok := exampleValues[name]; ok {
+				example[name] = value
+				break
+			}
+		}
+	}
+	return example
+}
+
+// docExampleValue picks a sample value for a parameter
+func docExampleValue(name string, prop map[string]any) any {
+	if value, ok := exampleValues[name]; ok {
+		return value
+	}
+	if value, ok := prop["default"]; ok {
+		return value
+	}
+	if enum, ok := prop["enum"].([]string); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	switch prop["type"] {
+	case "number", "integer":
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []any{}
+	case "object":
+		return map[string]any{}
+	}
+	return "..."
+}
+
+// docErrors lists the errors callers commonly hit with a tool's parameters
+func docErrors(properties map[string]any, required []string) []string {
+	var errs []string
+	if len(required) > 0 {
+		errs = append(errs, fmt.Sprintf("`%s`: %s is missing", core.ErrMissingParameter, strings.Join(required, ", ")))
+	}
+	if len(properties) > 0 {
+		errs = append(errs, fmt.Sprintf("`%s`: a value is out of range or malformed; the message names the parameter and the guidance gives the valid form", core.ErrInvalidParameter))
+	}
+	if _, ok := properties["latitude"]; ok {
+		errs = append(errs, "Coordinates must be decimal degrees, latitude -90 to 90 and longitude -180 to 180")
+	}
+	if _, ok := properties["radius"]; ok {
+		errs = append(errs, fmt.Sprintf("`%s`: radius is not positive or exceeds the tool's limit", core.ErrInvalidRadius))
+	}
+	errs = append(errs,
+		fmt.Sprintf("`%s` or `%s`: an upstream OpenStreetMap service failed or is rate limiting; retry later, see rate_limit_status", core.ErrServiceUnavailable, core.ErrRateLimit),
+		fmt.Sprintf("`%s` from transform: the JMESPath expression does not compile or does not fit the result", core.ErrInvalidParameter),
+	)
+	return errs
+}
+
+// docJSON formats a value for the documentation
+func docJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestToolDoc(t *testing.T) {
+	registry := NewRegistry(slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithFilter(func(name string) bool { return name == "reverse_geocode" })
+	defs := registry.GetToolDefinitions()
+	if len(defs) != 1 {
+		t.Fatalf("expected one tool, got %d", len(defs))
+	}
+
+	doc := toolDoc(defs[0])
+	for _, want := range []string{
+		"# reverse_geocode",
+		"- `latitude` (number, required)",
+		"- `transform` (string)",
+		`"latitude":51.5074`,
+		"`MISSING_PARAMETER`: latitude, longitude is missing",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("expected the doc to contain %q:\n%s", want, doc)
+		}
+	}
+	if !strings.Contains(docIndex(defs), "[reverse_geocode](doc://tools/reverse_geocode)") {
+		t.Errorf("unexpected index %q", docIndex(defs))
+	}
+}
+
+func TestRegisterDocs(t *testing.T) {
+	registry := NewRegistry(slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithFilter(func(name string) bool { return name == "geo_distance" || name == "geo_midpoint" })
+	srv := server.NewMCPServer("test", "1.0.0")
+	registry.RegisterDocs(srv)
+
+	message := `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"doc://tools/geo_midpoint"}}`
+	response := srv.HandleMessage(context.Background(), json.RawMessage(message))
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Result struct {
+			Contents []mcp.TextResourceContents `json:"contents"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Result.Contents) != 1 {
+		t.Fatalf("expected one content, got %s", data)
+	}
+	text := decoded.Result.Contents[0]
+	if text.MIMEType != docMIMEType || !strings.HasPrefix(text.Text, "# geo_midpoint") {
+		t.Errorf("unexpected contents %+v", decoded.Result.Contents[0])
+	}
+
+	message = `{"jsonrpc":"2.0","id":2,"method":"resources/list"}`
+	data, _ = json.Marshal(srv.HandleMessage(context.Background(), json.RawMessage(message)))
+	for _, uri := range []string{"doc://tools", "doc://tools/geo_distance", "doc://tools/geo_midpoint"} {
+		if !strings.Contains(string(data), `"`+uri+`"`) {
+			t.Errorf("expected %s to be listed in %s", uri, data)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// entranceBuildingRadius finds buildings next to a destination that lies
+	// just outside the mapped outline, in meters
+	entranceBuildingRadius = 15.0
+	// maxEntranceDistance ignores entrances further than this many meters
+	// from the destination, so a neighbouring building is never chosen
+	maxEntranceDistance = 250.0
+)
+
+// entranceRank orders the entrance tag values that are suitable route
+// destinations. Lower ranks are preferred; other values such as service,
+// emergency or exit are never chosen.
+var entranceRank = map[string]int{
+	"main": 0,
+	"yes":  1,
+}
+
+// BuildingEntrance is the building entrance a route was directed to instead
+// of the destination coordinates
+type BuildingEntrance struct {
+	ID       string   `json:"id"`   // OSM node, e.g. node/123
+	Type     string   `json:"type"` // entrance tag value: main or yes
+	Name     string   `json:"name,omitempty"`
+	Location Location `json:"location"`
+	Distance float64  `json:"distance"` // meters from the requested destination
+}
+
+// entranceDefault reports whether routes for an OSRM profile go to the
+// entrance by default. Only walkers need to reach the door; vehicles stop at
+// the road.
+func entranceDefault(profile string) bool {
+	return profile == "foot"
+}
+
+// resolveEntrance finds the preferred entrance of the building at a
+// destination. It returns false when the destination is not a building or the
+// building has no mapped entrance.
+func resolveEntrance(ctx context.Context, start, end geo.Location) (*BuildingEntrance, bool, error) {
+	elements, err := executeOverpassQuery(ctx, buildEntranceQuery(end.Latitude, end.Longitude))
+	if err != nil {
+		return nil, false, err
+	}
+	entrance, ok := chooseEntrance(elements, start, end)
+	return entrance, ok, nil
+}
+
+// buildEntranceQuery finds the entrance nodes on the outline of buildings
+// containing, or immediately next to, a point
+func buildEntranceQuery(lat, lon float64) string {
+	return fmt.Sprintf(`[out:json][timeout:25];
+is_in(%[1]f,%[2]f)->.areas;
+(way(pivot.areas)["building"];rel(pivot.areas)["building"];way["building"](around:%.1[3]f,%[1]f,%[2]f);)->.buildings;
+(way.buildings;way(r.buildings);)->.outlines;
+node(w.outlines)["entrance"];
+out;`, lat, lon, entranceBuildingRadius)
+}
+
+// chooseEntrance picks the best entrance near the destination: main entrances
+// before generic ones, then the entrance closest to the start of the route,
+// then by node ID so the choice is stable
+func chooseEntrance(elements []osm.OverpassElement, start, end geo.Location) (*BuildingEntrance, bool) {
+	type candidate struct {
+		entrance  BuildingEntrance
+		rank      int
+		fromStart float64
+	}
+
+	candidates := make([]candidate, 0, len(elements))
+	for _, element := range elements {
+		if element.Type != "node" {
+			continue
+		}
+		rank, ok := entranceRank[element.Tags["entrance"]]
+		if !ok {
+			continue
+		}
+		if element.Tags["access"] == "private" || element.Tags["access"] == "no" {
+			continue
+		}
+
+		distance := geo.HaversineDistance(end.Latitude, end.Longitude, element.Lat, element.Lon)
+		if distance > maxEntranceDistance {
+			continue
+		}
+
+		candidates = append(candidates, candidate{
+			entrance: BuildingEntrance{
+				ID:       fmt.Sprintf("node/%d", element.ID),
+				Type:     element.Tags["entrance"],
+				Name:     element.Tags["name"],
+				Location: Location{Latitude: element.Lat, Longitude: element.Lon},
+				Distance: distance,
+			},
+			rank:      rank,
+			fromStart: geo.HaversineDistance(start.Latitude, start.Longitude, element.Lat, element.Lon),
+		})
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].rank != candidates[j].rank {
+			return candidates[i].rank < candidates[j].rank
+		}
+		if candidates[i].fromStart != candidates[j].fromStart {
+			return candidates[i].fromStart < candidates[j].fromStart
+		}
+		return candidates[i].entrance.ID < candidates[j].entrance.ID
+	})
+
+	entrance := candidates[0].entrance
+	return &entrance, true
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func entranceNode(id int, lat, lon float64, tags map[string]string) osm.OverpassElement {
+	return osm.OverpassElement{ID: id, Type: "node", Lat: lat, Lon: lon, Tags: tags}
+}
+
+func TestChooseEntrance(t *testing.T) {
+	end := geo.Location{Latitude: 51.5000, Longitude: -0.1000}
+	start := geo.Location{Latitude: 51.5000, Longitude: -0.1100} // to the west
+
+	tests := []struct {
+		name     string
+		elements []osm.OverpassElement
+		wantID   string
+		wantOK   bool
+	}{
+		{
+			name: "main entrance preferred over closer generic entrance",
+			elements: []osm.OverpassElement{
+				entranceNode(1, 51.5000, -0.1004, map[string]string{"entrance": "yes"}),
+				entranceNode(2, 51.5000, -0.0996, map[string]string{"entrance": "main"}),
+			},
+			wantID: "node/2",
+			wantOK: true,
+		},
+		{
+			name: "entrance nearest the start wins among equals",
+			elements: []osm.OverpassElement{
+				entranceNode(1, 51.5000, -0.0996, map[string]string{"entrance": "yes"}),
+				entranceNode(2, 51.5000, -0.1004, map[string]string{"entrance": "yes"}),
+			},
+			wantID: "node/2",
+			wantOK: true,
+		},
+		{
+			name: "service, emergency and private entrances are skipped",
+			elements: []osm.OverpassElement{
+				entranceNode(1, 51.5000, -0.1004, map[string]string{"entrance": "service"}),
+				entranceNode(2, 51.5000, -0.1004, map[string]string{"entrance": "emergency"}),
+				entranceNode(3, 51.5000, -0.1004, map[string]string{"entrance": "main", "access": "private"}),
+			},
+			wantOK: false,
+		},
+		{
+			name: "distant entrances are ignored",
+			elements: []osm.OverpassElement{
+				entranceNode(1, 51.5100, -0.1000, map[string]string{"entrance": "main"}),
+			},
+			wantOK: false,
+		},
+		{
+			name:     "no entrances",
+			elements: nil,
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entrance, ok := chooseEntrance(tt.elements, start, end)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && entrance.ID != tt.wantID {
+				t.Errorf("entrance = %s, want %s", entrance.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestBuildEntranceQuery(t *testing.T) {
+	query := buildEntranceQuery(51.5, -0.1)
+
+	for _, want := range []string{
+		"is_in(51.500000,-0.100000)",
+		`way["building"](around:15.0,51.500000,-0.100000)`,
+		`node(w.outlines)["entrance"]`,
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+}
+
+func TestEntranceDefault(t *testing.T) {
+	if !entranceDefault("foot") {
+		t.Error("foot routes should go to the entrance by default")
+	}
+	if entranceDefault("car") || entranceDefault("bike") {
+		t.Error("vehicle routes should not go to the entrance by default")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
 )
 
 // APIError represents an error that occurred while communicating with
@@ -92,10 +94,8 @@ func ErrorWithGuidance(err *APIError) *mcp.CallToolResult {
 func ValidationError(lat, lon, radius float64, maxRadius float64) *APIError {
 	var message string
 
-	if lat < -90 || lat > 90 {
-		message = fmt.Sprintf("Invalid latitude value: %f (must be between -90 and 90)", lat)
-	} else if lon < -180 || lon > 180 {
-		message = fmt.Sprintf("Invalid longitude value: %f (must be between -180 and 180)", lon)
+	if err := geo.ValidateCoords(lat, lon); err != nil {
+		message = err.(geo.ValidationError).Message
 	} else if radius <= 0 {
 		message = "Radius must be greater than 0"
 	} else if radius > maxRadius {
@@ -156,6 +156,12 @@ func GetToolUsageExample(toolName string) string {
   "radius": 1000,
   "include_private": false,
   "limit": 10
+}`,
+		"parking_for_destination": `{
+  "destination": {"latitude": 40.7580, "longitude": -73.9855},
+  "origin": {"latitude": 40.7128, "longitude": -74.0060},
+  "max_walk_distance": 500,
+  "limit": 3
 }`,
 		"find_charging_stations": `{
   "latitude": 40.7128,
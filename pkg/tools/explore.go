@@ -3,6 +3,8 @@ package tools
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -10,15 +12,18 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/NERVsystems/osmmcp/pkg/cache"
 	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
 // ExploreAreaTool returns a tool definition for exploring an area
 func ExploreAreaTool() mcp.Tool {
+	limits := searchLimitsFor("explore_area")
 	return mcp.NewTool("explore_area",
 		mcp.WithDescription("Explore and describe an area based on its coordinates"),
 		mcp.WithNumber("latitude",
@@ -31,7 +36,7 @@ func ExploreAreaTool() mcp.Tool {
 		),
 		mcp.WithNumber("radius",
 			mcp.Required(),
-			mcp.Description("Search radius in meters (max 5000)"),
+			mcp.Description(limits.radiusDescription()),
 		),
 	)
 }
@@ -58,6 +63,7 @@ type NeighborhoodInfo struct {
 // HandleExploreArea implements area exploration functionality
 func HandleExploreArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logger := slog.Default().With("tool", "explore_area")
+	limits := searchLimitsFor("explore_area")
 
 	// Parse and validate coordinates
 	latStr := mcp.ParseString(req, "latitude", "")
@@ -99,7 +105,7 @@ func HandleExploreArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 		), nil
 	}
 
-	if err := ValidateCoordinates(lat, lon); err != nil {
+	if err := core.ValidateCoords(lat, lon); err != nil {
 		logger.Error("coordinate validation failed", "error", err)
 		return NewGeocodeDetailedError(
 			"INVALID_COORDINATES",
@@ -124,13 +130,13 @@ func HandleExploreArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 		}
 	}
 
-	if err := ValidateRadius(radius, 5000); err != nil {
+	if err := ValidateRadius(radius, limits.MaxRadius); err != nil {
 		logger.Error("radius validation failed", "radius", radius, "error", err)
 		return NewGeocodeDetailedError(
 			"INVALID_RADIUS",
 			err.Error(),
 			"",
-			"Radius must be positive and less than 5000 meters",
+			limits.radiusGuidance(),
 		), nil
 	}
 
@@ -139,15 +145,19 @@ func HandleExploreArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 		WithTimeout(25).
 		WithCenter(lat, lon, radius)
 
-	// Add various amenities to search for
-	queryBuilder.WithTag("amenity", "")
-	queryBuilder.WithTag("shop", "")
-	queryBuilder.WithTag("tourism", "")
-	queryBuilder.WithTag("leisure", "")
-	queryBuilder.WithTag("natural", "")
-	queryBuilder.WithTag("landuse", "park")
-	queryBuilder.WithTag("leisure", "park")
-	queryBuilder.WithTag("place", "")
+	// Add various amenities to search for, each its own statement so that
+	// elements with any of the tags match
+	for _, tag := range []core.TagFilter{
+		{Key: "amenity"},
+		{Key: "shop"},
+		{Key: "tourism"},
+		{Key: "leisure"},
+		{Key: "natural"},
+		{Key: "landuse", Values: []string{"park"}},
+		{Key: "place"},
+	} {
+		queryBuilder.WithNode(tag).WithWay(tag).WithRelation(tag)
+	}
 
 	// Execute the query
 	elements, err := executeOverpassQuery(ctx, queryBuilder.Build())
@@ -327,8 +337,25 @@ func HandleExploreArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
-// executeOverpassQuery executes an Overpass API query and returns the elements
+// executeOverpassQuery executes an Overpass API query and returns the
+// elements. Responses are cached for the POI TTL, so identical queries
+// repeated within it see identical results, which keeps pagination cursors
+// stable.
 func executeOverpassQuery(ctx context.Context, query string) ([]osm.OverpassElement, error) {
+	sum := sha256.Sum256([]byte(query))
+	cacheKey := "overpass:" + hex.EncodeToString(sum[:])
+	cache.RecordQuery(ctx, "global", cache.GetGlobalCache(), cacheKey, func(ctx context.Context) error {
+		_, err := executeOverpassQuery(ctx, query)
+		return err
+	})
+	if cached, found := cache.GetGlobalCache().Get(cacheKey); found && !cache.IsRefresh(ctx) {
+		if entry, ok := cached.(overpassCacheEntry); ok {
+			osm.RecordDataTimestamp(ctx, entry.Timestamp)
+			// Copy so callers cannot reorder the cached slice
+			return append([]osm.OverpassElement(nil), entry.Elements...), nil
+		}
+	}
+
 	// Build request
 	reqURL, err := url.Parse(osm.OverpassBaseURL)
 	if err != nil {
@@ -355,6 +382,11 @@ func executeOverpassQuery(ctx context.Context, query string) ([]osm.OverpassElem
 	client := osm.GetClient(ctx)
 	resp, err := core.WithRetryFactory(ctx, requestFactory, client, core.DefaultRetryOptions)
 	if err != nil {
+		// Keep the wait Overpass asked for, so clients can decide whether
+		// to wait or narrow the query
+		if mcpErr, ok := err.(*core.MCPError); ok && mcpErr.RetryAfterSeconds > 0 {
+			return nil, mcpErr
+		}
 		return nil, core.ServiceError("Overpass", http.StatusServiceUnavailable, "Failed to communicate with OSM service")
 	}
 	defer resp.Body.Close()
@@ -366,12 +398,25 @@ func executeOverpassQuery(ctx context.Context, query string) ([]osm.OverpassElem
 
 	// Parse response
 	var overpassResp struct {
+		Osm3s struct {
+			TimestampOsmBase string `json:"timestamp_osm_base"`
+		} `json:"osm3s"`
 		Elements []osm.OverpassElement `json:"elements"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
-		return nil, core.NewError(core.ErrParseError, "Failed to parse area data")
+	if err := core.DecodeUpstream("Overpass", resp.Body, &overpassResp, "elements"); err != nil {
+		return nil, err
 	}
 
-	return overpassResp.Elements, nil
+	entry := overpassCacheEntry{Elements: overpassResp.Elements}
+	entry.Timestamp, _ = time.Parse(time.RFC3339, overpassResp.Osm3s.TimestampOsmBase)
+	cache.GetGlobalCache().SetFor(cache.ClassPOI, cacheKey, entry)
+	return append([]osm.OverpassElement(nil), overpassResp.Elements...), nil
+}
+
+// overpassCacheEntry is a cached Overpass response: its elements and the
+// timestamp of their data, reported again on cache hits
+type overpassCacheEntry struct {
+	Elements  []osm.OverpassElement
+	Timestamp time.Time
 }
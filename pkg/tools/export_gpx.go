@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// gpxNamespace is the GPX 1.1 schema namespace
+	gpxNamespace = "http://www.topografix.com/GPX/1/1"
+	// maxGPXPoints bounds the points in an exported document
+	maxGPXPoints = 50000
+)
+
+// gpxDocument is a GPX 1.1 document. Elements are declared in the order
+// the schema requires.
+type gpxDocument struct {
+	XMLName  xml.Name    `xml:"gpx"`
+	Version  string      `xml:"version,attr"`
+	Creator  string      `xml:"creator,attr"`
+	XMLNS    string      `xml:"xmlns,attr"`
+	Metadata gpxMetadata `xml:"metadata"`
+	Wpts     []gpxPoint  `xml:"wpt"`
+	Rte      *gpxRoute   `xml:"rte,omitempty"`
+	Trk      *gpxTrack   `xml:"trk,omitempty"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name,omitempty"`
+	Time string `xml:"time"`
+}
+
+// gpxPoint is a wpt, rtept or trkpt
+type gpxPoint struct {
+	Lat  string   `xml:"lat,attr"`
+	Lon  string   `xml:"lon,attr"`
+	Ele  *float64 `xml:"ele,omitempty"`
+	Name string   `xml:"name,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxTrack struct {
+	Name     string            `xml:"name,omitempty"`
+	Segments []gpxTrackSegment `xml:"trkseg"`
+}
+
+type gpxTrackSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// ExportGPXTool returns a tool definition for exporting GPX documents
+func ExportGPXTool() mcp.Tool {
+	return mcp.NewTool("export_gpx",
+		mcp.WithDescription("Convert a route polyline (e.g. from route_fetch or get_route_directions) or a list of waypoints into a GPX 1.1 document, returned as text, for loading into GPS devices and mapping apps"),
+		mcp.WithString("polyline",
+			mcp.Description("Encoded polyline of the route or track"),
+		),
+		mcp.WithArray("waypoints",
+			mcp.Description("Waypoints, each with latitude, longitude and optional name and elevation (meters). Exported as wpt elements, and as the route or track itself when no polyline is given"),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the document and of its route or track"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Export the line as a track (trk, recorded path), a route (rte, points to navigate through) or only waypoints"),
+			mcp.Enum("track", "route", "waypoints"),
+			mcp.DefaultString("track"),
+		),
+	)
+}
+
+// HandleExportGPX converts a polyline or waypoints to GPX
+func HandleExportGPX(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "export_gpx")
+
+	kind := mcp.ParseString(req, "kind", "track")
+	if kind != "track" && kind != "route" && kind != "waypoints" {
+		return core.NewError(core.ErrInvalidParameter, "kind must be track, route or waypoints").ToMCPResult(), nil
+	}
+
+	waypoints, errResult := parseGPXWaypoints(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	var line []gpxPoint
+	if polyline := mcp.ParseString(req, "polyline", ""); polyline != "" {
+		if !isPrintableASCII(polyline) {
+			return core.NewError(core.ErrInvalidParameter, "Failed to decode polyline: malformed input").ToMCPResult(), nil
+		}
+		points := osm.DecodePolyline(polyline)
+		if len(points) == 0 {
+			return core.NewError(core.ErrInvalidParameter, "Failed to decode polyline: malformed input").ToMCPResult(), nil
+		}
+		for _, point := range points {
+			line = append(line, newGPXPoint(point.Latitude, point.Longitude))
+		}
+	}
+
+	if len(line) == 0 && len(waypoints) == 0 {
+		return core.NewError(core.ErrMissingParameter, "polyline or waypoints is required").
+			WithGuidance("Pass the encoded polyline from route_fetch, or waypoints as [{\"latitude\": 51.5, \"longitude\": -0.12, \"name\": \"Start\"}, ...]").
+			ToMCPResult(), nil
+	}
+	if len(line)+len(waypoints) > maxGPXPoints {
+		return core.NewError(core.ErrInvalidInput, fmt.Sprintf("At most %d points can be exported", maxGPXPoints)).ToMCPResult(), nil
+	}
+
+	name := mcp.ParseString(req, "name", "")
+	doc := buildGPX(name, kind, line, waypoints, core.Now())
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		logger.Error("failed to encode GPX", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate GPX").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(xml.Header + string(data) + "\n"), nil
+}
+
+// parseGPXWaypoints reads the waypoints parameter
+func parseGPXWaypoints(req mcp.CallToolRequest) ([]gpxPoint, *mcp.CallToolResult) {
+	raw, _ := req.GetArguments()["waypoints"].([]any)
+	waypoints := make([]gpxPoint, 0, len(raw))
+	for i, item := range raw {
+		obj, _ := item.(map[string]any)
+		lat, latOK := obj["latitude"].(float64)
+		lon, lonOK := obj["longitude"].(float64)
+		if !latOK || !lonOK || core.ValidateCoords(lat, lon) != nil {
+			return nil, core.NewError(core.ErrInvalidParameter,
+				fmt.Sprintf("waypoints[%d] must have a valid latitude and longitude", i)).ToMCPResult()
+		}
+		point := newGPXPoint(lat, lon)
+		point.Name, _ = obj["name"].(string)
+		if ele, ok := obj["elevation"].(float64); ok {
+			point.Ele = &ele
+		}
+		waypoints = append(waypoints, point)
+	}
+	return waypoints, nil
+}
+
+// buildGPX assembles a document with line as its route or track and the
+// waypoints as wpt elements. Without a line the waypoints are the route or
+// track.
+func buildGPX(name, kind string, line, waypoints []gpxPoint, now time.Time) gpxDocument {
+	doc := gpxDocument{
+		Version:  "1.1",
+		Creator:  "osmmcp",
+		XMLNS:    gpxNamespace,
+		Metadata: gpxMetadata{Name: name, Time: now.UTC().Format(time.RFC3339)},
+	}
+
+	if len(line) == 0 {
+		line, waypoints = waypoints, nil
+	}
+	switch kind {
+	case "waypoints":
+		doc.Wpts = append(line, waypoints...)
+	case "route":
+		doc.Rte = &gpxRoute{Name: name, Points: line}
+		doc.Wpts = waypoints
+	default:
+		doc.Trk = &gpxTrack{Name: name, Segments: []gpxTrackSegment{{Points: line}}}
+		doc.Wpts = waypoints
+	}
+	return doc
+}
+
+// newGPXPoint formats coordinates to about 1 cm
+func newGPXPoint(lat, lon float64) gpxPoint {
+	return gpxPoint{
+		Lat: strconv.FormatFloat(lat, 'f', 7, 64),
+		Lon: strconv.FormatFloat(lon, 'f', 7, 64),
+	}
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func exportGPX(t *testing.T, args map[string]any) (string, bool) {
+	t.Helper()
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "export_gpx", Arguments: args}}
+	result, err := HandleExportGPX(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.Content[0].(mcp.TextContent).Text, result.IsError
+}
+
+func TestHandleExportGPX(t *testing.T) {
+	// Polyline for (38.5, -120.2), (40.7, -120.95), (43.252, -126.453)
+	text, isError := exportGPX(t, map[string]any{
+		"polyline":  "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+		"name":      "Test route",
+		"waypoints": []any{map[string]any{"latitude": 38.5, "longitude": -120.2, "name": "Start", "elevation": 120.5}},
+	})
+	if isError {
+		t.Fatalf("export failed: %s", text)
+	}
+
+	var doc gpxDocument
+	if err := xml.Unmarshal([]byte(text), &doc); err != nil {
+		t.Fatalf("invalid GPX %s: %v", text, err)
+	}
+	if doc.Version != "1.1" || !strings.Contains(text, `xmlns="http://www.topografix.com/GPX/1/1"`) {
+		t.Errorf("expected a GPX 1.1 document, got %s", text)
+	}
+	if doc.Trk == nil || len(doc.Trk.Segments) != 1 || len(doc.Trk.Segments[0].Points) != 3 {
+		t.Fatalf("expected a track of three points, got %s", text)
+	}
+	if doc.Trk.Segments[0].Points[2].Lat != "43.2520000" || doc.Trk.Name != "Test route" {
+		t.Errorf("unexpected track %+v", doc.Trk)
+	}
+	if len(doc.Wpts) != 1 || doc.Wpts[0].Name != "Start" || doc.Wpts[0].Ele == nil || *doc.Wpts[0].Ele != 120.5 {
+		t.Errorf("unexpected waypoints %+v", doc.Wpts)
+	}
+
+	// Without a polyline the waypoints form the route
+	text, isError = exportGPX(t, map[string]any{
+		"kind": "route",
+		"waypoints": []any{
+			map[string]any{"latitude": 51.5, "longitude": -0.12},
+			map[string]any{"latitude": 51.51, "longitude": -0.1},
+		},
+	})
+	if isError {
+		t.Fatalf("export failed: %s", text)
+	}
+	doc = gpxDocument{}
+	if err := xml.Unmarshal([]byte(text), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Rte == nil || len(doc.Rte.Points) != 2 || len(doc.Wpts) != 0 || doc.Trk != nil {
+		t.Errorf("expected a two point route only, got %s", text)
+	}
+}
+
+func TestHandleExportGPXErrors(t *testing.T) {
+	for name, args := range map[string]map[string]any{
+		"Missing input":    {},
+		"Invalid waypoint": {"waypoints": []any{map[string]any{"latitude": 91.0, "longitude": 0.0}}},
+		"Invalid kind":     {"polyline": "_p~iF~ps|U", "kind": "area"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if text, isError := exportGPX(t, args); !isError {
+				t.Errorf("expected an error, got %s", text)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// maxSelectedFields limits the number of paths in a fields parameter
+const maxSelectedFields = 50
+
+// fieldSelectionTargets maps the tools that accept a fields parameter to the
+// key holding the records the selection applies to. An empty key selects
+// from the top-level result; other top-level keys are returned unchanged.
+var fieldSelectionTargets = map[string]string{
+	// POI tools
+	"find_nearby_places":      "places",
+	"search_places_by_name":   "places",
+	"explore_area":            "top_places",
+	"find_parking_facilities": "facilities",
+	"parking_for_destination": "facilities",
+	"find_charging_stations":  "charging_stations",
+	"find_schools_nearby":     "schools",
+
+	// Routing tools
+	"route_fetch":          "",
+	"get_route_directions": "",
+	"route_narrative":      "steps",
+	"optimize_stops":       "stops",
+}
+
+// withFieldSelection adds the fields parameter to the tools listed in
+// fieldSelectionTargets and wraps their handlers to apply it
+func withFieldSelection(defs []ToolDefinition) []ToolDefinition {
+	for i, def := range defs {
+		target, ok := fieldSelectionTargets[def.Name]
+		if !ok {
+			continue
+		}
+
+		description := "Only return these fields of each result, e.g. [\"name\", \"location\", \"distance\"]. Use dots for nested fields such as location.latitude"
+		if target == "" {
+			description = "Only return these top-level fields, e.g. [\"distance\", \"duration\"]. Use dots for nested fields"
+		}
+		mcp.WithArray("fields", mcp.Description(description), mcp.WithStringItems())(&defs[i].Tool)
+		defs[i].Handler = selectFieldsHandler(target, def.Handler)
+	}
+	return defs
+}
+
+// selectFieldsHandler wraps a handler to trim its JSON result to the
+// requested fields
+func selectFieldsHandler(target string, handler func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fields, err := parseFields(req)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance("Pass fields as an array of field names such as [\"name\", \"location\"]").
+				ToMCPResult(), nil
+		}
+
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError || len(fields) == 0 {
+			return result, err
+		}
+		return selectResultFields(result, target, fields), nil
+	}
+}
+
+// parseFields reads the optional fields argument, given as an array of
+// strings or a comma-separated string
+func parseFields(req mcp.CallToolRequest) ([]string, error) {
+	args, ok := req.Params.Arguments.(map[string]any)
+	if !ok || args["fields"] == nil {
+		return nil, nil
+	}
+
+	var raw []string
+	switch v := args["fields"].(type) {
+	case string:
+		raw = strings.Split(v, ",")
+	case []string:
+		raw = v
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("fields must be strings")
+			}
+			raw = append(raw, s)
+		}
+	default:
+		return nil, fmt.Errorf("fields must be an array of strings")
+	}
+
+	fields := make([]string, 0, len(raw))
+	for _, field := range raw {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) > maxSelectedFields {
+		return nil, fmt.Errorf("at most %d fields can be selected", maxSelectedFields)
+	}
+	return fields, nil
+}
+
+// fieldTree is a set of selected field paths, keyed by path segment. A
+// segment with no children selects the whole value.
+type fieldTree map[string]fieldTree
+
+// newFieldTree builds a tree from dotted field paths
+func newFieldTree(fields []string) fieldTree {
+	tree := make(fieldTree)
+	for _, field := range fields {
+		node := tree
+		segments := strings.Split(field, ".")
+		for i, segment := range segments {
+			child, ok := node[segment]
+			if ok && len(child) == 0 {
+				// A shorter path already selects the whole value
+				break
+			}
+			if !ok || i == len(segments)-1 {
+				child = make(fieldTree)
+				node[segment] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// selectResultFields returns a copy of a text result trimmed to the selected
+// fields. Results that are not JSON objects are returned unchanged.
+func selectResultFields(result *mcp.CallToolResult, target string, fields []string) *mcp.CallToolResult {
+	if len(result.Content) != 1 {
+		return result
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return result
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(text.Text)))
+	decoder.UseNumber()
+	var root map[string]any
+	if err := decoder.Decode(&root); err != nil {
+		return result
+	}
+
+	tree := newFieldTree(fields)
+	var selected any
+	if target == "" {
+		selected = selectFields(root, tree)
+	} else {
+		if records, ok := root[target]; ok {
+			root[target] = selectFields(records, tree)
+		}
+		selected = root
+	}
+
+	data, err := json.Marshal(selected)
+	if err != nil {
+		return result
+	}
+	return mcp.NewToolResultText(string(data))
+}
+
+// selectFields keeps only the selected fields of an object, or of each
+// object in an array
+func selectFields(value any, tree fieldTree) any {
+	if len(tree) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		selected := make(map[string]any, len(tree))
+		for key, child := range tree {
+			if field, ok := v[key]; ok {
+				selected[key] = selectFields(field, child)
+			}
+		}
+		return selected
+	case []any:
+		selected := make([]any, len(v))
+		for i, item := range v {
+			selected[i] = selectFields(item, tree)
+		}
+		return selected
+	default:
+		return value
+	}
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewFieldTree(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   fieldTree
+	}{
+		{[]string{"name", "location.latitude"}, fieldTree{"name": {}, "location": {"latitude": {}}}},
+		{[]string{"location", "location.latitude"}, fieldTree{"location": {}}},
+		{[]string{"location.latitude", "location"}, fieldTree{"location": {}}},
+		{[]string{"a.b.c", "a.d"}, fieldTree{"a": {"b": {"c": {}}, "d": {}}}},
+	}
+	for _, tt := range tests {
+		if got := newFieldTree(tt.fields); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("newFieldTree(%v) = %v, want %v", tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestSelectResultFields(t *testing.T) {
+	result := mcp.NewToolResultText(`{"count":2,"places":[{"id":"1","name":"A","distance":10,"location":{"latitude":1.5,"longitude":2.5},"tags":{"x":"y"}},{"id":"2","name":"B","distance":20}]}`)
+
+	selected := selectResultFields(result, "places", []string{"name", "location.latitude"})
+	got := selected.Content[0].(mcp.TextContent).Text
+	want := `{"count":2,"places":[{"location":{"latitude":1.5},"name":"A"},{"name":"B"}]}`
+	if got != want {
+		t.Errorf("selectResultFields() = %s, want %s", got, want)
+	}
+
+	// Top-level selection drops everything else
+	selected = selectResultFields(mcp.NewToolResultText(`{"distance":1234.5,"duration":99,"polyline":"abc"}`), "", []string{"distance"})
+	if got := selected.Content[0].(mcp.TextContent).Text; got != `{"distance":1234.5}` {
+		t.Errorf("unexpected top-level selection %s", got)
+	}
+
+	// Non-JSON results are returned unchanged
+	plain := mcp.NewToolResultText("not json")
+	if selectResultFields(plain, "", []string{"a"}) != plain {
+		t.Error("expected non-JSON result to be returned unchanged")
+	}
+}
+
+func TestWithFieldSelection(t *testing.T) {
+	defs := NewRegistry(slog.Default()).GetToolDefinitions()
+	for _, def := range defs {
+		_, selectable := fieldSelectionTargets[def.Name]
+		_, hasParam := def.Tool.InputSchema.Properties["fields"]
+		if selectable != hasParam {
+			t.Errorf("tool %s: fields parameter %v, want %v", def.Name, hasParam, selectable)
+		}
+	}
+
+	handler := selectFieldsHandler("", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"distance":10,"duration":5}`), nil
+	})
+
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{"fields": "duration, distance"}}}
+	result, err := handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %v", err, result)
+	}
+	var output map[string]any
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil || len(output) != 2 {
+		t.Errorf("unexpected output %v", output)
+	}
+
+	req.Params.Arguments = map[string]any{"fields": []any{"distance", 3}}
+	result, err = handler(context.Background(), req)
+	if err != nil || !result.IsError {
+		t.Error("expected an error result for non-string fields")
+	}
+}
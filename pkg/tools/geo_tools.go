@@ -5,28 +5,51 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/geo"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
+// routedDistanceModes are the OSRM profiles geo_distance routes with when
+// asked for road distances
+var routedDistanceModes = []string{"car", "foot"}
+
 // GeoDistanceInput defines the input parameters for calculating distance
 type GeoDistanceInput struct {
-	From geo.Location `json:"from"`
-	To   geo.Location `json:"to"`
+	From   geo.Location `json:"from"`
+	To     geo.Location `json:"to"`
+	Units  string       `json:"units,omitempty"`
+	Routed bool         `json:"routed,omitempty"`
 }
 
 // GeoDistanceOutput defines the output for distance calculation
 type GeoDistanceOutput struct {
-	Distance float64 `json:"distance"` // in meters
+	Distance float64            `json:"distance"` // in meters
+	Units    *ConvertedMeasures `json:"units,omitempty"`
+	Routes   []RoutedDistance   `json:"routes,omitempty"` // when routed
+}
+
+// RoutedDistance is the distance and duration of the route between two
+// points for one travel mode
+type RoutedDistance struct {
+	Mode     string  `json:"mode"`               // car or foot
+	Distance float64 `json:"distance,omitempty"` // along the route, in meters
+	Duration float64 `json:"duration,omitempty"` // in seconds
+	// DetourFactor is the route distance over the great-circle distance
+	DetourFactor float64            `json:"detour_factor,omitempty"`
+	Units        *ConvertedMeasures `json:"units,omitempty"`
+	// Error says why there is no route for this mode
+	Error string `json:"error,omitempty"`
 }
 
 // GeoDistanceTool returns a tool definition for calculating geographic distance
 func GeoDistanceTool() mcp.Tool {
 	return mcp.NewTool("geo_distance",
-		mcp.WithDescription("Calculate the distance between two geographic coordinates using the Haversine formula"),
+		mcp.WithDescription("Calculate the distance between two geographic coordinates using the Haversine formula, optionally alongside the driving and walking distance by road"),
 		mcp.WithObject("from",
 			mcp.Required(),
 			mcp.Description("The starting point as {latitude, longitude}"),
@@ -35,6 +58,13 @@ func GeoDistanceTool() mcp.Tool {
 			mcp.Required(),
 			mcp.Description("The ending point as {latitude, longitude}"),
 		),
+		mcp.WithString("units",
+			mcp.Description(unitsDescription),
+		),
+		mcp.WithBoolean("routed",
+			mcp.Description("Also return the driving and walking distance and duration along roads, from OSRM"),
+			mcp.DefaultBool(false),
+		),
 	)
 }
 
@@ -56,24 +86,12 @@ func HandleGeoDistance(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	}
 
 	// Validate input coordinates
-	if input.From.Latitude == 0 && input.From.Longitude == 0 {
-		logger.Error("missing 'from' coordinates")
-		return ErrorResponse("Missing 'from' coordinates"), nil
+	if errResult := validateFromTo(input.From, input.To, logger); errResult != nil {
+		return errResult, nil
 	}
 
-	if input.To.Latitude == 0 && input.To.Longitude == 0 {
-		logger.Error("missing 'to' coordinates")
-		return ErrorResponse("Missing 'to' coordinates"), nil
-	}
-
-	if err := osm.ValidateCoords(input.From.Latitude, input.From.Longitude); err != nil {
-		logger.Error("invalid 'from' coordinates", "error", err)
-		return ErrorResponse(fmt.Sprintf("Invalid 'from' coordinates: %s", err)), nil
-	}
-
-	if err := osm.ValidateCoords(input.To.Latitude, input.To.Longitude); err != nil {
-		logger.Error("invalid 'to' coordinates", "error", err)
-		return ErrorResponse(fmt.Sprintf("Invalid 'to' coordinates: %s", err)), nil
+	if !validUnits(input.Units) {
+		return ErrorResponse(fmt.Sprintf("Invalid units: %s (use metric, imperial or nautical)", input.Units)), nil
 	}
 
 	// Calculate distance using Haversine formula
@@ -85,6 +103,12 @@ func HandleGeoDistance(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	// Create output
 	output := GeoDistanceOutput{
 		Distance: distance,
+		Units:    convertMeasures(input.Units, distance, 0),
+	}
+	if input.Routed {
+		for _, mode := range routedDistanceModes {
+			output.Routes = append(output.Routes, routeDistance(ctx, input.From, input.To, mode, distance, input.Units))
+		}
 	}
 
 	// Return result
@@ -97,6 +121,39 @@ func HandleGeoDistance(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// routeDistance routes between two points with an OSRM profile. Failures
+// are reported in the result, so the great-circle distance is still
+// returned when routing is unavailable.
+func routeDistance(ctx context.Context, from, to geo.Location, mode string, greatCircle float64, units string) RoutedDistance {
+	routed := RoutedDistance{Mode: mode}
+
+	// Only the distance and duration are needed, not the geometry
+	options := routeFetchOptions(mode)
+	options.Overview = "false"
+	result, err := core.GetRoute(ctx, [][]float64{{from.Longitude, from.Latitude}, {to.Longitude, to.Latitude}}, options)
+	if err != nil {
+		slog.Default().With("tool", "geo_distance").Warn("failed to get route", "mode", mode, "error", err)
+		routed.Error = "routing service unavailable"
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			routed.Error = mcpErr.Message
+		}
+		return routed
+	}
+	if len(result.Routes) == 0 {
+		routed.Error = "no route found"
+		return routed
+	}
+
+	route := result.Routes[0]
+	routed.Distance = route.Distance
+	routed.Duration = route.Duration
+	if greatCircle > 0 {
+		routed.DetourFactor = math.Round(route.Distance/greatCircle*100) / 100
+	}
+	routed.Units = convertMeasures(units, route.Distance, route.Duration)
+	return routed
+}
+
 // BBoxFromPointsInput defines the input parameters for creating a bounding box
 type BBoxFromPointsInput struct {
 	Points []geo.Location `json:"points"`
@@ -150,7 +207,7 @@ func HandleBBoxFromPoints(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 			return ErrorResponse(fmt.Sprintf("Missing coordinates at index %d", i)), nil
 		}
 
-		if err := osm.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+		if err := geo.ValidateCoords(p.Latitude, p.Longitude); err != nil {
 			logger.Error("invalid coordinates", "error", err, "index", i)
 			return ErrorResponse(fmt.Sprintf("Invalid coordinates at index %d: %s", i, err)), nil
 		}
@@ -225,7 +282,7 @@ func HandleCentroidPoints(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 			return ErrorResponse(fmt.Sprintf("Missing coordinates at index %d", i)), nil
 		}
 
-		if err := osm.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+		if err := geo.ValidateCoords(p.Latitude, p.Longitude); err != nil {
 			logger.Error("invalid coordinates", "error", err, "index", i)
 			return ErrorResponse(fmt.Sprintf("Invalid coordinates at index %d: %s", i, err)), nil
 		}
@@ -252,3 +309,444 @@ func HandleCentroidPoints(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
+
+const (
+	defaultGreatCirclePoints = 64
+	maxGreatCirclePoints     = 1000
+)
+
+// GreatCirclePathInput defines the input parameters for great_circle_path
+type GreatCirclePathInput struct {
+	From   geo.Location `json:"from"`
+	To     geo.Location `json:"to"`
+	Points int          `json:"points,omitempty"`
+	Units  string       `json:"units,omitempty"`
+}
+
+// GreatCirclePathOutput defines the output for great_circle_path
+type GreatCirclePathOutput struct {
+	Points         []geo.Location     `json:"points"`
+	Distance       float64            `json:"distance"`        // in meters
+	InitialBearing float64            `json:"initial_bearing"` // degrees clockwise from north
+	FinalBearing   float64            `json:"final_bearing"`   // degrees clockwise from north
+	Units          *ConvertedMeasures `json:"units,omitempty"`
+}
+
+// GreatCirclePathTool returns a tool definition for sampling a great circle path
+func GreatCirclePathTool() mcp.Tool {
+	return mcp.NewTool("great_circle_path",
+		mcp.WithDescription("Generate evenly spaced points along the great circle (shortest path over the Earth's surface) between two coordinates, with distance and initial/final bearings. Useful for flight paths and long-distance lines on maps"),
+		mcp.WithObject("from",
+			mcp.Required(),
+			mcp.Description("The starting point as {latitude, longitude}"),
+		),
+		mcp.WithObject("to",
+			mcp.Required(),
+			mcp.Description("The ending point as {latitude, longitude}"),
+		),
+		mcp.WithString("units",
+			mcp.Description(unitsDescription),
+		),
+		mcp.WithNumber("points",
+			mcp.Description("Number of points to return, including both endpoints (2-1000)"),
+			mcp.DefaultNumber(defaultGreatCirclePoints),
+		),
+	)
+}
+
+// HandleGreatCirclePath implements great circle path sampling
+func HandleGreatCirclePath(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "great_circle_path")
+
+	// Parse input
+	var input GreatCirclePathInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if errResult := validateFromTo(input.From, input.To, logger); errResult != nil {
+		return errResult, nil
+	}
+
+	if !validUnits(input.Units) {
+		return ErrorResponse(fmt.Sprintf("Invalid units: %s (use metric, imperial or nautical)", input.Units)), nil
+	}
+
+	if input.Points == 0 {
+		input.Points = defaultGreatCirclePoints
+	}
+	if input.Points < 2 || input.Points > maxGreatCirclePoints {
+		return ErrorResponse(fmt.Sprintf("Points must be between 2 and %d", maxGreatCirclePoints)), nil
+	}
+
+	if geo.IsAntipodal(input.From, input.To) {
+		return ErrorResponse("The points are antipodal, so every great circle through them is equally short"), nil
+	}
+
+	output := GreatCirclePathOutput{
+		Points:         geo.GreatCirclePath(input.From, input.To, input.Points),
+		Distance:       geo.HaversineDistance(input.From.Latitude, input.From.Longitude, input.To.Latitude, input.To.Longitude),
+		InitialBearing: geo.InitialBearing(input.From, input.To),
+		FinalBearing:   geo.FinalBearing(input.From, input.To),
+	}
+	output.Units = convertMeasures(input.Units, output.Distance, 0)
+
+	// Return result
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return ErrorResponse("Failed to generate result"), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// GeoMidpointOutput defines the output for geo_midpoint
+type GeoMidpointOutput struct {
+	Midpoint geo.Location       `json:"midpoint"`
+	Distance float64            `json:"distance"` // between the two points, in meters
+	Units    *ConvertedMeasures `json:"units,omitempty"`
+}
+
+// GeoMidpointTool returns a tool definition for the great circle midpoint
+func GeoMidpointTool() mcp.Tool {
+	return mcp.NewTool("geo_midpoint",
+		mcp.WithDescription("Calculate the midpoint along the great circle between two geographic coordinates"),
+		mcp.WithObject("from",
+			mcp.Required(),
+			mcp.Description("The first point as {latitude, longitude}"),
+		),
+		mcp.WithObject("to",
+			mcp.Required(),
+			mcp.Description("The second point as {latitude, longitude}"),
+		),
+		mcp.WithString("units",
+			mcp.Description(unitsDescription),
+		),
+	)
+}
+
+// HandleGeoMidpoint implements great circle midpoint calculation
+func HandleGeoMidpoint(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "geo_midpoint")
+
+	// Parse input
+	var input GeoDistanceInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if errResult := validateFromTo(input.From, input.To, logger); errResult != nil {
+		return errResult, nil
+	}
+
+	if !validUnits(input.Units) {
+		return ErrorResponse(fmt.Sprintf("Invalid units: %s (use metric, imperial or nautical)", input.Units)), nil
+	}
+
+	if geo.IsAntipodal(input.From, input.To) {
+		return ErrorResponse("The points are antipodal, so there is no unique midpoint"), nil
+	}
+
+	output := GeoMidpointOutput{
+		Midpoint: geo.Midpoint(input.From, input.To),
+		Distance: geo.HaversineDistance(input.From.Latitude, input.From.Longitude, input.To.Latitude, input.To.Longitude),
+	}
+	output.Units = convertMeasures(input.Units, output.Distance, 0)
+
+	// Return result
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return ErrorResponse("Failed to generate result"), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// AntipodeInput defines the input parameters for antipode
+type AntipodeInput struct {
+	Point geo.Location `json:"point"`
+}
+
+// AntipodeOutput defines the output for antipode
+type AntipodeOutput struct {
+	Antipode geo.Location `json:"antipode"`
+}
+
+// AntipodeTool returns a tool definition for the antipode of a point
+func AntipodeTool() mcp.Tool {
+	return mcp.NewTool("antipode",
+		mcp.WithDescription("Calculate the antipode: the point on the exact opposite side of the Earth"),
+		mcp.WithObject("point",
+			mcp.Required(),
+			mcp.Description("The point as {latitude, longitude}"),
+		),
+	)
+}
+
+// HandleAntipode implements antipode calculation
+func HandleAntipode(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "antipode")
+
+	// Parse input
+	var input AntipodeInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if err := geo.ValidateCoords(input.Point.Latitude, input.Point.Longitude); err != nil {
+		logger.Error("invalid coordinates", "error", err)
+		return ErrorResponse(fmt.Sprintf("Invalid coordinates: %s", err)), nil
+	}
+
+	output := AntipodeOutput{
+		Antipode: geo.Antipode(input.Point),
+	}
+
+	// Return result
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return ErrorResponse("Failed to generate result"), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// maxAreaPoints caps the points of a geo_area polygon
+const maxAreaPoints = 50000
+
+// GeoAreaInput defines the input parameters for geo_area
+type GeoAreaInput struct {
+	Points   []geo.Location `json:"points,omitempty"`
+	Polyline string         `json:"polyline,omitempty"`
+	Geometry *AreaGeometry  `json:"geometry,omitempty"`
+	Units    string         `json:"units,omitempty"`
+}
+
+// GeoAreaOutput defines the output for geo_area
+type GeoAreaOutput struct {
+	Area         float64            `json:"area"` // in square meters, holes excluded
+	AreaHectares float64            `json:"area_hectares"`
+	AreaKm2      float64            `json:"area_km2"`
+	Perimeter    float64            `json:"perimeter"` // in meters, holes included
+	Polygons     int                `json:"polygons"`
+	Holes        int                `json:"holes,omitempty"`
+	Units        *ConvertedMeasures `json:"units,omitempty"` // the perimeter
+}
+
+// GeoAreaTool returns a tool definition for the area and perimeter of a polygon
+func GeoAreaTool() mcp.Tool {
+	return mcp.NewTool("geo_area",
+		mcp.WithDescription("Calculate the area and perimeter of a polygon on a spherical Earth, given as points, an encoded polyline or a GeoJSON Polygon or MultiPolygon geometry such as one returned by get_osm_geometry. Rings may be open or closed; holes are subtracted from the area. Rings must not cross themselves"),
+		mcp.WithArray("points",
+			mcp.Description("The polygon outline as an array of {latitude, longitude} points, at least 3"),
+		),
+		mcp.WithString("polyline",
+			mcp.Description("The polygon outline as an encoded polyline, instead of points"),
+		),
+		mcp.WithObject("geometry",
+			mcp.Description("A GeoJSON Polygon or MultiPolygon geometry with [longitude, latitude] positions, instead of points"),
+		),
+		mcp.WithString("units",
+			mcp.Description("Also report the perimeter in a unit system: metric (km), imperial (mi) or nautical (nmi). Square meters, hectares and square kilometers are always returned"),
+		),
+	)
+}
+
+// HandleGeoArea implements polygon area and perimeter calculation
+func HandleGeoArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "geo_area")
+
+	// Parse input
+	var input GeoAreaInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return ErrorResponse("Invalid input format"), nil
+	}
+
+	if !validUnits(input.Units) {
+		return ErrorResponse(fmt.Sprintf("Invalid units: %s (use metric, imperial or nautical)", input.Units)), nil
+	}
+
+	polygons, err := areaInputPolygons("points", input.Points, input.Polyline, input.Geometry)
+	if err != nil {
+		logger.Error("invalid polygon", "error", err)
+		return ErrorResponse(err.Error()), nil
+	}
+
+	output := GeoAreaOutput{Polygons: len(polygons)}
+	for _, rings := range polygons {
+		for i, ring := range rings {
+			area := geo.PolygonArea(ring)
+			if i == 0 {
+				output.Area += area
+			} else {
+				output.Area -= area
+				output.Holes++
+			}
+			output.Perimeter += ringPerimeter(ring)
+		}
+	}
+	output.Area = math.Round(math.Max(output.Area, 0)*100) / 100
+	output.AreaHectares = math.Round(output.Area/100) / 100
+	output.AreaKm2 = math.Round(output.Area/1000) / 1000
+	output.Perimeter = math.Round(output.Perimeter*100) / 100
+	output.Units = convertMeasures(input.Units, output.Perimeter, 0)
+
+	// Return result
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return ErrorResponse("Failed to generate result"), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// areaInputPolygons reads a polygon given to a tool as an outline of
+// points, an encoded polyline or a GeoJSON geometry, returning polygons,
+// each an outer ring followed by its holes, with validated coordinates.
+// pointsParam names the outline parameter in errors.
+func areaInputPolygons(pointsParam string, outline []geo.Location, polyline string, geometry *AreaGeometry) ([][][]geo.Location, error) {
+	given := 0
+	for _, set := range []bool{len(outline) > 0, polyline != "", geometry != nil} {
+		if set {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, fmt.Errorf("provide exactly one of %s, polyline or geometry", pointsParam)
+	}
+
+	var polygons [][][]geo.Location
+	switch {
+	case len(outline) > 0:
+		polygons = [][][]geo.Location{{outline}}
+	case polyline != "":
+		points := osm.DecodePolyline(polyline)
+		if len(points) == 0 {
+			return nil, fmt.Errorf("invalid polyline")
+		}
+		polygons = [][][]geo.Location{{points}}
+	default:
+		var positions [][][][]float64
+		switch geometry.Type {
+		case "Polygon":
+			var polygon [][][]float64
+			if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+				return nil, fmt.Errorf("invalid Polygon coordinates: %s", err)
+			}
+			positions = [][][][]float64{polygon}
+		case "MultiPolygon":
+			if err := json.Unmarshal(geometry.Coordinates, &positions); err != nil {
+				return nil, fmt.Errorf("invalid MultiPolygon coordinates: %s", err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported geometry type %q (use Polygon or MultiPolygon)", geometry.Type)
+		}
+		for _, polygon := range positions {
+			rings := make([][]geo.Location, 0, len(polygon))
+			for _, ring := range polygon {
+				locs := make([]geo.Location, 0, len(ring))
+				for _, p := range ring {
+					if len(p) < 2 {
+						return nil, fmt.Errorf("invalid position %v: want [longitude, latitude]", p)
+					}
+					locs = append(locs, geo.Location{Latitude: p[1], Longitude: p[0]})
+				}
+				rings = append(rings, locs)
+			}
+			polygons = append(polygons, rings)
+		}
+	}
+
+	total := 0
+	for i, rings := range polygons {
+		if len(rings) == 0 {
+			return nil, fmt.Errorf("polygon %d has no rings", i)
+		}
+		for _, ring := range rings {
+			distinct := len(ring)
+			if distinct > 1 && ring[0] == ring[len(ring)-1] {
+				distinct--
+			}
+			if distinct < 3 {
+				return nil, fmt.Errorf("a ring needs at least 3 distinct points, got %d", distinct)
+			}
+			for j, p := range ring {
+				if err := geo.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+					return nil, fmt.Errorf("invalid coordinates at index %d: %s", j, err)
+				}
+			}
+			total += len(ring)
+		}
+	}
+	if total > maxAreaPoints {
+		return nil, fmt.Errorf("too many points: %d (maximum is %d)", total, maxAreaPoints)
+	}
+	return polygons, nil
+}
+
+// ringPerimeter returns the length of a ring in meters, closing it if open
+func ringPerimeter(ring []geo.Location) float64 {
+	perimeter := pathLength(ring)
+	if first, last := ring[0], ring[len(ring)-1]; first != last {
+		perimeter += geo.HaversineDistance(last.Latitude, last.Longitude, first.Latitude, first.Longitude)
+	}
+	return perimeter
+}
+
+// validateFromTo checks the from and to points shared by the two-point geo
+// tools, returning an error result if either is missing or invalid
+func validateFromTo(from, to geo.Location, logger *slog.Logger) *mcp.CallToolResult {
+	if from.Latitude == 0 && from.Longitude == 0 {
+		logger.Error("missing 'from' coordinates")
+		return ErrorResponse("Missing 'from' coordinates")
+	}
+
+	if to.Latitude == 0 && to.Longitude == 0 {
+		logger.Error("missing 'to' coordinates")
+		return ErrorResponse("Missing 'to' coordinates")
+	}
+
+	if err := geo.ValidateCoords(from.Latitude, from.Longitude); err != nil {
+		logger.Error("invalid 'from' coordinates", "error", err)
+		return ErrorResponse(fmt.Sprintf("Invalid 'from' coordinates: %s", err))
+	}
+
+	if err := geo.ValidateCoords(to.Latitude, to.Longitude); err != nil {
+		logger.Error("invalid 'to' coordinates", "error", err)
+		return ErrorResponse(fmt.Sprintf("Invalid 'to' coordinates: %s", err))
+	}
+
+	return nil
+}
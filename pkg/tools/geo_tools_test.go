@@ -2,12 +2,17 @@ package tools
 
 import (
 	"context"
+	"io"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
 func TestHandleGeoDistance(t *testing.T) {
@@ -93,6 +98,62 @@ func TestHandleGeoDistance(t *testing.T) {
 	}
 }
 
+func TestHandleGeoDistanceRouted(t *testing.T) {
+	// Driving finds a 1.5x detour and walking has no route
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("overview") != "false" {
+			t.Errorf("route requested with geometry: %s", r.URL)
+		}
+		if strings.Contains(r.URL.Path, "/foot/") {
+			io.WriteString(w, `{"code":"NoRoute","routes":[]}`)
+			return
+		}
+		io.WriteString(w, `{"code":"Ok","routes":[{"distance":1668,"duration":120,"geometry":""}]}`)
+	}))
+	t.Cleanup(srv.Close)
+	if err := osm.SetServiceURLs(osm.ServiceURLs{OSRM: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	osm.UpdateOSRMRateLimits(1000, 1000)
+	t.Cleanup(func() {
+		osm.SetServiceURLs(osm.ServiceURLs{OSRM: osm.DefaultOSRMBaseURL})
+		osm.UpdateOSRMRateLimits(1, 1)
+	})
+
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{
+		"from":   map[string]any{"latitude": 12.3401, "longitude": 45.6701},
+		"to":     map[string]any{"latitude": 12.3501, "longitude": 45.6701},
+		"units":  "metric",
+		"routed": true,
+	}}}
+	result, err := HandleGeoDistance(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertSuccessResult(t, result, "routed distance should succeed")
+	var output GeoDistanceOutput
+	if err := ParseResultJSON(result, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output.Routes) != 2 {
+		t.Fatalf("got %d routes, want car and foot", len(output.Routes))
+	}
+	car, foot := output.Routes[0], output.Routes[1]
+	if car.Mode != "car" || car.Distance != 1668 || car.Duration != 120 || car.DetourFactor != 1.5 {
+		t.Errorf("car = %+v, want 1668 m in 120 s with a 1.5 detour factor", car)
+	}
+	if car.Units == nil || car.Units.Distance != 1.67 {
+		t.Errorf("car units = %+v", car.Units)
+	}
+	if foot.Mode != "foot" || foot.Error == "" || foot.Distance != 0 {
+		t.Errorf("foot = %+v, want an error and no distance", foot)
+	}
+	if math.Abs(output.Distance-1112) > 1 {
+		t.Errorf("great-circle distance = %f, want about 1112 m", output.Distance)
+	}
+}
+
 func TestHandleBBoxFromPoints(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -253,3 +314,224 @@ func TestHandleCentroidPoints(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleGreatCirclePath(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        map[string]any
+		expectError bool
+		points      int
+	}{
+		{
+			name: "Default points",
+			args: map[string]any{
+				"from": geo.Location{Latitude: 51.47, Longitude: -0.4543},
+				"to":   geo.Location{Latitude: 40.6413, Longitude: -73.7781},
+			},
+			points: 64,
+		},
+		{
+			name: "Custom points",
+			args: map[string]any{
+				"from":   geo.Location{Latitude: 51.47, Longitude: -0.4543},
+				"to":     geo.Location{Latitude: 40.6413, Longitude: -73.7781},
+				"points": 5,
+			},
+			points: 5,
+		},
+		{
+			name: "Too many points",
+			args: map[string]any{
+				"from":   geo.Location{Latitude: 51.47, Longitude: -0.4543},
+				"to":     geo.Location{Latitude: 40.6413, Longitude: -73.7781},
+				"points": 5000,
+			},
+			expectError: true,
+		},
+		{
+			name: "Antipodal points",
+			args: map[string]any{
+				"from": geo.Location{Latitude: 40, Longitude: -74},
+				"to":   geo.Location{Latitude: -40, Longitude: 106},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "great_circle_path", Arguments: tt.args}}
+			result, err := HandleGreatCirclePath(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.expectError {
+				AssertErrorResult(t, result, "Expected error result, but got success")
+				return
+			}
+			AssertSuccessResult(t, result, "Expected success result, but got error")
+
+			var output GreatCirclePathOutput
+			if err := ParseResultJSON(result, &output); err != nil {
+				t.Fatalf("Failed to unmarshal result: %v", err)
+			}
+			if len(output.Points) != tt.points {
+				t.Errorf("Expected %d points, got %d", tt.points, len(output.Points))
+			}
+			if math.Abs(output.Distance-5540000) > 20000 {
+				t.Errorf("Unexpected distance %.0f", output.Distance)
+			}
+			if output.InitialBearing < 280 || output.InitialBearing > 295 {
+				t.Errorf("Unexpected initial bearing %.1f", output.InitialBearing)
+			}
+		})
+	}
+}
+
+func TestHandleGeoMidpointAndAntipode(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "geo_midpoint",
+			Arguments: map[string]any{
+				"from": geo.Location{Latitude: 10, Longitude: 0},
+				"to":   geo.Location{Latitude: 10, Longitude: 90},
+			},
+		},
+	}
+	result, err := HandleGeoMidpoint(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	AssertSuccessResult(t, result, "Expected success result, but got error")
+
+	var midpoint GeoMidpointOutput
+	if err := ParseResultJSON(result, &midpoint); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	// The great circle bulges poleward of the parallel
+	if math.Abs(midpoint.Midpoint.Longitude-45) > 1e-6 || midpoint.Midpoint.Latitude <= 10 {
+		t.Errorf("Unexpected midpoint %+v", midpoint.Midpoint)
+	}
+
+	req = mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "antipode",
+			Arguments: map[string]any{"point": geo.Location{Latitude: 51.5, Longitude: -0.1}},
+		},
+	}
+	result, err = HandleAntipode(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	AssertSuccessResult(t, result, "Expected success result, but got error")
+
+	var antipode AntipodeOutput
+	if err := ParseResultJSON(result, &antipode); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	if math.Abs(antipode.Antipode.Latitude+51.5) > 1e-9 || math.Abs(antipode.Antipode.Longitude-179.9) > 1e-9 {
+		t.Errorf("Unexpected antipode %+v", antipode.Antipode)
+	}
+
+	req.Params.Arguments = map[string]any{"point": geo.Location{Latitude: 95, Longitude: 0}}
+	result, err = HandleAntipode(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	AssertErrorResult(t, result, "Expected error for invalid latitude")
+}
+
+func TestHandleGeoArea(t *testing.T) {
+	// 0.01 x 0.01 degree square at the equator, about 1113 m a side
+	square := []geo.Location{
+		{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.01},
+		{Latitude: 0.01, Longitude: 0.01}, {Latitude: 0.01, Longitude: 0},
+	}
+	side := geo.HaversineDistance(0, 0, 0, 0.01)
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		area      float64
+		perimeter float64
+		holes     int
+	}{
+		{
+			name:      "Open ring",
+			args:      map[string]any{"points": square, "units": "metric"},
+			area:      side * side,
+			perimeter: 4 * side,
+		},
+		{
+			name:      "Closed ring",
+			args:      map[string]any{"points": append(append([]geo.Location{}, square...), square[0])},
+			area:      side * side,
+			perimeter: 4 * side,
+		},
+		{
+			name:      "Polyline",
+			args:      map[string]any{"polyline": osm.EncodePolyline(square)},
+			area:      side * side,
+			perimeter: 4 * side,
+		},
+		{
+			name: "GeoJSON polygon with a hole",
+			args: map[string]any{"geometry": map[string]any{
+				"type": "Polygon",
+				"coordinates": [][][]float64{
+					{{0, 0}, {0.01, 0}, {0.01, 0.01}, {0, 0.01}, {0, 0}},
+					{{0.004, 0.004}, {0.004, 0.006}, {0.006, 0.006}, {0.006, 0.004}, {0.004, 0.004}},
+				},
+			}},
+			area:      side * side * 0.96,
+			perimeter: 4.8 * side,
+			holes:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "geo_area", Arguments: tt.args}}
+			result, err := HandleGeoArea(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			AssertSuccessResult(t, result, "Expected success result, but got error")
+
+			var output GeoAreaOutput
+			if err := ParseResultJSON(result, &output); err != nil {
+				t.Fatalf("Failed to unmarshal result: %v", err)
+			}
+			if math.Abs(output.Area-tt.area)/tt.area > 0.005 {
+				t.Errorf("area = %.0f m², want about %.0f", output.Area, tt.area)
+			}
+			if math.Abs(output.Perimeter-tt.perimeter)/tt.perimeter > 0.005 {
+				t.Errorf("perimeter = %.0f m, want about %.0f", output.Perimeter, tt.perimeter)
+			}
+			if output.Holes != tt.holes || output.Polygons != 1 {
+				t.Errorf("got %d polygons with %d holes", output.Polygons, output.Holes)
+			}
+			if math.Abs(output.AreaHectares-output.Area/10000) > 0.01 {
+				t.Errorf("hectares = %v for %v m²", output.AreaHectares, output.Area)
+			}
+		})
+	}
+
+	for name, args := range map[string]map[string]any{
+		"Nothing":         {},
+		"Two inputs":      {"points": square, "polyline": osm.EncodePolyline(square)},
+		"Too few points":  {"points": square[:2]},
+		"Invalid point":   {"points": []geo.Location{{Latitude: 95}, {Latitude: 1}, {Longitude: 1}}},
+		"Line geometry":   {"geometry": map[string]any{"type": "LineString", "coordinates": [][]float64{{0, 0}, {1, 1}}}},
+		"Invalid units":   {"points": square, "units": "furlongs"},
+		"Degenerate ring": {"points": []geo.Location{square[0], square[1], square[0]}},
+	} {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "geo_area", Arguments: args}}
+		result, err := HandleGeoArea(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		AssertErrorResult(t, result, "Expected error for "+name)
+	}
+}
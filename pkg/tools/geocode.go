@@ -10,34 +10,30 @@ import (
 	"net/url"
 	"os"
 	"regexp"
-	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/mark3labs/mcp-go/mcp"
 	"golang.org/x/sync/singleflight"
 
+	"github.com/NERVsystems/osmmcp/pkg/cache"
 	"github.com/NERVsystems/osmmcp/pkg/coords"
 	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
 const (
-	// Nominatim is OSM's geocoding service
-	nominatimBaseURL = "https://nominatim.openstreetmap.org"
-
 	// UserAgent identifies our application to Nominatim
 	userAgent = "NERV-MCP-Geocoder/1.0 (contact: ops@nerv.systems)"
 
 	// Search parameters
-	maxResults    = 3   // Maximum number of results to return
-	minImportance = 0.4 // Minimum importance threshold for result selection
+	maxResults = 3 // Maximum number of results to return
 
-	// Cache configuration
-	cacheSize = 512            // Maximum number of entries in the LRU cache
-	cacheTTL  = 24 * time.Hour // Cache entries valid for 24 hours
+	// Cache configuration; entry TTLs come from the cache policy table
+	cacheSize = 512 // Maximum number of entries in each geocoding cache
 
 	// Retry configuration
 	maxRetries     = 3                      // Maximum number of retries for failed requests
@@ -59,13 +55,18 @@ var defaultRegion = func() string {
 	return "Singapore"
 }()
 
+// SetDefaultRegion overrides the region appended to single token queries
+func SetDefaultRegion(region string) {
+	defaultRegion = region
+}
+
 // Global cache and request group to deduplicate in-flight requests
 var (
-	// geocodeCache is an LRU cache for geocoding results
-	geocodeCache *lru.Cache[string, []byte]
+	// geocodeCache caches geocoding results
+	geocodeCache *cache.TTLCache
 
-	// reverseGeocodeCache is an LRU cache for reverse geocoding results
-	reverseGeocodeCache *lru.Cache[string, []byte]
+	// reverseGeocodeCache caches reverse geocoding results
+	reverseGeocodeCache *cache.TTLCache
 
 	// requestGroup deduplicates in-flight requests
 	requestGroup singleflight.Group
@@ -74,26 +75,15 @@ var (
 	initOnce sync.Once
 )
 
-// initCaches initializes the LRU caches
+// initCaches initializes the geocoding caches
 func initCaches() {
 	initOnce.Do(func() {
-		var err error
-
-		// Initialize geocoding cache
-		geocodeCache, err = lru.New[string, []byte](cacheSize)
-		if err != nil {
-			slog.Error("failed to create geocode cache", "error", err)
-			// Create a minimal cache as fallback
-			geocodeCache, _ = lru.New[string, []byte](10)
-		}
-
-		// Initialize reverse geocoding cache
-		reverseGeocodeCache, err = lru.New[string, []byte](cacheSize)
-		if err != nil {
-			slog.Error("failed to create reverse geocode cache", "error", err)
-			// Create a minimal cache as fallback
-			reverseGeocodeCache, _ = lru.New[string, []byte](10)
-		}
+		geocodeCache = cache.NewTTLCache(cache.TTLFor(cache.ClassGeocode), time.Minute, cache.MaxEntriesFor("geocode", cacheSize))
+		reverseGeocodeCache = cache.NewTTLCache(cache.TTLFor(cache.ClassReverseGeocode), time.Minute, cache.MaxEntriesFor("reverse_geocode", cacheSize))
+		cache.Register("geocode", geocodeCache)
+		cache.Register("reverse_geocode", reverseGeocodeCache)
+		geocodeCache.Persist(cache.DefaultBackend(), "geocode", cache.BytesCodec)
+		reverseGeocodeCache.Persist(cache.DefaultBackend(), "reverse_geocode", cache.BytesCodec)
 
 		// Set the user agent for all OSM requests
 		osm.SetUserAgent(userAgent)
@@ -102,14 +92,22 @@ func initCaches() {
 
 // GeocodeAddressInput defines the input parameters for geocoding an address
 type GeocodeAddressInput struct {
-	Address string `json:"address"`
-	Region  string `json:"region,omitempty"` // Optional region for context
+	Address        string `json:"address,omitempty"`
+	Street         string `json:"street,omitempty"`          // House number and street name
+	City           string `json:"city,omitempty"`            // City, town or village
+	State          string `json:"state,omitempty"`           // State, province or region
+	Country        string `json:"country,omitempty"`         // Country name or code
+	PostalCode     string `json:"postalcode,omitempty"`      // Postal code
+	Region         string `json:"region,omitempty"`          // Optional region for context
+	IncludePolygon bool   `json:"include_polygon,omitempty"` // Return the boundary of area features
 }
 
 // GeocodeAddressOutput defines the output format for geocoded addresses
 type GeocodeAddressOutput struct {
-	Place      Place   `json:"place"`
-	Candidates []Place `json:"candidates,omitempty"`
+	Place      Place          `json:"place"`
+	Candidates []Place        `json:"candidates,omitempty"`
+	Polygon    []geo.Location `json:"polygon,omitempty"`  // simplified outer boundary, when requested
+	Geometry   *AreaGeometry  `json:"geometry,omitempty"` // simplified GeoJSON boundary, when requested
 }
 
 // GeocodeDetailedError provides detailed error information with suggestions
@@ -149,18 +147,39 @@ Automatically detects and converts:
 - MGRS (Military Grid Reference System): e.g., "54SVK2747201448", "18SUJ23370651"
 - UTM (Universal Transverse Mercator): e.g., "47N 485986 2197460"
 - DMS (Degrees Minutes Seconds): e.g., "19°51'22"N 99°48'59"E"
+- Plus codes (Open Location Code): e.g., "7FG49QCJ+2V", or short codes with a locality such as "CWC8+R9 Mountain View"
 - Place names and street addresses
 
-For MGRS/UTM/DMS coordinates, returns precise lat/lon directly without external lookup.
-Essential for tactical/military coordinate handling.`),
+For MGRS/UTM/DMS coordinates and full plus codes, returns precise lat/lon directly without external lookup.
+Essential for tactical/military coordinate handling.
+
+For well-formed addresses, pass street, city, state, country and postalcode instead of, or as well as, address: they are matched to the corresponding address parts, which is more precise than free text.`),
 		mcp.WithString("address",
-			mcp.Required(),
-			mcp.Description("The address, place name, or coordinate to geocode. Accepts MGRS (e.g., '54SVK2747201448'), UTM (e.g., '47N 485986 2197460'), DMS, or place names. For addresses, include city/country for best results."),
+			mcp.Description("The address, place name, or coordinate to geocode. Accepts MGRS (e.g., '54SVK2747201448'), UTM (e.g., '47N 485986 2197460'), DMS, plus codes (e.g., '7FG49QCJ+2V' or 'CWC8+R9 Mountain View'), or place names. For addresses, include city/country for best results. Required unless structured fields are given."),
+		),
+		mcp.WithString("street",
+			mcp.Description("Structured query: house number and street name, e.g. '10 Downing Street'"),
+		),
+		mcp.WithString("city",
+			mcp.Description("Structured query: city, town or village"),
+		),
+		mcp.WithString("state",
+			mcp.Description("Structured query: state, province or region"),
+		),
+		mcp.WithString("country",
+			mcp.Description("Structured query: country name or ISO code"),
+		),
+		mcp.WithString("postalcode",
+			mcp.Description("Structured query: postal code"),
 		),
 		mcp.WithString("region",
 			mcp.Description("Optional region context to improve results for ambiguous queries (e.g., 'Singapore'). Will be automatically appended to short queries."),
 			mcp.DefaultString(""),
 		),
+		mcp.WithBoolean("include_polygon",
+			mcp.Description("Return the simplified boundary of area features such as cities and parks: 'polygon' (outer ring as {latitude, longitude} points, usable directly as a polygon parameter) and 'geometry' (GeoJSON). Omitted for point features such as street addresses"),
+			mcp.DefaultBool(false),
+		),
 	)
 }
 
@@ -251,12 +270,15 @@ func reverseGeoCacheKey(lat, lon float64) string {
 
 // NominatimResult represents a result from the Nominatim geocoding service
 type NominatimResult struct {
-	PlaceID     json.Number `json:"place_id"` // Using json.Number to handle both string and numeric IDs
-	DisplayName string      `json:"display_name"`
-	Lat         string      `json:"lat"`
-	Lon         string      `json:"lon"`
-	Type        string      `json:"type"`
-	Importance  float64     `json:"importance"`
+	PlaceID     json.Number     `json:"place_id"` // Using json.Number to handle both string and numeric IDs
+	Name        string          `json:"name"`
+	DisplayName string          `json:"display_name"`
+	Lat         string          `json:"lat"`
+	Lon         string          `json:"lon"`
+	Class       string          `json:"class"`
+	Type        string          `json:"type"`
+	Importance  float64         `json:"importance"`
+	GeoJSON     json.RawMessage `json:"geojson,omitempty"` // only with polygon_geojson=1
 	Address     struct {
 		Road        string `json:"road"`
 		HouseNumber string `json:"house_number"`
@@ -268,8 +290,89 @@ type NominatimResult struct {
 	} `json:"address"`
 }
 
+// geocodeDataClass picks the cache class for geocoding results: street
+// addresses are stable and cached longest, other places use the default
+func geocodeDataClass(results []NominatimResult) cache.DataClass {
+	if len(results) > 0 && results[0].Address.HouseNumber != "" {
+		return cache.ClassAddress
+	}
+	return cache.ClassGeocode
+}
+
+// geocodeOptions selects optional Nominatim output
+type geocodeOptions struct {
+	includePolygon bool              // request simplified polygon_geojson for areas
+	limit          int               // results requested, maxResults when zero
+	countryCodes   string            // comma-separated ISO 3166-1 codes restricting results
+	structured     structuredAddress // search these address parts instead of the free-text query
+}
+
+// structuredAddress holds the address parts of a Nominatim structured query
+type structuredAddress struct {
+	Street     string
+	City       string
+	State      string
+	Country    string
+	PostalCode string
+}
+
+// parseStructuredAddress reads the structured query fields of a request
+func parseStructuredAddress(req mcp.CallToolRequest) (structuredAddress, error) {
+	address := structuredAddress{
+		Street:     strings.TrimSpace(mcp.ParseString(req, "street", "")),
+		City:       strings.TrimSpace(mcp.ParseString(req, "city", "")),
+		State:      strings.TrimSpace(mcp.ParseString(req, "state", "")),
+		Country:    strings.TrimSpace(mcp.ParseString(req, "country", "")),
+		PostalCode: strings.TrimSpace(mcp.ParseString(req, "postalcode", "")),
+	}
+	for _, part := range address.params() {
+		if len(part[1]) > maxAddressLength {
+			return structuredAddress{}, fmt.Errorf("%s must be at most %d characters", part[0], maxAddressLength)
+		}
+	}
+	return address, nil
+}
+
+// params returns the Nominatim parameters of the parts that are set, in
+// address order
+func (a structuredAddress) params() [][2]string {
+	var params [][2]string
+	for _, part := range [][2]string{
+		{"street", a.Street},
+		{"city", a.City},
+		{"state", a.State},
+		{"postalcode", a.PostalCode},
+		{"country", a.Country},
+	} {
+		if part[1] != "" {
+			params = append(params, part)
+		}
+	}
+	return params
+}
+
+// isZero reports whether no address part is set
+func (a structuredAddress) isZero() bool {
+	return len(a.params()) == 0
+}
+
+// String joins the address parts into a free-text query
+func (a structuredAddress) String() string {
+	parts := make([]string, 0, 5)
+	for _, part := range a.params() {
+		parts = append(parts, part[1])
+	}
+	return strings.Join(parts, ", ")
+}
+
 // geocodeQuery performs a single geocoding request with caching
 func geocodeQuery(ctx context.Context, query string) ([]NominatimResult, error) {
+	return geocodeQueryWithOptions(ctx, query, geocodeOptions{})
+}
+
+// geocodeQueryWithOptions performs a single geocoding request with caching,
+// requesting the optional output selected by opts
+func geocodeQueryWithOptions(ctx context.Context, query string, opts geocodeOptions) ([]NominatimResult, error) {
 	logger := slog.Default().With("query", query)
 
 	// Initialize caches if needed
@@ -277,10 +380,35 @@ func geocodeQuery(ctx context.Context, query string) ([]NominatimResult, error)
 
 	// Create a normalized key for caching
 	key := cacheKey(query)
+	if !opts.structured.isZero() {
+		key = "structured"
+		for _, part := range opts.structured.params() {
+			key += "|" + part[0] + "=" + cacheKey(part[1])
+		}
+	}
+	if opts.includePolygon {
+		key += "|polygon"
+	}
+	limit := maxResults
+	if opts.limit > 0 {
+		limit = opts.limit
+		key += fmt.Sprintf("|limit=%d", limit)
+	}
+	if opts.countryCodes != "" {
+		key += "|countries=" + opts.countryCodes
+	}
 
-	// Check cache first
-	if cachedData, found := geocodeCache.Get(key); found {
+	// Count the query for cache warming, which refreshes popular entries
+	// before they expire
+	cache.RecordQuery(ctx, "geocode", geocodeCache, key, func(ctx context.Context) error {
+		_, err := geocodeQueryWithOptions(ctx, query, opts)
+		return err
+	})
+
+	// Check cache first, unless this is a warming refresh
+	if cached, found := geocodeCache.Get(key); found && !cache.IsRefresh(ctx) {
 		logger.Info("cache hit", "query", query)
+		cachedData, _ := cached.([]byte)
 
 		var results []NominatimResult
 		if err := json.Unmarshal(cachedData, &results); err != nil {
@@ -293,17 +421,29 @@ func geocodeQuery(ctx context.Context, query string) ([]NominatimResult, error)
 	// Use singleflight to deduplicate in-flight requests for the same query
 	result, err, _ := requestGroup.Do(key, func() (interface{}, error) {
 		// Build request URL
-		reqURL, err := url.Parse(fmt.Sprintf("%s/search", nominatimBaseURL))
+		reqURL, err := url.Parse(fmt.Sprintf("%s/search", osm.NominatimBaseURL))
 		if err != nil {
 			return nil, core.NewError(core.ErrInternalError, "Failed to parse URL for geocoding service")
 		}
 
 		// Add query parameters
 		q := reqURL.Query()
-		q.Add("q", query)
+		if opts.structured.isZero() {
+			q.Add("q", query)
+		}
+		for _, part := range opts.structured.params() {
+			q.Add(part[0], part[1])
+		}
 		q.Add("format", "json")
-		q.Add("limit", fmt.Sprintf("%d", maxResults)) // Increased limit
-		q.Add("addressdetails", "1")                  // Get detailed address info
+		q.Add("limit", fmt.Sprintf("%d", limit))
+		q.Add("addressdetails", "1") // Get detailed address info
+		if opts.countryCodes != "" {
+			q.Add("countrycodes", opts.countryCodes)
+		}
+		if opts.includePolygon {
+			q.Add("polygon_geojson", "1")
+			q.Add("polygon_threshold", polygonThreshold)
+		}
 		reqURL.RawQuery = q.Encode()
 
 		// Create HTTP request factory for retries
@@ -331,14 +471,14 @@ func geocodeQuery(ctx context.Context, query string) ([]NominatimResult, error)
 
 		// Parse response
 		var results []NominatimResult
-		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-			return nil, core.NewError(core.ErrParseError, "Failed to decode geocoding response")
+		if err := core.DecodeUpstream("Nominatim", resp.Body, &results); err != nil {
+			return nil, err
 		}
 
 		// Cache the results
 		resultsJSON, err := json.Marshal(results)
 		if err == nil {
-			geocodeCache.Add(key, resultsJSON)
+			geocodeCache.SetFor(geocodeDataClass(results), key, resultsJSON)
 		}
 
 		return results, nil
@@ -409,6 +549,29 @@ func resultsToPlaces(results []NominatimResult) ([]Place, error) {
 	return places, nil
 }
 
+// recoverShortPlusCode geocodes a locality and expands a short plus code
+// to the nearest full code around it
+func recoverShortPlusCode(ctx context.Context, code, locality string) (string, error) {
+	results, err := geocodeQuery(ctx, locality)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", fmt.Errorf("locality not found: %s", locality)
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid latitude for locality: %w", err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid longitude for locality: %w", err)
+	}
+
+	return coords.RecoverPlusCode(code, lat, lon)
+}
+
 // HandleGeocodeAddress implements the geocoding functionality
 //
 // This function automatically detects and converts coordinate formats:
@@ -429,23 +592,49 @@ func HandleGeocodeAddress(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 	// Parse input
 	address := mcp.ParseString(rawInput, "address", "")
 	region := mcp.ParseString(rawInput, "region", defaultRegion)
+	opts := geocodeOptions{
+		includePolygon: mcp.ParseBoolean(rawInput, "include_polygon", false),
+	}
+	structured, err := parseStructuredAddress(rawInput)
+	if err != nil {
+		return NewGeocodeDetailedError("INVALID_ADDRESS", err.Error(), address), nil
+	}
 
 	// Log the original query for diagnostics
-	logger.Info("geocoding address", "original_query", address, "region", region)
+	logger.Info("geocoding address", "original_query", address, "structured", structured.String(), "region", region)
 
-	if address == "" {
+	if address == "" && structured.isZero() {
 		return NewGeocodeDetailedError(
 			"EMPTY_ADDRESS",
 			"Address must not be empty",
 			address,
 			"Provide a specific address or place name",
+			"Or pass structured fields such as street, city and country",
 			"Include city/region for better results",
 		), nil
 	}
+	if address == "" {
+		// The free-text fallback needs no region when the fields name the place
+		address, region = structured.String(), ""
+	}
 
-	// Check if input is a coordinate format (MGRS, UTM, DMS, decimal)
+	// Short plus codes ("CWC8+R9 Mountain View") are expanded to full codes
+	// using the geocoded locality as the reference location
+	if code, locality, ok := coords.SplitShortPlusCode(address); ok {
+		full, err := recoverShortPlusCode(ctx, code, ensureRegion(locality, region))
+		if err != nil {
+			logger.Warn("short plus code could not be resolved",
+				"input", address,
+				"error", err)
+		} else {
+			logger.Info("short plus code resolved", "input", address, "code", full)
+			address = full
+		}
+	}
+
+	// Check if input is a coordinate format (MGRS, UTM, DMS, decimal, plus code)
 	// If so, convert directly without calling Nominatim
-	if coords.IsCoordinate(address) {
+	if structured.isZero() && coords.IsCoordinate(address) {
 		result, err := coords.Parse(address)
 		if err != nil {
 			logger.Warn("coordinate detection matched but parse failed",
@@ -530,10 +719,21 @@ func HandleGeocodeAddress(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 	var firstSuccess string
 	var queryErr error
 
-	for _, query := range uniqueQueries {
-		logger.Info("trying query", "query", query)
+	// Structured fields are the most precise query, as Nominatim matches
+	// each to its address part, so they are tried first. A misspelled or
+	// misplaced part finds nothing though, so the free-text queries follow.
+	if !structured.isZero() {
+		uniqueQueries = append([]string{structured.String()}, uniqueQueries...)
+	}
 
-		results, err := geocodeQuery(ctx, query)
+	for i, query := range uniqueQueries {
+		queryOpts := opts
+		if i == 0 && !structured.isZero() {
+			queryOpts.structured = structured
+		}
+		logger.Info("trying query", "query", query, "structured", !queryOpts.structured.isZero())
+
+		results, err := geocodeQueryWithOptions(ctx, query, queryOpts)
 		if err != nil {
 			logger.Error("query failed", "query", query, "error", err)
 			queryErr = err
@@ -596,22 +796,14 @@ func HandleGeocodeAddress(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 		), nil
 	}
 
-	// Sort results by importance
-	sort.Slice(allResults, func(i, j int) bool {
-		return allResults[i].Importance > allResults[j].Importance
-	})
-
-	// Find the best result - either first result with importance > threshold or the top result
-	bestResultIndex := 0
-	for i, result := range allResults {
-		if result.Importance >= minImportance {
-			bestResultIndex = i
-			break
-		}
-	}
+	// Find the best result for what the query asks for, so a house number
+	// is not passed over for a more important landmark nearby
+	queryType := classifyGeocodeQuery(address, structured, allResults)
+	bestResultIndex := selectBestResult(allResults, queryType)
 
 	bestResult := allResults[bestResultIndex]
 	logger.Info("selected best result",
+		"query_type", queryType,
 		"importance", bestResult.Importance,
 		"name", bestResult.DisplayName,
 		"successful_query", firstSuccess)
@@ -641,6 +833,12 @@ func HandleGeocodeAddress(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 		Place:      places[bestResultIndex],
 		Candidates: places,
 	}
+	if opts.includePolygon {
+		if polygon, geometry, ok := areaPolygon(bestResult.GeoJSON); ok {
+			output.Polygon = polygon
+			output.Geometry = geometry
+		}
+	}
 
 	// Return result
 	resultBytes, err := json.Marshal(output)
@@ -696,14 +894,8 @@ func HandleReverseGeocode(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 	// Parse and validate input coordinates
 	latitude, longitude, err := core.ParseCoordsWithLog(rawInput, logger, "latitude", "longitude")
 	if err != nil {
-		// Extract the specific error code from the validation error
-		errorCode := "INVALID_COORDINATES"
-		if valErr, ok := err.(core.ValidationError); ok {
-			errorCode = valErr.Code
-		}
-
 		return NewGeocodeDetailedError(
-			errorCode,
+			coordinateErrorCode(err),
 			err.Error(),
 			fmt.Sprintf("lat: %f, lon: %f", latitude, longitude),
 			"Ensure coordinates are in decimal degrees",
@@ -714,8 +906,9 @@ func HandleReverseGeocode(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 	key := reverseGeoCacheKey(latitude, longitude)
 
 	// Check cache first
-	if cachedData, found := reverseGeocodeCache.Get(key); found {
+	if cached, found := reverseGeocodeCache.Get(key); found {
 		logger.Info("cache hit", "key", key)
+		cachedData, _ := cached.([]byte)
 
 		var result struct {
 			Place Place `json:"place"`
@@ -733,10 +926,47 @@ func HandleReverseGeocode(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 		}
 	}
 
-	// Use singleflight to deduplicate in-flight requests
+	place, err := fetchReversePlace(ctx, latitude, longitude)
+	if err != nil {
+		logger.Error("request failed", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return NewGeocodeDetailedError(
+				mcpErr.Code,
+				mcpErr.Message,
+				fmt.Sprintf("lat: %f, lon: %f", latitude, longitude),
+				"Try again in a few moments",
+			), nil
+		}
+		return NewGeocodeDetailedError(
+			"SERVICE_ERROR",
+			"Failed to communicate with geocoding service",
+			fmt.Sprintf("lat: %f, lon: %f", latitude, longitude),
+			"Try again in a few moments",
+		), nil
+	}
+
+	// Create output
+	output := ReverseGeocodeOutput{
+		Place: place,
+	}
+
+	// Cache the result
+	outputJSON, err := json.Marshal(output)
+	if err == nil {
+		reverseGeocodeCache.SetFor(cache.ClassReverseGeocode, key, outputJSON)
+	}
+
+	return mcp.NewToolResultText(string(outputJSON)), nil
+}
+
+// fetchReversePlace reverse geocodes a point with Nominatim, deduplicating
+// concurrent requests for the same point. Requests go through the shared
+// client, which waits on the Nominatim rate limiter.
+func fetchReversePlace(ctx context.Context, latitude, longitude float64) (Place, error) {
+	key := reverseGeoCacheKey(latitude, longitude)
 	responseData, err, _ := requestGroup.Do(key, func() (interface{}, error) {
 		// Build request URL
-		reqURL, err := url.Parse(fmt.Sprintf("%s/reverse", nominatimBaseURL))
+		reqURL, err := url.Parse(fmt.Sprintf("%s/reverse", osm.NominatimBaseURL))
 		if err != nil {
 			return nil, core.NewError(core.ErrInternalError, "Failed to parse URL for geocoding service")
 		}
@@ -774,56 +1004,22 @@ func HandleReverseGeocode(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 
 		// Parse response
 		var result NominatimResult
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, core.NewError(core.ErrParseError, "Failed to decode geocoding response")
+		if err := core.DecodeUpstream("Nominatim", resp.Body, &result); err != nil {
+			return nil, err
 		}
 
 		return result, nil
 	})
 
 	if err != nil {
-		logger.Error("request failed", "error", err)
-		if mcpErr, ok := err.(*core.MCPError); ok {
-			return NewGeocodeDetailedError(
-				mcpErr.Code,
-				mcpErr.Message,
-				fmt.Sprintf("lat: %f, lon: %f", latitude, longitude),
-				"Try again in a few moments",
-			), nil
-		}
-		return NewGeocodeDetailedError(
-			"SERVICE_ERROR",
-			"Failed to communicate with geocoding service",
-			fmt.Sprintf("lat: %f, lon: %f", latitude, longitude),
-			"Try again in a few moments",
-		), nil
+		return Place{}, err
 	}
 
-	result := responseData.(NominatimResult)
-
-	// Convert to Place
-	place, err := resultToPlace(result)
+	place, err := resultToPlace(responseData.(NominatimResult))
 	if err != nil {
-		logger.Error("failed to convert result to place", "error", err)
-		return NewGeocodeDetailedError(
-			"PARSE_ERROR",
-			"Failed to parse geocoding response",
-			fmt.Sprintf("lat: %f, lon: %f", latitude, longitude),
-		), nil
+		return Place{}, core.NewError(core.ErrParseError, "Failed to parse geocoding response")
 	}
-
-	// Create output
-	output := ReverseGeocodeOutput{
-		Place: place,
-	}
-
-	// Cache the result
-	outputJSON, err := json.Marshal(output)
-	if err == nil {
-		reverseGeocodeCache.Add(key, outputJSON)
-	}
-
-	return mcp.NewToolResultText(string(outputJSON)), nil
+	return place, nil
 }
 
 // Example end-to-end flow for "Merlion Park (Singapore)"
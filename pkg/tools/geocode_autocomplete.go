@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// minAutocompleteLength is the shortest query suggestions are made for
+	minAutocompleteLength = 3
+	// defaultAutocompleteLimit is the default number of suggestions
+	defaultAutocompleteLimit = 5
+	// maxAutocompleteLimit caps the number of suggestions
+	maxAutocompleteLimit = 10
+	// autocompleteFetchLimit is how many results each Nominatim search asks
+	// for, so ranking has more to choose from than it returns
+	autocompleteFetchLimit = 10
+	// autocompleteImportanceWeight is the share of a suggestion's score
+	// taken from Nominatim importance rather than how well it matches
+	autocompleteImportanceWeight = 0.3
+)
+
+// Suggestion match kinds
+const (
+	matchFull    = "full"    // every word typed matches
+	matchPartial = "partial" // some words typed match
+)
+
+// countryCodesPattern matches comma-separated ISO 3166-1 alpha-2 codes
+var countryCodesPattern = regexp.MustCompile(`^[a-z]{2}(,[a-z]{2})*$`)
+
+// AutocompleteSuggestion is a place that may complete a partial query
+type AutocompleteSuggestion struct {
+	Place
+	Type  string  `json:"type,omitempty"` // Nominatim place type, e.g. house, street, city
+	Match string  `json:"match"`          // full or partial
+	Score float64 `json:"score"`          // ranking score between 0 and 1
+}
+
+// GeocodeAutocompleteOutput defines the output for geocode_autocomplete
+type GeocodeAutocompleteOutput struct {
+	Query       string                   `json:"query"`
+	Suggestions []AutocompleteSuggestion `json:"suggestions"`
+}
+
+// GeocodeAutocompleteTool returns a tool definition for address autocomplete
+func GeocodeAutocompleteTool() mcp.Tool {
+	return mcp.NewTool("geocode_autocomplete",
+		mcp.WithDescription("Suggest places and addresses completing a partial query, such as the start of a street address a user typed, ranked by how well they match. The last word may be incomplete. Pass the chosen suggestion's location on, or its name to geocode_address. Needs a self-hosted Nominatim, as the public service does not allow autocomplete."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("The partial address or place name, at least %d characters, e.g. '10 Downing Str'", minAutocompleteLength)),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of suggestions to return (max %d)", maxAutocompleteLimit)),
+			mcp.DefaultNumber(defaultAutocompleteLimit),
+		),
+		mcp.WithString("country",
+			mcp.Description("Restrict suggestions to these countries, as comma-separated ISO 3166-1 alpha-2 codes, e.g. 'gb' or 'de,at,ch'"),
+			mcp.DefaultString(""),
+		),
+	)
+}
+
+// HandleGeocodeAutocomplete suggests places completing a partial query
+func HandleGeocodeAutocomplete(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "geocode_autocomplete")
+
+	query := mcp.ParseString(req, "query", "")
+	if strings.TrimSpace(query) == "" {
+		return core.NewError(core.ErrEmptyParameter, "query must not be empty").
+			WithGuidance("Pass the text typed so far, e.g. '10 Downing Str'").ToMCPResult(), nil
+	}
+	if len(query) > maxAddressLength {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("query must be at most %d characters", maxAddressLength)).ToMCPResult(), nil
+	}
+	if len([]rune(strings.TrimSpace(query))) < minAutocompleteLength {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("query must be at least %d characters", minAutocompleteLength)).
+			WithGuidance("Wait for more of the address before asking for suggestions").ToMCPResult(), nil
+	}
+
+	limit := int(mcp.ParseFloat64(req, "limit", defaultAutocompleteLimit))
+	if limit <= 0 || limit > maxAutocompleteLimit {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("limit must be between 1 and %d", maxAutocompleteLimit)).ToMCPResult(), nil
+	}
+
+	country := strings.ToLower(strings.ReplaceAll(mcp.ParseString(req, "country", ""), " ", ""))
+	if country != "" && !countryCodesPattern.MatchString(country) {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid country: %s", country)).
+			WithGuidance("Use comma-separated ISO 3166-1 alpha-2 codes, e.g. 'gb' or 'de,at,ch'").ToMCPResult(), nil
+	}
+
+	// The public Nominatim usage policy forbids autocomplete searches,
+	// which send a request as each word is typed
+	if osm.NominatimBaseURL == osm.DefaultNominatimBaseURL {
+		return core.NewError(core.ErrServiceUnavailable, "Autocomplete is not available on the public Nominatim service").
+			WithGuidance("Search the complete address with geocode_address, or run the server with --nominatim-url pointing at a self-hosted Nominatim").
+			ToMCPResult(), nil
+	}
+
+	complete, partial := splitAutocompleteQuery(query)
+	opts := geocodeOptions{limit: autocompleteFetchLimit, countryCodes: country}
+
+	// Nominatim matches whole words, so a query ending in a partly typed
+	// word is also searched without it and the results filtered on its
+	// prefix. Both searches go through the geocoding cache, and the query
+	// without the last word stays the same while it is typed, so each
+	// keystroke costs at most one upstream request.
+	results, err := geocodeQueryWithOptions(ctx, query, opts)
+	if err != nil {
+		logger.Error("autocomplete search failed", "query", query, "error", err)
+		return geocodeErrorResult(err, query), nil
+	}
+	if baseQuery := strings.Join(complete, " "); partial != "" && len(baseQuery) >= minAutocompleteLength {
+		base, err := geocodeQueryWithOptions(ctx, baseQuery, opts)
+		if err != nil {
+			logger.Warn("autocomplete search without the last word failed", "query", query, "error", err)
+		} else {
+			results = append(results, base...)
+		}
+	}
+
+	output := GeocodeAutocompleteOutput{
+		Query:       query,
+		Suggestions: rankSuggestions(results, complete, partial, limit),
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// geocodeErrorResult converts a geocoding error into a tool result
+func geocodeErrorResult(err error, query string) *mcp.CallToolResult {
+	if mcpErr, ok := err.(*core.MCPError); ok {
+		return mcpErr.ToMCPResult()
+	}
+	return NewGeocodeDetailedError("SERVICE_ERROR", "Failed to search for suggestions", query,
+		"Try again in a few moments")
+}
+
+// splitAutocompleteQuery splits a query into its complete words and the
+// word being typed, which is empty when the query ends in a space or
+// punctuation
+func splitAutocompleteQuery(query string) (complete []string, partial string) {
+	words := autocompleteWords(query)
+	trimmed := strings.TrimRightFunc(query, unicode.IsSpace)
+	if len(words) == 0 || len(trimmed) < len(query) {
+		return words, ""
+	}
+	last := []rune(trimmed)[len([]rune(trimmed))-1]
+	if !unicode.IsLetter(last) && !unicode.IsDigit(last) {
+		return words, ""
+	}
+	return words[:len(words)-1], words[len(words)-1]
+}
+
+// autocompleteWords splits text into lower case words
+func autocompleteWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchQuality returns the share of the typed words a result matches:
+// complete words must appear as words of its name, the partial word as
+// the start of one
+func matchQuality(label string, complete []string, partial string) float64 {
+	words := autocompleteWords(label)
+	has := func(match func(string) bool) bool {
+		for _, word := range words {
+			if match(word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	matched, total := 0, len(complete)
+	for _, typed := range complete {
+		if has(func(word string) bool { return word == typed }) {
+			matched++
+		}
+	}
+	if partial != "" {
+		total++
+		if has(func(word string) bool { return strings.HasPrefix(word, partial) }) {
+			matched++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(matched) / float64(total)
+}
+
+// rankSuggestions converts Nominatim results into suggestions, dropping
+// duplicates and results matching none of the typed words, and returns
+// the best limit of them
+func rankSuggestions(results []NominatimResult, complete []string, partial string, limit int) []AutocompleteSuggestion {
+	seen := make(map[string]bool, len(results))
+	suggestions := make([]AutocompleteSuggestion, 0, len(results))
+	for _, result := range results {
+		place, err := resultToPlace(result)
+		if err != nil || seen[place.ID] {
+			continue
+		}
+		seen[place.ID] = true
+
+		quality := matchQuality(result.DisplayName, complete, partial)
+		if quality == 0 {
+			continue
+		}
+		match := matchPartial
+		if quality == 1 {
+			match = matchFull
+		}
+		if result.Name != "" {
+			place.Name = result.Name
+		}
+
+		importance := math.Min(math.Max(result.Importance, 0), 1)
+		score := (1-autocompleteImportanceWeight)*quality + autocompleteImportanceWeight*importance
+		suggestions = append(suggestions, AutocompleteSuggestion{
+			Place: place,
+			Type:  result.Type,
+			Match: match,
+			Score: math.Round(score*1000) / 1000,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].ID < suggestions[j].ID
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSplitAutocompleteQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		complete []string
+		partial  string
+	}{
+		{"10 Downing Str", []string{"10", "downing"}, "str"},
+		{"10 Downing Street ", []string{"10", "downing", "street"}, ""},
+		{"Downing Street,", []string{"downing", "street"}, ""},
+		{"Münch", []string{}, "münch"},
+	}
+
+	for _, tt := range tests {
+		complete, partial := splitAutocompleteQuery(tt.query)
+		if !slices.Equal(complete, tt.complete) || partial != tt.partial {
+			t.Errorf("splitAutocompleteQuery(%q) = %q, %q; want %q, %q", tt.query, complete, partial, tt.complete, tt.partial)
+		}
+	}
+}
+
+func TestRankSuggestions(t *testing.T) {
+	results := []NominatimResult{
+		{PlaceID: "1", Name: "Downing Street", DisplayName: "Downing Street, Westminster, London, United Kingdom", Lat: "51.5033", Lon: "-0.1276", Type: "residential", Importance: 0.6},
+		{PlaceID: "2", DisplayName: "10, Downing Street, Westminster, London, United Kingdom", Lat: "51.5034", Lon: "-0.1275", Type: "house", Importance: 0.3},
+		{PlaceID: "3", Name: "Downing College", DisplayName: "Downing College, Cambridge, United Kingdom", Lat: "52.2005", Lon: "0.1234", Type: "university", Importance: 0.5},
+		// Duplicate from the search without the last word
+		{PlaceID: "2", DisplayName: "10, Downing Street, Westminster, London, United Kingdom", Lat: "51.5034", Lon: "-0.1275", Type: "house", Importance: 0.3},
+		// Matches none of the words typed
+		{PlaceID: "4", Name: "Elsewhere", DisplayName: "Elsewhere, Nowhere", Lat: "1", Lon: "1", Importance: 0.9},
+	}
+
+	suggestions := rankSuggestions(results, []string{"10", "downing"}, "str", 5)
+	if len(suggestions) != 3 {
+		t.Fatalf("expected 3 suggestions, got %d: %+v", len(suggestions), suggestions)
+	}
+	if suggestions[0].ID != "2" || suggestions[0].Match != matchFull || suggestions[0].Type != "house" {
+		t.Errorf("expected the full match first, got %+v", suggestions[0])
+	}
+	if suggestions[1].ID != "1" || suggestions[1].Match != matchPartial || suggestions[1].Name != "Downing Street" {
+		t.Errorf("expected Downing Street second, got %+v", suggestions[1])
+	}
+	if suggestions[2].ID != "3" || suggestions[2].Score >= suggestions[1].Score {
+		t.Errorf("expected Downing College last, got %+v", suggestions[2])
+	}
+
+	if limited := rankSuggestions(results, []string{"10", "downing"}, "str", 1); len(limited) != 1 || limited[0].ID != "2" {
+		t.Errorf("unexpected limited suggestions: %+v", limited)
+	}
+}
+
+func TestHandleGeocodeAutocompleteValidation(t *testing.T) {
+	tests := []struct {
+		args   map[string]any
+		expect string
+	}{
+		{map[string]any{"query": " "}, "must not be empty"},
+		{map[string]any{"query": "ab"}, "at least 3 characters"},
+		{map[string]any{"query": "Downing", "limit": 50}, "limit must be between 1 and 10"},
+		{map[string]any{"query": "Downing", "country": "united kingdom"}, "Invalid country"},
+		{map[string]any{"query": "10 Downing Str"}, "not available on the public Nominatim"},
+	}
+
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = tt.args
+		result, err := HandleGeocodeAutocomplete(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if !result.IsError || !strings.Contains(text, tt.expect) {
+			t.Errorf("args %v: expected an error containing %q, got %s", tt.args, tt.expect, text)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Geocoding query types, which need different importance thresholds:
+// Nominatim ranks house numbers far below famous places nearby
+const (
+	queryTypeAddress  = "address"  // street address with a house number
+	queryTypeLandmark = "landmark" // named place such as a building or park
+	queryTypeArea     = "area"     // city, region or other administrative area
+)
+
+// DefaultMinImportance is the importance a result needs to be picked as
+// the best match of each query type, before more important results that
+// fit the query type less well. House numbers rarely reach 0.1, so any
+// address match is taken.
+var DefaultMinImportance = map[string]float64{
+	queryTypeAddress:  0,
+	queryTypeLandmark: 0.4,
+	queryTypeArea:     0.4,
+}
+
+// minImportance holds the thresholds in use
+var minImportance = DefaultMinImportance
+
+// SetMinImportance overrides the importance thresholds of some query types
+func SetMinImportance(thresholds map[string]float64) error {
+	merged := make(map[string]float64, len(DefaultMinImportance))
+	for queryType, threshold := range DefaultMinImportance {
+		merged[queryType] = threshold
+	}
+	for queryType, threshold := range thresholds {
+		if _, ok := DefaultMinImportance[queryType]; !ok {
+			return fmt.Errorf("unknown geocoding query type %q (types: address, landmark, area)", queryType)
+		}
+		if threshold < 0 || threshold > 1 {
+			return fmt.Errorf("importance threshold for %s must be between 0 and 1, got %g", queryType, threshold)
+		}
+		merged[queryType] = threshold
+	}
+	minImportance = merged
+	return nil
+}
+
+// ParseMinImportance parses importance thresholds given as type=threshold
+// pairs separated by commas, e.g. address=0,landmark=0.5
+func ParseMinImportance(spec string) (map[string]float64, error) {
+	thresholds := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		queryType, value, ok := strings.Cut(entry, "=")
+		queryType = strings.TrimSpace(queryType)
+		if !ok || queryType == "" {
+			return nil, fmt.Errorf("importance threshold %q must be type=threshold", entry)
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("importance threshold for %s must be a number", queryType)
+		}
+		thresholds[queryType] = threshold
+	}
+	return thresholds, nil
+}
+
+// houseNumberPattern matches a house number before the street name, as in
+// "10 Downing Street", or after it, as in "Unter den Linden 77, Berlin"
+var houseNumberPattern = regexp.MustCompile(`^\d+[a-zA-Z]?(-\d+)?,?\s+\pL|\pL\s+\d+[a-zA-Z]?(-\d+)?\s*(,|$)`)
+
+// areaPlaceTypes are the Nominatim place types of settlements and regions
+var areaPlaceTypes = map[string]bool{
+	"city": true, "town": true, "village": true, "hamlet": true, "suburb": true,
+	"quarter": true, "neighbourhood": true, "borough": true, "municipality": true,
+	"county": true, "district": true, "state": true, "province": true, "region": true,
+	"country": true,
+}
+
+// classifyGeocodeQuery tells what a query asks for from its wording, or
+// for a place name from the kind of the most important result
+func classifyGeocodeQuery(address string, structured structuredAddress, results []NominatimResult) string {
+	switch {
+	case structured.Street != "":
+		return queryTypeAddress
+	case !structured.isZero():
+		return queryTypeArea
+	case houseNumberPattern.MatchString(strings.TrimSpace(address)):
+		return queryTypeAddress
+	}
+
+	top := 0
+	for i, result := range results {
+		if result.Importance > results[top].Importance {
+			top = i
+		}
+	}
+	if len(results) > 0 && isAreaResult(results[top]) {
+		return queryTypeArea
+	}
+	return queryTypeLandmark
+}
+
+// isAreaResult reports whether a result is an administrative area or
+// settlement
+func isAreaResult(result NominatimResult) bool {
+	return result.Class == "boundary" || (result.Class == "place" && areaPlaceTypes[result.Type])
+}
+
+// fitsQueryType reports whether a result is the kind of place a query of
+// the type asks for
+func fitsQueryType(result NominatimResult, queryType string) bool {
+	switch queryType {
+	case queryTypeAddress:
+		return result.Address.HouseNumber != ""
+	case queryTypeArea:
+		return isAreaResult(result)
+	}
+	return true
+}
+
+// selectBestResult sorts results by importance and returns the index of
+// the best match for the query type: the most important result of the
+// type reaching its threshold, else the most important one overall
+func selectBestResult(results []NominatimResult, queryType string) int {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Importance > results[j].Importance
+	})
+
+	threshold := minImportance[queryType]
+	for i, result := range results {
+		if fitsQueryType(result, queryType) && result.Importance >= threshold {
+			return i
+		}
+	}
+	return 0
+}
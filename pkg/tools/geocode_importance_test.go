@@ -0,0 +1,94 @@
+package tools
+
+import "testing"
+
+func TestClassifyGeocodeQuery(t *testing.T) {
+	city := []NominatimResult{{Class: "boundary", Type: "administrative", Importance: 0.8}, {Class: "tourism", Type: "museum", Importance: 0.3}}
+	museum := []NominatimResult{{Class: "tourism", Type: "museum", Importance: 0.6}}
+
+	tests := []struct {
+		address    string
+		structured structuredAddress
+		results    []NominatimResult
+		want       string
+	}{
+		{"10 Downing Street, London", structuredAddress{}, museum, queryTypeAddress},
+		{"Unter den Linden 77, Berlin", structuredAddress{}, museum, queryTypeAddress},
+		{"221B Baker Street", structuredAddress{}, nil, queryTypeAddress},
+		{"British Museum", structuredAddress{}, museum, queryTypeLandmark},
+		{"Springfield", structuredAddress{}, city, queryTypeArea},
+		{"", structuredAddress{Street: "10 Downing Street", City: "London"}, city, queryTypeAddress},
+		{"", structuredAddress{City: "London", Country: "gb"}, museum, queryTypeArea},
+	}
+
+	for _, tt := range tests {
+		if got := classifyGeocodeQuery(tt.address, tt.structured, tt.results); got != tt.want {
+			t.Errorf("classifyGeocodeQuery(%q, %+v) = %s, want %s", tt.address, tt.structured, got, tt.want)
+		}
+	}
+}
+
+func TestSelectBestResult(t *testing.T) {
+	t.Cleanup(func() { SetMinImportance(nil) })
+
+	results := func() []NominatimResult {
+		house := NominatimResult{PlaceID: "house", Class: "place", Type: "house", Importance: 0.05}
+		house.Address.HouseNumber = "10"
+		return []NominatimResult{
+			house,
+			{PlaceID: "landmark", Class: "tourism", Type: "attraction", Importance: 0.7},
+			{PlaceID: "city", Class: "boundary", Type: "administrative", Importance: 0.5},
+		}
+	}
+
+	tests := []struct {
+		queryType string
+		want      string
+	}{
+		{queryTypeAddress, "house"},
+		{queryTypeLandmark, "landmark"},
+		{queryTypeArea, "city"},
+	}
+	for _, tt := range tests {
+		candidates := results()
+		if got := candidates[selectBestResult(candidates, tt.queryType)].PlaceID.String(); got != tt.want {
+			t.Errorf("%s query picked %s, want %s", tt.queryType, got, tt.want)
+		}
+	}
+
+	// A house number below the address threshold gives way to the most
+	// important result
+	if err := SetMinImportance(map[string]float64{queryTypeAddress: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	candidates := results()
+	if got := candidates[selectBestResult(candidates, queryTypeAddress)].PlaceID.String(); got != "landmark" {
+		t.Errorf("address query below threshold picked %s, want landmark", got)
+	}
+}
+
+func TestSetMinImportance(t *testing.T) {
+	t.Cleanup(func() { SetMinImportance(nil) })
+
+	thresholds, err := ParseMinImportance("address=0.05, area=0.3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SetMinImportance(thresholds); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if minImportance[queryTypeAddress] != 0.05 || minImportance[queryTypeArea] != 0.3 || minImportance[queryTypeLandmark] != 0.4 {
+		t.Errorf("unexpected thresholds %v", minImportance)
+	}
+
+	for _, spec := range []string{"address", "address=high"} {
+		if _, err := ParseMinImportance(spec); err == nil {
+			t.Errorf("expected an error parsing %q", spec)
+		}
+	}
+	for _, thresholds := range []map[string]float64{{"poi": 0.2}, {queryTypeArea: 1.5}} {
+		if err := SetMinImportance(thresholds); err == nil {
+			t.Errorf("expected an error setting %v", thresholds)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"encoding/json"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+const (
+	// polygonThreshold asks Nominatim to pre-simplify polygons, in degrees
+	polygonThreshold = "0.0001"
+
+	// polygonTolerance is the Douglas-Peucker tolerance in meters applied to
+	// returned polygons
+	polygonTolerance = 10.0
+
+	// maxAreaPolygonPoints caps the points in each returned ring
+	maxAreaPolygonPoints = 500
+)
+
+// AreaGeometry is a GeoJSON Polygon or MultiPolygon geometry
+type AreaGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// areaPolygon simplifies the GeoJSON geometry returned by Nominatim for an
+// area feature. It returns the largest outer ring, ready for tools that take
+// a polygon of locations, and the full simplified geometry for rendering.
+// Point and line features are not areas and return ok=false.
+func areaPolygon(raw json.RawMessage) ([]geo.Location, *AreaGeometry, bool) {
+	if len(raw) == 0 {
+		return nil, nil, false
+	}
+
+	var geometry AreaGeometry
+	if err := json.Unmarshal(raw, &geometry); err != nil {
+		return nil, nil, false
+	}
+
+	var polygons [][][][]float64
+	switch geometry.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+			return nil, nil, false
+		}
+		polygons = [][][][]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return nil, nil, false
+		}
+	default:
+		return nil, nil, false
+	}
+
+	var largest []geo.Location
+	largestArea := -1.0
+	simplifiedPolygons := make([][][][]float64, 0, len(polygons))
+	for _, polygon := range polygons {
+		rings := make([][][]float64, 0, len(polygon))
+		for i, positions := range polygon {
+			ring := make([]geo.Location, 0, len(positions))
+			for _, p := range positions {
+				if len(p) < 2 {
+					continue
+				}
+				ring = append(ring, geo.Location{Latitude: p[1], Longitude: p[0]})
+			}
+			ring = geo.SimplifyToLimit(ring, polygonTolerance, maxAreaPolygonPoints)
+			if len(ring) < 4 {
+				continue // collapsed to a line
+			}
+			if i == 0 {
+				if area := geo.PolygonArea(ring); area > largestArea {
+					largest, largestArea = ring, area
+				}
+			}
+
+			coords := make([][]float64, len(ring))
+			for j, loc := range ring {
+				coords[j] = []float64{loc.Longitude, loc.Latitude}
+			}
+			rings = append(rings, coords)
+		}
+		if len(rings) > 0 {
+			simplifiedPolygons = append(simplifiedPolygons, rings)
+		}
+	}
+	if largest == nil {
+		return nil, nil, false
+	}
+
+	simplified := &AreaGeometry{Type: "MultiPolygon"}
+	var coordinates any = simplifiedPolygons
+	if len(simplifiedPolygons) == 1 {
+		simplified.Type = "Polygon"
+		coordinates = simplifiedPolygons[0]
+	}
+	data, err := json.Marshal(coordinates)
+	if err != nil {
+		return nil, nil, false
+	}
+	simplified.Coordinates = data
+
+	return largest, simplified, true
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAreaPolygon(t *testing.T) {
+	// A square park with a redundant midpoint on one edge, and a smaller
+	// detached part
+	raw := json.RawMessage(`{"type":"MultiPolygon","coordinates":[
+		[[[0,0],[0.001,0],[0.002,0],[0.002,0.002],[0,0.002],[0,0]]],
+		[[[1,1],[1.0005,1],[1.0005,1.0005],[1,1.0005],[1,1]]]
+	]}`)
+
+	polygon, geometry, ok := areaPolygon(raw)
+	if !ok {
+		t.Fatalf("expected an area polygon")
+	}
+	if len(polygon) != 5 {
+		t.Errorf("expected the largest ring simplified to 5 points, got %d", len(polygon))
+	}
+	if polygon[0].Latitude != 0 || polygon[0].Longitude != 0 {
+		t.Errorf("expected the largest ring to be returned, got %v", polygon[0])
+	}
+	if geometry.Type != "MultiPolygon" {
+		t.Errorf("expected MultiPolygon geometry, got %s", geometry.Type)
+	}
+
+	var coords [][][][]float64
+	if err := json.Unmarshal(geometry.Coordinates, &coords); err != nil {
+		t.Fatalf("invalid geometry coordinates: %v", err)
+	}
+	if len(coords) != 2 || len(coords[0][0]) != 5 {
+		t.Errorf("unexpected simplified geometry: %v", coords)
+	}
+}
+
+func TestAreaPolygonPointFeature(t *testing.T) {
+	for _, raw := range []string{
+		`{"type":"Point","coordinates":[13.4,52.5]}`,
+		`{"type":"LineString","coordinates":[[0,0],[1,1]]}`,
+		``,
+	} {
+		if _, _, ok := areaPolygon(json.RawMessage(raw)); ok {
+			t.Errorf("expected %q not to be an area", raw)
+		}
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
 func TestSanitizeAddress(t *testing.T) {
@@ -442,3 +447,85 @@ func TestParentheticalHandling(t *testing.T) {
 			expectedLat, expectedLon)
 	}
 }
+
+func TestHandleGeocodeAddressPlusCode(t *testing.T) {
+	// Full plus codes are decoded locally without a Nominatim request
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "geocode_address",
+			Arguments: map[string]any{"address": "7FG49QCJ+2V"},
+		},
+	}
+
+	result, err := HandleGeocodeAddress(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("HandleGeocodeAddress() failed: %v %v", err, result.Content)
+	}
+
+	var output GeocodeAddressOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if output.Place.ID != "coord-pluscode" {
+		t.Errorf("expected plus code place, got %s", output.Place.ID)
+	}
+	if math.Abs(output.Place.Location.Latitude-20.3700625) > 1e-6 || math.Abs(output.Place.Location.Longitude-2.7821875) > 1e-6 {
+		t.Errorf("unexpected location %+v", output.Place.Location)
+	}
+}
+
+func TestStructuredAddress(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"street": " 10 Downing Street ", "city": "London", "country": "gb", "postalcode": "SW1A 2AA"}
+	address, err := parseStructuredAddress(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if address.isZero() {
+		t.Fatal("expected structured fields")
+	}
+	if got, want := address.String(), "10 Downing Street, London, SW1A 2AA, gb"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if empty, _ := parseStructuredAddress(mcp.CallToolRequest{}); !empty.isZero() {
+		t.Errorf("expected no structured fields, got %+v", empty)
+	}
+}
+
+func TestHandleGeocodeAddressStructured(t *testing.T) {
+	var queries []url.Values
+	nominatim := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"place_id": 1, "display_name": "10, Downing Street, London, SW1A 2AA, United Kingdom", "lat": "51.5034", "lon": "-0.1276", "importance": 0.3, "address": {"house_number": "10", "road": "Downing Street"}}]`))
+	}))
+	defer nominatim.Close()
+	if err := osm.SetServiceURLs(osm.ServiceURLs{Nominatim: nominatim.URL}); err != nil {
+		t.Fatal(err)
+	}
+	defer osm.SetServiceURLs(osm.ServiceURLs{Nominatim: osm.DefaultNominatimBaseURL})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"street": "10 Downing Street", "city": "London", "postalcode": "SW1A 2AA"}
+	result, err := HandleGeocodeAddress(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("HandleGeocodeAddress() failed: %v %v", err, result.Content)
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("expected one Nominatim request, got %d", len(queries))
+	}
+	query := queries[0]
+	if query.Has("q") || query.Get("street") != "10 Downing Street" || query.Get("city") != "London" || query.Get("postalcode") != "SW1A 2AA" || query.Has("state") {
+		t.Errorf("expected a structured query, got %v", query)
+	}
+
+	var output GeocodeAddressOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if output.Place.Address.HouseNumber != "10" {
+		t.Errorf("unexpected place %+v", output.Place)
+	}
+}
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// maxGeohashRing caps the neighbor ring size for geohash_neighbors
+const maxGeohashRing = 5
+
+// GeohashCell describes a geohash cell
+type GeohashCell struct {
+	Geohash  string          `json:"geohash"`
+	Center   geo.Location    `json:"center"`
+	BBox     geo.BoundingBox `json:"bbox"`
+	Boundary []geo.Location  `json:"boundary"` // closed ring, counter-clockwise from south-west
+	Width    float64         `json:"width"`    // in meters at the cell center
+	Height   float64         `json:"height"`   // in meters
+}
+
+// newGeohashCell builds the cell description for a geohash
+func newGeohashCell(hash string) (GeohashCell, error) {
+	bbox, err := geo.DecodeGeohash(hash)
+	if err != nil {
+		return GeohashCell{}, err
+	}
+	center := bbox.Center()
+	return GeohashCell{
+		Geohash: strings.ToLower(hash),
+		Center:  center,
+		BBox:    bbox,
+		Boundary: []geo.Location{
+			{Latitude: bbox.MinLat, Longitude: bbox.MinLon},
+			{Latitude: bbox.MinLat, Longitude: bbox.MaxLon},
+			{Latitude: bbox.MaxLat, Longitude: bbox.MaxLon},
+			{Latitude: bbox.MaxLat, Longitude: bbox.MinLon},
+			{Latitude: bbox.MinLat, Longitude: bbox.MinLon},
+		},
+		Width:  geo.HaversineDistance(center.Latitude, bbox.MinLon, center.Latitude, bbox.MaxLon),
+		Height: geo.HaversineDistance(bbox.MinLat, center.Longitude, bbox.MaxLat, center.Longitude),
+	}, nil
+}
+
+// EncodeGeohashInput defines the input parameters for encode_geohash
+type EncodeGeohashInput struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Precision int     `json:"precision,omitempty"`
+}
+
+// EncodeGeohashTool returns a tool definition for encoding coordinates as a geohash
+func EncodeGeohashTool() mcp.Tool {
+	return mcp.NewTool("encode_geohash",
+		mcp.WithDescription("Encode a coordinate as a geohash cell ID, returning the cell's bounds and size"),
+		mcp.WithNumber("latitude",
+			mcp.Required(),
+			mcp.Description("The latitude coordinate"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Required(),
+			mcp.Description("The longitude coordinate"),
+		),
+		mcp.WithNumber("precision",
+			mcp.Description("Geohash length 1-12; 5 is ~4.9km cells, 7 is ~150m, 9 is ~5m"),
+			mcp.DefaultNumber(9),
+		),
+	)
+}
+
+// HandleEncodeGeohash encodes a coordinate as a geohash
+func HandleEncodeGeohash(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "encode_geohash")
+
+	var input EncodeGeohashInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := core.ValidateCoords(input.Latitude, input.Longitude); err != nil {
+		return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid coordinates: %s", err)).ToMCPResult(), nil
+	}
+	if input.Precision == 0 {
+		input.Precision = 9
+	}
+
+	hash, err := geo.EncodeGeohash(input.Latitude, input.Longitude, input.Precision)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+
+	return geohashCellResult(logger, hash)
+}
+
+// DecodeGeohashTool returns a tool definition for decoding a geohash
+func DecodeGeohashTool() mcp.Tool {
+	return mcp.NewTool("decode_geohash",
+		mcp.WithDescription("Decode a geohash cell ID into its center point, bounding box and boundary polygon"),
+		mcp.WithString("geohash",
+			mcp.Required(),
+			mcp.Description("The geohash to decode (1-12 characters)"),
+		),
+	)
+}
+
+// HandleDecodeGeohash decodes a geohash into its cell geometry
+func HandleDecodeGeohash(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "decode_geohash")
+
+	hash := strings.TrimSpace(mcp.ParseString(req, "geohash", ""))
+	if hash == "" {
+		return core.NewError(core.ErrEmptyParameter, "Geohash must not be empty").ToMCPResult(), nil
+	}
+
+	return geohashCellResult(logger, hash)
+}
+
+// GeohashNeighborsTool returns a tool definition for listing neighboring geohash cells
+func GeohashNeighborsTool() mcp.Tool {
+	return mcp.NewTool("geohash_neighbors",
+		mcp.WithDescription("List the geohash cells surrounding a cell (k-ring), for proximity lookups keyed by geohash"),
+		mcp.WithString("geohash",
+			mcp.Required(),
+			mcp.Description("The center geohash"),
+		),
+		mcp.WithNumber("k",
+			mcp.Description("Ring size: 1 returns the 8 adjacent cells, 2 the surrounding 24, up to 5"),
+			mcp.DefaultNumber(1),
+		),
+	)
+}
+
+// HandleGeohashNeighbors lists the cells within k cells of a geohash
+func HandleGeohashNeighbors(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "geohash_neighbors")
+
+	hash := strings.ToLower(strings.TrimSpace(mcp.ParseString(req, "geohash", "")))
+	if hash == "" {
+		return core.NewError(core.ErrEmptyParameter, "Geohash must not be empty").ToMCPResult(), nil
+	}
+
+	k := int(mcp.ParseFloat64(req, "k", 1))
+	if k < 1 || k > maxGeohashRing {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("k must be between 1 and %d", maxGeohashRing)).ToMCPResult(), nil
+	}
+
+	cells, err := geo.GeohashNeighbors(hash, k)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+
+	output := struct {
+		Geohash   string   `json:"geohash"`
+		K         int      `json:"k"`
+		Neighbors []string `json:"neighbors"`
+	}{
+		Geohash:   hash,
+		K:         k,
+		Neighbors: cells,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// geohashCellResult returns the cell description for a geohash as a tool result
+func geohashCellResult(logger *slog.Logger, hash string) (*mcp.CallToolResult, error) {
+	cell, err := newGeohashCell(hash)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance("Geohashes use the characters 0-9 and b-z, excluding a, i, l and o").
+			ToMCPResult(), nil
+	}
+
+	resultBytes, err := json.Marshal(cell)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleEncodeDecodeGeohash(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "encode_geohash",
+			Arguments: map[string]any{"latitude": 37.7749, "longitude": -122.4194, "precision": 6.0},
+		},
+	}
+	result, err := HandleEncodeGeohash(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("encode failed: %v %v", err, result.Content)
+	}
+
+	var encoded GeohashCell
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &encoded); err != nil {
+		t.Fatal(err)
+	}
+	if encoded.Geohash != "9q8yyk" || len(encoded.Boundary) != 5 {
+		t.Errorf("unexpected encode output: %+v", encoded)
+	}
+	if encoded.Width < 500 || encoded.Width > 1000 || encoded.Height < 500 || encoded.Height > 700 {
+		t.Errorf("unexpected cell size %.0fm x %.0fm", encoded.Width, encoded.Height)
+	}
+
+	req = mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "decode_geohash",
+			Arguments: map[string]any{"geohash": "9Q8YYK"},
+		},
+	}
+	result, err = HandleDecodeGeohash(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("decode failed: %v %v", err, result.Content)
+	}
+
+	var decoded GeohashCell
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.BBox != encoded.BBox {
+		t.Errorf("decoded bbox %+v differs from encoded %+v", decoded.BBox, encoded.BBox)
+	}
+}
+
+func TestHandleGeohashValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		args    map[string]any
+	}{
+		{"Encode invalid latitude", HandleEncodeGeohash, map[string]any{"latitude": 95.0, "longitude": 0.0}},
+		{"Encode precision too high", HandleEncodeGeohash, map[string]any{"latitude": 1.0, "longitude": 1.0, "precision": 20.0}},
+		{"Decode invalid character", HandleDecodeGeohash, map[string]any{"geohash": "9q8yya"}},
+		{"Decode empty", HandleDecodeGeohash, map[string]any{"geohash": ""}},
+		{"Neighbors k too large", HandleGeohashNeighbors, map[string]any{"geohash": "9q8yyk", "k": 10.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.args}}
+			result, err := tt.handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/h3"
+)
+
+const (
+	// defaultH3Resolution is about 0.1 km² cells, roughly a city block
+	defaultH3Resolution = 9
+	// maxH3Ring caps the ring size for h3_neighbors
+	maxH3Ring = 10
+)
+
+// H3Cell describes an H3 cell
+type H3Cell struct {
+	H3         string         `json:"h3"`
+	Resolution int            `json:"resolution"`
+	Center     geo.Location   `json:"center"`
+	Boundary   []geo.Location `json:"boundary"` // closed ring, counter-clockwise
+	Area       float64        `json:"area"`     // in square meters
+	Pentagon   bool           `json:"pentagon,omitempty"`
+}
+
+// newH3Cell builds the cell description for an H3 cell
+func newH3Cell(cell h3.Cell) H3Cell {
+	boundary := cell.Boundary()
+	boundary = append(boundary, boundary[0])
+	return H3Cell{
+		H3:         cell.String(),
+		Resolution: cell.Resolution(),
+		Center:     cell.Center(),
+		Boundary:   boundary,
+		Area:       math.Round(geo.PolygonArea(boundary)*10) / 10,
+		Pentagon:   cell.IsPentagon(),
+	}
+}
+
+// EncodeH3Input defines the input parameters for encode_h3
+type EncodeH3Input struct {
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Resolution *int    `json:"resolution,omitempty"`
+}
+
+// EncodeH3Tool returns a tool definition for encoding coordinates as an H3 cell
+func EncodeH3Tool() mcp.Tool {
+	return mcp.NewTool("encode_h3",
+		mcp.WithDescription("Encode a coordinate as an H3 cell ID, returning the cell's center, boundary polygon and area"),
+		mcp.WithNumber("latitude",
+			mcp.Required(),
+			mcp.Description("The latitude coordinate"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Required(),
+			mcp.Description("The longitude coordinate"),
+		),
+		mcp.WithNumber("resolution",
+			mcp.Description(fmt.Sprintf("H3 resolution 0-%d; 5 is ~250km² cells, 7 is ~5km², 9 is ~0.1km², 12 is ~300m²", h3.MaxResolution)),
+			mcp.DefaultNumber(defaultH3Resolution),
+		),
+	)
+}
+
+// HandleEncodeH3 encodes a coordinate as an H3 cell
+func HandleEncodeH3(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "encode_h3")
+
+	var input EncodeH3Input
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := core.ValidateCoords(input.Latitude, input.Longitude); err != nil {
+		return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid coordinates: %s", err)).ToMCPResult(), nil
+	}
+	resolution := defaultH3Resolution
+	if input.Resolution != nil {
+		resolution = *input.Resolution
+	}
+
+	cell, err := h3.LatLngToCell(input.Latitude, input.Longitude, resolution)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+
+	return h3CellResult(logger, newH3Cell(cell))
+}
+
+// DecodeH3Tool returns a tool definition for decoding an H3 cell
+func DecodeH3Tool() mcp.Tool {
+	return mcp.NewTool("decode_h3",
+		mcp.WithDescription("Decode an H3 cell ID into its resolution, center point, boundary polygon and area"),
+		mcp.WithString("h3",
+			mcp.Required(),
+			mcp.Description("The H3 cell ID in hexadecimal, such as 8928308280fffff"),
+		),
+	)
+}
+
+// HandleDecodeH3 decodes an H3 cell into its geometry
+func HandleDecodeH3(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "decode_h3")
+
+	cell, mcpErr := parseH3Argument(req)
+	if mcpErr != nil {
+		return mcpErr.ToMCPResult(), nil
+	}
+
+	return h3CellResult(logger, newH3Cell(cell))
+}
+
+// H3NeighborsTool returns a tool definition for listing neighboring H3 cells
+func H3NeighborsTool() mcp.Tool {
+	return mcp.NewTool("h3_neighbors",
+		mcp.WithDescription("List the H3 cells surrounding a cell (k-ring), nearest first, for proximity lookups keyed by H3"),
+		mcp.WithString("h3",
+			mcp.Required(),
+			mcp.Description("The center H3 cell ID"),
+		),
+		mcp.WithNumber("k",
+			mcp.Description(fmt.Sprintf("Ring size: 1 returns the 6 adjacent cells, 2 the surrounding 18, up to %d", maxH3Ring)),
+			mcp.DefaultNumber(1),
+		),
+	)
+}
+
+// HandleH3Neighbors lists the cells within k cells of an H3 cell
+func HandleH3Neighbors(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "h3_neighbors")
+
+	cell, mcpErr := parseH3Argument(req)
+	if mcpErr != nil {
+		return mcpErr.ToMCPResult(), nil
+	}
+
+	k := int(mcp.ParseFloat64(req, "k", 1))
+	if k < 1 || k > maxH3Ring {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("k must be between 1 and %d", maxH3Ring)).ToMCPResult(), nil
+	}
+
+	disk, err := h3.GridDisk(cell, k)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+
+	// The disk starts with the cell itself
+	neighbors := make([]string, 0, len(disk)-1)
+	for _, c := range disk[1:] {
+		neighbors = append(neighbors, c.String())
+	}
+
+	output := struct {
+		H3        string   `json:"h3"`
+		K         int      `json:"k"`
+		Neighbors []string `json:"neighbors"`
+	}{
+		H3:        cell.String(),
+		K:         k,
+		Neighbors: neighbors,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// parseH3Argument reads the h3 argument of a request as a cell
+func parseH3Argument(req mcp.CallToolRequest) (h3.Cell, *core.MCPError) {
+	id := strings.TrimSpace(mcp.ParseString(req, "h3", ""))
+	if id == "" {
+		return 0, core.NewError(core.ErrEmptyParameter, "H3 cell ID must not be empty")
+	}
+	cell, err := h3.ParseCell(id)
+	if err != nil {
+		return 0, core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance("H3 cell IDs are 15 hexadecimal digits, as returned by encode_h3")
+	}
+	return cell, nil
+}
+
+// h3CellResult returns an H3 cell description as a tool result
+func h3CellResult(logger *slog.Logger, cell H3Cell) (*mcp.CallToolResult, error) {
+	resultBytes, err := json.Marshal(cell)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
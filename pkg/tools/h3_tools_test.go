@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleEncodeDecodeH3(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "encode_h3",
+			Arguments: map[string]any{"latitude": 37.3615593, "longitude": -122.0553238, "resolution": 7.0},
+		},
+	}
+	result, err := HandleEncodeH3(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("encode failed: %v %v", err, result.Content)
+	}
+
+	var encoded H3Cell
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &encoded); err != nil {
+		t.Fatal(err)
+	}
+	if encoded.H3 != "87283472bffffff" || encoded.Resolution != 7 || len(encoded.Boundary) != 7 {
+		t.Errorf("unexpected encode output: %+v", encoded)
+	}
+	if encoded.Boundary[0] != encoded.Boundary[6] {
+		t.Errorf("boundary is not closed: %+v", encoded.Boundary)
+	}
+	// Resolution 7 cells average 5.16 km²
+	if encoded.Area < 4e6 || encoded.Area > 6.5e6 {
+		t.Errorf("unexpected cell area %.0fm²", encoded.Area)
+	}
+
+	req = mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "decode_h3",
+			Arguments: map[string]any{"h3": "87283472BFFFFFF"},
+		},
+	}
+	result, err = HandleDecodeH3(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("decode failed: %v %v", err, result.Content)
+	}
+
+	var decoded H3Cell
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Center != encoded.Center || decoded.H3 != encoded.H3 {
+		t.Errorf("decoded cell %+v differs from encoded %+v", decoded, encoded)
+	}
+}
+
+func TestHandleH3Neighbors(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "h3_neighbors",
+			Arguments: map[string]any{"h3": "8928308280fffff", "k": 2.0},
+		},
+	}
+	result, err := HandleH3Neighbors(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("neighbors failed: %v %v", err, result.Content)
+	}
+
+	var output struct {
+		H3        string   `json:"h3"`
+		K         int      `json:"k"`
+		Neighbors []string `json:"neighbors"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Neighbors) != 18 {
+		t.Fatalf("got %d neighbors, want 18", len(output.Neighbors))
+	}
+	for _, n := range output.Neighbors {
+		if n == output.H3 {
+			t.Error("neighbors include the center cell")
+		}
+	}
+}
+
+func TestHandleH3Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		args    map[string]any
+	}{
+		{"Encode invalid latitude", HandleEncodeH3, map[string]any{"latitude": 95.0, "longitude": 0.0}},
+		{"Encode resolution too high", HandleEncodeH3, map[string]any{"latitude": 1.0, "longitude": 1.0, "resolution": 16.0}},
+		{"Encode negative resolution", HandleEncodeH3, map[string]any{"latitude": 1.0, "longitude": 1.0, "resolution": -1.0}},
+		{"Decode invalid cell", HandleDecodeH3, map[string]any{"h3": "8928308280ffff7"}},
+		{"Decode empty", HandleDecodeH3, map[string]any{"h3": ""}},
+		{"Neighbors k too large", HandleH3Neighbors, map[string]any{"h3": "8928308280fffff", "k": 20.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.args}}
+			result, err := tt.handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
 )
 
 // InputParser is a generic function to parse request arguments into a strongly typed struct
@@ -62,14 +65,18 @@ func WithParsedInput[T any](
 }
 
 // ValidateCoordinates validates latitude and longitude are within valid ranges
+// Deprecated: Use core.ValidateCoords instead
 func ValidateCoordinates(lat, lon float64) error {
-	if lat < -90 || lat > 90 {
-		return fmt.Errorf("latitude must be between -90 and 90, got %f", lat)
-	}
-	if lon < -180 || lon > 180 {
-		return fmt.Errorf("longitude must be between -180 and 180, got %f", lon)
+	return core.ValidateCoords(lat, lon)
+}
+
+// coordinateErrorCode returns the error code of a coordinate validation
+// error, or INVALID_COORDINATES for other errors
+func coordinateErrorCode(err error) string {
+	if valErr, ok := err.(core.ValidationError); ok {
+		return valErr.Code
 	}
-	return nil
+	return geo.ErrCodeInvalidCoordinates
 }
 
 // ValidateRadius validates that a radius is positive and within the specified maximum
@@ -92,7 +99,7 @@ func WithValidatedCoordinates[T any](
 	return WithParsedInput(handlerName, func(ctx context.Context, input T, logger *slog.Logger) (interface{}, error) {
 		// Extract and validate coordinates
 		lat, lon := getCoords(input)
-		if err := ValidateCoordinates(lat, lon); err != nil {
+		if err := core.ValidateCoords(lat, lon); err != nil {
 			return nil, err
 		}
 
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// maxHydrateElements caps the elements fetched in one call
+	maxHydrateElements = 100
+	// maxHydrateGeometryPoints caps the points of each way geometry
+	maxHydrateGeometryPoints = 500
+	// hydrateGeometryTolerance is the Douglas-Peucker tolerance in meters
+	// applied to way geometries over the cap
+	hydrateGeometryTolerance = 2.0
+)
+
+// RelationMember is a member of a relation
+type RelationMember struct {
+	Type string `json:"type"`
+	Ref  int64  `json:"ref"`
+	Role string `json:"role,omitempty"`
+}
+
+// HydratedElement is an OSM element with all of its tags and geometry
+type HydratedElement struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Location *geo.Location     `json:"location,omitempty"` // nodes
+	Center   *geo.Location     `json:"center,omitempty"`   // ways and relations
+	Tags     map[string]string `json:"tags,omitempty"`
+	Geometry []geo.Location    `json:"geometry,omitempty"` // way outline or line, simplified when long
+	Members  []RelationMember  `json:"members,omitempty"`  // relations
+}
+
+// HydrateElementsOutput defines the output for hydrate_elements
+type HydrateElementsOutput struct {
+	Elements []HydratedElement `json:"elements"`
+	Missing  []string          `json:"missing,omitempty"` // requested elements Overpass did not return, e.g. deleted ones
+}
+
+// HydrateElementsTool returns a tool definition for fetching the full
+// details of elements by ID
+func HydrateElementsTool() mcp.Tool {
+	return mcp.NewTool("hydrate_elements",
+		mcp.WithDescription(fmt.Sprintf("Fetch the full tags and geometry of up to %d OSM elements by type and ID in one Overpass request, e.g. for the few results of a trimmed or clustered listing the user cares about. Elements are returned in the order requested", maxHydrateElements)),
+		mcp.WithArray("elements",
+			mcp.Required(),
+			mcp.Description("Elements to fetch, each as a string such as \"node/2003764150\" or \"W24312356\", or an object {\"type\": \"way\", \"id\": 24312356}"),
+		),
+	)
+}
+
+// HandleHydrateElements fetches the full details of elements by ID
+func HandleHydrateElements(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "hydrate_elements")
+
+	ids, errResult := parseHydrateElements(req.GetArguments()["elements"], maxHydrateElements)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	elements, err := executeOverpassQuery(ctx, buildHydrateQuery(ids))
+	if err != nil {
+		logger.Error("failed to fetch elements", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("Overpass", http.StatusServiceUnavailable,
+			"Failed to fetch the elements").ToMCPResult(), nil
+	}
+
+	byID := make(map[string]osm.OverpassElement, len(elements))
+	for _, element := range elements {
+		byID[element.Type+"/"+strconv.Itoa(element.ID)] = element
+	}
+
+	output := HydrateElementsOutput{Elements: make([]HydratedElement, 0, len(ids))}
+	for _, id := range ids {
+		element, ok := byID[id]
+		if !ok {
+			output.Missing = append(output.Missing, id)
+			continue
+		}
+		output.Elements = append(output.Elements, hydrateElement(element))
+	}
+
+	logger.Info("hydrated elements", "requested", len(ids), "found", len(output.Elements))
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// parseHydrateElements reads up to limit requested elements as "type/id"
+// strings, dropping duplicates
+func parseHydrateElements(raw any, limit int) ([]string, *mcp.CallToolResult) {
+	items, ok := raw.([]any)
+	if !ok || len(items) == 0 {
+		return nil, core.NewError(core.ErrMissingParameter, "elements is required").
+			WithGuidance("Pass elements as [\"node/2003764150\", {\"type\": \"way\", \"id\": 24312356}]").
+			ToMCPResult()
+	}
+	if len(items) > limit {
+		return nil, core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("At most %d elements can be fetched at once, got %d", limit, len(items))).ToMCPResult()
+	}
+
+	seen := make(map[string]bool, len(items))
+	ids := make([]string, 0, len(items))
+	for i, item := range items {
+		var id, osmType string
+		switch v := item.(type) {
+		case string:
+			id = v
+		case map[string]any:
+			osmType, _ = v["type"].(string)
+			switch ref := v["id"].(type) {
+			case float64:
+				id = strconv.FormatFloat(ref, 'f', -1, 64)
+			case string:
+				id = ref
+			}
+		}
+
+		codes, errResult := parseOSMObjectID(strings.TrimSpace(id), osmType)
+		if errResult != nil {
+			return nil, errResult
+		}
+		if len(codes) != 1 {
+			return nil, core.NewError(core.ErrInvalidParameter,
+				fmt.Sprintf("elements[%d] needs an element type", i)).
+				WithGuidance("Give IDs such as node/2003764150, or objects with type and id").
+				ToMCPResult()
+		}
+		key := osmTypeNames[codes[0][:1]] + "/" + codes[0][1:]
+		if !seen[key] {
+			seen[key] = true
+			ids = append(ids, key)
+		}
+	}
+	return ids, nil
+}
+
+// buildHydrateQuery builds an Overpass query for elements given as
+// "type/id". Nodes and ways are returned with their geometry; relations
+// with their center and members, since full member geometry can be huge.
+func buildHydrateQuery(ids []string) string {
+	byType := make(map[string][]string)
+	for _, id := range ids {
+		osmType, ref, _ := strings.Cut(id, "/")
+		byType[osmType] = append(byType[osmType], ref)
+	}
+	for _, refs := range byType {
+		sort.Strings(refs) // stable queries share the Overpass cache
+	}
+
+	var query strings.Builder
+	query.WriteString("[out:json][timeout:25];\n")
+	if len(byType["node"])+len(byType["way"]) > 0 {
+		query.WriteString("(")
+		for _, osmType := range []string{"node", "way"} {
+			if refs := byType[osmType]; len(refs) > 0 {
+				fmt.Fprintf(&query, "%s(id:%s);", osmType, strings.Join(refs, ","))
+			}
+		}
+		query.WriteString(");\nout geom;\n")
+	}
+	if refs := byType["relation"]; len(refs) > 0 {
+		fmt.Fprintf(&query, "relation(id:%s);\nout center;\n", strings.Join(refs, ","))
+	}
+	return query.String()
+}
+
+// hydrateElement converts an Overpass element, centering ways on their
+// geometry
+func hydrateElement(element osm.OverpassElement) HydratedElement {
+	hydrated := HydratedElement{
+		ID:   strconv.Itoa(element.ID),
+		Type: element.Type,
+		Tags: element.Tags,
+	}
+
+	if element.Type == "node" {
+		hydrated.Location = &geo.Location{Latitude: element.Lat, Longitude: element.Lon}
+	}
+	if element.Center != nil {
+		hydrated.Center = &geo.Location{Latitude: element.Center.Lat, Longitude: element.Center.Lon}
+	}
+
+	if len(element.Geometry) > 0 {
+		path := make([]geo.Location, len(element.Geometry))
+		for i, point := range element.Geometry {
+			path[i] = geo.Location{Latitude: point.Lat, Longitude: point.Lon}
+		}
+		hydrated.Geometry = geo.SimplifyToLimit(path, hydrateGeometryTolerance, maxHydrateGeometryPoints)
+		if hydrated.Center == nil {
+			box := geo.NewBoundingBox()
+			for _, point := range path {
+				box.ExtendWithPoint(point.Latitude, point.Longitude)
+			}
+			center := box.Center()
+			hydrated.Center = &center
+		}
+	}
+
+	for _, member := range element.Members {
+		hydrated.Members = append(hydrated.Members, RelationMember{Type: member.Type, Ref: member.Ref, Role: member.Role})
+	}
+	return hydrated
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestParseHydrateElements(t *testing.T) {
+	ids, errResult := parseHydrateElements([]any{
+		"node/2003764150",
+		"W24312356",
+		map[string]any{"type": "relation", "id": 1124039.0},
+		map[string]any{"type": "way", "id": "24312356"},
+	}, maxHydrateElements)
+	if errResult != nil {
+		t.Fatalf("unexpected error: %+v", errResult)
+	}
+	if want := []string{"node/2003764150", "way/24312356", "relation/1124039"}; !slices.Equal(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+
+	for _, raw := range []any{nil, []any{}, []any{"2003764150"}, []any{map[string]any{"id": 1.0}}, []any{"street/1"}} {
+		if _, errResult := parseHydrateElements(raw, maxHydrateElements); errResult == nil {
+			t.Errorf("expected an error for %v", raw)
+		}
+	}
+}
+
+func TestBuildHydrateQuery(t *testing.T) {
+	query := buildHydrateQuery([]string{"way/2", "node/9", "relation/5", "node/1"})
+	want := "[out:json][timeout:25];\n(node(id:1,9);way(id:2););\nout geom;\nrelation(id:5);\nout center;\n"
+	if query != want {
+		t.Errorf("got query\n%s\nwant\n%s", query, want)
+	}
+
+	if query := buildHydrateQuery([]string{"relation/5"}); query != "[out:json][timeout:25];\nrelation(id:5);\nout center;\n" {
+		t.Errorf("unexpected relation-only query\n%s", query)
+	}
+}
+
+func TestHydrateElement(t *testing.T) {
+	way := osm.OverpassElement{ID: 7, Type: "way", Tags: map[string]string{"highway": "residential"}}
+	for _, point := range [][2]float64{{0, 0}, {0, 0.002}, {0.002, 0.002}} {
+		way.Geometry = append(way.Geometry, struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		}{point[0], point[1]})
+	}
+
+	hydrated := hydrateElement(way)
+	if hydrated.ID != "7" || len(hydrated.Geometry) != 3 || hydrated.Location != nil {
+		t.Errorf("unexpected element %+v", hydrated)
+	}
+	if hydrated.Center == nil || hydrated.Center.Latitude != 0.001 || hydrated.Center.Longitude != 0.001 {
+		t.Errorf("expected the way centered on its geometry, got %+v", hydrated.Center)
+	}
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// levelDescription documents the level filter shared by POI tools
+const levelDescription = "Only return places on this indoor level, using OSM level numbers (0 is usually the ground floor, -1 the first basement, 1.5 a mezzanine). Places without a level tag are excluded"
+
+// PlaceLevel is the floor a place is mapped on in indoor-mapped buildings
+// such as malls, stations and airports
+type PlaceLevel struct {
+	Value  string    `json:"value,omitempty"`  // raw level tag, e.g. "1" or "0;1"
+	Levels []float64 `json:"levels,omitempty"` // every level the place is on
+	Ref    string    `json:"ref,omitempty"`    // level name shown in the building, e.g. "G" or "L2"
+	Indoor string    `json:"indoor,omitempty"` // indoor tag, e.g. room, area or corridor
+}
+
+// placeLevel extracts the level and indoor tags of a feature. It returns nil
+// for features without indoor mapping.
+func placeLevel(tags map[string]string) *PlaceLevel {
+	level := &PlaceLevel{
+		Value:  tags["level"],
+		Levels: elementLevels(tags),
+		Ref:    tags["level:ref"],
+		Indoor: tags["indoor"],
+	}
+	if level.Ref == "" {
+		level.Ref = tags["addr:floor"]
+	}
+	if level.Value == "" && len(level.Levels) == 0 && level.Ref == "" && level.Indoor == "" {
+		return nil
+	}
+	return level
+}
+
+// elementLevels returns the levels a feature is on, from its level tag and
+// the repeat_on tag used for features present on several floors
+func elementLevels(tags map[string]string) []float64 {
+	levels := parseLevels(tags["level"])
+	for _, level := range parseLevels(tags["repeat_on"]) {
+		if !containsLevel(levels, level) {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// parseLevels parses an OSM level value: a number, a semicolon separated
+// list ("0;1") or a range ("-1-2"). Ranges expand to their whole levels plus
+// the endpoints. Invalid parts are ignored.
+func parseLevels(value string) []float64 {
+	var levels []float64
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if low, high, ok := parseLevelRange(part); ok {
+			levels = append(levels, low)
+			for l := math.Floor(low) + 1; l < high; l++ {
+				levels = append(levels, l)
+			}
+			if high != low {
+				levels = append(levels, high)
+			}
+			continue
+		}
+
+		if level, err := strconv.ParseFloat(part, 64); err == nil {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// parseLevelRange parses "low-high", where either bound may be negative
+func parseLevelRange(part string) (float64, float64, bool) {
+	// The separator is a dash that is not a leading minus sign
+	for i := 1; i < len(part); i++ {
+		if part[i] != '-' || part[i-1] == '-' {
+			continue
+		}
+		low, errLow := strconv.ParseFloat(part[:i], 64)
+		high, errHigh := strconv.ParseFloat(part[i+1:], 64)
+		if errLow != nil || errHigh != nil {
+			return 0, 0, false
+		}
+		if low > high {
+			low, high = high, low
+		}
+		return low, high, true
+	}
+	return 0, 0, false
+}
+
+// parseLevelFilter parses the level argument of a POI tool. It returns false
+// when no level was requested.
+func parseLevelFilter(value string) (float64, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false, nil
+	}
+	level, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(level) || math.IsInf(level, 0) {
+		return 0, false, fmt.Errorf("level must be a number such as 0, -1 or 2, got %q", value)
+	}
+	return level, true, nil
+}
+
+// onLevel reports whether a feature is mapped on the given level
+func onLevel(tags map[string]string, level float64) bool {
+	return containsLevel(elementLevels(tags), level)
+}
+
+// containsLevel reports whether a level list includes a level
+func containsLevel(levels []float64, level float64) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []float64
+	}{
+		{"0", []float64{0}},
+		{"-1", []float64{-1}},
+		{"1.5", []float64{1.5}},
+		{"0;1", []float64{0, 1}},
+		{"0-2", []float64{0, 1, 2}},
+		{"-2--1", []float64{-2, -1}},
+		{"-1-1", []float64{-1, 0, 1}},
+		{"2-2", []float64{2}},
+		{"G", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseLevels(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseLevels(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestOnLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		tags  map[string]string
+		level float64
+		want  bool
+	}{
+		{"matching level", map[string]string{"level": "1"}, 1, true},
+		{"other level", map[string]string{"level": "1"}, 0, false},
+		{"level list", map[string]string{"level": "0;1"}, 1, true},
+		{"repeat_on", map[string]string{"level": "0", "repeat_on": "1-3"}, 2, true},
+		{"no level tag", map[string]string{"name": "Cafe"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got := onLevel(tt.tags, tt.level); got != tt.want {
+			t.Errorf("%s: onLevel = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceLevel(t *testing.T) {
+	if level := placeLevel(map[string]string{"name": "Kiosk"}); level != nil {
+		t.Errorf("expected no level for a feature without indoor tags, got %+v", level)
+	}
+
+	level := placeLevel(map[string]string{"level": "-1", "level:ref": "B1", "indoor": "room"})
+	want := &PlaceLevel{Value: "-1", Levels: []float64{-1}, Ref: "B1", Indoor: "room"}
+	if !reflect.DeepEqual(level, want) {
+		t.Errorf("placeLevel = %+v, want %+v", level, want)
+	}
+
+	// addr:floor names the floor when level:ref is missing
+	level = placeLevel(map[string]string{"addr:floor": "3"})
+	if level == nil || level.Ref != "3" {
+		t.Errorf("expected addr:floor as the level ref, got %+v", level)
+	}
+}
+
+func TestParseLevelFilter(t *testing.T) {
+	if _, ok, err := parseLevelFilter(""); ok || err != nil {
+		t.Errorf("empty level should disable the filter, got ok=%v err=%v", ok, err)
+	}
+	if level, ok, err := parseLevelFilter("-1"); !ok || err != nil || level != -1 {
+		t.Errorf("parseLevelFilter(-1) = %v, %v, %v", level, ok, err)
+	}
+	if _, _, err := parseLevelFilter("ground"); err == nil {
+		t.Error("expected an error for a non-numeric level")
+	}
+}
@@ -0,0 +1,348 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// defaultIntersectionRadius is the search radius around the reference point in meters
+	defaultIntersectionRadius = 3000.0
+	// maxIntersectionRadius caps the search radius in meters
+	maxIntersectionRadius = 20000.0
+	// intersectionMergeDistance merges junction nodes closer than this many
+	// meters, such as the carriageways of a divided road, into one intersection
+	intersectionMergeDistance = 40.0
+	// maxIntersections caps the number of intersections returned
+	maxIntersections = 10
+)
+
+// intersectionSeparators split "A & B" style queries, in order of
+// preference. The words "at" and "x" also occur in street names, as in
+// "Malcolm X Blvd", so they are only tried when no other separator is
+// present.
+var intersectionSeparators = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\s*(?:&|/|@|\+|\band\b)\s*`),
+	regexp.MustCompile(`(?i)\s+at\s+`),
+	regexp.MustCompile(`(?i)\s+x\s+`),
+}
+
+// streetAbbreviations maps common street name words to their abbreviations
+var streetAbbreviations = map[string]string{
+	"street":    "st",
+	"avenue":    "ave",
+	"road":      "rd",
+	"boulevard": "blvd",
+	"drive":     "dr",
+	"lane":      "ln",
+	"court":     "ct",
+	"place":     "pl",
+	"highway":   "hwy",
+	"parkway":   "pkwy",
+	"square":    "sq",
+	"terrace":   "ter",
+	"north":     "n",
+	"south":     "s",
+	"east":      "e",
+	"west":      "w",
+}
+
+// Intersection is a junction of the two requested streets
+type Intersection struct {
+	Location Location `json:"location"`
+	Streets  []string `json:"streets"`            // names of all streets meeting here
+	NodeIDs  []string `json:"node_ids"`           // junction nodes merged into this intersection
+	Distance float64  `json:"distance,omitempty"` // meters from the reference point
+}
+
+// FindIntersectionOutput defines the output for find_intersection
+type FindIntersectionOutput struct {
+	StreetA       string         `json:"street_a"`
+	StreetB       string         `json:"street_b"`
+	Intersections []Intersection `json:"intersections"`
+}
+
+// FindIntersectionTool returns a tool definition for geocoding street intersections
+func FindIntersectionTool() mcp.Tool {
+	return mcp.NewTool("find_intersection",
+		mcp.WithDescription("Find where two named streets meet, e.g. \"Main St & 5th Ave\", by locating the nodes the two streets share in OpenStreetMap. Use instead of geocode_address for intersections, which the geocoder handles poorly. Needs a reference point (near) or a city to search around; the closest intersection comes first"),
+		mcp.WithString("query",
+			mcp.Description("Intersection as one string, e.g. \"Main St & 5th Ave\", \"Broadway and W 42nd Street\". Alternative to street_a and street_b"),
+		),
+		mcp.WithString("street_a",
+			mcp.Description("Name of the first street"),
+		),
+		mcp.WithString("street_b",
+			mcp.Description("Name of the second street"),
+		),
+		mcp.WithObject("near",
+			mcp.Description("Reference point {latitude, longitude} to search around"),
+		),
+		mcp.WithString("city",
+			mcp.Description("City or area to search in when no reference point is known, e.g. \"Portland, Oregon\""),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("Search radius in meters around the reference point (max 20000)"),
+			mcp.DefaultNumber(defaultIntersectionRadius),
+		),
+	)
+}
+
+// HandleFindIntersection finds the junctions of two named streets
+func HandleFindIntersection(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "find_intersection")
+
+	streetA := strings.TrimSpace(mcp.ParseString(req, "street_a", ""))
+	streetB := strings.TrimSpace(mcp.ParseString(req, "street_b", ""))
+	if query := strings.TrimSpace(mcp.ParseString(req, "query", "")); query != "" && (streetA == "" || streetB == "") {
+		var ok bool
+		streetA, streetB, ok = parseIntersectionQuery(query)
+		if !ok {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Could not split %q into two street names", query)).
+				WithGuidance("Separate the streets with '&' or 'and', e.g. \"Main St & 5th Ave\", or pass street_a and street_b").
+				ToMCPResult(), nil
+		}
+	}
+	if streetA == "" || streetB == "" {
+		return core.NewError(core.ErrMissingParameter, "Two street names are required").
+			WithGuidance("Pass query (\"Main St & 5th Ave\") or both street_a and street_b").
+			ToMCPResult(), nil
+	}
+	for _, name := range []string{streetA, streetB} {
+		if err := core.ValidateStringLength(name, 1, maxAddressLength); err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+		}
+	}
+
+	radius := mcp.ParseFloat64(req, "radius", defaultIntersectionRadius)
+	if err := core.ValidateRadius(radius, maxIntersectionRadius); err != nil {
+		return core.NewError(core.ErrInvalidRadius, err.Error()).ToMCPResult(), nil
+	}
+
+	// Resolve the reference point
+	var center geo.Location
+	if near, ok := req.GetArguments()["near"].(map[string]any); ok {
+		lat, _ := near["latitude"].(float64)
+		lon, _ := near["longitude"].(float64)
+		if err := core.ValidateCoords(lat, lon); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid near coordinates: %s", err)).ToMCPResult(), nil
+		}
+		center = geo.Location{Latitude: lat, Longitude: lon}
+	} else if city := strings.TrimSpace(mcp.ParseString(req, "city", "")); city != "" {
+		results, err := geocodeQuery(ctx, city)
+		if err != nil || len(results) == 0 {
+			logger.Warn("failed to geocode city", "city", city, "error", err)
+			return core.NewError(core.ErrNoResults, fmt.Sprintf("Could not locate %q", city)).
+				WithGuidance("Check the city name or pass a near point instead").
+				ToMCPResult(), nil
+		}
+		place, err := resultToPlace(results[0])
+		if err != nil {
+			return core.NewError(core.ErrParseError, "Failed to read the city location").ToMCPResult(), nil
+		}
+		center = geo.Location{Latitude: place.Location.Latitude, Longitude: place.Location.Longitude}
+	} else {
+		return core.NewError(core.ErrMissingParameter, "A reference point or city is required").
+			WithGuidance("Street names repeat across cities; pass near {latitude, longitude} or city").
+			ToMCPResult(), nil
+	}
+
+	elements, err := executeOverpassQuery(ctx, buildIntersectionQuery(streetA, streetB, center, radius))
+	if err != nil {
+		logger.Error("failed to query intersection", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("Overpass", http.StatusServiceUnavailable,
+			"Failed to search for the intersection").ToMCPResult(), nil
+	}
+
+	intersections := elementsToIntersections(elements, center)
+	if len(intersections) == 0 {
+		return core.NewError(core.ErrNoResults, fmt.Sprintf("No intersection of %q and %q found within %.0f m", streetA, streetB, radius)).
+			WithGuidance("Check the spelling of both streets, widen the radius, or move the reference point closer").
+			ToMCPResult(), nil
+	}
+	if len(intersections) > maxIntersections {
+		intersections = intersections[:maxIntersections]
+	}
+
+	output := FindIntersectionOutput{
+		StreetA:       streetA,
+		StreetB:       streetB,
+		Intersections: intersections,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// parseIntersectionQuery splits "A & B" style queries into two street names
+func parseIntersectionQuery(query string) (string, string, bool) {
+	query = strings.TrimSpace(query)
+	lower := strings.ToLower(query)
+	for _, prefix := range []string{"corner of ", "intersection of "} {
+		if strings.HasPrefix(lower, prefix) {
+			query = query[len(prefix):]
+			break
+		}
+	}
+
+	var parts []string
+	for _, separator := range intersectionSeparators {
+		if separator.MatchString(query) {
+			parts = separator.Split(query, -1)
+			break
+		}
+	}
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	a, b := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if a == "" || b == "" {
+		return "", "", false
+	}
+	return a, b, true
+}
+
+// streetNamePattern builds a case-insensitive Overpass regex matching a
+// street name with or without common abbreviations ("Main St" matches
+// "Main Street" and vice versa)
+func streetNamePattern(name string) string {
+	expansions := make(map[string]string, len(streetAbbreviations))
+	for full, abbr := range streetAbbreviations {
+		expansions[abbr] = full
+	}
+
+	words := strings.Fields(strings.ToLower(name))
+	patterns := make([]string, len(words))
+	for i, word := range words {
+		word = strings.TrimSuffix(word, ".")
+		switch {
+		case streetAbbreviations[word] != "":
+			patterns[i] = fmt.Sprintf("(%s|%s\\.?)", regexp.QuoteMeta(word), regexp.QuoteMeta(streetAbbreviations[word]))
+		case expansions[word] != "":
+			patterns[i] = fmt.Sprintf("(%s|%s\\.?)", regexp.QuoteMeta(expansions[word]), regexp.QuoteMeta(word))
+		default:
+			patterns[i] = regexp.QuoteMeta(word)
+		}
+	}
+
+	pattern := "^" + strings.Join(patterns, " ") + "$"
+	return strings.ReplaceAll(strings.ReplaceAll(pattern, `\`, `\\`), `"`, `\"`)
+}
+
+// buildIntersectionQuery finds the nodes shared by the two streets and the
+// ways meeting at them
+func buildIntersectionQuery(streetA, streetB string, center geo.Location, radius float64) string {
+	around := fmt.Sprintf("(around:%.1f,%.6f,%.6f)", radius, center.Latitude, center.Longitude)
+	return fmt.Sprintf(`[out:json][timeout:25];`+
+		`way["highway"]["name"~"%s",i]%s->.a;`+
+		`way["highway"]["name"~"%s",i]%s->.b;`+
+		`node(w.a)->.na;node(w.b)->.nb;`+
+		`node.na.nb->.x;`+
+		`.x out;`+
+		`way(bn.x)["highway"]["name"];out body;`,
+		streetNamePattern(streetA), around, streetNamePattern(streetB), around)
+}
+
+// elementsToIntersections groups junction nodes into intersections, sorted
+// by distance from the reference point and then node ID
+func elementsToIntersections(elements []osm.OverpassElement, center geo.Location) []Intersection {
+	// Street names meeting at each node
+	streetsByNode := make(map[int64][]string)
+	for _, element := range elements {
+		if element.Type != "way" {
+			continue
+		}
+		for _, node := range element.Nodes {
+			streetsByNode[node] = appendUnique(streetsByNode[node], element.Tags["name"])
+		}
+	}
+
+	var nodes []osm.OverpassElement
+	for _, element := range elements {
+		if element.Type == "node" {
+			nodes = append(nodes, element)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	// Merge nodes of the same physical junction, such as divided roads
+	var intersections []Intersection
+	var members [][]geo.Location
+	for _, node := range nodes {
+		id := strconv.Itoa(node.ID)
+		streets := streetsByNode[int64(node.ID)]
+
+		merged := false
+		for i := range intersections {
+			loc := intersections[i].Location
+			if geo.HaversineDistance(loc.Latitude, loc.Longitude, node.Lat, node.Lon) <= intersectionMergeDistance {
+				members[i] = append(members[i], geo.Location{Latitude: node.Lat, Longitude: node.Lon})
+				intersections[i].NodeIDs = append(intersections[i].NodeIDs, id)
+				for _, street := range streets {
+					intersections[i].Streets = appendUnique(intersections[i].Streets, street)
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			intersections = append(intersections, Intersection{
+				Location: Location{Latitude: node.Lat, Longitude: node.Lon},
+				Streets:  append([]string(nil), streets...),
+				NodeIDs:  []string{id},
+			})
+			members = append(members, []geo.Location{{Latitude: node.Lat, Longitude: node.Lon}})
+		}
+	}
+
+	for i := range intersections {
+		var lat, lon float64
+		for _, m := range members[i] {
+			lat += m.Latitude
+			lon += m.Longitude
+		}
+		n := float64(len(members[i]))
+		intersections[i].Location = Location{Latitude: lat / n, Longitude: lon / n}
+		intersections[i].Distance = geo.HaversineDistance(center.Latitude, center.Longitude, lat/n, lon/n)
+		sort.Strings(intersections[i].Streets)
+	}
+
+	sortByDistanceThenID(intersections, func(in Intersection) (float64, string) {
+		return in.Distance, in.NodeIDs[0]
+	})
+	return intersections
+}
+
+// appendUnique appends a non-empty value that is not already present
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestParseIntersectionQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		a, b  string
+		ok    bool
+	}{
+		{"Main St & 5th Ave", "Main St", "5th Ave", true},
+		{"Broadway and W 42nd Street", "Broadway", "W 42nd Street", true},
+		{"corner of Haight St at Ashbury St", "Haight St", "Ashbury St", true},
+		{"Market Street / Castro Street", "Market Street", "Castro Street", true},
+		{"Malcolm X Blvd & 125th St", "Malcolm X Blvd", "125th St", true},
+		{"Malcolm X Blvd at 125th St", "Malcolm X Blvd", "125th St", true},
+		{"Rua Augusta x Rua Oscar Freire", "Rua Augusta", "Rua Oscar Freire", true},
+		{"Main Street", "", "", false},
+		{"A & B & C", "", "", false},
+	}
+
+	for _, tt := range tests {
+		a, b, ok := parseIntersectionQuery(tt.query)
+		if a != tt.a || b != tt.b || ok != tt.ok {
+			t.Errorf("parseIntersectionQuery(%q) = %q, %q, %v; want %q, %q, %v", tt.query, a, b, ok, tt.a, tt.b, tt.ok)
+		}
+	}
+}
+
+func TestStreetNamePattern(t *testing.T) {
+	// Overpass string escaping doubles backslashes; undo it to test the regex
+	compile := func(name string) *regexp.Regexp {
+		pattern := strings.ReplaceAll(streetNamePattern(name), `\\`, `\`)
+		return regexp.MustCompile("(?i)" + pattern)
+	}
+
+	tests := []struct {
+		name    string
+		matches []string
+		rejects []string
+	}{
+		{"Main St", []string{"Main Street", "Main St", "Main St."}, []string{"Main Street East", "Old Main Street"}},
+		{"5th Avenue", []string{"5th Ave", "5th Avenue"}, []string{"15th Avenue"}},
+		{"W 42nd Street", []string{"West 42nd Street", "W 42nd St"}, nil},
+	}
+
+	for _, tt := range tests {
+		re := compile(tt.name)
+		for _, m := range tt.matches {
+			if !re.MatchString(m) {
+				t.Errorf("pattern for %q should match %q", tt.name, m)
+			}
+		}
+		for _, r := range tt.rejects {
+			if re.MatchString(r) {
+				t.Errorf("pattern for %q should not match %q", tt.name, r)
+			}
+		}
+	}
+}
+
+func TestElementsToIntersections(t *testing.T) {
+	center := geo.Location{Latitude: 40.0, Longitude: -75.0}
+	elements := []osm.OverpassElement{
+		// Two nodes of a divided road junction, 20 m apart
+		{ID: 101, Type: "node", Lat: 40.0010, Lon: -75.0},
+		{ID: 102, Type: "node", Lat: 40.0010, Lon: -75.00023},
+		// A second junction further away
+		{ID: 200, Type: "node", Lat: 40.0100, Lon: -75.0},
+		{ID: 1, Type: "way", Nodes: []int64{101, 200}, Tags: map[string]string{"name": "Main Street"}},
+		{ID: 2, Type: "way", Nodes: []int64{101}, Tags: map[string]string{"name": "5th Avenue"}},
+		{ID: 3, Type: "way", Nodes: []int64{102}, Tags: map[string]string{"name": "5th Avenue"}},
+		{ID: 4, Type: "way", Nodes: []int64{200}, Tags: map[string]string{"name": "5th Avenue"}},
+	}
+
+	intersections := elementsToIntersections(elements, center)
+	if len(intersections) != 2 {
+		t.Fatalf("expected 2 intersections, got %d: %+v", len(intersections), intersections)
+	}
+
+	first := intersections[0]
+	if len(first.NodeIDs) != 2 || first.NodeIDs[0] != "101" {
+		t.Errorf("expected the divided junction to be merged, got %v", first.NodeIDs)
+	}
+	if strings.Join(first.Streets, ",") != "5th Avenue,Main Street" {
+		t.Errorf("unexpected streets: %v", first.Streets)
+	}
+	if first.Distance >= intersections[1].Distance {
+		t.Errorf("expected intersections sorted by distance")
+	}
+}
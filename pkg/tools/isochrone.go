@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// maxIsochroneMinutes bounds the travel time of an isochrone
+const maxIsochroneMinutes = 60
+
+// IsochroneGeometry is a GeoJSON Polygon
+type IsochroneGeometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"` // [longitude, latitude]
+}
+
+// IsochroneOutput defines the output for get_isochrone
+type IsochroneOutput struct {
+	Origin    geo.Location      `json:"origin"`
+	Mode      string            `json:"mode"`
+	Minutes   float64           `json:"minutes"`
+	Polygon   []geo.Location    `json:"polygon"`  // closed ring, usable as search_isochrone_boundary input
+	Polyline  string            `json:"polyline"` // the ring as an encoded polyline
+	GeoJSON   IsochroneGeometry `json:"geojson"`
+	AreaKm2   float64           `json:"area_km2"`
+	MaxReach  float64           `json:"max_reach"` // meters from the origin to the farthest vertex
+	Samples   int               `json:"samples"`
+	Reachable int               `json:"reachable_samples"`
+	Method    string            `json:"method"`
+}
+
+// IsochroneTool returns a tool definition for computing reachable areas
+func IsochroneTool() mcp.Tool {
+	return mcp.NewTool("get_isochrone",
+		mcp.WithDescription("Compute the area reachable from a point within a travel time, e.g. what can be reached in 15 minutes on foot. Returns the polygon as points, an encoded polyline ring and GeoJSON, with its area. The boundary is estimated by timing routes to points sampled around the origin"),
+		mcp.WithNumber("latitude",
+			mcp.Required(),
+			mcp.Description("The latitude coordinate of the origin"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Required(),
+			mcp.Description("The longitude coordinate of the origin"),
+		),
+		mcp.WithNumber("minutes",
+			mcp.Description("Travel time in minutes (max 60)"),
+			mcp.DefaultNumber(15),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Transportation mode: car, bike, foot"),
+			mcp.DefaultString("car"),
+		),
+	)
+}
+
+// HandleIsochrone computes a reachable-area polygon
+func HandleIsochrone(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "get_isochrone")
+
+	lat := mcp.ParseFloat64(req, "latitude", 0)
+	lon := mcp.ParseFloat64(req, "longitude", 0)
+	if err := core.ValidateCoords(lat, lon); err != nil {
+		return core.NewError(core.ErrInvalidInput, err.Error()).ToMCPResult(), nil
+	}
+	minutes := mcp.ParseFloat64(req, "minutes", 15)
+	if minutes <= 0 || minutes > maxIsochroneMinutes {
+		return core.NewError(core.ErrInvalidParameter, "minutes must be greater than 0 and at most 60").ToMCPResult(), nil
+	}
+	profile := mapModeToProfile(mcp.ParseString(req, "mode", "car"))
+
+	options := core.OSRMOptions{
+		BaseURL: osm.OSRMBaseURL,
+		Profile: profile,
+		Client:  osm.GetClient(ctx),
+		RetryOptions: core.RetryOptions{
+			MaxAttempts:  3,
+			InitialDelay: 500 * time.Millisecond,
+			MaxDelay:     5 * time.Second,
+			Multiplier:   2.0,
+		},
+	}
+
+	origin := geo.Location{Latitude: lat, Longitude: lon}
+	isochrone, err := core.Isochrone(ctx, origin, minutes*60, options)
+	if err != nil {
+		logger.Error("failed to compute isochrone", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("OSRM", http.StatusServiceUnavailable,
+			"Failed to communicate with routing service").ToMCPResult(), nil
+	}
+
+	output := IsochroneOutput{
+		Origin:    origin,
+		Mode:      profile,
+		Minutes:   minutes,
+		Polygon:   isochrone.Polygon,
+		Polyline:  osm.EncodePolyline(isochrone.Polygon),
+		GeoJSON:   IsochroneGeometry{Type: "Polygon", Coordinates: [][][2]float64{make([][2]float64, len(isochrone.Polygon))}},
+		AreaKm2:   math.Round(isochrone.Area/1e4) / 100,
+		Samples:   isochrone.Samples,
+		Reachable: isochrone.Reachable,
+		Method:    "osrm_table_sampling",
+	}
+	for i, p := range isochrone.Polygon {
+		output.GeoJSON.Coordinates[0][i] = [2]float64{p.Longitude, p.Latitude}
+	}
+	for _, reach := range isochrone.Reach {
+		output.MaxReach = math.Max(output.MaxReach, math.Round(reach))
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,231 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// defaultBoundaryBand is how far inside the boundary a place may lie, in meters
+	defaultBoundaryBand = 300.0
+	// maxBoundaryBand caps the boundary band
+	maxBoundaryBand = 5000.0
+	// maxPolygonPoints caps the polygon size to keep Overpass queries reasonable
+	maxPolygonPoints = 500
+)
+
+// IsochroneBoundaryInput defines the input parameters for search_isochrone_boundary
+type IsochroneBoundaryInput struct {
+	Polygon     []geo.Location `json:"polygon"`
+	Category    string         `json:"category"`
+	Origin      *geo.Location  `json:"origin,omitempty"`
+	Band        float64        `json:"band,omitempty"`
+	Limit       int            `json:"limit,omitempty"`
+	IncludeTags bool           `json:"include_tags,omitempty"`
+}
+
+// BoundaryPlace is a place inside a reachable area together with its distance to the edge
+type BoundaryPlace struct {
+	Place              Place   `json:"place"`
+	DistanceToBoundary float64 `json:"distance_to_boundary"` // in meters, inside the polygon
+}
+
+// IsochroneBoundaryOutput defines the output for search_isochrone_boundary
+type IsochroneBoundaryOutput struct {
+	Places []BoundaryPlace `json:"places"`
+	Band   float64         `json:"band"`
+}
+
+// IsochroneBoundaryTool returns a tool definition for finding places near the edge of a reachable area
+func IsochroneBoundaryTool() mcp.Tool {
+	return mcp.NewTool("search_isochrone_boundary",
+		mcp.WithDescription("Find places of a category just inside the boundary of a reachable area (isochrone polygon), e.g. the farthest coffee shops reachable in 10 minutes. Places closest to the edge are returned first"),
+		mcp.WithArray("polygon",
+			mcp.Required(),
+			mcp.Description("The reachable-area polygon as an array of {latitude, longitude} points (3-500 points)"),
+		),
+		mcp.WithString("category",
+			mcp.Required(),
+			mcp.Description("Category of place to find (e.g., cafe, restaurant, pharmacy)"),
+		),
+		mcp.WithObject("origin",
+			mcp.Description("Optional isochrone origin as {latitude, longitude}; when set, each place's distance from it is reported"),
+		),
+		mcp.WithNumber("band",
+			mcp.Description("How far inside the boundary, in meters, a place may lie to be returned (max 5000)"),
+			mcp.DefaultNumber(defaultBoundaryBand),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of places to return (max 50)"),
+			mcp.DefaultNumber(10),
+		),
+		mcp.WithBoolean("include_tags",
+			mcp.Description(includeTagsDescription),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// HandleIsochroneBoundary finds places in the band just inside a reachable-area polygon
+func HandleIsochroneBoundary(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "search_isochrone_boundary")
+
+	// Parse input
+	var input IsochroneBoundaryInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").
+			WithGuidance("polygon must be an array of {latitude, longitude} objects").
+			ToMCPResult(), nil
+	}
+
+	if len(input.Polygon) < 3 || len(input.Polygon) > maxPolygonPoints {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Polygon must have between 3 and %d points", maxPolygonPoints)).ToMCPResult(), nil
+	}
+	for _, p := range input.Polygon {
+		if err := core.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid polygon coordinates: %s", err)).ToMCPResult(), nil
+		}
+	}
+
+	input.Category = strings.TrimSpace(input.Category)
+	if input.Category == "" {
+		return core.NewError(core.ErrEmptyParameter, "Category must not be empty").
+			WithGuidance("Provide a category such as cafe, restaurant or pharmacy").
+			ToMCPResult(), nil
+	}
+
+	if input.Origin != nil {
+		if err := core.ValidateCoords(input.Origin.Latitude, input.Origin.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid origin coordinates: %s", err)).ToMCPResult(), nil
+		}
+	}
+
+	if input.Band == 0 {
+		input.Band = defaultBoundaryBand
+	}
+	if err := core.ValidateRadius(input.Band, maxBoundaryBand); err != nil {
+		return core.NewError(core.ErrInvalidRadius, err.Error()).
+			WithGuidance("band must be positive and at most 5000 meters").
+			ToMCPResult(), nil
+	}
+
+	if input.Limit <= 0 {
+		input.Limit = 10
+	}
+	if input.Limit > 50 {
+		input.Limit = 50
+	}
+
+	query := buildPolygonCategoryQuery(input.Polygon, mapCategoryToOSMTags(input.Category))
+	elements, err := executeOverpassQuery(ctx, query)
+	if err != nil {
+		logger.Error("failed to query places in polygon", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.NewError(core.ErrServiceUnavailable, "Failed to search places in polygon").ToMCPResult(), nil
+	}
+
+	places := boundaryPlaces(elements, input.Polygon, input.Origin, input.Band, input.IncludeTags)
+	if len(places) > input.Limit {
+		places = places[:input.Limit]
+	}
+
+	logger.Info("found places near isochrone boundary", "category", input.Category, "count", len(places))
+
+	resultBytes, err := json.Marshal(IsochroneBoundaryOutput{Places: places, Band: input.Band})
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// buildPolygonCategoryQuery builds an Overpass query for any of the given tags inside a polygon
+func buildPolygonCategoryQuery(polygon []geo.Location, tags map[string][]string) string {
+	points := make([]string, 0, len(polygon))
+	for _, p := range polygon {
+		points = append(points, fmt.Sprintf("%.6f %.6f", p.Latitude, p.Longitude))
+	}
+	area := fmt.Sprintf(`(poly:"%s")`, strings.Join(points, " "))
+
+	// Sort keys so the query is deterministic and cacheable
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var query strings.Builder
+	query.WriteString("[out:json][timeout:25];(")
+	for _, key := range keys {
+		// A "*" value matches any value, with the key-only filter
+		for _, value := range tags[key] {
+			filter := fmt.Sprintf(`["%s"="%s"]`, key, value)
+			if value == "*" {
+				filter = fmt.Sprintf(`["%s"]`, key)
+			}
+			fmt.Fprintf(&query, `node%[1]s%[2]s;way%[1]s%[2]s;`, filter, area)
+			if value == "*" {
+				break
+			}
+		}
+	}
+	query.WriteString(");out center 500;")
+	return query.String()
+}
+
+// boundaryPlaces keeps named elements inside the polygon and within band meters
+// of its edge, ordered closest to the edge first, with their raw tags if
+// includeTags is set
+func boundaryPlaces(elements []osm.OverpassElement, polygon []geo.Location, origin *geo.Location, band float64, includeTags bool) []BoundaryPlace {
+	seen := make(map[string]bool)
+	places := make([]BoundaryPlace, 0)
+	for _, element := range elements {
+		place, ok := elementToPlace(element)
+		if !ok || seen[place.ID] {
+			continue
+		}
+		seen[place.ID] = true
+
+		lat, lon := place.Location.Latitude, place.Location.Longitude
+		if !geo.PointInPolygon(lat, lon, polygon) {
+			continue
+		}
+		toEdge := geo.DistanceToBoundary(lat, lon, polygon)
+		if toEdge > band {
+			continue
+		}
+		if origin != nil {
+			place.Distance = geo.HaversineDistance(origin.Latitude, origin.Longitude, lat, lon)
+		}
+		if includeTags {
+			place.Tags = element.Tags
+		}
+
+		places = append(places, BoundaryPlace{Place: place, DistanceToBoundary: toEdge})
+	}
+
+	sortByDistanceThenID(places, func(p BoundaryPlace) (float64, string) {
+		return p.DistanceToBoundary, p.Place.ID
+	})
+	return places
+}
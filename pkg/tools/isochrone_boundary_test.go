@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// testPolygon is a ~1.1km square
+var testPolygon = []geo.Location{
+	{Latitude: 0, Longitude: 0},
+	{Latitude: 0, Longitude: 0.01},
+	{Latitude: 0.01, Longitude: 0.01},
+	{Latitude: 0.01, Longitude: 0},
+}
+
+func TestBoundaryPlaces(t *testing.T) {
+	elements := []osm.OverpassElement{
+		{ID: 1, Type: "node", Lat: 0.005, Lon: 0.005, Tags: map[string]string{"name": "Center Cafe"}},
+		{ID: 2, Type: "node", Lat: 0.0005, Lon: 0.005, Tags: map[string]string{"name": "Edge Cafe"}},
+		{ID: 3, Type: "node", Lat: 0.002, Lon: 0.005, Tags: map[string]string{"name": "Near Edge Cafe"}},
+		{ID: 4, Type: "node", Lat: -0.001, Lon: 0.005, Tags: map[string]string{"name": "Outside Cafe"}},
+		{ID: 5, Type: "node", Lat: 0.0005, Lon: 0.006, Tags: map[string]string{}},
+	}
+
+	origin := &geo.Location{Latitude: 0.005, Longitude: 0.005}
+	places := boundaryPlaces(elements, testPolygon, origin, 300, false)
+
+	if len(places) != 2 {
+		t.Fatalf("expected 2 places in the band, got %d: %+v", len(places), places)
+	}
+	if places[0].Place.Name != "Edge Cafe" || places[1].Place.Name != "Near Edge Cafe" {
+		t.Errorf("unexpected order: %s, %s", places[0].Place.Name, places[1].Place.Name)
+	}
+	if places[0].Place.Distance <= places[1].Place.Distance {
+		t.Errorf("expected edge place to be farther from origin")
+	}
+	if places[0].Place.Tags != nil {
+		t.Errorf("expected no raw tags unless requested, got %v", places[0].Place.Tags)
+	}
+
+	elements[1].Tags["brand"] = "Edge Coffee"
+	places = boundaryPlaces(elements, testPolygon, origin, 300, true)
+	if places[0].Place.Tags["brand"] != "Edge Coffee" {
+		t.Errorf("expected the raw tags, got %v", places[0].Place.Tags)
+	}
+}
+
+func TestBuildPolygonCategoryQuery(t *testing.T) {
+	query := buildPolygonCategoryQuery(testPolygon, map[string][]string{"amenity": {"cafe"}})
+
+	if !strings.Contains(query, `(poly:"0.000000 0.000000 0.000000 0.010000 0.010000 0.010000 0.010000 0.000000")`) {
+		t.Errorf("query missing polygon filter: %s", query)
+	}
+	if !strings.Contains(query, `node["amenity"="cafe"]`) || !strings.Contains(query, `way["amenity"="cafe"]`) {
+		t.Errorf("query missing tag filters: %s", query)
+	}
+
+	// Categories matching any value of a key filter on the key alone
+	query = buildPolygonCategoryQuery(testPolygon, mapCategoryToOSMTags("shop"))
+	if !strings.Contains(query, `node["shop"](poly:`) || !strings.Contains(query, `way["shop"](poly:`) {
+		t.Errorf("query missing key-only filters: %s", query)
+	}
+	if strings.Contains(query, `"*"`) {
+		t.Errorf("query filters on a literal * value: %s", query)
+	}
+}
+
+func TestHandleIsochroneBoundaryValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+	}{
+		{
+			name: "Too few points",
+			arguments: map[string]any{
+				"polygon":  []any{map[string]any{"latitude": 0.0, "longitude": 0.0}},
+				"category": "cafe",
+			},
+		},
+		{
+			name: "Missing category",
+			arguments: map[string]any{
+				"polygon": []any{
+					map[string]any{"latitude": 0.0, "longitude": 0.0},
+					map[string]any{"latitude": 0.0, "longitude": 0.01},
+					map[string]any{"latitude": 0.01, "longitude": 0.01},
+				},
+			},
+		},
+		{
+			name: "Band too large",
+			arguments: map[string]any{
+				"polygon": []any{
+					map[string]any{"latitude": 0.0, "longitude": 0.0},
+					map[string]any{"latitude": 0.0, "longitude": 0.01},
+					map[string]any{"latitude": 0.01, "longitude": 0.01},
+				},
+				"category": "cafe",
+				"band":     10000.0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "search_isochrone_boundary",
+					Arguments: tt.arguments,
+				},
+			}
+
+			result, err := HandleIsochroneBoundary(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// maxListFilters limits the number of where expressions in one call
+const maxListFilters = 10
+
+// listOptionTools lists the tools whose handlers filter, sort and page
+// their results with processList, and so accept sort_by, order and where
+var listOptionTools = map[string]bool{
+	"find_nearby_places":      true,
+	"search_places_by_name":   true,
+	"find_parking_facilities": true,
+	"find_charging_stations":  true,
+	"find_schools_nearby":     true,
+	"osm_query_bbox":          true,
+}
+
+// withListOptions adds the sort_by, order and where parameters to the tools
+// listed in listOptionTools
+func withListOptions(defs []ToolDefinition) []ToolDefinition {
+	for i, def := range defs {
+		if !listOptionTools[def.Name] {
+			continue
+		}
+		mcp.WithString("sort_by",
+			mcp.Description("Result field to order by instead of distance, e.g. \"name\" or \"tags.capacity\". Results missing the field come last; ties are ordered by distance, then OSM ID"),
+		)(&defs[i].Tool)
+		mcp.WithString("order",
+			mcp.Description("Sort direction: asc (default) or desc"),
+			mcp.Enum("asc", "desc"),
+		)(&defs[i].Tool)
+		mcp.WithArray("where",
+			mcp.Description("Conditions every result must meet, each \"<field> <op> <value>\" with op one of =, !=, <, <=, >, >= or ~ (contains, case-insensitive), or a bare field that must be present, e.g. [\"distance < 500\", \"tags.cuisine ~ pizza\", \"website\"]. Applied before paging"),
+			mcp.WithStringItems(),
+		)(&defs[i].Tool)
+	}
+	return defs
+}
+
+// listFilter is one parsed where condition
+type listFilter struct {
+	path  []string
+	op    string // empty when the field only has to be present
+	value string
+}
+
+// listOptions are the filter, sort and page arguments of a list tool
+type listOptions struct {
+	sortBy      []string // field path; nil for distance, then OSM ID
+	descending  bool
+	where       []listFilter
+	after       *pageCursor
+	fingerprint string
+	limit       int
+}
+
+// listFilterPattern splits a where condition into field, operator and value
+var listFilterPattern = regexp.MustCompile(`^\s*([A-Za-z0-9_:.\-]+)\s*(?:(!=|<=|>=|=|<|>|~)\s*(.*?))?\s*$`)
+
+// parseListOptions reads the cursor, sort_by, order and where arguments of
+// a list tool returning up to limit results per page
+func parseListOptions(req mcp.CallToolRequest, limit int) (listOptions, *mcp.CallToolResult) {
+	opts := listOptions{fingerprint: requestFingerprint(req), limit: limit}
+
+	after, err := parseCursor(req, opts.fingerprint)
+	if err != nil {
+		return opts, core.NewError(core.ErrInvalidParameter, "Invalid cursor: "+err.Error()).
+			WithGuidance("Pass the next_cursor from a previous call with the same parameters, or omit cursor for the first page").
+			ToMCPResult()
+	}
+	opts.after = after
+
+	if sortBy := strings.TrimSpace(mcp.ParseString(req, "sort_by", "")); sortBy != "" {
+		opts.sortBy = strings.Split(sortBy, ".")
+	}
+	switch order := strings.ToLower(mcp.ParseString(req, "order", "asc")); order {
+	case "", "asc":
+	case "desc":
+		opts.descending = true
+	default:
+		return opts, core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid order %q", order)).
+			WithGuidance("Use asc or desc").
+			ToMCPResult()
+	}
+
+	opts.where, err = parseListFilters(req.GetArguments()["where"])
+	if err != nil {
+		return opts, core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance("Write conditions such as \"distance < 500\", \"tags.cuisine ~ pizza\" or \"website\"").
+			ToMCPResult()
+	}
+	return opts, nil
+}
+
+// parseListLimit reads the limit argument of a list tool, clamped to the
+// tool's configured range
+func parseListLimit(req mcp.CallToolRequest, limits SearchLimits, logger *slog.Logger) (int, *mcp.CallToolResult) {
+	limitStr := mcp.ParseString(req, "limit", "")
+	if limitStr == "" {
+		return limits.DefaultLimit, nil
+	}
+	limit, err := strconv.ParseFloat(limitStr, 64)
+	if err != nil {
+		logger.Error("invalid limit", "input", limitStr, "error", err)
+		return 0, NewGeocodeDetailedError(
+			"INVALID_LIMIT",
+			fmt.Sprintf("Invalid limit value: %s", limitStr),
+			"",
+			"Limit must be a valid positive number",
+			"Example: 10 (numeric, no quotes)",
+		)
+	}
+	return limits.clampLimit(int(limit)), nil
+}
+
+// parseListFilters reads the where argument, given as an array of
+// conditions or a single condition
+func parseListFilters(raw any) ([]listFilter, error) {
+	var conditions []any
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		conditions = []any{v}
+	case []any:
+		conditions = v
+	default:
+		return nil, fmt.Errorf("where must be an array of conditions")
+	}
+	if len(conditions) > maxListFilters {
+		return nil, fmt.Errorf("at most %d where conditions can be given", maxListFilters)
+	}
+
+	filters := make([]listFilter, 0, len(conditions))
+	for _, item := range conditions {
+		condition, _ := item.(string)
+		match := listFilterPattern.FindStringSubmatch(condition)
+		if match == nil || (match[2] != "" && match[3] == "") {
+			return nil, fmt.Errorf("invalid where condition %q", condition)
+		}
+		value := match[3]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		if op := match[2]; op == "<" || op == "<=" || op == ">" || op == ">=" {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("where condition %q compares with a non-number", condition)
+			}
+		}
+		filters = append(filters, listFilter{path: strings.Split(match[1], "."), op: match[2], value: value})
+	}
+	return filters, nil
+}
+
+// processList filters items, orders them and returns the page following
+// the cursor, the number of items matching the filters and the cursor for
+// the next page, which is empty on the last page. key gives the distance
+// and OSM ID of an item, the default order and tie-breaker.
+func processList[T any](items []T, key func(T) (float64, string), opts listOptions) ([]T, int, string) {
+	type entry struct {
+		item   T
+		cursor pageCursor
+	}
+
+	entries := make([]entry, 0, len(items))
+	for _, item := range items {
+		var record any
+		if len(opts.where) > 0 || opts.sortBy != nil {
+			data, _ := json.Marshal(item)
+			_ = json.Unmarshal(data, &record)
+		}
+		if !matchesListFilters(record, opts.where) {
+			continue
+		}
+		d, id := key(item)
+		cursor := pageCursor{Distance: d, ID: id}
+		if opts.sortBy != nil {
+			cursor.Value, _ = lookupListField(record, opts.sortBy)
+		}
+		entries = append(entries, entry{item: item, cursor: cursor})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return opts.compare(entries[i].cursor, entries[j].cursor) < 0
+	})
+
+	start := 0
+	if opts.after != nil {
+		start = sort.Search(len(entries), func(i int) bool {
+			return opts.compare(*opts.after, entries[i].cursor) < 0
+		})
+	}
+
+	rest := entries[start:]
+	page := make([]T, 0, min(len(rest), opts.limit))
+	for _, e := range rest[:min(len(rest), opts.limit)] {
+		page = append(page, e.item)
+	}
+	if len(rest) <= opts.limit {
+		return page, len(entries), ""
+	}
+
+	last := rest[opts.limit-1].cursor
+	last.Query = opts.fingerprint
+	return page, len(entries), encodePageCursor(last)
+}
+
+// filterList returns the items matching the where conditions, in order
+func filterList[T any](items []T, opts listOptions) []T {
+	if len(opts.where) == 0 {
+		return items
+	}
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		var record any
+		data, _ := json.Marshal(item)
+		_ = json.Unmarshal(data, &record)
+		if matchesListFilters(record, opts.where) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// compare orders two result positions by the sort field, then distance,
+// then OSM ID. Results missing the sort field come last in either
+// direction.
+func (opts listOptions) compare(a, b pageCursor) int {
+	c := 0
+	if opts.sortBy != nil {
+		switch {
+		case a.Value == nil && b.Value == nil:
+		case a.Value == nil:
+			return 1
+		case b.Value == nil:
+			return -1
+		default:
+			c = compareListValues(a.Value, b.Value)
+		}
+	}
+	if c == 0 {
+		switch {
+		case a.Distance < b.Distance:
+			c = -1
+		case a.Distance > b.Distance:
+			c = 1
+		default:
+			c = compareIDs(a.ID, b.ID)
+		}
+	}
+	if opts.descending {
+		return -c
+	}
+	return c
+}
+
+// lookupListField returns the value at a field path of a decoded JSON
+// record, and whether it is present. Empty strings count as missing.
+func lookupListField(record any, path []string) (any, bool) {
+	value := record
+	for _, segment := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+	switch value.(type) {
+	case map[string]any, []any:
+		return nil, false // only scalar fields can be compared
+	case string:
+		if value == "" {
+			return nil, false
+		}
+	}
+	return value, value != nil
+}
+
+// matchesListFilters reports whether a decoded JSON record meets every
+// condition
+func matchesListFilters(record any, filters []listFilter) bool {
+	for _, filter := range filters {
+		value, ok := lookupListField(record, filter.path)
+		if !filter.matches(value, ok) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether a field value, present or not, meets the
+// condition. Only != matches a missing field.
+func (f listFilter) matches(value any, present bool) bool {
+	if !present {
+		return f.op == "!="
+	}
+
+	text := fmt.Sprint(value)
+	switch f.op {
+	case "":
+		return true
+	case "~":
+		return strings.Contains(strings.ToLower(text), strings.ToLower(f.value))
+	case "=", "!=":
+		equal := strings.EqualFold(text, f.value)
+		if number, ok := value.(float64); ok {
+			if want, err := strconv.ParseFloat(f.value, 64); err == nil {
+				equal = number == want
+			}
+		}
+		return equal == (f.op == "=")
+	}
+
+	// Ordered comparisons need a number; tag values are strings
+	number, ok := value.(float64)
+	if !ok {
+		var err error
+		if number, err = strconv.ParseFloat(text, 64); err != nil {
+			return false
+		}
+	}
+	want, _ := strconv.ParseFloat(f.value, 64)
+	switch f.op {
+	case "<":
+		return number < want
+	case "<=":
+		return number <= want
+	case ">":
+		return number > want
+	default:
+		return number >= want
+	}
+}
+
+// compareListValues orders decoded JSON scalars: numbers and numeric
+// strings numerically, other strings case-insensitively and false before true. Values of different
+// kinds order numbers, then strings, then booleans.
+func compareListValues(a, b any) int {
+	rank := func(v any) int {
+		switch v.(type) {
+		case float64:
+			return 0
+		case string:
+			return 1
+		default:
+			return 2
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+
+	switch av := a.(type) {
+	case float64:
+		bv := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case string:
+		bv := b.(string)
+		// Numeric tag values such as capacity=12 order as numbers
+		an, errA := strconv.ParseFloat(av, 64)
+		bn, errB := strconv.ParseFloat(bv, 64)
+		if errA == nil && errB == nil {
+			return compareListValues(an, bn)
+		}
+		if c := strings.Compare(strings.ToLower(av), strings.ToLower(bv)); c != 0 {
+			return c
+		}
+		return strings.Compare(av, bv)
+	case bool:
+		bv, _ := b.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
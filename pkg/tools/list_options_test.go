@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"testing"
+)
+
+func listPlaces() []Place {
+	return []Place{
+		{ID: "1", Name: "Cafe Zeta", Distance: 300, Tags: map[string]string{"capacity": "12", "cuisine": "coffee_shop"}},
+		{ID: "2", Name: "bistro", Distance: 100, Tags: map[string]string{"capacity": "9"}},
+		{ID: "3", Name: "Alpha Pizza", Distance: 200, Tags: map[string]string{"cuisine": "pizza;italian"}},
+		{ID: "4", Distance: 50},
+	}
+}
+
+func placeIDs(places []Place) []string {
+	ids := make([]string, len(places))
+	for i, p := range places {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func TestParseListOptions(t *testing.T) {
+	opts, errResult := parseListOptions(pageRequest(map[string]any{
+		"sort_by": "tags.capacity",
+		"order":   "DESC",
+		"where":   []any{"distance < 250", `name ~ "pizza"`, "website"},
+	}), 5)
+	if errResult != nil {
+		t.Fatalf("unexpected error: %v", errResult.Content)
+	}
+	if len(opts.sortBy) != 2 || opts.sortBy[1] != "capacity" || !opts.descending || opts.limit != 5 {
+		t.Errorf("got options %+v", opts)
+	}
+	want := []listFilter{
+		{path: []string{"distance"}, op: "<", value: "250"},
+		{path: []string{"name"}, op: "~", value: "pizza"},
+		{path: []string{"website"}},
+	}
+	if len(opts.where) != len(want) {
+		t.Fatalf("got filters %+v", opts.where)
+	}
+	for i, filter := range want {
+		got := opts.where[i]
+		if len(got.path) != len(filter.path) || got.path[0] != filter.path[0] || got.op != filter.op || got.value != filter.value {
+			t.Errorf("filter %d: got %+v, want %+v", i, got, filter)
+		}
+	}
+
+	for _, args := range []map[string]any{
+		{"order": "sideways"},
+		{"where": []any{"distance <"}},
+		{"where": []any{"distance < near"}},
+		{"where": []any{"no spaces allowed"}},
+		{"where": 42.0},
+		{"cursor": "not a cursor!"},
+	} {
+		if _, errResult := parseListOptions(pageRequest(args), 5); errResult == nil || !errResult.IsError {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestProcessListFiltersAndSorts(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  map[string]any
+		want  []string
+		total int
+	}{
+		{"distance order", map[string]any{}, []string{"4", "2", "3", "1"}, 4},
+		{"descending", map[string]any{"order": "desc"}, []string{"1", "3", "2", "4"}, 4},
+		{"by name, case-insensitive, missing last", map[string]any{"sort_by": "name"}, []string{"3", "2", "1", "4"}, 4},
+		{"numeric tag values", map[string]any{"sort_by": "tags.capacity", "order": "desc"}, []string{"1", "2", "3", "4"}, 4},
+		{"range", map[string]any{"where": []any{"distance >= 100", "distance < 300"}}, []string{"2", "3"}, 2},
+		{"contains in a tag list", map[string]any{"where": "tags.cuisine ~ ITALIAN"}, []string{"3"}, 1},
+		{"tag value as number", map[string]any{"where": []any{"tags.capacity > 10"}}, []string{"1"}, 1},
+		{"present", map[string]any{"where": []any{"tags.cuisine"}}, []string{"3", "1"}, 2},
+		{"not equal matches missing", map[string]any{"where": []any{"name != bistro"}}, []string{"4", "3", "1"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, errResult := parseListOptions(pageRequest(tt.args), 10)
+			if errResult != nil {
+				t.Fatalf("unexpected error: %v", errResult.Content)
+			}
+			page, total, next := processList(listPlaces(), placeSortKey, opts)
+			got := placeIDs(page)
+			if len(got) != len(tt.want) || total != tt.total || next != "" {
+				t.Fatalf("got %v (total %d, next %q), want %v (total %d)", got, total, next, tt.want, tt.total)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessListPagesBySortField(t *testing.T) {
+	args := map[string]any{"sort_by": "name", "order": "desc", "where": []any{"distance > 60"}}
+
+	var seen []string
+	for pages := 0; pages < 5; pages++ {
+		opts, errResult := parseListOptions(pageRequest(args), 2)
+		if errResult != nil {
+			t.Fatalf("unexpected error: %v", errResult.Content)
+		}
+		page, total, next := processList(listPlaces(), placeSortKey, opts)
+		if total != 3 {
+			t.Errorf("got total %d, want 3", total)
+		}
+		seen = append(seen, placeIDs(page)...)
+		if next == "" {
+			break
+		}
+		args["cursor"] = next
+	}
+
+	want := []string{"1", "2", "3"}
+	if len(seen) != len(want) {
+		t.Fatalf("got %v across pages, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("got %v across pages, want %v", seen, want)
+		}
+	}
+
+	// A cursor only continues the query that produced it
+	args["order"] = "asc"
+	if _, errResult := parseListOptions(pageRequest(args), 2); errResult == nil {
+		t.Error("expected a cursor from a different sort order to be rejected")
+	}
+}
+
+func TestWithListOptions(t *testing.T) {
+	defs := withListOptions([]ToolDefinition{
+		{Name: "find_nearby_places", Tool: FindNearbyPlacesTool()},
+		{Name: "geocode_address", Tool: GeocodeAddressTool()},
+	})
+	for _, param := range []string{"sort_by", "order", "where"} {
+		if _, ok := defs[0].Tool.InputSchema.Properties[param]; !ok {
+			t.Errorf("find_nearby_places is missing %s", param)
+		}
+		if _, ok := defs[1].Tool.InputSchema.Properties[param]; ok {
+			t.Errorf("geocode_address should not have %s", param)
+		}
+	}
+}
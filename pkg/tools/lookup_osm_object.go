@@ -0,0 +1,367 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// osmTypeNames maps the one-letter OSM type codes used by Nominatim to the
+// element types other tools return
+var osmTypeNames = map[string]string{"N": "node", "W": "way", "R": "relation"}
+
+// osmObjectIDPattern matches element IDs as "node/123", "way 123", "N123"
+// or a bare "123"
+var osmObjectIDPattern = regexp.MustCompile(`(?i)^(?:(node|way|relation|[nwr])\s*[/:\s]?\s*)?(\d+)$`)
+
+// OSMObject is an OSM element as described by Nominatim
+type OSMObject struct {
+	OSMType     string            `json:"osm_type"` // node, way or relation
+	OSMID       int64             `json:"osm_id"`
+	Name        string            `json:"name,omitempty"`
+	Class       string            `json:"class"`
+	Type        string            `json:"type"`
+	Location    geo.Location      `json:"location"`
+	Address     Address           `json:"address"`
+	ExtraTags   map[string]string `json:"extratags,omitempty"`
+	Wikidata    string            `json:"wikidata,omitempty"`
+	WikidataURL string            `json:"wikidata_url,omitempty"`
+	Wikipedia   string            `json:"wikipedia,omitempty"`
+	Geometry    *AreaGeometry     `json:"geometry,omitempty"`  // simplified outline of areas
+	Hierarchy   []AddressLevel    `json:"hierarchy,omitempty"` // from the details API, when requested
+}
+
+// AddressLevel is one of the places an object lies in, most specific first
+type AddressLevel struct {
+	Name       string `json:"name"`
+	Class      string `json:"class"`
+	Type       string `json:"type"`
+	AdminLevel int    `json:"admin_level,omitempty"`
+}
+
+// LookupOSMObjectOutput defines the output for lookup_osm_object
+type LookupOSMObjectOutput struct {
+	Objects []OSMObject `json:"objects"`
+}
+
+// nominatimLookupResult is an entry of a Nominatim /lookup response
+type nominatimLookupResult struct {
+	OSMType   string            `json:"osm_type"`
+	OSMID     int64             `json:"osm_id"`
+	Name      string            `json:"name"`
+	Display   string            `json:"display_name"`
+	Category  string            `json:"category"`
+	Type      string            `json:"type"`
+	Lat       string            `json:"lat"`
+	Lon       string            `json:"lon"`
+	Address   map[string]string `json:"address"`
+	ExtraTags map[string]string `json:"extratags"`
+	GeoJSON   json.RawMessage   `json:"geojson,omitempty"`
+}
+
+// nominatimDetailsResult is the part of a Nominatim /details response
+// holding the address hierarchy
+type nominatimDetailsResult struct {
+	Address []struct {
+		LocalName  string `json:"localname"`
+		Class      string `json:"class"`
+		Type       string `json:"type"`
+		AdminLevel int    `json:"admin_level"`
+		IsAddress  bool   `json:"isaddress"`
+	} `json:"address"`
+}
+
+// LookupOSMObjectTool returns a tool definition for looking up an OSM
+// element by ID
+func LookupOSMObjectTool() mcp.Tool {
+	return mcp.NewTool("lookup_osm_object",
+		mcp.WithDescription("Look up an OpenStreetMap element by ID, as returned by osm_query_bbox or find_nearby_places, and get its full address, extra tags, Wikidata and Wikipedia links and, for areas, its outline"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The element ID, e.g. \"node/2003764150\", \"W123456\" or a bare number. A bare number is looked up as a node, a way and a relation unless osm_type is given"),
+		),
+		mcp.WithString("osm_type",
+			mcp.Description("The element type of a bare number ID: node, way or relation"),
+		),
+		mcp.WithBoolean("include_geometry",
+			mcp.Description("Return the simplified outline of area elements as GeoJSON"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_hierarchy",
+			mcp.Description("Also fetch the places the element lies in, from street to country, with the Nominatim details API (one more request per element)"),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// HandleLookupOSMObject looks up an OSM element with Nominatim
+func HandleLookupOSMObject(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "lookup_osm_object")
+
+	id := strings.TrimSpace(mcp.ParseString(req, "id", ""))
+	if id == "" {
+		return core.NewError(core.ErrMissingParameter, "id is required").ToMCPResult(), nil
+	}
+	osmIDs, errResult := parseOSMObjectID(id, mcp.ParseString(req, "osm_type", ""))
+	if errResult != nil {
+		return errResult, nil
+	}
+	includeGeometry := mcp.ParseBoolean(req, "include_geometry", false)
+	includeHierarchy := mcp.ParseBoolean(req, "include_hierarchy", false)
+
+	initCaches()
+	results, err := lookupOSMObjects(ctx, osmIDs, includeGeometry)
+	if err != nil {
+		logger.Error("failed to look up element", "id", id, "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("Nominatim", http.StatusServiceUnavailable,
+			"Failed to look up the element").ToMCPResult(), nil
+	}
+	if len(results) == 0 {
+		return core.NewError(core.ErrNoResults, fmt.Sprintf("No element %s found", id)).
+			WithGuidance("Nominatim only knows named or addressable elements; check the ID and its type").
+			ToMCPResult(), nil
+	}
+
+	output := LookupOSMObjectOutput{Objects: make([]OSMObject, 0, len(results))}
+	for _, result := range results {
+		object := lookupResultToObject(result)
+		if includeHierarchy {
+			hierarchy, err := fetchAddressHierarchy(ctx, result)
+			if err != nil {
+				logger.Warn("failed to fetch address hierarchy", "osm_type", result.OSMType, "osm_id", result.OSMID, "error", err)
+			}
+			object.Hierarchy = hierarchy
+		}
+		output.Objects = append(output.Objects, object)
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// parseOSMObjectID converts an element ID to the Nominatim osm_ids form,
+// e.g. "W123". A bare number without osmType becomes all three types.
+func parseOSMObjectID(id, osmType string) ([]string, *mcp.CallToolResult) {
+	match := osmObjectIDPattern.FindStringSubmatch(id)
+	if match == nil {
+		return nil, core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid element ID %q", id)).
+			WithGuidance("Give an ID such as node/2003764150, W123456 or a bare number").
+			ToMCPResult()
+	}
+
+	code := strings.ToUpper(match[1][:min(1, len(match[1]))])
+	if osmType = strings.ToLower(strings.TrimSpace(osmType)); osmType != "" {
+		typeCode := strings.ToUpper(osmType[:1])
+		if osmTypeNames[typeCode] != osmType {
+			return nil, core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Unknown osm_type %q", osmType)).
+				WithGuidance("Use node, way or relation").
+				ToMCPResult()
+		}
+		if code != "" && code != typeCode {
+			return nil, core.NewError(core.ErrInvalidParameter,
+				fmt.Sprintf("osm_type %s does not match the element ID %s", osmType, id)).ToMCPResult()
+		}
+		code = typeCode
+	}
+
+	if code == "" {
+		return []string{"N" + match[2], "W" + match[2], "R" + match[2]}, nil
+	}
+	return []string{code + match[2]}, nil
+}
+
+// lookupOSMObjects fetches elements from the Nominatim /lookup API,
+// through the geocoding cache
+func lookupOSMObjects(ctx context.Context, osmIDs []string, includeGeometry bool) ([]nominatimLookupResult, error) {
+	key := "lookup:" + strings.Join(osmIDs, ",")
+	if includeGeometry {
+		key += "|geometry"
+	}
+	if cached, found := geocodeCache.Get(key); found {
+		var results []nominatimLookupResult
+		if json.Unmarshal(cached.([]byte), &results) == nil {
+			return results, nil
+		}
+	}
+
+	params := url.Values{}
+	params.Set("osm_ids", strings.Join(osmIDs, ","))
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	params.Set("extratags", "1")
+	if includeGeometry {
+		params.Set("polygon_geojson", "1")
+		params.Set("polygon_threshold", polygonThreshold)
+	}
+
+	var results []nominatimLookupResult
+	if err := fetchNominatimJSON(ctx, "/lookup", params, &results); err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(results); err == nil {
+		geocodeCache.SetFor(cache.ClassGeocode, key, data)
+	}
+	return results, nil
+}
+
+// fetchAddressHierarchy fetches the places an element lies in from the
+// Nominatim /details API, through the geocoding cache
+func fetchAddressHierarchy(ctx context.Context, result nominatimLookupResult) ([]AddressLevel, error) {
+	code := strings.ToUpper(result.OSMType[:min(1, len(result.OSMType))])
+	key := fmt.Sprintf("details:%s%d", code, result.OSMID)
+
+	var details nominatimDetailsResult
+	cached, found := geocodeCache.Get(key)
+	if found {
+		found = json.Unmarshal(cached.([]byte), &details) == nil
+	}
+	if !found {
+		params := url.Values{}
+		params.Set("osmtype", code)
+		params.Set("osmid", strconv.FormatInt(result.OSMID, 10))
+		params.Set("addressdetails", "1")
+		params.Set("format", "json")
+		if err := fetchNominatimJSON(ctx, "/details", params, &details); err != nil {
+			return nil, err
+		}
+		if data, err := json.Marshal(details); err == nil {
+			geocodeCache.SetFor(cache.ClassGeocode, key, data)
+		}
+	}
+
+	var hierarchy []AddressLevel
+	for _, level := range details.Address {
+		// Skip the element itself and places that are not part of its address
+		if !level.IsAddress || level.LocalName == "" || level.LocalName == result.Name {
+			continue
+		}
+		adminLevel := level.AdminLevel
+		if adminLevel >= 15 {
+			adminLevel = 0 // Nominatim's placeholder for non-administrative places
+		}
+		hierarchy = append(hierarchy, AddressLevel{
+			Name:       level.LocalName,
+			Class:      level.Class,
+			Type:       level.Type,
+			AdminLevel: adminLevel,
+		})
+	}
+	return hierarchy, nil
+}
+
+// fetchNominatimJSON requests a Nominatim API path and decodes its JSON
+// response. Requests go through the shared client, which waits on the
+// Nominatim rate limiter.
+func fetchNominatimJSON(ctx context.Context, path string, params url.Values, out any) error {
+	reqURL, err := url.Parse(osm.NominatimBaseURL + path)
+	if err != nil {
+		return core.NewError(core.ErrInternalError, "Failed to parse URL for geocoding service")
+	}
+	reqURL.RawQuery = params.Encode()
+
+	requestFactory := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		return req, nil
+	}
+
+	resp, err := core.WithRetryFactory(ctx, requestFactory, osm.GetClient(ctx), core.DefaultRetryOptions)
+	if err != nil {
+		return core.ServiceError("Nominatim", http.StatusServiceUnavailable, "Failed to communicate with geocoding service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return core.ServiceError("Nominatim", resp.StatusCode, fmt.Sprintf("Geocoding service error: %d", resp.StatusCode))
+	}
+	if err := core.DecodeUpstream("Nominatim", resp.Body, out); err != nil {
+		return err
+	}
+	return nil
+}
+
+// lookupResultToObject converts a Nominatim /lookup result, pulling the
+// Wikidata and Wikipedia links out of its extra tags
+func lookupResultToObject(result nominatimLookupResult) OSMObject {
+	object := OSMObject{
+		OSMType:   result.OSMType,
+		OSMID:     result.OSMID,
+		Name:      result.Name,
+		Class:     result.Category,
+		Type:      result.Type,
+		Address:   addressFromFields(result.Address, result.Display),
+		ExtraTags: result.ExtraTags,
+	}
+	if name, ok := osmTypeNames[strings.ToUpper(result.OSMType)]; ok {
+		object.OSMType = name // some Nominatim versions return the type code
+	}
+	object.Location.Latitude, _ = strconv.ParseFloat(result.Lat, 64)
+	object.Location.Longitude, _ = strconv.ParseFloat(result.Lon, 64)
+
+	if wikidata := result.ExtraTags["wikidata"]; wikidata != "" {
+		object.Wikidata = wikidata
+		object.WikidataURL = "https://www.wikidata.org/wiki/" + url.PathEscape(wikidata)
+	}
+	object.Wikipedia = wikipediaURL(result.ExtraTags["wikipedia"])
+
+	if _, geometry, ok := areaPolygon(result.GeoJSON); ok {
+		object.Geometry = geometry
+	}
+	return object
+}
+
+// addressFromFields converts Nominatim address fields, taking the street
+// and city from the first of the fields that can hold them
+func addressFromFields(fields map[string]string, formatted string) Address {
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := fields[key]; value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	return Address{
+		Street:      first("road", "pedestrian", "footway", "square"),
+		HouseNumber: fields["house_number"],
+		City:        first("city", "town", "village", "hamlet", "municipality"),
+		State:       fields["state"],
+		Country:     fields["country"],
+		PostalCode:  fields["postcode"],
+		Formatted:   formatted,
+	}
+}
+
+// wikipediaURL converts a wikipedia tag such as "en:Monte Carlo Casino" to
+// the article URL, returning tags without a language unchanged
+func wikipediaURL(tag string) string {
+	lang, title, ok := strings.Cut(tag, ":")
+	if !ok || lang == "" || title == "" || len(lang) > 12 {
+		return tag
+	}
+	return fmt.Sprintf("https://%s.wikipedia.org/wiki/%s", lang, url.PathEscape(strings.ReplaceAll(title, " ", "_")))
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestParseOSMObjectID(t *testing.T) {
+	tests := []struct {
+		id, osmType string
+		want        []string
+	}{
+		{"node/2003764150", "", []string{"N2003764150"}},
+		{"W123", "", []string{"W123"}},
+		{"relation 42", "", []string{"R42"}},
+		{"way:7", "way", []string{"W7"}},
+		{"123", "", []string{"N123", "W123", "R123"}},
+		{"123", "Relation", []string{"R123"}},
+		{"node/abc", "", nil},
+		{"street/1", "", nil},
+		{"123", "area", nil},
+		{"node/1", "way", nil},
+	}
+
+	for _, tt := range tests {
+		got, errResult := parseOSMObjectID(tt.id, tt.osmType)
+		if tt.want == nil {
+			if errResult == nil {
+				t.Errorf("parseOSMObjectID(%q, %q) = %v, want an error", tt.id, tt.osmType, got)
+			}
+			continue
+		}
+		if errResult != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("parseOSMObjectID(%q, %q) = %v, want %v", tt.id, tt.osmType, got, tt.want)
+		}
+	}
+}
+
+func TestWikipediaURL(t *testing.T) {
+	if got := wikipediaURL("en:Monte Carlo Casino"); got != "https://en.wikipedia.org/wiki/Monte_Carlo_Casino" {
+		t.Errorf("unexpected URL %s", got)
+	}
+	if got := wikipediaURL("Monte Carlo Casino"); got != "Monte Carlo Casino" {
+		t.Errorf("expected a tag without a language unchanged, got %s", got)
+	}
+}
+
+func TestHandleLookupOSMObject(t *testing.T) {
+	initCaches()
+	geocodeCache.Clear()
+
+	var queries []url.Values
+	nominatim := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/lookup":
+			w.Write([]byte(`[{"osm_type": "way", "osm_id": 123, "name": "Jardin Exotique", "display_name": "Jardin Exotique, Boulevard du Jardin Exotique, Monaco", "category": "leisure", "type": "park", "lat": "43.73", "lon": "7.41",
+				"address": {"road": "Boulevard du Jardin Exotique", "city": "Monaco", "country": "Monaco"},
+				"extratags": {"wikidata": "Q1431464", "wikipedia": "fr:Jardin exotique de Monaco"},
+				"geojson": {"type": "Polygon", "coordinates": [[[7.410, 43.730], [7.412, 43.730], [7.412, 43.732], [7.410, 43.732], [7.410, 43.730]]]}}]`))
+		case "/details":
+			w.Write([]byte(`{"address": [
+				{"localname": "Jardin Exotique", "class": "leisure", "type": "park", "admin_level": 15, "isaddress": true},
+				{"localname": "Monaco", "class": "boundary", "type": "administrative", "admin_level": 2, "isaddress": true},
+				{"localname": "Les Révoires", "class": "place", "type": "quarter", "admin_level": 15, "isaddress": false}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer nominatim.Close()
+	if err := osm.SetServiceURLs(osm.ServiceURLs{Nominatim: nominatim.URL}); err != nil {
+		t.Fatal(err)
+	}
+	defer osm.SetServiceURLs(osm.ServiceURLs{Nominatim: osm.DefaultNominatimBaseURL})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"id": "123", "include_geometry": true, "include_hierarchy": true}
+	result, err := HandleLookupOSMObject(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %+v", err, result)
+	}
+
+	if len(queries) != 2 || queries[0].Get("osm_ids") != "N123,W123,R123" || queries[0].Get("polygon_geojson") != "1" || queries[1].Get("osmtype") != "W" {
+		t.Errorf("unexpected Nominatim queries %v", queries)
+	}
+
+	var output LookupOSMObjectOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Objects) != 1 {
+		t.Fatalf("expected one object, got %+v", output.Objects)
+	}
+	object := output.Objects[0]
+	if object.OSMType != "way" || object.Address.Street != "Boulevard du Jardin Exotique" || object.Address.City != "Monaco" {
+		t.Errorf("unexpected object %+v", object)
+	}
+	if object.WikidataURL != "https://www.wikidata.org/wiki/Q1431464" || object.Wikipedia != "https://fr.wikipedia.org/wiki/Jardin_exotique_de_Monaco" {
+		t.Errorf("unexpected links %q and %q", object.WikidataURL, object.Wikipedia)
+	}
+	if object.Geometry == nil || object.Geometry.Type != "Polygon" {
+		t.Errorf("expected the park outline, got %+v", object.Geometry)
+	}
+	if len(object.Hierarchy) != 1 || object.Hierarchy[0].Name != "Monaco" || object.Hierarchy[0].AdminLevel != 2 {
+		t.Errorf("unexpected hierarchy %+v", object.Hierarchy)
+	}
+
+	// A second lookup is served from the cache
+	if _, err := HandleLookupOSMObject(context.Background(), req); err != nil || len(queries) != 2 {
+		t.Errorf("expected cached results, got %d requests", len(queries))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
@@ -31,6 +32,10 @@ func GetMapImageTool() mcp.Tool {
 			mcp.Description("Zoom level (1-19, higher values show more detail)"),
 			mcp.DefaultNumber(14),
 		),
+		mcp.WithString("format",
+			mcp.Description("image (default) returns the tile as MCP image content followed by a text description; json returns a data URL and tile info"),
+			mcp.Enum("image", "json"),
+		),
 	)
 }
 
@@ -67,7 +72,8 @@ func HandleGetMapImage(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	}
 
 	// Encode tile to base64 with data URL prefix
-	base64Image := "data:image/png;base64," + base64.StdEncoding.EncodeToString(tileData)
+	mimeType := imageMIMEType(tileData)
+	base64Image := "data:" + mimeType + ";base64," + encodeToBase64(tileData)
 
 	// Detect which response format to use based on request name
 	toolName := req.Params.Name
@@ -146,11 +152,7 @@ func HandleGetMapImage(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	// Return both the text description, metadata, and the image as the result
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			mcp.ImageContent{
-				Type:     "image",
-				Data:     encodeToBase64(tileData),
-				MIMEType: "image/png",
-			},
+			mcp.NewImageContent(encodeToBase64(tileData), mimeType),
 			mcp.TextContent{
 				Type: "text",
 				Text: description + "\n\nMetadata: " + string(metadataJSON),
@@ -177,6 +179,15 @@ func fetchImageFromURL(ctx context.Context, url string) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
+// imageMIMEType detects the type of image data, so tiles from servers
+// serving JPEG or WebP are labelled correctly. It defaults to PNG.
+func imageMIMEType(data []byte) string {
+	if mimeType := http.DetectContentType(data); strings.HasPrefix(mimeType, "image/") {
+		return mimeType
+	}
+	return "image/png"
+}
+
 // encodeToBase64 encodes binary data to base64 string
 func encodeToBase64(data []byte) string {
 	return base64.StdEncoding.EncodeToString(data)
@@ -28,3 +28,20 @@ func TestHandleGetMapImage(t *testing.T) {
 		t.Fatalf("unexpected empty result")
 	}
 }
+
+func TestImageMIMEType(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{[]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{[]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), "image/jpeg"},
+		{[]byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "image/webp"},
+		{[]byte("<html>not an image</html>"), "image/png"},
+	}
+	for _, tt := range tests {
+		if got := imageMIMEType(tt.data); got != tt.want {
+			t.Errorf("imageMIMEType(%q) = %s, want %s", tt.data[:4], got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
@@ -33,6 +34,9 @@ type NeighborhoodAnalysis struct {
 	Summary         string   `json:"summary"`          // Textual summary of the analysis
 	KeyAmenities    []string `json:"key_amenities"`    // List of notable amenities nearby
 	KeyIssues       []string `json:"key_issues"`       // List of notable issues or drawbacks
+
+	// Environment holds noise and land-use proxy indicators, when requested
+	Environment *EnvironmentIndicators `json:"environment,omitempty"`
 }
 
 // AnalyzeNeighborhoodTool returns a tool definition for analyzing neighborhood livability
@@ -59,6 +63,13 @@ func AnalyzeNeighborhoodTool() mcp.Tool {
 			mcp.Description("Whether to include pricing and real estate data in the analysis"),
 			mcp.DefaultBool(true),
 		),
+		mcp.WithBoolean("include_environment",
+			mcp.Description("Whether to include noise and land-use proxy indicators (distance to motorways, railways and airports, industrial and green space shares)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithNumber("population",
+			mcp.Description("Optional resident population within the radius, used to report green space per capita"),
+		),
 	)
 }
 
@@ -72,17 +83,19 @@ func HandleAnalyzeNeighborhood(ctx context.Context, req mcp.CallToolRequest) (*m
 	neighborhoodName := mcp.ParseString(req, "neighborhood_name", "")
 	radius := mcp.ParseFloat64(req, "radius", 1000)
 	includePriceData := mcp.ParseBoolean(req, "include_price_data", true)
+	includeEnvironment := mcp.ParseBoolean(req, "include_environment", true)
+	population := int(mcp.ParseFloat64(req, "population", 0))
 
 	// Basic validation
-	if latitude < -90 || latitude > 90 {
-		return ErrorResponse("Latitude must be between -90 and 90"), nil
-	}
-	if longitude < -180 || longitude > 180 {
-		return ErrorResponse("Longitude must be between -180 and 180"), nil
+	if err := core.ValidateCoords(latitude, longitude); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
 	if radius <= 0 || radius > 2000 {
 		return ErrorResponse("Radius must be between 1 and 2000 meters"), nil
 	}
+	if population < 0 {
+		return ErrorResponse("Population must not be negative"), nil
+	}
 
 	// If neighborhood name not provided, attempt to get it via reverse geocoding
 	if neighborhoodName == "" {
@@ -185,9 +198,9 @@ func HandleAnalyzeNeighborhood(ctx context.Context, req mcp.CallToolRequest) (*m
 		} `json:"elements"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
+	if err := core.DecodeUpstream("Overpass", resp.Body, &overpassResp, "elements"); err != nil {
 		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse neighborhood data"), nil
+		return err.ToMCPResult(), nil
 	}
 
 	// Process and categorize elements
@@ -326,6 +339,23 @@ func HandleAnalyzeNeighborhood(ctx context.Context, req mcp.CallToolRequest) (*m
 		shoppingScore, diningScore, recreationScore, safetyScore, healthcareScore,
 	)
 
+	// Environment indicators come from a separate query and are best effort
+	var environment *EnvironmentIndicators
+	if includeEnvironment {
+		elements, err := executeOverpassQuery(ctx, buildEnvironmentQuery(latitude, longitude, radius))
+		if err != nil {
+			logger.Warn("failed to fetch environment data", "error", err)
+		} else {
+			environment = computeEnvironment(elements, latitude, longitude, radius, population)
+			if environment.NoiseExposure == NoiseExposureHigh {
+				keyIssues = append(keyIssues, "High noise exposure (close to a motorway, railway or airport)")
+			}
+			if environment.IndustrialShare >= 0.2 {
+				keyIssues = append(keyIssues, "Significant industrial land use nearby")
+			}
+		}
+	}
+
 	// Generate textual summary
 	summary := generateNeighborhoodSummary(
 		neighborhoodName, overallScore, keyAmenities, keyIssues,
@@ -350,6 +380,7 @@ func HandleAnalyzeNeighborhood(ctx context.Context, req mcp.CallToolRequest) (*m
 		Summary:         summary,
 		KeyAmenities:    keyAmenities,
 		KeyIssues:       keyIssues,
+		Environment:     environment,
 	}
 
 	// Convert to JSON and return
@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// Search distances for noise sources, in meters
+	majorRoadSearchRadius = 2000.0
+	railwaySearchRadius   = 2000.0
+	airportSearchRadius   = 15000.0
+
+	// environmentGridSize is the number of sample rows and columns used to
+	// estimate land-use shares within the analysis circle
+	environmentGridSize = 40
+)
+
+// Noise exposure levels
+const (
+	NoiseExposureLow      = "low"
+	NoiseExposureModerate = "moderate"
+	NoiseExposureHigh     = "high"
+)
+
+// environmentMethodology documents how each environment indicator is derived
+var environmentMethodology = map[string]string{
+	"noise_exposure":      "Proxy from distance to noise sources, not measured sound levels: high if a motorway/trunk road is within 200 m, a surface railway within 100 m or a commercial airport within 3 km; moderate within 500 m, 300 m or 8 km respectively; otherwise low",
+	"nearest_distances":   "Straight-line distance from the analysis point to the nearest mapped motorway/trunk road (searched within 2 km), surface railway (2 km) and airport with an ICAO or IATA code (15 km, measured to its center)",
+	"industrial_share":    "Fraction of the analysis circle covered by landuse=industrial areas, estimated on a regular sample grid",
+	"green_share":         "Fraction of the analysis circle covered by parks, gardens, woods, forest, grass, meadow and recreation grounds, estimated on a regular sample grid. Multipolygon relations are not included",
+	"green_per_capita_m2": "Green area within the analysis circle divided by the population supplied by the caller; omitted when no population is given",
+}
+
+// EnvironmentIndicators are proxy indicators of environmental quality
+type EnvironmentIndicators struct {
+	NoiseExposure      string            `json:"noise_exposure"`
+	NearestMotorway    *float64          `json:"nearest_motorway_m,omitempty"`
+	NearestRailway     *float64          `json:"nearest_railway_m,omitempty"`
+	NearestAirport     *float64          `json:"nearest_airport_m,omitempty"`
+	NearestAirportName string            `json:"nearest_airport_name,omitempty"`
+	IndustrialShare    float64           `json:"industrial_share"` // 0-1
+	GreenShare         float64           `json:"green_share"`      // 0-1
+	GreenAreaSqM       float64           `json:"green_area_m2"`
+	GreenPerCapitaSqM  *float64          `json:"green_per_capita_m2,omitempty"`
+	Methodology        map[string]string `json:"methodology"`
+}
+
+// buildEnvironmentQuery builds an Overpass query for noise sources and
+// land use around a point
+func buildEnvironmentQuery(lat, lon, radius float64) string {
+	var query strings.Builder
+	query.WriteString("[out:json][timeout:25];(")
+	fmt.Fprintf(&query, `way[highway~"^(motorway|trunk)$"](around:%.0f,%f,%f);`, majorRoadSearchRadius, lat, lon)
+	fmt.Fprintf(&query, `way[railway~"^(rail|light_rail)$"](around:%.0f,%f,%f);`, railwaySearchRadius, lat, lon)
+	fmt.Fprintf(&query, `way[landuse~"^(industrial|forest|grass|meadow|recreation_ground|village_green)$"](around:%.0f,%f,%f);`, radius, lat, lon)
+	fmt.Fprintf(&query, `way[leisure~"^(park|garden|nature_reserve|recreation_ground)$"](around:%.0f,%f,%f);`, radius, lat, lon)
+	fmt.Fprintf(&query, `way[natural=wood](around:%.0f,%f,%f);`, radius, lat, lon)
+	query.WriteString(");out geom;")
+	fmt.Fprintf(&query, `nwr[aeroway=aerodrome](around:%.0f,%f,%f);out center;`, airportSearchRadius, lat, lon)
+	return query.String()
+}
+
+// computeEnvironment derives environment indicators from the elements
+// returned by buildEnvironmentQuery. population may be 0 when unknown.
+func computeEnvironment(elements []osm.OverpassElement, lat, lon, radius float64, population int) *EnvironmentIndicators {
+	env := &EnvironmentIndicators{Methodology: environmentMethodology}
+
+	var industrial, green [][]geo.Location
+	for _, element := range elements {
+		tags := element.Tags
+		switch {
+		case tags["aeroway"] == "aerodrome":
+			if tags["icao"] == "" && tags["iata"] == "" {
+				continue
+			}
+			locations := elementLocations([]osm.OverpassElement{element})
+			if len(locations) == 0 {
+				continue
+			}
+			d := geo.HaversineDistance(lat, lon, locations[0].Latitude, locations[0].Longitude)
+			if env.NearestAirport == nil || d < *env.NearestAirport {
+				env.NearestAirport = roundedMeters(d)
+				env.NearestAirportName = tags["name"]
+			}
+
+		case tags["highway"] == "motorway" || tags["highway"] == "trunk":
+			env.NearestMotorway = nearerLine(env.NearestMotorway, lat, lon, element)
+
+		case tags["railway"] != "":
+			if tags["tunnel"] == "yes" {
+				continue
+			}
+			env.NearestRailway = nearerLine(env.NearestRailway, lat, lon, element)
+
+		case tags["landuse"] == "industrial":
+			if ring := elementRing(element); ring != nil {
+				industrial = append(industrial, ring)
+			}
+
+		default:
+			if ring := elementRing(element); ring != nil {
+				green = append(green, ring)
+			}
+		}
+	}
+
+	env.IndustrialShare, env.GreenShare = coverageShares(lat, lon, radius, industrial, green)
+	env.GreenAreaSqM = math.Round(env.GreenShare * math.Pi * radius * radius)
+	if population > 0 {
+		perCapita := math.Round(env.GreenAreaSqM/float64(population)*10) / 10
+		env.GreenPerCapitaSqM = &perCapita
+	}
+	env.NoiseExposure = noiseExposure(env)
+	return env
+}
+
+// noiseExposure classifies noise exposure from distances to noise sources
+func noiseExposure(env *EnvironmentIndicators) string {
+	within := func(d *float64, limit float64) bool {
+		return d != nil && *d <= limit
+	}
+	switch {
+	case within(env.NearestMotorway, 200) || within(env.NearestRailway, 100) || within(env.NearestAirport, 3000):
+		return NoiseExposureHigh
+	case within(env.NearestMotorway, 500) || within(env.NearestRailway, 300) || within(env.NearestAirport, 8000):
+		return NoiseExposureModerate
+	default:
+		return NoiseExposureLow
+	}
+}
+
+// nearerLine returns the smaller of current and the distance to a way's geometry
+func nearerLine(current *float64, lat, lon float64, element osm.OverpassElement) *float64 {
+	line := elementLine(element)
+	if len(line) == 0 {
+		return current
+	}
+	d := geo.DistanceToPolyline(lat, lon, line)
+	if current == nil || d < *current {
+		return roundedMeters(d)
+	}
+	return current
+}
+
+// elementLine converts a way's geometry to locations
+func elementLine(element osm.OverpassElement) []geo.Location {
+	line := make([]geo.Location, 0, len(element.Geometry))
+	for _, p := range element.Geometry {
+		line = append(line, geo.Location{Latitude: p.Lat, Longitude: p.Lon})
+	}
+	return line
+}
+
+// elementRing returns a way's geometry if it is a closed ring
+func elementRing(element osm.OverpassElement) []geo.Location {
+	ring := elementLine(element)
+	if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+		return nil
+	}
+	return ring
+}
+
+// coverageShares estimates the fraction of a circle covered by industrial
+// and green rings by testing a regular grid of sample points
+func coverageShares(lat, lon, radius float64, industrial, green [][]geo.Location) (float64, float64) {
+	sets := [][][]geo.Location{industrial, green}
+	bbox := geo.NewBoundingBox()
+	bbox.ExtendWithPoint(lat, lon)
+	bbox.Buffer(radius)
+
+	covered := make([]int, len(sets))
+	total := 0
+	for i := 0; i < environmentGridSize; i++ {
+		for j := 0; j < environmentGridSize; j++ {
+			pLat := bbox.MinLat + (float64(i)+0.5)*(bbox.MaxLat-bbox.MinLat)/environmentGridSize
+			pLon := bbox.MinLon + (float64(j)+0.5)*(bbox.MaxLon-bbox.MinLon)/environmentGridSize
+			if geo.HaversineDistance(lat, lon, pLat, pLon) > radius {
+				continue
+			}
+			total++
+			for s, rings := range sets {
+				for _, ring := range rings {
+					if geo.PointInPolygon(pLat, pLon, ring) {
+						covered[s]++
+						break
+					}
+				}
+			}
+		}
+	}
+
+	shares := make([]float64, len(sets))
+	for s := range sets {
+		if total > 0 {
+			shares[s] = math.Round(float64(covered[s])/float64(total)*1000) / 1000
+		}
+	}
+	return shares[0], shares[1]
+}
+
+// roundedMeters returns a distance rounded to the meter
+func roundedMeters(d float64) *float64 {
+	rounded := math.Round(d)
+	return &rounded
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// wayWithGeometry builds a way element from [lat, lon] pairs
+func wayWithGeometry(tags map[string]string, points ...[2]float64) osm.OverpassElement {
+	element := osm.OverpassElement{Type: "way", Tags: tags}
+	for _, p := range points {
+		element.Geometry = append(element.Geometry, struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		}{Lat: p[0], Lon: p[1]})
+	}
+	return element
+}
+
+func TestBuildEnvironmentQuery(t *testing.T) {
+	query := buildEnvironmentQuery(51.5, -0.1, 1000)
+	for _, want := range []string{"out geom", "motorway", "railway", "landuse", "aeroway=aerodrome", "out center"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q: %s", want, query)
+		}
+	}
+}
+
+func TestComputeEnvironment(t *testing.T) {
+	lat, lon := 0.0, 0.0
+	elements := []osm.OverpassElement{
+		// Motorway running north-south about 111 m east of the point
+		wayWithGeometry(map[string]string{"highway": "motorway"}, [2]float64{-0.01, 0.001}, [2]float64{0.01, 0.001}),
+		// Railway in a tunnel is ignored
+		wayWithGeometry(map[string]string{"railway": "rail", "tunnel": "yes"}, [2]float64{-0.01, 0}, [2]float64{0.01, 0}),
+		// Green area covering the western half of the circle
+		wayWithGeometry(map[string]string{"leisure": "park"},
+			[2]float64{-0.01, -0.01}, [2]float64{0.01, -0.01}, [2]float64{0.01, 0}, [2]float64{-0.01, 0}, [2]float64{-0.01, -0.01}),
+		// Unclosed industrial way is ignored
+		wayWithGeometry(map[string]string{"landuse": "industrial"}, [2]float64{0, 0}, [2]float64{0.01, 0.01}),
+		// Airports without an ICAO or IATA code are ignored
+		{Type: "node", Lat: 0.01, Lon: 0.01, Tags: map[string]string{"aeroway": "aerodrome", "name": "Airstrip"}},
+		{Type: "node", Lat: 0.1, Lon: 0, Tags: map[string]string{"aeroway": "aerodrome", "iata": "XYZ", "name": "Intl"}},
+	}
+
+	env := computeEnvironment(elements, lat, lon, 500, 1000)
+
+	if env.NearestMotorway == nil || *env.NearestMotorway < 100 || *env.NearestMotorway > 120 {
+		t.Errorf("unexpected motorway distance %v", env.NearestMotorway)
+	}
+	if env.NearestRailway != nil {
+		t.Errorf("tunnel railway should be ignored, got %v", *env.NearestRailway)
+	}
+	if env.NearestAirport == nil || env.NearestAirportName != "Intl" {
+		t.Errorf("unexpected airport %v %q", env.NearestAirport, env.NearestAirportName)
+	}
+	if env.NoiseExposure != NoiseExposureHigh {
+		t.Errorf("expected high noise exposure, got %s", env.NoiseExposure)
+	}
+	if env.GreenShare < 0.45 || env.GreenShare > 0.55 {
+		t.Errorf("expected green share near 0.5, got %f", env.GreenShare)
+	}
+	if env.IndustrialShare != 0 {
+		t.Errorf("expected no industrial share, got %f", env.IndustrialShare)
+	}
+	if env.GreenPerCapitaSqM == nil || *env.GreenPerCapitaSqM < 350 || *env.GreenPerCapitaSqM > 430 {
+		t.Errorf("unexpected green per capita %v", env.GreenPerCapitaSqM)
+	}
+	if env.Methodology["noise_exposure"] == "" {
+		t.Error("expected methodology for noise exposure")
+	}
+}
+
+func TestNoiseExposureLevels(t *testing.T) {
+	d := func(v float64) *float64 { return &v }
+	tests := []struct {
+		env  EnvironmentIndicators
+		want string
+	}{
+		{EnvironmentIndicators{}, NoiseExposureLow},
+		{EnvironmentIndicators{NearestRailway: d(250)}, NoiseExposureModerate},
+		{EnvironmentIndicators{NearestAirport: d(2500)}, NoiseExposureHigh},
+		{EnvironmentIndicators{NearestMotorway: d(900)}, NoiseExposureLow},
+	}
+	for _, tt := range tests {
+		if got := noiseExposure(&tt.env); got != tt.want {
+			t.Errorf("noiseExposure(%+v) = %s, want %s", tt.env, got, tt.want)
+		}
+	}
+}
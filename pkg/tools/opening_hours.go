@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm/openinghours"
+)
+
+const (
+	// openNowDescription documents the open_now parameter of place searches
+	openNowDescription = "Only return places whose opening_hours say they are open now (or at as_of). Places without parseable hours are left out"
+	// openAtDescription documents the open_at parameter of place searches
+	openAtDescription = "Only return places open at this RFC 3339 time, e.g. 2024-12-24T18:00:00Z, and annotate opening status for that time"
+)
+
+// openingStatus is the opening state of a place at a point in time
+type openingStatus struct {
+	known           bool // the opening_hours tag was present and parsed
+	open            bool
+	closesInMinutes *int // nil when closed, or open for the next week
+	opensInMinutes  *int // nil when open, or closed for the next week
+	nextOpen        *time.Time
+}
+
+// evaluateOpeningHours evaluates an opening_hours tag value at now, which
+// must already be in the feature's local time zone.
+func evaluateOpeningHours(value string, now time.Time) openingStatus {
+	if value == "" {
+		return openingStatus{}
+	}
+
+	schedule, err := openinghours.Parse(value)
+	if err != nil {
+		return openingStatus{}
+	}
+
+	status := openingStatus{known: true, open: schedule.IsOpen(now)}
+	if closes, ok := schedule.NextClose(now); ok {
+		minutes := int(closes.Sub(now).Minutes())
+		status.closesInMinutes = &minutes
+	}
+	if opens, ok := schedule.NextOpen(now); ok {
+		minutes := int(opens.Sub(now).Minutes())
+		status.opensInMinutes = &minutes
+		status.nextOpen = &opens
+	}
+	return status
+}
+
+// annotate records the opening hours tag and status on a place
+func (s openingStatus) annotate(place *Place, value string) {
+	place.OpeningHours = value
+	if !s.known {
+		return
+	}
+	open := s.open
+	place.Open = &open
+	place.ClosesInMinutes = s.closesInMinutes
+	place.OpensInMinutes = s.opensInMinutes
+	if s.nextOpen != nil {
+		place.NextOpen = s.nextOpen.Format(time.RFC3339)
+	}
+}
+
+// parseOpenFilter reads the open_now and open_at parameters and the time
+// zone, returning the local time to evaluate opening hours at and whether
+// to keep only places open then. open_at replaces as_of and the current time.
+func parseOpenFilter(req mcp.CallToolRequest) (time.Time, bool, error) {
+	tz, err := loadTimezone(mcp.ParseString(req, "timezone", ""))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	openAt := mcp.ParseString(req, "open_at", "")
+	if openAt == "" {
+		asOf, err := parseAsOf(req)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return asOf.In(tz), mcp.ParseBoolean(req, "open_now", false), nil
+	}
+
+	if mcp.ParseString(req, "as_of", "") != "" {
+		return time.Time{}, false, fmt.Errorf("open_at cannot be combined with as_of")
+	}
+	at, err := time.Parse(time.RFC3339, openAt)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("open_at must be an RFC 3339 time such as 2024-12-24T18:00:00Z")
+	}
+	return at.In(tz), true, nil
+}
+
+// remainsOpenFor reports whether the place is open now and stays open for at
+// least the given number of minutes. Places with unknown hours never match.
+func (s openingStatus) remainsOpenFor(minutes int) bool {
+	if !s.known || !s.open {
+		return false
+	}
+	return s.closesInMinutes == nil || *s.closesInMinutes >= minutes
+}
+
+// loadTimezone resolves an IANA time zone name, defaulting to the server's local zone
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
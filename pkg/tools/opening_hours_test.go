@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestEvaluateOpeningHours(t *testing.T) {
+	// 2024-06-03 is a Monday
+	now := time.Date(2024, time.June, 3, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		value       string
+		wantKnown   bool
+		wantOpen    bool
+		wantCloses  int // -1 for nil
+		wantOpens   int // -1 for nil
+		remainsOpen int
+		wantRemains bool
+	}{
+		{"Closes soon", "Mo-Fr 08:00-18:00", true, true, 60, -1, 45, true},
+		{"Closes too soon", "Mo-Fr 08:00-18:00", true, true, 60, -1, 90, false},
+		{"Opens later", "Mo-Fr 18:30-23:00", true, false, -1, 90, 10, false},
+		{"Always open", "24/7", true, true, -1, -1, 600, true},
+		{"Missing hours", "", false, false, -1, -1, 10, false},
+		{"Unparseable hours", "sunrise-sunset", false, false, -1, -1, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := evaluateOpeningHours(tt.value, now)
+			if status.known != tt.wantKnown || status.open != tt.wantOpen {
+				t.Errorf("known/open = %v/%v, want %v/%v", status.known, status.open, tt.wantKnown, tt.wantOpen)
+			}
+			checkMinutes(t, "closes_in_minutes", status.closesInMinutes, tt.wantCloses)
+			checkMinutes(t, "opens_in_minutes", status.opensInMinutes, tt.wantOpens)
+			if got := status.remainsOpenFor(tt.remainsOpen); got != tt.wantRemains {
+				t.Errorf("remainsOpenFor(%d) = %v, want %v", tt.remainsOpen, got, tt.wantRemains)
+			}
+		})
+	}
+}
+
+func checkMinutes(t *testing.T, field string, got *int, want int) {
+	t.Helper()
+	if want < 0 {
+		if got != nil {
+			t.Errorf("%s = %d, want nil", field, *got)
+		}
+		return
+	}
+	if got == nil || *got != want {
+		t.Errorf("%s = %v, want %d", field, got, want)
+	}
+}
+
+func TestLoadTimezone(t *testing.T) {
+	if loc, err := loadTimezone(""); err != nil || loc != time.Local {
+		t.Errorf("expected local time zone by default, got %v, %v", loc, err)
+	}
+	if _, err := loadTimezone("Not/AZone"); err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+}
+
+func TestParseOpenFilter(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"open_at": "2024-06-03T15:00:00Z", "timezone": "Europe/Paris"}
+	at, openOnly, err := parseOpenFilter(req)
+	if err != nil || !openOnly || at.Hour() != 17 || at.Location().String() != "Europe/Paris" {
+		t.Errorf("got %v, %v, %v; want 17:00 Paris time, filtered", at, openOnly, err)
+	}
+
+	req.Params.Arguments = map[string]any{"as_of": "2024-06-03T15:00:00Z", "timezone": "UTC"}
+	if at, openOnly, err := parseOpenFilter(req); err != nil || openOnly || at.Hour() != 15 {
+		t.Errorf("got %v, %v, %v; want 15:00 unfiltered", at, openOnly, err)
+	}
+
+	for _, arguments := range []map[string]any{
+		{"open_at": "tomorrow"},
+		{"open_at": "2024-06-03T15:00:00Z", "as_of": "2024-06-03T15:00:00Z"},
+		{"open_now": true, "timezone": "Not/AZone"},
+	} {
+		req.Params.Arguments = arguments
+		if _, _, err := parseOpenFilter(req); err == nil {
+			t.Errorf("expected an error for %v", arguments)
+		}
+	}
+}
+
+func TestOpeningStatusAnnotate(t *testing.T) {
+	// 2024-06-03 is a Monday
+	now := time.Date(2024, time.June, 3, 17, 0, 0, 0, time.UTC)
+
+	var place Place
+	evaluateOpeningHours("Mo-Fr 18:30-23:00", now).annotate(&place, "Mo-Fr 18:30-23:00")
+	if place.Open == nil || *place.Open || place.NextOpen != "2024-06-03T18:30:00Z" || place.OpeningHours != "Mo-Fr 18:30-23:00" {
+		t.Errorf("unexpected annotation %+v", place)
+	}
+
+	place = Place{}
+	evaluateOpeningHours("sunrise-sunset", now).annotate(&place, "sunrise-sunset")
+	if place.Open != nil || place.OpeningHours != "sunrise-sunset" {
+		t.Errorf("expected unknown status for unparseable hours, got %+v", place)
+	}
+}
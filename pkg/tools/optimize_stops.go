@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// OptimizeStop is a stop to visit, with an optional label
+type OptimizeStop struct {
+	Name      string  `json:"name,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// OptimizeStopsInput defines the input parameters for optimize_stops
+type OptimizeStopsInput struct {
+	Stops     []OptimizeStop `json:"stops"`
+	Mode      string         `json:"mode,omitempty"`
+	Roundtrip *bool          `json:"roundtrip,omitempty"`
+	Units     string         `json:"units,omitempty"`
+}
+
+// OptimizedStop is a stop in visiting order
+type OptimizedStop struct {
+	Order    int      `json:"order"`          // position in the optimized trip
+	Index    int      `json:"input_index"`    // position in the input list
+	Name     string   `json:"name,omitempty"` // label from the input
+	Location Location `json:"location"`       // input coordinates
+	Snapped  Location `json:"snapped"`        // nearest point on the road network
+	Distance float64  `json:"leg_distance"`   // meters from the previous stop
+	Duration float64  `json:"leg_duration"`   // seconds from the previous stop
+}
+
+// OptimizeStopsOutput defines the output for optimize_stops
+type OptimizeStopsOutput struct {
+	Stops     []OptimizedStop `json:"stops"`
+	Order     []int           `json:"order"`    // input indices in visiting order
+	Distance  float64         `json:"distance"` // total, in meters
+	Duration  float64         `json:"duration"` // total, in seconds
+	Polyline  string          `json:"polyline"`
+	Roundtrip bool            `json:"roundtrip"`
+	ReturnLeg *OptimizedStop  `json:"return_leg,omitempty"` // back to the first stop on round trips
+
+	// Units repeats the total distance, speed and duration in the requested unit system
+	Units *ConvertedMeasures `json:"units,omitempty"`
+}
+
+// OptimizeStopsTool returns a tool definition for ordering multiple stops
+func OptimizeStopsTool() mcp.Tool {
+	return mcp.NewTool("optimize_stops",
+		mcp.WithDescription("Find the fastest order to visit up to 25 stops (traveling salesman) using the OSRM trip service. The first stop is always the start; round trips return to it, otherwise the last stop is the end"),
+		mcp.WithArray("stops",
+			mcp.Required(),
+			mcp.Description("Array of {latitude, longitude, name} stops (2-25); the first is the starting point"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Travel mode (car, bike, foot)"),
+			mcp.DefaultString("car"),
+		),
+		mcp.WithBoolean("roundtrip",
+			mcp.Description("Return to the first stop at the end. When false the last stop is kept as the final destination"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithString("units",
+			mcp.Description(unitsDescription),
+		),
+	)
+}
+
+// HandleOptimizeStops orders stops for the shortest trip
+func HandleOptimizeStops(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "optimize_stops")
+
+	// Parse input
+	var input OptimizeStopsInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if len(input.Stops) < 2 || len(input.Stops) > core.MaxTripStops {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Between 2 and %d stops are required", core.MaxTripStops)).ToMCPResult(), nil
+	}
+	for i, stop := range input.Stops {
+		if err := core.ValidateCoords(stop.Latitude, stop.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid coordinates for stop %d: %s", i, err)).ToMCPResult(), nil
+		}
+	}
+
+	if input.Mode == "" {
+		input.Mode = "car"
+	}
+	profile := convertModeToProfile(input.Mode)
+	if profile == "" {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid mode: %s", input.Mode)).
+			WithGuidance("Use 'car', 'bike', or 'foot'").
+			ToMCPResult(), nil
+	}
+
+	if !validUnits(input.Units) {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid units: %s", input.Units)).
+			WithGuidance("Use 'metric', 'imperial', or 'nautical'").
+			ToMCPResult(), nil
+	}
+
+	roundtrip := input.Roundtrip == nil || *input.Roundtrip
+
+	coords := make([][]float64, len(input.Stops))
+	for i, stop := range input.Stops {
+		coords[i] = []float64{stop.Longitude, stop.Latitude}
+	}
+
+	options := routeFetchOptions(profile)
+	options.Overview = "full"
+	trip, err := core.GetTrip(ctx, coords, options, core.TripOptions{Roundtrip: roundtrip})
+	if err != nil {
+		logger.Error("failed to optimize stops", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("OSRM", http.StatusServiceUnavailable, "Failed to optimize stops").
+			WithGuidance("Try again later or check if the stops are reachable").
+			ToMCPResult(), nil
+	}
+
+	output, err := orderTripStops(input.Stops, trip, roundtrip)
+	if err != nil {
+		logger.Error("unexpected trip response", "error", err)
+		return core.NewError(core.ErrParseError, "Unexpected response from routing service").ToMCPResult(), nil
+	}
+	output.Units = convertMeasures(input.Units, output.Distance, output.Duration)
+
+	logger.Info("optimized stops", "stops", len(output.Stops), "distance", output.Distance, "duration", output.Duration)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// orderTripStops arranges the input stops in the order of an OSRM trip and
+// attaches the leg from the previous stop to each one
+func orderTripStops(stops []OptimizeStop, trip *core.OSRMTripResult, roundtrip bool) (*OptimizeStopsOutput, error) {
+	if len(trip.Trips) == 0 || len(trip.Waypoints) != len(stops) {
+		return nil, fmt.Errorf("trip has %d waypoints for %d stops", len(trip.Waypoints), len(stops))
+	}
+	route := trip.Trips[0]
+
+	ordered := make([]OptimizedStop, len(stops))
+	for i, wp := range trip.Waypoints {
+		if wp.TripsIndex != 0 {
+			return nil, fmt.Errorf("stop %d was placed in a separate trip", i)
+		}
+		ordered[i] = OptimizedStop{
+			Order:    wp.WaypointIndex,
+			Index:    i,
+			Name:     stops[i].Name,
+			Location: Location{Latitude: stops[i].Latitude, Longitude: stops[i].Longitude},
+		}
+		if len(wp.Location) == 2 {
+			ordered[i].Snapped = Location{Latitude: wp.Location[1], Longitude: wp.Location[0]}
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+
+	output := &OptimizeStopsOutput{
+		Stops:     ordered,
+		Order:     make([]int, len(ordered)),
+		Distance:  route.Distance,
+		Duration:  route.Duration,
+		Polyline:  route.Geometry,
+		Roundtrip: roundtrip,
+	}
+	for i := range ordered {
+		output.Order[i] = ordered[i].Index
+		if i > 0 && i-1 < len(route.Legs) {
+			ordered[i].Distance = route.Legs[i-1].Distance
+			ordered[i].Duration = route.Legs[i-1].Duration
+		}
+	}
+
+	// Round trips have one more leg, back to the start
+	if roundtrip && len(route.Legs) == len(ordered) {
+		last := route.Legs[len(route.Legs)-1]
+		back := ordered[0]
+		back.Order = len(ordered)
+		back.Distance, back.Duration = last.Distance, last.Duration
+		output.ReturnLeg = &back
+	}
+
+	return output, nil
+}
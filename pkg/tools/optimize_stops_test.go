@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+func TestOrderTripStops(t *testing.T) {
+	stops := []OptimizeStop{
+		{Name: "Depot", Latitude: 0, Longitude: 0},
+		{Name: "Far", Latitude: 0, Longitude: 0.02},
+		{Name: "Near", Latitude: 0, Longitude: 0.01},
+	}
+	trip := &core.OSRMTripResult{
+		Trips: []core.OSRMRoute{{
+			Distance: 4400,
+			Duration: 440,
+			Legs: []core.OSRMLeg{
+				{Distance: 1100, Duration: 110},
+				{Distance: 1100, Duration: 110},
+				{Distance: 2200, Duration: 220},
+			},
+		}},
+		Waypoints: []core.OSRMTripWaypoint{
+			{OSRMWaypoint: core.OSRMWaypoint{Location: []float64{0, 0}}, WaypointIndex: 0},
+			{OSRMWaypoint: core.OSRMWaypoint{Location: []float64{0.02, 0}}, WaypointIndex: 2},
+			{OSRMWaypoint: core.OSRMWaypoint{Location: []float64{0.01, 0}}, WaypointIndex: 1},
+		},
+	}
+
+	output, err := orderTripStops(stops, trip, true)
+	if err != nil {
+		t.Fatalf("orderTripStops() error: %v", err)
+	}
+
+	want := []int{0, 2, 1}
+	for i, idx := range want {
+		if output.Order[i] != idx {
+			t.Fatalf("order = %v, want %v", output.Order, want)
+		}
+	}
+	if output.Stops[1].Name != "Near" || output.Stops[1].Distance != 1100 || output.Stops[0].Distance != 0 {
+		t.Errorf("unexpected stops %+v", output.Stops)
+	}
+	if output.ReturnLeg == nil || output.ReturnLeg.Name != "Depot" || output.ReturnLeg.Distance != 2200 {
+		t.Errorf("unexpected return leg %+v", output.ReturnLeg)
+	}
+
+	// Open trips have no return leg
+	trip.Trips[0].Legs = trip.Trips[0].Legs[:2]
+	output, err = orderTripStops(stops, trip, false)
+	if err != nil {
+		t.Fatalf("orderTripStops() error: %v", err)
+	}
+	if output.ReturnLeg != nil {
+		t.Errorf("expected no return leg, got %+v", output.ReturnLeg)
+	}
+}
+
+func TestHandleOptimizeStopsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"Single stop", map[string]any{"stops": []any{map[string]any{"latitude": 1.0, "longitude": 1.0}}}},
+		{"Invalid coordinates", map[string]any{"stops": []any{
+			map[string]any{"latitude": 1.0, "longitude": 1.0},
+			map[string]any{"latitude": 100.0, "longitude": 1.0},
+		}}},
+		{"Invalid mode", map[string]any{"mode": "boat", "stops": []any{
+			map[string]any{"latitude": 1.0, "longitude": 1.0},
+			map[string]any{"latitude": 1.1, "longitude": 1.0},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "optimize_stops", Arguments: tt.args}}
+			result, err := HandleOptimizeStops(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
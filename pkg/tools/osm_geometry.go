@@ -0,0 +1,422 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// maxGeometryElements caps the elements fetched in one call, since
+	// relations such as boundaries can have huge geometries
+	maxGeometryElements = 20
+	// defaultGeometryMaxPoints caps the points of each ring or line unless
+	// the caller asks otherwise
+	defaultGeometryMaxPoints = 2000
+	// geometrySimplifyTolerance is the initial Douglas-Peucker tolerance in
+	// meters for rings and lines over the cap
+	geometrySimplifyTolerance = 1.0
+)
+
+// linearWayKeys mark closed ways that are still lines, such as roundabouts
+// and fences, unless tagged area=yes
+var linearWayKeys = []string{"highway", "barrier", "railway", "waterway", "power", "aerialway", "route"}
+
+// OSMGeometry is a GeoJSON geometry assembled from OSM element geometry
+type OSMGeometry struct {
+	Type        string `json:"type"`        // Point, LineString, MultiLineString, Polygon or MultiPolygon
+	Coordinates any    `json:"coordinates"` // [longitude, latitude] positions
+}
+
+// ElementGeometry is the assembled geometry of an OSM element. Lengths
+// and areas are measured before simplification.
+type ElementGeometry struct {
+	ID              string            `json:"id"` // e.g. way/24312356
+	Tags            map[string]string `json:"tags,omitempty"`
+	Geometry        OSMGeometry       `json:"geometry"`
+	LengthMeters    float64           `json:"length_meters,omitempty"`    // lines
+	PerimeterMeters float64           `json:"perimeter_meters,omitempty"` // polygons, including holes
+	AreaSqMeters    float64           `json:"area_sq_meters,omitempty"`   // polygons, excluding holes
+	PointCount      int               `json:"point_count"`
+	Simplified      bool              `json:"simplified,omitempty"`
+	// Incomplete is set when member ways of a relation did not join into
+	// closed rings; the open parts are left out of polygons
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// GetOSMGeometryOutput defines the output for get_osm_geometry
+type GetOSMGeometryOutput struct {
+	Elements        []ElementGeometry `json:"elements"`
+	Missing         []string          `json:"missing,omitempty"`          // requested elements Overpass did not return
+	WithoutGeometry []string          `json:"without_geometry,omitempty"` // e.g. relations of relations
+}
+
+// GetOSMGeometryTool returns a tool definition for fetching the full
+// geometry of ways and relations
+func GetOSMGeometryTool() mcp.Tool {
+	return mcp.NewTool("get_osm_geometry",
+		mcp.WithDescription(fmt.Sprintf("Fetch the full geometry of up to %d OSM elements by type and ID as GeoJSON: nodes as Points, ways as LineStrings or, for closed areas such as buildings and parks, Polygons, and multipolygon and boundary relations as Polygons or MultiPolygons assembled from their member ways, with holes. Other relations, e.g. routes, become LineStrings or MultiLineStrings. Each element comes with its length, or perimeter and area, measured on the full geometry", maxGeometryElements)),
+		mcp.WithArray("elements",
+			mcp.Required(),
+			mcp.Description("Elements to fetch, each as a string such as \"way/24312356\" or \"R1124039\", or an object {\"type\": \"relation\", \"id\": 1124039}"),
+		),
+		mcp.WithNumber("max_points",
+			mcp.Description("Simplify each ring or line to at most this many points (0 keeps every point)"),
+			mcp.DefaultNumber(defaultGeometryMaxPoints),
+			mcp.Min(0),
+		),
+	)
+}
+
+// HandleGetOSMGeometry fetches and assembles the geometry of elements
+func HandleGetOSMGeometry(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "get_osm_geometry")
+
+	ids, errResult := parseHydrateElements(req.GetArguments()["elements"], maxGeometryElements)
+	if errResult != nil {
+		return errResult, nil
+	}
+	maxPoints := int(req.GetFloat("max_points", defaultGeometryMaxPoints))
+	if maxPoints < 0 {
+		return core.NewError(core.ErrInvalidParameter, "max_points must not be negative").
+			WithGuidance("Use 0 to keep every point").
+			ToMCPResult(), nil
+	}
+
+	elements, err := executeOverpassQuery(ctx, buildGeometryQuery(ids))
+	if err != nil {
+		logger.Error("failed to fetch geometry", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("Overpass", http.StatusServiceUnavailable,
+			"Failed to fetch the geometry").ToMCPResult(), nil
+	}
+
+	byID := make(map[string]osm.OverpassElement, len(elements))
+	for _, element := range elements {
+		byID[element.Type+"/"+strconv.Itoa(element.ID)] = element
+	}
+
+	output := GetOSMGeometryOutput{Elements: make([]ElementGeometry, 0, len(ids))}
+	for _, id := range ids {
+		element, ok := byID[id]
+		if !ok {
+			output.Missing = append(output.Missing, id)
+			continue
+		}
+		geometry, ok := assembleGeometry(element, maxPoints)
+		if !ok {
+			output.WithoutGeometry = append(output.WithoutGeometry, id)
+			continue
+		}
+		output.Elements = append(output.Elements, geometry)
+	}
+
+	logger.Info("fetched element geometry", "requested", len(ids), "assembled", len(output.Elements))
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// buildGeometryQuery builds an Overpass query returning elements given as
+// "type/id" with their full geometry, including relation member geometry
+func buildGeometryQuery(ids []string) string {
+	byType := make(map[string][]string)
+	for _, id := range ids {
+		osmType, ref, _ := strings.Cut(id, "/")
+		byType[osmType] = append(byType[osmType], ref)
+	}
+
+	var query strings.Builder
+	query.WriteString("[out:json][timeout:60];\n(")
+	for _, osmType := range []string{"node", "way", "relation"} {
+		if refs := byType[osmType]; len(refs) > 0 {
+			sort.Strings(refs) // stable queries share the Overpass cache
+			fmt.Fprintf(&query, "%s(id:%s);", osmType, strings.Join(refs, ","))
+		}
+	}
+	query.WriteString(");\nout geom;\n")
+	return query.String()
+}
+
+// assembleGeometry converts an element fetched with "out geom" into a
+// GeoJSON geometry. It returns false for elements without usable geometry.
+func assembleGeometry(element osm.OverpassElement, maxPoints int) (ElementGeometry, bool) {
+	result := ElementGeometry{
+		ID:   element.Type + "/" + strconv.Itoa(element.ID),
+		Tags: element.Tags,
+	}
+
+	switch element.Type {
+	case "node":
+		result.Geometry = OSMGeometry{Type: "Point", Coordinates: []float64{element.Lon, element.Lat}}
+		result.PointCount = 1
+		return result, true
+
+	case "way":
+		path := make([]geo.Location, len(element.Geometry))
+		for i, point := range element.Geometry {
+			path[i] = geo.Location{Latitude: point.Lat, Longitude: point.Lon}
+		}
+		if len(path) < 2 {
+			return result, false
+		}
+		if isAreaWay(element.Tags, path) {
+			result.setPolygons([][][]geo.Location{{path}}, maxPoints)
+		} else {
+			result.setLines([][]geo.Location{path}, maxPoints)
+		}
+		return result, true
+
+	case "relation":
+		var outers, inners, others [][]geo.Location
+		for _, member := range element.Members {
+			if member.Type != "way" || len(member.Geometry) < 2 {
+				continue
+			}
+			path := make([]geo.Location, len(member.Geometry))
+			for i, point := range member.Geometry {
+				path[i] = geo.Location{Latitude: point.Lat, Longitude: point.Lon}
+			}
+			switch member.Role {
+			case "outer", "":
+				outers = append(outers, path)
+			case "inner":
+				inners = append(inners, path)
+			default:
+				others = append(others, path)
+			}
+		}
+
+		relationType := element.Tags["type"]
+		if relationType == "multipolygon" || relationType == "boundary" {
+			outerRings, openOuters := joinWays(outers)
+			innerRings, openInners := joinWays(inners)
+			if len(outerRings) > 0 {
+				polygons, orphans := groupRings(outerRings, innerRings)
+				result.setPolygons(polygons, maxPoints)
+				result.Incomplete = len(openOuters) > 0 || len(openInners) > 0 || orphans > 0
+				return result, true
+			}
+			// No ring could be closed, e.g. a boundary cut by missing
+			// members; return what there is as lines
+			result.Incomplete = true
+		}
+
+		closed, open := joinWays(append(append(outers, inners...), others...))
+		lines := append(closed, open...)
+		if len(lines) == 0 {
+			return result, false
+		}
+		result.setLines(lines, maxPoints)
+		return result, true
+	}
+	return result, false
+}
+
+// setLines stores lines as a LineString or MultiLineString
+func (g *ElementGeometry) setLines(lines [][]geo.Location, maxPoints int) {
+	coordinates := make([][][]float64, len(lines))
+	for i, line := range lines {
+		g.LengthMeters += pathLength(line)
+		coordinates[i] = g.positions(line, maxPoints)
+	}
+	g.LengthMeters = math.Round(g.LengthMeters*10) / 10
+
+	if len(coordinates) == 1 {
+		g.Geometry = OSMGeometry{Type: "LineString", Coordinates: coordinates[0]}
+		return
+	}
+	g.Geometry = OSMGeometry{Type: "MultiLineString", Coordinates: coordinates}
+}
+
+// setPolygons stores polygons, each an outer ring followed by its holes,
+// as a Polygon or MultiPolygon with the right-hand rule winding of RFC 7946
+func (g *ElementGeometry) setPolygons(polygons [][][]geo.Location, maxPoints int) {
+	coordinates := make([][][][]float64, len(polygons))
+	for i, rings := range polygons {
+		coordinates[i] = make([][][]float64, len(rings))
+		for j, ring := range rings {
+			g.PerimeterMeters += pathLength(ring)
+			if j == 0 {
+				g.AreaSqMeters += geo.PolygonArea(ring)
+			} else {
+				g.AreaSqMeters -= geo.PolygonArea(ring)
+			}
+			// Outer rings run counterclockwise, holes clockwise
+			if (signedRingArea(ring) < 0) == (j == 0) {
+				ring = reversed(ring)
+			}
+			coordinates[i][j] = g.positions(ring, maxPoints)
+		}
+	}
+	g.PerimeterMeters = math.Round(g.PerimeterMeters*10) / 10
+	g.AreaSqMeters = math.Round(math.Max(g.AreaSqMeters, 0)*10) / 10
+
+	if len(coordinates) == 1 {
+		g.Geometry = OSMGeometry{Type: "Polygon", Coordinates: coordinates[0]}
+		return
+	}
+	g.Geometry = OSMGeometry{Type: "MultiPolygon", Coordinates: coordinates}
+}
+
+// positions simplifies a path to at most maxPoints points and returns it as
+// [longitude, latitude] positions, counting the points
+func (g *ElementGeometry) positions(path []geo.Location, maxPoints int) [][]float64 {
+	if maxPoints > 0 && len(path) > maxPoints {
+		path = geo.SimplifyToLimit(path, geometrySimplifyTolerance, maxPoints)
+		g.Simplified = true
+	}
+	positions := make([][]float64, len(path))
+	for i, loc := range path {
+		positions[i] = []float64{loc.Longitude, loc.Latitude}
+	}
+	g.PointCount += len(positions)
+	return positions
+}
+
+// isAreaWay reports whether a way is an area rather than a line: closed
+// ways are areas unless tagged as linear features, or area=no
+func isAreaWay(tags map[string]string, path []geo.Location) bool {
+	if len(path) < 4 || path[0] != path[len(path)-1] {
+		return false
+	}
+	switch tags["area"] {
+	case "yes":
+		return true
+	case "no":
+		return false
+	}
+	switch tags["natural"] {
+	case "coastline", "cliff", "ridge", "arete", "tree_row":
+		return false
+	}
+	switch tags["waterway"] {
+	case "riverbank", "dock", "boatyard":
+		return true
+	}
+	for _, key := range linearWayKeys {
+		if tags[key] != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// joinWays joins way geometries that share end points into longer paths,
+// reversing ways as needed. Paths whose ends meet are returned as closed
+// rings, the rest as open lines.
+func joinWays(ways [][]geo.Location) (rings, open [][]geo.Location) {
+	remaining := make([][]geo.Location, 0, len(ways))
+	for _, way := range ways {
+		if len(way) >= 2 {
+			remaining = append(remaining, way)
+		}
+	}
+
+	for len(remaining) > 0 {
+		chain := append([]geo.Location(nil), remaining[0]...)
+		remaining = remaining[1:]
+
+		for chain[0] != chain[len(chain)-1] {
+			joined := false
+			for i, way := range remaining {
+				first, last := way[0], way[len(way)-1]
+				switch chain[len(chain)-1] {
+				case first:
+					chain = append(chain, way[1:]...)
+				case last:
+					chain = append(chain, reversed(way)[1:]...)
+				default:
+					switch chain[0] {
+					case last:
+						chain = append(append([]geo.Location(nil), way...), chain[1:]...)
+					case first:
+						chain = append(reversed(way), chain[1:]...)
+					default:
+						continue
+					}
+				}
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				joined = true
+				break
+			}
+			if !joined {
+				break
+			}
+		}
+
+		if len(chain) >= 4 && chain[0] == chain[len(chain)-1] {
+			rings = append(rings, chain)
+		} else {
+			open = append(open, chain)
+		}
+	}
+	return rings, open
+}
+
+// groupRings assigns each inner ring to the smallest outer ring containing
+// it, returning polygons as an outer ring followed by its holes and the
+// number of inner rings outside every outer ring
+func groupRings(outers, inners [][]geo.Location) ([][][]geo.Location, int) {
+	polygons := make([][][]geo.Location, len(outers))
+	areas := make([]float64, len(outers))
+	for i, outer := range outers {
+		polygons[i] = [][]geo.Location{outer}
+		areas[i] = geo.PolygonArea(outer)
+	}
+
+	orphans := 0
+	for _, inner := range inners {
+		best := -1
+		for i, outer := range outers {
+			if geo.PointInPolygon(inner[0].Latitude, inner[0].Longitude, outer) && (best < 0 || areas[i] < areas[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			orphans++
+			continue
+		}
+		polygons[best] = append(polygons[best], inner)
+	}
+	return polygons, orphans
+}
+
+// signedRingArea returns twice the planar area of a ring in square
+// degrees, positive when it runs counterclockwise
+func signedRingArea(ring []geo.Location) float64 {
+	area := 0.0
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area += a.Longitude*b.Latitude - b.Longitude*a.Latitude
+	}
+	return area
+}
+
+// reversed returns a reversed copy of a path
+func reversed(path []geo.Location) []geo.Location {
+	out := make([]geo.Location, len(path))
+	for i, loc := range path {
+		out[len(path)-1-i] = loc
+	}
+	return out
+}
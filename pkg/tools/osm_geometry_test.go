@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestBuildGeometryQuery(t *testing.T) {
+	got := buildGeometryQuery([]string{"relation/9", "way/3", "way/12", "node/1"})
+	want := "[out:json][timeout:60];\n(node(id:1);way(id:12,3);relation(id:9););\nout geom;\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIsAreaWay(t *testing.T) {
+	closed := []geo.Location{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 1}, {Latitude: 1, Longitude: 1}, {Latitude: 0, Longitude: 0}}
+	open := closed[:3]
+
+	tests := []struct {
+		tags map[string]string
+		path []geo.Location
+		want bool
+	}{
+		{map[string]string{"building": "yes"}, closed, true},
+		{map[string]string{"building": "yes"}, open, false},
+		{map[string]string{"highway": "primary", "junction": "roundabout"}, closed, false},
+		{map[string]string{"highway": "pedestrian", "area": "yes"}, closed, true},
+		{map[string]string{"barrier": "fence"}, closed, false},
+		{map[string]string{"natural": "coastline"}, closed, false},
+		{map[string]string{"waterway": "riverbank"}, closed, true},
+		{map[string]string{"leisure": "park", "area": "no"}, closed, false},
+	}
+	for _, tt := range tests {
+		if got := isAreaWay(tt.tags, tt.path); got != tt.want {
+			t.Errorf("isAreaWay(%v, %d points) = %v, want %v", tt.tags, len(tt.path), got, tt.want)
+		}
+	}
+}
+
+func TestJoinWays(t *testing.T) {
+	p := func(lat, lon float64) geo.Location { return geo.Location{Latitude: lat, Longitude: lon} }
+
+	// A square split into three ways, one of them reversed, plus a stray line
+	rings, open := joinWays([][]geo.Location{
+		{p(0, 0), p(0, 1)},
+		{p(1, 1), p(0, 1)},
+		{p(1, 1), p(1, 0), p(0, 0)},
+		{p(5, 5), p(6, 6)},
+	})
+	if len(rings) != 1 || len(open) != 1 {
+		t.Fatalf("got %d rings and %d open lines", len(rings), len(open))
+	}
+	if ring := rings[0]; len(ring) != 5 || ring[0] != ring[4] {
+		t.Errorf("ring = %v", ring)
+	}
+}
+
+func TestAssembleGeometryMultipolygon(t *testing.T) {
+	// 0.01 degree outer square with a hole, as returned by "out geom"
+	var element osm.OverpassElement
+	err := json.Unmarshal([]byte(`{
+		"type": "relation", "id": 42, "tags": {"type": "multipolygon", "leisure": "park"},
+		"members": [
+			{"type": "way", "ref": 1, "role": "outer", "geometry": [{"lat": 0, "lon": 0}, {"lat": 0, "lon": 0.01}, {"lat": 0.01, "lon": 0.01}]},
+			{"type": "way", "ref": 2, "role": "outer", "geometry": [{"lat": 0, "lon": 0}, {"lat": 0.01, "lon": 0}, {"lat": 0.01, "lon": 0.01}]},
+			{"type": "way", "ref": 3, "role": "inner", "geometry": [{"lat": 0.004, "lon": 0.004}, {"lat": 0.004, "lon": 0.006}, {"lat": 0.006, "lon": 0.006}, {"lat": 0.006, "lon": 0.004}, {"lat": 0.004, "lon": 0.004}]},
+			{"type": "node", "ref": 4, "role": "label", "lat": 0.005, "lon": 0.005}
+		]}`), &element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	geometry, ok := assembleGeometry(element, 0)
+	if !ok || geometry.Geometry.Type != "Polygon" || geometry.Incomplete {
+		t.Fatalf("got %+v, %v", geometry, ok)
+	}
+	rings := geometry.Geometry.Coordinates.([][][]float64)
+	if len(rings) != 2 || len(rings[0]) != 5 || len(rings[1]) != 5 {
+		t.Fatalf("rings = %v", rings)
+	}
+
+	// RFC 7946 winding: outer counterclockwise, holes clockwise
+	toLocations := func(ring [][]float64) []geo.Location {
+		locs := make([]geo.Location, len(ring))
+		for i, p := range ring {
+			locs[i] = geo.Location{Latitude: p[1], Longitude: p[0]}
+		}
+		return locs
+	}
+	if signedRingArea(toLocations(rings[0])) <= 0 || signedRingArea(toLocations(rings[1])) >= 0 {
+		t.Error("rings should follow the right-hand rule")
+	}
+
+	outer := 1111.95 * 1111.95 // ~1.11 km per 0.01 degree at the equator
+	want := outer * (1 - 0.04)
+	if math.Abs(geometry.AreaSqMeters-want)/want > 0.01 {
+		t.Errorf("area = %.0f m², want about %.0f", geometry.AreaSqMeters, want)
+	}
+	if geometry.PointCount != 10 {
+		t.Errorf("point count = %d, want 10", geometry.PointCount)
+	}
+}
+
+func TestAssembleGeometryIncompleteAndLines(t *testing.T) {
+	var boundary osm.OverpassElement
+	_ = json.Unmarshal([]byte(`{
+		"type": "relation", "id": 7, "tags": {"type": "boundary"},
+		"members": [{"type": "way", "ref": 1, "role": "outer", "geometry": [{"lat": 0, "lon": 0}, {"lat": 0, "lon": 1}]}]}`), &boundary)
+	geometry, ok := assembleGeometry(boundary, 0)
+	if !ok || geometry.Geometry.Type != "LineString" || !geometry.Incomplete || geometry.LengthMeters < 111000 {
+		t.Errorf("unclosed boundary: got %+v, %v", geometry, ok)
+	}
+
+	var route osm.OverpassElement
+	_ = json.Unmarshal([]byte(`{
+		"type": "relation", "id": 8, "tags": {"type": "route"},
+		"members": [
+			{"type": "way", "ref": 1, "role": "", "geometry": [{"lat": 0, "lon": 0}, {"lat": 0, "lon": 1}]},
+			{"type": "way", "ref": 2, "role": "", "geometry": [{"lat": 0, "lon": 2}, {"lat": 0, "lon": 1}]},
+			{"type": "way", "ref": 3, "role": "", "geometry": [{"lat": 3, "lon": 3}, {"lat": 4, "lon": 4}]}
+		]}`), &route)
+	geometry, ok = assembleGeometry(route, 0)
+	if !ok || geometry.Geometry.Type != "MultiLineString" || geometry.Incomplete {
+		t.Errorf("route: got %+v, %v", geometry, ok)
+	}
+	if lines := geometry.Geometry.Coordinates.([][][]float64); len(lines) != 2 || len(lines[0]) != 3 {
+		t.Errorf("route lines = %v", lines)
+	}
+
+	var superRelation osm.OverpassElement
+	_ = json.Unmarshal([]byte(`{"type": "relation", "id": 9, "members": [{"type": "relation", "ref": 8, "role": ""}]}`), &superRelation)
+	if _, ok := assembleGeometry(superRelation, 0); ok {
+		t.Error("a relation of relations has no geometry")
+	}
+}
+
+func TestAssembleGeometrySimplifies(t *testing.T) {
+	element := osm.OverpassElement{ID: 1, Type: "way", Tags: map[string]string{"highway": "track"}}
+	for i := range 100 {
+		element.Geometry = append(element.Geometry, struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		}{Lat: 0.001 * math.Sin(float64(i)), Lon: 0.001 * float64(i)})
+	}
+
+	full, _ := assembleGeometry(element, 0)
+	simplified, _ := assembleGeometry(element, 20)
+	if full.PointCount != 100 || full.Simplified {
+		t.Errorf("max_points 0 should keep every point, got %d", full.PointCount)
+	}
+	if simplified.PointCount > 20 || !simplified.Simplified {
+		t.Errorf("got %d points, want at most 20", simplified.PointCount)
+	}
+	if simplified.LengthMeters != full.LengthMeters {
+		t.Error("length should be measured on the full geometry")
+	}
+}
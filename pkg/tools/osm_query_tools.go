@@ -1,18 +1,22 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
-	"sort"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/geo"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 	"github.com/NERVsystems/osmmcp/pkg/osm/queries"
@@ -58,10 +62,16 @@ func validateTags(tags map[string]string) error {
 			return fmt.Errorf("tag value contains invalid characters")
 		}
 
-		// Additional validation for injection prevention
-		if strings.Contains(key, "..") || strings.Contains(value, "..") {
+		// Additional validation for injection prevention; regex values are
+		// quoted, so only they may contain ".."
+		if strings.Contains(key, "..") || (strings.Contains(value, "..") && !strings.HasPrefix(value, "~")) {
 			return fmt.Errorf("tag contains potentially unsafe sequences")
 		}
+
+		// Check negations and regular expressions
+		if _, err := queries.TagFilterClause(key, value); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -85,20 +95,48 @@ type OSMElement struct {
 
 // OSMQueryBBoxOutput defines the output for OSM query results
 type OSMQueryBBoxOutput struct {
-	Elements []OSMElement `json:"elements"`
+	Elements   []OSMElement `json:"elements"`
+	Total      int          `json:"total"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	// Truncated is set when Overpass returned as many elements as the
+	// element limit allows, so more may match
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+const (
+	// defaultBBoxPageSize and maxBBoxPageSize bound the elements returned
+	// per osm_query_bbox call
+	defaultBBoxPageSize = 500
+	maxBBoxPageSize     = 5000
+)
+
+// osmTypeRank orders element types for pagination
+var osmTypeRank = map[string]float64{"node": 0, "way": 1, "relation": 2}
+
+// osmElementSortKey orders elements by type, then ID. Elements carry no
+// distance here, so the type rank takes its place in the page cursor.
+func osmElementSortKey(e OSMElement) (float64, string) {
+	return osmTypeRank[e.Type], e.ID
 }
 
 // OSMQueryBBoxTool returns a tool definition for querying OSM data by bounding box
 func OSMQueryBBoxTool() mcp.Tool {
 	return mcp.NewTool("osm_query_bbox",
-		mcp.WithDescription("Query OpenStreetMap data within a bounding box with tag filters. Requirements: (1) Use exact field names: minLat, minLon, maxLat, maxLon (case-sensitive), (2) Latitude range: -90 to 90, (3) Longitude range: -180 to 180, (4) minLat < maxLat, (5) minLon < maxLon. Example usage: bbox: {\"minLat\": 37.77, \"minLon\": -122.42, \"maxLat\": 37.78, \"maxLon\": -122.41}, tags: {\"amenity\": \"restaurant\", \"cuisine\": \"*\"}"),
+		mcp.WithDescription("Query OpenStreetMap data within a bounding box with tag filters. Requirements: (1) Use exact field names: minLat, minLon, maxLat, maxLon (case-sensitive), (2) Latitude range: -90 to 90, (3) Longitude range: -180 to 180, (4) minLat < maxLat, (5) minLon < maxLon. Example usage: bbox: {\"minLat\": 37.77, \"minLon\": -122.42, \"maxLat\": 37.78, \"maxLon\": -122.41}, tags: {\"amenity\": \"restaurant\", \"cuisine\": \"*\"}. Queries matching too many elements are refused with TOO_MANY_RESULTS before any data is downloaded"),
 		mcp.WithObject("bbox",
 			mcp.Required(),
 			mcp.Description("Bounding box object with required fields: minLat (number), minLon (number), maxLat (number), maxLon (number). Example: {\"minLat\": 37.77, \"minLon\": -122.42, \"maxLat\": 37.78, \"maxLon\": -122.41}"),
 		),
 		mcp.WithObject("tags",
 			mcp.Required(),
-			mcp.Description("Tags to filter by as key-value string pairs. Use '*' as value to match any value for a key. Example: {\"amenity\": \"restaurant\", \"cuisine\": \"*\", \"name\": \"Pizza\"}. Common keys: amenity, shop, leisure, highway, building, name, cuisine, brand"),
+			mcp.Description("Tags to filter by as key-value string pairs. Use '*' as value to match any value for a key, '!key' as key to require a key to be absent, and '~pattern' as value for a regular expression, with ',i' after it for case-insensitive matching. Example: {\"amenity\": \"restaurant\", \"cuisine\": \"*\", \"name\": \"~pizz(a|eria),i\", \"!takeaway\": \"\"}. Common keys: amenity, shop, leisure, highway, building, name, cuisine, brand"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of elements to return per page (max %d). Elements are ordered by type (nodes, ways, relations), then ID", maxBBoxPageSize)),
+			mcp.DefaultNumber(defaultBBoxPageSize),
+		),
+		mcp.WithString("cursor",
+			mcp.Description(cursorDescription),
 		),
 	)
 }
@@ -121,10 +159,8 @@ func HandleOSMQueryBBox(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 	}
 
 	// Validate bounding box
-	if input.BBox.MinLat < -90 || input.BBox.MinLat > 90 ||
-		input.BBox.MaxLat < -90 || input.BBox.MaxLat > 90 ||
-		input.BBox.MinLon < -180 || input.BBox.MinLon > 180 ||
-		input.BBox.MaxLon < -180 || input.BBox.MaxLon > 180 ||
+	if geo.ValidateCoords(input.BBox.MinLat, input.BBox.MinLon) != nil ||
+		geo.ValidateCoords(input.BBox.MaxLat, input.BBox.MaxLon) != nil ||
 		input.BBox.MinLat >= input.BBox.MaxLat ||
 		input.BBox.MinLon >= input.BBox.MaxLon {
 		logger.Error("invalid bounding box",
@@ -141,6 +177,15 @@ func HandleOSMQueryBBox(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 		return ErrorResponse(fmt.Sprintf("Invalid tags: %v", err)), nil
 	}
 
+	limit := int(mcp.ParseFloat64(req, "limit", defaultBBoxPageSize))
+	if limit < 1 || limit > maxBBoxPageSize {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("limit must be between 1 and %d", maxBBoxPageSize)).ToMCPResult(), nil
+	}
+	opts, errResult := parseListOptions(req, limit)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Build Overpass query using the query builder
 	queryBuilder := queries.NewOverpassBuilder()
 	queryBuilder.Begin()
@@ -169,12 +214,49 @@ func HandleOSMQueryBBox(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 		input.BBox.MaxLat, input.BBox.MaxLon,
 		input.Tags,
 	)
-	queryBuilder.End().WithOutput("center")
+	// Bound the download to the element limit even if the count pre-check
+	// below fails
+	elementLimit := osm.OverpassElementLimit()
+	if elementLimit > 0 {
+		queryBuilder.End().WithOutput(fmt.Sprintf("center %d", elementLimit))
+	} else {
+		queryBuilder.End().WithOutput("center")
+	}
+	if err := queryBuilder.Err(); err != nil {
+		logger.Error("invalid tags", "error", err)
+		return ErrorResponse(fmt.Sprintf("Invalid tags: %v", err)), nil
+	}
 	overpassQuery := queryBuilder.Build()
 
 	// Log the generated query for debugging
 	logger.Info("generated Overpass query", "query", overpassQuery)
 
+	// Later pages are served from the results of the first
+	sum := sha256.Sum256([]byte(overpassQuery))
+	cacheKey := "osm_query_bbox:" + hex.EncodeToString(sum[:])
+	if cached, found := cache.GetGlobalCache().Get(cacheKey); found {
+		if elements, ok := cached.([]OSMElement); ok {
+			return bboxPage(elements, elementLimit, opts, logger), nil
+		}
+	}
+
+	// Refuse queries that would return more elements than the configured
+	// limit, rather than downloading a huge response
+	if limit := osm.OverpassElementLimit(); limit > 0 {
+		count, err := osm.CountOverpassElements(ctx, overpassQuery)
+		if err != nil {
+			// The pre-check is an optimisation, so run the query anyway
+			logger.Warn("element count pre-check failed", "error", err)
+		} else if count.Total > limit {
+			logger.Info("query refused by element count pre-check", "count", count.Total, "limit", limit)
+			return core.NewError(core.ErrTooManyResults,
+				fmt.Sprintf("Query matches %d elements (%d nodes, %d ways, %d relations), more than the limit of %d",
+					count.Total, count.Nodes, count.Ways, count.Relations, limit)).
+				WithGuidance("Use a smaller bounding box or more specific tags, for example add a value to a wildcard tag").
+				ToMCPResult(), nil
+		}
+	}
+
 	// Wait for rate limiting
 	if err := osm.WaitForService(ctx, osm.ServiceOverpass); err != nil {
 		logger.Error("rate limit exceeded", "error", err)
@@ -248,25 +330,33 @@ func HandleOSMQueryBBox(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 		} `json:"elements"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
-		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse Overpass API response"), nil
+	// Stream very large responses to disk rather than buffering them
+	body, spooled, err := readUpstreamBody(resp.Body, "osm_query_bbox response", "application/json", logger)
+	if err != nil {
+		logger.Error("failed to read response", "error", err)
+		return ErrorResponse("Failed to read Overpass response"), nil
+	}
+	if spooled != nil {
+		logger.Info("spooled large Overpass response", "uri", spooled.URI, "size", spooled.Size)
+		return spooledResult(spooled, "The resource holds the raw Overpass JSON response", logger)
 	}
 
-	// Convert to output format
-	output := OSMQueryBBoxOutput{
-		Elements: make([]OSMElement, len(overpassResp.Elements)),
+	if err := core.DecodeUpstream("Overpass", bytes.NewReader(body), &overpassResp, "elements"); err != nil {
+		logger.Error("failed to decode response", "error", err)
+		return err.ToMCPResult(), nil
 	}
 
+	// Convert to output format
+	elements := make([]OSMElement, len(overpassResp.Elements))
 	for i, element := range overpassResp.Elements {
 		// Convert ID to string
-		output.Elements[i].ID = fmt.Sprintf("%d", element.ID)
-		output.Elements[i].Type = element.Type
-		output.Elements[i].Tags = element.Tags
+		elements[i].ID = fmt.Sprintf("%d", element.ID)
+		elements[i].Type = element.Type
+		elements[i].Tags = element.Tags
 
 		// Set location for nodes
 		if element.Type == "node" {
-			output.Elements[i].Location = &geo.Location{
+			elements[i].Location = &geo.Location{
 				Latitude:  element.Lat,
 				Longitude: element.Lon,
 			}
@@ -274,21 +364,35 @@ func HandleOSMQueryBBox(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 
 		// Set center for ways and relations
 		if element.Center != nil {
-			output.Elements[i].Center = &geo.Location{
+			elements[i].Center = &geo.Location{
 				Latitude:  element.Center.Lat,
 				Longitude: element.Center.Lon,
 			}
 		}
 	}
+	sortByDistanceThenID(elements, osmElementSortKey)
+	cache.GetGlobalCache().SetFor(cache.ClassPOI, cacheKey, elements)
+
+	return bboxPage(elements, elementLimit, opts, logger), nil
+}
+
+// bboxPage returns the page of matching elements following the cursor.
+// elementLimit is the element limit the query was run with.
+func bboxPage(elements []OSMElement, elementLimit int, opts listOptions, logger *slog.Logger) *mcp.CallToolResult {
+	page, total, nextCursor := processList(elements, osmElementSortKey, opts)
+	output := OSMQueryBBoxOutput{
+		Elements:   page,
+		Total:      total,
+		NextCursor: nextCursor,
+		Truncated:  elementLimit > 0 && len(elements) >= elementLimit,
+	}
 
-	// Return result
 	resultBytes, err := json.Marshal(output)
 	if err != nil {
 		logger.Error("failed to marshal result", "error", err)
-		return ErrorResponse("Failed to generate result"), nil
+		return ErrorResponse("Failed to generate result")
 	}
-
-	return mcp.NewToolResultText(string(resultBytes)), nil
+	return mcp.NewToolResultText(string(resultBytes))
 }
 
 // FilterTagsInput defines the input parameters for filtering OSM elements by tag
@@ -427,7 +531,7 @@ type SortByDistanceOutput struct {
 // SortByDistanceTool returns a tool definition for sorting OSM elements by distance
 func SortByDistanceTool() mcp.Tool {
 	return mcp.NewTool("sort_by_distance",
-		mcp.WithDescription("Sort OSM elements by distance from a reference point"),
+		mcp.WithDescription("Sort OSM elements by distance from a reference point, breaking ties by element ID"),
 		mcp.WithArray("elements",
 			mcp.Required(),
 			mcp.Description("Array of OSM elements to sort"),
@@ -468,7 +572,7 @@ func HandleSortByDistance(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 		return ErrorResponse("Missing 'ref' coordinates"), nil
 	}
 
-	if err := osm.ValidateCoords(input.Ref.Latitude, input.Ref.Longitude); err != nil {
+	if err := geo.ValidateCoords(input.Ref.Latitude, input.Ref.Longitude); err != nil {
 		logger.Error("invalid 'ref' coordinates", "error", err)
 		return ErrorResponse(fmt.Sprintf("Invalid 'ref' coordinates: %s", err)), nil
 	}
@@ -500,9 +604,9 @@ func HandleSortByDistance(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 		elements[i].Distance = distance
 	}
 
-	// Sort elements by distance
-	sort.Slice(elements, func(i, j int) bool {
-		return elements[i].Distance < elements[j].Distance
+	// Sort elements by distance, breaking ties by ID
+	sortByDistanceThenID(elements, func(e OSMElement) (float64, string) {
+		return e.Distance, e.ID
 	})
 
 	// Create output
@@ -2,6 +2,9 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -490,3 +493,53 @@ func TestHandleOSMQueryBBox_ErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestBBoxPagination(t *testing.T) {
+	elements := []OSMElement{
+		{ID: "7", Type: "way"},
+		{ID: "30", Type: "node"},
+		{ID: "4", Type: "node"},
+		{ID: "2", Type: "relation"},
+		{ID: "5", Type: "way"},
+	}
+	sortByDistanceThenID(elements, osmElementSortKey)
+
+	var got []string
+	var after *pageCursor
+	for page := 0; page < 5; page++ {
+		result := bboxPage(elements, 0, listOptions{after: after, fingerprint: "fp", limit: 2}, slog.Default())
+		var output OSMQueryBBoxOutput
+		if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+			t.Fatal(err)
+		}
+		if output.Total != len(elements) || output.Truncated {
+			t.Errorf("page %d: total %d, truncated %v", page, output.Total, output.Truncated)
+		}
+		for _, e := range output.Elements {
+			got = append(got, e.Type+"/"+e.ID)
+		}
+		if output.NextCursor == "" {
+			break
+		}
+
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"cursor": output.NextCursor}
+		var err error
+		if after, err = parseCursor(req, "fp"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := "node/4 node/30 way/5 way/7 relation/2"
+	if strings.Join(got, " ") != want {
+		t.Errorf("pages returned %s, want %s", strings.Join(got, " "), want)
+	}
+}
+
+func TestBBoxPageTruncated(t *testing.T) {
+	elements := []OSMElement{{ID: "1", Type: "node"}, {ID: "2", Type: "node"}}
+	result := bboxPage(elements, 2, listOptions{fingerprint: "fp", limit: 10}, slog.Default())
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, `"truncated":true`) {
+		t.Errorf("expected a result at the element limit to be truncated: %s", text)
+	}
+}
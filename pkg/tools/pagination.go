@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// orderingDescription documents the result order shared by list tools
+const orderingDescription = "Results are ordered by distance, then by OSM ID, so repeated identical queries return the same order"
+
+// cursorDescription documents the cursor parameter shared by paginated tools
+const cursorDescription = "Opaque next_cursor token from a previous call with the same parameters, to fetch the next page"
+
+// pageArguments are request arguments that do not change which results
+// match, so they are left out of the cursor fingerprint
+var pageArguments = map[string]bool{
+	"cursor":               true,
+	"limit":                true,
+	"fields":               true,
+	"coordinate_precision": true,
+	"output_format":        true,
+	"transform":            true,
+}
+
+// pageCursor marks the last result returned on a page. Results sorting
+// after it make up the next page.
+type pageCursor struct {
+	Query    string  `json:"q"`           // fingerprint of the request the cursor belongs to
+	Value    any     `json:"v,omitempty"` // sort_by field value, when sorting by a field
+	Distance float64 `json:"d"`
+	ID       string  `json:"id"`
+}
+
+// compareIDs orders OSM IDs numerically when both are numbers and as
+// strings otherwise
+func compareIDs(a, b string) int {
+	na, errA := strconv.ParseInt(a, 10, 64)
+	nb, errB := strconv.ParseInt(b, 10, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		default:
+			return 0
+		}
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// lessByDistanceThenID reports whether a result at distance da with ID ida
+// sorts before one at distance db with ID idb
+func lessByDistanceThenID(da float64, ida string, db float64, idb string) bool {
+	if da != db {
+		return da < db
+	}
+	return compareIDs(ida, idb) < 0
+}
+
+// sortByDistanceThenID sorts items by distance, breaking ties by OSM ID
+func sortByDistanceThenID[T any](items []T, key func(T) (float64, string)) {
+	sort.SliceStable(items, func(i, j int) bool {
+		di, idi := key(items[i])
+		dj, idj := key(items[j])
+		return lessByDistanceThenID(di, idi, dj, idj)
+	})
+}
+
+// requestFingerprint hashes the arguments that select results, so a cursor
+// can only be used with the query that produced it
+func requestFingerprint(req mcp.CallToolRequest) string {
+	selected := make(map[string]any)
+	if args, ok := req.Params.Arguments.(map[string]any); ok {
+		for key, value := range args {
+			if !pageArguments[key] {
+				selected[key] = value
+			}
+		}
+	}
+	// Map keys are marshaled in sorted order, so equal arguments hash equally
+	data, _ := json.Marshal(selected)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodeCursor builds an opaque cursor token for the result after which
+// the next page starts
+func encodeCursor(fingerprint string, distance float64, id string) string {
+	return encodePageCursor(pageCursor{Query: fingerprint, Distance: distance, ID: id})
+}
+
+// encodePageCursor builds the opaque token for a cursor
+func encodePageCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseCursor reads the optional cursor argument and checks that it was
+// issued for the same query. It returns nil when no cursor was given.
+func parseCursor(req mcp.CallToolRequest, fingerprint string) (*pageCursor, error) {
+	token := mcp.ParseString(req, "cursor", "")
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if cursor.Query != fingerprint {
+		return nil, fmt.Errorf("cursor belongs to a different query")
+	}
+	return &cursor, nil
+}
+
+// paginate returns the page of up to limit items that follow the cursor in
+// distance, then ID order, and the cursor for the next page, which is
+// empty on the last page
+func paginate[T any](items []T, key func(T) (float64, string), after *pageCursor, fingerprint string, limit int) ([]T, string) {
+	page, _, next := processList(items, key, listOptions{after: after, fingerprint: fingerprint, limit: limit})
+	return page, next
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func pageRequest(args map[string]any) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	return req
+}
+
+func TestSortByDistanceThenID(t *testing.T) {
+	places := []Place{
+		{ID: "30", Distance: 100},
+		{ID: "9", Distance: 50},
+		{ID: "100", Distance: 100},
+		{ID: "2", Distance: 100},
+	}
+	sortPlacesByDistance(places)
+
+	want := []string{"9", "2", "30", "100"}
+	for i, id := range want {
+		if places[i].ID != id {
+			t.Fatalf("position %d: got ID %s, want %s", i, places[i].ID, id)
+		}
+	}
+}
+
+func TestCompareIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"9", "10", -1},
+		{"10", "9", 1},
+		{"42", "42", 0},
+		{"node/9", "node/10", 1}, // non-numeric IDs compare as strings
+	}
+	for _, tt := range tests {
+		if got := compareIDs(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareIDs(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPaginateWalksAllResults(t *testing.T) {
+	places := []Place{
+		{ID: "1", Distance: 10}, {ID: "2", Distance: 10}, {ID: "3", Distance: 20},
+		{ID: "4", Distance: 30}, {ID: "5", Distance: 30}, {ID: "6", Distance: 40},
+		{ID: "7", Distance: 50},
+	}
+	sortPlacesByDistance(places)
+
+	args := map[string]any{"latitude": 1.0, "longitude": 2.0, "category": "cafe", "limit": 3}
+	fingerprint := requestFingerprint(pageRequest(args))
+
+	var seen []string
+	var after *pageCursor
+	for pages := 0; pages < 10; pages++ {
+		page, next := paginate(places, placeSortKey, after, fingerprint, 3)
+		for _, p := range page {
+			seen = append(seen, p.ID)
+		}
+		if next == "" {
+			break
+		}
+
+		args["cursor"] = next
+		var err error
+		after, err = parseCursor(pageRequest(args), fingerprint)
+		if err != nil {
+			t.Fatalf("parseCursor: %v", err)
+		}
+	}
+
+	if len(seen) != len(places) {
+		t.Fatalf("got %d results across pages, want %d: %v", len(seen), len(places), seen)
+	}
+	for i, p := range places {
+		if seen[i] != p.ID {
+			t.Errorf("result %d: got ID %s, want %s", i, seen[i], p.ID)
+		}
+	}
+}
+
+func TestRequestFingerprintIgnoresPageArguments(t *testing.T) {
+	base := requestFingerprint(pageRequest(map[string]any{"latitude": 1.0, "category": "cafe"}))
+	paged := requestFingerprint(pageRequest(map[string]any{
+		"latitude": 1.0, "category": "cafe", "limit": 5, "cursor": "abc", "fields": []any{"name"},
+		"coordinate_precision": 3, "output_format": "csv", "transform": "length(results)",
+	}))
+	if base != paged {
+		t.Error("page and output arguments should not change the fingerprint")
+	}
+
+	other := requestFingerprint(pageRequest(map[string]any{"latitude": 1.0, "category": "park"}))
+	if base == other {
+		t.Error("different queries should have different fingerprints")
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	fingerprint := requestFingerprint(pageRequest(map[string]any{"category": "cafe"}))
+
+	cursor, err := parseCursor(pageRequest(map[string]any{"category": "cafe"}), fingerprint)
+	if err != nil || cursor != nil {
+		t.Fatalf("no cursor: got %v, %v", cursor, err)
+	}
+
+	token := encodeCursor(fingerprint, 123.5, "42")
+	cursor, err = parseCursor(pageRequest(map[string]any{"category": "cafe", "cursor": token}), fingerprint)
+	if err != nil {
+		t.Fatalf("valid cursor: %v", err)
+	}
+	if cursor.Distance != 123.5 || cursor.ID != "42" {
+		t.Errorf("got cursor %+v", cursor)
+	}
+
+	otherQuery := requestFingerprint(pageRequest(map[string]any{"category": "park"}))
+	if _, err := parseCursor(pageRequest(map[string]any{"cursor": token}), otherQuery); err == nil {
+		t.Error("expected an error for a cursor from a different query")
+	}
+	if _, err := parseCursor(pageRequest(map[string]any{"cursor": "not a cursor!"}), fingerprint); err == nil {
+		t.Error("expected an error for a malformed cursor")
+	}
+}
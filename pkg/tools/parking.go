@@ -6,9 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"net/http"
-	"net/url"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -20,24 +17,28 @@ import (
 
 // ParkingArea represents a parking facility
 type ParkingArea struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	Location     Location `json:"location"`
-	Distance     float64  `json:"distance,omitempty"`     // in meters
-	Type         string   `json:"type,omitempty"`         // e.g., surface, underground, multi-storey
-	Access       string   `json:"access,omitempty"`       // e.g., public, private, customers
-	Capacity     int      `json:"capacity,omitempty"`     // number of parking spaces if available
-	Fee          bool     `json:"fee,omitempty"`          // whether there's a parking fee
-	MaxStay      string   `json:"max_stay,omitempty"`     // maximum parking duration if available
-	Availability string   `json:"availability,omitempty"` // if real-time availability is known
-	Wheelchair   bool     `json:"wheelchair,omitempty"`   // wheelchair accessibility
-	Operator     string   `json:"operator,omitempty"`     // who operates the facility
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Location         Location `json:"location"`
+	Distance         float64  `json:"distance,omitempty"`          // in meters
+	Type             string   `json:"type,omitempty"`              // e.g., surface, underground, multi-storey
+	Access           string   `json:"access,omitempty"`            // e.g., public, private, customers
+	Capacity         int      `json:"capacity,omitempty"`          // number of parking spaces if available
+	DisabledCapacity int      `json:"capacity_disabled,omitempty"` // number of spaces reserved for disabled drivers
+	ChargingCapacity int      `json:"capacity_charging,omitempty"` // number of spaces with EV charging equipment
+	Fee              bool     `json:"fee,omitempty"`               // whether there's a parking fee
+	MaxStay          string   `json:"max_stay,omitempty"`          // maximum parking duration if available
+	Availability     string   `json:"availability,omitempty"`      // if real-time availability is known
+	Wheelchair       bool     `json:"wheelchair,omitempty"`        // wheelchair accessibility
+	Operator         string   `json:"operator,omitempty"`          // who operates the facility
+	Contact          *Contact `json:"contact,omitempty"`           // normalized contact details
 }
 
 // FindParkingAreasTool returns a tool definition for finding parking facilities
 func FindParkingAreasTool() mcp.Tool {
+	limits := searchLimitsFor("find_parking_facilities")
 	return mcp.NewTool("find_parking_facilities",
-		mcp.WithDescription("Find parking facilities near a specific location"),
+		mcp.WithDescription("Find parking facilities near a specific location. "+orderingDescription),
 		mcp.WithNumber("latitude",
 			mcp.Required(),
 			mcp.Description("The latitude coordinate of the center point"),
@@ -47,8 +48,8 @@ func FindParkingAreasTool() mcp.Tool {
 			mcp.Description("The longitude coordinate of the center point"),
 		),
 		mcp.WithNumber("radius",
-			mcp.Description("Search radius in meters (max 5000)"),
-			mcp.DefaultNumber(1000),
+			mcp.Description(limits.radiusDescription()),
+			mcp.DefaultNumber(limits.DefaultRadius),
 		),
 		mcp.WithString("type",
 			mcp.Description("Optional type filter (e.g., surface, underground, multi-storey)"),
@@ -58,9 +59,16 @@ func FindParkingAreasTool() mcp.Tool {
 			mcp.Description("Whether to include private parking facilities"),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithBoolean("wheelchair",
+			mcp.Description("Only return facilities that are wheelchair accessible or have disabled spaces"),
+			mcp.DefaultBool(false),
+		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results to return (max 50)"),
-			mcp.DefaultNumber(10),
+			mcp.Description(limits.limitDescription()),
+			mcp.DefaultNumber(float64(limits.DefaultLimit)),
+		),
+		mcp.WithString("cursor",
+			mcp.Description(cursorDescription),
 		),
 	)
 }
@@ -68,6 +76,7 @@ func FindParkingAreasTool() mcp.Tool {
 // HandleFindParkingFacilities implements finding parking facilities functionality
 func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logger := slog.Default().With("tool", "find_parking_facilities")
+	limits := searchLimitsFor("find_parking_facilities")
 
 	// Parse and validate coordinates
 	latStr := mcp.ParseString(req, "latitude", "")
@@ -75,7 +84,7 @@ func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (
 	radiusStr := mcp.ParseString(req, "radius", "")
 	facilityType := mcp.ParseString(req, "type", "")
 	includePrivateStr := mcp.ParseString(req, "include_private", "false")
-	limitStr := mcp.ParseString(req, "limit", "")
+	wheelchairOnly := mcp.ParseBoolean(req, "wheelchair", false)
 
 	if latStr == "" || lonStr == "" {
 		logger.Error("missing required coordinates", "latitude", latStr, "longitude", lonStr)
@@ -112,7 +121,7 @@ func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (
 		), nil
 	}
 
-	if err := ValidateCoordinates(lat, lon); err != nil {
+	if err := core.ValidateCoords(lat, lon); err != nil {
 		logger.Error("coordinate validation failed", "error", err)
 		return NewGeocodeDetailedError(
 			"INVALID_COORDINATES",
@@ -122,7 +131,7 @@ func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (
 		), nil
 	}
 
-	var radius float64 = 1000 // Default radius
+	radius := limits.DefaultRadius
 	if radiusStr != "" {
 		radius, err = strconv.ParseFloat(radiusStr, 64)
 		if err != nil {
@@ -137,13 +146,13 @@ func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (
 		}
 	}
 
-	if err := ValidateRadius(radius, 5000); err != nil {
+	if err := ValidateRadius(radius, limits.MaxRadius); err != nil {
 		logger.Error("radius validation failed", "radius", radius, "error", err)
 		return NewGeocodeDetailedError(
 			"INVALID_RADIUS",
 			err.Error(),
 			"",
-			"Radius must be positive and less than 5000 meters",
+			limits.radiusGuidance(),
 		), nil
 	}
 
@@ -153,27 +162,14 @@ func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (
 		includePrivate = strings.ToLower(includePrivateStr) == "true"
 	}
 
-	var limit int = 10 // Default limit
-	if limitStr != "" {
-		limitFloat, err := strconv.ParseFloat(limitStr, 64)
-		if err != nil {
-			logger.Error("invalid limit", "input", limitStr, "error", err)
-			return NewGeocodeDetailedError(
-				"INVALID_LIMIT",
-				fmt.Sprintf("Invalid limit value: %s", limitStr),
-				"",
-				"Limit must be a valid positive number",
-				"Example: 10 (numeric, no quotes)",
-			), nil
-		}
-		limit = int(limitFloat)
+	limit, errResult := parseListLimit(req, limits, logger)
+	if errResult != nil {
+		return errResult, nil
 	}
 
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 50 {
-		limit = 50
+	opts, errResult := parseListOptions(req, limit)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Build Overpass query using the fluent builder
@@ -195,27 +191,22 @@ func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (
 	}
 
 	// Process results
-	facilities, err := processParkingFacilities(results, lat, lon, includePrivate, facilityType)
+	facilities, err := processParkingFacilities(results, lat, lon, includePrivate, facilityType, wheelchairOnly)
 	if err != nil {
 		logger.Error("failed to process parking facilities", "error", err)
 		return core.NewError(core.ErrParseError, "Failed to process parking data").ToMCPResult(), nil
 	}
 
-	// Sort facilities by distance (closest first)
-	sort.Slice(facilities, func(i, j int) bool {
-		return facilities[i].Distance < facilities[j].Distance
-	})
-
-	// Limit results
-	if len(facilities) > limit {
-		facilities = facilities[:limit]
-	}
+	// Filter, order and take the requested page
+	facilities, _, nextCursor := processList(facilities, parkingSortKey, opts)
 
 	// Create output
 	output := struct {
 		Facilities []ParkingArea `json:"facilities"`
+		NextCursor string        `json:"next_cursor,omitempty"`
 	}{
 		Facilities: facilities,
+		NextCursor: nextCursor,
 	}
 
 	// Return result
@@ -230,55 +221,16 @@ func HandleFindParkingFacilities(ctx context.Context, req mcp.CallToolRequest) (
 
 // fetchParkingFacilities fetches parking facilities from the Overpass API
 func fetchParkingFacilities(ctx context.Context, query string) ([]osm.OverpassElement, error) {
-	// Build request
-	reqURL, err := url.Parse(osm.OverpassBaseURL)
-	if err != nil {
-		return nil, core.NewError(core.ErrInternalError, "Internal server error")
-	}
-
-	// Create HTTP request factory for retries
-	requestFactory := func() (*http.Request, error) {
-		req, err := http.NewRequestWithContext(
-			context.Background(),
-			http.MethodPost,
-			reqURL.String(),
-			strings.NewReader("data="+url.QueryEscape(query)),
-		)
-		if err != nil {
-			return nil, err
-		}
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		req.Header.Set("User-Agent", osm.UserAgent)
-		return req, nil
-	}
-
-	// Execute request with retries
-	client := osm.GetClient(ctx)
-	resp, err := core.WithRetryFactory(ctx, requestFactory, client, core.DefaultRetryOptions)
-	if err != nil {
-		return nil, core.ServiceError("Overpass", http.StatusServiceUnavailable, "Failed to communicate with OSM service")
-	}
-	defer resp.Body.Close()
-
-	// Process response
-	if resp.StatusCode != http.StatusOK {
-		return nil, core.ServiceError("Overpass", resp.StatusCode, fmt.Sprintf("OSM service error: %d", resp.StatusCode))
-	}
-
-	// Parse response
-	var overpassResp struct {
-		Elements []osm.OverpassElement `json:"elements"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
-		return nil, core.NewError(core.ErrParseError, "Failed to parse parking facilities data")
-	}
+	return executeOverpassQuery(ctx, query)
+}
 
-	return overpassResp.Elements, nil
+// parkingSortKey returns the distance and ID facilities are ordered by
+func parkingSortKey(p ParkingArea) (float64, string) {
+	return p.Distance, p.ID
 }
 
 // processParkingFacilities processes OSM elements into parking facilities
-func processParkingFacilities(elements []osm.OverpassElement, lat, lon float64, includePrivate bool, facilityType string) ([]ParkingArea, error) {
+func processParkingFacilities(elements []osm.OverpassElement, lat, lon float64, includePrivate bool, facilityType string, wheelchairOnly bool) ([]ParkingArea, error) {
 	facilities := make([]ParkingArea, 0)
 
 	for _, element := range elements {
@@ -316,13 +268,10 @@ func processParkingFacilities(elements []osm.OverpassElement, lat, lon float64,
 			elemLat, elemLon,
 		)
 
-		// Parse capacity if available
-		capacity := 0
-		if capacityStr := element.Tags["capacity"]; capacityStr != "" {
-			_, _ = fmt.Sscanf(capacityStr, "%d", &capacity)
-		} else if capacityStr := element.Tags["capacity:disabled"]; capacityStr != "" {
-			_, _ = fmt.Sscanf(capacityStr, "%d", &capacity)
-		}
+		// Parse capacities if available
+		capacity := parseCapacityTag(element.Tags["capacity"])
+		disabledCapacity := parseCapacityTag(element.Tags["capacity:disabled"])
+		chargingCapacity := parseCapacityTag(element.Tags["capacity:charging"])
 
 		// Determine if there's a fee
 		hasFee := false
@@ -336,6 +285,11 @@ func processParkingFacilities(elements []osm.OverpassElement, lat, lon float64,
 			hasWheelchair = true
 		}
 
+		// Skip inaccessible facilities if wheelchair access was requested
+		if wheelchairOnly && !hasWheelchair && disabledCapacity == 0 {
+			continue
+		}
+
 		// Create facility object
 		name := element.Tags["name"]
 		if name == "" {
@@ -354,14 +308,17 @@ func processParkingFacilities(elements []osm.OverpassElement, lat, lon float64,
 				Latitude:  elemLat,
 				Longitude: elemLon,
 			},
-			Distance:   distance,
-			Type:       element.Tags["parking"],
-			Access:     element.Tags["access"],
-			Capacity:   capacity,
-			Fee:        hasFee,
-			MaxStay:    element.Tags["maxstay"],
-			Wheelchair: hasWheelchair,
-			Operator:   element.Tags["operator"],
+			Distance:         distance,
+			Type:             element.Tags["parking"],
+			Access:           element.Tags["access"],
+			Capacity:         capacity,
+			DisabledCapacity: disabledCapacity,
+			ChargingCapacity: chargingCapacity,
+			Fee:              hasFee,
+			MaxStay:          element.Tags["maxstay"],
+			Wheelchair:       hasWheelchair,
+			Operator:         element.Tags["operator"],
+			Contact:          parseContact(element.Tags),
 		}
 
 		facilities = append(facilities, facility)
@@ -369,3 +326,13 @@ func processParkingFacilities(elements []osm.OverpassElement, lat, lon float64,
 
 	return facilities, nil
 }
+
+// parseCapacityTag parses an OSM capacity value, returning 0 for missing or
+// non-numeric values such as "yes".
+func parseCapacityTag(value string) int {
+	capacity := 0
+	if value != "" {
+		_, _ = fmt.Sscanf(value, "%d", &capacity)
+	}
+	return capacity
+}
@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+const (
+	// defaultMaxWalkDistance is the default search radius around the destination in meters
+	defaultMaxWalkDistance = 500.0
+	// maxMaxWalkDistance caps the search radius around the destination
+	maxMaxWalkDistance = 2000.0
+	// maxWalkingCandidates caps how many lots are routed on foot per request
+	maxWalkingCandidates = 10
+)
+
+// ParkingForDestinationInput defines the input parameters for parking_for_destination
+type ParkingForDestinationInput struct {
+	Destination     geo.Location  `json:"destination"`
+	Origin          *geo.Location `json:"origin,omitempty"`
+	MaxWalkDistance float64       `json:"max_walk_distance"`
+	Limit           int           `json:"limit"`
+	IncludePrivate  bool          `json:"include_private"`
+	Wheelchair      bool          `json:"wheelchair"`
+}
+
+// RouteLeg summarises one leg of a park-and-walk trip
+type RouteLeg struct {
+	Distance     float64  `json:"distance"` // in meters
+	Duration     float64  `json:"duration"` // in seconds
+	Polyline     string   `json:"polyline,omitempty"`
+	Instructions []string `json:"instructions,omitempty"`
+}
+
+// DestinationParking is a parking facility ranked by walking time to a destination
+type DestinationParking struct {
+	Parking      ParkingArea `json:"parking"`
+	WalkingTime  float64     `json:"walking_time"`            // in seconds
+	WalkingRoute RouteLeg    `json:"walking_route"`           // lot to destination on foot
+	DrivingRoute *RouteLeg   `json:"driving_route,omitempty"` // origin to lot by car, when origin is given
+}
+
+// ParkingForDestinationOutput defines the output for parking_for_destination
+type ParkingForDestinationOutput struct {
+	Destination geo.Location         `json:"destination"`
+	Facilities  []DestinationParking `json:"facilities"`
+}
+
+// ParkingForDestinationTool returns a tool definition for finding parking near a destination
+func ParkingForDestinationTool() mcp.Tool {
+	return mcp.NewTool("parking_for_destination",
+		mcp.WithDescription("Find parking near a destination ranked by walking time, with driving directions to each lot and walking directions onward"),
+		mcp.WithObject("destination",
+			mcp.Required(),
+			mcp.Description("The final destination as {latitude, longitude}"),
+		),
+		mcp.WithObject("origin",
+			mcp.Description("Optional starting point as {latitude, longitude}; when set, driving directions to each lot are included"),
+		),
+		mcp.WithNumber("max_walk_distance",
+			mcp.Description("Maximum straight-line distance in meters between lot and destination (max 2000)"),
+			mcp.DefaultNumber(defaultMaxWalkDistance),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of lots to return (max 10)"),
+			mcp.DefaultNumber(3),
+		),
+		mcp.WithBoolean("include_private",
+			mcp.Description("Whether to include private parking facilities"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("wheelchair",
+			mcp.Description("Only return facilities that are wheelchair accessible or have disabled spaces"),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+// HandleParkingForDestination finds parking around a destination and ranks it by walking time
+func HandleParkingForDestination(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "parking_for_destination")
+
+	// Parse input
+	var input ParkingForDestinationInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	dest := input.Destination
+	if err := core.ValidateCoords(dest.Latitude, dest.Longitude); err != nil {
+		logger.Error("invalid destination coordinates", "error", err)
+		return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid destination coordinates: %s", err)).ToMCPResult(), nil
+	}
+
+	if input.Origin != nil {
+		if err := core.ValidateCoords(input.Origin.Latitude, input.Origin.Longitude); err != nil {
+			logger.Error("invalid origin coordinates", "error", err)
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid origin coordinates: %s", err)).ToMCPResult(), nil
+		}
+	}
+
+	if input.MaxWalkDistance == 0 {
+		input.MaxWalkDistance = defaultMaxWalkDistance
+	}
+	if err := core.ValidateRadius(input.MaxWalkDistance, maxMaxWalkDistance); err != nil {
+		logger.Error("invalid max_walk_distance", "value", input.MaxWalkDistance, "error", err)
+		return core.NewError(core.ErrInvalidRadius, err.Error()).
+			WithGuidance("max_walk_distance must be positive and at most 2000 meters").
+			ToMCPResult(), nil
+	}
+
+	if input.Limit <= 0 {
+		input.Limit = 3
+	}
+	if input.Limit > maxWalkingCandidates {
+		input.Limit = maxWalkingCandidates
+	}
+
+	// Find candidate parking around the destination
+	query := core.NewOverpassBuilder().
+		WithTimeout(25).
+		WithCenter(dest.Latitude, dest.Longitude, input.MaxWalkDistance).
+		WithTag("amenity", "parking").
+		Build()
+
+	elements, err := fetchParkingFacilities(ctx, query)
+	if err != nil {
+		logger.Error("failed to fetch parking facilities", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.NewError(core.ErrServiceUnavailable, "Failed to fetch parking facilities").ToMCPResult(), nil
+	}
+
+	facilities, err := processParkingFacilities(elements, dest.Latitude, dest.Longitude, input.IncludePrivate, "", input.Wheelchair)
+	if err != nil {
+		logger.Error("failed to process parking facilities", "error", err)
+		return core.NewError(core.ErrParseError, "Failed to process parking data").ToMCPResult(), nil
+	}
+
+	// Only route the closest lots on foot to keep OSRM traffic bounded
+	sortByDistanceThenID(facilities, parkingSortKey)
+	if len(facilities) > maxWalkingCandidates {
+		facilities = facilities[:maxWalkingCandidates]
+	}
+
+	destCoord := []float64{dest.Longitude, dest.Latitude}
+	ranked := make([]DestinationParking, 0, len(facilities))
+	for _, facility := range facilities {
+		lotCoord := []float64{facility.Location.Longitude, facility.Location.Latitude}
+
+		walk, err := core.GetSimpleRoute(ctx, lotCoord, destCoord, "foot")
+		if err != nil {
+			logger.Warn("failed to get walking route", "parking_id", facility.ID, "error", err)
+			continue
+		}
+
+		ranked = append(ranked, DestinationParking{
+			Parking:      facility,
+			WalkingTime:  walk.Duration,
+			WalkingRoute: simpleRouteToLeg(walk),
+		})
+	}
+
+	if len(ranked) == 0 && len(facilities) > 0 {
+		return core.ServiceError("OSRM", http.StatusServiceUnavailable, "Failed to compute walking routes to the destination").
+			WithGuidance("Try again later or check that the destination is reachable on foot").
+			ToMCPResult(), nil
+	}
+
+	// Rank by walking time, closest lot first on ties
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].WalkingTime != ranked[j].WalkingTime {
+			return ranked[i].WalkingTime < ranked[j].WalkingTime
+		}
+		return lessByDistanceThenID(ranked[i].Parking.Distance, ranked[i].Parking.ID,
+			ranked[j].Parking.Distance, ranked[j].Parking.ID)
+	})
+	if len(ranked) > input.Limit {
+		ranked = ranked[:input.Limit]
+	}
+
+	// Add driving directions for the returned lots only
+	if input.Origin != nil {
+		originCoord := []float64{input.Origin.Longitude, input.Origin.Latitude}
+		for i := range ranked {
+			lot := ranked[i].Parking.Location
+			drive, err := core.GetSimpleRoute(ctx, originCoord, []float64{lot.Longitude, lot.Latitude}, "car")
+			if err != nil {
+				logger.Warn("failed to get driving route", "parking_id", ranked[i].Parking.ID, "error", err)
+				continue
+			}
+			leg := simpleRouteToLeg(drive)
+			ranked[i].DrivingRoute = &leg
+		}
+	}
+
+	output := ParkingForDestinationOutput{
+		Destination: dest,
+		Facilities:  ranked,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// simpleRouteToLeg converts an OSRM simple route into a RouteLeg
+func simpleRouteToLeg(route *core.SimpleRoute) RouteLeg {
+	return RouteLeg{
+		Distance:     route.Distance,
+		Duration:     route.Duration,
+		Polyline:     route.Polyline,
+		Instructions: route.Instructions,
+	}
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleParkingForDestinationValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+	}{
+		{
+			name: "Invalid destination latitude",
+			arguments: map[string]any{
+				"destination": map[string]any{"latitude": 95.0, "longitude": -74.0},
+			},
+		},
+		{
+			name: "Invalid origin longitude",
+			arguments: map[string]any{
+				"destination": map[string]any{"latitude": 40.7, "longitude": -74.0},
+				"origin":      map[string]any{"latitude": 40.7, "longitude": -190.0},
+			},
+		},
+		{
+			name: "Walk distance too large",
+			arguments: map[string]any{
+				"destination":       map[string]any{"latitude": 40.7, "longitude": -74.0},
+				"max_walk_distance": 5000.0,
+			},
+		},
+		{
+			name: "Malformed destination",
+			arguments: map[string]any{
+				"destination": "40.7,-74.0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "parking_for_destination",
+					Arguments: tt.arguments,
+				},
+			}
+
+			result, err := HandleParkingForDestination(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			AssertErrorResult(t, result, "Expected validation error")
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestProcessParkingFacilitiesCapacities(t *testing.T) {
+	elements := []osm.OverpassElement{
+		{
+			ID:   1,
+			Type: "node",
+			Lat:  40.7128,
+			Lon:  -74.0060,
+			Tags: map[string]string{
+				"amenity":           "parking",
+				"capacity":          "120",
+				"capacity:disabled": "6",
+				"capacity:charging": "4",
+			},
+		},
+		{
+			ID:   2,
+			Type: "node",
+			Lat:  40.7130,
+			Lon:  -74.0062,
+			Tags: map[string]string{
+				"amenity":    "parking",
+				"capacity":   "yes",
+				"wheelchair": "designated",
+			},
+		},
+		{
+			ID:   3,
+			Type: "node",
+			Lat:  40.7132,
+			Lon:  -74.0064,
+			Tags: map[string]string{
+				"amenity":  "parking",
+				"capacity": "30",
+			},
+		},
+	}
+
+	facilities, err := processParkingFacilities(elements, 40.7128, -74.0060, false, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(facilities) != 3 {
+		t.Fatalf("expected 3 facilities, got %d", len(facilities))
+	}
+
+	first := facilities[0]
+	if first.Capacity != 120 {
+		t.Errorf("expected capacity 120, got %d", first.Capacity)
+	}
+	if first.DisabledCapacity != 6 {
+		t.Errorf("expected disabled capacity 6, got %d", first.DisabledCapacity)
+	}
+	if first.ChargingCapacity != 4 {
+		t.Errorf("expected charging capacity 4, got %d", first.ChargingCapacity)
+	}
+	if facilities[1].Capacity != 0 {
+		t.Errorf("expected non-numeric capacity to be ignored, got %d", facilities[1].Capacity)
+	}
+}
+
+func TestProcessParkingFacilitiesWheelchairFilter(t *testing.T) {
+	elements := []osm.OverpassElement{
+		{ID: 1, Type: "node", Lat: 1, Lon: 1, Tags: map[string]string{"capacity:disabled": "2"}},
+		{ID: 2, Type: "node", Lat: 1, Lon: 1, Tags: map[string]string{"wheelchair": "yes"}},
+		{ID: 3, Type: "node", Lat: 1, Lon: 1, Tags: map[string]string{"wheelchair": "no"}},
+		{ID: 4, Type: "node", Lat: 1, Lon: 1, Tags: map[string]string{}},
+	}
+
+	facilities, err := processParkingFacilities(elements, 1, 1, false, "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(facilities) != 2 {
+		t.Fatalf("expected 2 accessible facilities, got %d", len(facilities))
+	}
+	for _, f := range facilities {
+		if f.ID != "1" && f.ID != "2" {
+			t.Errorf("unexpected facility %s in wheelchair results", f.ID)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// maxTerritoryZones caps how many zones a territory can be split into
+	maxTerritoryZones = 20
+
+	// maxTerritoryPoints caps the caller-supplied weighting points
+	maxTerritoryPoints = 10000
+
+	// territoryGridSize is the number of sample rows and columns used to
+	// balance zones by area
+	territoryGridSize = 64
+)
+
+// Territory balancing modes
+const (
+	BalanceByArea     = "area"
+	BalanceByPOICount = "poi_count"
+	BalanceByPoints   = "points"
+)
+
+// PartitionTerritoryInput defines the input parameters for partition_territory
+type PartitionTerritoryInput struct {
+	Polygon   []geo.Location `json:"polygon"`
+	Zones     int            `json:"zones"`
+	BalanceBy string         `json:"balance_by,omitempty"`
+	Category  string         `json:"category,omitempty"`
+	Points    []geo.Location `json:"points,omitempty"`
+}
+
+// TerritoryZoneProperties are the GeoJSON properties of a zone
+type TerritoryZoneProperties struct {
+	Zone     int     `json:"zone"`
+	Weight   float64 `json:"weight"` // area in km², or number of POIs/points
+	Share    float64 `json:"share"`  // fraction of the total weight
+	AreaSqKm float64 `json:"area_sq_km"`
+}
+
+// TerritoryZone is a GeoJSON Feature describing one zone
+type TerritoryZone struct {
+	Type       string                  `json:"type"`
+	Properties TerritoryZoneProperties `json:"properties"`
+	Geometry   struct {
+		Type        string         `json:"type"`
+		Coordinates [][][2]float64 `json:"coordinates"` // [lon, lat]
+	} `json:"geometry"`
+}
+
+// PartitionTerritoryOutput defines the output for partition_territory
+type PartitionTerritoryOutput struct {
+	BalanceBy   string  `json:"balance_by"`
+	TotalWeight float64 `json:"total_weight"`
+	Imbalance   float64 `json:"imbalance"` // largest relative deviation from an equal share
+	GeoJSON     struct {
+		Type     string          `json:"type"`
+		Features []TerritoryZone `json:"features"`
+	} `json:"geojson"`
+}
+
+// PartitionTerritoryTool returns a tool definition for splitting a polygon into balanced zones
+func PartitionTerritoryTool() mcp.Tool {
+	return mcp.NewTool("partition_territory",
+		mcp.WithDescription("Split a polygon into N balanced zones for sales or delivery territory planning, balanced by area, by the number of POIs of a category, or by caller-supplied points (e.g. customer locations). Returns the zones as a GeoJSON FeatureCollection"),
+		mcp.WithArray("polygon",
+			mcp.Required(),
+			mcp.Description("The territory as an array of {latitude, longitude} points (3-500 points)"),
+		),
+		mcp.WithNumber("zones",
+			mcp.Required(),
+			mcp.Description("Number of zones to create (2-20)"),
+		),
+		mcp.WithString("balance_by",
+			mcp.Description("What to balance: 'area', 'poi_count' (requires category) or 'points' (requires points)"),
+			mcp.DefaultString(BalanceByArea),
+		),
+		mcp.WithString("category",
+			mcp.Description("POI category to count when balance_by is 'poi_count' (e.g., restaurant, pharmacy)"),
+		),
+		mcp.WithArray("points",
+			mcp.Description("Array of {latitude, longitude} points to balance when balance_by is 'points' (max 10000)"),
+		),
+	)
+}
+
+// HandlePartitionTerritory splits a polygon into balanced zones
+func HandlePartitionTerritory(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "partition_territory")
+
+	// Parse input
+	var input PartitionTerritoryInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").
+			WithGuidance("polygon and points must be arrays of {latitude, longitude} objects").
+			ToMCPResult(), nil
+	}
+
+	if len(input.Polygon) < 3 || len(input.Polygon) > maxPolygonPoints {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Polygon must have between 3 and %d points", maxPolygonPoints)).ToMCPResult(), nil
+	}
+	for _, p := range input.Polygon {
+		if err := core.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid polygon coordinates: %s", err)).ToMCPResult(), nil
+		}
+	}
+
+	if input.Zones < 2 || input.Zones > maxTerritoryZones {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("zones must be between 2 and %d", maxTerritoryZones)).ToMCPResult(), nil
+	}
+
+	input.BalanceBy = strings.ToLower(strings.TrimSpace(input.BalanceBy))
+	if input.BalanceBy == "" {
+		input.BalanceBy = BalanceByArea
+	}
+
+	var samples []geo.Location
+	switch input.BalanceBy {
+	case BalanceByArea:
+		samples = areaSamples(input.Polygon)
+
+	case BalanceByPOICount:
+		input.Category = strings.TrimSpace(input.Category)
+		if input.Category == "" {
+			return core.NewError(core.ErrEmptyParameter, "category is required when balance_by is 'poi_count'").ToMCPResult(), nil
+		}
+		query := buildPolygonCategoryQuery(input.Polygon, mapCategoryToOSMTags(input.Category))
+		elements, err := executeOverpassQuery(ctx, query)
+		if err != nil {
+			logger.Error("failed to query POIs in polygon", "error", err)
+			if mcpErr, ok := err.(*core.MCPError); ok {
+				return mcpErr.ToMCPResult(), nil
+			}
+			return core.NewError(core.ErrServiceUnavailable, "Failed to count POIs in polygon").ToMCPResult(), nil
+		}
+		samples = elementLocations(elements)
+
+	case BalanceByPoints:
+		if len(input.Points) == 0 || len(input.Points) > maxTerritoryPoints {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Between 1 and %d points are required when balance_by is 'points'", maxTerritoryPoints)).ToMCPResult(), nil
+		}
+		for i, p := range input.Points {
+			if err := core.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+				return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid coordinates at index %d: %s", i, err)).ToMCPResult(), nil
+			}
+		}
+		samples = input.Points
+
+	default:
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Unsupported balance_by: %s", input.BalanceBy)).
+			WithGuidance("Use 'area', 'poi_count' or 'points'. Drive-time balancing needs a travel-time matrix, which this tool does not compute").
+			ToMCPResult(), nil
+	}
+
+	// Weighting points outside the territory are ignored
+	inside := make([]geo.Location, 0, len(samples))
+	for _, p := range samples {
+		if geo.PointInPolygon(p.Latitude, p.Longitude, input.Polygon) {
+			inside = append(inside, p)
+		}
+	}
+	if len(inside) == 0 && input.BalanceBy != BalanceByArea {
+		logger.Warn("no weighting points inside polygon, balancing by area", "balance_by", input.BalanceBy)
+		input.BalanceBy = BalanceByArea
+		inside = areaSamples(input.Polygon)
+	}
+
+	output := partitionTerritory(input.Polygon, inside, input.Zones, input.BalanceBy)
+
+	logger.Info("partitioned territory", "zones", input.Zones, "balance_by", output.BalanceBy, "imbalance", output.Imbalance)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// partitionTerritory splits polygon into n zones with roughly equal numbers
+// of samples by recursive bisection along the longer axis
+func partitionTerritory(polygon, samples []geo.Location, n int, balanceBy string) *PartitionTerritoryOutput {
+	rings, counts := bisectTerritory(polygon, samples, n)
+
+	output := &PartitionTerritoryOutput{BalanceBy: balanceBy}
+	output.GeoJSON.Type = "FeatureCollection"
+
+	weights := make([]float64, n)
+	for i, ring := range rings {
+		area := geo.PolygonArea(ring) / 1e6
+		weights[i] = float64(counts[i])
+		if balanceBy == BalanceByArea {
+			weights[i] = area
+		}
+		output.TotalWeight += weights[i]
+
+		zone := TerritoryZone{Type: "Feature"}
+		zone.Properties = TerritoryZoneProperties{Zone: i + 1, Weight: math.Round(weights[i]*1000) / 1000, AreaSqKm: math.Round(area*1000) / 1000}
+		zone.Geometry.Type = "Polygon"
+		zone.Geometry.Coordinates = [][][2]float64{closedRingCoordinates(ring)}
+		output.GeoJSON.Features = append(output.GeoJSON.Features, zone)
+	}
+
+	if output.TotalWeight > 0 {
+		mean := output.TotalWeight / float64(n)
+		for i := range output.GeoJSON.Features {
+			output.GeoJSON.Features[i].Properties.Share = math.Round(weights[i]/output.TotalWeight*1000) / 1000
+			output.Imbalance = math.Max(output.Imbalance, math.Abs(weights[i]-mean)/mean)
+		}
+	}
+	output.TotalWeight = math.Round(output.TotalWeight*1000) / 1000
+	output.Imbalance = math.Round(output.Imbalance*1000) / 1000
+	return output
+}
+
+// bisectTerritory recursively splits a ring into n rings, returning each
+// ring with the number of samples it contains
+func bisectTerritory(ring, samples []geo.Location, n int) ([][]geo.Location, []int) {
+	if n == 1 {
+		return [][]geo.Location{ring}, []int{len(samples)}
+	}
+
+	bbox := geo.NewBoundingBox()
+	for _, p := range ring {
+		bbox.ExtendWithPoint(p.Latitude, p.Longitude)
+	}
+
+	// Cut across the longer side, measured in ground distance
+	cosLat := math.Cos((bbox.MinLat + bbox.MaxLat) / 2 * math.Pi / 180)
+	byLon := (bbox.MaxLon-bbox.MinLon)*cosLat >= bbox.MaxLat-bbox.MinLat
+	coord := func(p geo.Location) float64 {
+		if byLon {
+			return p.Longitude
+		}
+		return p.Latitude
+	}
+
+	left := n / 2
+	lo, hi := bbox.MinLat, bbox.MaxLat
+	if byLon {
+		lo, hi = bbox.MinLon, bbox.MaxLon
+	}
+
+	// Place the cut so the first side holds left/n of the samples
+	cut := lo + (hi-lo)*float64(left)/float64(n)
+	sorted := append([]geo.Location{}, samples...)
+	sort.Slice(sorted, func(i, j int) bool { return coord(sorted[i]) < coord(sorted[j]) })
+	if k := len(sorted) * left / n; k > 0 && k < len(sorted) {
+		cut = (coord(sorted[k-1]) + coord(sorted[k])) / 2
+	}
+
+	var lowSamples, highSamples []geo.Location
+	for _, p := range samples {
+		if coord(p) < cut {
+			lowSamples = append(lowSamples, p)
+		} else {
+			highSamples = append(highSamples, p)
+		}
+	}
+
+	lowRings, lowCounts := bisectTerritory(clipRing(ring, byLon, cut, true), lowSamples, left)
+	highRings, highCounts := bisectTerritory(clipRing(ring, byLon, cut, false), highSamples, n-left)
+	return append(lowRings, highRings...), append(lowCounts, highCounts...)
+}
+
+// clipRing clips a polygon ring to one side of a meridian (byLon) or
+// parallel at value, keeping the side below it when keepBelow is set
+// (Sutherland-Hodgman against a single edge)
+func clipRing(ring []geo.Location, byLon bool, value float64, keepBelow bool) []geo.Location {
+	coord := func(p geo.Location) float64 {
+		if byLon {
+			return p.Longitude
+		}
+		return p.Latitude
+	}
+	inside := func(p geo.Location) bool {
+		if keepBelow {
+			return coord(p) <= value
+		}
+		return coord(p) >= value
+	}
+	intersect := func(a, b geo.Location) geo.Location {
+		t := (value - coord(a)) / (coord(b) - coord(a))
+		return geo.Location{
+			Latitude:  a.Latitude + t*(b.Latitude-a.Latitude),
+			Longitude: a.Longitude + t*(b.Longitude-a.Longitude),
+		}
+	}
+
+	clipped := make([]geo.Location, 0, len(ring)+2)
+	for i := range ring {
+		current, next := ring[i], ring[(i+1)%len(ring)]
+		switch {
+		case inside(current) && inside(next):
+			clipped = append(clipped, next)
+		case inside(current):
+			clipped = append(clipped, intersect(current, next))
+		case inside(next):
+			clipped = append(clipped, intersect(current, next), next)
+		}
+	}
+	return clipped
+}
+
+// areaSamples returns a regular grid of points inside a polygon
+func areaSamples(polygon []geo.Location) []geo.Location {
+	bbox := geo.NewBoundingBox()
+	for _, p := range polygon {
+		bbox.ExtendWithPoint(p.Latitude, p.Longitude)
+	}
+
+	dLat := (bbox.MaxLat - bbox.MinLat) / territoryGridSize
+	dLon := (bbox.MaxLon - bbox.MinLon) / territoryGridSize
+	samples := make([]geo.Location, 0, territoryGridSize*territoryGridSize)
+	for i := 0; i < territoryGridSize; i++ {
+		for j := 0; j < territoryGridSize; j++ {
+			lat := bbox.MinLat + (float64(i)+0.5)*dLat
+			lon := bbox.MinLon + (float64(j)+0.5)*dLon
+			if geo.PointInPolygon(lat, lon, polygon) {
+				samples = append(samples, geo.Location{Latitude: lat, Longitude: lon})
+			}
+		}
+	}
+	return samples
+}
+
+// elementLocations returns the position of each element, using the center
+// for ways and relations
+func elementLocations(elements []osm.OverpassElement) []geo.Location {
+	locations := make([]geo.Location, 0, len(elements))
+	for _, element := range elements {
+		lat, lon := element.Lat, element.Lon
+		if element.Center != nil {
+			lat, lon = element.Center.Lat, element.Center.Lon
+		}
+		if lat == 0 && lon == 0 {
+			continue
+		}
+		locations = append(locations, geo.Location{Latitude: lat, Longitude: lon})
+	}
+	return locations
+}
+
+// closedRingCoordinates converts a ring to closed GeoJSON [lon, lat] positions
+func closedRingCoordinates(ring []geo.Location) [][2]float64 {
+	coords := make([][2]float64, 0, len(ring)+1)
+	for _, p := range ring {
+		coords = append(coords, [2]float64{p.Longitude, p.Latitude})
+	}
+	if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+		coords = append(coords, [2]float64{ring[0].Longitude, ring[0].Latitude})
+	}
+	return coords
+}
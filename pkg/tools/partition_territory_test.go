@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// territorySquare is a ~2.2km square
+var territorySquare = []geo.Location{
+	{Latitude: 0, Longitude: 0},
+	{Latitude: 0, Longitude: 0.02},
+	{Latitude: 0.02, Longitude: 0.02},
+	{Latitude: 0.02, Longitude: 0},
+}
+
+func TestPartitionTerritoryByArea(t *testing.T) {
+	output := partitionTerritory(territorySquare, areaSamples(territorySquare), 4, BalanceByArea)
+
+	if len(output.GeoJSON.Features) != 4 {
+		t.Fatalf("expected 4 zones, got %d", len(output.GeoJSON.Features))
+	}
+	if output.Imbalance > 0.05 {
+		t.Errorf("area zones imbalanced by %.3f", output.Imbalance)
+	}
+	for _, zone := range output.GeoJSON.Features {
+		ring := zone.Geometry.Coordinates[0]
+		if ring[0] != ring[len(ring)-1] {
+			t.Errorf("zone %d ring is not closed", zone.Properties.Zone)
+		}
+		if math.Abs(zone.Properties.Share-0.25) > 0.02 {
+			t.Errorf("zone %d share = %.3f, want about 0.25", zone.Properties.Zone, zone.Properties.Share)
+		}
+	}
+}
+
+func TestPartitionTerritoryByPoints(t *testing.T) {
+	// Points crowd the western side, so the western zone must be narrower
+	points := make([]geo.Location, 0)
+	for i := 0; i < 30; i++ {
+		points = append(points, geo.Location{Latitude: 0.01, Longitude: 0.001 + float64(i)*0.0001})
+	}
+	for i := 0; i < 10; i++ {
+		points = append(points, geo.Location{Latitude: 0.01, Longitude: 0.015 + float64(i)*0.0001})
+	}
+
+	wide := []geo.Location{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 0.03},
+		{Latitude: 0.02, Longitude: 0.03},
+		{Latitude: 0.02, Longitude: 0},
+	}
+	output := partitionTerritory(wide, points, 2, BalanceByPoints)
+	west, east := output.GeoJSON.Features[0].Properties, output.GeoJSON.Features[1].Properties
+	if west.Weight != 20 || east.Weight != 20 {
+		t.Errorf("weights = %.0f/%.0f, want 20/20", west.Weight, east.Weight)
+	}
+	if west.AreaSqKm >= east.AreaSqKm {
+		t.Errorf("expected the crowded zone to be smaller: %.3f vs %.3f km²", west.AreaSqKm, east.AreaSqKm)
+	}
+}
+
+func TestClipRing(t *testing.T) {
+	below := clipRing(territorySquare, true, 0.005, true)
+	area := geo.PolygonArea(below) / geo.PolygonArea(territorySquare)
+	if math.Abs(area-0.25) > 1e-6 {
+		t.Errorf("clipped area fraction = %.4f, want 0.25", area)
+	}
+	if above := clipRing(territorySquare, false, 0.03, false); len(above) != 0 {
+		t.Errorf("expected empty ring beyond the polygon, got %v", above)
+	}
+}
+
+func TestHandlePartitionTerritoryValidation(t *testing.T) {
+	polygon := []any{
+		map[string]any{"latitude": 0.0, "longitude": 0.0},
+		map[string]any{"latitude": 0.0, "longitude": 0.02},
+		map[string]any{"latitude": 0.02, "longitude": 0.02},
+	}
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"Too few zones", map[string]any{"polygon": polygon, "zones": 1.0}},
+		{"Degenerate polygon", map[string]any{"polygon": polygon[:2], "zones": 2.0}},
+		{"POI count without category", map[string]any{"polygon": polygon, "zones": 2.0, "balance_by": "poi_count"}},
+		{"Points without points", map[string]any{"polygon": polygon, "zones": 2.0, "balance_by": "points"}},
+		{"Drive time unsupported", map[string]any{"polygon": polygon, "zones": 2.0, "balance_by": "drive_time"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "partition_territory", Arguments: tt.args}}
+			result, err := HandlePartitionTerritory(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// crossingMatchDistance is how far from the route a crossing node may be,
+	// in meters
+	crossingMatchDistance = 10.0
+	// sidewalkMatchDistance is how far from a route segment a street may be
+	// to be considered the street walked along, in meters
+	sidewalkMatchDistance = 12.0
+	// maxSafetyQueryPoints caps the route vertices sent to Overpass
+	maxSafetyQueryPoints = 150
+	// safetySimplifyTolerance is the initial simplification tolerance for the
+	// corridor query, in meters
+	safetySimplifyTolerance = 3.0
+	// maxSafetyRouteLength skips annotation for routes longer than this many
+	// meters, where the corridor query would be too heavy
+	maxSafetyRouteLength = 25000.0
+)
+
+// Crossing types reported on walking routes
+const (
+	CrossingSignals = "signals" // signal-controlled
+	CrossingZebra   = "zebra"   // marked with priority for pedestrians
+	CrossingMarked  = "marked"  // marked without signals or zebra priority
+	CrossingNone    = "none"    // unmarked
+	CrossingRailway = "railway" // level crossing of a railway or tramway
+	CrossingUnknown = "unknown" // crossing mapped without a type
+)
+
+// RouteCrossing is a mapped pedestrian crossing on a route
+type RouteCrossing struct {
+	ID            string   `json:"id"`
+	Type          string   `json:"type"`
+	Location      Location `json:"location"`
+	DistanceAlong float64  `json:"distance_along"`           // meters from the start of the route
+	Island        bool     `json:"island,omitempty"`         // refuge island in the middle of the road
+	TactilePaving string   `json:"tactile_paving,omitempty"` // tactile_paving tag
+	Kerb          string   `json:"kerb,omitempty"`           // kerb tag, e.g. lowered or flush
+}
+
+// SidewalkCoverage splits a route's length by the kind of walking surface
+type SidewalkCoverage struct {
+	Footway    float64 `json:"footway"`     // meters on footways and other dedicated paths
+	Sidewalk   float64 `json:"sidewalk"`    // meters along roads with a sidewalk
+	NoSidewalk float64 `json:"no_sidewalk"` // meters along roads mapped without a sidewalk
+	Unknown    float64 `json:"unknown"`     // meters where sidewalks are not mapped
+}
+
+// PedestrianSafety annotates a walking route with crossings and sidewalks
+type PedestrianSafety struct {
+	Crossings      []RouteCrossing  `json:"crossings"`
+	CrossingCounts map[string]int   `json:"crossing_counts"`
+	Sidewalk       SidewalkCoverage `json:"sidewalk"`
+	Message        string           `json:"message,omitempty"`
+}
+
+// safetyDefault reports whether routes for an OSRM profile are annotated
+// with pedestrian safety information by default
+func safetyDefault(profile string) bool {
+	return profile == "foot"
+}
+
+// annotatePedestrianSafety looks up crossings and sidewalks along a route
+// with a corridor Overpass query
+func annotatePedestrianSafety(ctx context.Context, path []geo.Location) (*PedestrianSafety, error) {
+	if pathLength(path) > maxSafetyRouteLength {
+		return &PedestrianSafety{
+			Crossings:      []RouteCrossing{},
+			CrossingCounts: map[string]int{},
+			Message:        fmt.Sprintf("Route is longer than %.0f km; crossings and sidewalks were not checked", maxSafetyRouteLength/1000),
+		}, nil
+	}
+
+	elements, err := executeOverpassQuery(ctx, buildSafetyQuery(path))
+	if err != nil {
+		return nil, err
+	}
+	return pedestrianSafetyFromElements(elements, path), nil
+}
+
+// buildSafetyQuery finds crossing nodes and streets in a corridor along the
+// route. Overpass treats an around filter with several coordinates as a line.
+func buildSafetyQuery(path []geo.Location) string {
+	simplified := geo.SimplifyToLimit(path, safetySimplifyTolerance, maxSafetyQueryPoints)
+
+	coords := make([]string, len(simplified))
+	for i, p := range simplified {
+		coords[i] = fmt.Sprintf("%.6f,%.6f", p.Latitude, p.Longitude)
+	}
+	line := strings.Join(coords, ",")
+
+	return fmt.Sprintf(`[out:json][timeout:25];
+(node["highway"="crossing"](around:%.0[1]f,%[3]s);node["railway"~"^(level_)?crossing$"](around:%.0[1]f,%[3]s);way["highway"](around:%.0[2]f,%[3]s););
+out geom;`, crossingMatchDistance, sidewalkMatchDistance, line)
+}
+
+// pedestrianSafetyFromElements builds the annotation from Overpass elements
+func pedestrianSafetyFromElements(elements []osm.OverpassElement, path []geo.Location) *PedestrianSafety {
+	safety := &PedestrianSafety{
+		Crossings:      []RouteCrossing{},
+		CrossingCounts: map[string]int{},
+	}
+
+	var streets []osm.OverpassElement
+	for _, element := range elements {
+		switch element.Type {
+		case "node":
+			if geo.DistanceToPolyline(element.Lat, element.Lon, path) > crossingMatchDistance {
+				continue
+			}
+			crossing := RouteCrossing{
+				ID:            fmt.Sprintf("node/%d", element.ID),
+				Type:          crossingType(element.Tags),
+				Location:      Location{Latitude: element.Lat, Longitude: element.Lon},
+				DistanceAlong: distanceAlongPath(path, element.Lat, element.Lon),
+				Island:        element.Tags["crossing:island"] == "yes" || element.Tags["traffic_calming"] == "island",
+				TactilePaving: element.Tags["tactile_paving"],
+				Kerb:          element.Tags["kerb"],
+			}
+			safety.Crossings = append(safety.Crossings, crossing)
+			safety.CrossingCounts[crossing.Type]++
+		case "way":
+			if len(element.Geometry) >= 2 {
+				streets = append(streets, element)
+			}
+		}
+	}
+
+	sort.Slice(safety.Crossings, func(i, j int) bool {
+		if safety.Crossings[i].DistanceAlong != safety.Crossings[j].DistanceAlong {
+			return safety.Crossings[i].DistanceAlong < safety.Crossings[j].DistanceAlong
+		}
+		return safety.Crossings[i].ID < safety.Crossings[j].ID
+	})
+
+	safety.Sidewalk = sidewalkCoverage(path, streets)
+	return safety
+}
+
+// crossingType classifies a crossing node from its crossing tags
+func crossingType(tags map[string]string) string {
+	crossing := tags["crossing"]
+	ref := tags["crossing_ref"]
+
+	switch {
+	case tags["railway"] != "":
+		return CrossingRailway
+	case crossing == "traffic_signals" || tags["crossing:signals"] == "yes" ||
+		ref == "pelican" || ref == "toucan" || ref == "puffin" || ref == "pegasus":
+		return CrossingSignals
+	case crossing == "zebra" || ref == "zebra" || tags["crossing:markings"] == "zebra":
+		return CrossingZebra
+	case crossing == "unmarked" || crossing == "no" || tags["crossing:markings"] == "no":
+		return CrossingNone
+	case crossing == "marked" || crossing == "uncontrolled" ||
+		(tags["crossing:markings"] != "" && tags["crossing:markings"] != "no"):
+		return CrossingMarked
+	default:
+		return CrossingUnknown
+	}
+}
+
+// sidewalkCoverage assigns each route segment to the nearest street and sums
+// segment lengths by that street's sidewalk status
+func sidewalkCoverage(path []geo.Location, streets []osm.OverpassElement) SidewalkCoverage {
+	lines := make([][]geo.Location, len(streets))
+	for i, street := range streets {
+		lines[i] = wayGeometry(street)
+	}
+
+	var coverage SidewalkCoverage
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		length := geo.HaversineDistance(a.Latitude, a.Longitude, b.Latitude, b.Longitude)
+		mid := geo.Midpoint(a, b)
+
+		status := "unknown"
+		best := sidewalkMatchDistance
+		for j, street := range streets {
+			d := geo.DistanceToPolyline(mid.Latitude, mid.Longitude, lines[j])
+			if d <= best {
+				best = d
+				status = sidewalkStatus(street.Tags)
+			}
+		}
+
+		switch status {
+		case "footway":
+			coverage.Footway += length
+		case "yes":
+			coverage.Sidewalk += length
+		case "no":
+			coverage.NoSidewalk += length
+		default:
+			coverage.Unknown += length
+		}
+	}
+
+	coverage.Footway = roundMeters(coverage.Footway)
+	coverage.Sidewalk = roundMeters(coverage.Sidewalk)
+	coverage.NoSidewalk = roundMeters(coverage.NoSidewalk)
+	coverage.Unknown = roundMeters(coverage.Unknown)
+	return coverage
+}
+
+// sidewalkStatus returns footway for dedicated pedestrian ways, yes or no for
+// roads with sidewalk tags, and unknown otherwise
+func sidewalkStatus(tags map[string]string) string {
+	switch tags["highway"] {
+	case "footway", "pedestrian", "path", "steps", "living_street", "corridor":
+		return "footway"
+	}
+
+	values := []string{tags["sidewalk"], tags["sidewalk:both"], tags["sidewalk:left"], tags["sidewalk:right"]}
+	mapped := false
+	for _, value := range values {
+		switch value {
+		case "both", "left", "right", "yes", "separate":
+			return "yes"
+		case "no", "none":
+			mapped = true
+		}
+	}
+	if mapped {
+		return "no"
+	}
+	return "unknown"
+}
+
+// wayGeometry converts the geometry of a way queried with "out geom"
+func wayGeometry(element osm.OverpassElement) []geo.Location {
+	line := make([]geo.Location, len(element.Geometry))
+	for i, p := range element.Geometry {
+		line[i] = geo.Location{Latitude: p.Lat, Longitude: p.Lon}
+	}
+	return line
+}
+
+// distanceAlongPath returns how far along a path, in meters, the point on the
+// path nearest to the given point lies
+func distanceAlongPath(path []geo.Location, lat, lon float64) float64 {
+	bestDistance := -1.0
+	along, bestAlong := 0.0, 0.0
+	for i := 1; i < len(path); i++ {
+		segment := path[i-1 : i+1]
+		nearest, d := geo.NearestPointOnPolyline(lat, lon, segment)
+		if bestDistance < 0 || d < bestDistance {
+			bestDistance = d
+			bestAlong = along + geo.HaversineDistance(path[i-1].Latitude, path[i-1].Longitude, nearest.Latitude, nearest.Longitude)
+		}
+		along += geo.HaversineDistance(path[i-1].Latitude, path[i-1].Longitude, path[i].Latitude, path[i].Longitude)
+	}
+	return roundMeters(bestAlong)
+}
+
+// pathLength returns the length of a path in meters
+func pathLength(path []geo.Location) float64 {
+	length := 0.0
+	for i := 1; i < len(path); i++ {
+		length += geo.HaversineDistance(path[i-1].Latitude, path[i-1].Longitude, path[i].Latitude, path[i].Longitude)
+	}
+	return length
+}
+
+// roundMeters rounds a distance to whole meters
+func roundMeters(m float64) float64 {
+	return float64(int(m + 0.5))
+}
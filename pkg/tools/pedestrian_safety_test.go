@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestCrossingType(t *testing.T) {
+	tests := []struct {
+		tags map[string]string
+		want string
+	}{
+		{map[string]string{"highway": "crossing", "crossing": "traffic_signals"}, CrossingSignals},
+		{map[string]string{"highway": "crossing", "crossing": "uncontrolled", "crossing_ref": "zebra"}, CrossingZebra},
+		{map[string]string{"highway": "crossing", "crossing_ref": "toucan"}, CrossingSignals},
+		{map[string]string{"highway": "crossing", "crossing": "marked"}, CrossingMarked},
+		{map[string]string{"highway": "crossing", "crossing": "unmarked"}, CrossingNone},
+		{map[string]string{"highway": "crossing", "crossing:markings": "no"}, CrossingNone},
+		{map[string]string{"railway": "crossing"}, CrossingRailway},
+		{map[string]string{"highway": "crossing"}, CrossingUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := crossingType(tt.tags); got != tt.want {
+			t.Errorf("crossingType(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestSidewalkStatus(t *testing.T) {
+	tests := []struct {
+		tags map[string]string
+		want string
+	}{
+		{map[string]string{"highway": "footway"}, "footway"},
+		{map[string]string{"highway": "residential", "sidewalk": "both"}, "yes"},
+		{map[string]string{"highway": "residential", "sidewalk:right": "yes", "sidewalk:left": "no"}, "yes"},
+		{map[string]string{"highway": "tertiary", "sidewalk": "no"}, "no"},
+		{map[string]string{"highway": "residential"}, "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := sidewalkStatus(tt.tags); got != tt.want {
+			t.Errorf("sidewalkStatus(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+// safetyWay builds a way element queried with "out geom"
+func safetyWay(id int, tags map[string]string, points ...geo.Location) osm.OverpassElement {
+	element := osm.OverpassElement{ID: id, Type: "way", Tags: tags}
+	for _, p := range points {
+		element.Geometry = append(element.Geometry, struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		}{p.Latitude, p.Longitude})
+	}
+	return element
+}
+
+func TestPedestrianSafetyFromElements(t *testing.T) {
+	// A 2 km walk due east along the equator-ish parallel
+	a := geo.Location{Latitude: 0, Longitude: 0}
+	b := geo.Location{Latitude: 0, Longitude: 0.009}
+	c := geo.Location{Latitude: 0, Longitude: 0.018}
+	path := []geo.Location{a, b, c}
+
+	elements := []osm.OverpassElement{
+		{ID: 2, Type: "node", Lat: 0, Lon: 0.012, Tags: map[string]string{"highway": "crossing", "crossing": "zebra"}},
+		{ID: 1, Type: "node", Lat: 0, Lon: 0.003, Tags: map[string]string{"highway": "crossing", "crossing": "traffic_signals", "tactile_paving": "yes"}},
+		{ID: 3, Type: "node", Lat: 0.01, Lon: 0.003, Tags: map[string]string{"highway": "crossing"}}, // far off the route
+		safetyWay(10, map[string]string{"highway": "residential", "sidewalk": "both"}, a, b),
+		safetyWay(11, map[string]string{"highway": "tertiary", "sidewalk": "no"}, b, c),
+	}
+
+	safety := pedestrianSafetyFromElements(elements, path)
+
+	if len(safety.Crossings) != 2 {
+		t.Fatalf("expected 2 crossings on the route, got %d", len(safety.Crossings))
+	}
+	if safety.Crossings[0].ID != "node/1" || safety.Crossings[0].Type != CrossingSignals {
+		t.Errorf("first crossing = %+v, want the signal crossing", safety.Crossings[0])
+	}
+	if safety.Crossings[0].TactilePaving != "yes" {
+		t.Errorf("expected tactile paving on the first crossing")
+	}
+	if safety.Crossings[1].Type != CrossingZebra || safety.Crossings[1].DistanceAlong <= safety.Crossings[0].DistanceAlong {
+		t.Errorf("second crossing = %+v, want the zebra further along", safety.Crossings[1])
+	}
+	if safety.CrossingCounts[CrossingSignals] != 1 || safety.CrossingCounts[CrossingZebra] != 1 {
+		t.Errorf("unexpected crossing counts %v", safety.CrossingCounts)
+	}
+
+	half := geo.HaversineDistance(0, 0, 0, 0.009)
+	if math.Abs(safety.Sidewalk.Sidewalk-half) > 1 || math.Abs(safety.Sidewalk.NoSidewalk-half) > 1 {
+		t.Errorf("sidewalk coverage = %+v, want about %.0f m with and without", safety.Sidewalk, half)
+	}
+	if safety.Sidewalk.Unknown != 0 {
+		t.Errorf("expected no unknown sidewalk length, got %.0f", safety.Sidewalk.Unknown)
+	}
+}
+
+func TestBuildSafetyQuery(t *testing.T) {
+	path := []geo.Location{{Latitude: 51.5, Longitude: -0.1}, {Latitude: 51.501, Longitude: -0.1}}
+	query := buildSafetyQuery(path)
+
+	for _, want := range []string{
+		`node["highway"="crossing"](around:10,51.500000,-0.100000,51.501000,-0.100000)`,
+		`way["highway"](around:12,51.500000,-0.100000,51.501000,-0.100000)`,
+		"out geom;",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm/queries"
+)
+
+// placeFiltersDescription documents the filters parameter of place searches
+const placeFiltersDescription = `Secondary tag filters every place must match, e.g. {"cuisine": ["pizza", "italian"], "diet": "vegan", "wheelchair": "yes", "outdoor_seating": true, "takeaway": true, "brand": "Starbucks"}. cuisine matches any of its values; diet requires each listed diet (vegan, vegetarian, halal, kosher, gluten_free, ...); wheelchair is yes, limited or no`
+
+// filterTokenPattern matches cuisine and diet values, which OSM writes in
+// lower case with underscores
+var filterTokenPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// wheelchairValues maps a requested wheelchair access level to the tag
+// values that satisfy it
+var wheelchairValues = map[string]string{
+	"yes":     "~^(yes|designated)$",
+	"limited": "~^(yes|designated|limited)$",
+	"no":      "no",
+}
+
+// parsePlaceFilters converts the filters parameter into tag filters for
+// the queries package, keyed by tag
+func parsePlaceFilters(raw any) (map[string]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	filters, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("filters must be an object")
+	}
+
+	tags := make(map[string]string, len(filters))
+	for name, value := range filters {
+		switch name {
+		case "cuisine":
+			cuisines, err := filterTokens(name, value)
+			if err != nil {
+				return nil, err
+			}
+			// cuisine holds a semicolon-separated list, e.g. pizza;italian
+			tags["cuisine"] = fmt.Sprintf("~(^|;) *(%s) *(;|$)", strings.Join(cuisines, "|"))
+		case "diet":
+			diets, err := filterTokens(name, value)
+			if err != nil {
+				return nil, err
+			}
+			for _, diet := range diets {
+				tags["diet:"+diet] = "~^(yes|only)$"
+			}
+		case "wheelchair":
+			level, ok := value.(string)
+			if b, isBool := value.(bool); isBool {
+				level, ok = map[bool]string{true: "yes", false: "no"}[b], true
+			}
+			pattern, known := wheelchairValues[strings.ToLower(level)]
+			if !ok || !known {
+				return nil, fmt.Errorf("filters.wheelchair must be yes, limited or no")
+			}
+			tags["wheelchair"] = pattern
+		case "outdoor_seating", "takeaway":
+			want, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("filters.%s must be true or false", name)
+			}
+			switch {
+			case !want:
+				tags[name] = "no"
+			case name == "takeaway":
+				tags[name] = "~^(yes|only)$"
+			default:
+				tags[name] = "yes"
+			}
+		case "brand":
+			brand, ok := value.(string)
+			if brand = strings.TrimSpace(brand); !ok || brand == "" {
+				return nil, fmt.Errorf("filters.brand must be a brand name")
+			}
+			tags["brand"] = "~^" + regexp.QuoteMeta(brand) + "$,i"
+		default:
+			return nil, fmt.Errorf("unknown filter %q (filters: cuisine, diet, wheelchair, outdoor_seating, takeaway, brand)", name)
+		}
+	}
+	return tags, nil
+}
+
+// filterTokens reads a filter given as a string or an array of strings,
+// normalizing "Gluten Free" to gluten_free
+func filterTokens(name string, value any) ([]string, error) {
+	var raw []any
+	switch v := value.(type) {
+	case string:
+		raw = []any{v}
+	case []any:
+		raw = v
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("filters.%s must be a string or an array of strings", name)
+	}
+
+	tokens := make([]string, 0, len(raw))
+	for _, item := range raw {
+		s, _ := item.(string)
+		token := strings.Join(strings.Fields(strings.ToLower(s)), "_")
+		if !filterTokenPattern.MatchString(token) {
+			return nil, fmt.Errorf("filters.%s has an invalid value %q", name, s)
+		}
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens) // equal filters build equal, cacheable queries
+	return tokens, nil
+}
+
+// buildNearbyPlacesQuery builds an Overpass query for elements within
+// radius of a point that match any of the category tags and all of the
+// filter tags. Ways and relations are returned with their center.
+func buildNearbyPlacesQuery(lat, lon, radius float64, categoryTags map[string][]string, filterTags map[string]string) (string, error) {
+	center := []geo.Location{{Latitude: lat, Longitude: lon}}
+	builder := queries.NewOverpassBuilder().Begin()
+
+	keys := make([]string, 0, len(categoryTags))
+	for key := range categoryTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tags := maps.Clone(filterTags)
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[key] = categoryValuesFilter(categoryTags[key])
+		builder.WithNodeAround(radius, center, tags).
+			WithWayAround(radius, center, tags).
+			WithRelationAround(radius, center, tags)
+	}
+	builder.End().WithOutput("center")
+
+	if err := builder.Err(); err != nil {
+		return "", err
+	}
+	return builder.Build(), nil
+}
+
+// categoryValuesFilter matches any of a category's values for its key, or
+// any value when the category lists "*"
+func categoryValuesFilter(values []string) string {
+	if len(values) == 0 || values[0] == "*" {
+		return ""
+	}
+	if len(values) == 1 {
+		return values[0]
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	return "~^(" + strings.Join(quoted, "|") + ")$"
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePlaceFilters(t *testing.T) {
+	tags, err := parsePlaceFilters(map[string]any{
+		"cuisine":         []any{"Pizza", "italian"},
+		"diet":            "vegan",
+		"wheelchair":      true,
+		"outdoor_seating": false,
+		"takeaway":        true,
+		"brand":           "Pizza Hut",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"cuisine":         "~(^|;) *(italian|pizza) *(;|$)",
+		"diet:vegan":      "~^(yes|only)$",
+		"wheelchair":      "~^(yes|designated)$",
+		"outdoor_seating": "no",
+		"takeaway":        "~^(yes|only)$",
+		"brand":           "~^Pizza Hut$,i",
+	}
+	for key, value := range want {
+		if tags[key] != value {
+			t.Errorf("tags[%s] = %q, want %q", key, tags[key], value)
+		}
+	}
+	if len(tags) != len(want) {
+		t.Errorf("unexpected tags %v", tags)
+	}
+
+	if tags, err := parsePlaceFilters(nil); err != nil || tags != nil {
+		t.Errorf("expected no filters, got %v, %v", tags, err)
+	}
+
+	for _, filters := range []any{
+		"vegan",
+		map[string]any{"parking": true},
+		map[string]any{"cuisine": []any{}},
+		map[string]any{"cuisine": "pizza\"]"},
+		map[string]any{"wheelchair": "maybe"},
+		map[string]any{"takeaway": "yes"},
+		map[string]any{"brand": ""},
+	} {
+		if _, err := parsePlaceFilters(filters); err == nil {
+			t.Errorf("expected an error for %v", filters)
+		}
+	}
+}
+
+func TestBuildNearbyPlacesQuery(t *testing.T) {
+	query, err := buildNearbyPlacesQuery(51.5, -0.12, 500,
+		map[string][]string{"amenity": {"restaurant", "fast_food"}, "shop": {"*"}},
+		map[string]string{"diet:vegan": "~^(yes|only)$"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Each category key is a separate union member, so places match any of
+	// them, and every member carries the filters
+	for _, part := range []string{
+		`node(around:500.0,51.500000,-0.120000)["amenity"~"^(restaurant|fast_food)$"]["diet:vegan"~"^(yes|only)$"];`,
+		`way(around:500.0,51.500000,-0.120000)["amenity"~"^(restaurant|fast_food)$"]["diet:vegan"~"^(yes|only)$"];`,
+		`relation(around:500.0,51.500000,-0.120000)["diet:vegan"~"^(yes|only)$"][shop];`,
+		`;out center;`,
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("query %s is missing %s", query, part)
+		}
+	}
+	if strings.Contains(query, "[amenity=restaurant][amenity=fast_food]") {
+		t.Errorf("category values must be alternatives: %s", query)
+	}
+}
@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// commonsUploadBaseURL is where Wikimedia Commons serves original files
+	commonsUploadBaseURL = "https://upload.wikimedia.org/wikipedia/commons"
+	// commonsPagePrefix is the page URL prefix used by Commons file links
+	commonsPagePrefix = "https://commons.wikimedia.org/wiki/"
+)
+
+// thumbnailWidths are the thumbnail variants generated for Commons images
+var thumbnailWidths = []int{320, 800}
+
+// PlaceImage is a picture of a place resolved from OSM image tags
+type PlaceImage struct {
+	URL        string           `json:"url"`                  // direct URL to the full image
+	Thumbnails []ImageThumbnail `json:"thumbnails,omitempty"` // scaled variants, Commons only
+	Source     string           `json:"source"`               // wikimedia_commons or image
+	PageURL    string           `json:"page_url,omitempty"`   // description page with licence details
+}
+
+// ImageThumbnail is a scaled variant of a PlaceImage
+type ImageThumbnail struct {
+	Width int    `json:"width"`
+	URL   string `json:"url"`
+}
+
+// resolvePlaceImages resolves the image and wikimedia_commons tag values of
+// an OSM element into direct image URLs. Commons categories and unparseable
+// values are ignored, and duplicate files are reported once.
+func resolvePlaceImages(imageTag, commonsTag string) []PlaceImage {
+	images := make([]PlaceImage, 0)
+	seen := make(map[string]bool)
+
+	add := func(img PlaceImage, ok bool) {
+		if ok && !seen[img.URL] {
+			seen[img.URL] = true
+			images = append(images, img)
+		}
+	}
+
+	for _, value := range splitTagValues(commonsTag) {
+		add(commonsImage(value, "wikimedia_commons"))
+	}
+
+	for _, value := range splitTagValues(imageTag) {
+		if strings.HasPrefix(value, commonsPagePrefix) || strings.HasPrefix(strings.ToLower(value), "file:") {
+			add(commonsImage(value, "image"))
+			continue
+		}
+		if u, err := url.Parse(value); err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" {
+			add(PlaceImage{URL: value, Source: "image"}, true)
+		}
+	}
+
+	if len(images) == 0 {
+		return nil
+	}
+	return images
+}
+
+// commonsImage builds upload URLs for a Commons file reference such as
+// "File:Golden Gate Bridge.jpg" or its commons.wikimedia.org page URL.
+func commonsImage(value, source string) (PlaceImage, bool) {
+	value = strings.TrimPrefix(value, commonsPagePrefix)
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+
+	if len(value) < 5 || !strings.EqualFold(value[:5], "file:") {
+		return PlaceImage{}, false
+	}
+
+	// Commons file names use underscores and an upper-case first letter
+	name := strings.ReplaceAll(strings.TrimSpace(value[5:]), " ", "_")
+	if name == "" {
+		return PlaceImage{}, false
+	}
+	name = strings.ToUpper(name[:1]) + name[1:]
+
+	sum := md5.Sum([]byte(name))
+	hash := hex.EncodeToString(sum[:])
+	escaped := url.PathEscape(name)
+	hashPath := fmt.Sprintf("%s/%s", hash[:1], hash[:2])
+
+	img := PlaceImage{
+		URL:     fmt.Sprintf("%s/%s/%s", commonsUploadBaseURL, hashPath, escaped),
+		Source:  source,
+		PageURL: commonsPagePrefix + "File:" + escaped,
+	}
+
+	for _, width := range thumbnailWidths {
+		thumb := fmt.Sprintf("%s/thumb/%s/%s/%dpx-%s", commonsUploadBaseURL, hashPath, escaped, width, escaped)
+		// SVG thumbnails are rendered to PNG
+		if strings.HasSuffix(strings.ToLower(name), ".svg") {
+			thumb += ".png"
+		}
+		img.Thumbnails = append(img.Thumbnails, ImageThumbnail{Width: width, URL: thumb})
+	}
+
+	return img, true
+}
+
+// splitTagValues splits a semicolon-separated OSM tag value
+func splitTagValues(value string) []string {
+	values := make([]string, 0)
+	for _, part := range strings.Split(value, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
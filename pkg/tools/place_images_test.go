@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestResolvePlaceImages(t *testing.T) {
+	tests := []struct {
+		name       string
+		image      string
+		commons    string
+		wantURLs   []string
+		wantThumbs int
+	}{
+		{
+			name:       "Commons file",
+			commons:    "File:Example.jpg",
+			wantURLs:   []string{"https://upload.wikimedia.org/wikipedia/commons/a/a9/Example.jpg"},
+			wantThumbs: 2,
+		},
+		{
+			name:       "Commons page URL in image tag",
+			image:      "https://commons.wikimedia.org/wiki/File:Example.jpg",
+			wantURLs:   []string{"https://upload.wikimedia.org/wikipedia/commons/a/a9/Example.jpg"},
+			wantThumbs: 2,
+		},
+		{
+			name:     "Plain image URL",
+			image:    "https://example.com/photo.jpg",
+			wantURLs: []string{"https://example.com/photo.jpg"},
+		},
+		{
+			name:       "Duplicates and categories",
+			image:      "File:Example.jpg",
+			commons:    "Category:Examples;File:Example.jpg",
+			wantURLs:   []string{"https://upload.wikimedia.org/wikipedia/commons/a/a9/Example.jpg"},
+			wantThumbs: 2,
+		},
+		{
+			name:  "Unusable values",
+			image: "not a url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images := resolvePlaceImages(tt.image, tt.commons)
+			if len(images) != len(tt.wantURLs) {
+				t.Fatalf("expected %d images, got %d: %+v", len(tt.wantURLs), len(images), images)
+			}
+			for i, img := range images {
+				if img.URL != tt.wantURLs[i] {
+					t.Errorf("image %d: expected URL %s, got %s", i, tt.wantURLs[i], img.URL)
+				}
+				if len(img.Thumbnails) != tt.wantThumbs {
+					t.Errorf("image %d: expected %d thumbnails, got %d", i, tt.wantThumbs, len(img.Thumbnails))
+				}
+			}
+		})
+	}
+}
+
+func TestCommonsImageThumbnailURL(t *testing.T) {
+	img, ok := commonsImage("File:example map.svg", "wikimedia_commons")
+	if !ok {
+		t.Fatal("expected Commons file to resolve")
+	}
+	want := "https://upload.wikimedia.org/wikipedia/commons/thumb/"
+	if len(img.Thumbnails) == 0 || img.Thumbnails[0].URL[:len(want)] != want {
+		t.Fatalf("unexpected thumbnails: %+v", img.Thumbnails)
+	}
+	if got := img.Thumbnails[0].URL; got[len(got)-4:] != ".png" {
+		t.Errorf("expected SVG thumbnail to be rendered as PNG, got %s", got)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -18,10 +17,15 @@ import (
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
+// includeTagsDescription describes the include_tags parameter of tools
+// returning places
+const includeTagsDescription = "Attach the full raw OSM tag map to each place, e.g. for brand, operator or ref"
+
 // FindNearbyPlacesTool returns a tool definition for finding nearby places
 func FindNearbyPlacesTool() mcp.Tool {
+	limits := searchLimitsFor("find_nearby_places")
 	return mcp.NewTool("find_nearby_places",
-		mcp.WithDescription("Find points of interest near a specific location"),
+		mcp.WithDescription("Find points of interest near a specific location. "+orderingDescription),
 		mcp.WithNumber("latitude",
 			mcp.Required(),
 			mcp.Description("The latitude coordinate of the center point"),
@@ -31,16 +35,52 @@ func FindNearbyPlacesTool() mcp.Tool {
 			mcp.Description("The longitude coordinate of the center point"),
 		),
 		mcp.WithNumber("radius",
-			mcp.Description("Search radius in meters (max 50000)"),
-			mcp.DefaultNumber(1000),
+			mcp.Description(limits.radiusDescription()),
+			mcp.DefaultNumber(limits.DefaultRadius),
 		),
 		mcp.WithString("category",
 			mcp.Description("Optional category filter (e.g., restaurant, hotel, park)"),
 			mcp.DefaultString(""),
 		),
+		mcp.WithObject("filters",
+			mcp.Description(placeFiltersDescription),
+		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results to return"),
-			mcp.DefaultNumber(10),
+			mcp.Description(limits.limitDescription()),
+			mcp.DefaultNumber(float64(limits.DefaultLimit)),
+		),
+		mcp.WithBoolean("include_images",
+			mcp.Description("Resolve image and wikimedia_commons tags into direct image and thumbnail URLs"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_tags",
+			mcp.Description(includeTagsDescription),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("min_remaining_open_minutes",
+			mcp.Description("Only return places that are open now and stay open for at least this many minutes (e.g. travel time plus visit time)"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone used to evaluate opening hours (e.g. Europe/London); defaults to the server's local time zone"),
+		),
+		mcp.WithString("as_of",
+			mcp.Description(asOfDescription+"; opening hours and min_remaining_open_minutes are evaluated at this time"),
+		),
+		mcp.WithBoolean("open_now",
+			mcp.Description(openNowDescription),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("open_at",
+			mcp.Description(openAtDescription),
+		),
+		mcp.WithString("level",
+			mcp.Description(levelDescription),
+		),
+		mcp.WithString("cursor",
+			mcp.Description(cursorDescription),
+		),
+		mcp.WithNumber("bands",
+			mcp.Description(bandsDescription),
 		),
 	)
 }
@@ -57,6 +97,49 @@ func HandleFindNearbyPlaces(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 
 	// Parse additional parameters
 	category := mcp.ParseString(req, "category", "")
+	includeImages := mcp.ParseBoolean(req, "include_images", false)
+	includeTags := mcp.ParseBoolean(req, "include_tags", false)
+	minRemainingOpen := int(mcp.ParseFloat64(req, "min_remaining_open_minutes", 0))
+	if minRemainingOpen < 0 {
+		return core.NewError(core.ErrInvalidParameter, "min_remaining_open_minutes must not be negative").ToMCPResult(), nil
+	}
+
+	now, openOnly, err := parseOpenFilter(req)
+	if err != nil {
+		logger.Error("invalid opening time", "error", err)
+		return core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance("Use an IANA time zone name such as America/New_York and RFC 3339 times").
+			ToMCPResult(), nil
+	}
+
+	level, filterLevel, err := parseLevelFilter(mcp.ParseString(req, "level", ""))
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+
+	filterTags, err := parsePlaceFilters(req.GetArguments()["filters"])
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance(`Example: "filters": {"cuisine": "pizza", "wheelchair": "yes"}`).
+			ToMCPResult(), nil
+	}
+
+	opts, errResult := parseListOptions(req, limit)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	bandWidth := mcp.ParseFloat64(req, "bands", 0)
+	if bandWidth != 0 {
+		if err := validateBandWidth(bandWidth, radius); err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+		}
+		if opts.after != nil || opts.sortBy != nil {
+			return core.NewError(core.ErrInvalidParameter, "cursor and sort_by cannot be combined with bands").
+				WithGuidance("Banded results are not paged; raise limit for more results per band").
+				ToMCPResult(), nil
+		}
+	}
 
 	if category == "" {
 		logger.Error("missing category parameter")
@@ -69,146 +152,121 @@ func HandleFindNearbyPlaces(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 		), nil
 	}
 
-	// Map generic categories to OSM tags
+	// Map generic categories to OSM tags, each key with any of its values,
+	// and add the secondary filters every place must match
 	osmTags := mapCategoryToOSMTags(category)
-
-	// Build Overpass query using the fluent builder
-	queryBuilder := core.NewOverpassBuilder().
-		WithTimeout(25).
-		WithCenter(lat, lon, radius)
-
-	// Add tag filters if category specified
-	if len(osmTags) > 0 {
-		for key, values := range osmTags {
-			for _, value := range values {
-				queryBuilder.WithTag(key, value)
-			}
-		}
-	}
-
-	// Execute the query using core HTTP utilities
-	overpassQuery := queryBuilder.Build()
-
-	// Create the request
-	reqURL, err := url.Parse(osm.OverpassBaseURL)
+	overpassQuery, err := buildNearbyPlacesQuery(lat, lon, radius, osmTags, filterTags)
 	if err != nil {
-		logger.Error("failed to parse URL", "error", err)
-		return core.NewError("INTERNAL_ERROR", "Internal server error").ToMCPResult(), nil
+		logger.Error("failed to build query", "error", err)
+		return core.NewError(core.ErrInvalidParameter, "Invalid category or filters: "+err.Error()).ToMCPResult(), nil
 	}
 
-	// Create a request factory for retry support
-	factory := func() (*http.Request, error) {
-		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, reqURL.String(),
-			strings.NewReader("data="+url.QueryEscape(overpassQuery)))
-		if err != nil {
-			return nil, err
-		}
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		req.Header.Set("User-Agent", osm.UserAgent)
-		return req, nil
-	}
-
-	// Execute request with retry
-	client := osm.GetClient(ctx)
-	resp, err := core.WithRetryFactory(ctx, factory, client, core.DefaultRetryOptions)
-
+	elements, err := executeOverpassQuery(ctx, overpassQuery)
 	if err != nil {
-		logger.Error("failed to execute request", "error", err)
+		logger.Error("failed to execute query", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
 		return core.ServiceError("Overpass", http.StatusServiceUnavailable,
 			"Failed to communicate with places service").ToMCPResult(), nil
 	}
-	defer resp.Body.Close()
-
-	// Process response
-	if resp.StatusCode != http.StatusOK {
-		logger.Error("places service returned error", "status", resp.StatusCode)
-		return core.ServiceError("Overpass", resp.StatusCode,
-			fmt.Sprintf("Places service error: %d", resp.StatusCode)).ToMCPResult(), nil
-	}
-
-	// Parse response
-	var overpassResp struct {
-		Elements []struct {
-			ID   int     `json:"id"`
-			Type string  `json:"type"`
-			Lat  float64 `json:"lat"`
-			Lon  float64 `json:"lon"`
-			Tags struct {
-				Name     string `json:"name"`
-				Amenity  string `json:"amenity"`
-				Shop     string `json:"shop"`
-				Tourism  string `json:"tourism"`
-				Leisure  string `json:"leisure"`
-				Highway  string `json:"highway"`
-				Building string `json:"building"`
-			} `json:"tags"`
-		} `json:"elements"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
-		logger.Error("failed to decode response", "error", err)
-		return core.NewError("PARSE_ERROR", "Failed to parse places response").ToMCPResult(), nil
-	}
 
 	// Convert to Place objects and calculate distances
 	places := make([]Place, 0)
-	for _, element := range overpassResp.Elements {
+	for _, element := range elements {
 		// Skip elements without a name
-		if element.Tags.Name == "" {
+		name := element.Tags["name"]
+		if name == "" {
+			continue
+		}
+
+		// Skip places on other floors when a level was requested
+		if filterLevel && !onLevel(element.Tags, level) {
 			continue
 		}
 
+		// Ways and relations are located at their center
+		elementLat, elementLon := element.Lat, element.Lon
+		if element.Center != nil {
+			elementLat, elementLon = element.Center.Lat, element.Center.Lon
+		}
+
 		// Calculate distance
 		distance := osm.HaversineDistance(
 			lat, lon,
-			element.Lat, element.Lon,
+			elementLat, elementLon,
 		)
 
 		// Determine place category
 		categories := []string{}
-		if element.Tags.Amenity != "" {
-			categories = append(categories, element.Tags.Amenity)
+		if amenity := element.Tags["amenity"]; amenity != "" {
+			categories = append(categories, amenity)
 		}
-		if element.Tags.Shop != "" {
-			categories = append(categories, "shop:"+element.Tags.Shop)
+		if shop := element.Tags["shop"]; shop != "" {
+			categories = append(categories, "shop:"+shop)
 		}
-		if element.Tags.Tourism != "" {
-			categories = append(categories, "tourism:"+element.Tags.Tourism)
+		if tourism := element.Tags["tourism"]; tourism != "" {
+			categories = append(categories, "tourism:"+tourism)
 		}
-		if element.Tags.Leisure != "" {
-			categories = append(categories, "leisure:"+element.Tags.Leisure)
+		if leisure := element.Tags["leisure"]; leisure != "" {
+			categories = append(categories, "leisure:"+leisure)
 		}
 
 		// Create place object
 		place := Place{
 			ID:   strconv.Itoa(element.ID),
-			Name: element.Tags.Name,
+			Name: name,
 			Location: Location{
-				Latitude:  element.Lat,
-				Longitude: element.Lon,
+				Latitude:  elementLat,
+				Longitude: elementLon,
 			},
 			Categories: categories,
 			Distance:   distance,
+			Contact:    parseContact(element.Tags),
+			Level:      placeLevel(element.Tags),
 		}
 
-		places = append(places, place)
-	}
+		if includeImages {
+			place.Images = resolvePlaceImages(element.Tags["image"], element.Tags["wikimedia_commons"])
+		}
+		if includeTags {
+			place.Tags = element.Tags
+		}
 
-	// Sort places by distance (closest first)
-	sort.Slice(places, func(i, j int) bool {
-		return places[i].Distance < places[j].Distance
-	})
+		// Annotate opening status and apply the open and remaining-open filters
+		status := evaluateOpeningHours(element.Tags["opening_hours"], now)
+		if openOnly && !status.remainsOpenFor(0) {
+			continue
+		}
+		if minRemainingOpen > 0 && !status.remainsOpenFor(minRemainingOpen) {
+			continue
+		}
+		status.annotate(&place, element.Tags["opening_hours"])
 
-	// Limit results
-	if len(places) > limit {
-		places = places[:limit]
+		places = append(places, place)
 	}
 
-	// Create output
-	output := struct {
-		Places []Place `json:"places"`
-	}{
-		Places: places,
+	// Create output, grouped into distance bands or as the requested page
+	var output interface{}
+	if bandWidth != 0 {
+		places = filterList(places, opts)
+		sortPlacesByDistance(places)
+		output = struct {
+			Total int            `json:"total"`
+			Bands []DistanceBand `json:"bands"`
+		}{
+			Total: len(places),
+			Bands: groupByDistance(places, bandWidth, radius, limit),
+		}
+	} else {
+		page, _, nextCursor := processList(places, placeSortKey, opts)
+		output = struct {
+			Places     []Place `json:"places"`
+			NextCursor string  `json:"next_cursor,omitempty"`
+		}{
+			Places:     page,
+			NextCursor: nextCursor,
+		}
 	}
 
 	// Return result
@@ -221,111 +279,60 @@ func HandleFindNearbyPlaces(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
-// mapCategoryToOSMTags maps generic category names to OSM tag combinations
+// mapCategoryToOSMTags maps generic category names to OSM tag combinations.
+// Names are looked up in the category table, tolerating synonyms, plurals
+// and typos; other names are tried as the value of common tag keys.
 func mapCategoryToOSMTags(category string) map[string][]string {
+	if mapping, ok := lookupCategory(category); ok {
+		if mapping.names[0] != normalizeCategory(category) {
+			slog.Debug("category matched", "category", category, "as", mapping.names[0])
+		}
+		return mapping.tags
+	}
+
 	// Convert to lowercase for case-insensitive matching
 	category = strings.ToLower(category)
 
-	// Common category mappings
-	switch category {
-	case "restaurant", "restaurants", "dining":
-		return map[string][]string{
-			"amenity": {"restaurant", "cafe", "fast_food", "bar", "pub", "food_court"},
-		}
-	case "park", "parks", "recreation":
-		return map[string][]string{
-			"leisure": {"park", "garden", "playground", "nature_reserve"},
-			"landuse": {"recreation_ground", "park", "greenfield"},
-			"natural": {"wood", "grassland", "meadow"},
-		}
-	case "hotel", "hotels", "lodging", "accommodation":
-		return map[string][]string{
-			"tourism": {"hotel", "hostel", "guest_house", "motel", "apartment", "resort"},
-		}
-	case "school", "schools", "education":
-		return map[string][]string{
-			"amenity": {"school", "kindergarten", "university", "college", "language_school", "music_school", "driving_school"},
-		}
-	case "bank", "banks", "atm", "atms", "finance":
-		return map[string][]string{
-			"amenity": {"bank", "atm", "bureau_de_change", "money_transfer"},
-		}
-	case "shop", "shops", "store", "stores", "shopping":
-		return map[string][]string{
-			"shop": {"*"}, // Match any shop type
-		}
-	case "cafe", "cafes", "coffee", "tea":
-		return map[string][]string{
-			"amenity": {"cafe", "ice_cream"},
-			"shop":    {"coffee", "tea"},
-		}
-	case "hospital", "hospitals", "medical", "healthcare":
+	// Split the category by spaces to handle multi-word categories
+	parts := strings.Fields(category)
+	if len(parts) > 1 {
+		// Try matching a more specific multi-word category
+		compound := strings.Join(parts, "_")
 		return map[string][]string{
-			"amenity": {"hospital", "clinic", "doctors", "dentist", "pharmacy", "healthcare"},
-		}
-	case "pharmacy", "pharmacies", "drugstore", "chemist":
-		return map[string][]string{
-			"amenity": {"pharmacy"},
-			"shop":    {"chemist", "drugstore", "medical_supply"},
-		}
-	case "supermarket", "grocery", "market", "food_shop":
-		return map[string][]string{
-			"shop": {"supermarket", "convenience", "grocery", "greengrocer", "butcher", "bakery", "deli"},
-		}
-	case "museum", "museums", "gallery", "galleries", "art":
-		return map[string][]string{
-			"tourism": {"museum", "gallery", "artwork"},
-			"amenity": {"arts_centre"},
-		}
-	case "attraction", "attractions", "tourist", "tourism", "sightseeing":
-		return map[string][]string{
-			"tourism": {"attraction", "viewpoint", "information", "museum", "gallery", "theme_park", "zoo"},
-		}
-	case "transport", "transportation", "transit", "bus", "train", "station":
-		return map[string][]string{
-			"public_transport": {"station", "stop_position", "platform"},
-			"railway":          {"station", "halt", "tram_stop", "subway_entrance"},
-			"amenity":          {"bus_station", "ferry_terminal", "taxi"},
-			"highway":          {"bus_stop"},
-		}
-	default:
-		// Split the category by spaces to handle multi-word categories
-		parts := strings.Fields(category)
-		if len(parts) > 1 {
-			// Try matching a more specific multi-word category
-			compound := strings.Join(parts, "_")
-			return map[string][]string{
-				"amenity":  {compound, category},
-				"shop":     {compound, category},
-				"tourism":  {compound, category},
-				"leisure":  {compound, category},
-				"natural":  {compound, category},
-				"historic": {compound, category},
-			}
+			"amenity":  {compound, category},
+			"shop":     {compound, category},
+			"tourism":  {compound, category},
+			"leisure":  {compound, category},
+			"natural":  {compound, category},
+			"historic": {compound, category},
 		}
+	}
 
-		// For unknown categories, try multiple tag combinations
-		return map[string][]string{
-			"amenity":  {category},
-			"shop":     {category},
-			"tourism":  {category},
-			"leisure":  {category},
-			"natural":  {category},
-			"historic": {category},
-		}
+	// For unknown categories, try multiple tag combinations
+	return map[string][]string{
+		"amenity":  {category},
+		"shop":     {category},
+		"tourism":  {category},
+		"leisure":  {category},
+		"natural":  {category},
+		"historic": {category},
 	}
 }
 
-// sortPlacesByDistance sorts places by distance (closest first)
+// sortPlacesByDistance sorts places by distance (closest first), breaking
+// ties by ID
 func sortPlacesByDistance(places []Place) {
-	// Replace bubble sort with sort.Slice for better performance
-	sort.Slice(places, func(i, j int) bool {
-		return places[i].Distance < places[j].Distance
-	})
+	sortByDistanceThenID(places, placeSortKey)
+}
+
+// placeSortKey returns the distance and ID places are ordered by
+func placeSortKey(p Place) (float64, string) {
+	return p.Distance, p.ID
 }
 
 // SearchCategoryTool returns a tool definition for searching places by category
 func SearchCategoryTool() mcp.Tool {
+	limits := searchLimitsFor("search_category")
 	return mcp.NewTool("search_category",
 		mcp.WithDescription("Find places of a specific category within a bounding box"),
 		mcp.WithString("category",
@@ -349,8 +356,25 @@ func SearchCategoryTool() mcp.Tool {
 			mcp.Description("Western boundary longitude"),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results to return"),
-			mcp.DefaultNumber(20),
+			mcp.Description(limits.limitDescription()),
+			mcp.DefaultNumber(float64(limits.DefaultLimit)),
+		),
+		mcp.WithString("level",
+			mcp.Description(levelDescription),
+		),
+		mcp.WithBoolean("include_tags",
+			mcp.Description(includeTagsDescription),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("open_now",
+			mcp.Description(openNowDescription),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("open_at",
+			mcp.Description(openAtDescription),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone used to evaluate opening hours (e.g. Europe/London); defaults to the server's local time zone"),
 		),
 	)
 }
@@ -358,6 +382,7 @@ func SearchCategoryTool() mcp.Tool {
 // HandleSearchCategory implements category search functionality
 func HandleSearchCategory(ctx context.Context, rawInput mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logger := slog.Default().With("tool", "search_category")
+	limits := searchLimitsFor("search_category")
 
 	// Parse input parameters
 	category := mcp.ParseString(rawInput, "category", "")
@@ -365,7 +390,16 @@ func HandleSearchCategory(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 	southLat := mcp.ParseFloat64(rawInput, "south_lat", 0)
 	eastLon := mcp.ParseFloat64(rawInput, "east_lon", 0)
 	westLon := mcp.ParseFloat64(rawInput, "west_lon", 0)
-	limit := int(mcp.ParseFloat64(rawInput, "limit", 20))
+	limit := int(mcp.ParseFloat64(rawInput, "limit", float64(limits.DefaultLimit)))
+	includeTags := mcp.ParseBoolean(rawInput, "include_tags", false)
+	level, filterLevel, err := parseLevelFilter(mcp.ParseString(rawInput, "level", ""))
+	if err != nil {
+		return ErrorResponse(err.Error()), nil
+	}
+	now, openOnly, err := parseOpenFilter(rawInput)
+	if err != nil {
+		return ErrorResponse(err.Error()), nil
+	}
 
 	// Basic validation
 	if category == "" {
@@ -374,18 +408,13 @@ func HandleSearchCategory(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 	if northLat < southLat {
 		return ErrorResponse("North latitude must be greater than south latitude"), nil
 	}
-	if northLat < -90 || northLat > 90 || southLat < -90 || southLat > 90 {
-		return ErrorResponse("Latitude must be between -90 and 90"), nil
-	}
-	if eastLon < -180 || eastLon > 180 || westLon < -180 || westLon > 180 {
-		return ErrorResponse("Longitude must be between -180 and 180"), nil
+	if err := core.ValidateCoords(southLat, westLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
-	if limit <= 0 {
-		limit = 20 // Default limit
-	}
-	if limit > 100 {
-		limit = 100 // Max limit
+	if err := core.ValidateCoords(northLat, eastLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
+	limit = limits.clampLimit(limit)
 
 	// Map generic categories to OSM tags
 	osmTags := mapCategoryToOSMTags(category)
@@ -490,9 +519,9 @@ func HandleSearchCategory(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 		} `json:"elements"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
+	if err := core.DecodeUpstream("Overpass", resp.Body, &overpassResp, "elements"); err != nil {
 		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse places response"), nil
+		return err.ToMCPResult(), nil
 	}
 
 	// Log response size
@@ -520,6 +549,11 @@ func HandleSearchCategory(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 			continue
 		}
 
+		// Skip places on other floors when a level was requested
+		if filterLevel && !onLevel(element.Tags, level) {
+			continue
+		}
+
 		// Skip elements without a name (unless we want to include unnamed places)
 		name := element.Tags["name"]
 		if name == "" {
@@ -562,7 +596,18 @@ func HandleSearchCategory(ctx context.Context, rawInput mcp.CallToolRequest) (*m
 				Longitude: lon,
 			},
 			Categories: categories,
+			Contact:    parseContact(element.Tags),
+			Level:      placeLevel(element.Tags),
+		}
+		if includeTags {
+			place.Tags = element.Tags
+		}
+
+		status := evaluateOpeningHours(element.Tags["opening_hours"], now)
+		if openOnly && !status.remainsOpenFor(0) {
+			continue
 		}
+		status.annotate(&place, element.Tags["opening_hours"])
 
 		places = append(places, place)
 	}
@@ -135,7 +135,7 @@ func HandlePolylineEncode(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 		}
 
 		// Validate coordinates range
-		if err := osm.ValidateCoords(p.Latitude, p.Longitude); err != nil {
+		if err := geo.ValidateCoords(p.Latitude, p.Longitude); err != nil {
 			logger.Error("invalid coordinates", "error", err, "index", i)
 			return ErrorResponse(fmt.Sprintf("Invalid coordinates at index %d: %s", i, err)), nil
 		}
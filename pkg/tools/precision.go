@@ -0,0 +1,283 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+const (
+	// DefaultCoordinatePrecision is the number of decimals output
+	// coordinates are rounded to, about 0.1 m, well within the accuracy of
+	// OSM data
+	DefaultCoordinatePrecision = 6
+	// maxCoordinatePrecision is the most decimals a call can ask for, past
+	// which float64 coordinates carry no more information
+	maxCoordinatePrecision = 15
+)
+
+// coordinatePrecision is the server-wide number of decimals; negative keeps
+// full precision
+var coordinatePrecision = DefaultCoordinatePrecision
+
+// SetCoordinatePrecision sets the number of decimals output coordinates are
+// rounded to when a call does not pass coordinate_precision; a negative
+// value keeps full precision
+func SetCoordinatePrecision(decimals int) {
+	coordinatePrecision = min(decimals, maxCoordinatePrecision)
+}
+
+// coordinateKeys are the object fields holding a single latitude or
+// longitude
+var coordinateKeys = map[string]bool{
+	"latitude": true, "longitude": true,
+	"lat": true, "lon": true, "lng": true,
+	"minLat": true, "minLon": true, "maxLat": true, "maxLon": true,
+	"centerLat": true, "centerLon": true, "center_lat": true, "center_lon": true,
+	"northLat": true, "southLat": true, "eastLon": true, "westLon": true,
+	"north_lat": true, "south_lat": true, "east_lon": true, "west_lon": true,
+}
+
+// coordinateArrayKeys are the object fields holding GeoJSON positions,
+// every number in which is a coordinate
+var coordinateArrayKeys = map[string]bool{
+	"coordinates": true,
+	"bbox":        true,
+}
+
+// coordinateFreeTools are the tools whose results hold no coordinates, or
+// only encoded ones such as polylines, images and GPX, so they take no
+// coordinate_precision
+var coordinateFreeTools = map[string]bool{
+	"enrich_emissions":  true,
+	"export_gpx":        true,
+	"geo_area":          true,
+	"geo_distance":      true,
+	"geohash_neighbors": true,
+	"get_map_image":     true,
+	"get_version":       true,
+	"h3_neighbors":      true,
+	"polyline_encode":   true,
+	"rate_limit_status": true,
+	"route_fetch":       true,
+	"tile_cache":        true,
+	"tiles_for_bbox":    true,
+	"unwatch_area":      true,
+}
+
+// withCoordinatePrecision adds the coordinate_precision parameter to the
+// tools with coordinates in their results and wraps their handlers to round
+// them. It runs before field selection and transforms, which may rename
+// coordinate fields.
+func withCoordinatePrecision(defs []ToolDefinition) []ToolDefinition {
+	for i, def := range defs {
+		if coordinateFreeTools[def.Name] {
+			continue
+		}
+		mcp.WithNumber("coordinate_precision",
+			mcp.Description(fmt.Sprintf("Decimals output coordinates are rounded to, 0-%d (default %d, about 0.1 m)", maxCoordinatePrecision, DefaultCoordinatePrecision)),
+			mcp.Min(0),
+			mcp.Max(maxCoordinatePrecision),
+		)(&defs[i].Tool)
+		defs[i].Handler = coordinatePrecisionHandler(def.Handler)
+	}
+	return defs
+}
+
+// coordinatePrecisionHandler wraps a handler to round the coordinates in its
+// JSON result to the requested or server-wide precision
+func coordinatePrecisionHandler(handler func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		decimals, err := parseCoordinatePrecision(req)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance(fmt.Sprintf("Pass coordinate_precision as a whole number of decimals from 0 to %d", maxCoordinatePrecision)).
+				ToMCPResult(), nil
+		}
+
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError || decimals < 0 {
+			return result, err
+		}
+		return roundResultCoordinates(result, decimals), nil
+	}
+}
+
+// parseCoordinatePrecision reads the optional coordinate_precision argument,
+// falling back to the server-wide precision
+func parseCoordinatePrecision(req mcp.CallToolRequest) (int, error) {
+	args, ok := req.Params.Arguments.(map[string]any)
+	if !ok || args["coordinate_precision"] == nil {
+		return coordinatePrecision, nil
+	}
+
+	var decimals float64
+	switch v := args["coordinate_precision"].(type) {
+	case float64:
+		decimals = v
+	case int:
+		decimals = float64(v)
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("coordinate_precision must be a number")
+		}
+		decimals = float64(parsed)
+	default:
+		return 0, fmt.Errorf("coordinate_precision must be a number")
+	}
+	if decimals != math.Trunc(decimals) || decimals < 0 || decimals > maxCoordinatePrecision {
+		return 0, fmt.Errorf("coordinate_precision must be a whole number from 0 to %d", maxCoordinatePrecision)
+	}
+	return int(decimals), nil
+}
+
+// roundResultCoordinates returns a copy of a text result with its
+// coordinates rounded, keeping the order of its fields. Results that are
+// not JSON are returned unchanged.
+func roundResultCoordinates(result *mcp.CallToolResult, decimals int) *mcp.CallToolResult {
+	if len(result.Content) == 0 {
+		return result
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return result
+	}
+
+	rounded, err := roundJSONCoordinates([]byte(text.Text), decimals)
+	if err != nil {
+		return result
+	}
+	out := *result
+	out.Content = append([]mcp.Content{mcp.NewTextContent(string(rounded))}, result.Content[1:]...)
+	return &out
+}
+
+// roundJSONCoordinates rewrites a JSON document token by token, rounding
+// the numbers held by coordinate fields and leaving everything else as it
+// was
+func roundJSONCoordinates(data []byte, decimals int) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	// Each open object or array records whether its values are
+	// coordinates, and objects whether the next token is a key
+	type frame struct {
+		object, expectKey, coordinates, first bool
+		key                                   string
+	}
+	var (
+		out   bytes.Buffer
+		stack []frame
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Separators and whether this value is a coordinate
+		inCoordinates := false
+		if n := len(stack); n > 0 {
+			top := &stack[n-1]
+			isEnd := token == json.Delim('}') || token == json.Delim(']')
+			if !isEnd {
+				if top.object && top.expectKey {
+					if !top.first {
+						out.WriteByte(',')
+					}
+				} else if top.object {
+					out.WriteByte(':')
+				} else if !top.first {
+					out.WriteByte(',')
+				}
+				top.first = false
+			}
+			if top.object && !top.expectKey {
+				inCoordinates = top.coordinates || coordinateKeys[top.key] || coordinateArrayKeys[top.key]
+			} else if !top.object {
+				inCoordinates = top.coordinates
+			}
+		}
+
+		isKey := false
+		if n := len(stack); n > 0 && stack[n-1].object && stack[n-1].expectKey {
+			if key, ok := token.(string); ok {
+				isKey = true
+				stack[n-1].key = key
+				stack[n-1].expectKey = false
+			}
+		}
+
+		switch v := token.(type) {
+		case json.Delim:
+			switch v {
+			case '{', '[':
+				out.WriteByte(byte(v))
+				// Only positions in coordinate arrays are rounded, not
+				// numbers in objects nested below them
+				stack = append(stack, frame{object: v == '{', expectKey: v == '{', first: true,
+					coordinates: inCoordinates && v == '['})
+				continue
+			case '}', ']':
+				out.WriteByte(byte(v))
+				stack = stack[:len(stack)-1]
+			}
+		case json.Number:
+			if inCoordinates {
+				out.WriteString(roundCoordinate(v, decimals))
+			} else {
+				out.WriteString(v.String())
+			}
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(encoded)
+		}
+
+		// A value completes the pending key of its object
+		if n := len(stack); n > 0 && stack[n-1].object && !isKey {
+			stack[n-1].expectKey = true
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// roundCoordinate rounds a JSON number to decimals, dropping trailing zeros.
+// Numbers already that short are returned as written.
+func roundCoordinate(n json.Number, decimals int) string {
+	s := n.String()
+	if !strings.ContainsAny(s, "eE") {
+		dot := strings.IndexByte(s, '.')
+		if dot < 0 || len(s)-dot-1 <= decimals {
+			return s
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return s
+	}
+	s = strconv.FormatFloat(f, 'f', decimals, 64)
+	if strings.IndexByte(s, '.') >= 0 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
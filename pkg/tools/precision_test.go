@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithCoordinatePrecision(t *testing.T) {
+	for _, def := range NewRegistry(slog.Default()).GetToolDefinitions() {
+		_, ok := def.Tool.InputSchema.Properties["coordinate_precision"]
+		if want := !coordinateFreeTools[def.Name]; ok != want {
+			t.Errorf("tool %s has coordinate_precision parameter %v, want %v", def.Name, ok, want)
+		}
+	}
+}
+
+func TestRoundJSONCoordinates(t *testing.T) {
+	input := `{"name":"Café 51.5","location":{"latitude":51.50735123456,"longitude":-0.12775829},"distance":12.3456789,` +
+		`"bbox":{"minLat":51.1234567,"minLon":-0.1,"maxLat":52,"maxLon":1e-7},` +
+		`"geometry":{"type":"LineString","coordinates":[[-0.1277583,51.5073512],[-0.1,51.5]]},` +
+		`"elements":[{"id":123456789012,"lat":-0.00000001,"tags":{"lat":"51.123456789"}}],"ok":true,"note":null}`
+	want := `{"name":"Café 51.5","location":{"latitude":51.507351,"longitude":-0.127758},"distance":12.3456789,` +
+		`"bbox":{"minLat":51.123457,"minLon":-0.1,"maxLat":52,"maxLon":0},` +
+		`"geometry":{"type":"LineString","coordinates":[[-0.127758,51.507351],[-0.1,51.5]]},` +
+		`"elements":[{"id":123456789012,"lat":0,"tags":{"lat":"51.123456789"}}],"ok":true,"note":null}`
+
+	got, err := roundJSONCoordinates([]byte(input), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	if _, err := roundJSONCoordinates([]byte("not json"), 6); err == nil {
+		t.Error("expected an error for text that is not JSON")
+	}
+}
+
+func TestCoordinatePrecisionHandler(t *testing.T) {
+	handler := coordinatePrecisionHandler(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"latitude":51.50735123,"longitude":-0.12775829}`), nil
+	})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if got := call(nil).Content[0].(mcp.TextContent).Text; got != `{"latitude":51.507351,"longitude":-0.127758}` {
+		t.Errorf("default precision: got %s", got)
+	}
+	if got := call(map[string]any{"coordinate_precision": float64(3)}).Content[0].(mcp.TextContent).Text; got != `{"latitude":51.507,"longitude":-0.128}` {
+		t.Errorf("precision 3: got %s", got)
+	}
+
+	defer SetCoordinatePrecision(DefaultCoordinatePrecision)
+	SetCoordinatePrecision(-1)
+	if got := call(nil).Content[0].(mcp.TextContent).Text; got != `{"latitude":51.50735123,"longitude":-0.12775829}` {
+		t.Errorf("full precision: got %s", got)
+	}
+
+	for _, invalid := range []any{-1.0, 16.0, 2.5, "six", true} {
+		if result := call(map[string]any{"coordinate_precision": invalid}); !result.IsError {
+			t.Errorf("expected an error result for coordinate_precision %v", invalid)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// slowWaitMs is the estimated wait above which a service is reported as throttled
+const slowWaitMs = 1000
+
+// RateLimitStatusOutput defines the output for rate_limit_status
+type RateLimitStatusOutput struct {
+	Services  []osm.RateLimiterStatus `json:"services"`
+	Tiles     core.TileUsageStatus    `json:"tiles"`
+	Throttled []string                `json:"throttled,omitempty"` // services with a noticeable wait
+	Summary   string                  `json:"summary"`
+}
+
+// RateLimitStatusTool returns a tool definition for inspecting upstream rate limits
+func RateLimitStatusTool() mcp.Tool {
+	return mcp.NewTool("rate_limit_status",
+		mcp.WithDescription("Show the rate limiter state for each upstream service (Nominatim, Overpass, OSRM and map tiles): tokens available, requests queued, recent wait times and the estimated wait for a new request. Use it to see why calls are slow before they time out"),
+	)
+}
+
+// HandleRateLimitStatus reports the current upstream rate limiter state
+func HandleRateLimitStatus(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "rate_limit_status")
+
+	output := rateLimitStatusOutput(osm.RateLimitStatus(), core.TileUsage())
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// rateLimitStatusOutput summarizes limiter snapshots
+func rateLimitStatusOutput(services []osm.RateLimiterStatus, tiles core.TileUsageStatus) RateLimitStatusOutput {
+	output := RateLimitStatusOutput{
+		Services: services,
+		Tiles:    tiles,
+	}
+
+	var slowest *osm.RateLimiterStatus
+	for i, status := range services {
+		if status.EstimatedWaitMs < slowWaitMs && status.QueueDepth == 0 {
+			continue
+		}
+		output.Throttled = append(output.Throttled, status.Service)
+		if slowest == nil || status.EstimatedWaitMs > slowest.EstimatedWaitMs {
+			slowest = &services[i]
+		}
+	}
+
+	switch {
+	case slowest != nil:
+		output.Summary = fmt.Sprintf("%s is throttled: %d request(s) queued, a new request would wait about %.1fs",
+			slowest.Service, slowest.QueueDepth, float64(slowest.EstimatedWaitMs)/1000)
+	case tiles.HourlyLimit > 0 && tiles.FetchesLastHour >= tiles.HourlyLimit:
+		output.Summary = "Tile fetches have reached the hourly limit; only cached tiles are available"
+	default:
+		output.Summary = "No upstream service is currently throttled"
+	}
+
+	return output
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestRateLimitStatusOutput(t *testing.T) {
+	idle := []osm.RateLimiterStatus{
+		{Service: "nominatim", TokensAvailable: 1},
+		{Service: "overpass", TokensAvailable: 1},
+	}
+	output := rateLimitStatusOutput(idle, core.TileUsageStatus{})
+	if len(output.Throttled) != 0 {
+		t.Errorf("expected no throttled services, got %v", output.Throttled)
+	}
+	if !strings.Contains(output.Summary, "No upstream service") {
+		t.Errorf("unexpected summary %q", output.Summary)
+	}
+
+	busy := []osm.RateLimiterStatus{
+		{Service: "nominatim", QueueDepth: 1, EstimatedWaitMs: 1500},
+		{Service: "overpass", QueueDepth: 3, EstimatedWaitMs: 60000},
+		{Service: "osrm", TokensAvailable: 4},
+	}
+	output = rateLimitStatusOutput(busy, core.TileUsageStatus{})
+	if len(output.Throttled) != 2 {
+		t.Fatalf("expected 2 throttled services, got %v", output.Throttled)
+	}
+	if !strings.HasPrefix(output.Summary, "overpass is throttled") {
+		t.Errorf("summary should name the slowest service, got %q", output.Summary)
+	}
+
+	tiles := core.TileUsageStatus{FetchesLastHour: 500, HourlyLimit: 500}
+	output = rateLimitStatusOutput(idle, tiles)
+	if !strings.Contains(output.Summary, "hourly limit") {
+		t.Errorf("expected tile limit summary, got %q", output.Summary)
+	}
+}
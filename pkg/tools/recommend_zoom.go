@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/render"
+)
+
+// metersPerDegreeLat is the approximate length of a degree of latitude
+const metersPerDegreeLat = 111320.0
+
+// RecommendZoomInput defines the input parameters for recommend_zoom
+type RecommendZoomInput struct {
+	BBox    *geo.BoundingBox `json:"bbox,omitempty"`
+	Center  *geo.Location    `json:"center,omitempty"`
+	Radius  float64          `json:"radius,omitempty"` // meters around center
+	Width   *int             `json:"width,omitempty"`
+	Height  *int             `json:"height,omitempty"`
+	Padding *int             `json:"padding,omitempty"`
+	MaxZoom *int             `json:"max_zoom,omitempty"`
+}
+
+// RecommendZoomOutput defines the output for recommend_zoom
+type RecommendZoomOutput struct {
+	Zoom           int         `json:"zoom"`
+	MetersPerPixel float64     `json:"meters_per_pixel"` // ground resolution at the view center
+	MapScale       string      `json:"map_scale"`
+	View           render.View `json:"view"`
+	Tiles          int         `json:"tiles"` // tiles needed to cover the view
+	EstimatedBytes int64       `json:"estimated_bytes"`
+}
+
+// RecommendZoomTool returns a tool definition for choosing a zoom level
+func RecommendZoomTool() mcp.Tool {
+	return mcp.NewTool("recommend_zoom",
+		mcp.WithDescription("Recommend the tile zoom level that fits an area into an image of a given pixel size, with the ground resolution, map scale and tile count. Use before get_map_image or tiles_for_bbox instead of guessing; city-wide views need zoom 10-12, not 19. Matches the zoom visualize_route and visualize_places would choose"),
+		mcp.WithObject("bbox",
+			mcp.Description("Area to show: bounding box with minLat, minLon, maxLat, maxLon. Give either bbox or center and radius"),
+		),
+		mcp.WithObject("center",
+			mcp.Description("Center of the area to show, with latitude and longitude"),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("Radius in meters around center to show"),
+		),
+		mcp.WithNumber("width",
+			mcp.Description(fmt.Sprintf("Output width in pixels (%d-%d)", render.MinSize, render.MaxSize)),
+			mcp.DefaultNumber(defaultRenderWidth),
+		),
+		mcp.WithNumber("height",
+			mcp.Description(fmt.Sprintf("Output height in pixels (%d-%d)", render.MinSize, render.MaxSize)),
+			mcp.DefaultNumber(defaultRenderHeight),
+		),
+		mcp.WithNumber("padding",
+			mcp.Description("Margin in pixels to keep around the area"),
+			mcp.DefaultNumber(defaultRenderPadding),
+		),
+		mcp.WithNumber("max_zoom",
+			mcp.Description(fmt.Sprintf("Highest zoom level to recommend (1-%d)", render.MaxZoom)),
+			mcp.DefaultNumber(defaultRenderMaxZoom),
+		),
+	)
+}
+
+// HandleRecommendZoom picks the zoom level fitting an area into an image
+func HandleRecommendZoom(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "recommend_zoom")
+
+	var input RecommendZoomInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").
+			WithGuidance("Expected a bbox object, or a center object with latitude and longitude and a numeric radius").
+			ToMCPResult(), nil
+	}
+
+	var bbox geo.BoundingBox
+	switch {
+	case input.BBox != nil:
+		if err := validateTileBBox(*input.BBox); err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance("Use minLat < maxLat and minLon < maxLon within valid coordinate ranges").
+				ToMCPResult(), nil
+		}
+		bbox = *input.BBox
+	case input.Center != nil:
+		if err := core.ValidateCoords(input.Center.Latitude, input.Center.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+		}
+		if input.Radius <= 0 || input.Radius > 20000000 {
+			return core.NewError(core.ErrInvalidRadius, "radius must be between 0 and 20000000 meters").ToMCPResult(), nil
+		}
+		bbox = radiusBBox(*input.Center, input.Radius)
+	default:
+		return core.NewError(core.ErrMissingParameter, "bbox or center and radius is required").ToMCPResult(), nil
+	}
+
+	// Sizes default to those of the rendering tools
+	width, height := intOr(input.Width, defaultRenderWidth), intOr(input.Height, defaultRenderHeight)
+	padding, maxZoom := intOr(input.Padding, defaultRenderPadding), intOr(input.MaxZoom, defaultRenderMaxZoom)
+	switch {
+	case width < render.MinSize || width > render.MaxSize || height < render.MinSize || height > render.MaxSize:
+		return core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("width and height must be between %d and %d pixels", render.MinSize, render.MaxSize)).ToMCPResult(), nil
+	case padding < 0 || 2*padding >= width || 2*padding >= height:
+		return core.NewError(core.ErrInvalidParameter, "padding must leave room for the map").ToMCPResult(), nil
+	case maxZoom < 1 || maxZoom > render.MaxZoom:
+		return core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("max_zoom must be between 1 and %d", render.MaxZoom)).ToMCPResult(), nil
+	}
+
+	view := render.FitBounds(bbox, width, height, padding, maxZoom)
+	metersPerPixel, mapScale := core.GroundResolution(view.Center.Latitude, view.Zoom)
+	tiles := tilesForBBox(view.Bounds, view.Zoom, 0)
+
+	output := RecommendZoomOutput{
+		Zoom:           view.Zoom,
+		MetersPerPixel: metersPerPixel,
+		MapScale:       mapScale,
+		View:           view,
+		Tiles:          tiles.Count,
+		EstimatedBytes: tiles.EstimatedBytes,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// radiusBBox returns the bounding box of a circle, clamped to valid
+// coordinates
+func radiusBBox(center geo.Location, radius float64) geo.BoundingBox {
+	dLat := radius / metersPerDegreeLat
+	dLon := 180.0
+	if cos := math.Cos(center.Latitude * math.Pi / 180); cos > 1e-6 {
+		dLon = math.Min(180, radius/(metersPerDegreeLat*cos))
+	}
+	return geo.BoundingBox{
+		MinLat: math.Max(-90, center.Latitude-dLat),
+		MinLon: math.Max(-180, center.Longitude-dLon),
+		MaxLat: math.Min(90, center.Latitude+dLat),
+		MaxLon: math.Min(180, center.Longitude+dLon),
+	}
+}
+
+// intOr returns the value of an optional integer, or a default
+func intOr(value *int, fallback int) int {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
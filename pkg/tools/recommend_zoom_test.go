@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleRecommendZoom(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+		zoom int
+	}{
+		{"city radius", map[string]any{
+			"center": map[string]any{"latitude": 51.5074, "longitude": -0.1278},
+			"radius": 10000,
+		}, 10},
+		{"street bbox", map[string]any{
+			"bbox": map[string]any{"minLat": 51.500, "minLon": -0.130, "maxLat": 51.502, "maxLon": -0.126},
+		}, 17},
+		{"street bbox larger image", map[string]any{
+			"bbox":     map[string]any{"minLat": 51.500, "minLon": -0.130, "maxLat": 51.502, "maxLon": -0.126},
+			"max_zoom": 19,
+			"width":    1024,
+			"height":   1024,
+		}, 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.args}}
+			result, err := HandleRecommendZoom(context.Background(), req)
+			if err != nil || result.IsError {
+				t.Fatalf("unexpected error: %v %v", err, result)
+			}
+			var output RecommendZoomOutput
+			if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+				t.Fatal(err)
+			}
+			if output.Zoom != tt.zoom {
+				t.Errorf("zoom = %d, want %d", output.Zoom, tt.zoom)
+			}
+			if output.MetersPerPixel <= 0 || output.Tiles == 0 || output.MapScale == "" {
+				t.Errorf("unexpected output %+v", output)
+			}
+		})
+	}
+}
+
+func TestHandleRecommendZoomValidation(t *testing.T) {
+	invalid := []map[string]any{
+		{},
+		{"center": map[string]any{"latitude": 51.5, "longitude": -0.1}},
+		{"center": map[string]any{"latitude": 95, "longitude": -0.1}, "radius": 100},
+		{"bbox": map[string]any{"minLat": 2, "minLon": 0, "maxLat": 1, "maxLon": 1}},
+		{"center": map[string]any{"latitude": 51.5, "longitude": -0.1}, "radius": 100, "width": 5000},
+	}
+	for _, args := range invalid {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+		result, err := HandleRecommendZoom(context.Background(), req)
+		if err != nil || !result.IsError {
+			t.Errorf("expected an error result for %v", args)
+		}
+	}
+}
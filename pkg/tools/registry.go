@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/monitoring"
 	"github.com/NERVsystems/osmmcp/pkg/tools/prompts"
 	"github.com/NERVsystems/osmmcp/pkg/tracing"
 )
@@ -23,6 +24,7 @@ import (
 type Registry struct {
 	logger  *slog.Logger
 	factory *core.ToolFactory
+	include func(name string) bool
 }
 
 // NewRegistry creates a new tool registry
@@ -33,12 +35,25 @@ func NewRegistry(logger *slog.Logger) *Registry {
 	}
 }
 
+// WithFilter limits the registry to the tools for which include returns
+// true. It returns the registry for chaining.
+func (r *Registry) WithFilter(include func(name string) bool) *Registry {
+	r.include = include
+	return r
+}
+
 // ToolDefinition represents an OpenStreetMap MCP tool definition.
 type ToolDefinition struct {
 	Name        string
 	Description string
 	Tool        mcp.Tool
 	Handler     func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+	// Version is the behavior version of the tool, taken from an @vN name
+	// suffix and 1 otherwise. See withVersioning.
+	Version int
+	// Deprecation is set when the tool is being phased out
+	Deprecation *Deprecation
 }
 
 // GetToolDefinitions returns the list of all available tools.
@@ -51,20 +66,92 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 			Tool:        GetVersionTool(),
 			Handler:     HandleGetVersion,
 		},
+		{
+			Name:        "rate_limit_status",
+			Description: "Show upstream rate limiter state: tokens available, queued requests, recent waits and estimated wait per service. No parameters",
+			Tool:        RateLimitStatusTool(),
+			Handler:     HandleRateLimitStatus,
+		},
 
 		// Geocoding tools
 		{
 			Name:        "geocode_address",
-			Description: "Convert address, place name, or military coordinates (MGRS, UTM, DMS) to lat/lon. Essential for tactical coordinate handling.",
+			Description: "Convert address, place name, or military coordinates (MGRS, UTM, DMS) to lat/lon. Essential for tactical coordinate handling. Set include_polygon to get the simplified boundary of areas such as cities and parks.",
 			Tool:        GeocodeAddressTool(),
 			Handler:     HandleGeocodeAddress,
 		},
+		{
+			Name:        "geocode_autocomplete",
+			Description: "Suggest places and addresses completing a partial query, ranked by match. Parameters: query (string, the last word may be incomplete), limit (number, optional), country (string, ISO codes, optional)",
+			Tool:        GeocodeAutocompleteTool(),
+			Handler:     HandleGeocodeAutocomplete,
+		},
 		{
 			Name:        "reverse_geocode",
 			Description: "Convert geographic coordinates to a street address. Parameters: latitude (number), longitude (number)",
 			Tool:        ReverseGeocodeTool(),
 			Handler:     HandleReverseGeocode,
 		},
+		{
+			Name:        "reverse_geocode_candidates",
+			Description: "List the addresses and named places nearest to a coordinate with distances, for low-accuracy GPS fixes. Parameters: latitude (number), longitude (number), radius (number, meters, optional), limit (number, optional), include_pois (boolean, optional)",
+			Tool:        ReverseGeocodeCandidatesTool(),
+			Handler:     HandleReverseGeocodeCandidates,
+		},
+		{
+			Name:        "reverse_geocode_track",
+			Description: "List the localities a route or track passes through, in order",
+			Tool:        ReverseGeocodeTrackTool(),
+			Handler:     HandleReverseGeocodeTrack,
+		},
+		{
+			Name:        "reverse_geocode_batch",
+			Description: "Get the address of each of a list of points. Parameters: points (array of {latitude, longitude}) or polyline (string, encoded)",
+			Tool:        ReverseGeocodeBatchTool(),
+			Handler:     HandleReverseGeocodeBatch,
+		},
+		{
+			Name:        "lookup_osm_object",
+			Description: "Look up an OSM element by ID for its address, extra tags, Wikidata link and outline. Parameters: id (string, e.g. 'node/2003764150'), osm_type (string: node, way or relation, for bare numeric IDs, optional), include_geometry (boolean, optional), include_hierarchy (boolean, optional)",
+			Tool:        LookupOSMObjectTool(),
+			Handler:     HandleLookupOSMObject,
+		},
+		{
+			Name:        "find_intersection",
+			Description: "Find where two named streets meet. Parameters: query (string, e.g. 'Main St & 5th Ave') or street_a and street_b (strings), near (object with latitude/longitude) or city (string), radius (number, meters, optional)",
+			Tool:        FindIntersectionTool(),
+			Handler:     HandleFindIntersection,
+		},
+		{
+			Name:        "resolve_place_reference",
+			Description: "Resolve a free-text place reference to the most likely OSM feature with a confidence score. Parameters: text (string), bbox (optional object with minLat, minLon, maxLat, maxLon), near (optional object with latitude, longitude), radius (number in meters), previous_results (optional array of places), type_hint (string)",
+			Tool:        ResolvePlaceReferenceTool(),
+			Handler:     HandleResolvePlaceReference,
+		},
+		{
+			Name:        "search_isochrone_boundary",
+			Description: "Find places of a category just inside the boundary of a reachable-area polygon, closest to the edge first. Parameters: polygon (array of {latitude, longitude}), category (string), origin (object, optional), band (number, optional), limit (number, optional), include_tags (boolean, optional)",
+			Tool:        IsochroneBoundaryTool(),
+			Handler:     HandleIsochroneBoundary,
+		},
+		{
+			Name:        "get_isochrone",
+			Description: "Compute the area reachable from a point within a travel time",
+			Tool:        IsochroneTool(),
+			Handler:     HandleIsochrone,
+		},
+		{
+			Name:        "route_matrix",
+			Description: "Compute travel durations and distances between several origins and destinations",
+			Tool:        RouteMatrixTool(),
+			Handler:     HandleRouteMatrix,
+		},
+		{
+			Name:        "partition_territory",
+			Description: "Split a polygon into balanced zones as GeoJSON. Parameters: polygon (array of {latitude, longitude}), zones (number), balance_by (string: area, poi_count, points), category (string), points (array)",
+			Tool:        PartitionTerritoryTool(),
+			Handler:     HandlePartitionTerritory,
+		},
 
 		// Visualization tools
 		{
@@ -77,16 +164,34 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 		// Route and direction tools
 		{
 			Name:        "route_fetch",
-			Description: "Fetch a route between two points. Parameters: start (object with latitude/longitude), end (object with latitude/longitude), mode (string: car, bike, foot)",
+			Description: "Fetch a route between two points. Parameters: start (object with latitude/longitude), end (object with latitude/longitude), mode (string: car, bike, foot, air, sea), cruise_speed (number, knots, optional), units (string: metric, imperial, nautical, optional), ignore_closures (boolean, optional), avoid_areas (array of polygons, optional), to_entrance (boolean, optional, default true for foot)",
 			Tool:        RouteFetchTool(),
 			Handler:     HandleRouteFetch,
 		},
+		{
+			Name:        "report_closure",
+			Description: "Report, list, remove or import temporary road closures consulted by route_fetch and get_route_directions. Parameters: action (string: add, list, remove, clear, import), location (object) or geometry (array), radius (number), severity (string: closed, delay), delay_minutes (number), expires_in_minutes (number), geojson (object)",
+			Tool:        ReportClosureTool(),
+			Handler:     HandleReportClosure,
+		},
 		{
 			Name:        "get_route_directions",
-			Description: "Get turn-by-turn directions between two points. Parameters: start_lat (number), start_lon (number), end_lat (number), end_lon (number), mode (string: car, bike, foot)",
+			Description: "Get turn-by-turn directions between two points. Parameters: start_lat (number), start_lon (number), end_lat (number), end_lon (number), mode (string: car, bike, foot), units (string: metric, imperial, nautical, optional), ignore_closures (boolean, optional), avoid_areas (array of polygons, optional), to_entrance (boolean, optional, default true for foot), pedestrian_safety (boolean, optional, default true for foot)",
 			Tool:        GetRouteDirectionsTool(),
 			Handler:     HandleGetRouteDirections,
 		},
+		{
+			Name:        "route_narrative",
+			Description: "Get turn-by-turn directions described with nearby named landmarks. Parameters: start_lat (number), start_lon (number), end_lat (number), end_lon (number), mode (string), landmark_radius (number, meters)",
+			Tool:        RouteNarrativeTool(),
+			Handler:     HandleRouteNarrative,
+		},
+		{
+			Name:        "optimize_stops",
+			Description: "Find the fastest order to visit up to 25 stops. Parameters: stops (array of {latitude, longitude, name}), mode (string), roundtrip (boolean), units (string, optional)",
+			Tool:        OptimizeStopsTool(),
+			Handler:     HandleOptimizeStops,
+		},
 		{
 			Name:        "suggest_meeting_point",
 			Description: "Suggest a meeting point for multiple locations. Parameters: locations (array of latitude/longitude objects), radius (number), category (string)",
@@ -99,6 +204,18 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 			Tool:        RouteSampleTool(),
 			Handler:     HandleRouteSample,
 		},
+		{
+			Name:        "visualize_route",
+			Description: "Render a route polyline on a map image fitted to the route, with start and end markers. Parameters: polyline (string), width (number), height (number), padding (number), max_zoom (number)",
+			Tool:        VisualizeRouteTool(),
+			Handler:     HandleVisualizeRoute,
+		},
+		{
+			Name:        "visualize_places",
+			Description: "Plot places as numbered markers on a map image with a legend mapping numbers to place IDs. Parameters: places (array), max_markers (number), width (number), height (number), padding (number), max_zoom (number)",
+			Tool:        VisualizePlacesTool(),
+			Handler:     HandleVisualizePlaces,
+		},
 		{
 			Name:        "analyze_commute",
 			Description: "Analyze commute options between home and work locations. Parameters: home (object), work (object)",
@@ -109,48 +226,108 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 		// POI and exploration tools
 		{
 			Name:        "find_nearby_places",
-			Description: "Find places near a location. Parameters: latitude (number), longitude (number), radius (number in meters), category (string), limit (number)",
+			Description: "Find places near a location. Parameters: latitude (number), longitude (number), radius (number in meters), category (string), limit (number), include_images (boolean), include_tags (boolean), open_now (boolean), open_at (string, RFC 3339), min_remaining_open_minutes (number), timezone (string), level (string, indoor floor), filters (object: cuisine, diet, wheelchair, outdoor_seating, takeaway, brand), cursor (string). Ordered by distance then OSM ID",
 			Tool:        FindNearbyPlacesTool(),
 			Handler:     HandleFindNearbyPlaces,
 		},
+		{
+			Name:        "search_places_by_name",
+			Description: "Find places whose name or name:* translation contains a text within a radius or bbox, e.g. the nearest Starbucks. Parameters: query (string), regex (boolean), include_translations (boolean), latitude (number), longitude (number), radius (number in meters) or bbox (object with minLat, minLon, maxLat, maxLon), limit (number), cursor (string). Ordered by distance then OSM ID",
+			Tool:        SearchPlacesByNameTool(),
+			Handler:     HandleSearchPlacesByName,
+		},
+		{
+			Name:        "next_departures",
+			Description: "List upcoming departures at a transit stop from the configured departures providers. Parameters: stop_id (string, e.g. node/123), limit (number), after (string, RFC 3339, optional)",
+			Tool:        NextDeparturesTool(),
+			Handler:     HandleNextDepartures,
+		},
+		{
+			Name:        "find_transit_routes_at_stop",
+			Description: "List the transit routes serving a stop from loaded GTFS feeds. Parameters: stop_id (string, optional), latitude (number), longitude (number), radius (number, meters)",
+			Tool:        FindTransitRoutesAtStopTool(),
+			Handler:     HandleFindTransitRoutesAtStop,
+		},
 		{
 			Name:        "explore_area",
 			Description: "Explore an area and get key features. Parameters: latitude (number), longitude (number), radius (number in meters)",
 			Tool:        ExploreAreaTool(),
 			Handler:     HandleExploreArea,
 		},
+		{
+			Name:        "watch_area",
+			Description: "Watch an area for added, removed or changed places, published as a resource with update notifications",
+			Tool:        WatchAreaTool(),
+			Handler:     HandleWatchArea,
+		},
+		{
+			Name:        "unwatch_area",
+			Description: "Stop an area watch started with watch_area",
+			Tool:        UnwatchAreaTool(),
+			Handler:     HandleUnwatchArea,
+		},
 		{
 			Name:        "find_parking_facilities",
-			Description: "Find parking facilities near a location. Parameters: latitude (number), longitude (number), radius (number in meters), type (string), include_private (boolean), limit (number)",
+			Description: "Find parking facilities near a location. Parameters: latitude (number), longitude (number), radius (number in meters), type (string), include_private (boolean), wheelchair (boolean), limit (number), cursor (string). Ordered by distance then OSM ID",
 			Tool:        FindParkingAreasTool(),
 			Handler:     HandleFindParkingFacilities,
 		},
+		{
+			Name:        "parking_for_destination",
+			Description: "Find parking near a destination ranked by walking time, with driving and walking directions. Parameters: destination (object with latitude, longitude), origin (optional object with latitude, longitude), max_walk_distance (number in meters), limit (number), include_private (boolean), wheelchair (boolean)",
+			Tool:        ParkingForDestinationTool(),
+			Handler:     HandleParkingForDestination,
+		},
 		{
 			Name:        "find_charging_stations",
-			Description: "Find EV charging stations near a location. Parameters: latitude (number), longitude (number), radius (number in meters), limit (number)",
+			Description: "Find EV charging stations near a location. Parameters: latitude (number), longitude (number), radius (number in meters), limit (number), cursor (string). Ordered by distance then OSM ID",
 			Tool:        FindChargingStationsTool(),
 			Handler:     HandleFindChargingStations,
 		},
 		{
 			Name:        "find_schools_nearby",
-			Description: "Find schools near a location. Parameters: latitude (number), longitude (number), radius (number in meters), limit (number)",
+			Description: "Find schools near a location. Parameters: latitude (number), longitude (number), radius (number in meters), school_type (string), limit (number), cursor (string). Ordered by distance then OSM ID",
 			Tool:        FindSchoolsNearbyTool(),
 			Handler:     HandleFindSchoolsNearby,
 		},
 		{
 			Name:        "analyze_neighborhood",
-			Description: "Analyze a neighborhood for livability. Parameters: latitude (number), longitude (number), name (string)",
+			Description: "Analyze a neighborhood for livability, including noise and green space proxies. Parameters: latitude (number), longitude (number), name (string), include_environment (boolean), population (number)",
 			Tool:        AnalyzeNeighborhoodTool(),
 			Handler:     HandleAnalyzeNeighborhood,
 		},
+		{
+			Name:        "terrain_risk_screen",
+			Description: "Screen a site for flood exposure from elevation and proximity to water (non-authoritative). Parameters: latitude (number), longitude (number), search_radius (number)",
+			Tool:        TerrainRiskScreenTool(),
+			Handler:     HandleTerrainRiskScreen,
+		},
 
 		// Geo utility tools
 		{
 			Name:        "geo_distance",
-			Description: "Calculate distance between two points. Parameters: from (object with latitude/longitude), to (object with latitude/longitude)",
+			Description: "Calculate distance between two points. Parameters: from (object with latitude/longitude), to (object with latitude/longitude), units (string: metric, imperial, nautical, optional), routed (boolean, optional: add driving and walking route distances)",
 			Tool:        GeoDistanceTool(),
 			Handler:     HandleGeoDistance,
 		},
+		{
+			Name:        "great_circle_path",
+			Description: "Generate points along the great circle between two coordinates. Parameters: from (object), to (object), points (number), units (string, optional)",
+			Tool:        GreatCirclePathTool(),
+			Handler:     HandleGreatCirclePath,
+		},
+		{
+			Name:        "geo_midpoint",
+			Description: "Calculate the great circle midpoint of two coordinates. Parameters: from (object), to (object), units (string, optional)",
+			Tool:        GeoMidpointTool(),
+			Handler:     HandleGeoMidpoint,
+		},
+		{
+			Name:        "antipode",
+			Description: "Calculate the point on the opposite side of the Earth. Parameters: point (object)",
+			Tool:        AntipodeTool(),
+			Handler:     HandleAntipode,
+		},
 		{
 			Name:        "bbox_from_points",
 			Description: "Create a bounding box from multiple points. Parameters: points (array of latitude/longitude objects)",
@@ -163,6 +340,72 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 			Tool:        CentroidPointsTool(),
 			Handler:     HandleCentroidPoints,
 		},
+		{
+			Name:        "geo_area",
+			Description: "Calculate the area and perimeter of a polygon. Parameters: points (array of latitude/longitude objects), polyline (string) or geometry (GeoJSON Polygon or MultiPolygon), units (string, optional)",
+			Tool:        GeoAreaTool(),
+			Handler:     HandleGeoArea,
+		},
+		{
+			Name:        "point_in_polygon",
+			Description: "Test whether locations fall inside a polygon. Parameters: locations (array of latitude/longitude objects), polygon (array of latitude/longitude objects), polyline (string) or geometry (GeoJSON Polygon or MultiPolygon)",
+			Tool:        PointInPolygonTool(),
+			Handler:     HandlePointInPolygon,
+		},
+		{
+			Name:        "point_in_bbox",
+			Description: "Test whether locations fall inside a bounding box. Parameters: locations (array of latitude/longitude objects), bbox (object: minLat, minLon, maxLat, maxLon)",
+			Tool:        PointInBBoxTool(),
+			Handler:     HandlePointInBBox,
+		},
+		{
+			Name:        "aggregate_points",
+			Description: "Bin points into geohash cells with counts for privacy-preserving aggregation. Parameters: points (array of latitude/longitude objects), scheme (string: geohash), precision (number, 1-12), min_count (number, optional)",
+			Tool:        AggregatePointsTool(),
+			Handler:     HandleAggregatePoints,
+		},
+		{
+			Name:        "encode_geohash",
+			Description: "Encode a coordinate as a geohash. Parameters: latitude (number), longitude (number), precision (number, 1-12)",
+			Tool:        EncodeGeohashTool(),
+			Handler:     HandleEncodeGeohash,
+		},
+		{
+			Name:        "decode_geohash",
+			Description: "Decode a geohash into its center, bounding box and boundary polygon. Parameters: geohash (string)",
+			Tool:        DecodeGeohashTool(),
+			Handler:     HandleDecodeGeohash,
+		},
+		{
+			Name:        "geohash_neighbors",
+			Description: "List the geohash cells within k cells of a geohash. Parameters: geohash (string), k (number, 1-5)",
+			Tool:        GeohashNeighborsTool(),
+			Handler:     HandleGeohashNeighbors,
+		},
+		{
+			Name:        "encode_h3",
+			Description: "Encode a coordinate as an H3 cell with its boundary polygon. Parameters: latitude (number), longitude (number), resolution (number, 0-15)",
+			Tool:        EncodeH3Tool(),
+			Handler:     HandleEncodeH3,
+		},
+		{
+			Name:        "decode_h3",
+			Description: "Decode an H3 cell into its center, boundary polygon and area. Parameters: h3 (string)",
+			Tool:        DecodeH3Tool(),
+			Handler:     HandleDecodeH3,
+		},
+		{
+			Name:        "h3_neighbors",
+			Description: "List the H3 cells within k cells of an H3 cell. Parameters: h3 (string), k (number, 1-10)",
+			Tool:        H3NeighborsTool(),
+			Handler:     HandleH3Neighbors,
+		},
+		{
+			Name:        "sun_times",
+			Description: "Calculate sunrise, sunset, civil twilight and the sun's position. Parameters: latitude (number), longitude (number), date (string), time (string), timezone (string)",
+			Tool:        SunTimesTool(),
+			Handler:     HandleSunTimes,
+		},
 
 		// Polyline utilities
 		{
@@ -177,6 +420,12 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 			Tool:        PolylineEncodeTool(),
 			Handler:     HandlePolylineEncode,
 		},
+		{
+			Name:        "export_gpx",
+			Description: "Convert a route polyline or waypoints into a GPX 1.1 document",
+			Tool:        ExportGPXTool(),
+			Handler:     HandleExportGPX,
+		},
 		{
 			Name:        "enrich_emissions",
 			Description: "Enrich transportation modes with emissions data. Parameters: options (array of mode objects)",
@@ -191,6 +440,18 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 			Tool:        OSMQueryBBoxTool(),
 			Handler:     HandleOSMQueryBBox,
 		},
+		{
+			Name:        "hydrate_elements",
+			Description: "Fetch the full tags and geometry of OSM elements by type and ID in one request. Parameters: elements (array of up to 100 IDs such as 'node/2003764150' or {type, id} objects)",
+			Tool:        HydrateElementsTool(),
+			Handler:     HandleHydrateElements,
+		},
+		{
+			Name:        "get_osm_geometry",
+			Description: "Fetch the full geometry of ways and relations as GeoJSON LineStrings and Polygons, with length and area",
+			Tool:        GetOSMGeometryTool(),
+			Handler:     HandleGetOSMGeometry,
+		},
 		{
 			Name:        "filter_tags",
 			Description: "Filter OSM elements by tags. Parameters: elements (array), tags (object of string arrays)",
@@ -199,7 +460,7 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 		},
 		{
 			Name:        "sort_by_distance",
-			Description: "Sort OSM elements by distance from a reference point. Parameters: elements (array), ref (object with latitude/longitude)",
+			Description: "Sort OSM elements by distance from a reference point, ties broken by ID. Parameters: elements (array), ref (object with latitude/longitude)",
 			Tool:        SortByDistanceTool(),
 			Handler:     HandleSortByDistance,
 		},
@@ -211,9 +472,32 @@ func (r *Registry) GetToolDefinitions() []ToolDefinition {
 			Tool:        GetTileCacheTool(),
 			Handler:     HandleTileCache,
 		},
+		{
+			Name:        "tiles_for_bbox",
+			Description: "List the tiles covering a bounding box at a zoom level with count and estimated size. Parameters: bbox (object: minLat, minLon, maxLat, maxLon), zoom (number, 0-20), max_tiles (number, optional)",
+			Tool:        TilesForBBoxTool(),
+			Handler:     HandleTilesForBBox,
+		},
+		{
+			Name:        "recommend_zoom",
+			Description: "Recommend the zoom level fitting a bbox or center and radius into an image size, with ground resolution and tile count. Parameters: bbox (object) or center (object) and radius (number), width (number), height (number), padding (number), max_zoom (number)",
+			Tool:        RecommendZoomTool(),
+			Handler:     HandleRecommendZoom,
+		},
 	}
 
-	return defs
+	defs = withVersioning(withTabularOutput(withAttribution(withTransform(withFieldSelection(withListOptions(withCoordinatePrecision(defs)))))))
+	if r.include == nil {
+		return defs
+	}
+
+	included := defs[:0]
+	for _, def := range defs {
+		if r.include(def.Name) {
+			included = append(included, def)
+		}
+	}
+	return included
 }
 
 // RegisterTools registers all tools with the MCP server.
@@ -248,6 +532,8 @@ func (r *Registry) wrapWithTracing(toolName string, handler func(ctx context.Con
 		duration := time.Since(startTime)
 		durationMs := duration.Milliseconds()
 
+		monitoring.RecordMCPRequest(toolName, duration, err == nil && (result == nil || !result.IsError))
+
 		// Determine status
 		status := tracing.StatusSuccess
 		if err != nil {
@@ -301,13 +587,16 @@ func (r *Registry) GetToolNames() []string {
 	return names
 }
 
-// RegisterAll registers all tools and prompts with the MCP server.
+// RegisterAll registers all tools, their documentation and prompts with the
+// MCP server.
 func (r *Registry) RegisterAll(mcpServer *server.MCPServer) {
 	// Create a context with the registry for capabilities lookup
 	registryCtx := context.WithValue(context.Background(), "registry", r)
 	mcpServer.WithContext(registryCtx, nil)
 
-	// Register all tools and prompts
+	// Register all tools, docs and prompts
 	r.RegisterTools(mcpServer)
+	r.RegisterDocs(mcpServer)
+	r.RegisterSpool(mcpServer)
 	r.RegisterPrompts(mcpServer)
 }
@@ -0,0 +1,474 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// defaultResolveRadius is the POI search radius around "near" in meters
+	defaultResolveRadius = 2000.0
+	// maxResolveRadius caps the POI search radius around "near"
+	maxResolveRadius = 10000.0
+	// maxResolveAlternatives caps the number of alternative matches returned
+	maxResolveAlternatives = 5
+)
+
+// ResolvePlaceReferenceInput defines the input parameters for resolve_place_reference
+type ResolvePlaceReferenceInput struct {
+	Text            string           `json:"text"`
+	BBox            *geo.BoundingBox `json:"bbox,omitempty"`
+	Near            *geo.Location    `json:"near,omitempty"`
+	Radius          float64          `json:"radius,omitempty"`
+	PreviousResults []Place          `json:"previous_results,omitempty"`
+	TypeHint        string           `json:"type_hint,omitempty"`
+}
+
+// ResolvedPlace is a candidate feature with a confidence score
+type ResolvedPlace struct {
+	Place      Place   `json:"place"`
+	Confidence float64 `json:"confidence"` // 0..1
+	Source     string  `json:"source"`     // context, poi_search or geocoder
+}
+
+// ResolvePlaceReferenceOutput defines the output for resolve_place_reference
+type ResolvePlaceReferenceOutput struct {
+	Best         *ResolvedPlace  `json:"best,omitempty"`
+	Alternatives []ResolvedPlace `json:"alternatives,omitempty"`
+}
+
+// ResolvePlaceReferenceTool returns a tool definition for resolving free-text place references
+func ResolvePlaceReferenceTool() mcp.Tool {
+	return mcp.NewTool("resolve_place_reference",
+		mcp.WithDescription("Resolve a free-text place reference (e.g. \"the Starbucks by the station\") to the most likely OSM feature using conversational context, returning a confidence score"),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("The free-text place reference to resolve"),
+		),
+		mcp.WithObject("bbox",
+			mcp.Description("Optional bounding box {minLat, minLon, maxLat, maxLon} the reference is expected to lie within"),
+		),
+		mcp.WithObject("near",
+			mcp.Description("Optional point {latitude, longitude} the reference is expected to be near"),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("Search radius in meters around 'near' (max 10000)"),
+			mcp.DefaultNumber(defaultResolveRadius),
+		),
+		mcp.WithArray("previous_results",
+			mcp.Description("Places returned by earlier tool calls that the reference may point to"),
+		),
+		mcp.WithString("type_hint",
+			mcp.Description("Optional category hint such as restaurant, cafe, station or park"),
+		),
+	)
+}
+
+// HandleResolvePlaceReference resolves a free-text reference to the most likely OSM feature
+func HandleResolvePlaceReference(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "resolve_place_reference")
+
+	// Parse input
+	var input ResolvePlaceReferenceInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	input.Text = strings.TrimSpace(input.Text)
+	if input.Text == "" {
+		return core.NewError(core.ErrEmptyParameter, "Text must not be empty").
+			WithGuidance("Provide the place reference to resolve, e.g. \"Blue Bottle Coffee\"").
+			ToMCPResult(), nil
+	}
+	if err := core.ValidateStringLength(input.Text, 1, maxAddressLength); err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+
+	if input.BBox != nil {
+		if err := core.ValidateCoords(input.BBox.MinLat, input.BBox.MinLon); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid bbox: %s", err)).ToMCPResult(), nil
+		}
+		if err := core.ValidateCoords(input.BBox.MaxLat, input.BBox.MaxLon); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid bbox: %s", err)).ToMCPResult(), nil
+		}
+		if input.BBox.MinLat > input.BBox.MaxLat || input.BBox.MinLon > input.BBox.MaxLon {
+			return core.NewError(core.ErrInvalidInput, "Invalid bbox: min values must be less than max values").ToMCPResult(), nil
+		}
+	}
+
+	if input.Near != nil {
+		if err := core.ValidateCoords(input.Near.Latitude, input.Near.Longitude); err != nil {
+			return core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid near coordinates: %s", err)).ToMCPResult(), nil
+		}
+		if input.Radius == 0 {
+			input.Radius = defaultResolveRadius
+		}
+		if err := core.ValidateRadius(input.Radius, maxResolveRadius); err != nil {
+			return core.NewError(core.ErrInvalidRadius, err.Error()).ToMCPResult(), nil
+		}
+	}
+
+	candidates := make([]ResolvedPlace, 0)
+
+	// 1. Conversational context: places the user has already seen
+	exactContextMatch := false
+	for _, place := range input.PreviousResults {
+		nameScore := nameSimilarity(input.Text, place.Name)
+		if nameScore < 0.5 {
+			continue
+		}
+		if nameScore == 1 {
+			exactContextMatch = true
+		}
+		candidates = append(candidates, ResolvedPlace{
+			Place:      place,
+			Confidence: scoreCandidate(nameScore, 1.0, typeHintScore(input.TypeHint, place.Categories), input.TypeHint != ""),
+			Source:     "context",
+		})
+	}
+
+	// 2. Fuzzy POI name search within the spatial context
+	if !exactContextMatch && (input.BBox != nil || input.Near != nil) {
+		elements, err := executeOverpassQuery(ctx, buildNameSearchQuery(input))
+		if err != nil {
+			logger.Warn("POI name search failed", "error", err)
+		} else {
+			for _, element := range elements {
+				place, ok := elementToPlace(element)
+				if !ok {
+					continue
+				}
+				contextScore := 1.0
+				if input.Near != nil {
+					place.Distance = geo.HaversineDistance(input.Near.Latitude, input.Near.Longitude,
+						place.Location.Latitude, place.Location.Longitude)
+					contextScore = 1.0 - place.Distance/(2*input.Radius)
+				}
+				candidates = append(candidates, ResolvedPlace{
+					Place:      place,
+					Confidence: scoreCandidate(nameSimilarity(input.Text, place.Name), contextScore, typeHintScore(input.TypeHint, place.Categories), input.TypeHint != ""),
+					Source:     "poi_search",
+				})
+			}
+		}
+	}
+
+	// 3. Geocoder fallback, favouring results inside the spatial context
+	if !exactContextMatch {
+		candidates = append(candidates, geocodeCandidates(ctx, logger, input)...)
+	}
+
+	if len(candidates) == 0 {
+		return core.NewError(core.ErrNoResults, fmt.Sprintf("Could not resolve %q", input.Text)).
+			WithGuidance("Add a bbox or near point, or rephrase the reference using the place's name").
+			ToMCPResult(), nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+
+	best := candidates[0]
+	alternatives := candidates[1:]
+	if len(alternatives) > maxResolveAlternatives {
+		alternatives = alternatives[:maxResolveAlternatives]
+	}
+
+	output := ResolvePlaceReferenceOutput{
+		Best:         &best,
+		Alternatives: alternatives,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// geocodeCandidates geocodes the reference and scores the results against the input's spatial context
+func geocodeCandidates(ctx context.Context, logger *slog.Logger, input ResolvePlaceReferenceInput) []ResolvedPlace {
+	results, err := geocodeQuery(ctx, input.Text)
+	if err != nil {
+		logger.Warn("geocoding failed", "error", err)
+		return nil
+	}
+
+	candidates := make([]ResolvedPlace, 0, len(results))
+	for _, result := range results {
+		place, err := resultToPlace(result)
+		if err != nil {
+			continue
+		}
+
+		contextScore := 0.3 + 0.5*place.Importance
+		if input.BBox != nil && bboxContains(input.BBox, place.Location) {
+			contextScore = 1.0
+		} else if input.Near != nil {
+			distance := geo.HaversineDistance(input.Near.Latitude, input.Near.Longitude,
+				place.Location.Latitude, place.Location.Longitude)
+			if distance <= input.Radius {
+				contextScore = 1.0
+			}
+		}
+		if result.Type != "" {
+			place.Categories = []string{result.Type}
+		}
+
+		candidates = append(candidates, ResolvedPlace{
+			Place:      place,
+			Confidence: scoreCandidate(nameSimilarity(input.Text, place.Name), contextScore, typeHintScore(input.TypeHint, place.Categories), input.TypeHint != ""),
+			Source:     "geocoder",
+		})
+	}
+
+	return candidates
+}
+
+// nameStopwords are words left out of name searches, since references and
+// names often differ in them, including the words relating a place to a
+// landmark as in "the Starbucks by the station"
+var nameStopwords = map[string]bool{
+	"the": true, "a": true, "an": true, "of": true, "and": true,
+	"at": true, "on": true, "in": true,
+	"by": true, "near": true, "next": true, "to": true, "opposite": true, "behind": true,
+}
+
+// significantNameTokens splits a reference into its lower-cased words,
+// leaving out stopwords and single characters, such as the s of
+// "Starbuck's", unless nothing else remains
+func significantNameTokens(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		if !nameStopwords[word] && utf8.RuneCountInString(word) > 1 && !seen[word] {
+			seen[word] = true
+			tokens = append(tokens, word)
+		}
+	}
+	if len(tokens) == 0 {
+		return words
+	}
+	return tokens
+}
+
+// buildNameSearchQuery builds an Overpass search for the input's spatial
+// context matching names that contain any significant word of the
+// reference, case-insensitively, so "Coffee Blue Bottle", "the Blue Bottle
+// by the park" and "Blu Bottle" all find Blue Bottle Coffee. Candidates are
+// then ranked with nameSimilarity.
+func buildNameSearchQuery(input ResolvePlaceReferenceInput) string {
+	tokens := significantNameTokens(input.Text)
+	for i, token := range tokens {
+		tokens[i] = regexp.QuoteMeta(token)
+	}
+	filter := fmt.Sprintf(`["name"~"%s",i]`, strings.Join(tokens, "|"))
+
+	var area string
+	if input.BBox != nil {
+		area = fmt.Sprintf("(%.6f,%.6f,%.6f,%.6f)", input.BBox.MinLat, input.BBox.MinLon, input.BBox.MaxLat, input.BBox.MaxLon)
+	} else {
+		area = fmt.Sprintf("(around:%.1f,%.6f,%.6f)", input.Radius, input.Near.Latitude, input.Near.Longitude)
+	}
+
+	return fmt.Sprintf(`[out:json][timeout:25];(node%[1]s%[2]s;way%[1]s%[2]s;);out center 50;`, filter, area)
+}
+
+// elementToPlace converts an Overpass element into a Place, reporting false for unnamed or unlocated elements
+func elementToPlace(element osm.OverpassElement) (Place, bool) {
+	name := element.Tags["name"]
+	if name == "" {
+		return Place{}, false
+	}
+
+	lat, lon := element.Lat, element.Lon
+	if element.Center != nil {
+		lat, lon = element.Center.Lat, element.Center.Lon
+	}
+	if lat == 0 && lon == 0 {
+		return Place{}, false
+	}
+
+	categories := []string{}
+	for _, key := range []string{"amenity", "shop", "tourism", "leisure", "railway", "public_transport"} {
+		if value := element.Tags[key]; value != "" {
+			if key == "amenity" {
+				categories = append(categories, value)
+			} else {
+				categories = append(categories, key+":"+value)
+			}
+		}
+	}
+
+	return Place{
+		ID:         element.Type + "/" + strconv.Itoa(element.ID),
+		Name:       name,
+		Location:   Location{Latitude: lat, Longitude: lon},
+		Categories: categories,
+		Contact:    parseContact(element.Tags),
+		Level:      placeLevel(element.Tags),
+	}, true
+}
+
+// scoreCandidate blends name, context and type scores into a 0..1 confidence
+func scoreCandidate(nameScore, contextScore, typeScore float64, hasTypeHint bool) float64 {
+	contextScore = clampUnit(contextScore)
+	var score float64
+	if hasTypeHint {
+		score = 0.6*nameScore + 0.25*contextScore + 0.15*typeScore
+	} else {
+		score = 0.7*nameScore + 0.3*contextScore
+	}
+	return float64(int(clampUnit(score)*1000)) / 1000
+}
+
+// typeHintScore returns 1 when any category matches the hint, 0 otherwise
+func typeHintScore(hint string, categories []string) float64 {
+	hint = strings.ToLower(strings.TrimSpace(hint))
+	if hint == "" {
+		return 0
+	}
+
+	for _, category := range categories {
+		category = strings.ToLower(category)
+		if strings.Contains(category, hint) {
+			return 1
+		}
+		for _, values := range mapCategoryToOSMTags(hint) {
+			for _, value := range values {
+				if value != "*" && strings.HasSuffix(category, value) {
+					return 1
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// bboxContains reports whether the location lies inside the bounding box
+func bboxContains(bbox *geo.BoundingBox, loc Location) bool {
+	return loc.Latitude >= bbox.MinLat && loc.Latitude <= bbox.MaxLat &&
+		loc.Longitude >= bbox.MinLon && loc.Longitude <= bbox.MaxLon
+}
+
+// nameSimilarity returns a 0..1 similarity between a reference and a feature name.
+// Exact and containment matches score highest, otherwise a normalised edit
+// distance over the lower-cased, punctuation-free strings is used.
+func nameSimilarity(reference, name string) float64 {
+	a := normalizeName(reference)
+	b := normalizeName(name)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	// Geocoder display names carry the full address; compare against the leading part
+	if idx := strings.Index(b, ","); idx > 0 {
+		b = strings.TrimSpace(b[:idx])
+	}
+	if a == b {
+		return 1
+	}
+	if strings.Contains(b, a) || strings.Contains(a, b) {
+		shorter, longer := len(a), len(b)
+		if shorter > longer {
+			shorter, longer = longer, shorter
+		}
+		return 0.8 + 0.2*float64(shorter)/float64(longer)
+	}
+
+	// Words in another order, e.g. "Bottle Blue", still score close to an
+	// exact match
+	return math.Max(editSimilarity(a, b), editSimilarity(sortedWords(a), sortedWords(b))-0.05)
+}
+
+// editSimilarity returns 1 minus the edit distance between two strings,
+// normalised by the longer one
+func editSimilarity(a, b string) float64 {
+	distance := levenshtein(a, b)
+	longest := len([]rune(a))
+	if l := len([]rune(b)); l > longest {
+		longest = l
+	}
+	return clampUnit(1 - float64(distance)/float64(longest))
+}
+
+// sortedWords returns the words of a normalised name in sorted order
+func sortedWords(s string) string {
+	words := strings.Fields(s)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// normalizeName lower-cases a name and strips punctuation
+func normalizeName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || r == ',' {
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// clampUnit clamps a value to the range [0, 1]
+func clampUnit(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		reference string
+		name      string
+		minScore  float64
+		maxScore  float64
+	}{
+		{"Blue Bottle Coffee", "Blue Bottle Coffee", 1, 1},
+		{"blue bottle", "Blue Bottle Coffee", 0.8, 0.99},
+		{"Ferry Building", "Ferry Building, The Embarcadero, San Francisco", 1, 1},
+		{"Starbuks", "Starbucks", 0.85, 0.95},
+		{"Coffee Blue Bottle", "Blue Bottle Coffee", 0.9, 0.99},
+		{"Louvre", "Golden Gate Park", 0, 0.3},
+		{"", "Anything", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reference+"/"+tt.name, func(t *testing.T) {
+			score := nameSimilarity(tt.reference, tt.name)
+			if score < tt.minScore || score > tt.maxScore {
+				t.Errorf("nameSimilarity(%q, %q) = %f, want between %f and %f",
+					tt.reference, tt.name, score, tt.minScore, tt.maxScore)
+			}
+		})
+	}
+}
+
+func TestBuildNameSearchQuery(t *testing.T) {
+	input := ResolvePlaceReferenceInput{
+		Text:   `The "Blue" Bottle coffee (Mission)`,
+		Near:   &geo.Location{Latitude: 37.76, Longitude: -122.42},
+		Radius: 500,
+	}
+	query := buildNameSearchQuery(input)
+
+	// Names containing any significant word are candidates
+	if !strings.Contains(query, `node["name"~"blue|bottle|coffee|mission",i](around:500.0,37.760000,-122.420000);`) {
+		t.Errorf("unexpected query: %s", query)
+	}
+	if strings.Contains(query, "the") || strings.Contains(query, "(Mission)") {
+		t.Errorf("expected stopwords and punctuation to be dropped: %s", query)
+	}
+
+	// The tool description's example searches for the place and landmark,
+	// not the words relating them
+	input.Text = "the Starbucks by the station"
+	if tokens := significantNameTokens(input.Text); strings.Join(tokens, " ") != "starbucks station" {
+		t.Errorf("unexpected tokens: %v", tokens)
+	}
+	if query := buildNameSearchQuery(input); !strings.Contains(query, `node["name"~"starbucks|station",i](`) {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	// A misspelled reference still matches on its longer words
+	if tokens := significantNameTokens("Starbuck's café"); strings.Join(tokens, " ") != "starbuck café" {
+		t.Errorf("unexpected tokens: %v", tokens)
+	}
+
+	// A reference of stopwords only still searches for them
+	if tokens := significantNameTokens("The The"); len(tokens) != 2 || tokens[0] != "the" {
+		t.Errorf("unexpected tokens: %v", tokens)
+	}
+}
+
+func TestHandleResolvePlaceReferenceFromContext(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "resolve_place_reference",
+			Arguments: map[string]any{
+				"text": "Tartine Bakery",
+				"previous_results": []any{
+					map[string]any{
+						"id":         "node/1",
+						"name":       "Tartine Bakery",
+						"location":   map[string]any{"latitude": 37.7614, "longitude": -122.4241},
+						"categories": []any{"shop:bakery"},
+					},
+					map[string]any{
+						"id":       "node/2",
+						"name":     "Bi-Rite Creamery",
+						"location": map[string]any{"latitude": 37.7617, "longitude": -122.4258},
+					},
+				},
+				"type_hint": "bakery",
+			},
+		},
+	}
+
+	result, err := HandleResolvePlaceReference(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	AssertSuccessResult(t, result, "Expected context match to resolve")
+
+	var output ResolvePlaceReferenceOutput
+	if err := ParseResultJSON(result, &output); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if output.Best == nil || output.Best.Place.ID != "node/1" {
+		t.Fatalf("Expected node/1 as best match, got %+v", output.Best)
+	}
+	if output.Best.Source != "context" {
+		t.Errorf("Expected source context, got %s", output.Best.Source)
+	}
+	if output.Best.Confidence < 0.9 {
+		t.Errorf("Expected high confidence, got %f", output.Best.Confidence)
+	}
+}
+
+func TestHandleResolvePlaceReferenceValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+	}{
+		{"Empty text", map[string]any{"text": "  "}},
+		{"Invalid near", map[string]any{"text": "cafe", "near": map[string]any{"latitude": 100.0, "longitude": 0.0}}},
+		{"Inverted bbox", map[string]any{"text": "cafe", "bbox": map[string]any{"minLat": 2.0, "minLon": 0.0, "maxLat": 1.0, "maxLon": 1.0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{Name: "resolve_place_reference", Arguments: tt.arguments},
+			}
+			result, err := HandleResolvePlaceReference(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			AssertErrorResult(t, result, "Expected validation error")
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// maxBatchPoints caps the points reverse geocoded in one call, at about one
+// a second under the Nominatim usage policy
+const maxBatchPoints = 60
+
+// BatchAddress is the address of one point of a batch
+type BatchAddress struct {
+	Index    int          `json:"index"`
+	Location geo.Location `json:"location"`
+	Place    *Place       `json:"place,omitempty"` // nil when the point was not resolved
+}
+
+// ReverseGeocodeBatchOutput defines the output for reverse_geocode_batch
+type ReverseGeocodeBatchOutput struct {
+	Results    []BatchAddress `json:"results"`
+	Resolved   int            `json:"resolved"`
+	Requests   int            `json:"requests"` // points sent to Nominatim; the rest were cached
+	Incomplete bool           `json:"incomplete,omitempty"`
+}
+
+// reverseGeocodePlace looks up the address of a point; tests replace it to
+// avoid the network
+var reverseGeocodePlace = fetchReversePlace
+
+// ReverseGeocodeBatchTool returns a tool definition for reverse geocoding
+// a list of points
+func ReverseGeocodeBatchTool() mcp.Tool {
+	return mcp.NewTool("reverse_geocode_batch",
+		mcp.WithDescription(fmt.Sprintf("Get the address of each of a list of points, e.g. to annotate points sampled with route_sample. Requests are spaced within the Nominatim rate limit (about one uncached point per second, at most %d points) and share the reverse_geocode cache. Returns what was resolved if the request times out", maxBatchPoints)),
+		mcp.WithArray("points",
+			mcp.Description("Points to reverse geocode, each with latitude and longitude. Give either points or polyline"),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithString("polyline",
+			mcp.Description("An encoded polyline whose vertices are reverse geocoded"),
+		),
+	)
+}
+
+// HandleReverseGeocodeBatch reverse geocodes each point of a list
+func HandleReverseGeocodeBatch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "reverse_geocode_batch")
+
+	points, errResult := parseTrackPoints(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if len(points) > maxBatchPoints {
+		return core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("At most %d points can be reverse geocoded at once, got %d", maxBatchPoints, len(points))).
+			WithGuidance("Sample the route with route_sample, or use reverse_geocode_track for the localities along a long track").
+			ToMCPResult(), nil
+	}
+
+	initCaches()
+	output := ReverseGeocodeBatchOutput{Results: make([]BatchAddress, len(points))}
+	for i, point := range points {
+		output.Results[i] = BatchAddress{Index: i, Location: point}
+	}
+
+	for i, point := range points {
+		key := reverseGeoCacheKey(point.Latitude, point.Longitude)
+
+		var cachedOutput ReverseGeocodeOutput
+		cached, found := reverseGeocodeCache.Get(key)
+		if found {
+			found = json.Unmarshal(cached.([]byte), &cachedOutput) == nil
+		}
+		place := cachedOutput.Place
+		if !found {
+			if ctx.Err() != nil {
+				output.Incomplete = true
+				break
+			}
+			var err error
+			place, err = reverseGeocodePlace(ctx, point.Latitude, point.Longitude)
+			output.Requests++
+			if err != nil {
+				if ctx.Err() != nil {
+					output.Incomplete = true
+					break
+				}
+				logger.Error("failed to reverse geocode point", "index", i, "error", err)
+				if output.Resolved == 0 {
+					if mcpErr, ok := err.(*core.MCPError); ok {
+						return mcpErr.ToMCPResult(), nil
+					}
+					return core.ServiceError("Nominatim", http.StatusServiceUnavailable,
+						"Failed to reverse geocode the points").ToMCPResult(), nil
+				}
+				// Keep what was resolved, e.g. when the rate limit is exceeded
+				output.Incomplete = true
+				break
+			}
+			// Cached in the form reverse_geocode uses, so the tools share entries
+			if data, err := json.Marshal(ReverseGeocodeOutput{Place: place}); err == nil {
+				reverseGeocodeCache.SetFor(cache.ClassReverseGeocode, key, data)
+			}
+		}
+
+		output.Results[i].Place = &place
+		output.Resolved++
+	}
+
+	logger.Info("reverse geocoded points", "points", len(points), "requests", output.Requests, "resolved", output.Resolved)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleReverseGeocodeBatch(t *testing.T) {
+	initCaches()
+	reverseGeocodeCache.Clear()
+
+	calls := 0
+	reverseGeocodePlace = func(ctx context.Context, latitude, longitude float64) (Place, error) {
+		calls++
+		if calls > 2 {
+			return Place{}, errors.New("rate limited")
+		}
+		return Place{Name: "Street", Location: Location{Latitude: latitude, Longitude: longitude}}, nil
+	}
+	defer func() { reverseGeocodePlace = fetchReversePlace }()
+
+	point := func(lat, lon float64) any { return map[string]any{"latitude": lat, "longitude": lon} }
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"points": []any{point(1, 1), point(2, 2), point(1, 1), point(3, 3)}}
+
+	result, err := HandleReverseGeocodeBatch(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %+v", err, result)
+	}
+	var output ReverseGeocodeBatchOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+
+	// The repeated point is cached, and the failure keeps what was resolved
+	if output.Resolved != 3 || output.Requests != 3 || !output.Incomplete {
+		t.Errorf("expected 3 resolved points from 3 requests, got %+v", output)
+	}
+	if len(output.Results) != 4 || output.Results[2].Place == nil || output.Results[3].Place != nil {
+		t.Errorf("unexpected results %+v", output.Results)
+	}
+
+	// reverse_geocode shares the cache
+	req.Params.Arguments = map[string]any{"latitude": 2.0, "longitude": 2.0}
+	result, _ = HandleReverseGeocode(context.Background(), req)
+	if result.IsError || calls != 3 {
+		t.Errorf("expected a cached reverse_geocode result, got %d lookups", calls)
+	}
+}
+
+func TestHandleReverseGeocodeBatchLimit(t *testing.T) {
+	points := make([]any, maxBatchPoints+1)
+	for i := range points {
+		points[i] = map[string]any{"latitude": 1.0, "longitude": 1.0}
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"points": points}
+
+	result, err := HandleReverseGeocodeBatch(context.Background(), req)
+	if err != nil || !result.IsError {
+		t.Errorf("expected an error for too many points, got %v %+v", err, result)
+	}
+}
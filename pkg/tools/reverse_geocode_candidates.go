@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// defaultCandidateRadius is the default search radius in meters
+	defaultCandidateRadius = 50.0
+	// maxCandidateRadius caps the search radius in meters
+	maxCandidateRadius = 500.0
+	// defaultCandidateLimit is the default number of candidates returned
+	defaultCandidateLimit = 5
+	// maxCandidateLimit caps the number of candidates returned
+	maxCandidateLimit = 20
+	// candidateRadiusGrowth widens the search once when nothing is found
+	candidateRadiusGrowth = 4.0
+)
+
+// poiCandidateKeys are the tags whose named features are offered as candidates
+var poiCandidateKeys = []string{"amenity", "shop", "tourism", "office", "leisure", "craft"}
+
+// ReverseGeocodeCandidate is one address or POI near the queried point
+type ReverseGeocodeCandidate struct {
+	Place
+	Kind string `json:"kind"` // address or poi
+}
+
+// ReverseGeocodeCandidatesOutput defines the output for reverse_geocode_candidates
+type ReverseGeocodeCandidatesOutput struct {
+	Candidates []ReverseGeocodeCandidate `json:"candidates"`
+	Radius     float64                   `json:"radius"` // meters actually searched
+}
+
+// ReverseGeocodeCandidatesTool returns a tool definition for multi-result reverse geocoding
+func ReverseGeocodeCandidatesTool() mcp.Tool {
+	return mcp.NewTool("reverse_geocode_candidates",
+		mcp.WithDescription("List the addresses and named places nearest to a coordinate with their distances, instead of the single best match from reverse_geocode. Use when GPS accuracy is low: set radius to the accuracy and let the user or workflow pick. "+orderingDescription),
+		mcp.WithNumber("latitude",
+			mcp.Required(),
+			mcp.Description("The latitude coordinate as a decimal between -90 and 90"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Required(),
+			mcp.Description("The longitude coordinate as a decimal between -180 and 180"),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("Search radius in meters, e.g. the GPS accuracy (max 500). Widened once if nothing is found"),
+			mcp.DefaultNumber(defaultCandidateRadius),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of candidates to return (max 20)"),
+			mcp.DefaultNumber(defaultCandidateLimit),
+		),
+		mcp.WithBoolean("include_pois",
+			mcp.Description("Include named places such as shops and restaurants, not just street addresses"),
+			mcp.DefaultBool(true),
+		),
+	)
+}
+
+// HandleReverseGeocodeCandidates returns the addresses and POIs nearest to a point
+func HandleReverseGeocodeCandidates(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "reverse_geocode_candidates")
+
+	lat, lon, err := core.ParseCoordsWithLog(req, logger, "latitude", "longitude")
+	if err != nil {
+		return core.NewError(core.ErrInvalidInput, err.Error()).ToMCPResult(), nil
+	}
+
+	radius := mcp.ParseFloat64(req, "radius", defaultCandidateRadius)
+	if err := core.ValidateRadius(radius, maxCandidateRadius); err != nil {
+		return core.NewError(core.ErrInvalidRadius, err.Error()).ToMCPResult(), nil
+	}
+
+	limit := int(mcp.ParseFloat64(req, "limit", defaultCandidateLimit))
+	if limit <= 0 || limit > maxCandidateLimit {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Limit must be between 1 and %d", maxCandidateLimit)).ToMCPResult(), nil
+	}
+
+	includePOIs := mcp.ParseBoolean(req, "include_pois", true)
+
+	var candidates []ReverseGeocodeCandidate
+	for {
+		elements, err := executeOverpassQuery(ctx, buildCandidateQuery(lat, lon, radius, includePOIs))
+		if err != nil {
+			logger.Error("failed to query nearby addresses", "error", err)
+			if mcpErr, ok := err.(*core.MCPError); ok {
+				return mcpErr.ToMCPResult(), nil
+			}
+			return core.ServiceError("Overpass", http.StatusServiceUnavailable,
+				"Failed to search nearby addresses").ToMCPResult(), nil
+		}
+
+		candidates = elementsToCandidates(elements, lat, lon, radius, includePOIs)
+		if len(candidates) > 0 || radius >= maxCandidateRadius {
+			break
+		}
+		radius = math.Min(radius*candidateRadiusGrowth, maxCandidateRadius)
+		logger.Info("no candidates found, widening search", "radius", radius)
+	}
+
+	sortByDistanceThenID(candidates, func(c ReverseGeocodeCandidate) (float64, string) {
+		return c.Distance, c.ID
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	output := ReverseGeocodeCandidatesOutput{
+		Candidates: candidates,
+		Radius:     radius,
+	}
+	if output.Candidates == nil {
+		output.Candidates = []ReverseGeocodeCandidate{}
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// buildCandidateQuery finds addressed features, and optionally named POIs,
+// around a point
+func buildCandidateQuery(lat, lon, radius float64, includePOIs bool) string {
+	around := fmt.Sprintf("(around:%.1f,%.6f,%.6f)", radius, lat, lon)
+
+	var query strings.Builder
+	query.WriteString("[out:json][timeout:25];(")
+	fmt.Fprintf(&query, `nwr["addr:housenumber"]%s;`, around)
+	if includePOIs {
+		for _, key := range poiCandidateKeys {
+			fmt.Fprintf(&query, `nwr["name"]["%s"]%s;`, key, around)
+		}
+	}
+	query.WriteString(");out center 200;")
+	return query.String()
+}
+
+// elementsToCandidates converts Overpass elements into candidates within the
+// radius. Named POIs are reported as "poi", other features as "address".
+func elementsToCandidates(elements []osm.OverpassElement, lat, lon, radius float64, includePOIs bool) []ReverseGeocodeCandidate {
+	candidates := make([]ReverseGeocodeCandidate, 0, len(elements))
+	for _, element := range elements {
+		elemLat, elemLon := element.Lat, element.Lon
+		if element.Center != nil {
+			elemLat, elemLon = element.Center.Lat, element.Center.Lon
+		}
+		if elemLat == 0 && elemLon == 0 {
+			continue
+		}
+
+		distance := geo.HaversineDistance(lat, lon, elemLat, elemLon)
+		if distance > radius {
+			continue
+		}
+
+		address := addressFromTags(element.Tags)
+		kind := "address"
+		name := address.Formatted
+		if place, ok := elementToPlace(element); ok && includePOIs && len(place.Categories) > 0 {
+			kind = "poi"
+			name = place.Name
+		}
+		if name == "" {
+			continue
+		}
+
+		candidates = append(candidates, ReverseGeocodeCandidate{
+			Place: Place{
+				ID:       fmt.Sprintf("%s/%d", element.Type, element.ID),
+				Name:     name,
+				Location: Location{Latitude: elemLat, Longitude: elemLon},
+				Address:  address,
+				Distance: distance,
+			},
+			Kind: kind,
+		})
+	}
+	return candidates
+}
+
+// addressFromTags builds an address from OSM addr:* tags
+func addressFromTags(tags map[string]string) Address {
+	address := Address{
+		Street:      tags["addr:street"],
+		HouseNumber: tags["addr:housenumber"],
+		City:        tags["addr:city"],
+		State:       tags["addr:state"],
+		Country:     tags["addr:country"],
+		PostalCode:  tags["addr:postcode"],
+	}
+
+	street := strings.TrimSpace(address.HouseNumber + " " + address.Street)
+	locality := strings.TrimSpace(address.PostalCode + " " + address.City)
+	parts := make([]string, 0, 2)
+	for _, part := range []string{street, locality} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	address.Formatted = strings.Join(parts, ", ")
+	return address
+}
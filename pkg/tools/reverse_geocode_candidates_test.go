@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestElementsToCandidates(t *testing.T) {
+	elements := []osm.OverpassElement{
+		{ID: 1, Type: "node", Lat: 52.52001, Lon: 13.40500, Tags: map[string]string{
+			"addr:housenumber": "12", "addr:street": "Unter den Linden", "addr:postcode": "10117", "addr:city": "Berlin",
+		}},
+		{ID: 2, Type: "node", Lat: 52.52010, Lon: 13.40500, Tags: map[string]string{
+			"name": "Café Einstein", "amenity": "cafe", "addr:housenumber": "42", "addr:street": "Unter den Linden",
+		}},
+		// Outside the radius
+		{ID: 3, Type: "node", Lat: 52.53000, Lon: 13.40500, Tags: map[string]string{
+			"addr:housenumber": "1", "addr:street": "Far Street",
+		}},
+		// No address or name
+		{ID: 4, Type: "node", Lat: 52.52000, Lon: 13.40500, Tags: map[string]string{"building": "yes"}},
+	}
+
+	candidates := elementsToCandidates(elements, 52.52, 13.405, 50, true)
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %d: %+v", len(candidates), candidates)
+	}
+
+	if candidates[0].Kind != "address" || candidates[0].Name != "12 Unter den Linden, 10117 Berlin" {
+		t.Errorf("unexpected address candidate: %+v", candidates[0])
+	}
+	if candidates[1].Kind != "poi" || candidates[1].Name != "Café Einstein" || candidates[1].Address.HouseNumber != "42" {
+		t.Errorf("unexpected POI candidate: %+v", candidates[1])
+	}
+	if candidates[0].Distance >= candidates[1].Distance {
+		t.Errorf("expected the address to be closer: %f >= %f", candidates[0].Distance, candidates[1].Distance)
+	}
+
+	// Without POIs, the café is reported by its address
+	candidates = elementsToCandidates(elements, 52.52, 13.405, 50, false)
+	if len(candidates) != 2 || candidates[1].Kind != "address" || candidates[1].Name != "42 Unter den Linden" {
+		t.Errorf("unexpected candidates without POIs: %+v", candidates)
+	}
+}
+
+func TestBuildCandidateQuery(t *testing.T) {
+	query := buildCandidateQuery(52.52, 13.405, 50, false)
+	if !strings.Contains(query, `nwr["addr:housenumber"](around:50.0,52.520000,13.405000);`) {
+		t.Errorf("missing address clause: %s", query)
+	}
+	if strings.Contains(query, `["name"]`) {
+		t.Errorf("expected no POI clauses: %s", query)
+	}
+
+	query = buildCandidateQuery(52.52, 13.405, 50, true)
+	if !strings.Contains(query, `nwr["name"]["shop"]`) {
+		t.Errorf("missing POI clauses: %s", query)
+	}
+}
@@ -0,0 +1,341 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// defaultTrackSamples is the default number of points reverse geocoded
+	defaultTrackSamples = 20
+	// maxTrackSamples caps the points reverse geocoded, at about one a
+	// second under the Nominatim usage policy
+	maxTrackSamples = 60
+	// minTrackInterval is the smallest spacing between samples in meters
+	minTrackInterval = 250.0
+	// localityZoom is the Nominatim reverse zoom for city, town and village
+	// level results
+	localityZoom = 13
+)
+
+// localityAddressKeys are the Nominatim address fields naming a locality,
+// most specific first
+var localityAddressKeys = []string{"city", "town", "village", "hamlet", "municipality", "suburb", "county", "state"}
+
+// TrackLocality is a locality a track passes through
+type TrackLocality struct {
+	Name          string       `json:"name"`
+	Type          string       `json:"type"` // the address field, e.g. city or village
+	State         string       `json:"state,omitempty"`
+	Country       string       `json:"country,omitempty"`
+	FirstSample   int          `json:"first_sample"`   // index of the first sample in this locality
+	DistanceAlong float64      `json:"distance_along"` // meters from the start to that sample
+	Location      geo.Location `json:"location"`       // the first sample in this locality
+}
+
+// ReverseGeocodeTrackOutput defines the output for reverse_geocode_track
+type ReverseGeocodeTrackOutput struct {
+	Localities []TrackLocality `json:"localities"`
+	Summary    string          `json:"summary"`
+	Samples    int             `json:"samples"`
+	Interval   float64         `json:"interval"` // meters between samples
+	Requests   int             `json:"requests"` // samples sent to Nominatim; the rest were cached
+	Incomplete bool            `json:"incomplete,omitempty"`
+}
+
+// locality is a reverse geocoded sample, as cached
+type locality struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	State   string `json:"state,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// reverseGeocodeLocality looks up the locality of a point; tests replace it
+// to avoid the network
+var reverseGeocodeLocality = fetchLocality
+
+// ReverseGeocodeTrackTool returns a tool definition for naming the
+// localities along a track
+func ReverseGeocodeTrackTool() mcp.Tool {
+	return mcp.NewTool("reverse_geocode_track",
+		mcp.WithDescription(fmt.Sprintf("List the towns and cities a route or GPS track passes through, in order, e.g. \"passes through A, B, C\". Samples the track evenly and reverse geocodes each sample at locality level within the Nominatim rate limit (about one uncached sample per second, at most %d samples), caching results. Returns what was resolved if the request times out", maxTrackSamples)),
+		mcp.WithString("polyline",
+			mcp.Description("The encoded polyline of the track. Give either polyline or points"),
+		),
+		mcp.WithArray("points",
+			mcp.Description("Track points in order, each with latitude and longitude"),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithNumber("interval",
+			mcp.Description(fmt.Sprintf("Meters between samples (min %.0f). Defaults to spreading max_samples over the track; widened if it would exceed max_samples", minTrackInterval)),
+		),
+		mcp.WithNumber("max_samples",
+			mcp.Description(fmt.Sprintf("Maximum number of points to reverse geocode (2-%d)", maxTrackSamples)),
+			mcp.DefaultNumber(defaultTrackSamples),
+		),
+	)
+}
+
+// HandleReverseGeocodeTrack names the localities along a track
+func HandleReverseGeocodeTrack(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "reverse_geocode_track")
+
+	points, errResult := parseTrackPoints(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	maxSamples := int(mcp.ParseFloat64(req, "max_samples", defaultTrackSamples))
+	if maxSamples < 2 || maxSamples > maxTrackSamples {
+		return core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("max_samples must be between 2 and %d", maxTrackSamples)).ToMCPResult(), nil
+	}
+	interval := mcp.ParseFloat64(req, "interval", 0)
+	if interval < 0 {
+		return core.NewError(core.ErrInvalidParameter, "interval must be positive").ToMCPResult(), nil
+	}
+
+	samples, interval := trackSamples(points, interval, maxSamples)
+
+	initCaches()
+	output := ReverseGeocodeTrackOutput{
+		Samples:  len(samples),
+		Interval: interval,
+	}
+	distances := cumulativeDistances(samples)
+	for i, sample := range samples {
+		key := "locality:" + reverseGeoCacheKey(sample.Latitude, sample.Longitude)
+
+		var loc locality
+		cached, found := reverseGeocodeCache.Get(key)
+		if found {
+			found = json.Unmarshal(cached.([]byte), &loc) == nil
+		}
+		if !found {
+			if ctx.Err() != nil {
+				output.Incomplete = true
+				break
+			}
+			var err error
+			loc, err = reverseGeocodeLocality(ctx, sample)
+			output.Requests++
+			if err != nil {
+				if ctx.Err() != nil {
+					output.Incomplete = true
+					break
+				}
+				logger.Error("failed to reverse geocode sample", "index", i, "error", err)
+				if len(output.Localities) == 0 {
+					if mcpErr, ok := err.(*core.MCPError); ok {
+						return mcpErr.ToMCPResult(), nil
+					}
+					return core.ServiceError("Nominatim", http.StatusServiceUnavailable,
+						"Failed to reverse geocode the track").ToMCPResult(), nil
+				}
+				// Keep what was resolved, e.g. when the rate limit is exceeded
+				output.Incomplete = true
+				break
+			}
+			if data, err := json.Marshal(loc); err == nil {
+				reverseGeocodeCache.SetFor(cache.ClassReverseGeocode, key, data)
+			}
+		}
+
+		output.Localities = appendLocality(output.Localities, loc, i, distances[i], sample)
+	}
+
+	if output.Localities == nil {
+		output.Localities = []TrackLocality{}
+	}
+	output.Summary = trackSummary(output.Localities, output.Incomplete)
+
+	logger.Info("reverse geocoded track", "samples", output.Samples, "requests", output.Requests, "localities", len(output.Localities))
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// parseTrackPoints reads the track from the polyline or points parameter
+func parseTrackPoints(req mcp.CallToolRequest) ([]geo.Location, *mcp.CallToolResult) {
+	if polyline := mcp.ParseString(req, "polyline", ""); polyline != "" {
+		if !isPrintableASCII(polyline) {
+			return nil, core.NewError(core.ErrInvalidParameter, "Failed to decode polyline: malformed input").ToMCPResult()
+		}
+		points := osm.DecodePolyline(polyline)
+		if len(points) == 0 {
+			return nil, core.NewError(core.ErrInvalidParameter, "Failed to decode polyline: malformed input").ToMCPResult()
+		}
+		return points, nil
+	}
+
+	raw, ok := req.GetArguments()["points"].([]any)
+	if !ok || len(raw) == 0 {
+		return nil, core.NewError(core.ErrMissingParameter, "polyline or points is required").
+			WithGuidance("Pass an encoded polyline, or points as [{\"latitude\": 51.5, \"longitude\": -0.12}, ...]").
+			ToMCPResult()
+	}
+	points := make([]geo.Location, 0, len(raw))
+	for i, item := range raw {
+		obj, _ := item.(map[string]any)
+		lat, latOK := obj["latitude"].(float64)
+		lon, lonOK := obj["longitude"].(float64)
+		if !latOK || !lonOK || core.ValidateCoords(lat, lon) != nil {
+			return nil, core.NewError(core.ErrInvalidParameter,
+				fmt.Sprintf("points[%d] must have a valid latitude and longitude", i)).ToMCPResult()
+		}
+		points = append(points, geo.Location{Latitude: lat, Longitude: lon})
+	}
+	return points, nil
+}
+
+// trackSamples spaces up to maxSamples points evenly along a track,
+// including both ends, and returns them with the interval used
+func trackSamples(points []geo.Location, interval float64, maxSamples int) ([]geo.Location, float64) {
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		length += geo.HaversineDistance(points[i-1].Latitude, points[i-1].Longitude, points[i].Latitude, points[i].Longitude)
+	}
+	if length == 0 {
+		return points[:1], 0
+	}
+
+	// Widen the interval so the samples, plus the end point, fit
+	minInterval := math.Max(minTrackInterval, length/float64(maxSamples-1))
+	if interval < minInterval {
+		interval = minInterval
+	}
+
+	samples := samplePolylinePoints(points, interval)
+	if len(samples) > maxSamples-1 {
+		samples = samples[:maxSamples-1]
+	}
+	end := points[len(points)-1]
+	if last := samples[len(samples)-1]; last != end {
+		samples = append(samples, end)
+	}
+	return samples, interval
+}
+
+// cumulativeDistances returns the distance from the first point to each
+// point along a path
+func cumulativeDistances(points []geo.Location) []float64 {
+	distances := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		distances[i] = distances[i-1] + geo.HaversineDistance(points[i-1].Latitude, points[i-1].Longitude, points[i].Latitude, points[i].Longitude)
+	}
+	return distances
+}
+
+// appendLocality adds a sample's locality unless the track is still in the
+// previous one. Samples without a locality, e.g. at sea, are skipped.
+func appendLocality(localities []TrackLocality, loc locality, index int, distance float64, at geo.Location) []TrackLocality {
+	if loc.Name == "" {
+		return localities
+	}
+	if n := len(localities); n > 0 && localities[n-1].Name == loc.Name && localities[n-1].State == loc.State {
+		return localities
+	}
+	return append(localities, TrackLocality{
+		Name:          loc.Name,
+		Type:          loc.Type,
+		State:         loc.State,
+		Country:       loc.Country,
+		FirstSample:   index,
+		DistanceAlong: math.Round(distance),
+		Location:      at,
+	})
+}
+
+// trackSummary describes the localities in a sentence
+func trackSummary(localities []TrackLocality, incomplete bool) string {
+	if len(localities) == 0 {
+		return "No named localities found along the track"
+	}
+	names := make([]string, len(localities))
+	for i, loc := range localities {
+		names[i] = loc.Name
+	}
+	summary := "Passes through " + strings.Join(names, ", ")
+	if incomplete {
+		summary += " (incomplete: the rest of the track was not resolved in time)"
+	}
+	return summary
+}
+
+// localityFromAddress picks the most specific locality in a Nominatim address
+func localityFromAddress(address map[string]string) locality {
+	loc := locality{State: address["state"], Country: address["country"]}
+	for _, key := range localityAddressKeys {
+		if name := address[key]; name != "" {
+			loc.Name, loc.Type = name, key
+			break
+		}
+	}
+	if loc.Type == "state" {
+		loc.State = ""
+	}
+	return loc
+}
+
+// fetchLocality reverse geocodes a point at locality level. Requests go
+// through the shared client, which waits on the Nominatim rate limiter.
+func fetchLocality(ctx context.Context, at geo.Location) (locality, error) {
+	reqURL, err := url.Parse(fmt.Sprintf("%s/reverse", osm.NominatimBaseURL))
+	if err != nil {
+		return locality{}, core.NewError(core.ErrInternalError, "Failed to parse URL for geocoding service")
+	}
+	q := reqURL.Query()
+	q.Add("lat", fmt.Sprintf("%f", at.Latitude))
+	q.Add("lon", fmt.Sprintf("%f", at.Longitude))
+	q.Add("format", "json")
+	q.Add("zoom", fmt.Sprintf("%d", localityZoom))
+	q.Add("addressdetails", "1")
+	reqURL.RawQuery = q.Encode()
+
+	requestFactory := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		return req, nil
+	}
+
+	resp, err := core.WithRetryFactory(ctx, requestFactory, osm.GetClient(ctx), core.DefaultRetryOptions)
+	if err != nil {
+		return locality{}, core.ServiceError("Nominatim", http.StatusServiceUnavailable, "Failed to communicate with geocoding service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return locality{}, core.ServiceError("Nominatim", resp.StatusCode, fmt.Sprintf("Geocoding service error: %d", resp.StatusCode))
+	}
+
+	var result struct {
+		Error   string            `json:"error"` // e.g. "Unable to geocode" at sea
+		Address map[string]string `json:"address"`
+	}
+	if err := core.DecodeUpstream("Nominatim", resp.Body, &result); err != nil {
+		return locality{}, err
+	}
+	return localityFromAddress(result.Address), nil
+}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestLocalityFromAddress(t *testing.T) {
+	loc := localityFromAddress(map[string]string{"village": "Grantchester", "county": "Cambridgeshire", "state": "England", "country": "United Kingdom"})
+	if loc.Name != "Grantchester" || loc.Type != "village" || loc.State != "England" {
+		t.Errorf("unexpected locality %+v", loc)
+	}
+
+	// Only a state is known, so it is the locality
+	loc = localityFromAddress(map[string]string{"state": "Nevada", "country": "United States"})
+	if loc.Name != "Nevada" || loc.Type != "state" || loc.State != "" {
+		t.Errorf("unexpected locality %+v", loc)
+	}
+
+	if loc := localityFromAddress(nil); loc.Name != "" {
+		t.Errorf("expected no locality at sea, got %+v", loc)
+	}
+}
+
+func TestTrackSamples(t *testing.T) {
+	// About 111 km due north
+	track := []geo.Location{{Latitude: 0, Longitude: 0}, {Latitude: 1, Longitude: 0}}
+
+	samples, interval := trackSamples(track, 1000, 10)
+	if len(samples) != 10 {
+		t.Fatalf("expected 10 samples, got %d", len(samples))
+	}
+	if interval < 12000 {
+		t.Errorf("expected the interval to widen to fit, got %.0f", interval)
+	}
+	if samples[0] != track[0] || samples[len(samples)-1] != track[1] {
+		t.Errorf("expected both ends to be sampled, got %v and %v", samples[0], samples[len(samples)-1])
+	}
+
+	if samples, _ := trackSamples([]geo.Location{track[0], track[0]}, 0, 10); len(samples) != 1 {
+		t.Errorf("expected one sample for a zero-length track, got %d", len(samples))
+	}
+}
+
+func TestHandleReverseGeocodeTrack(t *testing.T) {
+	initCaches()
+	reverseGeocodeCache.Clear()
+
+	// Localities by latitude band, with the sea in the middle
+	lookups := 0
+	reverseGeocodeLocality = func(ctx context.Context, at geo.Location) (locality, error) {
+		lookups++
+		switch {
+		case at.Latitude < 0.3:
+			return locality{Name: "Alpha", Type: "town"}, nil
+		case at.Latitude < 0.5:
+			return locality{}, nil
+		case at.Latitude < 0.8:
+			return locality{Name: "Bravo", Type: "city"}, nil
+		default:
+			return locality{Name: "Charlie", Type: "village"}, nil
+		}
+	}
+	defer func() { reverseGeocodeLocality = fetchLocality }()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"points": []any{
+			map[string]any{"latitude": 0.0, "longitude": 0.0},
+			map[string]any{"latitude": 1.0, "longitude": 0.0},
+		},
+		"max_samples": 11.0,
+	}
+
+	result, err := HandleReverseGeocodeTrack(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %+v", err, result)
+	}
+	var output ReverseGeocodeTrackOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, loc := range output.Localities {
+		names = append(names, loc.Name)
+	}
+	if len(names) != 3 || names[0] != "Alpha" || names[1] != "Bravo" || names[2] != "Charlie" {
+		t.Errorf("expected Alpha, Bravo, Charlie, got %v", names)
+	}
+	if output.Summary != "Passes through Alpha, Bravo, Charlie" {
+		t.Errorf("unexpected summary %q", output.Summary)
+	}
+	if output.Samples != 11 || output.Requests != 11 || lookups != 11 {
+		t.Errorf("expected 11 samples and requests, got %d, %d and %d lookups", output.Samples, output.Requests, lookups)
+	}
+
+	// A second run is served from the cache
+	result, _ = HandleReverseGeocodeTrack(context.Background(), req)
+	output = ReverseGeocodeTrackOutput{}
+	_ = json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output)
+	if output.Requests != 0 || lookups != 11 {
+		t.Errorf("expected cached localities, got %d requests", output.Requests)
+	}
+}
+
+func TestHandleReverseGeocodeTrackPartial(t *testing.T) {
+	initCaches()
+	reverseGeocodeCache.Clear()
+
+	calls := 0
+	reverseGeocodeLocality = func(ctx context.Context, at geo.Location) (locality, error) {
+		calls++
+		if calls > 2 {
+			return locality{}, errors.New("rate limited")
+		}
+		return locality{Name: "Alpha", Type: "town"}, nil
+	}
+	defer func() { reverseGeocodeLocality = fetchLocality }()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"polyline": "_p~iF~ps|U_ulLnnqC_mqNvxq`@"}
+
+	result, err := HandleReverseGeocodeTrack(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %+v", err, result)
+	}
+	var output ReverseGeocodeTrackOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	if !output.Incomplete || len(output.Localities) != 1 {
+		t.Errorf("expected a partial result, got %+v", output)
+	}
+
+	// Failing on the first sample is an error
+	reverseGeocodeCache.Clear()
+	calls = 2
+	if result, _ := HandleReverseGeocodeTrack(context.Background(), req); !result.IsError {
+		t.Error("expected an error when nothing resolves")
+	}
+
+	req.Params.Arguments = map[string]any{}
+	if result, _ := HandleReverseGeocodeTrack(context.Background(), req); !result.IsError {
+		t.Error("expected an error without a track")
+	}
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// Route geometry formats for the geometry_precision argument
+const (
+	GeometryPolyline5 = "polyline5" // encoded polyline, 5 decimal places (~1 m)
+	GeometryPolyline6 = "polyline6" // encoded polyline, 6 decimal places (~0.1 m)
+	GeometryGeoJSON   = "geojson"   // GeoJSON LineString
+	GeometryNone      = "none"      // distance and duration only
+)
+
+// geometryFormats lists the geometry_precision values
+var geometryFormats = []string{GeometryPolyline5, GeometryPolyline6, GeometryGeoJSON, GeometryNone}
+
+// maxRouteGeometryPoints bounds max_points
+const maxRouteGeometryPoints = 10000
+
+// simplifyTolerance is the first tolerance in meters tried when
+// simplifying a route to max_points
+const simplifyTolerance = 1.0
+
+const geometryPrecisionDescription = "Route geometry format: polyline5 (default), polyline6 (more precise), geojson (LineString) or none to return only distance and duration"
+
+const maxPointsDescription = "Simplify the route geometry to at most this many points (0 keeps every point)"
+
+// RouteGeometry is a route's geometry in the format chosen with
+// geometry_precision
+type RouteGeometry struct {
+	Polyline       string             `json:"polyline,omitempty"`
+	GeoJSON        *GeoJSONLineString `json:"geojson,omitempty"`
+	GeometryFormat string             `json:"geometry_format,omitempty"`
+	PointCount     int                `json:"point_count,omitempty"`
+}
+
+// GeoJSONLineString is a GeoJSON LineString geometry
+type GeoJSONLineString struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"` // [longitude, latitude] pairs
+}
+
+// routeGeometry is how a routing tool returns geometry
+type routeGeometry struct {
+	format    string
+	maxPoints int
+}
+
+// newRouteGeometry validates the geometry_precision and max_points
+// arguments; an empty format is polyline5
+func newRouteGeometry(format string, maxPoints int) (routeGeometry, *core.MCPError) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = GeometryPolyline5
+	}
+	if !slices.Contains(geometryFormats, format) {
+		return routeGeometry{}, core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid geometry_precision: %s", format)).
+			WithGuidance("Use " + strings.Join(geometryFormats, ", "))
+	}
+	if maxPoints < 0 || maxPoints == 1 || maxPoints > maxRouteGeometryPoints {
+		return routeGeometry{}, core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("max_points must be 0 or between 2 and %d", maxRouteGeometryPoints))
+	}
+	return routeGeometry{format: format, maxPoints: maxPoints}, nil
+}
+
+// parseRouteGeometry reads geometry_precision and max_points from a request
+func parseRouteGeometry(req mcp.CallToolRequest) (routeGeometry, *core.MCPError) {
+	return newRouteGeometry(mcp.ParseString(req, "geometry_precision", ""), mcp.ParseInt(req, "max_points", 0))
+}
+
+// osrmParameters returns the OSRM geometries and overview parameters
+// fetching the geometry this format needs: none when it is not returned,
+// and polyline6 when precision beyond polyline5 is wanted
+func (g routeGeometry) osrmParameters(overview string) (geometries string, overviewParam string) {
+	switch g.format {
+	case GeometryNone:
+		return "polyline", "false"
+	case GeometryPolyline6, GeometryGeoJSON:
+		return "polyline6", overview
+	}
+	return "polyline", overview
+}
+
+// precision returns the polyline precision osrmParameters requests
+func (g routeGeometry) precision() int {
+	if g.format == GeometryPolyline6 || g.format == GeometryGeoJSON {
+		return 6
+	}
+	return 5
+}
+
+// render converts a polyline encoded with the given precision to the
+// requested format, simplified to maxPoints
+func (g routeGeometry) render(encoded string, precision int) RouteGeometry {
+	if g.format == GeometryNone || encoded == "" {
+		return RouteGeometry{}
+	}
+
+	points := osm.DecodePolylinePrecision(encoded, precision)
+	if g.maxPoints > 0 && len(points) > g.maxPoints {
+		points = geo.SimplifyToLimit(points, simplifyTolerance, g.maxPoints)
+	}
+
+	out := RouteGeometry{GeometryFormat: g.format, PointCount: len(points)}
+	switch g.format {
+	case GeometryGeoJSON:
+		coordinates := make([][]float64, len(points))
+		for i, p := range points {
+			coordinates[i] = []float64{p.Longitude, p.Latitude}
+		}
+		out.GeoJSON = &GeoJSONLineString{Type: "LineString", Coordinates: coordinates}
+	case GeometryPolyline6:
+		out.Polyline = osm.EncodePolylinePrecision(points, 6)
+	default:
+		out.Polyline = osm.EncodePolylinePrecision(points, 5)
+	}
+	return out
+}
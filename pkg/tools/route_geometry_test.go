@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestNewRouteGeometry(t *testing.T) {
+	if g, err := newRouteGeometry("", 0); err != nil || g.format != GeometryPolyline5 {
+		t.Errorf("expected polyline5 by default, got %+v (%v)", g, err)
+	}
+	for _, format := range []string{"polyline7", "wkt"} {
+		if _, err := newRouteGeometry(format, 0); err == nil {
+			t.Errorf("expected %s to be rejected", format)
+		}
+	}
+	for _, maxPoints := range []int{-1, 1, maxRouteGeometryPoints + 1} {
+		if _, err := newRouteGeometry(GeometryGeoJSON, maxPoints); err == nil {
+			t.Errorf("expected max_points %d to be rejected", maxPoints)
+		}
+	}
+
+	g, _ := newRouteGeometry(GeometryNone, 0)
+	if geometries, overview := g.osrmParameters("full"); geometries != "polyline" || overview != "false" {
+		t.Errorf("expected no overview for none, got %s %s", geometries, overview)
+	}
+	g, _ = newRouteGeometry(GeometryGeoJSON, 0)
+	if geometries, overview := g.osrmParameters("full"); geometries != "polyline6" || overview != "full" {
+		t.Errorf("expected polyline6 for geojson, got %s %s", geometries, overview)
+	}
+}
+
+func TestRouteGeometryRender(t *testing.T) {
+	// A straight line with 101 points, which simplifies to its ends
+	var line []geo.Location
+	for i := 0; i <= 100; i++ {
+		line = append(line, geo.Location{Latitude: 51.5, Longitude: -0.1 + float64(i)*0.001})
+	}
+	encoded := osm.EncodePolylinePrecision(line, 6)
+
+	render := func(format string, maxPoints int) RouteGeometry {
+		g, err := newRouteGeometry(format, maxPoints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g.render(encoded, 6)
+	}
+
+	out := render(GeometryPolyline5, 0)
+	if out.PointCount != 101 || len(osm.DecodePolyline(out.Polyline)) != 101 || out.GeoJSON != nil {
+		t.Errorf("expected a polyline5 with every point, got %+v", out)
+	}
+
+	out = render(GeometryPolyline6, 10)
+	if out.PointCount > 10 || len(osm.DecodePolylinePrecision(out.Polyline, 6)) != out.PointCount {
+		t.Errorf("expected at most 10 points, got %d", out.PointCount)
+	}
+
+	out = render(GeometryGeoJSON, 0)
+	if out.Polyline != "" || out.GeoJSON == nil || out.GeoJSON.Type != "LineString" || len(out.GeoJSON.Coordinates) != 101 {
+		t.Fatalf("expected a GeoJSON LineString, got %+v", out)
+	}
+	if first := out.GeoJSON.Coordinates[0]; math.Abs(first[0]+0.1) > 1e-9 || math.Abs(first[1]-51.5) > 1e-9 {
+		t.Errorf("expected [lon, lat] coordinates, got %v", first)
+	}
+
+	if out := render(GeometryNone, 0); out != (RouteGeometry{}) {
+		t.Errorf("expected no geometry, got %+v", out)
+	}
+}
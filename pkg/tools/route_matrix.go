@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+// RouteMatrixOutput defines the output for route_matrix
+type RouteMatrixOutput struct {
+	Mode         string         `json:"mode"`
+	Origins      []geo.Location `json:"origins"`
+	Destinations []geo.Location `json:"destinations"`
+	Durations    [][]*float64   `json:"durations"` // seconds, [origin][destination], null without a route
+	Distances    [][]*float64   `json:"distances"` // meters, [origin][destination], null without a route
+}
+
+// RouteMatrixTool returns a tool definition for travel time matrices
+func RouteMatrixTool() mcp.Tool {
+	return mcp.NewTool("route_matrix",
+		mcp.WithDescription(fmt.Sprintf("Compute travel durations and distances from each of several origins to each of several destinations in one request, e.g. to find the nearest of several places by travel time. Entries are null where no route exists. At most %d origins and destinations together", core.MaxTableCoordinates)),
+		mcp.WithArray("origins",
+			mcp.Required(),
+			mcp.Description("Starting points, each with latitude and longitude"),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithArray("destinations",
+			mcp.Description("End points, each with latitude and longitude. Defaults to the origins, giving the matrix between all of them"),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Transportation mode: car, bike, foot"),
+			mcp.DefaultString("car"),
+		),
+	)
+}
+
+// HandleRouteMatrix fetches a duration and distance matrix
+func HandleRouteMatrix(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "route_matrix")
+
+	origins, errResult := parseMatrixLocations(req, "origins")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if len(origins) == 0 {
+		return core.NewError(core.ErrMissingParameter, "origins is required").
+			WithGuidance("Pass origins as [{\"latitude\": 51.5, \"longitude\": -0.12}, ...]").
+			ToMCPResult(), nil
+	}
+	destinations, errResult := parseMatrixLocations(req, "destinations")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if len(origins)+len(destinations) > core.MaxTableCoordinates {
+		return core.NewError(core.ErrInvalidInput,
+			fmt.Sprintf("At most %d origins and destinations together are supported", core.MaxTableCoordinates)).
+			WithGuidance("Split the matrix into several requests").
+			ToMCPResult(), nil
+	}
+	profile := mapModeToProfile(mcp.ParseString(req, "mode", "car"))
+
+	options := core.OSRMOptions{
+		BaseURL: osm.OSRMBaseURL,
+		Profile: profile,
+		Client:  osm.GetClient(ctx),
+		RetryOptions: core.RetryOptions{
+			MaxAttempts:  3,
+			InitialDelay: 500 * time.Millisecond,
+			MaxDelay:     5 * time.Second,
+			Multiplier:   2.0,
+		},
+	}
+
+	table, err := core.GetTable(ctx, lonLatPairs(origins), lonLatPairs(destinations), options)
+	if err != nil {
+		logger.Error("failed to fetch route matrix", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("OSRM", http.StatusServiceUnavailable,
+			"Failed to communicate with routing service").ToMCPResult(), nil
+	}
+
+	// Without destinations the matrix is between the origins
+	if len(destinations) == 0 {
+		destinations = origins
+	}
+	output := RouteMatrixOutput{
+		Mode:         profile,
+		Origins:      origins,
+		Destinations: destinations,
+		Durations:    table.Durations,
+		Distances:    table.Distances,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// parseMatrixLocations reads an array of points, accepting the same shapes
+// as visualize_places so search results can be passed directly
+func parseMatrixLocations(req mcp.CallToolRequest, name string) ([]geo.Location, *mcp.CallToolResult) {
+	raw, _ := req.GetArguments()[name].([]any)
+	locations := make([]geo.Location, 0, len(raw))
+	for i, item := range raw {
+		obj, _ := item.(map[string]any)
+		loc, ok := placeLocation(obj)
+		if !ok {
+			return nil, core.NewError(core.ErrInvalidParameter,
+				fmt.Sprintf("%s[%d] must have a valid latitude and longitude", name, i)).ToMCPResult()
+		}
+		locations = append(locations, loc)
+	}
+	return locations, nil
+}
+
+// lonLatPairs converts locations to OSRM [longitude, latitude] coordinates
+func lonLatPairs(locations []geo.Location) [][]float64 {
+	pairs := make([][]float64, len(locations))
+	for i, loc := range locations {
+		pairs[i] = []float64{loc.Longitude, loc.Latitude}
+	}
+	return pairs
+}
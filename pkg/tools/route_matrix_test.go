@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleRouteMatrixInvalid(t *testing.T) {
+	many := make([]any, 51)
+	for i := range many {
+		many[i] = map[string]any{"latitude": 1.0, "longitude": float64(i) / 100}
+	}
+
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"Missing origins", map[string]any{}},
+		{"Invalid origin", map[string]any{"origins": []any{map[string]any{"latitude": 91.0, "longitude": 0.0}}}},
+		{"Invalid destination", map[string]any{
+			"origins":      []any{map[string]any{"latitude": 1.0, "longitude": 1.0}},
+			"destinations": []any{"here"},
+		}},
+		{"Too many points", map[string]any{"origins": many}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := HandleRouteMatrix(context.Background(), req)
+			if err != nil || !result.IsError {
+				t.Errorf("expected an error result, got %v %+v", err, result)
+			}
+		})
+	}
+}
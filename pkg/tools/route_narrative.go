@@ -0,0 +1,354 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// defaultLandmarkRadius is how far from a maneuver a landmark may be, in meters
+	defaultLandmarkRadius = 60.0
+
+	// maxLandmarkRadius caps landmark_radius
+	maxLandmarkRadius = 200.0
+
+	// maxNarrativeManeuvers caps how many maneuvers are searched for landmarks
+	maxNarrativeManeuvers = 60
+
+	// landmarkAtThreshold is the along-route offset in meters within which a
+	// landmark is described as being "at" the maneuver
+	landmarkAtThreshold = 15.0
+)
+
+// landmarkNouns describes common landmark tags in navigation text
+var landmarkNouns = map[string]string{
+	"amenity=fuel":             "gas station",
+	"amenity=bank":             "bank",
+	"amenity=pharmacy":         "pharmacy",
+	"amenity=restaurant":       "restaurant",
+	"amenity=cafe":             "café",
+	"amenity=fast_food":        "restaurant",
+	"amenity=pub":              "pub",
+	"amenity=bar":              "bar",
+	"amenity=place_of_worship": "place of worship",
+	"amenity=school":           "school",
+	"amenity=hospital":         "hospital",
+	"amenity=post_office":      "post office",
+	"amenity=police":           "police station",
+	"amenity=fire_station":     "fire station",
+	"amenity=library":          "library",
+	"amenity=townhall":         "town hall",
+	"shop=supermarket":         "supermarket",
+	"tourism=hotel":            "hotel",
+	"tourism=museum":           "museum",
+	"leisure=park":             "park",
+}
+
+// RouteLandmark is a named feature used to describe a maneuver
+type RouteLandmark struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind,omitempty"`
+	Location Location `json:"location"`
+	Distance float64  `json:"distance"` // from the maneuver, in meters
+	Position string   `json:"position"` // before, at or after the maneuver
+}
+
+// NarrativeStep is a route step with landmark-enriched instructions
+type NarrativeStep struct {
+	Instruction string         `json:"instruction"` // plain instruction from the street names
+	Narrative   string         `json:"narrative"`   // instruction with landmark reference
+	Distance    float64        `json:"distance"`
+	Duration    float64        `json:"duration"`
+	Location    Location       `json:"location"`
+	Landmark    *RouteLandmark `json:"landmark,omitempty"`
+}
+
+// RouteNarrativeOutput defines the output for route_narrative
+type RouteNarrativeOutput struct {
+	Distance  float64         `json:"distance"`
+	Duration  float64         `json:"duration"`
+	Steps     []NarrativeStep `json:"steps"`
+	Landmarks int             `json:"landmarks"` // steps described with a landmark
+	Message   string          `json:"message,omitempty"`
+}
+
+// RouteNarrativeTool returns a tool definition for landmark-based directions
+func RouteNarrativeTool() mcp.Tool {
+	return mcp.NewTool("route_narrative",
+		mcp.WithDescription("Get turn-by-turn directions described with nearby named landmarks (e.g. \"Turn left onto Main Street after the Shell gas station\")"),
+		mcp.WithNumber("start_lat",
+			mcp.Required(),
+			mcp.Description("The latitude of the starting point"),
+		),
+		mcp.WithNumber("start_lon",
+			mcp.Required(),
+			mcp.Description("The longitude of the starting point"),
+		),
+		mcp.WithNumber("end_lat",
+			mcp.Required(),
+			mcp.Description("The latitude of the destination"),
+		),
+		mcp.WithNumber("end_lon",
+			mcp.Required(),
+			mcp.Description("The longitude of the destination"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Transportation mode: car, bike, foot"),
+			mcp.DefaultString("car"),
+		),
+		mcp.WithNumber("landmark_radius",
+			mcp.Description("How far from each maneuver to look for landmarks, in meters (max 200)"),
+			mcp.DefaultNumber(defaultLandmarkRadius),
+		),
+	)
+}
+
+// HandleRouteNarrative builds landmark-enriched directions between two points
+func HandleRouteNarrative(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "route_narrative")
+
+	startLat, startLon, endLat, endLon, mode, errResult, err := ValidateRouteParameters(req, logger)
+	if err != nil {
+		return errResult, nil
+	}
+
+	radius := mcp.ParseFloat64(req, "landmark_radius", defaultLandmarkRadius)
+	if radius <= 0 || radius > maxLandmarkRadius {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("landmark_radius must be between 1 and %.0f meters", maxLandmarkRadius)).ToMCPResult(), nil
+	}
+
+	options := core.DefaultOSRMOptions()
+	options.BaseURL = osm.OSRMBaseURL
+	options.Profile = mapModeToProfile(mode)
+	options.Overview = "false"
+	options.Steps = true
+	options.Client = osm.GetClient(ctx)
+	options.RetryOptions = core.RetryOptions{
+		MaxAttempts:  3,
+		InitialDelay: 500 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+		Multiplier:   2.0,
+	}
+
+	route, err := core.GetRoute(ctx, [][]float64{{startLon, startLat}, {endLon, endLat}}, options)
+	if err != nil {
+		logger.Error("failed to get route", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("OSRM", http.StatusServiceUnavailable,
+			"Failed to communicate with routing service").ToMCPResult(), nil
+	}
+	if len(route.Routes) == 0 || len(route.Routes[0].Legs) == 0 {
+		return core.NewError("ROUTE_NOT_FOUND",
+			"No route found between the specified points").ToMCPResult(), nil
+	}
+
+	best := route.Routes[0]
+	steps := best.Legs[0].Steps
+	output := RouteNarrativeOutput{
+		Distance: best.Distance,
+		Duration: best.Duration,
+	}
+
+	// Landmarks are a best-effort enrichment; fall back to plain directions
+	var landmarks []RouteLandmark
+	if query := buildLandmarkQuery(steps, radius); query != "" {
+		elements, err := executeOverpassQuery(ctx, query)
+		if err != nil {
+			logger.Warn("landmark lookup failed", "error", err)
+			output.Message = "Landmarks are unavailable; directions use street names only"
+		} else {
+			landmarks = landmarksFromElements(elements)
+		}
+	}
+
+	output.Steps = narrateSteps(steps, landmarks, radius)
+	for _, step := range output.Steps {
+		if step.Landmark != nil {
+			output.Landmarks++
+		}
+	}
+
+	logger.Info("built route narrative", "steps", len(output.Steps), "landmarks", output.Landmarks)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// isNarratedManeuver reports whether a step's maneuver benefits from a landmark
+func isNarratedManeuver(step core.OSRMStep) bool {
+	switch step.Maneuver.Type {
+	case "depart", "arrive", "new name", "notification":
+		return false
+	}
+	return len(step.Maneuver.Location) == 2
+}
+
+// buildLandmarkQuery builds an Overpass query for named features around
+// each narrated maneuver, or "" when no step needs one
+func buildLandmarkQuery(steps []core.OSRMStep, radius float64) string {
+	var query strings.Builder
+	count := 0
+	for _, step := range steps {
+		if !isNarratedManeuver(step) || count >= maxNarrativeManeuvers {
+			continue
+		}
+		count++
+		lat, lon := step.Maneuver.Location[1], step.Maneuver.Location[0]
+		fmt.Fprintf(&query, `nwr(around:%.0f,%f,%f)[name][~"^(amenity|shop|tourism|historic|leisure)$"~"."];`, radius, lat, lon)
+	}
+	if count == 0 {
+		return ""
+	}
+	return "[out:json][timeout:25];(" + query.String() + ");out center 500;"
+}
+
+// landmarksFromElements converts Overpass elements to landmarks
+func landmarksFromElements(elements []osm.OverpassElement) []RouteLandmark {
+	landmarks := make([]RouteLandmark, 0, len(elements))
+	seen := make(map[string]bool)
+	for _, element := range elements {
+		place, ok := elementToPlace(element)
+		if !ok || seen[place.ID] {
+			continue
+		}
+		seen[place.ID] = true
+		landmarks = append(landmarks, RouteLandmark{
+			ID:       place.ID,
+			Name:     place.Name,
+			Kind:     landmarkKind(element.Tags),
+			Location: place.Location,
+		})
+	}
+	return landmarks
+}
+
+// landmarkKind returns a short description of a landmark's type
+func landmarkKind(tags map[string]string) string {
+	for _, key := range []string{"amenity", "shop", "tourism", "leisure", "historic"} {
+		value := tags[key]
+		if value == "" {
+			continue
+		}
+		if noun, ok := landmarkNouns[key+"="+value]; ok {
+			return noun
+		}
+		if key == "shop" {
+			return strings.ReplaceAll(value, "_", " ") + " shop"
+		}
+		return strings.ReplaceAll(value, "_", " ")
+	}
+	return ""
+}
+
+// narrateSteps pairs each maneuver with its nearest landmark and builds the text
+func narrateSteps(steps []core.OSRMStep, landmarks []RouteLandmark, radius float64) []NarrativeStep {
+	narrated := make([]NarrativeStep, 0, len(steps))
+	used := make(map[string]bool)
+	var previous []float64
+
+	for _, step := range steps {
+		instruction := generateInstruction(step.Maneuver.Type, step.Maneuver.Modifier, step.Name)
+		out := NarrativeStep{
+			Instruction: instruction,
+			Narrative:   instruction,
+			Distance:    step.Distance,
+			Duration:    step.Duration,
+		}
+		if len(step.Maneuver.Location) == 2 {
+			out.Location = Location{Latitude: step.Maneuver.Location[1], Longitude: step.Maneuver.Location[0]}
+		}
+
+		if isNarratedManeuver(step) {
+			if landmark := nearestLandmark(out.Location, previous, landmarks, radius, used); landmark != nil {
+				used[landmark.ID] = true
+				out.Landmark = landmark
+				out.Narrative = fmt.Sprintf("%s %s %s", instruction, landmark.Position, landmarkPhrase(*landmark))
+			}
+		}
+
+		if len(step.Maneuver.Location) == 2 {
+			previous = step.Maneuver.Location
+		}
+		narrated = append(narrated, out)
+	}
+	return narrated
+}
+
+// nearestLandmark finds the closest unused landmark to a maneuver and
+// places it before, at or after the turn along the approach from previous
+// ([lon, lat], may be nil)
+func nearestLandmark(at Location, previous []float64, landmarks []RouteLandmark, radius float64, used map[string]bool) *RouteLandmark {
+	var best *RouteLandmark
+	for i := range landmarks {
+		if used[landmarks[i].ID] {
+			continue
+		}
+		d := geo.HaversineDistance(at.Latitude, at.Longitude, landmarks[i].Location.Latitude, landmarks[i].Location.Longitude)
+		if d > radius || (best != nil && d >= best.Distance) {
+			continue
+		}
+		candidate := landmarks[i]
+		candidate.Distance = math.Round(d)
+		best = &candidate
+	}
+	if best == nil {
+		return nil
+	}
+
+	best.Position = "at"
+	if len(previous) == 2 {
+		offset := alongTrackOffset(previous[1], previous[0], at, best.Location)
+		switch {
+		case offset < -landmarkAtThreshold:
+			best.Position = "after" // passed on the approach, before turning
+		case offset > landmarkAtThreshold:
+			best.Position = "before" // lies beyond the turn
+		}
+	}
+	return best
+}
+
+// alongTrackOffset returns how far, in meters, point p lies beyond the
+// maneuver at along the approach direction from (fromLat, fromLon).
+// Negative values mean p is passed before reaching the maneuver.
+func alongTrackOffset(fromLat, fromLon float64, at, p Location) float64 {
+	cosLat := math.Cos(at.Latitude * math.Pi / 180)
+	dx := (at.Longitude - fromLon) * cosLat
+	dy := at.Latitude - fromLat
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return 0
+	}
+	px := (p.Longitude - at.Longitude) * cosLat
+	py := p.Latitude - at.Latitude
+	metersPerDegree := geo.EarthRadius * math.Pi / 180
+	return (px*dx + py*dy) / length * metersPerDegree
+}
+
+// landmarkPhrase names a landmark for use in an instruction
+func landmarkPhrase(landmark RouteLandmark) string {
+	if landmark.Kind == "" || strings.Contains(strings.ToLower(landmark.Name), strings.ToLower(landmark.Kind)) {
+		return landmark.Name
+	}
+	return fmt.Sprintf("the %s %s", landmark.Name, landmark.Kind)
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+func narrativeTestSteps() []core.OSRMStep {
+	return []core.OSRMStep{
+		{Name: "Oak Street", Maneuver: core.OSRMManeuver{Type: "depart", Location: []float64{0, 0}}},
+		{Name: "Main Street", Distance: 200, Maneuver: core.OSRMManeuver{Type: "turn", Modifier: "left", Location: []float64{0.01, 0}}},
+		{Name: "", Maneuver: core.OSRMManeuver{Type: "arrive", Location: []float64{0.01, 0.01}}},
+	}
+}
+
+func TestBuildLandmarkQuery(t *testing.T) {
+	query := buildLandmarkQuery(narrativeTestSteps(), 50)
+	if strings.Count(query, "around:50,") != 1 {
+		t.Errorf("expected one around clause for the single turn, got %s", query)
+	}
+	if !strings.Contains(query, "out center") {
+		t.Errorf("expected center output, got %s", query)
+	}
+
+	depart := narrativeTestSteps()[:1]
+	if query := buildLandmarkQuery(depart, 50); query != "" {
+		t.Errorf("expected no query without maneuvers, got %s", query)
+	}
+}
+
+func TestNarrateSteps(t *testing.T) {
+	tests := []struct {
+		name     string
+		landmark RouteLandmark
+		want     string
+	}{
+		{
+			name:     "Passed before turning",
+			landmark: RouteLandmark{ID: "node/1", Name: "Shell", Kind: "gas station", Location: Location{Latitude: 0.0001, Longitude: 0.0096}},
+			want:     "Turn left onto Main Street after the Shell gas station",
+		},
+		{
+			name:     "Beyond the turn",
+			landmark: RouteLandmark{ID: "node/2", Name: "City Bank", Kind: "bank", Location: Location{Latitude: -0.0001, Longitude: 0.0104}},
+			want:     "Turn left onto Main Street before City Bank",
+		},
+		{
+			name:     "At the corner",
+			landmark: RouteLandmark{ID: "node/3", Name: "Rose Café", Kind: "café", Location: Location{Latitude: 0.0002, Longitude: 0.01}},
+			want:     "Turn left onto Main Street at Rose Café",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := narrateSteps(narrativeTestSteps(), []RouteLandmark{tt.landmark}, 60)
+			if steps[1].Narrative != tt.want {
+				t.Errorf("narrative = %q, want %q", steps[1].Narrative, tt.want)
+			}
+			if steps[0].Landmark != nil || steps[2].Landmark != nil {
+				t.Error("depart and arrive steps should not reference landmarks")
+			}
+		})
+	}
+
+	// Landmarks beyond the radius are ignored
+	far := RouteLandmark{ID: "node/4", Name: "Far Away", Location: Location{Latitude: 0.002, Longitude: 0.01}}
+	steps := narrateSteps(narrativeTestSteps(), []RouteLandmark{far}, 60)
+	if steps[1].Landmark != nil || steps[1].Narrative != steps[1].Instruction {
+		t.Errorf("expected plain instruction, got %+v", steps[1])
+	}
+}
+
+func TestHandleRouteNarrativeValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"Missing end", map[string]any{"start_lat": 1.0, "start_lon": 1.0}},
+		{"Radius too large", map[string]any{"start_lat": 1.0, "start_lon": 1.0, "end_lat": 1.1, "end_lon": 1.1, "landmark_radius": 500.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "route_narrative", Arguments: tt.args}}
+			result, err := HandleRouteNarrative(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,18 +17,54 @@ import (
 
 // RouteFetchInput defines the input parameters for fetching a route
 type RouteFetchInput struct {
-	Start geo.Location `json:"start"`
-	End   geo.Location `json:"end"`
-	Mode  string       `json:"mode"`
+	Start          geo.Location     `json:"start"`
+	End            geo.Location     `json:"end"`
+	Mode           string           `json:"mode"`
+	IgnoreClosures bool             `json:"ignore_closures"`
+	AvoidAreas     [][]geo.Location `json:"avoid_areas,omitempty"`
+	CruiseSpeed    float64          `json:"cruise_speed,omitempty"` // knots, air and sea modes
+	Units          string           `json:"units,omitempty"`
+	ToEntrance     *bool            `json:"to_entrance,omitempty"` // nil uses the mode default
+
+	GeometryPrecision string `json:"geometry_precision,omitempty"`
+	MaxPoints         int    `json:"max_points,omitempty"`
 }
 
 // RouteFetchOutput defines the output for a fetched route
 type RouteFetchOutput struct {
-	Polyline string  `json:"polyline"`
-	Distance float64 `json:"distance"` // in meters
-	Duration float64 `json:"duration"` // in seconds
+	RouteGeometry
+	Distance       float64        `json:"distance"`                  // in meters
+	Duration       float64        `json:"duration"`                  // in seconds
+	Closures       []core.Closure `json:"closures,omitempty"`        // reported closures on this route
+	ClosurePenalty float64        `json:"closure_penalty,omitempty"` // in seconds
+
+	// AvoidAreas reports whether avoid_areas could be honored
+	AvoidAreas *core.AvoidAreasResult `json:"avoid_areas,omitempty"`
+
+	// Geodesic routes for the air and sea modes follow the great circle
+	Geodesic    bool    `json:"geodesic,omitempty"`
+	CruiseSpeed float64 `json:"cruise_speed_knots,omitempty"`
+	Note        string  `json:"note,omitempty"`
+
+	// Units repeats distance, speed and duration in the requested unit system
+	Units *ConvertedMeasures `json:"units,omitempty"`
+
+	// Entrance is the building entrance the route ends at instead of end
+	Entrance *BuildingEntrance `json:"entrance,omitempty"`
 }
 
+// Default cruise speeds in knots for the geodesic travel modes
+const (
+	defaultAirCruiseSpeed = 450.0
+	defaultSeaCruiseSpeed = 12.0
+	maxCruiseSpeed        = 2000.0
+
+	// geodesicPointSpacing is the distance between points of a geodesic
+	// route polyline, in meters
+	geodesicPointSpacing = 25000.0
+	maxGeodesicPoints    = 500
+)
+
 // RouteFetchTool returns a tool definition for fetching routes
 func RouteFetchTool() mcp.Tool {
 	return mcp.NewTool("route_fetch",
@@ -41,9 +78,34 @@ func RouteFetchTool() mcp.Tool {
 			mcp.Description("The ending point as {latitude, longitude}"),
 		),
 		mcp.WithString("mode",
-			mcp.Description("Travel mode (car, bike, foot)"),
+			mcp.Description("Travel mode (car, bike, foot). The air and sea modes skip the road network and return a great circle path timed at a cruise speed"),
 			mcp.DefaultString("car"),
 		),
+		mcp.WithNumber("cruise_speed",
+			mcp.Description("Cruise speed in knots for the air and sea modes, at least 1 (defaults to 450 for air, 12 for sea)"),
+		),
+		mcp.WithString("units",
+			mcp.Description(unitsDescription),
+		),
+		mcp.WithBoolean("ignore_closures",
+			mcp.Description("Ignore closures reported with report_closure when choosing the route"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithArray("avoid_areas",
+			mcp.Description("Polygons to route around, each an array of {latitude, longitude} points (max 10). Avoidance is best effort; the result reports whether it was honored"),
+		),
+		mcp.WithBoolean("to_entrance",
+			mcp.Description("When the end point is a building, route to its main entrance instead of its centroid. Defaults to true for foot and false otherwise"),
+		),
+		mcp.WithString("geometry_precision",
+			mcp.Description(geometryPrecisionDescription),
+			mcp.Enum(geometryFormats...),
+			mcp.DefaultString(GeometryPolyline5),
+		),
+		mcp.WithNumber("max_points",
+			mcp.Description(maxPointsDescription),
+			mcp.DefaultNumber(0),
+		),
 	)
 }
 
@@ -89,21 +151,151 @@ func HandleRouteFetch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTo
 		return core.NewError(core.ErrInvalidLongitude, fmt.Sprintf("Invalid end coordinates: %s", err)).ToMCPResult(), nil
 	}
 
+	if !validUnits(input.Units) {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid units: %s", input.Units)).
+			WithGuidance("Use 'metric', 'imperial', or 'nautical'").
+			ToMCPResult(), nil
+	}
+
+	geometry, geomErr := newRouteGeometry(input.GeometryPrecision, input.MaxPoints)
+	if geomErr != nil {
+		return geomErr.ToMCPResult(), nil
+	}
+
+	// Air and sea routes follow the great circle instead of the road network
+	if input.Mode == "air" || input.Mode == "sea" {
+		if len(input.AvoidAreas) > 0 {
+			return core.NewError(core.ErrInvalidParameter, "avoid_areas is not supported for air and sea routes").ToMCPResult(), nil
+		}
+		// Zero leaves the mode default
+		if (input.CruiseSpeed != 0 && input.CruiseSpeed < 1) || input.CruiseSpeed > maxCruiseSpeed {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("cruise_speed must be between 1 and %.0f knots", maxCruiseSpeed)).ToMCPResult(), nil
+		}
+		if geo.IsAntipodal(input.Start, input.End) {
+			return core.NewError(core.ErrInvalidParameter, "Start and end are antipodal, so there is no unique great circle route").ToMCPResult(), nil
+		}
+
+		output := geodesicRoute(input.Start, input.End, input.Mode, input.CruiseSpeed, geometry.precision())
+		output.RouteGeometry = geometry.render(output.Polyline, geometry.precision())
+		output.Units = convertMeasures(input.Units, output.Distance, output.Duration)
+
+		resultBytes, err := json.Marshal(output)
+		if err != nil {
+			logger.Error("failed to marshal result", "error", err)
+			return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+		}
+		return mcp.NewToolResultText(string(resultBytes)), nil
+	}
+
 	// Validate mode
 	profile := convertModeToProfile(input.Mode)
 	if profile == "" {
 		logger.Error("invalid mode", "mode", input.Mode)
 		errResult := core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid mode: %s", input.Mode))
-		errResult = errResult.WithGuidance("Use 'car', 'bike', or 'foot'")
+		errResult = errResult.WithGuidance("Use 'car', 'bike', 'foot', 'air', or 'sea'")
 		return errResult.ToMCPResult(), nil
 	}
 
+	// Route to the building entrance rather than the centroid when asked
+	var entrance *BuildingEntrance
+	toEntrance := entranceDefault(profile)
+	if input.ToEntrance != nil {
+		toEntrance = *input.ToEntrance
+	}
+	if toEntrance {
+		found, ok, err := resolveEntrance(ctx, input.Start, input.End)
+		switch {
+		case err != nil:
+			logger.Warn("failed to look up building entrance", "error", err)
+		case ok:
+			logger.Debug("routing to building entrance", "entrance", found.ID, "type", found.Type)
+			entrance = found
+			input.End = geo.Location{Latitude: found.Location.Latitude, Longitude: found.Location.Longitude}
+		}
+	}
+
 	// Setup the coordinates (longitude first, latitude second, as expected by OSRM)
 	startCoord := []float64{input.Start.Longitude, input.Start.Latitude}
 	endCoord := []float64{input.End.Longitude, input.End.Latitude}
 
-	// Use the simpler core.GetSimpleRoute helper
-	route, err := core.GetSimpleRoute(ctx, startCoord, endCoord, profile)
+	if err := core.ValidateAvoidAreas(input.AvoidAreas); err != nil {
+		logger.Error("invalid avoid areas", "error", err)
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid avoid_areas: %s", err)).ToMCPResult(), nil
+	}
+
+	// Route around avoid areas, reporting closures on the chosen route
+	if len(input.AvoidAreas) > 0 {
+		// Avoidance checks need the geometry even when it is not returned
+		options := routeFetchOptions(profile)
+		options.Geometries, _ = geometry.osrmParameters("full")
+		closures := core.DefaultClosureStore()
+		if input.IgnoreClosures {
+			closures = nil
+		}
+		avoid, err := core.RouteAvoidingAreas(ctx, startCoord, endCoord, options, input.AvoidAreas, closures)
+		if err != nil {
+			logger.Error("failed to get route", "error", err)
+			if mcpErr, ok := err.(*core.MCPError); ok {
+				return mcpErr.ToMCPResult(), nil
+			}
+			return core.ServiceError("OSRM", http.StatusServiceUnavailable, "Failed to get route").
+				WithGuidance("Try again later or check if the locations are reachable").
+				ToMCPResult(), nil
+		}
+		if !avoid.Honored {
+			logger.Warn("avoid areas not fully honored", "violated", avoid.Violated, "attempts", avoid.Attempts)
+		}
+
+		output := RouteFetchOutput{
+			RouteGeometry:  geometry.render(avoid.Route.Geometry, geometry.precision()),
+			Distance:       avoid.Route.Distance,
+			Duration:       avoid.Route.Duration,
+			Closures:       avoid.Closures,
+			ClosurePenalty: avoid.ClosurePenalty,
+			AvoidAreas:     avoid,
+			Entrance:       entrance,
+		}
+		output.Units = convertMeasures(input.Units, output.Distance, output.Duration)
+
+		resultBytes, err := json.Marshal(output)
+		if err != nil {
+			logger.Error("failed to marshal result", "error", err)
+			return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+		}
+		return mcp.NewToolResultText(string(resultBytes)), nil
+	}
+
+	// Re-rank alternatives against reported closures when there are any
+	if !input.IgnoreClosures && len(core.DefaultClosureStore().Active()) > 0 {
+		output, err := fetchRouteAvoidingClosures(ctx, startCoord, endCoord, profile, geometry)
+		if err != nil {
+			logger.Error("failed to get route", "error", err)
+			if mcpErr, ok := err.(*core.MCPError); ok {
+				return mcpErr.ToMCPResult(), nil
+			}
+			return core.ServiceError("OSRM", http.StatusServiceUnavailable, "Failed to get route").
+				WithGuidance("Try again later or check if the locations are reachable").
+				ToMCPResult(), nil
+		}
+		if len(output.Closures) > 0 {
+			logger.Warn("best route still affected by closures", "count", len(output.Closures))
+		}
+		output.RouteGeometry = geometry.render(output.Polyline, geometry.precision())
+		output.Units = convertMeasures(input.Units, output.Distance, output.Duration)
+		output.Entrance = entrance
+
+		resultBytes, err := json.Marshal(output)
+		if err != nil {
+			logger.Error("failed to marshal result", "error", err)
+			return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+		}
+		return mcp.NewToolResultText(string(resultBytes)), nil
+	}
+
+	// Fetch only the geometry the format needs
+	options := routeFetchOptions(profile)
+	options.Geometries, options.Overview = geometry.osrmParameters(options.Overview)
+	result, err := core.GetRoute(ctx, [][]float64{startCoord, endCoord}, options)
 	if err != nil {
 		logger.Error("failed to get route", "error", err)
 		if mcpErr, ok := err.(*core.MCPError); ok {
@@ -115,11 +307,20 @@ func HandleRouteFetch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTo
 			ToMCPResult(), nil
 	}
 
+	if len(result.Routes) == 0 {
+		return core.NewError(core.ErrNoResults, "no routes found").
+			WithGuidance("No route could be calculated between these points. The locations may be inaccessible by the selected mode of transport").
+			ToMCPResult(), nil
+	}
+	route := result.Routes[0]
+
 	// Create output from route result
 	output := RouteFetchOutput{
-		Polyline: route.Polyline,
-		Distance: route.Distance,
-		Duration: route.Duration,
+		RouteGeometry: geometry.render(route.Geometry, geometry.precision()),
+		Distance:      route.Distance,
+		Duration:      route.Duration,
+		Units:         convertMeasures(input.Units, route.Distance, route.Duration),
+		Entrance:      entrance,
 	}
 
 	// Return result
@@ -132,6 +333,90 @@ func HandleRouteFetch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTo
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// routeFetchOptions returns the OSRM options route_fetch uses for a profile
+func routeFetchOptions(profile string) core.OSRMOptions {
+	options := core.DefaultOSRMOptions()
+	options.Profile = profile
+	return options
+}
+
+// geodesicRoute builds an air or sea route along the great circle between
+// two points, timed at a cruise speed in knots (0 for the mode default), with
+// its path encoded with precision decimals
+func geodesicRoute(start, end geo.Location, mode string, cruiseSpeed float64, precision int) RouteFetchOutput {
+	if cruiseSpeed == 0 {
+		cruiseSpeed = defaultAirCruiseSpeed
+		if mode == "sea" {
+			cruiseSpeed = defaultSeaCruiseSpeed
+		}
+	}
+
+	distance := geo.HaversineDistance(start.Latitude, start.Longitude, end.Latitude, end.Longitude)
+	points := int(math.Min(distance/geodesicPointSpacing+2, maxGeodesicPoints))
+
+	note := "Great circle path; ignores airways, airspace restrictions, wind, taxi and climb time"
+	if mode == "sea" {
+		note = "Great circle path; ignores land masses, shipping lanes, currents and port approaches"
+	}
+
+	return RouteFetchOutput{
+		RouteGeometry: RouteGeometry{Polyline: osm.EncodePolylinePrecision(geo.GreatCirclePath(start, end, points), precision)},
+		Distance:      distance,
+		Duration:      math.Round(distance / knotsToMetersPerSecond(cruiseSpeed)),
+		Geodesic:      true,
+		CruiseSpeed:   cruiseSpeed,
+		Note:          note,
+	}
+}
+
+// fetchRouteAvoidingClosures requests alternatives from OSRM and picks the one
+// with the lowest duration after closure penalties. The geometry is fetched
+// with the precision the returned format needs.
+func fetchRouteAvoidingClosures(ctx context.Context, start, end []float64, profile string, geometry routeGeometry) (*RouteFetchOutput, error) {
+	options := routeFetchOptions(profile)
+	options.Overview = "full"
+	options.Alternatives = 2
+	options.Geometries, _ = geometry.osrmParameters(options.Overview)
+
+	result, err := core.GetRoute(ctx, [][]float64{start, end}, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Routes) == 0 {
+		return nil, core.NewError(core.ErrNoResults, "no routes found").
+			WithGuidance("No route could be calculated between these points. The locations may be inaccessible by the selected mode of transport")
+	}
+
+	route, closures, penalty := leastPenalizedRoute(result.Routes, geometry.precision())
+	return &RouteFetchOutput{
+		RouteGeometry:  RouteGeometry{Polyline: route.Geometry},
+		Distance:       route.Distance,
+		Duration:       route.Duration,
+		Closures:       closures,
+		ClosurePenalty: penalty,
+	}, nil
+}
+
+// leastPenalizedRoute picks the route with the lowest duration after the
+// penalties of the reported closures on it, returning those closures and
+// their penalty. Route geometries are polylines encoded with precision
+// decimals.
+func leastPenalizedRoute(routes []core.OSRMRoute, precision int) (core.OSRMRoute, []core.Closure, float64) {
+	var (
+		best         core.OSRMRoute
+		bestClosures []core.Closure
+		bestPenalty  float64
+	)
+	for i, route := range routes {
+		closures := core.DefaultClosureStore().AlongRoute(osm.DecodePolylinePrecision(route.Geometry, precision))
+		penalty := core.ClosurePenalty(closures)
+		if i == 0 || route.Duration+penalty < best.Duration+bestPenalty {
+			best, bestClosures, bestPenalty = route, closures, penalty
+		}
+	}
+	return best, bestClosures, bestPenalty
+}
+
 // RouteSampleInput defines the input parameters for sampling points along a route
 type RouteSampleInput struct {
 	Polyline string  `json:"polyline"`
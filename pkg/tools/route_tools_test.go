@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -263,3 +264,158 @@ func TestHandleEnrichEmissions(t *testing.T) {
 }
 
 // Note: TestHandleRouteFetch is omitted because it would require mocking the OSRM API
+
+func TestHandleRouteFetchInvalidAvoidAreas(t *testing.T) {
+	tests := []struct {
+		name       string
+		avoidAreas any
+	}{
+		{
+			name:       "Degenerate polygon",
+			avoidAreas: []any{[]any{map[string]any{"latitude": 40.0, "longitude": -74.0}}},
+		},
+		{
+			name: "Invalid coordinates",
+			avoidAreas: []any{[]any{
+				map[string]any{"latitude": 40.0, "longitude": -74.0},
+				map[string]any{"latitude": 95.0, "longitude": -74.0},
+				map[string]any{"latitude": 40.0, "longitude": -73.0},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name: "route_fetch",
+					Arguments: map[string]any{
+						"start":       map[string]any{"latitude": 40.0, "longitude": -74.0},
+						"end":         map[string]any{"latitude": 40.1, "longitude": -74.1},
+						"mode":        "car",
+						"avoid_areas": tt.avoidAreas,
+					},
+				},
+			}
+
+			result, err := HandleRouteFetch(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("Expected error result for invalid avoid_areas")
+			}
+		})
+	}
+}
+
+func TestGeodesicRoute(t *testing.T) {
+	start := geo.Location{Latitude: 51.47, Longitude: -0.4543}
+	end := geo.Location{Latitude: 40.6413, Longitude: -73.7781}
+
+	air := geodesicRoute(start, end, "air", 0, 5)
+	if !air.Geodesic || air.CruiseSpeed != defaultAirCruiseSpeed || air.Note == "" {
+		t.Errorf("unexpected air route %+v", air)
+	}
+	// ~2990 nmi at 450 kn is about 6.6 hours
+	if air.Duration < 6.4*3600 || air.Duration > 6.8*3600 {
+		t.Errorf("unexpected air duration %.0f", air.Duration)
+	}
+
+	sea := geodesicRoute(start, end, "sea", 20, 5)
+	if sea.CruiseSpeed != 20 || sea.Distance != air.Distance || sea.Duration <= air.Duration {
+		t.Errorf("unexpected sea route %+v", sea)
+	}
+}
+
+func TestHandleRouteFetchAirMode(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "route_fetch",
+			Arguments: map[string]any{
+				"start": geo.Location{Latitude: 51.47, Longitude: -0.4543},
+				"end":   geo.Location{Latitude: 40.6413, Longitude: -73.7781},
+				"mode":  "air",
+				"units": "nautical",
+			},
+		},
+	}
+	result, err := HandleRouteFetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	AssertSuccessResult(t, result, "Expected success result, but got error")
+
+	var output RouteFetchOutput
+	if err := ParseResultJSON(result, &output); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	if !output.Geodesic || len(osm.DecodePolyline(output.Polyline)) < 100 {
+		t.Errorf("expected a geodesic polyline, got %+v", output)
+	}
+	if output.Units == nil || output.Units.DistanceUnit != "nmi" || output.Units.Speed != 450 {
+		t.Errorf("unexpected units %+v", output.Units)
+	}
+
+	req.Params.Arguments = map[string]any{
+		"start": geo.Location{Latitude: 51.47, Longitude: -0.4543},
+		"end":   geo.Location{Latitude: 40.6413, Longitude: -73.7781},
+		"mode":  "sea",
+		"units": "leagues",
+	}
+	result, err = HandleRouteFetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	AssertErrorResult(t, result, "Expected error for invalid units")
+}
+
+func TestHandleRouteFetchCruiseSpeed(t *testing.T) {
+	for _, speed := range []float64{-1, 0.5, maxCruiseSpeed + 1} {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "route_fetch",
+				Arguments: map[string]any{
+					"start":        geo.Location{Latitude: 51.47, Longitude: -0.4543},
+					"end":          geo.Location{Latitude: 40.6413, Longitude: -73.7781},
+					"mode":         "air",
+					"cruise_speed": speed,
+				},
+			},
+		}
+		result, err := HandleRouteFetch(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		AssertErrorResult(t, result, "Expected error for out of range cruise_speed")
+	}
+}
+
+func TestHandleRouteFetchGeodesicPolyline6(t *testing.T) {
+	start := geo.Location{Latitude: 51.470022, Longitude: -0.454308}
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "route_fetch",
+			Arguments: map[string]any{
+				"start":              start,
+				"end":                geo.Location{Latitude: 40.6413, Longitude: -73.7781},
+				"mode":               "air",
+				"geometry_precision": GeometryPolyline6,
+			},
+		},
+	}
+	result, err := HandleRouteFetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	AssertSuccessResult(t, result, "Expected success result, but got error")
+
+	var output RouteFetchOutput
+	if err := ParseResultJSON(result, &output); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	points := osm.DecodePolylinePrecision(output.Polyline, 6)
+	if len(points) == 0 || math.Abs(points[0].Latitude-start.Latitude) > 1e-7 || math.Abs(points[0].Longitude-start.Longitude) > 1e-7 {
+		t.Errorf("expected the polyline6 path to start at %v, got %+v", start, points[:min(len(points), 1)])
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -17,6 +16,7 @@ import (
 
 	"github.com/NERVsystems/osmmcp/pkg/cache"
 	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
@@ -44,6 +44,22 @@ func GetRouteDirectionsTool() mcp.Tool {
 			mcp.Description("Transportation mode: car, bike, foot"),
 			mcp.DefaultString("car"),
 		),
+		mcp.WithString("units",
+			mcp.Description(unitsDescription),
+		),
+		mcp.WithBoolean("ignore_closures",
+			mcp.Description("Ignore closures reported with report_closure when choosing the route"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithArray("avoid_areas",
+			mcp.Description("Polygons to route around, each an array of {latitude, longitude} points (max 10). Avoidance is best effort; the result reports whether it was honored"),
+		),
+		mcp.WithBoolean("to_entrance",
+			mcp.Description("When the destination is a building, route to its main entrance instead of its centroid. Defaults to true for foot and false otherwise"),
+		),
+		mcp.WithBoolean("pedestrian_safety",
+			mcp.Description("Annotate the route with the crossings on it (signals, zebra, marked, none) and how much of it has sidewalks. Defaults to true for foot and false otherwise"),
+		),
 	)
 }
 
@@ -78,9 +94,49 @@ func HandleGetRouteDirections(ctx context.Context, req mcp.CallToolRequest) (*mc
 	// Map user-friendly mode to OSRM profile
 	profile := mapModeToProfile(mode)
 
-	// Check cache first
-	cacheKey := fmt.Sprintf("route:%s:%f,%f:%f,%f", profile, startLat, startLon, endLat, endLon)
-	if cachedData, found := cache.GetGlobalCache().Get(cacheKey); found {
+	units := mcp.ParseString(req, "units", "")
+	if !validUnits(units) {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid units: %s", units)).
+			WithGuidance("Use 'metric', 'imperial', or 'nautical'").
+			ToMCPResult(), nil
+	}
+
+	avoidAreas, err := parseAvoidAreas(req)
+	if err != nil {
+		logger.Error("invalid avoid areas", "error", err)
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid avoid_areas: %s", err)).ToMCPResult(), nil
+	}
+
+	// Route to the building entrance rather than the centroid when asked.
+	// Failing to find one is not an error; the route ends at the destination.
+	var entrance *BuildingEntrance
+	if mcp.ParseBoolean(req, "to_entrance", entranceDefault(profile)) {
+		start := geo.Location{Latitude: startLat, Longitude: startLon}
+		end := geo.Location{Latitude: endLat, Longitude: endLon}
+		found, ok, err := resolveEntrance(ctx, start, end)
+		switch {
+		case err != nil:
+			logger.Warn("failed to look up building entrance", "error", err)
+		case ok:
+			logger.Debug("routing to building entrance", "entrance", found.ID, "type", found.Type)
+			entrance = found
+			endLat, endLon = found.Location.Latitude, found.Location.Longitude
+		}
+	}
+
+	annotateSafety := mcp.ParseBoolean(req, "pedestrian_safety", safetyDefault(profile))
+
+	// Reported closures penalise the routes through them
+	closures := core.DefaultClosureStore()
+	if mcp.ParseBoolean(req, "ignore_closures", false) || len(closures.Active()) == 0 {
+		closures = nil
+	}
+
+	// Check cache first; avoid-area routes, and routes chosen around
+	// closures, which change, are not cached here
+	cacheable := len(avoidAreas) == 0 && closures == nil
+	cacheKey := fmt.Sprintf("route:%s:%f,%f:%f,%f:%s:%t", profile, startLat, startLon, endLat, endLon, units, annotateSafety)
+	if cachedData, found := cache.GetGlobalCache().Get(cacheKey); found && cacheable {
 		logger.Debug("route cache hit", "key", cacheKey)
 		result, ok := cachedData.(*mcp.CallToolResult)
 		if ok {
@@ -111,25 +167,57 @@ func HandleGetRouteDirections(ctx context.Context, req mcp.CallToolRequest) (*mc
 		},
 	}
 
-	// Execute the route request
-	route, err := core.GetRoute(ctx, coordinates, options)
-	if err != nil {
-		logger.Error("failed to get route", "error", err)
-		if mcpErr, ok := err.(*core.MCPError); ok {
-			return mcpErr.ToMCPResult(), nil
+	// Execute the route request, routing around avoid areas if requested
+	var (
+		bestRoute      core.OSRMRoute
+		avoid          *core.AvoidAreasResult
+		routeClosures  []core.Closure
+		closurePenalty float64
+	)
+	if len(avoidAreas) > 0 {
+		avoid, err = core.RouteAvoidingAreas(ctx, coordinates[0], coordinates[1], options, avoidAreas, closures)
+		if err != nil {
+			logger.Error("failed to get route", "error", err)
+			if mcpErr, ok := err.(*core.MCPError); ok {
+				return mcpErr.ToMCPResult(), nil
+			}
+			return core.ServiceError("OSRM", http.StatusServiceUnavailable,
+				"Failed to communicate with routing service").ToMCPResult(), nil
+		}
+		if !avoid.Honored {
+			logger.Warn("avoid areas not fully honored", "violated", avoid.Violated, "attempts", avoid.Attempts)
+		}
+		bestRoute, routeClosures, closurePenalty = avoid.Route, avoid.Closures, avoid.ClosurePenalty
+	} else {
+		// Re-rank alternatives against reported closures when there are any
+		if closures != nil {
+			options.Alternatives = 2
+		}
+		route, err := core.GetRoute(ctx, coordinates, options)
+		if err != nil {
+			logger.Error("failed to get route", "error", err)
+			if mcpErr, ok := err.(*core.MCPError); ok {
+				return mcpErr.ToMCPResult(), nil
+			}
+			return core.ServiceError("OSRM", http.StatusServiceUnavailable,
+				"Failed to communicate with routing service").ToMCPResult(), nil
 		}
-		return core.ServiceError("OSRM", http.StatusServiceUnavailable,
-			"Failed to communicate with routing service").ToMCPResult(), nil
-	}
 
-	// Check if we have valid route data
-	if len(route.Routes) == 0 {
-		return core.NewError("ROUTE_NOT_FOUND",
-			"No route found between the specified points").ToMCPResult(), nil
-	}
+		// Check if we have valid route data
+		if len(route.Routes) == 0 {
+			return core.NewError("ROUTE_NOT_FOUND",
+				"No route found between the specified points").ToMCPResult(), nil
+		}
 
-	// Get the first route (best match)
-	bestRoute := route.Routes[0]
+		// Get the first route (best match), or the one least delayed by closures
+		bestRoute = route.Routes[0]
+		if closures != nil {
+			bestRoute, routeClosures, closurePenalty = leastPenalizedRoute(route.Routes, core.PolylinePrecision(options.Geometries))
+			if len(routeClosures) > 0 {
+				logger.Warn("best route still affected by closures", "count", len(routeClosures))
+			}
+		}
+	}
 
 	// Extract and decode the polyline
 	polylinePoints := osm.DecodePolyline(bestRoute.Geometry)
@@ -139,6 +227,16 @@ func HandleGetRouteDirections(ctx context.Context, req mcp.CallToolRequest) (*mc
 			"Failed to decode route geometry").ToMCPResult(), nil
 	}
 
+	// Crossings and sidewalks are a best-effort enrichment
+	var safety *PedestrianSafety
+	if annotateSafety {
+		safety, err = annotatePedestrianSafety(ctx, polylinePoints)
+		if err != nil {
+			logger.Warn("pedestrian safety lookup failed", "error", err)
+			safety = nil
+		}
+	}
+
 	// Convert coordinates to the expected format
 	coordinatesArrays := make([][]float64, len(polylinePoints))
 	for i, point := range polylinePoints {
@@ -206,6 +304,15 @@ func HandleGetRouteDirections(ctx context.Context, req mcp.CallToolRequest) (*mc
 		EndPoint   Location `json:"end_point"`
 		RouteFile  string   `json:"route_file,omitempty"`
 		PointCount int      `json:"point_count"`
+
+		Closures       []core.Closure `json:"closures,omitempty"`        // reported closures on this route
+		ClosurePenalty float64        `json:"closure_penalty,omitempty"` // in seconds
+
+		AvoidAreas *core.AvoidAreasResult `json:"avoid_areas,omitempty"`
+		Units      *ConvertedMeasures     `json:"units,omitempty"`
+		Entrance   *BuildingEntrance      `json:"entrance,omitempty"`
+
+		PedestrianSafety *PedestrianSafety `json:"pedestrian_safety,omitempty"`
 	}{
 		Distance: bestRoute.Distance,
 		Duration: bestRoute.Duration,
@@ -219,6 +326,15 @@ func HandleGetRouteDirections(ctx context.Context, req mcp.CallToolRequest) (*mc
 		},
 		RouteFile:  routeFile,
 		PointCount: len(coordinatesArrays),
+
+		Closures:       routeClosures,
+		ClosurePenalty: closurePenalty,
+
+		AvoidAreas: avoid,
+		Units:      convertMeasures(units, bestRoute.Distance, bestRoute.Duration),
+		Entrance:   entrance,
+
+		PedestrianSafety: safety,
 	}
 
 	// Marshal to JSON
@@ -232,11 +348,34 @@ func HandleGetRouteDirections(ctx context.Context, req mcp.CallToolRequest) (*mc
 	result := mcp.NewToolResultText(string(resultBytes))
 
 	// Cache the result
-	cache.GetGlobalCache().Set(cacheKey, result)
+	if cacheable {
+		cache.GetGlobalCache().SetFor(cache.ClassRoute, cacheKey, result)
+	}
 
 	return result, nil
 }
 
+// parseAvoidAreas reads and validates the optional avoid_areas argument
+func parseAvoidAreas(req mcp.CallToolRequest) ([][]geo.Location, error) {
+	args, ok := req.Params.Arguments.(map[string]any)
+	if !ok || args["avoid_areas"] == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(args["avoid_areas"])
+	if err != nil {
+		return nil, err
+	}
+	var areas [][]geo.Location
+	if err := json.Unmarshal(data, &areas); err != nil {
+		return nil, fmt.Errorf("expected an array of polygons, each an array of {latitude, longitude} points")
+	}
+	if err := core.ValidateAvoidAreas(areas); err != nil {
+		return nil, err
+	}
+	return areas, nil
+}
+
 // SuggestMeetingPointTool returns a tool definition for suggesting meeting points
 func SuggestMeetingPointTool() mcp.Tool {
 	return mcp.NewTool("suggest_meeting_point",
@@ -387,9 +526,9 @@ func HandleSuggestMeetingPoint(ctx context.Context, req mcp.CallToolRequest) (*m
 		})
 	}
 
-	// Sort by average distance (closest first)
-	sort.Slice(scoredPlaces, func(i, j int) bool {
-		return scoredPlaces[i].AverageDistance < scoredPlaces[j].AverageDistance
+	// Sort by average distance (closest first), breaking ties by ID
+	sortByDistanceThenID(scoredPlaces, func(p ScoredPlace) (float64, string) {
+		return p.AverageDistance, p.Place.ID
 	})
 
 	// Create output
@@ -12,6 +12,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
@@ -94,6 +95,15 @@ func GetRouteTool() mcp.Tool {
 		mcp.WithBoolean("alternatives",
 			mcp.Description("Whether to return alternative routes"),
 		),
+		mcp.WithString("geometry_precision",
+			mcp.Description(geometryPrecisionDescription),
+			mcp.Enum(geometryFormats...),
+			mcp.DefaultString(GeometryPolyline5),
+		),
+		mcp.WithNumber("max_points",
+			mcp.Description(maxPointsDescription),
+			mcp.DefaultNumber(0),
+		),
 	)
 }
 
@@ -108,13 +118,17 @@ func HandleGetRoute(ctx context.Context, rawInput mcp.CallToolRequest) (*mcp.Cal
 	endLon := mcp.ParseFloat64(rawInput, "end_lon", 0)
 	profile := mcp.ParseString(rawInput, "profile", "driving")
 	alternatives := mcp.ParseBoolean(rawInput, "alternatives", false)
+	geometry, geomErr := parseRouteGeometry(rawInput)
+	if geomErr != nil {
+		return geomErr.ToMCPResult(), nil
+	}
 
 	// Validate parameters
-	if startLat < -90 || startLat > 90 || endLat < -90 || endLat > 90 {
-		return ErrorResponse("Invalid latitude values"), nil
+	if err := core.ValidateCoords(startLat, startLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
-	if startLon < -180 || startLon > 180 || endLon < -180 || endLon > 180 {
-		return ErrorResponse("Invalid longitude values"), nil
+	if err := core.ValidateCoords(endLat, endLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
 
 	// Build request URL
@@ -133,8 +147,9 @@ func HandleGetRoute(ctx context.Context, rawInput mcp.CallToolRequest) (*mcp.Cal
 
 	// Add query parameters
 	q := reqURL.Query()
-	q.Set("overview", "full")
-	q.Set("geometries", "geojson")
+	geometries, overview := geometry.osrmParameters("full")
+	q.Set("overview", overview)
+	q.Set("geometries", geometries)
 	q.Set("alternatives", strconv.FormatBool(alternatives))
 	q.Set("steps", "true")
 	q.Set("annotations", "true")
@@ -157,9 +172,9 @@ func HandleGetRoute(ctx context.Context, rawInput mcp.CallToolRequest) (*mcp.Cal
 
 	// Parse response
 	var osrmResp OSRMRouteResponse
-	if err := json.NewDecoder(resp.Body).Decode(&osrmResp); err != nil {
+	if err := core.DecodeUpstream("OSRM", resp.Body, &osrmResp, "code"); err != nil {
 		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse routing response"), nil
+		return err.ToMCPResult(), nil
 	}
 
 	// Check for errors
@@ -185,6 +200,7 @@ func HandleGetRoute(ctx context.Context, rawInput mcp.CallToolRequest) (*mcp.Cal
 			Latitude:  endLat,
 			Longitude: endLon,
 		},
+		RouteGeometry: geometry.render(osrmRoute.Geometry, geometry.precision()),
 	}
 
 	// Extract instructions from steps
@@ -6,13 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"net/http"
-	"net/url"
-	"sort"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
@@ -27,12 +25,14 @@ type School struct {
 	IsPublic    bool     `json:"is_public,omitempty"`    // true for public schools
 	Website     string   `json:"website,omitempty"`      // school website if available
 	PhoneNumber string   `json:"phone_number,omitempty"` // contact number if available
+	Contact     *Contact `json:"contact,omitempty"`      // normalized contact details
 }
 
 // FindSchoolsNearbyTool returns a tool definition for finding schools near a location
 func FindSchoolsNearbyTool() mcp.Tool {
+	limits := searchLimitsFor("find_schools_nearby")
 	return mcp.NewTool("find_schools_nearby",
-		mcp.WithDescription("Find educational institutions near a specific location"),
+		mcp.WithDescription("Find educational institutions near a specific location. "+orderingDescription),
 		mcp.WithNumber("latitude",
 			mcp.Required(),
 			mcp.Description("The latitude coordinate of the center point"),
@@ -42,16 +42,19 @@ func FindSchoolsNearbyTool() mcp.Tool {
 			mcp.Description("The longitude coordinate of the center point"),
 		),
 		mcp.WithNumber("radius",
-			mcp.Description("Search radius in meters (max 5000)"),
-			mcp.DefaultNumber(2000),
+			mcp.Description(limits.radiusDescription()),
+			mcp.DefaultNumber(limits.DefaultRadius),
 		),
 		mcp.WithString("school_type",
 			mcp.Description("Optional school type filter (e.g., elementary, secondary, university, college)"),
 			mcp.DefaultString(""),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results to return"),
-			mcp.DefaultNumber(10),
+			mcp.Description(limits.limitDescription()),
+			mcp.DefaultNumber(float64(limits.DefaultLimit)),
+		),
+		mcp.WithString("cursor",
+			mcp.Description(cursorDescription),
 		),
 	)
 }
@@ -59,29 +62,28 @@ func FindSchoolsNearbyTool() mcp.Tool {
 // HandleFindSchoolsNearby implements finding schools functionality
 func HandleFindSchoolsNearby(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logger := slog.Default().With("tool", "find_schools_nearby")
+	limits := searchLimitsFor("find_schools_nearby")
 
 	// Parse input parameters
 	latitude := mcp.ParseFloat64(req, "latitude", 0)
 	longitude := mcp.ParseFloat64(req, "longitude", 0)
-	radius := mcp.ParseFloat64(req, "radius", 2000)
+	radius := mcp.ParseFloat64(req, "radius", limits.DefaultRadius)
 	schoolType := mcp.ParseString(req, "school_type", "")
-	limit := int(mcp.ParseFloat64(req, "limit", 10))
 
 	// Basic validation
-	if latitude < -90 || latitude > 90 {
-		return ErrorResponse("Latitude must be between -90 and 90"), nil
-	}
-	if longitude < -180 || longitude > 180 {
-		return ErrorResponse("Longitude must be between -180 and 180"), nil
+	if err := core.ValidateCoords(latitude, longitude); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
-	if radius <= 0 || radius > 5000 {
-		return ErrorResponse("Radius must be between 1 and 5000 meters"), nil
+	if radius <= 0 || radius > limits.MaxRadius {
+		return ErrorResponse(limits.radiusGuidance()), nil
 	}
-	if limit <= 0 {
-		limit = 10 // Default limit
+	limit, errResult := parseListLimit(req, limits, logger)
+	if errResult != nil {
+		return errResult, nil
 	}
-	if limit > 50 {
-		limit = 50 // Max limit
+	opts, errResult := parseListOptions(req, limit)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Build Overpass query for schools
@@ -101,61 +103,16 @@ func HandleFindSchoolsNearby(ctx context.Context, req mcp.CallToolRequest) (*mcp
 	// Complete the query
 	queryBuilder.WriteString(");out center;")
 
-	// Build request
-	reqURL, err := url.Parse(osm.OverpassBaseURL)
-	if err != nil {
-		logger.Error("failed to parse URL", "error", err)
-		return ErrorResponse("Internal server error"), nil
-	}
-
-	// Make HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), strings.NewReader("data="+url.QueryEscape(queryBuilder.String())))
+	// Execute the query
+	elements, err := executeOverpassQuery(ctx, queryBuilder.String())
 	if err != nil {
-		logger.Error("failed to create request", "error", err)
-		return ErrorResponse("Failed to create request"), nil
-	}
-
-	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	httpReq.Header.Set("User-Agent", osm.UserAgent)
-
-	// Execute request
-	client := osm.GetClient(ctx)
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		logger.Error("failed to execute request", "error", err)
+		logger.Error("failed to execute query", "error", err)
 		return ErrorResponse("Failed to communicate with OSM service"), nil
 	}
-	defer resp.Body.Close()
-
-	// Process response
-	if resp.StatusCode != http.StatusOK {
-		logger.Error("OSM service returned error", "status", resp.StatusCode)
-		return ErrorResponse(fmt.Sprintf("OSM service error: %d", resp.StatusCode)), nil
-	}
-
-	// Parse response
-	var overpassResp struct {
-		Elements []struct {
-			ID     int     `json:"id"`
-			Type   string  `json:"type"`
-			Lat    float64 `json:"lat,omitempty"`
-			Lon    float64 `json:"lon,omitempty"`
-			Center *struct {
-				Lat float64 `json:"lat"`
-				Lon float64 `json:"lon"`
-			} `json:"center,omitempty"`
-			Tags map[string]string `json:"tags"`
-		} `json:"elements"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
-		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse schools data"), nil
-	}
 
 	// Convert to School objects and calculate distances
 	schools := make([]School, 0)
-	for _, element := range overpassResp.Elements {
+	for _, element := range elements {
 		// Get coordinates (handling both nodes and ways)
 		var lat, lon float64
 		if element.Type == "node" {
@@ -226,26 +183,22 @@ func HandleFindSchoolsNearby(ctx context.Context, req mcp.CallToolRequest) (*mcp
 			IsPublic:    element.Tags["school:type"] == "public" || element.Tags["operator:type"] == "public",
 			Website:     element.Tags["website"] + element.Tags["contact:website"],
 			PhoneNumber: element.Tags["phone"] + element.Tags["contact:phone"],
+			Contact:     parseContact(element.Tags),
 		}
 
 		schools = append(schools, school)
 	}
 
-	// Sort schools by distance (closest first)
-	sort.Slice(schools, func(i, j int) bool {
-		return schools[i].Distance < schools[j].Distance
-	})
-
-	// Limit results
-	if len(schools) > limit {
-		schools = schools[:limit]
-	}
+	// Filter, order and take the requested page
+	schools, _, nextCursor := processList(schools, schoolSortKey, opts)
 
 	// Create output
 	output := struct {
-		Schools []School `json:"schools"`
+		Schools    []School `json:"schools"`
+		NextCursor string   `json:"next_cursor,omitempty"`
 	}{
-		Schools: schools,
+		Schools:    schools,
+		NextCursor: nextCursor,
 	}
 
 	// Return result
@@ -257,3 +210,8 @@ func HandleFindSchoolsNearby(ctx context.Context, req mcp.CallToolRequest) (*mcp
 
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
+
+// schoolSortKey returns the distance and ID schools are ordered by
+func schoolSortKey(s School) (float64, string) {
+	return s.Distance, s.ID
+}
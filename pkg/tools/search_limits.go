@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SearchLimits are the default and maximum search radius and number of
+// results of a search tool. Zero fields are unset and fall back to the
+// deployment setting, then to the tool's built-in value.
+type SearchLimits struct {
+	DefaultRadius float64 // meters
+	MaxRadius     float64 // meters
+	DefaultLimit  int
+	MaxLimit      int
+}
+
+// builtinSearchLimits are the limits of each search tool when a deployment
+// sets none. Tools without a radius parameter leave the radius fields zero.
+var builtinSearchLimits = map[string]SearchLimits{
+	"find_nearby_places":           {DefaultRadius: 1000, MaxRadius: 50000, DefaultLimit: 10, MaxLimit: 50},
+	"search_category":              {DefaultLimit: 20, MaxLimit: 100},
+	"search_places_by_name":        {DefaultRadius: 2000, MaxRadius: 20000, DefaultLimit: 10, MaxLimit: 50},
+	"explore_area":                 {MaxRadius: 5000},
+	"find_parking_facilities":      {DefaultRadius: 1000, MaxRadius: 5000, DefaultLimit: 10, MaxLimit: 50},
+	"find_schools_nearby":          {DefaultRadius: 2000, MaxRadius: 5000, DefaultLimit: 10, MaxLimit: 50},
+	"find_charging_stations":       {DefaultRadius: 5000, MaxRadius: 5000, DefaultLimit: 10, MaxLimit: 50},
+	"find_route_charging_stations": {DefaultLimit: 10, MaxLimit: 50},
+}
+
+var (
+	// deploymentSearchLimits apply to every search tool
+	deploymentSearchLimits SearchLimits
+	// toolSearchLimits override deploymentSearchLimits for single tools
+	toolSearchLimits map[string]SearchLimits
+)
+
+// SearchLimitTools returns the names of the tools whose limits are
+// configurable
+func SearchLimitTools() []string {
+	names := make([]string, 0, len(builtinSearchLimits))
+	for name := range builtinSearchLimits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetSearchLimits sets the default and maximum radius and number of results
+// of the search tools, for all of them and for single tools. Self-hosted
+// backends can afford larger queries than the public ones. It must be called
+// before the tool definitions are created, as their schemas advertise the
+// limits.
+func SetSearchLimits(defaults SearchLimits, perTool map[string]SearchLimits) error {
+	for name := range perTool {
+		if _, ok := builtinSearchLimits[name]; !ok {
+			return fmt.Errorf("unknown search tool %q (tools: %s)", name, strings.Join(SearchLimitTools(), ", "))
+		}
+	}
+	for _, name := range SearchLimitTools() {
+		limits := resolveSearchLimits(name, defaults, perTool)
+		if limits.DefaultRadius > limits.MaxRadius {
+			return fmt.Errorf("%s: default radius %g exceeds the maximum radius %g", name, limits.DefaultRadius, limits.MaxRadius)
+		}
+		if limits.DefaultLimit > limits.MaxLimit {
+			return fmt.Errorf("%s: default limit %d exceeds the maximum limit %d", name, limits.DefaultLimit, limits.MaxLimit)
+		}
+	}
+	deploymentSearchLimits, toolSearchLimits = defaults, perTool
+	return nil
+}
+
+// searchLimitsFor returns the limits a search tool applies
+func searchLimitsFor(tool string) SearchLimits {
+	return resolveSearchLimits(tool, deploymentSearchLimits, toolSearchLimits)
+}
+
+// resolveSearchLimits layers the tool's own settings over the deployment's
+// over the built-in limits. Settings a tool has no parameter for, such as
+// the default radius of one whose radius is required, stay unset.
+func resolveSearchLimits(tool string, defaults SearchLimits, perTool map[string]SearchLimits) SearchLimits {
+	builtin := builtinSearchLimits[tool]
+	limits := builtin.merge(defaults).merge(perTool[tool])
+	if builtin.DefaultRadius == 0 {
+		limits.DefaultRadius = 0
+	}
+	if builtin.MaxRadius == 0 {
+		limits.MaxRadius = 0
+	}
+	if builtin.MaxLimit == 0 {
+		limits.DefaultLimit, limits.MaxLimit = 0, 0
+	}
+	return limits
+}
+
+// merge returns l with the fields set in other replacing its own
+func (l SearchLimits) merge(other SearchLimits) SearchLimits {
+	if other.DefaultRadius > 0 {
+		l.DefaultRadius = other.DefaultRadius
+	}
+	if other.MaxRadius > 0 {
+		l.MaxRadius = other.MaxRadius
+	}
+	if other.DefaultLimit > 0 {
+		l.DefaultLimit = other.DefaultLimit
+	}
+	if other.MaxLimit > 0 {
+		l.MaxLimit = other.MaxLimit
+	}
+	return l
+}
+
+// clampLimit returns the default limit for a missing or non-positive one
+// and the maximum for one above it
+func (l SearchLimits) clampLimit(limit int) int {
+	if limit <= 0 {
+		return l.DefaultLimit
+	}
+	return min(limit, l.MaxLimit)
+}
+
+// radiusDescription documents the radius parameter of a search tool
+func (l SearchLimits) radiusDescription() string {
+	return fmt.Sprintf("Search radius in meters (max %g)", l.MaxRadius)
+}
+
+// limitDescription documents the limit parameter of a search tool
+func (l SearchLimits) limitDescription() string {
+	return fmt.Sprintf("Maximum number of results to return (max %d)", l.MaxLimit)
+}
+
+// radiusGuidance explains the valid range of a search radius
+func (l SearchLimits) radiusGuidance() string {
+	return fmt.Sprintf("Radius must be positive and at most %g meters", l.MaxRadius)
+}
+
+// ParseToolSearchLimits parses per-tool search limits given as
+// tool:setting=value entries, with further settings of the same tool
+// following after commas and tools separated by semicolons, e.g.
+// find_nearby_places:max_radius=20000,max_limit=200;search_category:max_limit=500.
+// The settings are default_radius, max_radius, default_limit and max_limit.
+func ParseToolSearchLimits(spec string) (map[string]SearchLimits, error) {
+	perTool := make(map[string]SearchLimits)
+	tool := ""
+	for _, entry := range strings.FieldsFunc(spec, func(r rune) bool { return r == ';' || r == ',' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if name, setting, ok := strings.Cut(entry, ":"); ok {
+			tool, entry = strings.TrimSpace(name), setting
+		}
+		if tool == "" {
+			return nil, fmt.Errorf("search limit %q must follow a tool name, e.g. find_nearby_places:max_radius=20000", entry)
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("search limit %q for %s must be setting=value", entry, tool)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%s for %s must be a positive number, got %q", key, tool, value)
+		}
+
+		limits := perTool[tool]
+		switch key {
+		case "default_radius":
+			limits.DefaultRadius = n
+		case "max_radius":
+			limits.MaxRadius = n
+		case "default_limit":
+			limits.DefaultLimit = int(n)
+		case "max_limit":
+			limits.MaxLimit = int(n)
+		default:
+			return nil, fmt.Errorf("unknown search limit %q for %s (settings: default_radius, max_radius, default_limit, max_limit)", key, tool)
+		}
+		perTool[tool] = limits
+	}
+	return perTool, nil
+}
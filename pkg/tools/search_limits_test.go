@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseToolSearchLimits(t *testing.T) {
+	perTool, err := ParseToolSearchLimits("find_nearby_places:max_radius=20000,max_limit=200; search_category:default_limit=30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := perTool["find_nearby_places"]; got != (SearchLimits{MaxRadius: 20000, MaxLimit: 200}) {
+		t.Errorf("find_nearby_places limits = %+v", got)
+	}
+	if got := perTool["search_category"]; got != (SearchLimits{DefaultLimit: 30}) {
+		t.Errorf("search_category limits = %+v", got)
+	}
+
+	// A config file list is joined with commas
+	perTool, err = ParseToolSearchLimits("find_nearby_places:max_limit=200,search_category:max_limit=500")
+	if err != nil || perTool["find_nearby_places"].MaxLimit != 200 || perTool["search_category"].MaxLimit != 500 {
+		t.Errorf("unexpected limits %+v, error %v", perTool, err)
+	}
+
+	for _, spec := range []string{
+		"max_radius=20000",
+		"find_nearby_places:max_radius",
+		"find_nearby_places:max_radius=-1",
+		"find_nearby_places:radius=100",
+	} {
+		if _, err := ParseToolSearchLimits(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestSetSearchLimits(t *testing.T) {
+	t.Cleanup(func() { SetSearchLimits(SearchLimits{}, nil) })
+
+	if err := SetSearchLimits(SearchLimits{}, map[string]SearchLimits{"geocode_address": {MaxLimit: 5}}); err == nil {
+		t.Error("expected an error for a tool without search limits")
+	}
+	if err := SetSearchLimits(SearchLimits{DefaultRadius: 8000}, nil); err == nil {
+		t.Error("expected an error for a default radius above a tool's maximum")
+	}
+
+	err := SetSearchLimits(SearchLimits{MaxRadius: 20000, MaxLimit: 200},
+		map[string]SearchLimits{"find_nearby_places": {DefaultRadius: 2000, MaxLimit: 500}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := searchLimitsFor("find_nearby_places"); got != (SearchLimits{DefaultRadius: 2000, MaxRadius: 20000, DefaultLimit: 10, MaxLimit: 500}) {
+		t.Errorf("find_nearby_places limits = %+v", got)
+	}
+	if got := searchLimitsFor("find_parking_facilities"); got != (SearchLimits{DefaultRadius: 1000, MaxRadius: 20000, DefaultLimit: 10, MaxLimit: 200}) {
+		t.Errorf("find_parking_facilities limits = %+v", got)
+	}
+	// search_category has no radius parameter
+	if got := searchLimitsFor("search_category"); got != (SearchLimits{DefaultLimit: 20, MaxLimit: 200}) {
+		t.Errorf("search_category limits = %+v", got)
+	}
+
+	schema := FindNearbyPlacesTool().InputSchema.Properties
+	radius := schema["radius"].(map[string]any)
+	if radius["default"] != 2000.0 || !strings.Contains(radius["description"].(string), "max 20000") {
+		t.Errorf("radius schema does not advertise the limits: %v", radius)
+	}
+	limit := schema["limit"].(map[string]any)
+	if !strings.Contains(limit["description"].(string), "max 500") {
+		t.Errorf("limit schema does not advertise the limits: %v", limit)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"latitude": 43.7384, "longitude": 7.4246, "radius": 30000, "category": "cafe"}
+	result, err := HandleFindNearbyPlaces(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || !strings.Contains(text, "at most 20000 meters") {
+		t.Errorf("expected the configured maximum radius to be enforced, got %s", text)
+	}
+}
@@ -0,0 +1,333 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// maxNameSearchElements caps the elements fetched by one name search, so
+	// a common name in a large area does not download a huge response
+	maxNameSearchElements = 500
+	// maxNameSearchNarrowings is how many times a search reaching the cap is
+	// repeated over a smaller area around its center. Overpass returns
+	// elements in ID order, so the nearest matches are only certain to be
+	// included when the cap is not reached.
+	maxNameSearchNarrowings = 3
+	// nameSearchNarrowing is the factor the radius, or bbox extent, shrinks
+	// by on each narrowing
+	nameSearchNarrowing = 0.25
+)
+
+// NamedPlace is a place found by name, with the tag its name matched when
+// that was not name itself
+type NamedPlace struct {
+	Place
+	MatchedTag  string `json:"matched_tag,omitempty"`  // e.g. name:en
+	MatchedName string `json:"matched_name,omitempty"` // value of matched_tag
+}
+
+// SearchPlacesByNameInput defines the input parameters for search_places_by_name
+type SearchPlacesByNameInput struct {
+	Query               string           `json:"query"`
+	Regex               bool             `json:"regex"`
+	IncludeTranslations *bool            `json:"include_translations,omitempty"`
+	Latitude            *float64         `json:"latitude,omitempty"`
+	Longitude           *float64         `json:"longitude,omitempty"`
+	Radius              float64          `json:"radius,omitempty"`
+	BBox                *geo.BoundingBox `json:"bbox,omitempty"`
+}
+
+// SearchPlacesByNameTool returns a tool definition for searching places by
+// name within an area
+func SearchPlacesByNameTool() mcp.Tool {
+	limits := searchLimitsFor("search_places_by_name")
+	return mcp.NewTool("search_places_by_name",
+		mcp.WithDescription("Find places whose name contains a text, e.g. the nearest \"Starbucks\", within a radius of a point or inside a bounding box. Matching is case-insensitive and also covers name:* translations such as name:en. "+orderingDescription+"; with a bbox, distances are measured from its center"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Text the name must contain, e.g. \"Starbucks\", or a regular expression when regex is true"),
+		),
+		mcp.WithBoolean("regex",
+			mcp.Description("Treat query as a case-insensitive POSIX extended regular expression, e.g. \"^(Starbucks|Costa)\""),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_translations",
+			mcp.Description("Also match name:* tags such as name:en and name:ja"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithNumber("latitude",
+			mcp.Description("Latitude of the search center; give latitude and longitude or bbox"),
+			mcp.Min(-90),
+			mcp.Max(90),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Description("Longitude of the search center"),
+			mcp.Min(-180),
+			mcp.Max(180),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description(limits.radiusDescription()),
+			mcp.DefaultNumber(limits.DefaultRadius),
+		),
+		mcp.WithObject("bbox",
+			mcp.Description("Bounding box {minLat, minLon, maxLat, maxLon} to search instead of a radius"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(limits.limitDescription()),
+			mcp.DefaultNumber(float64(limits.DefaultLimit)),
+		),
+		mcp.WithString("cursor",
+			mcp.Description(cursorDescription),
+		),
+	)
+}
+
+// HandleSearchPlacesByName finds places by name within an area
+func HandleSearchPlacesByName(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "search_places_by_name")
+	limits := searchLimitsFor("search_places_by_name")
+
+	var input SearchPlacesByNameInput
+	if err := req.BindArguments(&input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	input.Query = strings.TrimSpace(input.Query)
+	if input.Query == "" {
+		return core.NewError(core.ErrEmptyParameter, "query must not be empty").
+			WithGuidance("Provide part of the name to look for, e.g. \"Starbucks\"").
+			ToMCPResult(), nil
+	}
+	if err := core.ValidateStringLength(input.Query, 1, maxAddressLength); err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+
+	pattern := regexp.QuoteMeta(input.Query)
+	if input.Regex {
+		pattern = input.Query
+		// Overpass evaluates POSIX extended regular expressions, so Perl
+		// syntax such as \d or (?:...) would fail there
+		if _, err := syntax.Parse(pattern, syntax.POSIX); err != nil {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid regular expression: %v", err)).
+				WithGuidance("Use POSIX extended syntax, e.g. [0-9] instead of \\d, or set regex to false to match the query as plain text").
+				ToMCPResult(), nil
+		}
+	}
+	matcher, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Invalid regular expression: %v", err)).
+			WithGuidance("Fix the pattern, or set regex to false to match the query as plain text").
+			ToMCPResult(), nil
+	}
+
+	area, errResult := nameSearchArea(input, limits)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	limit, errResult := parseListLimit(req, limits, logger)
+	if errResult != nil {
+		return errResult, nil
+	}
+	opts, errResult := parseListOptions(req, limit)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// Narrow the area around its center while the search reaches the
+	// element cap, so the matches returned are the nearest
+	translations := input.IncludeTranslations == nil || *input.IncludeTranslations
+	var elements []osm.OverpassElement
+	scale := 1.0
+	for narrowings := 0; ; narrowings++ {
+		elements, err = executeOverpassQuery(ctx, buildPlaceNameQuery(pattern, area.filter(scale), translations))
+		if err != nil {
+			logger.Error("failed to search places by name", "error", err)
+			if mcpErr, ok := err.(*core.MCPError); ok {
+				return mcpErr.ToMCPResult(), nil
+			}
+			return core.ServiceError("Overpass", http.StatusServiceUnavailable,
+				"Failed to search places by name").ToMCPResult(), nil
+		}
+		if len(elements) < maxNameSearchElements || narrowings == maxNameSearchNarrowings {
+			break
+		}
+		scale *= nameSearchNarrowing
+	}
+	center := area.center
+
+	places := make([]NamedPlace, 0, len(elements))
+	for _, element := range elements {
+		place, ok := namedPlace(element, matcher, translations)
+		if !ok {
+			continue
+		}
+		place.Distance = geo.HaversineDistance(center.Latitude, center.Longitude,
+			place.Location.Latitude, place.Location.Longitude)
+		places = append(places, place)
+	}
+
+	page, total, nextCursor := processList(places, func(p NamedPlace) (float64, string) {
+		return p.Distance, p.ID
+	}, opts)
+
+	logger.Info("searched places by name", "query", input.Query, "matched", total)
+
+	output := struct {
+		Places     []NamedPlace `json:"places"`
+		Total      int          `json:"total"`
+		NextCursor string       `json:"next_cursor,omitempty"`
+		// Narrowed is set when the area was shrunk around its center to
+		// stay under the element cap: every match near the center is
+		// included, but farther ones may be missing
+		Narrowed bool `json:"narrowed,omitempty"`
+		// Truncated is set when even the narrowed search hit the element
+		// cap, so closer matches may be missing; narrow the area or the query
+		Truncated bool `json:"truncated,omitempty"`
+	}{
+		Places:     page,
+		Total:      total,
+		NextCursor: nextCursor,
+		Narrowed:   scale < 1,
+		Truncated:  len(elements) >= maxNameSearchElements,
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// nameSearchBounds is the area of a name search: a radius around center,
+// or a bbox whose center distances are measured from
+type nameSearchBounds struct {
+	center geo.Location
+	radius float64
+	bbox   *geo.BoundingBox
+}
+
+// filter returns the Overpass area filter of the bounds shrunk around
+// their center by scale
+func (b nameSearchBounds) filter(scale float64) string {
+	if b.bbox == nil {
+		return fmt.Sprintf("(around:%.1f,%.6f,%.6f)", b.radius*scale, b.center.Latitude, b.center.Longitude)
+	}
+	halfLat := (b.bbox.MaxLat - b.bbox.MinLat) / 2 * scale
+	halfLon := (b.bbox.MaxLon - b.bbox.MinLon) / 2 * scale
+	return fmt.Sprintf("(%.6f,%.6f,%.6f,%.6f)", b.center.Latitude-halfLat, b.center.Longitude-halfLon,
+		b.center.Latitude+halfLat, b.center.Longitude+halfLon)
+}
+
+// nameSearchArea validates the search area
+func nameSearchArea(input SearchPlacesByNameInput, limits SearchLimits) (nameSearchBounds, *mcp.CallToolResult) {
+	if input.BBox != nil {
+		box := input.BBox
+		if err := core.ValidateCoords(box.MinLat, box.MinLon); err != nil {
+			return nameSearchBounds{}, core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid bbox: %s", err)).ToMCPResult()
+		}
+		if err := core.ValidateCoords(box.MaxLat, box.MaxLon); err != nil {
+			return nameSearchBounds{}, core.NewError(core.ErrInvalidInput, fmt.Sprintf("Invalid bbox: %s", err)).ToMCPResult()
+		}
+		if box.MinLat >= box.MaxLat || box.MinLon >= box.MaxLon {
+			return nameSearchBounds{}, core.NewError(core.ErrInvalidInput, "Invalid bbox: min values must be less than max values").ToMCPResult()
+		}
+		return nameSearchBounds{center: box.Center(), bbox: box}, nil
+	}
+
+	if input.Latitude == nil || input.Longitude == nil {
+		return nameSearchBounds{}, core.NewError(core.ErrMissingParameter, "A search area is required").
+			WithGuidance("Pass latitude and longitude with an optional radius, or a bbox").
+			ToMCPResult()
+	}
+	if err := core.ValidateCoords(*input.Latitude, *input.Longitude); err != nil {
+		return nameSearchBounds{}, core.NewError(core.ErrInvalidInput, err.Error()).ToMCPResult()
+	}
+	radius := input.Radius
+	if radius == 0 {
+		radius = limits.DefaultRadius
+	}
+	if err := core.ValidateRadius(radius, limits.MaxRadius); err != nil {
+		return nameSearchBounds{}, core.NewError(core.ErrInvalidRadius, err.Error()).
+			WithGuidance(limits.radiusGuidance()).
+			ToMCPResult()
+	}
+	center := geo.Location{Latitude: *input.Latitude, Longitude: *input.Longitude}
+	return nameSearchBounds{center: center, radius: radius}, nil
+}
+
+// buildPlaceNameQuery builds an Overpass query for elements in an area
+// whose name, and optionally a name:* translation, matches a pattern
+func buildPlaceNameQuery(pattern, area string, translations bool) string {
+	key := `"name"`
+	if translations {
+		key = `~"^name(:.+)?$"`
+	}
+	value := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(pattern) + `"`
+
+	var query strings.Builder
+	query.WriteString("[out:json][timeout:25];(")
+	for _, osmType := range []string{"node", "way", "relation"} {
+		fmt.Fprintf(&query, "%s[%s~%s,i]%s;", osmType, key, value, area)
+	}
+	fmt.Fprintf(&query, ");out center %d;", maxNameSearchElements)
+	return query.String()
+}
+
+// namedPlace converts an element found by name, recording the translation
+// that matched when name itself did not. Elements named only in a
+// translation take that as their name.
+func namedPlace(element osm.OverpassElement, matcher *regexp.Regexp, translations bool) (NamedPlace, bool) {
+	var matchedTag string
+	if !matcher.MatchString(element.Tags["name"]) {
+		if !translations {
+			return NamedPlace{}, false
+		}
+		keys := make([]string, 0)
+		for key := range element.Tags {
+			if strings.HasPrefix(key, "name:") && matcher.MatchString(element.Tags[key]) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			return NamedPlace{}, false
+		}
+		sort.Strings(keys) // prefer the same translation on every call
+		matchedTag = keys[0]
+	}
+
+	if element.Tags["name"] == "" {
+		// Do not change the tags of the cached response
+		element.Tags = maps.Clone(element.Tags)
+		element.Tags["name"] = element.Tags[matchedTag]
+	}
+	place, ok := elementToPlace(element)
+	if !ok {
+		return NamedPlace{}, false
+	}
+
+	named := NamedPlace{Place: place}
+	if matchedTag != "" {
+		named.MatchedTag = matchedTag
+		named.MatchedName = element.Tags[matchedTag]
+	}
+	return named, true
+}
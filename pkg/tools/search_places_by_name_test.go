@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestBuildPlaceNameQuery(t *testing.T) {
+	query := buildPlaceNameQuery(regexp.QuoteMeta(`Joe's "Cafe" 2.0`), "(around:500.0,1.000000,2.000000)", true)
+	want := `node[~"^name(:.+)?$"~"Joe's \"Cafe\" 2\\.0",i](around:500.0,1.000000,2.000000);`
+	if !strings.Contains(query, want) {
+		t.Errorf("query %s is missing %s", query, want)
+	}
+	if !strings.Contains(query, "relation[") || !strings.HasSuffix(query, ");out center 500;") {
+		t.Errorf("unexpected query %s", query)
+	}
+
+	query = buildPlaceNameQuery("Starbucks", "(1.0,2.0,3.0,4.0)", false)
+	if !strings.Contains(query, `way["name"~"Starbucks",i](1.0,2.0,3.0,4.0);`) {
+		t.Errorf("expected a name-only filter, got %s", query)
+	}
+}
+
+func TestNamedPlace(t *testing.T) {
+	matcher := regexp.MustCompile("(?i)" + regexp.QuoteMeta("starbucks"))
+	element := func(tags map[string]string) osm.OverpassElement {
+		return osm.OverpassElement{ID: 7, Type: "node", Lat: 35.6, Lon: 139.7, Tags: tags}
+	}
+
+	place, ok := namedPlace(element(map[string]string{"name": "Starbucks Coffee", "amenity": "cafe"}), matcher, true)
+	if !ok || place.Name != "Starbucks Coffee" || place.MatchedTag != "" || place.ID != "node/7" {
+		t.Errorf("name match: got %+v, %v", place, ok)
+	}
+
+	tags := map[string]string{"name": "スターバックス", "name:ja": "スターバックス", "name:en": "Starbucks", "name:fr": "Starbucks"}
+	place, ok = namedPlace(element(tags), matcher, true)
+	if !ok || place.Name != "スターバックス" || place.MatchedTag != "name:en" || place.MatchedName != "Starbucks" {
+		t.Errorf("translation match: got %+v, %v", place, ok)
+	}
+	if _, ok := namedPlace(element(tags), matcher, false); ok {
+		t.Error("translations should not match when excluded")
+	}
+
+	tags = map[string]string{"name:en": "Starbucks Reserve"}
+	place, ok = namedPlace(element(tags), matcher, true)
+	if !ok || place.Name != "Starbucks Reserve" {
+		t.Errorf("translation-only name: got %+v, %v", place, ok)
+	}
+	if _, changed := tags["name"]; changed {
+		t.Error("the element's tags must not be modified")
+	}
+
+	if _, ok := namedPlace(element(map[string]string{"name": "Costa"}), matcher, true); ok {
+		t.Error("a non-matching element should be dropped")
+	}
+}
+
+func TestSearchPlacesByNamePOSIXRegex(t *testing.T) {
+	for _, pattern := range []string{`Store \d+`, `(?:Costa|Starbucks)`, `\w+ Cafe`} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"query": pattern, "regex": true, "latitude": 51.5, "longitude": -0.1}
+		result, err := HandleSearchPlacesByName(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || !strings.Contains(text, "Invalid regular expression") {
+			t.Errorf("%s: expected a regular expression error, got %s", pattern, text)
+		}
+	}
+}
+
+func TestSearchPlacesByNameNarrowsAtCap(t *testing.T) {
+	// Overpass answers the full radius with the cap of far elements in ID
+	// order, and the narrowed radius with the nearby match
+	var radii []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		query := r.Form.Get("data")
+		radius := query[strings.Index(query, "around:")+7 : strings.Index(query, ",51.5")]
+		radii = append(radii, radius)
+
+		elements := []osm.OverpassElement{{Type: "node", ID: 1, Lat: 51.5001, Lon: -0.1, Tags: map[string]string{"name": "Starbucks Near"}}}
+		if radius == "4000.0" {
+			elements = elements[:0]
+			for i := 0; i < maxNameSearchElements; i++ {
+				elements = append(elements, osm.OverpassElement{Type: "node", ID: 100 + i, Lat: 51.53, Lon: -0.1, Tags: map[string]string{"name": "Starbucks Far"}})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"elements": elements})
+	}))
+	defer srv.Close()
+	if err := osm.SetServiceURLs(osm.ServiceURLs{Overpass: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { osm.SetServiceURLs(osm.ServiceURLs{Overpass: osm.DefaultOverpassBaseURL}) })
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"query": "Starbucks", "latitude": 51.5, "longitude": -0.1, "radius": 4000.0, "include_translations": false}
+	result, err := HandleSearchPlacesByName(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Places    []NamedPlace `json:"places"`
+		Narrowed  bool         `json:"narrowed"`
+		Truncated bool         `json:"truncated"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(radii) != 2 || radii[1] != "1000.0" {
+		t.Errorf("expected the search repeated at 1000 m, got radii %v", radii)
+	}
+	if !out.Narrowed || out.Truncated || len(out.Places) != 1 || out.Places[0].Name != "Starbucks Near" {
+		t.Errorf("expected the nearby match from the narrowed search, got %+v", out)
+	}
+}
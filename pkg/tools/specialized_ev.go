@@ -15,6 +15,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/NERVsystems/osmmcp/pkg/core"
 	"github.com/NERVsystems/osmmcp/pkg/osm"
 )
 
@@ -29,6 +30,7 @@ type ChargingStation struct {
 	Power       string   `json:"power,omitempty"` // max power in kW
 	Access      string   `json:"access,omitempty"`
 	Fee         bool     `json:"fee,omitempty"`
+	Contact     *Contact `json:"contact,omitempty"`
 }
 
 // RouteChargingStation extends ChargingStation with route-specific information
@@ -40,8 +42,9 @@ type RouteChargingStation struct {
 
 // FindChargingStationsTool returns a tool definition for finding EV charging stations
 func FindChargingStationsTool() mcp.Tool {
+	limits := searchLimitsFor("find_charging_stations")
 	return mcp.NewTool("find_charging_stations",
-		mcp.WithDescription("Find electric vehicle charging stations near a specific location"),
+		mcp.WithDescription("Find electric vehicle charging stations near a specific location. "+orderingDescription),
 		mcp.WithNumber("latitude",
 			mcp.Required(),
 			mcp.Description("The latitude coordinate of the center point"),
@@ -51,12 +54,15 @@ func FindChargingStationsTool() mcp.Tool {
 			mcp.Description("The longitude coordinate of the center point"),
 		),
 		mcp.WithNumber("radius",
-			mcp.Description("Search radius in meters (max 10000)"),
-			mcp.DefaultNumber(5000),
+			mcp.Description(limits.radiusDescription()),
+			mcp.DefaultNumber(limits.DefaultRadius),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results to return"),
-			mcp.DefaultNumber(10),
+			mcp.Description(limits.limitDescription()),
+			mcp.DefaultNumber(float64(limits.DefaultLimit)),
+		),
+		mcp.WithString("cursor",
+			mcp.Description(cursorDescription),
 		),
 	)
 }
@@ -64,12 +70,12 @@ func FindChargingStationsTool() mcp.Tool {
 // HandleFindChargingStations implements finding charging stations
 func HandleFindChargingStations(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logger := slog.Default().With("tool", "find_charging_stations")
+	limits := searchLimitsFor("find_charging_stations")
 
 	// Parse and validate coordinates
 	latStr := mcp.ParseString(req, "latitude", "")
 	lonStr := mcp.ParseString(req, "longitude", "")
 	radiusStr := mcp.ParseString(req, "radius", "")
-	limitStr := mcp.ParseString(req, "limit", "")
 
 	if latStr == "" || lonStr == "" {
 		logger.Error("missing required coordinates", "latitude", latStr, "longitude", lonStr)
@@ -106,7 +112,7 @@ func HandleFindChargingStations(ctx context.Context, req mcp.CallToolRequest) (*
 		), nil
 	}
 
-	if err := ValidateCoordinates(lat, lon); err != nil {
+	if err := core.ValidateCoords(lat, lon); err != nil {
 		logger.Error("coordinate validation failed", "error", err)
 		return NewGeocodeDetailedError(
 			"INVALID_COORDINATES",
@@ -116,7 +122,7 @@ func HandleFindChargingStations(ctx context.Context, req mcp.CallToolRequest) (*
 		), nil
 	}
 
-	var radius float64 = 1000 // Default radius
+	radius := limits.DefaultRadius
 	if radiusStr != "" {
 		radius, err = strconv.ParseFloat(radiusStr, 64)
 		if err != nil {
@@ -131,37 +137,24 @@ func HandleFindChargingStations(ctx context.Context, req mcp.CallToolRequest) (*
 		}
 	}
 
-	if err := ValidateRadius(radius, 5000); err != nil {
+	if err := ValidateRadius(radius, limits.MaxRadius); err != nil {
 		logger.Error("radius validation failed", "radius", radius, "error", err)
 		return NewGeocodeDetailedError(
 			"INVALID_RADIUS",
 			err.Error(),
 			"",
-			"Radius must be positive and less than 5000 meters",
+			limits.radiusGuidance(),
 		), nil
 	}
 
-	var limit int = 10 // Default limit
-	if limitStr != "" {
-		limitFloat, err := strconv.ParseFloat(limitStr, 64)
-		if err != nil {
-			logger.Error("invalid limit", "input", limitStr, "error", err)
-			return NewGeocodeDetailedError(
-				"INVALID_LIMIT",
-				fmt.Sprintf("Invalid limit value: %s", limitStr),
-				"",
-				"Limit must be a valid positive number",
-				"Example: 10 (numeric, no quotes)",
-			), nil
-		}
-		limit = int(limitFloat)
+	limit, errResult := parseListLimit(req, limits, logger)
+	if errResult != nil {
+		return errResult, nil
 	}
 
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 50 {
-		limit = 50
+	opts, errResult := parseListOptions(req, limit)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Build Overpass query for charging stations
@@ -171,57 +164,16 @@ func HandleFindChargingStations(ctx context.Context, req mcp.CallToolRequest) (*
 	queryBuilder.WriteString(fmt.Sprintf("way(around:%f,%f,%f)[amenity=charging_station];", radius, lat, lon))
 	queryBuilder.WriteString(");out body;")
 
-	// Build request
-	reqURL, err := url.Parse(osm.OverpassBaseURL)
+	// Execute the query
+	elements, err := executeOverpassQuery(ctx, queryBuilder.String())
 	if err != nil {
-		logger.Error("failed to parse URL", "error", err)
-		return ErrorResponse("Internal server error"), nil
-	}
-
-	// Make HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), strings.NewReader("data="+url.QueryEscape(queryBuilder.String())))
-	if err != nil {
-		logger.Error("failed to create request", "error", err)
-		return ErrorResponse("Failed to create request"), nil
-	}
-
-	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	httpReq.Header.Set("User-Agent", osm.UserAgent)
-
-	// Execute request
-	client := osm.GetClient(ctx)
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		logger.Error("failed to execute request", "error", err)
+		logger.Error("failed to execute query", "error", err)
 		return ErrorResponse("Failed to communicate with OSM service"), nil
 	}
-	defer resp.Body.Close()
-
-	// Process response
-	if resp.StatusCode != http.StatusOK {
-		logger.Error("OSM service returned error", "status", resp.StatusCode)
-		return ErrorResponse(fmt.Sprintf("OSM service error: %d", resp.StatusCode)), nil
-	}
-
-	// Parse response
-	var overpassResp struct {
-		Elements []struct {
-			ID   int               `json:"id"`
-			Type string            `json:"type"`
-			Lat  float64           `json:"lat,omitempty"`
-			Lon  float64           `json:"lon,omitempty"`
-			Tags map[string]string `json:"tags"`
-		} `json:"elements"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
-		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse charging stations data"), nil
-	}
 
 	// Convert to ChargingStation objects and calculate distances
 	stations := make([]ChargingStation, 0)
-	for _, element := range overpassResp.Elements {
+	for _, element := range elements {
 		// Skip elements without proper coordinates
 		if element.Lat == 0 && element.Lon == 0 {
 			continue
@@ -258,26 +210,22 @@ func HandleFindChargingStations(ctx context.Context, req mcp.CallToolRequest) (*
 			Power:       element.Tags["maxpower"],
 			Access:      element.Tags["access"],
 			Fee:         element.Tags["fee"] == "yes",
+			Contact:     parseContact(element.Tags),
 		}
 
 		stations = append(stations, station)
 	}
 
-	// Sort stations by distance (closest first)
-	sort.Slice(stations, func(i, j int) bool {
-		return stations[i].Distance < stations[j].Distance
-	})
-
-	// Limit results
-	if len(stations) > limit {
-		stations = stations[:limit]
-	}
+	// Filter, order and take the requested page
+	stations, _, nextCursor := processList(stations, stationSortKey, opts)
 
 	// Create output
 	output := struct {
 		ChargingStations []ChargingStation `json:"charging_stations"`
+		NextCursor       string            `json:"next_cursor,omitempty"`
 	}{
 		ChargingStations: stations,
+		NextCursor:       nextCursor,
 	}
 
 	// Return result
@@ -290,6 +238,11 @@ func HandleFindChargingStations(ctx context.Context, req mcp.CallToolRequest) (*
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// stationSortKey returns the distance and ID stations are ordered by
+func stationSortKey(s ChargingStation) (float64, string) {
+	return s.Distance, s.ID
+}
+
 // getStationName returns a name for the charging station
 func getStationName(tags map[string]string) string {
 	// Use name tag if available
@@ -308,6 +261,7 @@ func getStationName(tags map[string]string) string {
 
 // FindRouteChargingStationsTool returns a tool definition for finding charging stations along a route
 func FindRouteChargingStationsTool() mcp.Tool {
+	limits := searchLimitsFor("find_route_charging_stations")
 	return mcp.NewTool("find_route_charging_stations",
 		mcp.WithDescription("Find electric vehicle charging stations along a route between two locations"),
 		mcp.WithNumber("start_latitude",
@@ -331,8 +285,8 @@ func FindRouteChargingStationsTool() mcp.Tool {
 			mcp.DefaultNumber(2000),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results to return"),
-			mcp.DefaultNumber(10),
+			mcp.Description(limits.limitDescription()),
+			mcp.DefaultNumber(float64(limits.DefaultLimit)),
 		),
 	)
 }
@@ -340,6 +294,7 @@ func FindRouteChargingStationsTool() mcp.Tool {
 // HandleFindRouteChargingStations implements finding charging stations along a route
 func HandleFindRouteChargingStations(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logger := slog.Default().With("tool", "find_route_charging_stations")
+	limits := searchLimitsFor("find_route_charging_stations")
 
 	// Parse input parameters
 	startLat := mcp.ParseFloat64(req, "start_latitude", 0)
@@ -347,24 +302,19 @@ func HandleFindRouteChargingStations(ctx context.Context, req mcp.CallToolReques
 	endLat := mcp.ParseFloat64(req, "end_latitude", 0)
 	endLon := mcp.ParseFloat64(req, "end_longitude", 0)
 	bufferDistance := mcp.ParseFloat64(req, "buffer_distance", 2000)
-	limit := int(mcp.ParseFloat64(req, "limit", 10))
+	limit := int(mcp.ParseFloat64(req, "limit", float64(limits.DefaultLimit)))
 
 	// Basic validation
-	if startLat < -90 || startLat > 90 || endLat < -90 || endLat > 90 {
-		return ErrorResponse("Latitude must be between -90 and 90"), nil
+	if err := core.ValidateCoords(startLat, startLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
-	if startLon < -180 || startLon > 180 || endLon < -180 || endLon > 180 {
-		return ErrorResponse("Longitude must be between -180 and 180"), nil
+	if err := core.ValidateCoords(endLat, endLon); err != nil {
+		return ErrorResponse(err.Error()), nil
 	}
 	if bufferDistance <= 0 || bufferDistance > 5000 {
 		return ErrorResponse("Buffer distance must be between 1 and 5000 meters"), nil
 	}
-	if limit <= 0 {
-		limit = 10 // Default limit
-	}
-	if limit > 50 {
-		limit = 50 // Max limit
-	}
+	limit = limits.clampLimit(limit)
 
 	// First, get the route between the two points using OSRM
 	osrmURL := fmt.Sprintf("%s/route/v1/driving/%f,%f;%f,%f",
@@ -416,9 +366,9 @@ func HandleFindRouteChargingStations(ctx context.Context, req mcp.CallToolReques
 		} `json:"routes"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&osrmResp); err != nil {
+	if err := core.DecodeUpstream("OSRM", resp.Body, &osrmResp, "code"); err != nil {
 		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse routing data"), nil
+		return err.ToMCPResult(), nil
 	}
 
 	// Check if we have any routes
@@ -499,9 +449,9 @@ func HandleFindRouteChargingStations(ctx context.Context, req mcp.CallToolReques
 		} `json:"elements"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
+	if err := core.DecodeUpstream("Overpass", resp.Body, &overpassResp, "elements"); err != nil {
 		logger.Error("failed to decode response", "error", err)
-		return ErrorResponse("Failed to parse charging stations data"), nil
+		return err.ToMCPResult(), nil
 	}
 
 	// Process charging stations
@@ -571,6 +521,7 @@ func HandleFindRouteChargingStations(ctx context.Context, req mcp.CallToolReques
 				Power:       element.Tags["maxpower"],
 				Access:      element.Tags["access"],
 				Fee:         element.Tags["fee"] == "yes",
+				Contact:     parseContact(element.Tags),
 			},
 			DistanceFromStart: distFromStart,
 			PercentAlongRoute: (distFromStart / totalRouteDistance) * 100,
@@ -580,8 +531,9 @@ func HandleFindRouteChargingStations(ctx context.Context, req mcp.CallToolReques
 	}
 
 	// Sort stations by distance along route
-	sort.Slice(routeStations, func(i, j int) bool {
-		return routeStations[i].DistanceFromStart < routeStations[j].DistanceFromStart
+	sort.SliceStable(routeStations, func(i, j int) bool {
+		return lessByDistanceThenID(routeStations[i].DistanceFromStart, routeStations[i].ID,
+			routeStations[j].DistanceFromStart, routeStations[j].ID)
 	})
 
 	// Limit results
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// maxSpoolReadBytes caps the part of a spooled response one resource read
+// returns, so serving it never holds the whole response in memory. Larger
+// responses are read in parts, each naming the URI of the next.
+const maxSpoolReadBytes = 8 << 20
+
+// SpooledOutput is returned instead of a result too large to send inline
+type SpooledOutput struct {
+	Resource cache.SpooledResource `json:"resource"`
+	Message  string                `json:"message"`
+}
+
+// RegisterSpool serves spooled responses as spool://{id} resources
+func (r *Registry) RegisterSpool(mcpServer *server.MCPServer) {
+	template := mcp.NewResourceTemplate(cache.SpoolResourceScheme+"://{id}{?offset}", "Spooled response",
+		mcp.WithTemplateDescription(fmt.Sprintf("A large tool result stored on disk, linked from the tool result; readable for an hour. Results over %d MB are returned in parts: while _meta.next is set, read that URI for the next part", maxSpoolReadBytes>>20)),
+	)
+	mcpServer.AddResourceTemplate(template, func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readSpooledResource(req.Params.URI)
+	})
+}
+
+// readSpooledResource returns at most maxSpoolReadBytes of a spooled
+// response from the offset in its URI, as text for JSON and text and
+// base64 otherwise. Unless the part reaches the end, its _meta.next is the
+// URI of the next part.
+func readSpooledResource(uri string) ([]mcp.ResourceContents, error) {
+	resourceURI, offset, err := parseSpoolURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	spool, err := cache.DefaultSpool()
+	if err != nil {
+		return nil, err
+	}
+	resource, body, err := spool.Open(resourceURI)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if offset > resource.Size {
+		return nil, fmt.Errorf("offset %d is past the end of the %d byte response", offset, resource.Size)
+	}
+	if seeker, ok := body.(io.Seeker); ok {
+		_, err = seeker.Seek(offset, io.SeekStart)
+	} else {
+		_, err = io.CopyN(io.Discard, body, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxSpoolReadBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	isText := strings.HasPrefix(resource.MIMEType, "text/") || strings.Contains(resource.MIMEType, "json")
+	end := offset + int64(len(data))
+	if isText && end < resource.Size {
+		// End the part on a whole character, leaving the rest for the next
+		data = data[:len(data)-incompleteRuneSuffix(data)]
+		end = offset + int64(len(data))
+	}
+	var meta *mcp.Meta
+	if end < resource.Size {
+		meta = &mcp.Meta{AdditionalFields: map[string]any{
+			"offset": offset,
+			"size":   resource.Size,
+			"next":   fmt.Sprintf("%s?offset=%d", resourceURI, end),
+		}}
+	}
+
+	if isText {
+		return []mcp.ResourceContents{mcp.TextResourceContents{Meta: meta, URI: uri, MIMEType: resource.MIMEType, Text: string(data)}}, nil
+	}
+	return []mcp.ResourceContents{mcp.BlobResourceContents{Meta: meta, URI: uri, MIMEType: resource.MIMEType, Blob: base64.StdEncoding.EncodeToString(data)}}, nil
+}
+
+// parseSpoolURI splits a spool URI into the resource URI and the offset of
+// the part to read
+func parseSpoolURI(uri string) (string, int64, error) {
+	resourceURI, query, _ := strings.Cut(uri, "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid spool URI %q: %w", uri, err)
+	}
+	if values.Get("offset") == "" {
+		return resourceURI, 0, nil
+	}
+	offset, err := strconv.ParseInt(values.Get("offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return "", 0, fmt.Errorf("invalid offset %q in spool URI", values.Get("offset"))
+	}
+	return resourceURI, offset, nil
+}
+
+// incompleteRuneSuffix returns the length of a UTF-8 sequence cut off at
+// the end of data
+func incompleteRuneSuffix(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if utf8.FullRune(data[i:]) {
+				return 0
+			}
+			return len(data) - i
+		}
+	}
+	return 0
+}
+
+// readUpstreamBody reads a response body, or streams it to the spool if it
+// is larger than the spool threshold. Without a spool the body is read
+// into memory.
+func readUpstreamBody(r io.Reader, name, mimeType string, logger *slog.Logger) ([]byte, *cache.SpooledResource, error) {
+	spool, err := cache.DefaultSpool()
+	if err != nil {
+		logger.Warn("response spool unavailable", "error", err)
+		data, err := io.ReadAll(r)
+		return data, nil, err
+	}
+	return spool.ReadOrSpool(r, name, mimeType)
+}
+
+// spoolBytes stores a result that is already in memory in the spool if it
+// is larger than the spool threshold, so it is not sent inline. It returns
+// nil for smaller results.
+func spoolBytes(data []byte, name, mimeType string, logger *slog.Logger) *cache.SpooledResource {
+	spool, err := cache.DefaultSpool()
+	if err != nil || int64(len(data)) <= spool.Threshold() {
+		return nil
+	}
+	resource, err := spool.Store(bytes.NewReader(data), name, mimeType)
+	if err != nil {
+		logger.Warn("failed to spool large result", "error", err)
+		return nil
+	}
+	return resource
+}
+
+// spooledResult links to a spooled response, with a note on what it holds
+func spooledResult(resource *cache.SpooledResource, message string, logger *slog.Logger) (*mcp.CallToolResult, error) {
+	output := SpooledOutput{
+		Resource: *resource,
+		Message:  fmt.Sprintf("%s. The result is %d bytes, too large to return inline; read %s to fetch it", message, resource.Size, resource.URI),
+	}
+	if resource.Size > maxSpoolReadBytes {
+		output.Message += fmt.Sprintf(" in parts of up to %d MB, following each part's _meta.next", maxSpoolReadBytes>>20)
+	}
+	outputJSON, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(string(outputJSON)),
+			mcp.NewResourceLink(resource.URI, resource.Name, output.Message, resource.MIMEType),
+		},
+	}, nil
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+)
+
+func TestReadSpooledResourceInParts(t *testing.T) {
+	if err := cache.ConfigureSpool(t.TempDir(), 1, 64<<20); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cache.ConfigureSpool(t.TempDir(), cache.DefaultSpoolThreshold, cache.DefaultSpoolMaxBytes)
+	})
+	spool, err := cache.DefaultSpool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A leading ASCII byte makes the part boundary fall inside a character
+	text := "[" + strings.Repeat("é", maxSpoolReadBytes/2+100)
+	resource, err := spool.Store(strings.NewReader(text), "large", "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var read strings.Builder
+	uri, parts := resource.URI, 0
+	for uri != "" {
+		contents, err := readSpooledResource(uri)
+		if err != nil {
+			t.Fatalf("read %s: %v", uri, err)
+		}
+		part := contents[0].(mcp.TextResourceContents)
+		if len(part.Text) > maxSpoolReadBytes || !utf8.ValidString(part.Text) {
+			t.Fatalf("part %d is %d bytes, valid UTF-8 %v", parts, len(part.Text), utf8.ValidString(part.Text))
+		}
+		read.WriteString(part.Text)
+		parts++
+
+		uri = ""
+		if part.Meta != nil {
+			uri, _ = part.Meta.AdditionalFields["next"].(string)
+		}
+	}
+	if parts != 2 || read.String() != text {
+		t.Errorf("expected the response in 2 parts, got %d parts of %d bytes", parts, read.Len())
+	}
+
+	// Binary responses are read in parts too
+	blob := make([]byte, maxSpoolReadBytes+10)
+	resource, err = spool.Store(strings.NewReader(string(blob)), "tile", "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := readSpooledResource(fmt.Sprintf("%s?offset=%d", resource.URI, maxSpoolReadBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	part := contents[0].(mcp.BlobResourceContents)
+	if data, _ := base64.StdEncoding.DecodeString(part.Blob); len(data) != 10 || part.Meta != nil {
+		t.Errorf("expected the last 10 bytes without a next part, got %d bytes, meta %v", len(data), part.Meta)
+	}
+
+	for _, uri := range []string{resource.URI + "?offset=-1", resource.URI + "?offset=x", resource.URI + "?offset=99999999"} {
+		if _, err := readSpooledResource(uri); err == nil {
+			t.Errorf("expected an error reading %s", uri)
+		}
+	}
+}
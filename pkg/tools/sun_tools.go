@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// SunPositionInfo is the position of the sun in the sky at one moment
+type SunPositionInfo struct {
+	Time      string  `json:"time"`
+	Azimuth   float64 `json:"azimuth"`   // degrees clockwise from north
+	Elevation float64 `json:"elevation"` // degrees above the horizon
+	Daylight  bool    `json:"daylight"`  // sun above the horizon
+}
+
+// SunTimesOutput defines the output for sun_times
+type SunTimesOutput struct {
+	Location    Location        `json:"location"`
+	Date        string          `json:"date"`
+	Timezone    string          `json:"timezone"`
+	SolarNoon   string          `json:"solar_noon"`
+	Sunrise     string          `json:"sunrise,omitempty"`
+	Sunset      string          `json:"sunset,omitempty"`
+	CivilDawn   string          `json:"civil_dawn,omitempty"`
+	CivilDusk   string          `json:"civil_dusk,omitempty"`
+	DayLength   float64         `json:"day_length_minutes"`
+	PolarDay    bool            `json:"polar_day,omitempty"`
+	PolarNight  bool            `json:"polar_night,omitempty"`
+	SunPosition SunPositionInfo `json:"sun_position"`
+}
+
+// SunTimesTool returns a tool definition for sunrise, sunset and sun position
+func SunTimesTool() mcp.Tool {
+	return mcp.NewTool("sun_times",
+		mcp.WithDescription("Calculate sunrise, sunset, solar noon, civil twilight and day length for a coordinate and date, plus the sun's azimuth and elevation at a given time. Computed locally, no external service"),
+		mcp.WithNumber("latitude",
+			mcp.Required(),
+			mcp.Description("Latitude of the location"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Required(),
+			mcp.Description("Longitude of the location"),
+		),
+		mcp.WithString("date",
+			mcp.Description("Date as YYYY-MM-DD in the given time zone (defaults to today)"),
+		),
+		mcp.WithString("time",
+			mcp.Description("RFC 3339 time for the sun position, e.g. 2024-06-21T15:00:00Z (defaults to now)"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone for the date and returned times, e.g. Europe/London (defaults to UTC)"),
+		),
+		mcp.WithString("as_of",
+			mcp.Description(asOfDescription+"; the default for date and time"),
+		),
+	)
+}
+
+// HandleSunTimes computes daylight times and the sun's position
+func HandleSunTimes(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "sun_times")
+
+	lat := mcp.ParseFloat64(req, "latitude", 0)
+	lon := mcp.ParseFloat64(req, "longitude", 0)
+	if err := core.ValidateCoords(lat, lon); err != nil {
+		return core.NewError(core.ErrInvalidInput, err.Error()).ToMCPResult(), nil
+	}
+
+	tz := time.UTC
+	if name := mcp.ParseString(req, "timezone", ""); name != "" {
+		loc, err := loadTimezone(name)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance("Use an IANA time zone name such as America/New_York").
+				ToMCPResult(), nil
+		}
+		tz = loc
+	}
+
+	asOf, err := parseAsOf(req)
+	if err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+	at := asOf.In(tz)
+	if value := mcp.ParseString(req, "time", ""); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, "Invalid time: "+value).
+				WithGuidance("Use RFC 3339 format such as 2024-06-21T15:00:00Z").
+				ToMCPResult(), nil
+		}
+		at = parsed.In(tz)
+	}
+
+	date := at
+	if value := mcp.ParseString(req, "date", ""); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, tz)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, "Invalid date: "+value).
+				WithGuidance("Use YYYY-MM-DD format such as 2024-06-21").
+				ToMCPResult(), nil
+		}
+		date = parsed
+	}
+
+	output := sunTimesOutput(lat, lon, date, at, tz)
+
+	logger.Info("calculated sun times", "lat", lat, "lon", lon, "date", output.Date)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// sunTimesOutput computes the sun_times result for a date, with the sun's
+// position at time at, formatting times in tz
+func sunTimesOutput(lat, lon float64, date, at time.Time, tz *time.Location) SunTimesOutput {
+	events := geo.SunTimes(lat, lon, date)
+	format := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(tz).Format(time.RFC3339)
+	}
+
+	azimuth, elevation := geo.SunPosition(lat, lon, at)
+
+	return SunTimesOutput{
+		Location:   Location{Latitude: lat, Longitude: lon},
+		Date:       date.Format("2006-01-02"),
+		Timezone:   tz.String(),
+		SolarNoon:  format(&events.SolarNoon),
+		Sunrise:    format(events.Sunrise),
+		Sunset:     format(events.Sunset),
+		CivilDawn:  format(events.CivilDawn),
+		CivilDusk:  format(events.CivilDusk),
+		DayLength:  math.Round(events.DayLength().Minutes()),
+		PolarDay:   events.PolarDay,
+		PolarNight: events.PolarNight,
+		SunPosition: SunPositionInfo{
+			Time:      at.Format(time.RFC3339),
+			Azimuth:   math.Round(azimuth*10) / 10,
+			Elevation: math.Round(elevation*10) / 10,
+			Daylight:  elevation > 0,
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+func TestHandleSunTimes(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "sun_times",
+			Arguments: map[string]any{
+				"latitude":  51.5074,
+				"longitude": -0.1278,
+				"date":      "2024-06-21",
+				"time":      "2024-06-21T12:02:00Z",
+			},
+		},
+	}
+	result, err := HandleSunTimes(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("sun_times failed: %v %v", err, result.Content)
+	}
+
+	var output SunTimesOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Timezone != "UTC" || output.Date != "2024-06-21" {
+		t.Errorf("unexpected date or timezone: %+v", output)
+	}
+	if !strings.HasPrefix(output.Sunrise, "2024-06-21T03:4") || !strings.HasPrefix(output.Sunset, "2024-06-21T20:2") {
+		t.Errorf("unexpected sunrise %s or sunset %s", output.Sunrise, output.Sunset)
+	}
+	if output.DayLength < 990 || output.DayLength > 1005 {
+		t.Errorf("unexpected day length %.0f minutes", output.DayLength)
+	}
+	if !output.SunPosition.Daylight || output.SunPosition.Elevation < 61 || output.SunPosition.Azimuth < 175 || output.SunPosition.Azimuth > 185 {
+		t.Errorf("unexpected sun position %+v", output.SunPosition)
+	}
+}
+
+func TestHandleSunTimesAsOf(t *testing.T) {
+	defer core.ResetClock()
+	core.SetSimulatedClock(time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC), true)
+
+	check := func(args map[string]any, wantDate string) {
+		t.Helper()
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "sun_times", Arguments: args}}
+		result, err := HandleSunTimes(context.Background(), req)
+		if err != nil || result.IsError {
+			t.Fatalf("sun_times failed: %v %v", err, result.Content)
+		}
+		var output SunTimesOutput
+		if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+			t.Fatal(err)
+		}
+		if output.Date != wantDate {
+			t.Errorf("expected date %s, got %s", wantDate, output.Date)
+		}
+	}
+
+	// The simulated clock is the default, and as_of overrides it
+	check(map[string]any{"latitude": 51.5074, "longitude": -0.1278}, "2024-12-21")
+	check(map[string]any{"latitude": 51.5074, "longitude": -0.1278, "as_of": "2025-03-20T09:00:00Z"}, "2025-03-20")
+}
+
+func TestHandleSunTimesValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"Invalid latitude", map[string]any{"latitude": 91.0, "longitude": 0.0}},
+		{"Invalid date", map[string]any{"latitude": 0.0, "longitude": 0.0, "date": "21/06/2024"}},
+		{"Invalid time", map[string]any{"latitude": 0.0, "longitude": 0.0, "time": "noon"}},
+		{"Invalid timezone", map[string]any{"latitude": 0.0, "longitude": 0.0, "timezone": "Mars/Olympus"}},
+		{"Invalid as_of", map[string]any{"latitude": 0.0, "longitude": 0.0, "as_of": "yesterday"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "sun_times", Arguments: tt.args}}
+			result, err := HandleSunTimes(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected an error result")
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// tabularTargets maps the tools that accept an output_format parameter to
+// the key holding the list rendered as rows
+var tabularTargets = map[string]string{
+	// POI tools
+	"find_nearby_places":         "places",
+	"search_places_by_name":      "places",
+	"explore_area":               "top_places",
+	"find_parking_facilities":    "facilities",
+	"parking_for_destination":    "facilities",
+	"find_charging_stations":     "charging_stations",
+	"find_schools_nearby":        "schools",
+	"search_isochrone_boundary":  "places",
+	"geocode_autocomplete":       "suggestions",
+	"reverse_geocode_candidates": "candidates",
+	"reverse_geocode_track":      "localities",
+	"reverse_geocode_batch":      "results",
+	"lookup_osm_object":          "objects",
+	"find_intersection":          "intersections",
+	"next_departures":            "departures",
+
+	// Routing tools
+	"route_narrative": "steps",
+	"optimize_stops":  "stops",
+	"route_matrix":    "pairs",
+
+	// OSM element tools
+	"osm_query_bbox":   "elements",
+	"hydrate_elements": "elements",
+	"filter_tags":      "elements",
+	"sort_by_distance": "elements",
+
+	// Geo and tile tools
+	"aggregate_points": "cells",
+	"point_in_polygon": "results",
+	"point_in_bbox":    "results",
+	"tiles_for_bbox":   "tiles",
+}
+
+// tabularRowBuilders derive the rows of targets that are not lists in the
+// result
+var tabularRowBuilders = map[string]func(obj *orderedObject) []any{
+	"pairs": matrixPairRows,
+}
+
+// Output formats accepted by the output_format parameter
+const (
+	outputFormatJSON = "json"
+	outputFormatCSV  = "csv"
+	outputFormatTSV  = "tsv"
+)
+
+// withTabularOutput adds the output_format parameter to the tools listed in
+// tabularTargets and wraps their handlers to apply it. It runs after field
+// selection and transforms, so fields chooses the columns and a transform
+// may produce the rows.
+func withTabularOutput(defs []ToolDefinition) []ToolDefinition {
+	for i, def := range defs {
+		target, ok := tabularTargets[def.Name]
+		if !ok {
+			continue
+		}
+
+		mcp.WithString("output_format",
+			mcp.Description(fmt.Sprintf("Result format: json (default), or csv/tsv for a compact table with one row per entry of %s. Nested objects become dotted columns such as location.latitude; other top-level fields are omitted", target)),
+			mcp.Enum(outputFormatJSON, outputFormatCSV, outputFormatTSV),
+		)(&defs[i].Tool)
+		defs[i].Handler = tabularHandler(target, def.Handler)
+	}
+	return defs
+}
+
+// tabularHandler wraps a handler to render its JSON result as CSV or TSV
+func tabularHandler(target string, handler func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format := strings.ToLower(mcp.ParseString(req, "output_format", outputFormatJSON))
+		if format != outputFormatJSON && format != outputFormatCSV && format != outputFormatTSV {
+			return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("unknown output_format %q", format)).
+				WithGuidance("Use json, csv or tsv").
+				ToMCPResult(), nil
+		}
+
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError || format == outputFormatJSON {
+			return result, err
+		}
+
+		tabular, err := tabulateResult(result, target, format)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance("Use output_format json for results that are not lists").
+				ToMCPResult(), nil
+		}
+		return tabular, nil
+	}
+}
+
+// tabulateResult renders the rows of a JSON result as delimited text. The
+// rows are the target list of an object result, or the result itself when
+// it is an array, e.g. after a transform.
+func tabulateResult(result *mcp.CallToolResult, target, format string) (*mcp.CallToolResult, error) {
+	if len(result.Content) == 0 {
+		return result, nil
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return nil, fmt.Errorf("%s output only applies to JSON results", format)
+	}
+
+	root, err := decodeOrdered([]byte(text.Text))
+	if err != nil {
+		return nil, fmt.Errorf("%s output only applies to JSON results", format)
+	}
+	if obj, ok := root.(*orderedObject); ok {
+		if build, ok := tabularRowBuilders[target]; ok {
+			root = build(obj)
+		} else {
+			root = obj.values[target]
+		}
+	}
+	rows, ok := root.([]any)
+	if !ok {
+		return nil, fmt.Errorf("result has no %s list to render as %s", target, format)
+	}
+
+	data, err := renderTable(rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	tabular := *result
+	tabular.Content = append([]mcp.Content{mcp.NewTextContent(data)}, result.Content[1:]...)
+	return &tabular, nil
+}
+
+// renderTable writes rows as CSV or TSV with a header of the union of their
+// flattened columns, in first-seen order
+func renderTable(rows []any, format string) (string, error) {
+	var columns []string
+	seen := make(map[string]bool)
+	flattened := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		cells := make(map[string]string)
+		for _, column := range flattenRow("", row, cells) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+		flattened[i] = cells
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if format == outputFormatTSV {
+		w.Comma = '\t'
+	}
+	if err := w.Write(columns); err != nil {
+		return "", err
+	}
+	record := make([]string, len(columns))
+	for _, cells := range flattened {
+		for i, column := range columns {
+			record[i] = cells[column]
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// matrixPairRows turns the durations and distances of a route matrix into
+// one row per origin and destination pair
+func matrixPairRows(obj *orderedObject) []any {
+	origins, _ := obj.values["origins"].([]any)
+	destinations, _ := obj.values["destinations"].([]any)
+	durations, _ := obj.values["durations"].([]any)
+	distances, _ := obj.values["distances"].([]any)
+
+	cell := func(matrix []any, i, j int) any {
+		if i >= len(matrix) {
+			return nil
+		}
+		row, _ := matrix[i].([]any)
+		if j >= len(row) {
+			return nil
+		}
+		return row[j]
+	}
+
+	rows := make([]any, 0, len(origins)*len(destinations))
+	for i, origin := range origins {
+		for j, destination := range destinations {
+			rows = append(rows, &orderedObject{
+				keys: []string{"origin_index", "destination_index", "origin", "destination", "duration", "distance"},
+				values: map[string]any{
+					"origin_index":      json.Number(strconv.Itoa(i)),
+					"destination_index": json.Number(strconv.Itoa(j)),
+					"origin":            origin,
+					"destination":       destination,
+					"duration":          cell(durations, i, j),
+					"distance":          cell(distances, i, j),
+				},
+			})
+		}
+	}
+	return rows
+}
+
+// flattenRow stores the cells of a value under dotted column names and
+// returns the columns in order. Arrays are kept as JSON text in one cell.
+func flattenRow(prefix string, value any, cells map[string]string) []string {
+	obj, ok := value.(*orderedObject)
+	if !ok {
+		column := prefix
+		if column == "" {
+			column = "value"
+		}
+		cells[column] = cellText(value)
+		return []string{column}
+	}
+
+	var columns []string
+	for _, key := range obj.keys {
+		column := key
+		if prefix != "" {
+			column = prefix + "." + key
+		}
+		columns = append(columns, flattenRow(column, obj.values[key], cells)...)
+	}
+	return columns
+}
+
+// cellText formats a scalar or array for a table cell
+func cellText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// orderedObject is a JSON object that remembers its key order, so columns
+// follow the order of the result's fields
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// MarshalJSON writes the object with its keys in order
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered decodes JSON with objects as *orderedObject and numbers as
+// json.Number
+func decodeOrdered(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeOrderedValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+func decodeOrderedValue(decoder *json.Decoder) (any, error) {
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := &orderedObject{values: make(map[string]any)}
+		for decoder.More() {
+			keyTok, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		_, err := decoder.Token() // closing }
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for decoder.More() {
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := decoder.Token() // closing ]
+		return list, err
+	}
+	return tok, nil
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithTabularOutput(t *testing.T) {
+	defs := NewRegistry(slog.Default()).GetToolDefinitions()
+	names := make(map[string]bool)
+	for _, def := range defs {
+		names[def.Name] = true
+		_, tabular := tabularTargets[def.Name]
+		_, hasParam := def.Tool.InputSchema.Properties["output_format"]
+		if tabular != hasParam {
+			t.Errorf("tool %s: output_format parameter %v, want %v", def.Name, hasParam, tabular)
+		}
+	}
+	for name := range tabularTargets {
+		if !names[name] {
+			t.Errorf("tabularTargets lists unknown tool %s", name)
+		}
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	root, err := decodeOrdered([]byte(`[
+		{"name":"Cafe, A","location":{"latitude":51.5,"longitude":-0.1},"distance":120,"tags":["a","b"]},
+		{"name":"B","distance":80.5,"open":true,"location":{"latitude":51.6,"longitude":-0.2}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := renderTable(root.([]any), outputFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name,location.latitude,location.longitude,distance,tags,open\n" +
+		"\"Cafe, A\",51.5,-0.1,120,\"[\"\"a\"\",\"\"b\"\"]\",\n" +
+		"B,51.6,-0.2,80.5,,true\n"
+	if got != want {
+		t.Errorf("renderTable() =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = renderTable([]any{"x", "y"}, outputFormatTSV)
+	if err != nil || got != "value\nx\ny\n" {
+		t.Errorf("unexpected scalar rows %q %v", got, err)
+	}
+}
+
+func TestTabularHandler(t *testing.T) {
+	handler := tabularHandler("places", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"count":2,"places":[{"id":"1","name":"A"},{"id":"2","name":"B"}]}`), nil
+	})
+	call := func(format string) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{"output_format": format}}}
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if got := call("tsv").Content[0].(mcp.TextContent).Text; got != "id\tname\n1\tA\n2\tB\n" {
+		t.Errorf("unexpected TSV %q", got)
+	}
+	if got := call("json").Content[0].(mcp.TextContent).Text; got[0] != '{' {
+		t.Errorf("expected JSON to be returned unchanged, got %s", got)
+	}
+	if !call("xml").IsError {
+		t.Error("expected an error result for an unknown format")
+	}
+
+	// A transformed result that is not a list cannot be rendered
+	handler = tabularHandler("places", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"count":2}`), nil
+	})
+	if !call("csv").IsError {
+		t.Error("expected an error result for a result without rows")
+	}
+}
+
+func TestTabularRouteMatrix(t *testing.T) {
+	result := mcp.NewToolResultText(`{"mode":"car","origins":[{"latitude":1,"longitude":2}],` +
+		`"destinations":[{"latitude":3,"longitude":4},{"latitude":5,"longitude":6}],` +
+		`"durations":[[60.5,null]],"distances":[[800,null]]}`)
+	tabular, err := tabulateResult(result, "pairs", outputFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "origin_index,destination_index,origin.latitude,origin.longitude,destination.latitude,destination.longitude,duration,distance\n" +
+		"0,0,1,2,3,4,60.5,800\n" +
+		"0,1,1,2,5,6,,\n"
+	if got := tabular.Content[0].(mcp.TextContent).Text; got != want {
+		t.Errorf("unexpected CSV %q", got)
+	}
+}
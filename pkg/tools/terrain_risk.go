@@ -0,0 +1,271 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	defaultWaterSearchRadius = 2000.0
+	maxWaterSearchRadius     = 5000.0
+)
+
+// Terrain risk levels
+const (
+	TerrainRiskLow           = "low"
+	TerrainRiskModerate      = "moderate"
+	TerrainRiskHigh          = "high"
+	TerrainRiskIndeterminate = "indeterminate"
+)
+
+// terrainRiskDisclaimer is returned with every screening result
+const terrainRiskDisclaimer = "Screening estimate only, not an authoritative flood risk assessment. " +
+	"It is derived from mapped OpenStreetMap waterways and a ~90 m resolution elevation model, and ignores " +
+	"flood defenses, drainage, rainfall and local terrain detail. Consult official flood maps before making decisions."
+
+// terrainRiskMethodology explains how the risk level is derived
+const terrainRiskMethodology = "High if a river, stream, canal or water body is within 250 m and the site is at most 3 m above it, " +
+	"or the coastline is within 1 km and the site is at most 5 m above sea level. Moderate if water is within 1 km " +
+	"and the site is at most 8 m above it, or the coastline is within 3 km and the site is at most 10 m above sea level. " +
+	"Otherwise low. Height above water compares the elevation model at the site and at the nearest point of the water feature."
+
+// WaterFeature is the nearest point of a mapped waterway, water body or coastline
+type WaterFeature struct {
+	ID        string   `json:"id"`             // OSM way, e.g. way/123
+	Name      string   `json:"name,omitempty"` // name tag, if mapped
+	Kind      string   `json:"kind"`           // river, stream, canal, lake, coastline...
+	Distance  float64  `json:"distance_m"`     // from the site to the nearest point
+	Location  Location `json:"location"`       // nearest point of the feature
+	Elevation *float64 `json:"elevation_m,omitempty"`
+}
+
+// TerrainRiskOutput defines the output for terrain_risk_screen
+type TerrainRiskOutput struct {
+	Location         Location      `json:"location"`
+	Elevation        *float64      `json:"elevation_m,omitempty"`          // site elevation above sea level
+	NearestWaterway  *WaterFeature `json:"nearest_waterway,omitempty"`     // river, stream, canal or water body
+	NearestCoastline *WaterFeature `json:"nearest_coastline,omitempty"`    // sea coast
+	HeightAboveWater *float64      `json:"height_above_water_m,omitempty"` // site minus nearest waterway elevation
+	RiskLevel        string        `json:"risk_level"`
+	Factors          []string      `json:"factors"`
+	SearchRadius     float64       `json:"search_radius"`
+	Methodology      string        `json:"methodology"`
+	Disclaimer       string        `json:"disclaimer"`
+}
+
+// TerrainRiskScreenTool returns a tool definition for flood and elevation screening
+func TerrainRiskScreenTool() mcp.Tool {
+	return mcp.NewTool("terrain_risk_screen",
+		mcp.WithDescription("Screen a site for flood exposure by combining terrain elevation with proximity to mapped rivers, streams, water bodies and coastline. Flags low-lying points near water. Results are a non-authoritative screening estimate, not a flood risk assessment"),
+		mcp.WithNumber("latitude",
+			mcp.Required(),
+			mcp.Description("Latitude of the site"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Required(),
+			mcp.Description("Longitude of the site"),
+		),
+		mcp.WithNumber("search_radius",
+			mcp.Description("Distance in meters to search for water features (max 5000)"),
+			mcp.DefaultNumber(defaultWaterSearchRadius),
+		),
+	)
+}
+
+// HandleTerrainRiskScreen screens a site for low-lying ground near water
+func HandleTerrainRiskScreen(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "terrain_risk_screen")
+
+	lat := mcp.ParseFloat64(req, "latitude", 0)
+	lon := mcp.ParseFloat64(req, "longitude", 0)
+	radius := mcp.ParseFloat64(req, "search_radius", defaultWaterSearchRadius)
+
+	if err := core.ValidateCoords(lat, lon); err != nil {
+		return core.NewError(core.ErrInvalidInput, err.Error()).ToMCPResult(), nil
+	}
+	if radius <= 0 || radius > maxWaterSearchRadius {
+		return core.NewError(core.ErrInvalidRadius, fmt.Sprintf("search_radius must be between 1 and %.0f meters", maxWaterSearchRadius)).ToMCPResult(), nil
+	}
+
+	elements, err := executeOverpassQuery(ctx, buildWaterFeatureQuery(lat, lon, radius))
+	if err != nil {
+		logger.Error("failed to query water features", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.NewError(core.ErrServiceUnavailable, "Failed to query water features").ToMCPResult(), nil
+	}
+
+	output := TerrainRiskOutput{
+		Location:     Location{Latitude: lat, Longitude: lon},
+		SearchRadius: radius,
+		Methodology:  terrainRiskMethodology,
+		Disclaimer:   terrainRiskDisclaimer,
+	}
+	output.NearestWaterway, output.NearestCoastline = nearestWaterFeatures(elements, lat, lon)
+
+	// Elevation at the site, and at the waterway to compare against
+	points := []geo.Location{{Latitude: lat, Longitude: lon}}
+	if output.NearestWaterway != nil {
+		points = append(points, geo.Location{
+			Latitude:  output.NearestWaterway.Location.Latitude,
+			Longitude: output.NearestWaterway.Location.Longitude,
+		})
+	}
+
+	options := core.DefaultElevationOptions()
+	options.Client = osm.GetClient(ctx)
+	elevations, err := core.GetElevations(ctx, points, options)
+	if err != nil {
+		logger.Warn("failed to get elevations", "error", err)
+	} else {
+		output.Elevation = roundedElevation(elevations[0])
+		if output.NearestWaterway != nil {
+			output.NearestWaterway.Elevation = roundedElevation(elevations[1])
+			height := math.Round((elevations[0]-elevations[1])*10) / 10
+			output.HeightAboveWater = &height
+		}
+	}
+
+	output.RiskLevel, output.Factors = screenTerrainRisk(&output)
+
+	logger.Info("screened terrain risk", "lat", lat, "lon", lon, "risk", output.RiskLevel)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// buildWaterFeatureQuery builds an Overpass query for waterways, water
+// bodies and coastline around a point
+func buildWaterFeatureQuery(lat, lon, radius float64) string {
+	var query strings.Builder
+	query.WriteString("[out:json][timeout:25];(")
+	fmt.Fprintf(&query, `way[waterway~"^(river|stream|canal|drain|ditch)$"](around:%.0f,%f,%f);`, radius, lat, lon)
+	fmt.Fprintf(&query, `way[natural=water](around:%.0f,%f,%f);`, radius, lat, lon)
+	fmt.Fprintf(&query, `way[natural=coastline](around:%.0f,%f,%f);`, radius, lat, lon)
+	query.WriteString(");out geom;")
+	return query.String()
+}
+
+// nearestWaterFeatures finds the nearest inland water feature and the
+// nearest coastline among ways queried with geometry
+func nearestWaterFeatures(elements []osm.OverpassElement, lat, lon float64) (*WaterFeature, *WaterFeature) {
+	var water, coast *WaterFeature
+	for _, element := range elements {
+		line := elementLine(element)
+		if len(line) < 2 {
+			continue
+		}
+
+		kind := element.Tags["waterway"]
+		switch {
+		case element.Tags["natural"] == "coastline":
+			kind = "coastline"
+		case element.Tags["natural"] == "water":
+			kind = element.Tags["water"]
+			if kind == "" {
+				kind = "water"
+			}
+		case kind == "":
+			continue
+		}
+
+		point, d := geo.NearestPointOnPolyline(lat, lon, line)
+		feature := &WaterFeature{
+			ID:       fmt.Sprintf("way/%d", element.ID),
+			Name:     element.Tags["name"],
+			Kind:     kind,
+			Distance: math.Round(d),
+			Location: Location{Latitude: point.Latitude, Longitude: point.Longitude},
+		}
+
+		// Points inside a mapped water body are on the water
+		if element.Tags["natural"] == "water" && geo.PointInPolygon(lat, lon, line) {
+			feature.Distance = 0
+		}
+
+		if kind == "coastline" {
+			if coast == nil || feature.Distance < coast.Distance {
+				coast = feature
+			}
+		} else if water == nil || feature.Distance < water.Distance {
+			water = feature
+		}
+	}
+	return water, coast
+}
+
+// screenTerrainRisk classifies flood exposure and lists the contributing factors
+func screenTerrainRisk(output *TerrainRiskOutput) (string, []string) {
+	factors := make([]string, 0)
+	if output.NearestWaterway == nil && output.NearestCoastline == nil {
+		factors = append(factors, fmt.Sprintf("No mapped waterways, water bodies or coastline within %.0f m", output.SearchRadius))
+		return TerrainRiskLow, factors
+	}
+	if output.Elevation == nil {
+		factors = append(factors, "Elevation data unavailable; only proximity to water could be assessed")
+		return TerrainRiskIndeterminate, factors
+	}
+
+	level := TerrainRiskLow
+	raise := func(to string) {
+		if to == TerrainRiskHigh || level == TerrainRiskLow {
+			level = to
+		}
+	}
+
+	if water := output.NearestWaterway; water != nil && output.HeightAboveWater != nil {
+		height := *output.HeightAboveWater
+		label := water.Kind
+		if water.Name != "" {
+			label = fmt.Sprintf("%s (%s)", water.Name, water.Kind)
+		}
+		switch {
+		case water.Distance <= 250 && height <= 3:
+			raise(TerrainRiskHigh)
+			factors = append(factors, fmt.Sprintf("Site is %.0f m from %s and only %.1f m above it", water.Distance, label, height))
+		case water.Distance <= 1000 && height <= 8:
+			raise(TerrainRiskModerate)
+			factors = append(factors, fmt.Sprintf("Site is %.0f m from %s and %.1f m above it", water.Distance, label, height))
+		default:
+			factors = append(factors, fmt.Sprintf("Nearest water is %s, %.0f m away; site is %.1f m above it", label, water.Distance, height))
+		}
+	}
+
+	if coast := output.NearestCoastline; coast != nil {
+		elevation := *output.Elevation
+		switch {
+		case coast.Distance <= 1000 && elevation <= 5:
+			raise(TerrainRiskHigh)
+			factors = append(factors, fmt.Sprintf("Site is %.0f m from the coast at %.1f m above sea level", coast.Distance, elevation))
+		case coast.Distance <= 3000 && elevation <= 10:
+			raise(TerrainRiskModerate)
+			factors = append(factors, fmt.Sprintf("Site is %.0f m from the coast at %.1f m above sea level", coast.Distance, elevation))
+		default:
+			factors = append(factors, fmt.Sprintf("Coastline is %.0f m away; site is %.1f m above sea level", coast.Distance, elevation))
+		}
+	}
+
+	return level, factors
+}
+
+// roundedElevation rounds an elevation to a tenth of a meter
+func roundedElevation(e float64) *float64 {
+	rounded := math.Round(e*10) / 10
+	return &rounded
+}
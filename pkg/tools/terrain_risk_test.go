@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+func TestBuildWaterFeatureQuery(t *testing.T) {
+	query := buildWaterFeatureQuery(51.5, -0.1, 2000)
+	for _, want := range []string{"waterway", "natural=water", "natural=coastline", "out geom"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q: %s", want, query)
+		}
+	}
+}
+
+func TestNearestWaterFeatures(t *testing.T) {
+	river := wayWithGeometry(map[string]string{"waterway": "river", "name": "Test River"}, [2]float64{-0.01, 0.002}, [2]float64{0.01, 0.002})
+	river.ID = 1
+	stream := wayWithGeometry(map[string]string{"waterway": "stream"}, [2]float64{-0.01, 0.005}, [2]float64{0.01, 0.005})
+	stream.ID = 2
+	coast := wayWithGeometry(map[string]string{"natural": "coastline"}, [2]float64{0.01, -0.01}, [2]float64{0.01, 0.01})
+	coast.ID = 3
+	lake := wayWithGeometry(map[string]string{"natural": "water", "water": "lake"},
+		[2]float64{-0.001, -0.001}, [2]float64{-0.001, 0.001}, [2]float64{0.001, 0.001}, [2]float64{0.001, -0.001}, [2]float64{-0.001, -0.001})
+	lake.ID = 4
+	untagged := wayWithGeometry(map[string]string{"highway": "residential"}, [2]float64{0, 0}, [2]float64{0, 0.001})
+
+	water, shore := nearestWaterFeatures([]osm.OverpassElement{stream, river, coast, untagged}, 0, 0)
+	if water == nil || water.ID != "way/1" || water.Name != "Test River" || water.Kind != "river" {
+		t.Fatalf("unexpected nearest waterway %+v", water)
+	}
+	if water.Distance < 210 || water.Distance > 235 || water.Location.Longitude != 0.002 {
+		t.Errorf("unexpected waterway distance %.0f at %+v", water.Distance, water.Location)
+	}
+	if shore == nil || shore.Kind != "coastline" || shore.Distance < 1100 || shore.Distance > 1120 {
+		t.Errorf("unexpected coastline %+v", shore)
+	}
+
+	// A point inside a lake is on the water
+	water, _ = nearestWaterFeatures([]osm.OverpassElement{river, lake}, 0, 0)
+	if water == nil || water.Kind != "lake" || water.Distance != 0 {
+		t.Errorf("expected to be inside the lake, got %+v", water)
+	}
+}
+
+func TestScreenTerrainRisk(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	river := func(distance float64) *WaterFeature {
+		return &WaterFeature{Kind: "river", Distance: distance}
+	}
+	coast := func(distance float64) *WaterFeature {
+		return &WaterFeature{Kind: "coastline", Distance: distance}
+	}
+
+	tests := []struct {
+		name   string
+		output TerrainRiskOutput
+		want   string
+	}{
+		{"no water", TerrainRiskOutput{Elevation: f(3)}, TerrainRiskLow},
+		{"no elevation", TerrainRiskOutput{NearestWaterway: river(100)}, TerrainRiskIndeterminate},
+		{"low-lying by river", TerrainRiskOutput{Elevation: f(12), NearestWaterway: river(100), HeightAboveWater: f(1.5)}, TerrainRiskHigh},
+		{"near river", TerrainRiskOutput{Elevation: f(12), NearestWaterway: river(600), HeightAboveWater: f(5)}, TerrainRiskModerate},
+		{"high above river", TerrainRiskOutput{Elevation: f(40), NearestWaterway: river(100), HeightAboveWater: f(30)}, TerrainRiskLow},
+		{"low coast", TerrainRiskOutput{Elevation: f(2), NearestCoastline: coast(500)}, TerrainRiskHigh},
+		{"coast outranks river", TerrainRiskOutput{Elevation: f(2), NearestWaterway: river(600), HeightAboveWater: f(5), NearestCoastline: coast(500)}, TerrainRiskHigh},
+		{"river outranks coast", TerrainRiskOutput{Elevation: f(9), NearestWaterway: river(50), HeightAboveWater: f(1), NearestCoastline: coast(2500)}, TerrainRiskHigh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, factors := screenTerrainRisk(&tt.output)
+			if level != tt.want {
+				t.Errorf("risk = %s, want %s (factors %v)", level, tt.want, factors)
+			}
+			if len(factors) == 0 {
+				t.Error("expected at least one factor")
+			}
+		})
+	}
+}
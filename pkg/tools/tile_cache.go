@@ -19,7 +19,7 @@ func GetTileCacheTool() mcp.Tool {
 		mcp.WithDescription("Manage and access cached map tiles"),
 		mcp.WithString("action",
 			mcp.Required(),
-			mcp.Description("Action to perform: 'list', 'get', 'stats'"),
+			mcp.Description("Action to perform: 'list', 'get' (returns the tile as image content), 'stats'"),
 		),
 		mcp.WithNumber("x",
 			mcp.Description("Tile X coordinate (required for 'get' action)"),
@@ -148,15 +148,12 @@ func handleGetCachedTile(ctx context.Context, req mcp.CallToolRequest, tileManag
 				Text: fmt.Sprintf("Metadata for %s:\n%s", c.URI, c.Text),
 			})
 		case mcp.BlobResourceContents:
-			// For images, we provide the base64 data and info
-			preview := c.Blob
-			if len(c.Blob) > 100 {
-				preview = c.Blob[:100] + "..."
+			// Return images as image content so clients can display them
+			mimeType := c.MIMEType
+			if mimeType == "" {
+				mimeType = "image/png"
 			}
-			contents = append(contents, mcp.TextContent{
-				Type: "text",
-				Text: fmt.Sprintf("Tile image data (base64): %d bytes\nData: %s", len(c.Blob), preview),
-			})
+			contents = append(contents, mcp.NewImageContent(c.Blob, mimeType))
 		}
 	}
 
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+const (
+	// maxTileZoom is the highest zoom level accepted for tile planning
+	maxTileZoom = 20
+
+	// defaultMaxTilesListed caps how many tiles are listed individually
+	defaultMaxTilesListed = 256
+
+	// maxTilesListed is the upper bound for max_tiles
+	maxTilesListed = 4096
+
+	// estimatedTileBytes is the average size of a rendered OSM PNG tile
+	estimatedTileBytes = 20 * 1024
+)
+
+// TileRef identifies a single map tile
+type TileRef struct {
+	X    int `json:"x"`
+	Y    int `json:"y"`
+	Zoom int `json:"z"`
+}
+
+// TilesForBBoxInput defines the input parameters for tiles_for_bbox
+type TilesForBBoxInput struct {
+	BBox     geo.BoundingBox `json:"bbox"`
+	Zoom     int             `json:"zoom"`
+	MaxTiles int             `json:"max_tiles,omitempty"`
+}
+
+// TilesForBBoxOutput defines the output for tiles_for_bbox
+type TilesForBBoxOutput struct {
+	Zoom           int       `json:"zoom"`
+	MinX           int       `json:"min_x"`
+	MaxX           int       `json:"max_x"`
+	MinY           int       `json:"min_y"`
+	MaxY           int       `json:"max_y"`
+	Count          int       `json:"count"`
+	EstimatedBytes int64     `json:"estimated_bytes"`
+	Tiles          []TileRef `json:"tiles"`
+	Truncated      bool      `json:"truncated,omitempty"` // true when count exceeds max_tiles
+}
+
+// TilesForBBoxTool returns a tool definition for listing the tiles covering a bounding box
+func TilesForBBoxTool() mcp.Tool {
+	return mcp.NewTool("tiles_for_bbox",
+		mcp.WithDescription("List the map tiles (x/y/z) covering a bounding box at a zoom level, with the tile count and estimated download size. Useful for planning before prefetching tiles or composing area images"),
+		mcp.WithObject("bbox",
+			mcp.Required(),
+			mcp.Description("Bounding box object with required fields: minLat (number), minLon (number), maxLat (number), maxLon (number). Example: {\"minLat\": 37.77, \"minLon\": -122.42, \"maxLat\": 37.78, \"maxLon\": -122.41}"),
+		),
+		mcp.WithNumber("zoom",
+			mcp.Required(),
+			mcp.Description("Zoom level (0-20)"),
+		),
+		mcp.WithNumber("max_tiles",
+			mcp.Description("Maximum number of tiles to list individually (max 4096); the count and size always cover the whole bbox"),
+			mcp.DefaultNumber(defaultMaxTilesListed),
+		),
+	)
+}
+
+// HandleTilesForBBox lists the tiles covering a bounding box at a zoom level
+func HandleTilesForBBox(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "tiles_for_bbox")
+
+	// Parse input
+	var input TilesForBBoxInput
+	inputJSON, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		logger.Error("failed to marshal input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").ToMCPResult(), nil
+	}
+
+	if err := json.Unmarshal(inputJSON, &input); err != nil {
+		logger.Error("failed to parse input", "error", err)
+		return core.NewError(core.ErrInvalidInput, "Invalid input format").
+			WithGuidance("Expected bbox object with minLat, minLon, maxLat, maxLon fields and a numeric zoom").
+			ToMCPResult(), nil
+	}
+
+	if err := validateTileBBox(input.BBox); err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).
+			WithGuidance("Use minLat < maxLat and minLon < maxLon within valid coordinate ranges").
+			ToMCPResult(), nil
+	}
+
+	if input.Zoom < 0 || input.Zoom > maxTileZoom {
+		return core.NewError(core.ErrInvalidParameter, fmt.Sprintf("Zoom must be between 0 and %d", maxTileZoom)).ToMCPResult(), nil
+	}
+
+	if input.MaxTiles <= 0 {
+		input.MaxTiles = defaultMaxTilesListed
+	}
+	if input.MaxTiles > maxTilesListed {
+		input.MaxTiles = maxTilesListed
+	}
+
+	output := tilesForBBox(input.BBox, input.Zoom, input.MaxTiles)
+
+	logger.Info("computed tiles for bbox", "zoom", output.Zoom, "count", output.Count, "truncated", output.Truncated)
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// validateTileBBox checks that a bounding box is well formed
+func validateTileBBox(bbox geo.BoundingBox) error {
+	if err := core.ValidateCoords(bbox.MinLat, bbox.MinLon); err != nil {
+		return err
+	}
+	if err := core.ValidateCoords(bbox.MaxLat, bbox.MaxLon); err != nil {
+		return err
+	}
+	if bbox.MinLat >= bbox.MaxLat || bbox.MinLon >= bbox.MaxLon {
+		return fmt.Errorf("invalid bounding box: minLat must be less than maxLat and minLon less than maxLon")
+	}
+	return nil
+}
+
+// tilesForBBox computes the tile range covering bbox and lists up to limit tiles
+func tilesForBBox(bbox geo.BoundingBox, zoom, limit int) TilesForBBoxOutput {
+	maxIndex := (1 << zoom) - 1
+
+	// Tile y grows southwards, so the north-west corner gives the minimum
+	minX, minY := core.LatLonToTile(bbox.MaxLat, bbox.MinLon, zoom)
+	maxX, maxY := core.LatLonToTile(bbox.MinLat, bbox.MaxLon, zoom)
+
+	// The eastern and southern edges of the world fall one past the last tile
+	minX, maxX = clampTileIndex(minX, maxIndex), clampTileIndex(maxX, maxIndex)
+	minY, maxY = clampTileIndex(minY, maxIndex), clampTileIndex(maxY, maxIndex)
+
+	count := (maxX - minX + 1) * (maxY - minY + 1)
+
+	output := TilesForBBoxOutput{
+		Zoom:           zoom,
+		MinX:           minX,
+		MaxX:           maxX,
+		MinY:           minY,
+		MaxY:           maxY,
+		Count:          count,
+		EstimatedBytes: int64(count) * estimatedTileBytes,
+		Tiles:          make([]TileRef, 0, min(count, limit)),
+		Truncated:      count > limit,
+	}
+
+	for y := minY; y <= maxY && len(output.Tiles) < limit; y++ {
+		for x := minX; x <= maxX && len(output.Tiles) < limit; x++ {
+			output.Tiles = append(output.Tiles, TileRef{X: x, Y: y, Zoom: zoom})
+		}
+	}
+
+	return output
+}
+
+// clampTileIndex limits a tile index to [0, maxIndex]
+func clampTileIndex(v, maxIndex int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > maxIndex {
+		return maxIndex
+	}
+	return v
+}
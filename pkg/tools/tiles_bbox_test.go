@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+func TestTilesForBBox(t *testing.T) {
+	// A small area of San Francisco spans a 2x2 block of tiles at zoom 15
+	bbox := geo.BoundingBox{MinLat: 37.77, MinLon: -122.42, MaxLat: 37.78, MaxLon: -122.40}
+
+	out := tilesForBBox(bbox, 15, 100)
+	if out.Count != (out.MaxX-out.MinX+1)*(out.MaxY-out.MinY+1) {
+		t.Errorf("count %d does not match range x[%d,%d] y[%d,%d]", out.Count, out.MinX, out.MaxX, out.MinY, out.MaxY)
+	}
+	if len(out.Tiles) != out.Count || out.Truncated {
+		t.Errorf("expected all %d tiles listed, got %d (truncated=%v)", out.Count, len(out.Tiles), out.Truncated)
+	}
+	if out.EstimatedBytes != int64(out.Count)*estimatedTileBytes {
+		t.Errorf("unexpected estimated bytes %d", out.EstimatedBytes)
+	}
+	for _, tile := range out.Tiles {
+		if tile.Zoom != 15 || tile.X < out.MinX || tile.X > out.MaxX || tile.Y < out.MinY || tile.Y > out.MaxY {
+			t.Errorf("tile %+v outside reported range", tile)
+		}
+	}
+
+	// The whole world at zoom 0 is a single tile
+	world := tilesForBBox(geo.BoundingBox{MinLat: -85, MinLon: -180, MaxLat: 85, MaxLon: 180}, 0, 100)
+	if world.Count != 1 || world.Tiles[0] != (TileRef{X: 0, Y: 0, Zoom: 0}) {
+		t.Errorf("expected a single world tile, got %+v", world)
+	}
+
+	// Listing is truncated but the count covers the whole bbox
+	truncated := tilesForBBox(geo.BoundingBox{MinLat: -85, MinLon: -180, MaxLat: 85, MaxLon: 180}, 3, 10)
+	if truncated.Count != 64 || len(truncated.Tiles) != 10 || !truncated.Truncated {
+		t.Errorf("expected 64 tiles with 10 listed, got count=%d listed=%d", truncated.Count, len(truncated.Tiles))
+	}
+}
+
+func TestHandleTilesForBBoxValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		wantError bool
+	}{
+		{
+			name: "Valid request",
+			arguments: map[string]any{
+				"bbox": map[string]any{"minLat": 37.77, "minLon": -122.42, "maxLat": 37.78, "maxLon": -122.41},
+				"zoom": 14.0,
+			},
+		},
+		{
+			name: "Inverted bbox",
+			arguments: map[string]any{
+				"bbox": map[string]any{"minLat": 37.78, "minLon": -122.42, "maxLat": 37.77, "maxLon": -122.41},
+				"zoom": 14.0,
+			},
+			wantError: true,
+		},
+		{
+			name: "Zoom too high",
+			arguments: map[string]any{
+				"bbox": map[string]any{"minLat": 37.77, "minLon": -122.42, "maxLat": 37.78, "maxLon": -122.41},
+				"zoom": 25.0,
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "tiles_for_bbox",
+					Arguments: tt.arguments,
+				},
+			}
+
+			result, err := HandleTilesForBBox(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v", result.IsError, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+
+			var out TilesForBBoxOutput
+			text := result.Content[0].(mcp.TextContent).Text
+			if err := json.Unmarshal([]byte(text), &out); err != nil {
+				t.Fatalf("failed to decode output: %v", err)
+			}
+			if out.Count == 0 || out.Zoom != 14 {
+				t.Errorf("unexpected output %+v", out)
+			}
+		})
+	}
+}
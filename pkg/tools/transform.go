@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmespath/go-jmespath"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+)
+
+// maxTransformLength limits the size of a transform expression
+const maxTransformLength = 1024
+
+// withTransform adds the transform parameter to every tool and wraps the
+// handlers to apply it. It runs after field selection, so a transform sees
+// the trimmed result.
+func withTransform(defs []ToolDefinition) []ToolDefinition {
+	for i, def := range defs {
+		mcp.WithString("transform",
+			mcp.Description("JMESPath expression applied to the JSON result before it is returned, e.g. \"places[?distance < `500`].{name: name, distance: distance}\" or \"sort_by(places, &distance)[:3].name\""),
+		)(&defs[i].Tool)
+		defs[i].Handler = transformHandler(def.Handler)
+	}
+	return defs
+}
+
+// transformHandler wraps a handler to reshape its JSON result with the
+// requested JMESPath expression
+func transformHandler(handler func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		expr, err := parseTransform(req)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance("Pass transform as a JMESPath expression such as \"places[*].name\"; see https://jmespath.org").
+				ToMCPResult(), nil
+		}
+
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError || expr == nil {
+			return result, err
+		}
+
+		transformed, err := transformResult(result, expr)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).
+				WithGuidance("Check the transform against the tool's output, or call the tool without it to see the full result").
+				ToMCPResult(), nil
+		}
+		return transformed, nil
+	}
+}
+
+// parseTransform reads and compiles the optional transform argument
+func parseTransform(req mcp.CallToolRequest) (*jmespath.JMESPath, error) {
+	args, ok := req.Params.Arguments.(map[string]any)
+	if !ok || args["transform"] == nil {
+		return nil, nil
+	}
+
+	source, ok := args["transform"].(string)
+	if !ok {
+		return nil, fmt.Errorf("transform must be a string")
+	}
+	if source == "" {
+		return nil, nil
+	}
+	if len(source) > maxTransformLength {
+		return nil, fmt.Errorf("transform must be at most %d characters", maxTransformLength)
+	}
+
+	expr, err := jmespath.Compile(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform: %v", err)
+	}
+	return expr, nil
+}
+
+// transformResult applies an expression to the JSON text of a result.
+// Other content, such as deprecation warnings, is kept after it.
+func transformResult(result *mcp.CallToolResult, expr *jmespath.JMESPath) (*mcp.CallToolResult, error) {
+	if len(result.Content) == 0 {
+		return result, nil
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return nil, fmt.Errorf("transform only applies to JSON results")
+	}
+
+	var root any
+	if err := json.Unmarshal([]byte(text.Text), &root); err != nil {
+		return nil, fmt.Errorf("transform only applies to JSON results")
+	}
+
+	value, err := expr.Search(root)
+	if err != nil {
+		return nil, fmt.Errorf("transform failed: %v", err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("transform failed: %v", err)
+	}
+
+	transformed := *result
+	transformed.Content = append([]mcp.Content{mcp.NewTextContent(string(data))}, result.Content[1:]...)
+	return &transformed, nil
+}
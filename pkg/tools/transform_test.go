@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithTransform(t *testing.T) {
+	for _, def := range NewRegistry(slog.Default()).GetToolDefinitions() {
+		if _, ok := def.Tool.InputSchema.Properties["transform"]; !ok {
+			t.Errorf("tool %s has no transform parameter", def.Name)
+		}
+	}
+}
+
+func TestTransformHandler(t *testing.T) {
+	handler := transformHandler(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText(`{"places":[{"name":"A","distance":300,"osm_id":4294967296123},{"name":"B","distance":20,"osm_id":1}]}`)
+		result.Content = append(result.Content, mcp.NewTextContent("warning"))
+		return result, nil
+	})
+	call := func(transform any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{"transform": transform}}}
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	result := call("sort_by(places, &distance)[*].{n: name, id: osm_id}")
+	if result.IsError {
+		t.Fatalf("unexpected error result %v", result)
+	}
+	if got := result.Content[0].(mcp.TextContent).Text; got != `[{"id":1,"n":"B"},{"id":4294967296123,"n":"A"}]` {
+		t.Errorf("unexpected transform output %s", got)
+	}
+	if len(result.Content) != 2 || result.Content[1].(mcp.TextContent).Text != "warning" {
+		t.Error("expected trailing content to be kept")
+	}
+
+	// The example in the parameter description
+	if got := call("places[?distance < `500`].{name: name, distance: distance}").Content[0].(mcp.TextContent).Text; got != `[{"distance":300,"name":"A"},{"distance":20,"name":"B"}]` {
+		t.Errorf("unexpected filter output %s", got)
+	}
+
+	// An empty transform leaves the result unchanged
+	if got := call("").Content[0].(mcp.TextContent).Text; got[0] != '{' {
+		t.Errorf("expected the untransformed result, got %s", got)
+	}
+
+	for _, invalid := range []any{"places[", 42, "length(places, places)"} {
+		if result := call(invalid); !result.IsError {
+			t.Errorf("expected an error result for transform %v", invalid)
+		}
+	}
+}
+
+func TestTransformNonJSON(t *testing.T) {
+	handler := transformHandler(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("not json"), nil
+	})
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{"transform": "a"}}}
+	result, err := handler(context.Background(), req)
+	if err != nil || !result.IsError {
+		t.Errorf("expected an error result for a non-JSON result, got %v %v", result, err)
+	}
+}
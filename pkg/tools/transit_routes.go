@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/transit/gtfs"
+)
+
+const (
+	// defaultTransitStopRadius is the default search radius for GTFS stops in meters
+	defaultTransitStopRadius = 200.0
+	// maxTransitStopRadius caps the GTFS stop search radius in meters
+	maxTransitStopRadius = 1000.0
+	// maxTransitStops caps the number of stops returned for a location search
+	maxTransitStops = 10
+)
+
+// TransitStopRoute is a route serving a stop, with the destinations it runs to
+type TransitStopRoute struct {
+	gtfs.Route
+	Headsigns []string `json:"headsigns,omitempty"`
+}
+
+// TransitStopRoutes lists the routes serving one GTFS stop
+type TransitStopRoutes struct {
+	Feed     string             `json:"feed"`
+	Stop     gtfs.Stop          `json:"stop"`
+	Distance float64            `json:"distance"` // meters from the requested point or OSM stop
+	Routes   []TransitStopRoute `json:"routes"`
+}
+
+// FindTransitRoutesAtStopOutput defines the output for find_transit_routes_at_stop
+type FindTransitRoutesAtStopOutput struct {
+	Stops []TransitStopRoutes `json:"stops"`
+}
+
+// FindTransitRoutesAtStopTool returns a tool definition for routes serving a stop
+func FindTransitRoutesAtStopTool() mcp.Tool {
+	return mcp.NewTool("find_transit_routes_at_stop",
+		mcp.WithDescription("List the bus, tram, metro, rail and ferry routes serving a stop, with their destinations, from the loaded GTFS timetables. Give an OSM stop_id, or a latitude and longitude to list the stops nearby"),
+		mcp.WithString("stop_id",
+			mcp.Description("OSM ID of the stop, e.g. node/123456. A bare number is taken as a node ID"),
+		),
+		mcp.WithNumber("latitude",
+			mcp.Description("Latitude to search for stops around, when no stop_id is given"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Description("Longitude to search for stops around, when no stop_id is given"),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("Search radius in meters for a latitude/longitude search (max 1000)"),
+			mcp.DefaultNumber(defaultTransitStopRadius),
+		),
+	)
+}
+
+// HandleFindTransitRoutesAtStop lists the GTFS routes serving a stop
+func HandleFindTransitRoutesAtStop(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "find_transit_routes_at_stop")
+
+	feeds := gtfs.Feeds()
+	if len(feeds) == 0 {
+		return core.NewError(core.ErrServiceUnavailable, "No GTFS feed is loaded").
+			WithGuidance("Start the server with --gtfs-feed to load transit timetables").
+			ToMCPResult(), nil
+	}
+
+	output := FindTransitRoutesAtStopOutput{Stops: []TransitStopRoutes{}}
+
+	if stopID := mcp.ParseString(req, "stop_id", ""); stopID != "" {
+		elementType, id, err := parseElementID(stopID)
+		if err != nil {
+			return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+		}
+		stop, errResult := lookupTransitStop(ctx, logger, elementType, id)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		for _, feed := range feeds {
+			match, ok := feed.MatchStop(stop)
+			if !ok {
+				continue
+			}
+			output.Stops = append(output.Stops, stopRoutes(feed, match, stop.Location))
+		}
+		if len(output.Stops) == 0 {
+			return core.NewError(core.ErrNoResults, fmt.Sprintf("No GTFS stop matches %s", stop.OSMID)).
+				WithGuidance("The loaded feeds may not cover this stop; try a latitude/longitude search").
+				ToMCPResult(), nil
+		}
+	} else {
+		lat, lon, err := core.ParseCoordsWithLog(req, logger, "latitude", "longitude")
+		if err != nil {
+			return core.NewError(core.ErrInvalidInput, err.Error()).
+				WithGuidance("Provide either stop_id or latitude and longitude").
+				ToMCPResult(), nil
+		}
+		radius := mcp.ParseFloat64(req, "radius", defaultTransitStopRadius)
+		if err := core.ValidateRadius(radius, maxTransitStopRadius); err != nil {
+			return core.NewError(core.ErrInvalidRadius, err.Error()).ToMCPResult(), nil
+		}
+
+		at := geo.Location{Latitude: lat, Longitude: lon}
+		for _, feed := range feeds {
+			for _, stop := range feed.NearbyStops(at, radius) {
+				routes := stopRoutes(feed, stop, at)
+				if len(routes.Routes) > 0 {
+					output.Stops = append(output.Stops, routes)
+				}
+			}
+		}
+		sortByDistanceThenID(output.Stops, func(s TransitStopRoutes) (float64, string) {
+			return s.Distance, s.Feed + "/" + s.Stop.ID
+		})
+		if len(output.Stops) > maxTransitStops {
+			output.Stops = output.Stops[:maxTransitStops]
+		}
+	}
+
+	resultBytes, err := json.Marshal(output)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// stopRoutes collects the routes and destinations served at a GTFS stop
+func stopRoutes(feed *gtfs.Feed, stop *gtfs.Stop, from geo.Location) TransitStopRoutes {
+	result := TransitStopRoutes{
+		Feed:     feed.Name(),
+		Stop:     *stop,
+		Distance: geo.HaversineDistance(from.Latitude, from.Longitude, stop.Location.Latitude, stop.Location.Longitude),
+		Routes:   []TransitStopRoute{},
+	}
+	for _, route := range feed.RoutesAtStop(stop.ID) {
+		result.Routes = append(result.Routes, TransitStopRoute{
+			Route:     route,
+			Headsigns: feed.Headsigns(stop.ID, route.ID),
+		})
+	}
+	return result
+}
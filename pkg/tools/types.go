@@ -20,24 +20,33 @@ type Address struct {
 
 // Place represents a named location with coordinates and optional address
 type Place struct {
-	ID         string   `json:"id,omitempty"`
-	Name       string   `json:"name"`
-	Location   Location `json:"location"`
-	Address    Address  `json:"address,omitempty"`
-	Categories []string `json:"categories,omitempty"`
-	Rating     float64  `json:"rating,omitempty"`
-	Distance   float64  `json:"distance,omitempty"`   // in meters
-	Importance float64  `json:"importance,omitempty"` // Nominatim importance score
+	ID              string            `json:"id,omitempty"`
+	Name            string            `json:"name"`
+	Location        Location          `json:"location"`
+	Address         Address           `json:"address,omitempty"`
+	Categories      []string          `json:"categories,omitempty"`
+	Rating          float64           `json:"rating,omitempty"`
+	Distance        float64           `json:"distance,omitempty"`          // in meters
+	Importance      float64           `json:"importance,omitempty"`        // Nominatim importance score
+	Contact         *Contact          `json:"contact,omitempty"`           // normalized phone, website, email and social links
+	OpeningHours    string            `json:"opening_hours,omitempty"`     // raw opening_hours tag
+	Open            *bool             `json:"open,omitempty"`              // open at the evaluated time, when the hours are known
+	ClosesInMinutes *int              `json:"closes_in_minutes,omitempty"` // minutes until closing, when open
+	OpensInMinutes  *int              `json:"opens_in_minutes,omitempty"`  // minutes until opening, when closed
+	NextOpen        string            `json:"next_open,omitempty"`         // local RFC 3339 time of the next opening, when closed
+	Images          []PlaceImage      `json:"images,omitempty"`            // resolved image URLs, when requested
+	Level           *PlaceLevel       `json:"level,omitempty"`             // indoor level, when mapped
+	Tags            map[string]string `json:"tags,omitempty"`              // raw OSM tags, when requested
 }
 
 // Route represents a path between two locations
 type Route struct {
-	Distance     float64    `json:"distance"` // in meters
-	Duration     float64    `json:"duration"` // in seconds
-	StartPoint   Location   `json:"start_point"`
-	EndPoint     Location   `json:"end_point"`
-	Instructions []string   `json:"instructions"`
-	Polyline     []Location `json:"polyline,omitempty"`
+	Distance     float64  `json:"distance"` // in meters
+	Duration     float64  `json:"duration"` // in seconds
+	StartPoint   Location `json:"start_point"`
+	EndPoint     Location `json:"end_point"`
+	Instructions []string `json:"instructions"`
+	RouteGeometry
 }
 
 // TransportMode represents different transportation methods
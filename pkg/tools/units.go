@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Unit systems for converted distance and speed values
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+	UnitsNautical = "nautical"
+)
+
+const (
+	metersPerMile         = 1609.344
+	metersPerNauticalMile = 1852.0
+
+	// unitsDescription documents the units parameter shared by distance tools
+	unitsDescription = "Also report distance, average speed and duration in a unit system: metric (km, km/h), imperial (mi, mph) or nautical (nmi, kn). Meters and seconds are always returned"
+)
+
+// ConvertedMeasures are a distance, and optionally a duration, expressed in
+// a requested unit system
+type ConvertedMeasures struct {
+	System       string  `json:"system"`
+	Distance     float64 `json:"distance"`
+	DistanceUnit string  `json:"distance_unit"`        // km, mi or nmi
+	Speed        float64 `json:"speed,omitempty"`      // average speed
+	SpeedUnit    string  `json:"speed_unit,omitempty"` // km/h, mph or kn
+	Duration     string  `json:"duration,omitempty"`   // e.g. "2h 05m"
+}
+
+// validUnits reports whether units names a supported unit system. An empty
+// value is valid and means no conversion.
+func validUnits(units string) bool {
+	switch units {
+	case "", UnitsMetric, UnitsImperial, UnitsNautical:
+		return true
+	default:
+		return false
+	}
+}
+
+// convertMeasures expresses meters, and seconds if positive, in a unit
+// system. It returns nil when no unit system was requested.
+func convertMeasures(units string, meters, seconds float64) *ConvertedMeasures {
+	var unitMeters float64
+	var distanceUnit, speedUnit string
+	switch units {
+	case UnitsMetric:
+		unitMeters, distanceUnit, speedUnit = 1000, "km", "km/h"
+	case UnitsImperial:
+		unitMeters, distanceUnit, speedUnit = metersPerMile, "mi", "mph"
+	case UnitsNautical:
+		unitMeters, distanceUnit, speedUnit = metersPerNauticalMile, "nmi", "kn"
+	default:
+		return nil
+	}
+
+	converted := &ConvertedMeasures{
+		System:       units,
+		Distance:     math.Round(meters/unitMeters*100) / 100,
+		DistanceUnit: distanceUnit,
+	}
+	if seconds > 0 {
+		converted.Speed = math.Round(meters/unitMeters/(seconds/3600)*10) / 10
+		converted.SpeedUnit = speedUnit
+		converted.Duration = formatDuration(seconds)
+	}
+	return converted
+}
+
+// formatDuration formats seconds as hours and minutes, e.g. "2h 05m"
+func formatDuration(seconds float64) string {
+	d := time.Duration(math.Round(seconds/60)) * time.Minute
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", hours, minutes)
+}
+
+// knotsToMetersPerSecond converts a speed in knots to meters per second
+func knotsToMetersPerSecond(knots float64) float64 {
+	return knots * metersPerNauticalMile / 3600
+}
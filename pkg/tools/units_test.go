@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestConvertMeasures(t *testing.T) {
+	if convertMeasures("", 1000, 60) != nil {
+		t.Error("expected no conversion without units")
+	}
+
+	tests := []struct {
+		units        string
+		meters       float64
+		seconds      float64
+		distance     float64
+		distanceUnit string
+		speed        float64
+		speedUnit    string
+		duration     string
+	}{
+		{UnitsMetric, 12500, 900, 12.5, "km", 50, "km/h", "15m"},
+		{UnitsImperial, 16093.44, 3600, 10, "mi", 10, "mph", "1h 00m"},
+		{UnitsNautical, 18520, 7500, 10, "nmi", 4.8, "kn", "2h 05m"},
+		{UnitsNautical, 1852, 0, 1, "nmi", 0, "", ""},
+	}
+	for _, tt := range tests {
+		got := convertMeasures(tt.units, tt.meters, tt.seconds)
+		if got == nil {
+			t.Fatalf("convertMeasures(%s) returned nil", tt.units)
+		}
+		if got.Distance != tt.distance || got.DistanceUnit != tt.distanceUnit ||
+			got.Speed != tt.speed || got.SpeedUnit != tt.speedUnit || got.Duration != tt.duration {
+			t.Errorf("convertMeasures(%s, %.0f, %.0f) = %+v", tt.units, tt.meters, tt.seconds, got)
+		}
+	}
+
+	if validUnits("furlongs") || !validUnits("") || !validUnits(UnitsNautical) {
+		t.Error("unexpected units validation")
+	}
+}
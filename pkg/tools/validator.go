@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
 )
 
 // ValidateOSMParameters validates common parameters used in OSM tools
@@ -67,18 +69,20 @@ func ValidateOSMParameters(req mcp.CallToolRequest, toolName string, logger *slo
 	}
 
 	// Validate coordinates range
-	if err := ValidateCoordinates(lat, lon); err != nil {
+	if err := core.ValidateCoords(lat, lon); err != nil {
 		logger.Error("coordinate validation failed", "error", err)
 		return 0, 0, 0, 0, NewGeocodeDetailedError(
-			"INVALID_COORDINATES",
+			coordinateErrorCode(err),
 			err.Error(),
 			"",
 			"Latitude must be between -90 and 90, longitude between -180 and 180",
 		), fmt.Errorf("invalid coordinates")
 	}
 
+	limits := searchLimitsFor(toolName)
+
 	// Parse radius with default
-	var radius float64 = 1000 // Default radius
+	radius := limits.DefaultRadius
 	if radiusStr != "" {
 		radius, err = strconv.ParseFloat(radiusStr, 64)
 		if err != nil {
@@ -94,18 +98,18 @@ func ValidateOSMParameters(req mcp.CallToolRequest, toolName string, logger *slo
 	}
 
 	// Validate radius range
-	if err := ValidateRadius(radius, 50000); err != nil {
+	if err := ValidateRadius(radius, limits.MaxRadius); err != nil {
 		logger.Error("radius validation failed", "radius", radius, "error", err)
 		return 0, 0, 0, 0, NewGeocodeDetailedError(
 			"INVALID_RADIUS",
 			err.Error(),
 			"",
-			"Radius must be positive and less than 50000 meters",
+			limits.radiusGuidance(),
 		), fmt.Errorf("invalid radius range")
 	}
 
 	// Parse limit with default
-	var limit int = 10 // Default limit
+	limit := limits.DefaultLimit
 	if limitStr != "" {
 		limitFloat, err := strconv.ParseFloat(limitStr, 64)
 		if err != nil {
@@ -121,13 +125,8 @@ func ValidateOSMParameters(req mcp.CallToolRequest, toolName string, logger *slo
 		limit = int(limitFloat)
 	}
 
-	// Cap limit to reasonable range
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 50 {
-		limit = 50
-	}
+	// Cap limit to the configured range
+	limit = limits.clampLimit(limit)
 
 	return lat, lon, radius, limit, nil, nil
 }
@@ -201,7 +200,7 @@ func ValidateRouteParameters(req mcp.CallToolRequest, logger *slog.Logger) (star
 	}
 
 	// Validate start coordinates range
-	if err = ValidateCoordinates(startLat, startLon); err != nil {
+	if err = core.ValidateCoords(startLat, startLon); err != nil {
 		logger.Error("start coordinate validation failed", "error", err)
 		result = NewGeocodeDetailedError(
 			"INVALID_START_COORDINATES",
@@ -241,7 +240,7 @@ func ValidateRouteParameters(req mcp.CallToolRequest, logger *slog.Logger) (star
 	}
 
 	// Validate end coordinates range
-	if err = ValidateCoordinates(endLat, endLon); err != nil {
+	if err = core.ValidateCoords(endLat, endLon); err != nil {
 		logger.Error("end coordinate validation failed", "error", err)
 		result = NewGeocodeDetailedError(
 			"INVALID_END_COORDINATES",
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolVersionSeparator separates a tool name from its version, as in
+// geocode_address@v2
+const toolVersionSeparator = "@v"
+
+// Deprecation describes a tool version that is being phased out
+type Deprecation struct {
+	Since       string `json:"since"`                 // date announced, YYYY-MM-DD
+	Sunset      string `json:"sunset,omitempty"`      // date after which it may be removed, YYYY-MM-DD
+	Replacement string `json:"replacement,omitempty"` // tool to migrate to, e.g. geocode_address@v2
+	Note        string `json:"note,omitempty"`        // what changes for callers
+}
+
+// message describes the deprecation for agents, as of a time
+func (d *Deprecation) message(name string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is deprecated", name)
+	if d.Replacement != "" {
+		fmt.Fprintf(&b, "; use %s instead", d.Replacement)
+	}
+	if d.Sunset != "" {
+		if sunset, err := time.Parse(time.DateOnly, d.Sunset); err == nil && !now.Before(sunset) {
+			fmt.Fprintf(&b, ". It passed its sunset date of %s and may be removed at any time", d.Sunset)
+		} else {
+			fmt.Fprintf(&b, ". It will be removed after %s", d.Sunset)
+		}
+	}
+	if d.Note != "" {
+		fmt.Fprintf(&b, ". %s", d.Note)
+	}
+	return b.String()
+}
+
+// splitToolVersion splits a tool name such as geocode_address@v2 into its
+// base name and version. Names without a version are version 0.
+func splitToolVersion(name string) (string, int) {
+	i := strings.LastIndex(name, toolVersionSeparator)
+	if i < 0 {
+		return name, 0
+	}
+	version, err := strconv.Atoi(name[i+len(toolVersionSeparator):])
+	if err != nil || version < 1 {
+		return name, 0
+	}
+	return name[:i], version
+}
+
+// withVersioning applies tool versions and deprecations. A behavior change
+// ships as a new definition named name@vN next to the unversioned tool, so
+// existing prompts keep their behavior. When a tool has several versions,
+// or its unversioned definition is set to a later Version, that definition
+// is also registered under its pinned version, e.g. name@v1. Deprecated
+// versions carry the deprecation in their description and metadata, and
+// their results carry a warning.
+func withVersioning(defs []ToolDefinition) []ToolDefinition {
+	names := make(map[string]bool, len(defs))
+	versioned := make(map[string]bool)
+	for i, def := range defs {
+		names[def.Name] = true
+		base, version := splitToolVersion(def.Name)
+		if version > 0 {
+			versioned[base] = true
+			defs[i].Version = version
+		} else if def.Version == 0 {
+			defs[i].Version = 1
+		}
+	}
+
+	result := make([]ToolDefinition, 0, len(defs))
+	for _, def := range defs {
+		meta := map[string]any{"version": def.Version}
+		if def.Deprecation != nil {
+			meta["deprecation"] = def.Deprecation
+			message := def.Deprecation.message(def.Name, time.Now())
+			def.Description = strings.TrimSpace(def.Description + " DEPRECATED: " + message + ".")
+			def.Tool.Description = strings.TrimSpace(def.Tool.Description + " DEPRECATED: " + message + ".")
+			def.Handler = deprecationHandler(def.Name, def.Deprecation, def.Handler)
+		}
+		def.Tool.Meta = mcp.NewMetaFromMap(meta)
+		result = append(result, def)
+
+		// Pin the unversioned tool under its own version once others exist
+		alias := fmt.Sprintf("%s%s%d", def.Name, toolVersionSeparator, def.Version)
+		if _, version := splitToolVersion(def.Name); version == 0 && (versioned[def.Name] || def.Version > 1) && !names[alias] {
+			pinned := def
+			pinned.Name = alias
+			pinned.Tool.Name = alias
+			result = append(result, pinned)
+			names[alias] = true
+		}
+	}
+	return result
+}
+
+// deprecationHandler wraps a deprecated tool's handler to add a sunset
+// warning to its results
+func deprecationHandler(name string, deprecation *Deprecation, handler func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		message := deprecation.message(name, time.Now())
+		// The JSON payload stays the first content item; the warning follows it
+		result.Content = append(result.Content, mcp.NewTextContent("Warning: "+message+"."))
+		if result.Meta == nil {
+			result.Meta = &mcp.Meta{}
+		}
+		if result.Meta.AdditionalFields == nil {
+			result.Meta.AdditionalFields = make(map[string]any)
+		}
+		result.Meta.AdditionalFields["deprecation"] = deprecation
+		return result, nil
+	}
+}
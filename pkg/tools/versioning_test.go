@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSplitToolVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		version int
+	}{
+		{"geocode_address", "geocode_address", 0},
+		{"geocode_address@v2", "geocode_address", 2},
+		{"geocode_address@v0", "geocode_address@v0", 0},
+		{"geocode_address@vx", "geocode_address@vx", 0},
+	}
+	for _, tt := range tests {
+		base, version := splitToolVersion(tt.name)
+		if base != tt.base || version != tt.version {
+			t.Errorf("splitToolVersion(%q) = %q, %d; want %q, %d", tt.name, base, version, tt.base, tt.version)
+		}
+	}
+}
+
+func TestWithVersioning(t *testing.T) {
+	handler := func(text string) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(text), nil
+		}
+	}
+	defs := withVersioning([]ToolDefinition{
+		{
+			Name:        "geocode_address",
+			Description: "Geocode an address.",
+			Tool:        mcp.NewTool("geocode_address", mcp.WithDescription("Geocode an address.")),
+			Handler:     handler(`{"v":1}`),
+			Deprecation: &Deprecation{Since: "2025-01-01", Sunset: "2000-01-01", Replacement: "geocode_address@v2"},
+		},
+		{
+			Name:    "geocode_address@v2",
+			Tool:    mcp.NewTool("geocode_address@v2"),
+			Handler: handler(`{"v":2}`),
+		},
+		{
+			Name:    "get_version",
+			Tool:    mcp.NewTool("get_version"),
+			Handler: handler(`{}`),
+		},
+	})
+
+	byName := make(map[string]ToolDefinition)
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	if len(defs) != 4 {
+		t.Fatalf("expected the three tools and a geocode_address@v1 alias, got %d definitions", len(defs))
+	}
+
+	pinned, ok := byName["geocode_address@v1"]
+	if !ok || pinned.Tool.Name != "geocode_address@v1" || pinned.Version != 1 {
+		t.Fatalf("unexpected pinned alias %+v", pinned)
+	}
+	if byName["geocode_address@v2"].Version != 2 || byName["get_version"].Version != 1 {
+		t.Error("unexpected versions")
+	}
+	if _, ok := byName["get_version@v1"]; ok {
+		t.Error("unversioned tools should not get an alias")
+	}
+
+	deprecated := byName["geocode_address"]
+	if !strings.Contains(deprecated.Tool.Description, "DEPRECATED: geocode_address is deprecated; use geocode_address@v2 instead") {
+		t.Errorf("description missing deprecation: %q", deprecated.Tool.Description)
+	}
+	if deprecated.Tool.Meta == nil || deprecated.Tool.Meta.AdditionalFields["deprecation"] == nil {
+		t.Error("expected deprecation metadata on the tool")
+	}
+
+	result, err := deprecated.Handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil || len(result.Content) != 2 {
+		t.Fatalf("expected the payload and a warning, got %v %v", result, err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != `{"v":1}` {
+		t.Errorf("payload changed to %s", text)
+	}
+	if warning := result.Content[1].(mcp.TextContent).Text; !strings.Contains(warning, "passed its sunset date") {
+		t.Errorf("unexpected warning %q", warning)
+	}
+
+	result, _ = byName["geocode_address@v2"].Handler(context.Background(), mcp.CallToolRequest{})
+	if len(result.Content) != 1 || result.Meta != nil {
+		t.Error("current versions should not carry a warning")
+	}
+
+	// Once promoted to the unversioned name, v2 stays reachable as @v2
+	promoted := withVersioning([]ToolDefinition{{Name: "geocode_address", Tool: mcp.NewTool("geocode_address"), Version: 2}})
+	if len(promoted) != 2 || promoted[1].Name != "geocode_address@v2" {
+		t.Errorf("expected a geocode_address@v2 alias, got %d definitions", len(promoted))
+	}
+}
+
+func TestDeprecationMessage(t *testing.T) {
+	d := &Deprecation{Since: "2025-01-01", Sunset: "2025-07-01", Note: "Addresses are now nested"}
+
+	before := d.message("geocode_address", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	if before != "geocode_address is deprecated. It will be removed after 2025-07-01. Addresses are now nested" {
+		t.Errorf("unexpected message %q", before)
+	}
+
+	after := d.message("geocode_address", time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC))
+	if !strings.Contains(after, "passed its sunset date of 2025-07-01") {
+		t.Errorf("unexpected message %q", after)
+	}
+}
+
+func TestRegistryToolVersions(t *testing.T) {
+	for _, def := range NewRegistry(slog.Default()).GetToolDefinitions() {
+		if def.Version < 1 {
+			t.Errorf("tool %s has no version", def.Name)
+		}
+		if def.Tool.Name != def.Name {
+			t.Errorf("tool %s is registered as %s", def.Name, def.Tool.Name)
+		}
+	}
+}
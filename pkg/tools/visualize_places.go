@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/render"
+)
+
+// defaultPlaceMarkers is the default number of places plotted
+const defaultPlaceMarkers = 20
+
+// PlaceLegendEntry maps a marker number to the place it marks
+type PlaceLegendEntry struct {
+	Number   int          `json:"number"`
+	ID       string       `json:"id,omitempty"`
+	Name     string       `json:"name,omitempty"`
+	Location geo.Location `json:"location"`
+}
+
+// VisualizePlacesOutput is the metadata returned with a places image
+type VisualizePlacesOutput struct {
+	View        render.View        `json:"view"`
+	Legend      []PlaceLegendEntry `json:"legend"`
+	Skipped     int                `json:"skipped,omitempty"` // places beyond max_markers or without a location
+	Tiles       int                `json:"tiles"`
+	Attribution string             `json:"attribution"`
+}
+
+// VisualizePlacesTool returns a tool definition for plotting places on a map
+func VisualizePlacesTool() mcp.Tool {
+	return mcp.NewTool("visualize_places",
+		mcp.WithDescription("Plot places as numbered markers on an OpenStreetMap image fitted to them. Returns the PNG as image content followed by a legend mapping marker numbers to place IDs and names, for asking which place the user means"),
+		mcp.WithArray("places",
+			mcp.Required(),
+			mcp.Description("Places to plot in order, e.g. the places array from find_nearby_places. Each needs a location ({latitude, longitude}), latitude/longitude, lat/lon or center, and may have id and name"),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithNumber("max_markers",
+			mcp.Description(fmt.Sprintf("Maximum number of places to plot (1-%d)", render.MaxMarkerNumber)),
+			mcp.DefaultNumber(defaultPlaceMarkers),
+		),
+		mcp.WithNumber("width",
+			mcp.Description(fmt.Sprintf("Image width in pixels (%d-%d)", render.MinSize, render.MaxSize)),
+			mcp.DefaultNumber(defaultRenderWidth),
+		),
+		mcp.WithNumber("height",
+			mcp.Description(fmt.Sprintf("Image height in pixels (%d-%d)", render.MinSize, render.MaxSize)),
+			mcp.DefaultNumber(defaultRenderHeight),
+		),
+		mcp.WithNumber("padding",
+			mcp.Description("Minimum space in pixels between markers and the image edge"),
+			mcp.DefaultNumber(defaultRenderPadding),
+		),
+		mcp.WithNumber("max_zoom",
+			mcp.Description(fmt.Sprintf("Highest zoom level to use for close places (1-%d)", render.MaxZoom)),
+			mcp.DefaultNumber(defaultRenderMaxZoom),
+		),
+	)
+}
+
+// HandleVisualizePlaces plots numbered place markers over stitched map tiles
+func HandleVisualizePlaces(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "visualize_places")
+
+	args := req.GetArguments()
+	places, ok := args["places"].([]any)
+	if !ok || len(places) == 0 {
+		return core.NewError(core.ErrInvalidParameter, "places must be a non-empty array").
+			WithGuidance("Pass the places array from a search tool such as find_nearby_places").
+			ToMCPResult(), nil
+	}
+
+	maxMarkers := int(mcp.ParseFloat64(req, "max_markers", defaultPlaceMarkers))
+	if maxMarkers < 1 || maxMarkers > render.MaxMarkerNumber {
+		return core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("max_markers must be between 1 and %d", render.MaxMarkerNumber)).ToMCPResult(), nil
+	}
+	width, height, padding, maxZoom, errResult := parseRenderSize(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	var legend []PlaceLegendEntry
+	for _, item := range places {
+		if len(legend) == maxMarkers {
+			break
+		}
+		place, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		loc, ok := placeLocation(place)
+		if !ok {
+			continue
+		}
+		legend = append(legend, PlaceLegendEntry{
+			Number:   len(legend) + 1,
+			ID:       placeString(place["id"]),
+			Name:     placeString(place["name"]),
+			Location: loc,
+		})
+	}
+	if len(legend) == 0 {
+		return core.NewError(core.ErrInvalidParameter, "no place has a valid location").
+			WithGuidance("Give each place a location object with latitude and longitude").
+			ToMCPResult(), nil
+	}
+
+	bbox := geo.NewBoundingBox()
+	for _, entry := range legend {
+		bbox.ExtendWithPoint(entry.Location.Latitude, entry.Location.Longitude)
+	}
+
+	view := render.FitBounds(*bbox, width, height, padding, maxZoom)
+	m, err := render.NewMap(ctx, view, renderTileFetcher)
+	if err != nil {
+		return renderErrorResult(err, logger), nil
+	}
+	// Draw in reverse so lower numbers sit on top where markers overlap
+	for i := len(legend) - 1; i >= 0; i-- {
+		m.DrawNumberedMarker(legend[i].Location, legend[i].Number, render.MarkerColor)
+	}
+
+	output := VisualizePlacesOutput{
+		View:        view,
+		Legend:      legend,
+		Skipped:     len(places) - len(legend),
+		Tiles:       m.Tiles,
+		Attribution: "© OpenStreetMap contributors",
+	}
+	return renderedMapResult(m, output, logger)
+}
+
+// placeLocation reads the coordinates of a place in the shapes returned by
+// the search and OSM element tools
+func placeLocation(place map[string]any) (geo.Location, bool) {
+	candidates := []struct {
+		obj      any
+		lat, lon string
+	}{
+		{place["location"], "latitude", "longitude"},
+		{place, "latitude", "longitude"},
+		{place, "lat", "lon"},
+		{place["center"], "lat", "lon"},
+		{place["center"], "latitude", "longitude"},
+	}
+	for _, c := range candidates {
+		obj, ok := c.obj.(map[string]any)
+		if !ok {
+			continue
+		}
+		lat, latOK := obj[c.lat].(float64)
+		lon, lonOK := obj[c.lon].(float64)
+		if latOK && lonOK && core.ValidateCoords(lat, lon) == nil {
+			return geo.Location{Latitude: lat, Longitude: lon}, true
+		}
+	}
+	return geo.Location{}, false
+}
+
+// placeString formats an ID or name, which may be a number for OSM elements
+func placeString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
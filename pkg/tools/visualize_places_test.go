@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleVisualizePlaces(t *testing.T) {
+	useBlankTiles(t)
+
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{
+		"places": []any{
+			map[string]any{"id": "node/1", "name": "Cafe A", "location": map[string]any{"latitude": 51.5010, "longitude": -0.1420}},
+			map[string]any{"id": float64(42), "lat": 51.5033, "lon": -0.1196},
+			map[string]any{"name": "No location"},
+			map[string]any{"id": "way/7", "name": "Park", "center": map[string]any{"lat": 51.5073, "lon": -0.1657}},
+			map[string]any{"id": "node/9", "name": "Over the limit", "latitude": 51.51, "longitude": -0.13},
+		},
+		"max_markers": 3,
+	}}}
+	result, err := HandleVisualizePlaces(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %v", err, result)
+	}
+	if _, ok := result.Content[0].(mcp.ImageContent); !ok {
+		t.Fatalf("expected image content first, got %T", result.Content[0])
+	}
+
+	var output VisualizePlacesOutput
+	if err := json.Unmarshal([]byte(result.Content[1].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Legend) != 3 || output.Skipped != 2 {
+		t.Fatalf("unexpected legend %+v, skipped %d", output.Legend, output.Skipped)
+	}
+	want := []string{"node/1", "42", "way/7"}
+	for i, entry := range output.Legend {
+		if entry.Number != i+1 || entry.ID != want[i] {
+			t.Errorf("legend %d = %+v, want id %s", i, entry, want[i])
+		}
+	}
+}
+
+func TestHandleVisualizePlacesValidation(t *testing.T) {
+	useBlankTiles(t)
+
+	invalid := []map[string]any{
+		{},
+		{"places": []any{}},
+		{"places": []any{map[string]any{"name": "nowhere"}}},
+		{"places": []any{map[string]any{"lat": 1.0, "lon": 1.0}}, "max_markers": 100},
+	}
+	for _, args := range invalid {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+		result, err := HandleVisualizePlaces(context.Background(), req)
+		if err != nil || !result.IsError {
+			t.Errorf("expected an error result for %v", args)
+		}
+	}
+}
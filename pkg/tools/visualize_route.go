@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+	"github.com/NERVsystems/osmmcp/pkg/render"
+)
+
+// Defaults for rendered map images
+const (
+	defaultRenderWidth   = 640
+	defaultRenderHeight  = 480
+	defaultRenderPadding = 32
+	defaultRenderMaxZoom = 17
+)
+
+// renderTileFetcher fetches the tiles stitched into rendered maps; tests
+// replace it to avoid the network
+var renderTileFetcher render.TileFetcher = core.FetchMapTile
+
+// VisualizeRouteOutput is the metadata returned with a route image
+type VisualizeRouteOutput struct {
+	View        render.View     `json:"view"`
+	RouteBounds geo.BoundingBox `json:"route_bounds"`
+	Start       geo.Location    `json:"start"`
+	End         geo.Location    `json:"end"`
+	Points      int             `json:"points"`
+	Distance    float64         `json:"distance"` // meters along the polyline
+	Tiles       int             `json:"tiles"`
+	Attribution string          `json:"attribution"`
+}
+
+// VisualizeRouteTool returns a tool definition for rendering a route on a map
+func VisualizeRouteTool() mcp.Tool {
+	return mcp.NewTool("visualize_route",
+		mcp.WithDescription("Render a route on an OpenStreetMap image. The view is fitted to the route automatically, with green and red markers at the start and end. Returns the PNG as image content followed by view metadata"),
+		mcp.WithString("polyline",
+			mcp.Required(),
+			mcp.Description("The encoded polyline string representing the route, e.g. from route_fetch"),
+		),
+		mcp.WithNumber("width",
+			mcp.Description(fmt.Sprintf("Image width in pixels (%d-%d)", render.MinSize, render.MaxSize)),
+			mcp.DefaultNumber(defaultRenderWidth),
+		),
+		mcp.WithNumber("height",
+			mcp.Description(fmt.Sprintf("Image height in pixels (%d-%d)", render.MinSize, render.MaxSize)),
+			mcp.DefaultNumber(defaultRenderHeight),
+		),
+		mcp.WithNumber("padding",
+			mcp.Description("Minimum space in pixels between the route and the image edge"),
+			mcp.DefaultNumber(defaultRenderPadding),
+		),
+		mcp.WithNumber("max_zoom",
+			mcp.Description(fmt.Sprintf("Highest zoom level to use for short routes (1-%d)", render.MaxZoom)),
+			mcp.DefaultNumber(defaultRenderMaxZoom),
+		),
+	)
+}
+
+// HandleVisualizeRoute renders a polyline over stitched map tiles
+func HandleVisualizeRoute(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "visualize_route")
+
+	polyline := mcp.ParseString(req, "polyline", "")
+	if polyline == "" {
+		return core.NewError(core.ErrInvalidParameter, "polyline is required").ToMCPResult(), nil
+	}
+	if !isPrintableASCII(polyline) {
+		return core.NewError(core.ErrInvalidParameter, "Failed to decode polyline: malformed input").ToMCPResult(), nil
+	}
+	points := osm.DecodePolyline(polyline)
+	if len(points) == 0 {
+		return core.NewError(core.ErrInvalidParameter, "Failed to decode polyline: malformed input").ToMCPResult(), nil
+	}
+
+	width, height, padding, maxZoom, errResult := parseRenderSize(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	bbox := geo.NewBoundingBox()
+	distance := 0.0
+	for i, p := range points {
+		bbox.ExtendWithPoint(p.Latitude, p.Longitude)
+		if i > 0 {
+			distance += geo.HaversineDistance(points[i-1].Latitude, points[i-1].Longitude, p.Latitude, p.Longitude)
+		}
+	}
+
+	view := render.FitBounds(*bbox, width, height, padding, maxZoom)
+	m, err := render.NewMap(ctx, view, renderTileFetcher)
+	if err != nil {
+		return renderErrorResult(err, logger), nil
+	}
+	m.DrawPath(points, render.RouteColor, 4)
+	m.DrawMarker(points[0], render.StartColor, 6)
+	m.DrawMarker(points[len(points)-1], render.EndColor, 6)
+
+	output := VisualizeRouteOutput{
+		View:        view,
+		RouteBounds: *bbox,
+		Start:       points[0],
+		End:         points[len(points)-1],
+		Points:      len(points),
+		Distance:    distance,
+		Tiles:       m.Tiles,
+		Attribution: "© OpenStreetMap contributors",
+	}
+	return renderedMapResult(m, output, logger)
+}
+
+// parseRenderSize reads and validates the image size parameters shared by
+// the rendering tools
+func parseRenderSize(req mcp.CallToolRequest) (width, height, padding, maxZoom int, errResult *mcp.CallToolResult) {
+	width = int(mcp.ParseFloat64(req, "width", defaultRenderWidth))
+	height = int(mcp.ParseFloat64(req, "height", defaultRenderHeight))
+	padding = int(mcp.ParseFloat64(req, "padding", defaultRenderPadding))
+	maxZoom = int(mcp.ParseFloat64(req, "max_zoom", defaultRenderMaxZoom))
+
+	switch {
+	case width < render.MinSize || width > render.MaxSize || height < render.MinSize || height > render.MaxSize:
+		errResult = core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("width and height must be between %d and %d pixels", render.MinSize, render.MaxSize)).ToMCPResult()
+	case padding < 0 || 2*padding >= width || 2*padding >= height:
+		errResult = core.NewError(core.ErrInvalidParameter, "padding must leave room for the map").ToMCPResult()
+	case maxZoom < 1 || maxZoom > render.MaxZoom:
+		errResult = core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("max_zoom must be between 1 and %d", render.MaxZoom)).ToMCPResult()
+	}
+	return width, height, padding, maxZoom, errResult
+}
+
+// renderErrorResult reports a failure to render a map, passing on tile
+// policy errors such as rate limits
+func renderErrorResult(err error, logger *slog.Logger) *mcp.CallToolResult {
+	logger.Error("failed to render map", "error", err)
+	var mcpErr *core.MCPError
+	if errors.As(err, &mcpErr) {
+		return mcpErr.ToMCPResult()
+	}
+	return core.ServiceError("Tile", http.StatusServiceUnavailable, err.Error()).ToMCPResult()
+}
+
+// renderedMapResult returns a rendered map as image content followed by its
+// metadata as JSON text
+func renderedMapResult(m *render.Map, metadata any, logger *slog.Logger) (*mcp.CallToolResult, error) {
+	image, err := m.PNG()
+	if err != nil {
+		logger.Error("failed to encode map image", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to encode map image").ToMCPResult(), nil
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		logger.Error("failed to marshal metadata", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+
+	// Link to large composites instead of inlining them as base64
+	if spooled := spoolBytes(image, "Rendered map", "image/png", logger); spooled != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.NewResourceLink(spooled.URI, spooled.Name,
+					fmt.Sprintf("Rendered map image (%d bytes), too large to return inline", spooled.Size), spooled.MIMEType),
+				mcp.NewTextContent(string(metadataJSON)),
+			},
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewImageContent(encodeToBase64(image), "image/png"),
+			mcp.NewTextContent(string(metadataJSON)),
+		},
+	}, nil
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/NERVsystems/osmmcp/pkg/render"
+)
+
+// useBlankTiles replaces the tile fetcher with one serving blank tiles for
+// the duration of a test
+func useBlankTiles(t *testing.T) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, render.TileSize, render.TileSize))); err != nil {
+		t.Fatal(err)
+	}
+	previous := renderTileFetcher
+	renderTileFetcher = func(ctx context.Context, x, y, zoom int) ([]byte, error) {
+		return buf.Bytes(), nil
+	}
+	t.Cleanup(func() { renderTileFetcher = previous })
+}
+
+func TestHandleVisualizeRoute(t *testing.T) {
+	useBlankTiles(t)
+
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{
+		"polyline": "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+		"width":    400,
+		"height":   300,
+	}}}
+	result, err := HandleVisualizeRoute(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %v", err, result)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected image and metadata content, got %d items", len(result.Content))
+	}
+
+	imageContent, ok := result.Content[0].(mcp.ImageContent)
+	if !ok || imageContent.MIMEType != "image/png" {
+		t.Fatalf("expected PNG image content, got %T", result.Content[0])
+	}
+	data, err := base64.StdEncoding.DecodeString(imageContent.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil || img.Bounds().Dx() != 400 || img.Bounds().Dy() != 300 {
+		t.Errorf("unexpected image %v %v", img.Bounds(), err)
+	}
+
+	var output VisualizeRouteOutput
+	if err := json.Unmarshal([]byte(result.Content[1].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Points != 3 || output.Tiles == 0 || output.View.Zoom < 1 {
+		t.Errorf("unexpected metadata %+v", output)
+	}
+	if output.View.Bounds.MinLat > output.RouteBounds.MinLat || output.View.Bounds.MaxLon < output.RouteBounds.MaxLon {
+		t.Errorf("view %+v does not cover the route %+v", output.View.Bounds, output.RouteBounds)
+	}
+}
+
+func TestHandleVisualizeRouteValidation(t *testing.T) {
+	useBlankTiles(t)
+
+	invalid := []map[string]any{
+		{},
+		{"polyline": "_p~iF~ps|U", "width": 10},
+		{"polyline": "_p~iF~ps|U", "padding": 400},
+		{"polyline": "_p~iF~ps|U", "max_zoom": 25},
+	}
+	for _, args := range invalid {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+		result, err := HandleVisualizeRoute(context.Background(), req)
+		if err != nil || !result.IsError {
+			t.Errorf("expected an error result for %v", args)
+		}
+	}
+}
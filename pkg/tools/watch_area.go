@@ -0,0 +1,386 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/NERVsystems/osmmcp/pkg/cache"
+	"github.com/NERVsystems/osmmcp/pkg/core"
+	"github.com/NERVsystems/osmmcp/pkg/osm"
+)
+
+const (
+	// defaultWatchInterval is the default minutes between checks of a
+	// watched area
+	defaultWatchInterval = 15
+	// minWatchInterval keeps polling within the Overpass usage policy
+	minWatchInterval = 5
+	// maxWatchInterval is one check a day
+	maxWatchInterval = 1440
+	// maxWatchRadius bounds the area watched, in meters
+	maxWatchRadius = 5000.0
+	// maxAreaWatches caps the watches polling at once
+	maxAreaWatches = 20
+	// watchURIPrefix prefixes the resource URI of each watched area
+	watchURIPrefix = "watch://areas/"
+)
+
+// WatchedPlace is a point of interest in a watched area
+type WatchedPlace struct {
+	ID       string            `json:"id"` // e.g. node/123
+	Name     string            `json:"name,omitempty"`
+	Location Location          `json:"location"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// WatchChanges lists the places that changed between two checks
+type WatchChanges struct {
+	Added    []WatchedPlace `json:"added,omitempty"`
+	Removed  []WatchedPlace `json:"removed,omitempty"`
+	Modified []WatchedPlace `json:"modified,omitempty"`
+	At       time.Time      `json:"at"`
+}
+
+// empty reports whether nothing changed
+func (c WatchChanges) empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// AreaWatch is the state of a watched area, served as its resource
+type AreaWatch struct {
+	URI             string         `json:"uri"`
+	Category        string         `json:"category"`
+	Center          Location       `json:"center"`
+	Radius          float64        `json:"radius"`
+	IntervalMinutes int            `json:"interval_minutes"`
+	CheckedAt       time.Time      `json:"checked_at"`
+	UpdatedAt       time.Time      `json:"updated_at"` // when the places last changed
+	Places          []WatchedPlace `json:"places"`
+	LastChange      *WatchChanges  `json:"last_change,omitempty"`
+}
+
+// areaWatch polls an area and keeps its latest state
+type areaWatch struct {
+	mu     sync.Mutex
+	state  AreaWatch
+	query  string
+	cancel context.CancelFunc
+}
+
+var (
+	areaWatches   = make(map[string]*areaWatch)
+	areaWatchesMu sync.Mutex
+)
+
+// fetchWatchedPlaces runs a watch query; tests replace it
+var fetchWatchedPlaces = func(ctx context.Context, query string) ([]WatchedPlace, error) {
+	// Skip the response cache so each check sees current data
+	sum := sha256.Sum256([]byte(query))
+	cache.GetGlobalCache().Delete("overpass:" + hex.EncodeToString(sum[:]))
+
+	elements, err := executeOverpassQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return watchedPlaces(elements), nil
+}
+
+// WatchAreaTool returns a tool definition for watching an area for changes
+func WatchAreaTool() mcp.Tool {
+	return mcp.NewTool("watch_area",
+		mcp.WithDescription("Watch an area for points of interest of a category being added, removed or changed. "+
+			"The area is published as a watch://areas/ resource holding the current places and the last change, "+
+			"and a resource-updated notification is sent whenever a periodic check finds changes, so clients can read fresh data without polling tool calls"),
+		mcp.WithNumber("latitude",
+			mcp.Required(),
+			mcp.Description("The latitude coordinate of the center point"),
+		),
+		mcp.WithNumber("longitude",
+			mcp.Required(),
+			mcp.Description("The longitude coordinate of the center point"),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("Radius of the watched area in meters (max 5000)"),
+			mcp.DefaultNumber(1000),
+		),
+		mcp.WithString("category",
+			mcp.Required(),
+			mcp.Description("Category of places to watch (e.g., restaurant, cafe, charging_station)"),
+		),
+		mcp.WithNumber("interval_minutes",
+			mcp.Description("Minutes between checks (5 to 1440)"),
+			mcp.DefaultNumber(defaultWatchInterval),
+		),
+	)
+}
+
+// HandleWatchArea starts watching an area, or returns the existing watch
+// for the same area and category
+func HandleWatchArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "watch_area")
+
+	lat := mcp.ParseFloat64(req, "latitude", 0)
+	lon := mcp.ParseFloat64(req, "longitude", 0)
+	if err := core.ValidateCoords(lat, lon); err != nil {
+		return core.NewError(core.ErrInvalidInput, err.Error()).ToMCPResult(), nil
+	}
+	radius := mcp.ParseFloat64(req, "radius", 1000)
+	if err := ValidateRadius(radius, maxWatchRadius); err != nil {
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+	category := mcp.ParseString(req, "category", "")
+	if category == "" {
+		return core.NewError(core.ErrMissingParameter, "category is required").
+			WithGuidance("Example categories: restaurant, cafe, park, charging_station").
+			ToMCPResult(), nil
+	}
+	interval := int(mcp.ParseFloat64(req, "interval_minutes", defaultWatchInterval))
+	if interval < minWatchInterval || interval > maxWatchInterval {
+		return core.NewError(core.ErrInvalidParameter,
+			fmt.Sprintf("interval_minutes must be between %d and %d", minWatchInterval, maxWatchInterval)).ToMCPResult(), nil
+	}
+
+	query, err := buildWatchQuery(lat, lon, radius, category)
+	if err != nil {
+		logger.Error("failed to build query", "error", err)
+		return core.NewError(core.ErrInvalidParameter, err.Error()).ToMCPResult(), nil
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", query, interval)))
+	uri := watchURIPrefix + hex.EncodeToString(sum[:8])
+
+	areaWatchesMu.Lock()
+	existing, ok := areaWatches[uri]
+	count := len(areaWatches)
+	areaWatchesMu.Unlock()
+	if ok {
+		return watchResult(existing.snapshot(), logger)
+	}
+	if count >= maxAreaWatches {
+		return core.NewError(core.ErrInvalidInput, fmt.Sprintf("At most %d areas can be watched at once", maxAreaWatches)).
+			WithGuidance("Stop a watch with unwatch_area first").
+			ToMCPResult(), nil
+	}
+
+	places, err := fetchWatchedPlaces(ctx, query)
+	if err != nil {
+		logger.Error("failed to query watched area", "error", err)
+		if mcpErr, ok := err.(*core.MCPError); ok {
+			return mcpErr.ToMCPResult(), nil
+		}
+		return core.ServiceError("Overpass", http.StatusServiceUnavailable, "Failed to query the watched area").ToMCPResult(), nil
+	}
+
+	now := core.Now()
+	w := &areaWatch{
+		state: AreaWatch{
+			URI:             uri,
+			Category:        category,
+			Center:          Location{Latitude: lat, Longitude: lon},
+			Radius:          radius,
+			IntervalMinutes: interval,
+			CheckedAt:       now,
+			UpdatedAt:       now,
+			Places:          places,
+		},
+		query: query,
+	}
+
+	areaWatchesMu.Lock()
+	if existing, ok := areaWatches[uri]; ok {
+		// Another call started the same watch meanwhile
+		areaWatchesMu.Unlock()
+		return watchResult(existing.snapshot(), logger)
+	}
+	areaWatches[uri] = w
+	areaWatchesMu.Unlock()
+
+	// Publish the area as a resource and poll it for changes
+	if srv := server.ServerFromContext(ctx); srv != nil {
+		srv.AddResource(mcp.NewResource(uri, fmt.Sprintf("Watched %s near %.5f,%.5f", category, lat, lon),
+			mcp.WithResourceDescription(fmt.Sprintf("%s within %.0f m, checked every %d minutes", category, radius, interval)),
+			mcp.WithMIMEType("application/json"),
+		), w.read)
+
+		pollCtx, cancel := context.WithCancel(context.Background())
+		w.cancel = cancel
+		go w.run(pollCtx, srv, time.Duration(interval)*time.Minute, logger.With("uri", uri))
+	}
+
+	logger.Info("watching area", "uri", uri, "places", len(places))
+	return watchResult(w.snapshot(), logger)
+}
+
+// UnwatchAreaTool returns a tool definition for stopping an area watch
+func UnwatchAreaTool() mcp.Tool {
+	return mcp.NewTool("unwatch_area",
+		mcp.WithDescription("Stop watching an area started with watch_area and remove its resource"),
+		mcp.WithString("uri",
+			mcp.Required(),
+			mcp.Description("The watch://areas/ resource URI returned by watch_area"),
+		),
+	)
+}
+
+// HandleUnwatchArea stops an area watch
+func HandleUnwatchArea(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logger := slog.Default().With("tool", "unwatch_area")
+
+	uri := mcp.ParseString(req, "uri", "")
+	areaWatchesMu.Lock()
+	w, ok := areaWatches[uri]
+	delete(areaWatches, uri)
+	areaWatchesMu.Unlock()
+	if !ok {
+		return core.NewError(core.ErrInvalidParameter, "No area is watched with URI "+uri).
+			WithGuidance("Use the uri returned by watch_area").
+			ToMCPResult(), nil
+	}
+
+	if w.cancel != nil {
+		w.cancel()
+	}
+	if srv := server.ServerFromContext(ctx); srv != nil {
+		srv.DeleteResources(uri)
+	}
+
+	logger.Info("stopped watching area", "uri", uri)
+	return mcp.NewToolResultText(fmt.Sprintf(`{"uri":%q,"stopped":true}`, uri)), nil
+}
+
+// buildWatchQuery builds the Overpass query for the places of a category
+// around a point, matching any of the category's tags as find_nearby_places
+// does
+func buildWatchQuery(lat, lon, radius float64, category string) (string, error) {
+	return buildNearbyPlacesQuery(lat, lon, radius, mapCategoryToOSMTags(category), nil)
+}
+
+// watchedPlaces converts Overpass elements, sorted by ID
+func watchedPlaces(elements []osm.OverpassElement) []WatchedPlace {
+	places := make([]WatchedPlace, 0, len(elements))
+	for _, element := range elements {
+		location := Location{Latitude: element.Lat, Longitude: element.Lon}
+		if element.Center != nil {
+			location = Location{Latitude: element.Center.Lat, Longitude: element.Center.Lon}
+		}
+		places = append(places, WatchedPlace{
+			ID:       fmt.Sprintf("%s/%d", element.Type, element.ID),
+			Name:     element.Tags["name"],
+			Location: location,
+			Tags:     element.Tags,
+		})
+	}
+	sort.Slice(places, func(i, j int) bool { return places[i].ID < places[j].ID })
+	return places
+}
+
+// diffPlaces compares two checks of an area by place ID
+func diffPlaces(before, after []WatchedPlace) WatchChanges {
+	var changes WatchChanges
+	previous := make(map[string]WatchedPlace, len(before))
+	for _, place := range before {
+		previous[place.ID] = place
+	}
+	for _, place := range after {
+		old, ok := previous[place.ID]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, place)
+		case old.Location != place.Location || !maps.Equal(old.Tags, place.Tags):
+			changes.Modified = append(changes.Modified, place)
+		}
+		delete(previous, place.ID)
+	}
+	for _, place := range before {
+		if _, ok := previous[place.ID]; ok {
+			changes.Removed = append(changes.Removed, place)
+		}
+	}
+	return changes
+}
+
+// run checks the area every interval until ctx is cancelled
+func (w *areaWatch) run(ctx context.Context, srv *server.MCPServer, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx, srv, logger)
+		}
+	}
+}
+
+// check queries the area and, if its places changed, notifies clients that
+// the resource was updated
+func (w *areaWatch) check(ctx context.Context, srv *server.MCPServer, logger *slog.Logger) {
+	places, err := fetchWatchedPlaces(ctx, w.query)
+	if err != nil {
+		logger.Warn("failed to check watched area", "error", err)
+		return
+	}
+	changes := w.update(places, core.Now())
+	if changes.empty() {
+		return
+	}
+
+	logger.Info("watched area changed", "added", len(changes.Added), "removed", len(changes.Removed), "modified", len(changes.Modified))
+	if srv != nil {
+		srv.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": w.state.URI})
+	}
+}
+
+// update records a check of the area and returns what changed
+func (w *areaWatch) update(places []WatchedPlace, now time.Time) WatchChanges {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changes := diffPlaces(w.state.Places, places)
+	changes.At = now
+	w.state.CheckedAt = now
+	if !changes.empty() {
+		w.state.Places = places
+		w.state.UpdatedAt = now
+		w.state.LastChange = &changes
+	}
+	return changes
+}
+
+// snapshot returns a copy of the area's state
+func (w *areaWatch) snapshot() AreaWatch {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// read serves the area's state as its resource
+func (w *areaWatch) read(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(w.snapshot())
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(data)}}, nil
+}
+
+// watchResult returns the state of a watched area
+func watchResult(state AreaWatch, logger *slog.Logger) (*mcp.CallToolResult, error) {
+	resultBytes, err := json.Marshal(state)
+	if err != nil {
+		logger.Error("failed to marshal result", "error", err)
+		return core.NewError(core.ErrInternalError, "Failed to generate result").ToMCPResult(), nil
+	}
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestDiffPlaces(t *testing.T) {
+	before := []WatchedPlace{
+		{ID: "node/1", Name: "Cafe A", Tags: map[string]string{"amenity": "cafe"}},
+		{ID: "node/2", Name: "Cafe B", Tags: map[string]string{"amenity": "cafe"}},
+	}
+	after := []WatchedPlace{
+		{ID: "node/1", Name: "Cafe A", Tags: map[string]string{"amenity": "cafe", "opening_hours": "Mo-Fr 08:00-18:00"}},
+		{ID: "node/3", Name: "Cafe C", Tags: map[string]string{"amenity": "cafe"}},
+	}
+
+	changes := diffPlaces(before, after)
+	if len(changes.Added) != 1 || changes.Added[0].ID != "node/3" {
+		t.Errorf("expected node/3 added, got %+v", changes.Added)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0].ID != "node/2" {
+		t.Errorf("expected node/2 removed, got %+v", changes.Removed)
+	}
+	if len(changes.Modified) != 1 || changes.Modified[0].ID != "node/1" {
+		t.Errorf("expected node/1 modified, got %+v", changes.Modified)
+	}
+	if !diffPlaces(after, after).empty() {
+		t.Error("expected no changes between identical checks")
+	}
+}
+
+func TestWatchAreaResource(t *testing.T) {
+	places := []WatchedPlace{{ID: "node/1", Name: "Cafe A", Tags: map[string]string{"amenity": "cafe"}}}
+	original := fetchWatchedPlaces
+	fetchWatchedPlaces = func(ctx context.Context, query string) ([]WatchedPlace, error) {
+		return places, nil
+	}
+	defer func() { fetchWatchedPlaces = original }()
+
+	srv := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(false, true))
+	srv.AddTool(WatchAreaTool(), HandleWatchArea)
+	srv.AddTool(UnwatchAreaTool(), HandleUnwatchArea)
+
+	call := func(message string) []byte {
+		data, err := json.Marshal(srv.HandleMessage(context.Background(), json.RawMessage(message)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	data := call(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"watch_area","arguments":{"latitude":51.5,"longitude":-0.12,"category":"cafe"}}}`)
+	var result struct {
+		Result mcp.CallToolResult `json:"result"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	var watch AreaWatch
+	if err := json.Unmarshal([]byte(result.Result.Content[0].(mcp.TextContent).Text), &watch); err != nil {
+		t.Fatalf("unexpected result %s: %v", data, err)
+	}
+	if !strings.HasPrefix(watch.URI, watchURIPrefix) || len(watch.Places) != 1 {
+		t.Fatalf("unexpected watch %+v", watch)
+	}
+	defer call(`{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"unwatch_area","arguments":{"uri":"` + watch.URI + `"}}}`)
+
+	// A check that finds a new place updates the resource
+	places = append(places, WatchedPlace{ID: "node/2", Name: "Cafe B"})
+	areaWatchesMu.Lock()
+	w := areaWatches[watch.URI]
+	areaWatchesMu.Unlock()
+	w.check(context.Background(), srv, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	data = call(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"` + watch.URI + `"}}`)
+	var read struct {
+		Result struct {
+			Contents []mcp.TextResourceContents `json:"contents"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &read); err != nil || len(read.Result.Contents) != 1 {
+		t.Fatalf("unexpected resource %s: %v", data, err)
+	}
+	if err := json.Unmarshal([]byte(read.Result.Contents[0].Text), &watch); err != nil {
+		t.Fatal(err)
+	}
+	if len(watch.Places) != 2 || watch.LastChange == nil || len(watch.LastChange.Added) != 1 {
+		t.Errorf("expected the resource to show the added place, got %+v", watch)
+	}
+	if watch.UpdatedAt.IsZero() || watch.UpdatedAt.After(time.Now().Add(time.Minute)) {
+		t.Errorf("unexpected updated_at %v", watch.UpdatedAt)
+	}
+}
+
+func TestBuildWatchQuery(t *testing.T) {
+	query, err := buildWatchQuery(51.5, -0.12, 800, "restaurant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := categoryValuesFilter(mapCategoryToOSMTags("restaurant")["amenity"])
+	if !strings.HasPrefix(values, "~") {
+		t.Fatalf("restaurant should map to several amenity values, got %q", values)
+	}
+
+	// The category's values are alternatives on one filter, not ANDed tags
+	// that no element can match
+	if !strings.Contains(query, `node(around:800.0,51.500000,-0.120000)["amenity"`+values[:1]+`"`+values[1:]+`"]`) {
+		t.Errorf("query %s does not match any of the category's values", query)
+	}
+	if strings.Count(query, `["amenity"`) != 3 {
+		t.Errorf("query %s should have one amenity filter per element type", query)
+	}
+
+	// Unknown categories try each key as a separate union member
+	query, err = buildWatchQuery(51.5, -0.12, 800, "zorbing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{`node(around:800.0,51.500000,-0.120000)[amenity=zorbing];`, `way(around:800.0,51.500000,-0.120000)[shop=zorbing];`} {
+		if !strings.Contains(query, part) {
+			t.Errorf("query %s is missing %s", query, part)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package transit provides public transport data that OpenStreetMap does not
+// carry, such as departure times, through pluggable providers.
+package transit
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// ErrStopNotCovered is returned by a provider that has no data for a stop,
+// so the next provider can be tried
+var ErrStopNotCovered = errors.New("stop not covered by provider")
+
+// Stop identifies a transit stop mapped in OSM. Providers match it against
+// their own stop IDs using the ref tags and, failing that, the location.
+type Stop struct {
+	OSMID    string            `json:"osm_id"` // e.g. node/123
+	Name     string            `json:"name,omitempty"`
+	Location geo.Location      `json:"location"`
+	Refs     map[string]string `json:"refs,omitempty"` // ref, ref:*, gtfs:* and local_ref tags
+	Operator string            `json:"operator,omitempty"`
+	Network  string            `json:"network,omitempty"`
+}
+
+// StopFromTags builds a stop from an OSM element's tags
+func StopFromTags(osmID string, location geo.Location, tags map[string]string) Stop {
+	stop := Stop{
+		OSMID:    osmID,
+		Name:     tags["name"],
+		Location: location,
+		Refs:     make(map[string]string),
+		Operator: tags["operator"],
+		Network:  tags["network"],
+	}
+	for key, value := range tags {
+		if key == "ref" || key == "local_ref" || strings.HasPrefix(key, "ref:") || strings.HasPrefix(key, "gtfs:") {
+			stop.Refs[key] = value
+		}
+	}
+	return stop
+}
+
+// Departure is one departure from a stop
+type Departure struct {
+	Route     string     `json:"route"`              // route short name, e.g. "42"
+	Headsign  string     `json:"headsign,omitempty"` // destination shown on the vehicle
+	Mode      string     `json:"mode,omitempty"`     // bus, tram, subway, rail or ferry
+	Platform  string     `json:"platform,omitempty"`
+	Scheduled time.Time  `json:"scheduled"`
+	Expected  *time.Time `json:"expected,omitempty"` // real-time prediction, when known
+	Cancelled bool       `json:"cancelled,omitempty"`
+	Source    string     `json:"source,omitempty"` // provider name
+}
+
+// When returns the expected departure time, or the scheduled time when there
+// is no real-time prediction
+func (d Departure) When() time.Time {
+	if d.Expected != nil {
+		return *d.Expected
+	}
+	return d.Scheduled
+}
+
+// Provider supplies departures for stops, for example from a GTFS or
+// GTFS-RT feed or an operator API
+type Provider interface {
+	// Name identifies the provider in results and logs
+	Name() string
+
+	// Departures returns departures from a stop at or after a time, at most
+	// limit of them. It returns ErrStopNotCovered when it has no data for
+	// the stop.
+	Departures(ctx context.Context, stop Stop, from time.Time, limit int) ([]Departure, error)
+}
+
+var (
+	providers   []Provider
+	providersMu sync.RWMutex
+)
+
+// RegisterProvider adds a departures provider. Providers are consulted in
+// registration order.
+func RegisterProvider(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers = append(providers, p)
+}
+
+// Providers returns the registered departures providers
+func Providers() []Provider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return append([]Provider(nil), providers...)
+}
+
+// ResetProviders removes all registered providers
+func ResetProviders() {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers = nil
+}
+
+// NextDepartures collects departures for a stop from every provider that
+// covers it, ordered by expected departure time. It returns the names of the
+// providers that answered. ErrStopNotCovered is returned when no provider
+// covers the stop; other provider errors are returned only if no provider
+// answered.
+func NextDepartures(ctx context.Context, stop Stop, from time.Time, limit int) ([]Departure, []string, error) {
+	var (
+		departures []Departure
+		sources    []string
+		firstErr   error
+	)
+
+	for _, p := range Providers() {
+		found, err := p.Departures(ctx, stop, from, limit)
+		if errors.Is(err, ErrStopNotCovered) {
+			continue
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		for i := range found {
+			if found[i].Source == "" {
+				found[i].Source = p.Name()
+			}
+		}
+		departures = append(departures, found...)
+		sources = append(sources, p.Name())
+	}
+
+	if len(sources) == 0 {
+		if firstErr != nil {
+			return nil, nil, firstErr
+		}
+		return nil, nil, ErrStopNotCovered
+	}
+
+	sort.SliceStable(departures, func(i, j int) bool {
+		return departures[i].When().Before(departures[j].When())
+	})
+	if limit > 0 && len(departures) > limit {
+		departures = departures[:limit]
+	}
+	return departures, sources, nil
+}
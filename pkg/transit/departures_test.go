@@ -0,0 +1,153 @@
+package transit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// staticProvider returns fixed departures for the stops it covers
+type staticProvider struct {
+	name       string
+	covers     string
+	departures []Departure
+	err        error
+}
+
+func (p staticProvider) Name() string { return p.name }
+
+func (p staticProvider) Departures(_ context.Context, stop Stop, _ time.Time, _ int) ([]Departure, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if stop.OSMID != p.covers {
+		return nil, ErrStopNotCovered
+	}
+	return append([]Departure(nil), p.departures...), nil
+}
+
+func TestNextDepartures(t *testing.T) {
+	t.Cleanup(ResetProviders)
+	ResetProviders()
+
+	base := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	late := base.Add(9 * time.Minute)
+
+	RegisterProvider(staticProvider{name: "other", covers: "node/2"})
+	RegisterProvider(staticProvider{
+		name:   "feed-a",
+		covers: "node/1",
+		departures: []Departure{
+			{Route: "42", Scheduled: base.Add(5 * time.Minute), Expected: &late},
+			{Route: "7", Scheduled: base.Add(7 * time.Minute)},
+		},
+	})
+	RegisterProvider(staticProvider{
+		name:       "feed-b",
+		covers:     "node/1",
+		departures: []Departure{{Route: "N1", Scheduled: base.Add(2 * time.Minute), Source: "operator"}},
+	})
+
+	departures, sources, err := NextDepartures(context.Background(), Stop{OSMID: "node/1"}, base, 10)
+	if err != nil {
+		t.Fatalf("NextDepartures failed: %v", err)
+	}
+	if len(sources) != 2 || sources[0] != "feed-a" || sources[1] != "feed-b" {
+		t.Errorf("sources = %v, want [feed-a feed-b]", sources)
+	}
+
+	// Ordered by expected time, so the delayed 42 comes after the 7
+	var routes []string
+	for _, d := range departures {
+		routes = append(routes, d.Route)
+	}
+	if len(routes) != 3 || routes[0] != "N1" || routes[1] != "7" || routes[2] != "42" {
+		t.Errorf("routes = %v, want [N1 7 42]", routes)
+	}
+	if departures[1].Source != "feed-a" || departures[0].Source != "operator" {
+		t.Errorf("expected provider name as default source, got %q and %q", departures[1].Source, departures[0].Source)
+	}
+
+	limited, _, err := NextDepartures(context.Background(), Stop{OSMID: "node/1"}, base, 1)
+	if err != nil || len(limited) != 1 {
+		t.Errorf("expected 1 departure with limit 1, got %d (%v)", len(limited), err)
+	}
+}
+
+func TestNextDeparturesNotCovered(t *testing.T) {
+	t.Cleanup(ResetProviders)
+	ResetProviders()
+
+	RegisterProvider(staticProvider{name: "feed", covers: "node/1"})
+	if _, _, err := NextDepartures(context.Background(), Stop{OSMID: "node/9"}, time.Now(), 5); !errors.Is(err, ErrStopNotCovered) {
+		t.Errorf("expected ErrStopNotCovered, got %v", err)
+	}
+
+	failure := errors.New("feed down")
+	RegisterProvider(staticProvider{name: "broken", err: failure})
+	if _, _, err := NextDepartures(context.Background(), Stop{OSMID: "node/9"}, time.Now(), 5); !errors.Is(err, failure) {
+		t.Errorf("expected the provider error, got %v", err)
+	}
+}
+
+func TestStopFromTags(t *testing.T) {
+	stop := StopFromTags("node/1", geo.Location{Latitude: 1, Longitude: 2}, map[string]string{
+		"name":         "Central",
+		"ref":          "1234",
+		"ref:IFOPT":    "de:08111:6118",
+		"gtfs:stop_id": "S1",
+		"highway":      "bus_stop",
+		"operator":     "City Transit",
+	})
+
+	if stop.Name != "Central" || stop.Operator != "City Transit" {
+		t.Errorf("unexpected stop %+v", stop)
+	}
+	if len(stop.Refs) != 3 || stop.Refs["gtfs:stop_id"] != "S1" || stop.Refs["ref:IFOPT"] != "de:08111:6118" {
+		t.Errorf("unexpected refs %v", stop.Refs)
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	scheduled := time.Date(2025, 6, 1, 8, 5, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != "1234" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("osm_id") != "node/1" || r.URL.Query().Get("limit") != "5" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"departures": []Departure{{Route: "42", Headsign: "Airport", Scheduled: scheduled}},
+		})
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPProvider("", server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewHTTPProvider failed: %v", err)
+	}
+
+	departures, err := provider.Departures(context.Background(), Stop{OSMID: "node/1", Refs: map[string]string{"ref": "1234"}}, scheduled, 5)
+	if err != nil {
+		t.Fatalf("Departures failed: %v", err)
+	}
+	if len(departures) != 1 || departures[0].Headsign != "Airport" || !departures[0].Scheduled.Equal(scheduled) {
+		t.Errorf("unexpected departures %+v", departures)
+	}
+
+	if _, err := provider.Departures(context.Background(), Stop{OSMID: "node/2"}, scheduled, 5); !errors.Is(err, ErrStopNotCovered) {
+		t.Errorf("expected ErrStopNotCovered for an unknown stop, got %v", err)
+	}
+
+	if _, err := NewHTTPProvider("x", "not a url", nil); err == nil {
+		t.Error("expected an error for an invalid URL")
+	}
+}
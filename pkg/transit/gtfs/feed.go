@@ -0,0 +1,383 @@
+// Package gtfs loads static GTFS feeds and indexes their stops, routes and
+// timetables, so transit questions can be answered without a trip planner.
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+)
+
+// Stop is a GTFS stop, platform or station
+type Stop struct {
+	ID            string       `json:"id"`
+	Code          string       `json:"code,omitempty"` // stop_code shown to riders
+	Name          string       `json:"name"`
+	Location      geo.Location `json:"location"`
+	ParentStation string       `json:"parent_station,omitempty"`
+}
+
+// Route is a GTFS route
+type Route struct {
+	ID        string `json:"id"`
+	ShortName string `json:"short_name,omitempty"`
+	LongName  string `json:"long_name,omitempty"`
+	Mode      string `json:"mode"` // from route_type, e.g. bus, tram, rail
+	Agency    string `json:"agency,omitempty"`
+	Color     string `json:"color,omitempty"`
+}
+
+// DisplayName returns the short name, falling back to the long name
+func (r Route) DisplayName() string {
+	if r.ShortName != "" {
+		return r.ShortName
+	}
+	return r.LongName
+}
+
+// Trip is one run of a route
+type Trip struct {
+	ID        string
+	RouteID   string
+	ServiceID string
+	Headsign  string
+}
+
+// StopTime is a scheduled departure of a trip from a stop
+type StopTime struct {
+	TripID    string
+	Departure int // seconds after midnight of the service day, may exceed 24h
+	Sequence  int
+	Headsign  string // stop_headsign, overrides the trip headsign
+	NoPickup  bool   // pickup_type 1: passengers cannot board here
+}
+
+// Feed is a loaded, indexed GTFS feed
+type Feed struct {
+	name     string
+	Location *time.Location // agency time zone
+
+	Stops  map[string]*Stop
+	Routes map[string]*Route
+	Trips  map[string]*Trip
+
+	stopTimes  map[string][]StopTime // by stop ID, ordered by departure
+	stopRoutes map[string][]string   // route IDs serving each stop
+	children   map[string][]string   // stop IDs of each parent station
+	services   map[string]*service
+}
+
+var (
+	// feeds holds the loaded feeds consulted by the transit tools
+	feeds   []*Feed
+	feedsMu sync.RWMutex
+)
+
+// Register makes a loaded feed available to the transit tools
+func Register(feed *Feed) {
+	feedsMu.Lock()
+	defer feedsMu.Unlock()
+	feeds = append(feeds, feed)
+}
+
+// Feeds returns the registered feeds
+func Feeds() []*Feed {
+	feedsMu.RLock()
+	defer feedsMu.RUnlock()
+	return append([]*Feed(nil), feeds...)
+}
+
+// Reset removes all registered feeds
+func Reset() {
+	feedsMu.Lock()
+	defer feedsMu.Unlock()
+	feeds = nil
+}
+
+// opener opens a file of the feed by name
+type opener func(name string) (io.ReadCloser, error)
+
+// Load reads a GTFS feed from a zip archive or an unpacked directory. The
+// feed is named after the file unless a name is given.
+func Load(path, name string) (*Feed, error) {
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return load(name, func(file string) (io.ReadCloser, error) {
+			return os.Open(filepath.Join(path, file))
+		})
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GTFS archive: %w", err)
+	}
+	defer archive.Close()
+
+	return load(name, func(file string) (io.ReadCloser, error) {
+		for _, f := range archive.File {
+			// Some feeds nest their files in a single directory
+			if f.Name == file || filepath.Base(f.Name) == file {
+				return f.Open()
+			}
+		}
+		return nil, os.ErrNotExist
+	})
+}
+
+// load parses and indexes every table of a feed
+func load(name string, open opener) (*Feed, error) {
+	feed := &Feed{
+		name:       name,
+		Location:   time.UTC,
+		Stops:      make(map[string]*Stop),
+		Routes:     make(map[string]*Route),
+		Trips:      make(map[string]*Trip),
+		stopTimes:  make(map[string][]StopTime),
+		stopRoutes: make(map[string][]string),
+		children:   make(map[string][]string),
+		services:   make(map[string]*service),
+	}
+
+	agencies := make(map[string]string)
+	err := readTable(open, "agency.txt", false, func(row row) error {
+		agencies[row.get("agency_id")] = row.get("agency_name")
+		if tz := row.get("agency_timezone"); tz != "" && feed.Location == time.UTC {
+			loc, err := time.LoadLocation(tz)
+			if err != nil {
+				return fmt.Errorf("invalid agency_timezone %q", tz)
+			}
+			feed.Location = loc
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readTable(open, "stops.txt", true, func(row row) error {
+		lat, errLat := strconv.ParseFloat(row.get("stop_lat"), 64)
+		lon, errLon := strconv.ParseFloat(row.get("stop_lon"), 64)
+		if errLat != nil || errLon != nil {
+			// Generic nodes and boarding areas may have no location
+			return nil
+		}
+		stop := &Stop{
+			ID:            row.get("stop_id"),
+			Code:          row.get("stop_code"),
+			Name:          row.get("stop_name"),
+			Location:      geo.Location{Latitude: lat, Longitude: lon},
+			ParentStation: row.get("parent_station"),
+		}
+		feed.Stops[stop.ID] = stop
+		if stop.ParentStation != "" {
+			feed.children[stop.ParentStation] = append(feed.children[stop.ParentStation], stop.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readTable(open, "routes.txt", true, func(row row) error {
+		routeType, _ := strconv.Atoi(row.get("route_type"))
+		agency := agencies[row.get("agency_id")]
+		if agency == "" && len(agencies) == 1 {
+			for _, name := range agencies {
+				agency = name
+			}
+		}
+		route := &Route{
+			ID:        row.get("route_id"),
+			ShortName: row.get("route_short_name"),
+			LongName:  row.get("route_long_name"),
+			Mode:      routeMode(routeType),
+			Agency:    agency,
+			Color:     row.get("route_color"),
+		}
+		feed.Routes[route.ID] = route
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readTable(open, "trips.txt", true, func(row row) error {
+		trip := &Trip{
+			ID:        row.get("trip_id"),
+			RouteID:   row.get("route_id"),
+			ServiceID: row.get("service_id"),
+			Headsign:  row.get("trip_headsign"),
+		}
+		feed.Trips[trip.ID] = trip
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	routeSeen := make(map[string]map[string]bool)
+	err = readTable(open, "stop_times.txt", true, func(row row) error {
+		tripID := row.get("trip_id")
+		trip, ok := feed.Trips[tripID]
+		if !ok {
+			return nil
+		}
+		departure, ok := parseGTFSTime(row.get("departure_time"))
+		if !ok {
+			// Untimed stops between timepoints are skipped
+			departure, ok = parseGTFSTime(row.get("arrival_time"))
+			if !ok {
+				return nil
+			}
+		}
+		sequence, _ := strconv.Atoi(row.get("stop_sequence"))
+		stopID := row.get("stop_id")
+
+		feed.stopTimes[stopID] = append(feed.stopTimes[stopID], StopTime{
+			TripID:    tripID,
+			Departure: departure,
+			Sequence:  sequence,
+			Headsign:  row.get("stop_headsign"),
+			NoPickup:  row.get("pickup_type") == "1",
+		})
+
+		if routeSeen[stopID] == nil {
+			routeSeen[stopID] = make(map[string]bool)
+		}
+		if !routeSeen[stopID][trip.RouteID] {
+			routeSeen[stopID][trip.RouteID] = true
+			feed.stopRoutes[stopID] = append(feed.stopRoutes[stopID], trip.RouteID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for stopID, times := range feed.stopTimes {
+		sort.Slice(times, func(i, j int) bool { return times[i].Departure < times[j].Departure })
+		feed.stopTimes[stopID] = times
+		sort.Strings(feed.stopRoutes[stopID])
+	}
+
+	if err := loadCalendars(feed, open); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// row is one CSV record with named column access
+type row struct {
+	columns map[string]int
+	record  []string
+}
+
+// get returns a column value, or "" when the column is missing
+func (r row) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// readTable streams the rows of a feed table. Missing optional tables are
+// skipped.
+func readTable(open opener, name string, required bool, fn func(row) error) error {
+	file, err := open(name)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read %s header: %w", name, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.TrimPrefix(column, "\ufeff")
+		columns[strings.TrimSpace(column)] = i
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		if err := fn(row{columns: columns, record: record}); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+	}
+}
+
+// parseGTFSTime parses HH:MM:SS into seconds after midnight. Hours may
+// exceed 23 for trips running past midnight.
+func parseGTFSTime(value string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	h, errH := strconv.Atoi(parts[0])
+	m, errM := strconv.Atoi(parts[1])
+	s, errS := strconv.Atoi(parts[2])
+	if errH != nil || errM != nil || errS != nil || h < 0 || m < 0 || m > 59 || s < 0 || s > 59 {
+		return 0, false
+	}
+	return h*3600 + m*60 + s, true
+}
+
+// routeMode maps a GTFS route_type, including the extended types, to a mode
+func routeMode(routeType int) string {
+	switch {
+	case routeType == 0 || routeType == 5 || (routeType >= 900 && routeType < 1000):
+		return "tram"
+	case routeType == 1 || (routeType >= 400 && routeType < 500):
+		return "subway"
+	case routeType == 2 || (routeType >= 100 && routeType < 200):
+		return "rail"
+	case routeType == 3 || routeType == 11 || (routeType >= 200 && routeType < 300) || (routeType >= 700 && routeType < 800):
+		return "bus"
+	case routeType == 4 || (routeType >= 1000 && routeType < 1300):
+		return "ferry"
+	case routeType == 6 || (routeType >= 1300 && routeType < 1400):
+		return "aerialway"
+	case routeType == 7 || (routeType >= 1400 && routeType < 1500):
+		return "funicular"
+	case routeType == 12:
+		return "monorail"
+	default:
+		return "other"
+	}
+}
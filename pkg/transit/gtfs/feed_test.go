@@ -0,0 +1,237 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/transit"
+)
+
+// testFeed is a small feed: bus routes 10 and 9 and a night bus through the
+// Central station platforms, running on weekdays in June 2025
+var testFeed = map[string]string{
+	"agency.txt": "\ufeffagency_id,agency_name,agency_url,agency_timezone\n" +
+		"CT,City Transit,https://example.com,Europe/Berlin\n",
+	"stops.txt": "stop_id,stop_code,stop_name,stop_lat,stop_lon,location_type,parent_station\n" +
+		"central,,Central Station,52.5200,13.4050,1,\n" +
+		"central_a,A,Central Station,52.5201,13.4050,0,central\n" +
+		"central_b,B,Central Station,52.5199,13.4050,0,central\n" +
+		"park,4711,Park Street,52.5300,13.4100,0,\n",
+	"routes.txt": "route_id,agency_id,route_short_name,route_long_name,route_type\n" +
+		"r10,CT,10,Park Line,3\n" +
+		"r9,CT,9,Ring,3\n" +
+		"rn,CT,N1,Night Bus,3\n",
+	"trips.txt": "route_id,service_id,trip_id,trip_headsign\n" +
+		"r10,weekday,t1,Park Street\n" +
+		"r10,weekday,t2,Park Street\n" +
+		"r9,weekday,t3,Ring\n" +
+		"rn,weekday,t4,Airport\n",
+	"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence,pickup_type\n" +
+		"t1,08:00:00,08:00:00,central_a,1,0\n" +
+		"t1,08:10:00,08:10:00,park,2,1\n" +
+		"t2,08:30:00,08:30:00,central_a,1,0\n" +
+		"t2,08:40:00,08:40:00,park,2,1\n" +
+		"t3,08:15:00,08:15:00,central_b,1,0\n" +
+		"t4,24:30:00,24:30:00,central_b,1,0\n",
+	"calendar.txt": "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+		"weekday,1,1,1,1,1,0,0,20250601,20250630\n",
+	"calendar_dates.txt": "service_id,date,exception_type\n" +
+		"weekday,20250609,2\n",
+}
+
+// writeTestFeed writes the test feed as a directory and returns its path
+func writeTestFeed(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range testFeed {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func loadTestFeed(t *testing.T) *Feed {
+	t.Helper()
+	feed, err := Load(writeTestFeed(t), "city")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return feed
+}
+
+func TestLoad(t *testing.T) {
+	feed := loadTestFeed(t)
+
+	if feed.Name() != "city" {
+		t.Errorf("name = %q, want city", feed.Name())
+	}
+	if len(feed.Stops) != 4 || len(feed.Routes) != 3 || len(feed.Trips) != 4 {
+		t.Errorf("loaded %d stops, %d routes, %d trips", len(feed.Stops), len(feed.Routes), len(feed.Trips))
+	}
+	if feed.Location.String() != "Europe/Berlin" {
+		t.Errorf("location = %s, want Europe/Berlin from agency.txt", feed.Location)
+	}
+	if feed.Routes["r10"].Agency != "City Transit" || feed.Routes["r10"].Mode != "bus" {
+		t.Errorf("unexpected route %+v", feed.Routes["r10"])
+	}
+}
+
+func TestLoadZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(f)
+	for name, content := range testFeed {
+		// Nested in a directory, as some publishers ship them
+		w, err := archive.Create("feed/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	archive.Close()
+	f.Close()
+
+	feed, err := Load(path, "")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if feed.Name() != "city" || len(feed.Stops) != 4 {
+		t.Errorf("unexpected feed %q with %d stops", feed.Name(), len(feed.Stops))
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.zip"), ""); err == nil {
+		t.Error("expected an error for a missing feed")
+	}
+}
+
+func TestRoutesAtStop(t *testing.T) {
+	feed := loadTestFeed(t)
+
+	// The station includes the routes of its platforms, ordered numerically
+	var names []string
+	for _, route := range feed.RoutesAtStop("central") {
+		names = append(names, route.DisplayName())
+	}
+	if len(names) != 3 || names[0] != "9" || names[1] != "10" || names[2] != "N1" {
+		t.Errorf("routes at central = %v, want [9 10 N1]", names)
+	}
+
+	if headsigns := feed.Headsigns("central", "r10"); len(headsigns) != 1 || headsigns[0] != "Park Street" {
+		t.Errorf("headsigns = %v, want [Park Street]", headsigns)
+	}
+}
+
+func TestScheduledDepartures(t *testing.T) {
+	feed := loadTestFeed(t)
+	berlin := feed.Location
+
+	// Monday 2 June 2025 at 08:05
+	from := time.Date(2025, 6, 2, 8, 5, 0, 0, berlin)
+	departures := feed.ScheduledDepartures("central", from, 3)
+
+	var routes []string
+	for _, d := range departures {
+		routes = append(routes, d.Route)
+	}
+	// 9 at 08:15, 10 at 08:30, N1 at 00:30 the next morning
+	if len(routes) != 3 || routes[0] != "9" || routes[1] != "10" || routes[2] != "N1" {
+		t.Fatalf("departures = %v, want [9 10 N1]", routes)
+	}
+	if departures[0].Platform != "B" || departures[1].Platform != "A" {
+		t.Errorf("platforms = %q, %q; want B, A", departures[0].Platform, departures[1].Platform)
+	}
+	if !departures[2].Scheduled.Equal(time.Date(2025, 6, 3, 0, 30, 0, 0, berlin)) {
+		t.Errorf("night bus at %s, want 00:30 on 3 June", departures[2].Scheduled)
+	}
+
+	// Trips that do not pick up passengers are not departures
+	if park := feed.ScheduledDepartures("park", from, 10); len(park) != 0 {
+		t.Errorf("expected no departures at the drop-off only stop, got %d", len(park))
+	}
+
+	// Removed by calendar_dates.txt
+	if removed := feed.ScheduledDepartures("central_a", time.Date(2025, 6, 9, 7, 0, 0, 0, berlin), 10); len(removed) != 0 {
+		t.Errorf("expected no departures on an exception date, got %d", len(removed))
+	}
+
+	// Saturday, no service
+	if weekend := feed.ScheduledDepartures("central_a", time.Date(2025, 6, 7, 7, 0, 0, 0, berlin), 10); len(weekend) != 0 {
+		t.Errorf("expected no weekend departures, got %d", len(weekend))
+	}
+}
+
+func TestMatchStop(t *testing.T) {
+	feed := loadTestFeed(t)
+
+	tests := []struct {
+		name string
+		stop transit.Stop
+		want string
+	}{
+		{"gtfs stop id tag", transit.Stop{Refs: map[string]string{"gtfs:stop_id": "park"}}, "park"},
+		{"stop code", transit.Stop{Location: geo.Location{Latitude: 52.5302, Longitude: 13.4100}, Refs: map[string]string{"ref": "4711"}}, "park"},
+		{"name nearby", transit.Stop{Name: "Park St.", Location: geo.Location{Latitude: 52.5303, Longitude: 13.4100}}, ""},
+		{"same name", transit.Stop{Name: "park street", Location: geo.Location{Latitude: 52.5303, Longitude: 13.4100}}, "park"},
+		{"unnamed nearest", transit.Stop{Location: geo.Location{Latitude: 52.52011, Longitude: 13.4050}}, "central_a"},
+		{"too far", transit.Stop{Name: "Park Street", Location: geo.Location{Latitude: 52.54, Longitude: 13.41}}, ""},
+	}
+
+	for _, tt := range tests {
+		match, ok := feed.MatchStop(tt.stop)
+		got := ""
+		if ok {
+			got = match.ID
+		}
+		if got != tt.want {
+			t.Errorf("%s: matched %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFeedProvider(t *testing.T) {
+	feed := loadTestFeed(t)
+	from := time.Date(2025, 6, 2, 7, 0, 0, 0, feed.Location)
+
+	departures, err := feed.Departures(context.Background(), transit.Stop{Refs: map[string]string{"gtfs:stop_id": "central_a"}}, from, 1)
+	if err != nil {
+		t.Fatalf("Departures failed: %v", err)
+	}
+	if len(departures) != 1 || departures[0].Route != "10" || departures[0].Source != "city" {
+		t.Errorf("unexpected departures %+v", departures)
+	}
+
+	_, err = feed.Departures(context.Background(), transit.Stop{Location: geo.Location{Latitude: 10, Longitude: 10}}, from, 1)
+	if !errors.Is(err, transit.ErrStopNotCovered) {
+		t.Errorf("expected ErrStopNotCovered for a stop outside the feed, got %v", err)
+	}
+}
+
+func TestParseGTFSTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+		ok    bool
+	}{
+		{"08:05:30", 8*3600 + 5*60 + 30, true},
+		{"25:10:00", 25*3600 + 10*60, true},
+		{" 7:00:00", 7 * 3600, true},
+		{"", 0, false},
+		{"08:61:00", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseGTFSTime(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseGTFSTime(%q) = %d, %v; want %d, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
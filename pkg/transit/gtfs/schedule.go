@@ -0,0 +1,304 @@
+package gtfs
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERVsystems/osmmcp/pkg/geo"
+	"github.com/NERVsystems/osmmcp/pkg/transit"
+)
+
+const (
+	// stopMatchDistance is how far, in meters, an OSM stop may be from a GTFS
+	// stop with the same name or code to be considered the same stop
+	stopMatchDistance = 100.0
+	// stopNearDistance matches an unnamed OSM stop to the nearest GTFS stop
+	// within this many meters
+	stopNearDistance = 25.0
+	// departureHorizon is how far ahead departures are searched
+	departureHorizon = 24 * time.Hour
+)
+
+// service is a GTFS service calendar: the days a set of trips runs
+type service struct {
+	weekdays [7]bool // indexed by time.Weekday
+	start    string  // YYYYMMDD, inclusive
+	end      string  // YYYYMMDD, inclusive
+	added    map[string]bool
+	removed  map[string]bool
+}
+
+// activeOn reports whether the service runs on a date formatted YYYYMMDD
+func (s *service) activeOn(date string, weekday time.Weekday) bool {
+	if s.removed[date] {
+		return false
+	}
+	if s.added[date] {
+		return true
+	}
+	return s.weekdays[weekday] && s.start != "" && date >= s.start && date <= s.end
+}
+
+// loadCalendars reads calendar.txt and calendar_dates.txt. A feed needs at
+// least one of them.
+func loadCalendars(feed *Feed, open opener) error {
+	serviceFor := func(id string) *service {
+		s, ok := feed.services[id]
+		if !ok {
+			s = &service{added: make(map[string]bool), removed: make(map[string]bool)}
+			feed.services[id] = s
+		}
+		return s
+	}
+
+	days := []struct {
+		column  string
+		weekday time.Weekday
+	}{
+		{"monday", time.Monday}, {"tuesday", time.Tuesday}, {"wednesday", time.Wednesday},
+		{"thursday", time.Thursday}, {"friday", time.Friday}, {"saturday", time.Saturday},
+		{"sunday", time.Sunday},
+	}
+
+	err := readTable(open, "calendar.txt", false, func(row row) error {
+		s := serviceFor(row.get("service_id"))
+		for _, day := range days {
+			s.weekdays[day.weekday] = row.get(day.column) == "1"
+		}
+		s.start = row.get("start_date")
+		s.end = row.get("end_date")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return readTable(open, "calendar_dates.txt", false, func(row row) error {
+		s := serviceFor(row.get("service_id"))
+		switch row.get("exception_type") {
+		case "1":
+			s.added[row.get("date")] = true
+		case "2":
+			s.removed[row.get("date")] = true
+		}
+		return nil
+	})
+}
+
+// MatchStop finds the GTFS stop for an OSM stop: first by a gtfs:stop_id or
+// ref tag equal to a stop ID or code, then by name nearby, then by location
+func (f *Feed) MatchStop(stop transit.Stop) (*Stop, bool) {
+	for _, key := range []string{"gtfs:stop_id", "ref:gtfs", "gtfs_id"} {
+		if id := stop.Refs[key]; id != "" {
+			if s, ok := f.Stops[id]; ok {
+				return s, true
+			}
+		}
+	}
+
+	var (
+		best     *Stop
+		bestDist = math.Inf(1)
+		named    bool
+	)
+	name := normalizeStopName(stop.Name)
+	for _, s := range f.Stops {
+		d := geo.HaversineDistance(stop.Location.Latitude, stop.Location.Longitude, s.Location.Latitude, s.Location.Longitude)
+		if d > stopMatchDistance {
+			continue
+		}
+
+		codeMatch := s.Code != "" && (s.Code == stop.Refs["ref"] || s.Code == stop.Refs["local_ref"])
+		nameMatch := name != "" && normalizeStopName(s.Name) == name
+		switch {
+		case codeMatch || nameMatch:
+			if !named || d < bestDist || (d == bestDist && s.ID < best.ID) {
+				best, bestDist, named = s, d, true
+			}
+		case !named && d <= stopNearDistance && (d < bestDist || (d == bestDist && s.ID < best.ID)):
+			best, bestDist = s, d
+		}
+	}
+	return best, best != nil
+}
+
+// NearbyStops returns the stops within a radius of a point, nearest first
+func (f *Feed) NearbyStops(loc geo.Location, radius float64) []*Stop {
+	type near struct {
+		stop *Stop
+		dist float64
+	}
+	var found []near
+	for _, s := range f.Stops {
+		d := geo.HaversineDistance(loc.Latitude, loc.Longitude, s.Location.Latitude, s.Location.Longitude)
+		if d <= radius {
+			found = append(found, near{s, d})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].dist != found[j].dist {
+			return found[i].dist < found[j].dist
+		}
+		return found[i].stop.ID < found[j].stop.ID
+	})
+
+	stops := make([]*Stop, len(found))
+	for i, n := range found {
+		stops[i] = n.stop
+	}
+	return stops
+}
+
+// stopFamily returns a stop and, for a station, its platforms
+func (f *Feed) stopFamily(stopID string) []string {
+	return append([]string{stopID}, f.children[stopID]...)
+}
+
+// RoutesAtStop returns the routes serving a stop or the platforms of a
+// station, ordered by name
+func (f *Feed) RoutesAtStop(stopID string) []Route {
+	seen := make(map[string]bool)
+	var routes []Route
+	for _, id := range f.stopFamily(stopID) {
+		for _, routeID := range f.stopRoutes[id] {
+			route, ok := f.Routes[routeID]
+			if !ok || seen[routeID] {
+				continue
+			}
+			seen[routeID] = true
+			routes = append(routes, *route)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		a, b := routes[i].DisplayName(), routes[j].DisplayName()
+		if a != b {
+			return naturalLess(a, b)
+		}
+		return routes[i].ID < routes[j].ID
+	})
+	return routes
+}
+
+// Headsigns returns the distinct destinations of a route's trips from a stop
+func (f *Feed) Headsigns(stopID, routeID string) []string {
+	seen := make(map[string]bool)
+	var headsigns []string
+	for _, id := range f.stopFamily(stopID) {
+		for _, st := range f.stopTimes[id] {
+			trip := f.Trips[st.TripID]
+			if trip == nil || trip.RouteID != routeID {
+				continue
+			}
+			headsign := st.Headsign
+			if headsign == "" {
+				headsign = trip.Headsign
+			}
+			if headsign != "" && !seen[headsign] {
+				seen[headsign] = true
+				headsigns = append(headsigns, headsign)
+			}
+		}
+	}
+	sort.Strings(headsigns)
+	return headsigns
+}
+
+// ScheduledDepartures returns departures from a GTFS stop, or the platforms
+// of a station, at or after a time, ordered by time
+func (f *Feed) ScheduledDepartures(stopID string, from time.Time, limit int) []transit.Departure {
+	from = from.In(f.Location)
+	until := from.Add(departureHorizon)
+
+	var departures []transit.Departure
+	// Trips of the previous service day can run past midnight
+	for offset := -1; offset <= 1; offset++ {
+		day := time.Date(from.Year(), from.Month(), from.Day()+offset, 0, 0, 0, 0, f.Location)
+		date := day.Format("20060102")
+
+		for _, id := range f.stopFamily(stopID) {
+			for _, st := range f.stopTimes[id] {
+				if st.NoPickup {
+					continue
+				}
+				when := day.Add(time.Duration(st.Departure) * time.Second)
+				if when.Before(from) || !when.Before(until) {
+					continue
+				}
+				trip := f.Trips[st.TripID]
+				if trip == nil {
+					continue
+				}
+				svc := f.services[trip.ServiceID]
+				if svc == nil || !svc.activeOn(date, day.Weekday()) {
+					continue
+				}
+
+				departure := transit.Departure{
+					Headsign:  st.Headsign,
+					Scheduled: when,
+					Source:    f.name,
+				}
+				if departure.Headsign == "" {
+					departure.Headsign = trip.Headsign
+				}
+				if route := f.Routes[trip.RouteID]; route != nil {
+					departure.Route = route.DisplayName()
+					departure.Mode = route.Mode
+				}
+				if s := f.Stops[id]; s != nil && id != stopID {
+					departure.Platform = s.Code
+				}
+				departures = append(departures, departure)
+			}
+		}
+	}
+
+	sort.SliceStable(departures, func(i, j int) bool {
+		return departures[i].Scheduled.Before(departures[j].Scheduled)
+	})
+	if limit > 0 && len(departures) > limit {
+		departures = departures[:limit]
+	}
+	return departures
+}
+
+// Name implements transit.Provider
+func (f *Feed) Name() string {
+	return f.name
+}
+
+// Departures implements transit.Provider with the feed's timetable
+func (f *Feed) Departures(_ context.Context, stop transit.Stop, from time.Time, limit int) ([]transit.Departure, error) {
+	match, ok := f.MatchStop(stop)
+	if !ok {
+		return nil, transit.ErrStopNotCovered
+	}
+	return f.ScheduledDepartures(match.ID, from, limit), nil
+}
+
+// normalizeStopName lowercases a stop name and drops punctuation so OSM and
+// GTFS spellings compare equal
+func normalizeStopName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// naturalLess orders route names numerically when both are numbers, so
+// route 9 sorts before route 10
+func naturalLess(a, b string) bool {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return a < b
+}
@@ -0,0 +1,94 @@
+package transit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// HTTPProvider fetches departures from a JSON endpoint, typically a small
+// adapter in front of an operator API or GTFS-RT feed. The endpoint is
+// called with the query parameters osm_id, name, lat, lon, from (RFC 3339),
+// limit and one parameter per stop ref tag, and must answer with
+// {"departures": [...]} using the Departure JSON fields, or 404 when it does
+// not know the stop.
+type HTTPProvider struct {
+	name    string
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPProvider creates a provider for a departures endpoint. An empty
+// name defaults to the endpoint host.
+func NewHTTPProvider(name, baseURL string, client *http.Client) (*HTTPProvider, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid departures URL %q", baseURL)
+	}
+	if name == "" {
+		name = parsed.Host
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPProvider{name: name, baseURL: baseURL, client: client}, nil
+}
+
+// Name implements Provider
+func (p *HTTPProvider) Name() string {
+	return p.name
+}
+
+// Departures implements Provider
+func (p *HTTPProvider) Departures(ctx context.Context, stop Stop, from time.Time, limit int) ([]Departure, error) {
+	reqURL, err := url.Parse(p.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	query := reqURL.Query()
+	query.Set("osm_id", stop.OSMID)
+	if stop.Name != "" {
+		query.Set("name", stop.Name)
+	}
+	query.Set("lat", strconv.FormatFloat(stop.Location.Latitude, 'f', 6, 64))
+	query.Set("lon", strconv.FormatFloat(stop.Location.Longitude, 'f', 6, 64))
+	query.Set("from", from.UTC().Format(time.RFC3339))
+	query.Set("limit", strconv.Itoa(limit))
+	for key, value := range stop.Refs {
+		query.Set(key, value)
+	}
+	reqURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s departures request failed: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrStopNotCovered
+	default:
+		return nil, fmt.Errorf("%s departures request returned status %d", p.name, resp.StatusCode)
+	}
+
+	var body struct {
+		Departures []Departure `json:"departures"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode %s departures: %w", p.name, err)
+	}
+	return body.Departures, nil
+}